@@ -127,8 +127,9 @@ func GetPosition(ctx context.Context) (p Position) {
 
 // Error wraps a error msg.
 type Error struct {
-	msg    string
-	status modelv1.Status
+	messageStatuses map[uint64]modelv1.Status
+	msg             string
+	status          modelv1.Status
 }
 
 // NewError returns a new Error.
@@ -141,11 +142,68 @@ func NewErrorWithStatus(status modelv1.Status, msg string) *Error {
 	return &Error{status: status, msg: msg}
 }
 
+// NewErrorWithMessageStatuses returns a new Error of a batch, whose messages have their own statuses.
+// The messages absent from the statuses succeed.
+func NewErrorWithMessageStatuses(status modelv1.Status, msg string, messageStatuses map[uint64]modelv1.Status) *Error {
+	return &Error{status: status, msg: msg, messageStatuses: messageStatuses}
+}
+
+// MergeErrors merges the errors of the messages of a batch into one error.
+// An error without message statuses applies to all the messages.
+// The former errors take precedence over the latter ones for the same message, and the nil ones are skipped.
+func MergeErrors(messageIDs []uint64, errs ...*Error) *Error {
+	var merged *Error
+	var msgs []string
+	for _, e := range errs {
+		if e == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &Error{status: e.status, messageStatuses: make(map[uint64]modelv1.Status)}
+		}
+		msgs = append(msgs, e.msg)
+		if e.messageStatuses == nil {
+			for _, id := range messageIDs {
+				if _, ok := merged.messageStatuses[id]; !ok {
+					merged.messageStatuses[id] = e.status
+				}
+			}
+			continue
+		}
+		for id, s := range e.messageStatuses {
+			if _, ok := merged.messageStatuses[id]; !ok {
+				merged.messageStatuses[id] = s
+			}
+		}
+	}
+	if merged != nil {
+		merged.msg = strings.Join(msgs, "; ")
+	}
+	return merged
+}
+
 // Status returns the status.
 func (e Error) Status() modelv1.Status {
 	return e.status
 }
 
+// MessageStatus returns the status of a message of the batch.
+// It's the status of the error if the messages don't have their own statuses.
+func (e Error) MessageStatus(messageID uint64) modelv1.Status {
+	if e.messageStatuses == nil {
+		return e.status
+	}
+	if s, ok := e.messageStatuses[messageID]; ok {
+		return s
+	}
+	return modelv1.Status_STATUS_SUCCEED
+}
+
+// MessageStatuses returns the statuses of the messages of the batch, or nil if the error applies to all of them.
+func (e Error) MessageStatuses() map[uint64]modelv1.Status {
+	return e.messageStatuses
+}
+
 // Error returns the error msg.
 func (e Error) Error() string {
 	return fmt.Sprintf("code: %s, msg: %s", modelv1.Status_name[int32(e.status)], e.msg)
@@ -21,6 +21,7 @@ package data
 import (
 	"google.golang.org/protobuf/proto"
 
+	clusterv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/cluster/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	measurev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/measure/v1"
 	propertyv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/property/v1"
@@ -65,6 +66,15 @@ var (
 		TopicMeasureDropGroup.String():          TopicMeasureDropGroup,
 		TopicStreamDropGroup.String():           TopicStreamDropGroup,
 		TopicTraceDropGroup.String():            TopicTraceDropGroup,
+		TopicMeasureRepairShard.String():        TopicMeasureRepairShard,
+		TopicStreamRepairShard.String():         TopicStreamRepairShard,
+		TopicTraceRepairShard.String():          TopicTraceRepairShard,
+		TopicMeasureAntiEntropyDigest.String():  TopicMeasureAntiEntropyDigest,
+		TopicStreamAntiEntropyDigest.String():   TopicStreamAntiEntropyDigest,
+		TopicTraceAntiEntropyDigest.String():    TopicTraceAntiEntropyDigest,
+		TopicTagValues.String():                 TopicTagValues,
+		TopicListSeries.String():                TopicListSeries,
+		TopicSeriesCardinality.String():         TopicSeriesCardinality,
 	}
 
 	// TopicRequestMap is the map of topic name to request message.
@@ -169,6 +179,33 @@ var (
 		TopicTraceDropGroup: func() proto.Message {
 			return &databasev1.GroupRegistryServiceDeleteRequest{}
 		},
+		TopicMeasureRepairShard: func() proto.Message {
+			return &clusterv1.RepairShardRequest{}
+		},
+		TopicStreamRepairShard: func() proto.Message {
+			return &clusterv1.RepairShardRequest{}
+		},
+		TopicTraceRepairShard: func() proto.Message {
+			return &clusterv1.RepairShardRequest{}
+		},
+		TopicMeasureAntiEntropyDigest: func() proto.Message {
+			return &clusterv1.AntiEntropyDigestRequest{}
+		},
+		TopicStreamAntiEntropyDigest: func() proto.Message {
+			return &clusterv1.AntiEntropyDigestRequest{}
+		},
+		TopicTraceAntiEntropyDigest: func() proto.Message {
+			return &clusterv1.AntiEntropyDigestRequest{}
+		},
+		TopicTagValues: func() proto.Message {
+			return &databasev1.SeriesExplorerServiceTagValuesRequest{}
+		},
+		TopicListSeries: func() proto.Message {
+			return &databasev1.SeriesExplorerServiceListSeriesRequest{}
+		},
+		TopicSeriesCardinality: func() proto.Message {
+			return &databasev1.SeriesExplorerServiceSeriesCardinalityRequest{}
+		},
 	}
 
 	// TopicResponseMap is the map of topic name to response message.
@@ -228,6 +265,42 @@ var (
 		TopicTraceDropGroup: func() proto.Message {
 			return &databasev1.GroupRegistryServiceDeleteRequest{}
 		},
+		TopicMeasureSeriesIndexInsert: func() proto.Message {
+			return &clusterv1.SeriesIndexWriteResponse{}
+		},
+		TopicMeasureSeriesIndexUpdate: func() proto.Message {
+			return &clusterv1.SeriesIndexWriteResponse{}
+		},
+		TopicStreamSeriesIndexWrite: func() proto.Message {
+			return &clusterv1.SeriesIndexWriteResponse{}
+		},
+		TopicMeasureRepairShard: func() proto.Message {
+			return &clusterv1.RepairShardResponse{}
+		},
+		TopicStreamRepairShard: func() proto.Message {
+			return &clusterv1.RepairShardResponse{}
+		},
+		TopicTraceRepairShard: func() proto.Message {
+			return &clusterv1.RepairShardResponse{}
+		},
+		TopicMeasureAntiEntropyDigest: func() proto.Message {
+			return &clusterv1.AntiEntropyDigestResponse{}
+		},
+		TopicStreamAntiEntropyDigest: func() proto.Message {
+			return &clusterv1.AntiEntropyDigestResponse{}
+		},
+		TopicTraceAntiEntropyDigest: func() proto.Message {
+			return &clusterv1.AntiEntropyDigestResponse{}
+		},
+		TopicTagValues: func() proto.Message {
+			return &databasev1.SeriesExplorerServiceTagValuesResponse{}
+		},
+		TopicListSeries: func() proto.Message {
+			return &databasev1.SeriesExplorerServiceListSeriesResponse{}
+		},
+		TopicSeriesCardinality: func() proto.Message {
+			return &databasev1.SeriesExplorerServiceSeriesCardinalityResponse{}
+		},
 	}
 
 	// TopicCommon is the common topic for data transmission.
@@ -0,0 +1,50 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package data
+
+import (
+	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/pkg/bus"
+)
+
+// TagValuesKindVersion is the version tag of tag values kind.
+var TagValuesKindVersion = common.KindVersion{
+	Version: "v1",
+	Kind:    "tag-values",
+}
+
+// TopicTagValues is the topic for listing the distinct values of a tag.
+var TopicTagValues = bus.BiTopic(TagValuesKindVersion.String())
+
+// ListSeriesKindVersion is the version tag of list series kind.
+var ListSeriesKindVersion = common.KindVersion{
+	Version: "v1",
+	Kind:    "list-series",
+}
+
+// TopicListSeries is the topic for listing the series of a resource.
+var TopicListSeries = bus.BiTopic(ListSeriesKindVersion.String())
+
+// SeriesCardinalityKindVersion is the version tag of series cardinality kind.
+var SeriesCardinalityKindVersion = common.KindVersion{
+	Version: "v1",
+	Kind:    "series-cardinality",
+}
+
+// TopicSeriesCardinality is the topic for breaking down the series count of a resource.
+var TopicSeriesCardinality = bus.BiTopic(SeriesCardinalityKindVersion.String())
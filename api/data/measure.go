@@ -112,3 +112,9 @@ var TopicMeasureCollectLiaisonInfo = bus.BiTopic("measure-collect-liaison-info")
 
 // TopicMeasureDropGroup is the topic for dropping group data files.
 var TopicMeasureDropGroup = bus.BiTopic("measure-drop-group")
+
+// TopicMeasureRepairShard is the topic for asking a replica to push the parts of a shard to a data node.
+var TopicMeasureRepairShard = bus.BiTopic("measure-repair-shard")
+
+// TopicMeasureAntiEntropyDigest is the topic for asking a replica for the anti-entropy digests of a shard.
+var TopicMeasureAntiEntropyDigest = bus.BiTopic("measure-anti-entropy-digest")
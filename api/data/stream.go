@@ -102,3 +102,9 @@ var TopicStreamCollectLiaisonInfo = bus.BiTopic("stream-collect-liaison-info")
 
 // TopicStreamDropGroup is the topic for dropping group data files.
 var TopicStreamDropGroup = bus.BiTopic("stream-drop-group")
+
+// TopicStreamRepairShard is the topic for asking a replica to push the parts of a shard to a data node.
+var TopicStreamRepairShard = bus.BiTopic("stream-repair-shard")
+
+// TopicStreamAntiEntropyDigest is the topic for asking a replica for the anti-entropy digests of a shard.
+var TopicStreamAntiEntropyDigest = bus.BiTopic("stream-anti-entropy-digest")
@@ -84,3 +84,9 @@ var TopicTraceCollectLiaisonInfo = bus.BiTopic("trace-collect-liaison-info")
 
 // TopicTraceDropGroup is the topic for dropping group data files.
 var TopicTraceDropGroup = bus.BiTopic("trace-drop-group")
+
+// TopicTraceRepairShard is the topic for asking a replica to push the parts of a shard to a data node.
+var TopicTraceRepairShard = bus.BiTopic("trace-repair-shard")
+
+// TopicTraceAntiEntropyDigest is the topic for asking a replica for the anti-entropy digests of a shard.
+var TopicTraceAntiEntropyDigest = bus.BiTopic("trace-anti-entropy-digest")
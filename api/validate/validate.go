@@ -20,9 +20,11 @@ package validate
 
 import (
 	"errors"
+	"fmt"
 
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
 )
 
 // Group validates the provided Group object.
@@ -93,7 +95,15 @@ func GroupForNonProperty(group *commonv1.Group) error {
 	if group.ResourceOpts.Ttl.Unit == commonv1.IntervalRule_UNIT_UNSPECIFIED {
 		return errors.New("group ttl unit is unspecified")
 	}
-	return nil
+	if group.Catalog == commonv1.Catalog_CATALOG_TRACE {
+		// The trace parts are never offloaded, so a remote stage would silently keep them on the local disk.
+		for _, st := range group.ResourceOpts.Stages {
+			if st.GetRemote() {
+				return fmt.Errorf("group stage %s is remote, which is not supported by trace groups", st.GetName())
+			}
+		}
+	}
+	return seriesLimits(group.ResourceOpts.SeriesLimits)
 }
 
 // Stream validates the provided Stream object.
@@ -120,6 +130,9 @@ func Stream(stream *databasev1.Stream) error {
 	if len(stream.Entity.TagNames) == 0 {
 		return errors.New("stream entity tag names is empty")
 	}
+	if err := seriesLimits(stream.SeriesLimits); err != nil {
+		return err
+	}
 	return tagFamily(stream.TagFamilies)
 }
 
@@ -164,9 +177,27 @@ func Measure(measure *databasev1.Measure) error {
 	if measure.IndexMode && len(measure.Fields) > 0 {
 		return errors.New("index mode is enabled, but fields are not empty")
 	}
+	if err := seriesLimits(measure.SeriesLimits); err != nil {
+		return err
+	}
 	return tagFamily(measure.TagFamilies)
 }
 
+// seriesLimits validates the interval of the series limits.
+func seriesLimits(limits *commonv1.SeriesLimits) error {
+	if limits.GetInterval() == "" {
+		return nil
+	}
+	d, err := timestamp.ParseDuration(limits.GetInterval())
+	if err != nil {
+		return errors.New("series limits interval is malformed")
+	}
+	if d <= 0 {
+		return errors.New("series limits interval should be positive")
+	}
+	return nil
+}
+
 // Trace validates the provided Trace object.
 // It checks for nil values, empty strings, and unspecified enum values.
 func Trace(trace *databasev1.Trace) error {
@@ -268,6 +299,9 @@ func IndexRule(indexRule *databasev1.IndexRule) error {
 	if indexRule.Type == databasev1.IndexRule_TYPE_UNSPECIFIED {
 		return errors.New("indexRule type is unspecified")
 	}
+	if indexRule.Type == databasev1.IndexRule_TYPE_TREE && indexRule.Analyzer != "" {
+		return errors.New("indexRule analyzer is not supported by the tree index")
+	}
 	return nil
 }
 
@@ -22,7 +22,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/url"
 	"os"
 	"os/signal"
 	"path"
@@ -39,11 +38,8 @@ import (
 	"github.com/apache/skywalking-banyandb/banyand/backup/snapshot"
 	cfg "github.com/apache/skywalking-banyandb/pkg/config"
 	"github.com/apache/skywalking-banyandb/pkg/fs/remote"
-	"github.com/apache/skywalking-banyandb/pkg/fs/remote/aws"
-	"github.com/apache/skywalking-banyandb/pkg/fs/remote/azure"
 	remoteconfig "github.com/apache/skywalking-banyandb/pkg/fs/remote/config"
-	"github.com/apache/skywalking-banyandb/pkg/fs/remote/gcp"
-	"github.com/apache/skywalking-banyandb/pkg/fs/remote/local"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/provider"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 	banyandbpath "github.com/apache/skywalking-banyandb/pkg/path"
 	"github.com/apache/skywalking-banyandb/pkg/timestamp"
@@ -203,23 +199,7 @@ func backupAction(options backupOptions) error {
 }
 
 func newFS(dest string, config *remoteconfig.FsConfig) (remote.FS, error) {
-	u, err := url.Parse(dest)
-	if err != nil {
-		return nil, fmt.Errorf("invalid dest URL: %w", err)
-	}
-
-	switch u.Scheme {
-	case "file":
-		return local.NewFS(u.Path)
-	case "s3":
-		return aws.NewFS(u.Path, config)
-	case "azure":
-		return azure.NewFS(u.Host+u.Path, config)
-	case "gcs", "gs":
-		return gcp.NewFS(u.Host+u.Path, config)
-	default:
-		return nil, fmt.Errorf("unsupported scheme: %s", u.Scheme)
-	}
+	return provider.NewFS(dest, config)
 }
 
 func getTimeDir(style string) string {
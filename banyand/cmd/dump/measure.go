@@ -196,9 +196,14 @@ type measureColumnFamilyMetadata struct {
 }
 
 type measureColumnMetadata struct {
-	name      string
-	dataBlock measureDataBlock
-	valueType pbv1.ValueType
+	name         string
+	min          []byte
+	max          []byte
+	dataBlock    measureDataBlock
+	nullCount    uint64
+	valueType    pbv1.ValueType
+	compressType encoding.CompressType
+	hasZoneMap   bool
 }
 
 type measurePart struct {
@@ -417,7 +422,17 @@ func (ctx *measureDumpContext) processBlock(partID uint64, bm *measureBlockMetad
 	fieldsByDataPoint := ctx.readBlockFields(partID, bm, p, decoder)
 
 	// Read tag families
-	tagsByDataPoint := ctx.readBlockTagFamilies(partID, bm, p, decoder)
+	tagsByDataPoint, tagZoneMaps := ctx.readBlockTagFamilies(partID, bm, p, decoder)
+
+	if ctx.opts.verbose && !ctx.opts.csvOutput {
+		zoneMaps := tagZoneMaps
+		for _, colMeta := range bm.field.columns {
+			if colMeta.hasZoneMap {
+				zoneMaps = append(zoneMaps, newBlockZoneMap(colMeta.name, colMeta.valueType, colMeta.min, colMeta.max, colMeta.nullCount))
+			}
+		}
+		writeBlockZoneMaps(bm.seriesID, bm.count, zoneMaps)
+	}
 
 	rows := 0
 	for i := 0; i < len(timestamps); i++ {
@@ -461,8 +476,11 @@ func (ctx *measureDumpContext) processBlock(partID uint64, bm *measureBlockMetad
 	return rows, nil
 }
 
-func (ctx *measureDumpContext) readBlockTagFamilies(partID uint64, bm *measureBlockMetadata, p *measurePart, decoder *encoding.BytesBlockDecoder) map[string][][]byte {
+func (ctx *measureDumpContext) readBlockTagFamilies(partID uint64, bm *measureBlockMetadata, p *measurePart,
+	decoder *encoding.BytesBlockDecoder,
+) (map[string][][]byte, []blockZoneMap) {
 	tags := make(map[string][][]byte)
+	var zoneMaps []blockZoneMap
 	for tagFamilyName, tagFamilyBlock := range bm.tagFamilies {
 		// Read tag family metadata
 		tagFamilyMetadataData := make([]byte, tagFamilyBlock.size)
@@ -479,6 +497,9 @@ func (ctx *measureDumpContext) readBlockTagFamilies(partID uint64, bm *measureBl
 		// Read each tag (column) in the tag family
 		for _, colMeta := range cfm.columns {
 			fullTagName := tagFamilyName + "." + colMeta.name
+			if colMeta.hasZoneMap {
+				zoneMaps = append(zoneMaps, newBlockZoneMap(fullTagName, colMeta.valueType, colMeta.min, colMeta.max, colMeta.nullCount))
+			}
 			tagValues, err := readMeasureTagValues(decoder, colMeta.dataBlock, fullTagName, int(bm.count), p.tagFamilies[tagFamilyName], colMeta.valueType)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: Error reading tag %s for series %d in part %016x: %v\n", fullTagName, bm.seriesID, partID, err)
@@ -487,13 +508,13 @@ func (ctx *measureDumpContext) readBlockTagFamilies(partID uint64, bm *measureBl
 			tags[fullTagName] = tagValues
 		}
 	}
-	return tags
+	return tags, zoneMaps
 }
 
 func (ctx *measureDumpContext) readBlockFields(partID uint64, bm *measureBlockMetadata, p *measurePart, decoder *encoding.BytesBlockDecoder) map[string][][]byte {
 	fields := make(map[string][][]byte)
 	for _, colMeta := range bm.field.columns {
-		fieldValues, err := readMeasureFieldValues(decoder, colMeta.dataBlock, colMeta.name, int(bm.count), p.fieldValues, colMeta.valueType)
+		fieldValues, err := readMeasureFieldValues(decoder, colMeta.dataBlock, colMeta.name, int(bm.count), p.fieldValues, colMeta.valueType, colMeta.compressType)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: Error reading field %s for series %d in part %016x: %v\n", colMeta.name, bm.seriesID, partID, err)
 			continue
@@ -801,8 +822,30 @@ func (cm *measureColumnMetadata) unmarshal(src []byte) ([]byte, error) {
 	if len(src) < 1 {
 		return nil, fmt.Errorf("cannot unmarshal columnMetadata.valueType: src is too short")
 	}
-	cm.valueType = pbv1.ValueType(src[0])
+	// the high bit of valueType marks a following codec byte, whose low 4 bits are the compression method,
+	// and the next bit marks a following zone map of the int and float columns
+	cm.valueType = pbv1.ValueType(src[0] &^ 0xC0)
+	hasCodec := src[0]&0x80 != 0
+	cm.hasZoneMap = src[0]&0x40 != 0
 	src = src[1:]
+	if hasCodec {
+		if len(src) < 1 {
+			return nil, fmt.Errorf("cannot unmarshal columnMetadata.codec: src is too short")
+		}
+		cm.compressType = encoding.CompressType(src[0] & 0x0F)
+		src = src[1:]
+	}
+	if cm.hasZoneMap {
+		src, cm.min, err = encoding.DecodeBytes(src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot unmarshal columnMetadata.min: %w", err)
+		}
+		src, cm.max, err = encoding.DecodeBytes(src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot unmarshal columnMetadata.max: %w", err)
+		}
+		src, cm.nullCount = encoding.BytesToVarUint64(src)
+	}
 	src = cm.dataBlock.unmarshal(src)
 	return src, nil
 }
@@ -859,12 +902,19 @@ func readMeasureTagValues(decoder *encoding.BytesBlockDecoder, tagBlock measureD
 }
 
 func readMeasureFieldValues(decoder *encoding.BytesBlockDecoder, fieldBlock measureDataBlock, _ string, count int,
-	valueReader fs.Reader, valueType pbv1.ValueType,
+	valueReader fs.Reader, valueType pbv1.ValueType, compressType encoding.CompressType,
 ) ([][]byte, error) {
 	// Read field values
 	bb := &bytes.Buffer{}
 	bb.Buf = make([]byte, fieldBlock.size)
 	fs.MustReadData(valueReader, int64(fieldBlock.offset), bb.Buf)
+	if compressType.IsBlockCompressed() {
+		decompressed, err := encoding.DecompressBlockWith(nil, bb.Buf, compressType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decompress field values with %s: %w", compressType, err)
+		}
+		bb.Buf = decompressed
+	}
 
 	// Decode values based on value type
 	var values [][]byte
@@ -915,28 +965,31 @@ func readMeasureFieldValues(decoder *encoding.BytesBlockDecoder, fieldBlock meas
 				return nil, fmt.Errorf("cannot decode float64 field values (plain): %w", err)
 			}
 		} else {
-			const expectedLen = 11
-			if len(bb.Buf) < expectedLen {
-				return nil, fmt.Errorf("buffer too short for float64 field: expected at least %d bytes", expectedLen)
-			}
-			exp := convert.BytesToInt16(bb.Buf[1:3])
-			firstValue := convert.BytesToInt64(bb.Buf[3:11])
-			bb.Buf = bb.Buf[11:]
-			intValues := make([]int64, count)
-			intValues, err = encoding.BytesToInt64List(intValues[:0], bb.Buf, encodeType, firstValue, count)
-			if err != nil {
-				return nil, fmt.Errorf("cannot decode int values for float64: %w", err)
-			}
-			floatValues := make([]float64, count)
-			floatValues, err = encoding.DecimalIntListToFloat64List(floatValues[:0], intValues, exp, count)
+			var floatValues []float64
+			floatValues, err = encoding.BytesToFloat64List(floatValues, bb.Buf, count)
 			if err != nil {
-				return nil, fmt.Errorf("cannot convert DecimalIntList to Float64List: %w", err)
+				return nil, fmt.Errorf("cannot decode float64 field values: %w", err)
 			}
 			values = make([][]byte, count)
 			for i, v := range floatValues {
 				values[i] = convert.Float64ToBytes(v)
 			}
 		}
+	case pbv1.ValueTypeHistogram:
+		// Decode histogram values - similar to column.decodeHistogramColumn
+		if len(bb.Buf) < 1 {
+			return nil, fmt.Errorf("buffer too short for histogram field")
+		}
+		encodeType := encoding.EncodeType(bb.Buf[0])
+		bb.Buf = bb.Buf[1:]
+		if encodeType == encoding.EncodeTypePlain {
+			values, err = internalencoding.DecodeTagValues(values, decoder, bb, valueType, count)
+		} else {
+			values, err = encoding.DecodeHistogramBlock(values[:0], bb.Buf, uint64(count))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode histogram field values: %w", err)
+		}
 	default:
 		// Use default decoder for other types
 		values, err = internalencoding.DecodeTagValues(values, decoder, bb, valueType, count)
@@ -115,7 +115,7 @@ func TestDumpMeasurePartFormat(t *testing.T) {
 
 			// Read field values if available
 			for _, colMeta := range bm.field.columns {
-				fieldValues, err := readMeasureFieldValues(decoder, colMeta.dataBlock, colMeta.name, int(bm.count), p.fieldValues, colMeta.valueType)
+				fieldValues, err := readMeasureFieldValues(decoder, colMeta.dataBlock, colMeta.name, int(bm.count), p.fieldValues, colMeta.valueType, colMeta.compressType)
 				require.NoError(t, err, "should read field %s for series %d", colMeta.name, bm.seriesID)
 				assert.Len(t, fieldValues, int(bm.count), "field %s should have value for each data point", colMeta.name)
 
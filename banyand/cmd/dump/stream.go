@@ -369,7 +369,10 @@ func (ctx *streamDumpContext) processBlock(partID uint64, bm *streamBlockMetadat
 	}
 
 	// Read tag families
-	tagsByElement := ctx.readBlockTagFamilies(partID, bm, p, decoder)
+	tagsByElement, zoneMaps := ctx.readBlockTagFamilies(partID, bm, p, decoder)
+	if ctx.opts.verbose && !ctx.opts.csvOutput {
+		writeBlockZoneMaps(bm.seriesID, bm.count, zoneMaps)
+	}
 
 	rows := 0
 	for i := 0; i < len(timestamps); i++ {
@@ -408,8 +411,11 @@ func (ctx *streamDumpContext) processBlock(partID uint64, bm *streamBlockMetadat
 	return rows, nil
 }
 
-func (ctx *streamDumpContext) readBlockTagFamilies(partID uint64, bm *streamBlockMetadata, p *streamPart, decoder *encoding.BytesBlockDecoder) map[string][][]byte {
+func (ctx *streamDumpContext) readBlockTagFamilies(partID uint64, bm *streamBlockMetadata, p *streamPart,
+	decoder *encoding.BytesBlockDecoder,
+) (map[string][][]byte, []blockZoneMap) {
 	tags := make(map[string][][]byte)
+	var zoneMaps []blockZoneMap
 	for tagFamilyName, tagFamilyBlock := range bm.tagFamilies {
 		// Read tag family metadata
 		tagFamilyMetadataData := make([]byte, tagFamilyBlock.size)
@@ -425,6 +431,9 @@ func (ctx *streamDumpContext) readBlockTagFamilies(partID uint64, bm *streamBloc
 		// Read each tag in the tag family
 		for _, tagMeta := range tagMetadatas {
 			fullTagName := tagFamilyName + "." + tagMeta.name
+			if tagMeta.hasZoneMap {
+				zoneMaps = append(zoneMaps, newBlockZoneMap(fullTagName, tagMeta.valueType, tagMeta.min, tagMeta.max, tagMeta.nullCount))
+			}
 			tagValues, err := readStreamTagValues(decoder, tagMeta.dataBlock, fullTagName, int(bm.count), p.tagFamilies[tagFamilyName], tagMeta.valueType)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: Error reading tag %s for series %d in part %016x: %v\n", fullTagName, bm.seriesID, partID, err)
@@ -433,7 +442,7 @@ func (ctx *streamDumpContext) readBlockTagFamilies(partID uint64, bm *streamBloc
 			tags[fullTagName] = tagValues
 		}
 	}
-	return tags
+	return tags, zoneMaps
 }
 
 func (ctx *streamDumpContext) shouldSkip(tags map[string][]byte) bool {
@@ -738,11 +747,13 @@ func readStreamTimestamps(tm streamTimestampsMetadata, count int, reader fs.Read
 }
 
 type streamTagMetadata struct {
-	name      string
-	min       []byte
-	max       []byte
-	dataBlock streamDataBlock
-	valueType pbv1.ValueType
+	name       string
+	min        []byte
+	max        []byte
+	dataBlock  streamDataBlock
+	nullCount  uint64
+	valueType  pbv1.ValueType
+	hasZoneMap bool
 }
 
 func parseStreamTagFamilyMetadata(src []byte) ([]streamTagMetadata, error) {
@@ -765,8 +776,13 @@ func parseStreamTagFamilyMetadata(src []byte) ([]streamTagMetadata, error) {
 		if len(src) < 1 {
 			return nil, fmt.Errorf("cannot unmarshal tagMetadata.valueType: src is too short")
 		}
-		tm.valueType = pbv1.ValueType(src[0])
+		// the high bit of valueType marks a following null count of the int tags
+		tm.valueType = pbv1.ValueType(src[0] &^ 0x80)
+		hasNullCount := src[0]&0x80 != 0
 		src = src[1:]
+		if hasNullCount {
+			src, tm.nullCount = encoding.BytesToVarUint64(src)
+		}
 
 		src = tm.dataBlock.unmarshal(src)
 
@@ -780,6 +796,8 @@ func parseStreamTagFamilyMetadata(src []byte) ([]streamTagMetadata, error) {
 			return nil, fmt.Errorf("cannot unmarshal tagMetadata.max: %w", err)
 		}
 
+		tm.hasZoneMap = hasNullCount || tm.valueType == pbv1.ValueTypeInt64 && len(tm.min) > 0 && len(tm.max) > 0
+
 		// Skip filter block
 		var filterBlock streamDataBlock
 		src = filterBlock.unmarshal(src)
@@ -175,6 +175,12 @@ func formatTagValueForDisplay(data []byte, vt pbv1.ValueType) string {
 			return fmt.Sprintf("%q", string(data))
 		}
 		return fmt.Sprintf("(binary: %d bytes)", len(data))
+	case pbv1.ValueTypeHistogram:
+		h, err := pbv1.UnmarshalHistogram(data)
+		if err != nil {
+			return fmt.Sprintf("(invalid histogram data: %d bytes)", len(data))
+		}
+		return protojson.Format(h)
 	default:
 		if isPrintable(data) {
 			return fmt.Sprintf("%q", string(data))
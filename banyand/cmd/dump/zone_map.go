@@ -0,0 +1,65 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+)
+
+// blockZoneMap is the range and the null count of a column recorded in the block metadata.
+type blockZoneMap struct {
+	name      string
+	min       []byte
+	max       []byte
+	nullCount uint64
+	valueType pbv1.ValueType
+}
+
+func newBlockZoneMap(name string, valueType pbv1.ValueType, minValue, maxValue []byte, nullCount uint64) blockZoneMap {
+	return blockZoneMap{
+		name:      name,
+		valueType: valueType,
+		min:       minValue,
+		max:       maxValue,
+		nullCount: nullCount,
+	}
+}
+
+func writeBlockZoneMaps(seriesID common.SeriesID, count uint64, zoneMaps []blockZoneMap) {
+	if len(zoneMaps) == 0 {
+		return
+	}
+	sort.Slice(zoneMaps, func(i, j int) bool {
+		return zoneMaps[i].name < zoneMaps[j].name
+	})
+	fmt.Printf("Block: SeriesID %d, Count %d\n", seriesID, count)
+	fmt.Printf("  Zone maps:\n")
+	for _, zm := range zoneMaps {
+		if len(zm.min) == 0 || len(zm.max) == 0 {
+			fmt.Printf("    %s: <all null>, nulls=%d\n", zm.name, zm.nullCount)
+			continue
+		}
+		fmt.Printf("    %s: min=%s, max=%s, nulls=%d\n", zm.name,
+			formatTagValueForDisplay(zm.min, zm.valueType), formatTagValueForDisplay(zm.max, zm.valueType), zm.nullCount)
+	}
+	fmt.Printf("\n")
+}
@@ -65,6 +65,7 @@ type queryService struct {
 	mqp                  *measureQueryProcessor
 	nqp                  *topNQueryProcessor
 	tqp                  *traceQueryProcessor
+	sep                  *seriesExplorerProcessor
 	closer               *run.Closer
 	nodeID               string
 	hotStageNodeSelector string
@@ -100,6 +101,10 @@ func NewService(metaService metadata.Repo, pipeline queue.Server, broadcaster bu
 		traceService: traceSchemaSVC,
 		broadcaster:  broadcaster,
 	}
+	svc.sep = &seriesExplorerProcessor{
+		queryService: svc,
+		broadcaster:  broadcaster,
+	}
 	return svc, nil
 }
 
@@ -136,6 +141,9 @@ func (q *queryService) PreRun(ctx context.Context) error {
 		q.pipeline.Subscribe(data.TopicMeasureQuery, q.mqp),
 		q.pipeline.Subscribe(data.TopicTopNQuery, q.nqp),
 		q.pipeline.Subscribe(data.TopicTraceQuery, q.tqp),
+		q.pipeline.Subscribe(data.TopicTagValues, q.sep),
+		q.pipeline.Subscribe(data.TopicListSeries, q.sep),
+		q.pipeline.Subscribe(data.TopicSeriesCardinality, q.sep),
 	)
 }
 
@@ -0,0 +1,199 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/multierr"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/api/data"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/pkg/bus"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+)
+
+const defaultExplorerTimeout = 10 * time.Second
+
+type seriesExplorerProcessor struct {
+	broadcaster bus.Broadcaster
+	*queryService
+	*bus.UnImplementedHealthyListener
+}
+
+func (p *seriesExplorerProcessor) Rev(_ context.Context, message bus.Message) (resp bus.Message) {
+	now := bus.MessageID(time.Now().UnixNano())
+	if e := p.log.Debug(); e.Enabled() {
+		if request, ok := message.Data().(proto.Message); ok {
+			e.RawJSON("req", logger.Proto(request)).Msg("received a series explorer event")
+		}
+	}
+	var data any
+	var err error
+	switch request := message.Data().(type) {
+	case *databasev1.SeriesExplorerServiceTagValuesRequest:
+		data, err = p.tagValues(now, request)
+	case *databasev1.SeriesExplorerServiceListSeriesRequest:
+		data, err = p.listSeries(now, request)
+	case *databasev1.SeriesExplorerServiceSeriesCardinalityRequest:
+		data, err = p.seriesCardinality(now, request)
+	default:
+		return bus.NewMessage(now, common.NewError("invalid event data type"))
+	}
+	if err != nil {
+		return bus.NewMessage(now, common.NewError("fail to explore the series: %v", err))
+	}
+	return bus.NewMessage(now, data)
+}
+
+// broadcast sends the request to all data nodes and collects their responses.
+// The responses of the data nodes which succeeded are returned along with the errors of the others.
+func (p *seriesExplorerProcessor) broadcast(now bus.MessageID, topic bus.Topic, request any) ([]any, error) {
+	ff, err := p.broadcaster.Broadcast(defaultExplorerTimeout, topic, bus.NewMessage(now, request))
+	if err != nil {
+		return nil, err
+	}
+	var responses []any
+	var allErr error
+	for _, f := range ff {
+		m, getErr := f.Get()
+		if getErr != nil {
+			allErr = multierr.Append(allErr, getErr)
+			continue
+		}
+		switch d := m.Data().(type) {
+		case *common.Error:
+			allErr = multierr.Append(allErr, errors.New(d.Error()))
+		default:
+			responses = append(responses, d)
+		}
+	}
+	return responses, allErr
+}
+
+func (p *seriesExplorerProcessor) tagValues(now bus.MessageID, request *databasev1.SeriesExplorerServiceTagValuesRequest) (
+	*databasev1.SeriesExplorerServiceTagValuesResponse, error,
+) {
+	responses, err := p.broadcast(now, data.TopicTagValues, request)
+	if err != nil && len(responses) == 0 {
+		return nil, fmt.Errorf("fail to list the values of the tag %s: %w", request.GetTag(), err)
+	}
+	// The suggestions of the other data nodes are still useful.
+	if err != nil {
+		p.log.Warn().Err(err).Str("tag", request.GetTag()).Msg("some data nodes failed to list the tag values")
+	}
+	// Each data node returns its first values in ascending order,
+	// so the first values of the merged set are complete.
+	values := make(storage.TagValueSet)
+	for _, r := range responses {
+		if d, ok := r.(*databasev1.SeriesExplorerServiceTagValuesResponse); ok {
+			for _, v := range d.GetValues() {
+				values[v] = struct{}{}
+			}
+		}
+	}
+	return &databasev1.SeriesExplorerServiceTagValuesResponse{Values: values.Sorted(int(request.GetLimit()))}, nil
+}
+
+func (p *seriesExplorerProcessor) listSeries(now bus.MessageID, request *databasev1.SeriesExplorerServiceListSeriesRequest) (
+	*databasev1.SeriesExplorerServiceListSeriesResponse, error,
+) {
+	// A page without the series of a failed data node would move the page token past them, so it fails instead.
+	responses, err := p.broadcast(now, data.TopicListSeries, request)
+	if err != nil {
+		return nil, fmt.Errorf("fail to list the series: %w", err)
+	}
+	// Each data node returns its first series after the page token in the order of their keys,
+	// so the first series of the merged list are complete. Replicas are deduplicated by the key.
+	type keyedSeries struct {
+		entity *databasev1.SeriesEntity
+		key    []byte
+	}
+	var merged []keyedSeries
+	seen := make(map[string]struct{})
+	more := false
+	for _, r := range responses {
+		d, ok := r.(*databasev1.SeriesExplorerServiceListSeriesResponse)
+		if !ok {
+			continue
+		}
+		more = more || d.GetNextPageToken() != ""
+		for _, se := range d.GetSeries() {
+			s := pbv1.Series{Subject: request.GetName()}
+			for _, t := range se.GetEntity() {
+				s.EntityValues = append(s.EntityValues, t.GetValue())
+			}
+			if err = s.Marshal(); err != nil {
+				return nil, fmt.Errorf("fail to marshal the series %s: %w", se, err)
+			}
+			if _, ok := seen[string(s.Buffer)]; ok {
+				continue
+			}
+			seen[string(s.Buffer)] = struct{}{}
+			merged = append(merged, keyedSeries{entity: se, key: s.Buffer})
+		}
+	}
+	slices.SortFunc(merged, func(a, b keyedSeries) int {
+		return bytes.Compare(a.key, b.key)
+	})
+	if pageSize := int(request.GetPageSize()); pageSize > 0 && len(merged) > pageSize {
+		merged = merged[:pageSize]
+		more = true
+	}
+	resp := &databasev1.SeriesExplorerServiceListSeriesResponse{
+		Series: make([]*databasev1.SeriesEntity, 0, len(merged)),
+	}
+	for _, ks := range merged {
+		resp.Series = append(resp.Series, ks.entity)
+	}
+	if more && len(merged) > 0 {
+		resp.NextPageToken = base64.RawURLEncoding.EncodeToString(merged[len(merged)-1].key)
+	}
+	return resp, nil
+}
+
+func (p *seriesExplorerProcessor) seriesCardinality(now bus.MessageID, request *databasev1.SeriesExplorerServiceSeriesCardinalityRequest) (
+	*databasev1.SeriesExplorerServiceSeriesCardinalityResponse, error,
+) {
+	// The counts without a failed data node would be too low, so it fails instead.
+	responses, err := p.broadcast(now, data.TopicSeriesCardinality, request)
+	if err != nil {
+		return nil, fmt.Errorf("fail to count the series: %w", err)
+	}
+	bucketLists := make([][]*databasev1.SeriesCardinalityBucket, 0, len(responses))
+	for _, r := range responses {
+		if d, ok := r.(*databasev1.SeriesExplorerServiceSeriesCardinalityResponse); ok {
+			bucketLists = append(bucketLists, d.GetBuckets())
+		}
+	}
+	buckets, err := storage.MergeSeriesCardinality(bucketLists, int(request.GetTopN()))
+	if err != nil {
+		return nil, err
+	}
+	return &databasev1.SeriesExplorerServiceSeriesCardinalityResponse{Buckets: buckets}, nil
+}
@@ -33,8 +33,14 @@ var (
 	int64SlicePool   = pool.Register[*[]int64]("tag-encoder-int64Slice")
 	float64SlicePool = pool.Register[*[]float64]("tag-encoder-float64Slice")
 	dictionaryPool   = pool.Register[*encoding.Dictionary]("tag-encoder-dictionary")
+	fsstDecoderPool  = pool.Register[*encoding.FSSTDecoder]("tag-encoder-fsstDecoder")
+	fsstBufferPool   = bytes.NewBufferPool("tag-encoder-fsst")
 )
 
+// fsstMinSize is the minimum size of the values to try FSST on.
+// Below it, the symbol table costs more than it saves.
+const fsstMinSize = 1024
+
 func generateInt64Slice(length int) *[]int64 {
 	v := int64SlicePool.Get()
 	if v == nil {
@@ -86,10 +92,24 @@ func releaseDictionary(d *encoding.Dictionary) {
 	dictionaryPool.Put(d)
 }
 
+func generateFSSTDecoder() *encoding.FSSTDecoder {
+	v := fsstDecoderPool.Get()
+	if v == nil {
+		return &encoding.FSSTDecoder{}
+	}
+	return v
+}
+
+func releaseFSSTDecoder(d *encoding.FSSTDecoder) {
+	d.Reset()
+	fsstDecoderPool.Put(d)
+}
+
 // EncodeTagValues encodes tag values based on the value type with optimal compression.
 // For int64: uses delta encoding with first value storage.
 // For float64: converts to decimal integers with exponent, then delta encoding.
-// For other types: uses dictionary encoding, falls back to plain with zstd compression.
+// For other types: uses dictionary encoding, falls back to plain with zstd compression,
+// or FSST if it's smaller.
 func EncodeTagValues(bb *bytes.Buffer, values [][]byte, valueType pbv1.ValueType) (encoding.EncodeType, error) {
 	if len(values) == 0 {
 		return encoding.EncodeTypeUnknown, nil
@@ -212,10 +232,8 @@ func encodeDefaultTagValues(bb *bytes.Buffer, values [][]byte) (encoding.EncodeT
 
 	for _, v := range values {
 		if !dict.Add(v) {
-			// Dictionary encoding failed, use plain encoding with zstd compression
-			bb.Buf = encoding.EncodeBytesBlock(bb.Buf[:0], values)
-			bb.Buf = append([]byte{byte(encoding.EncodeTypePlain)}, bb.Buf...)
-			return encoding.EncodeTypePlain, nil
+			// Dictionary encoding failed, use plain encoding with zstd compression or FSST
+			return encodeHighCardinalityTagValues(bb, values), nil
 		}
 	}
 
@@ -225,6 +243,30 @@ func encodeDefaultTagValues(bb *bytes.Buffer, values [][]byte) (encoding.EncodeT
 	return encoding.EncodeTypeDictionary, nil
 }
 
+// encodeHighCardinalityTagValues uses FSST instead of the plain encoding if it's smaller.
+// FSST compresses each value on its own, so the values stay randomly accessible.
+func encodeHighCardinalityTagValues(bb *bytes.Buffer, values [][]byte) encoding.EncodeType {
+	bb.Buf = append(bb.Buf[:0], byte(encoding.EncodeTypePlain))
+	bb.Buf = encoding.EncodeBytesBlock(bb.Buf, values)
+
+	var size int
+	for _, v := range values {
+		size += len(v)
+	}
+	if size < fsstMinSize {
+		return encoding.EncodeTypePlain
+	}
+	fb := fsstBufferPool.Generate()
+	defer fsstBufferPool.Release(fb)
+	fb.Buf = append(fb.Buf[:0], byte(encoding.EncodeTypeFSST))
+	fb.Buf = encoding.EncodeFSST(fb.Buf, values)
+	if len(fb.Buf) >= len(bb.Buf) {
+		return encoding.EncodeTypePlain
+	}
+	bb.Buf = append(bb.Buf[:0], fb.Buf...)
+	return encoding.EncodeTypeFSST
+}
+
 func decodeInt64TagValues(dst [][]byte, decoder *encoding.BytesBlockDecoder, bb *bytes.Buffer, count uint64) ([][]byte, error) {
 	intValuesPtr := generateInt64Slice(int(count))
 	intValues := *intValuesPtr
@@ -347,6 +389,12 @@ func decodeDefaultTagValues(dst [][]byte, decoder *encoding.BytesBlockDecoder, b
 		dict := generateDictionary()
 		defer releaseDictionary(dict)
 		dst, err = dict.Decode(dst[:0], bb.Buf[1:], count)
+	case encoding.EncodeTypeFSST:
+		fsstDecoder := generateFSSTDecoder()
+		defer releaseFSSTDecoder(fsstDecoder)
+		if err = fsstDecoder.Init(bb.Buf[1:], count); err == nil {
+			dst, err = fsstDecoder.Decode(dst[:0])
+		}
 	case encoding.EncodeTypePlain:
 		dst, err = decoder.Decode(dst[:0], bb.Buf[1:], count)
 	default:
@@ -18,6 +18,8 @@
 package encoding
 
 import (
+	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -203,3 +205,44 @@ func TestEncodeDecodeTagValues_Int64_EmptyInput(t *testing.T) {
 	require.NoError(t, err)
 	assert.Nil(t, decoded)
 }
+
+func TestEncodeDecodeTagValues_String_HighCardinality(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	traceIDs := make([][]byte, 1000)
+	for i := range traceIDs {
+		traceIDs[i] = []byte(fmt.Sprintf("%08x-%04x-%04x-%012x", r.Uint32(), r.Intn(1<<16), r.Intn(1<<16), r.Int63n(1<<48)))
+	}
+	traceIDs[3] = nil
+	traceIDs[5] = []byte{}
+	statements := make([][]byte, 1000)
+	for i := range statements {
+		statements[i] = []byte(fmt.Sprintf("select * from orders where id = %d", i))
+	}
+
+	tests := []struct {
+		name       string
+		values     [][]byte
+		encodeType pkgencoding.EncodeType
+	}{
+		{name: "random ids use fsst", values: traceIDs, encodeType: pkgencoding.EncodeTypeFSST},
+		{name: "repetitive values use plain", values: statements, encodeType: pkgencoding.EncodeTypePlain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bb := &bytes.Buffer{}
+			encodeType, err := EncodeTagValues(bb, tt.values, pbv1.ValueTypeStr)
+			require.NoError(t, err)
+			require.Equal(t, tt.encodeType, encodeType)
+			require.Equal(t, byte(tt.encodeType), bb.Buf[0])
+
+			decoder := &pkgencoding.BytesBlockDecoder{}
+			decoded, err := DecodeTagValues(nil, decoder, bb, pbv1.ValueTypeStr, len(tt.values))
+			require.NoError(t, err)
+			require.Len(t, decoded, len(tt.values))
+			for i, original := range tt.values {
+				assert.Equal(t, original == nil, decoded[i] == nil, "value %d", i)
+				assert.Equal(t, string(original), string(decoded[i]), "value %d", i)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handoff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/skywalking-banyandb/banyand/metadata"
+)
+
+// NewShardAssignmentResolver returns a resolver which places the copies of a shard
+// on the sorted data nodes round-robin, starting from the node at the shard's index.
+func NewShardAssignmentResolver(repo metadata.Repo, dataNodes []string) func(group string, shardID uint32) ([]string, error) {
+	sortedNodes := append([]string(nil), dataNodes...)
+	sort.Strings(sortedNodes)
+	return func(group string, shardID uint32) ([]string, error) {
+		if repo == nil {
+			return nil, fmt.Errorf("metadata repo is not initialized")
+		}
+		if len(sortedNodes) == 0 {
+			return nil, fmt.Errorf("no data nodes configured for handoff")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		groupSchema, err := repo.GroupRegistry().GetGroup(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		if groupSchema == nil || groupSchema.ResourceOpts == nil {
+			return nil, fmt.Errorf("group %s missing resource options", group)
+		}
+		copies := groupSchema.ResourceOpts.Replicas + 1
+		nodes := make([]string, 0, copies)
+		seen := make(map[string]struct{}, copies)
+		for replica := uint32(0); replica < copies; replica++ {
+			nodeID := sortedNodes[(int(shardID)+int(replica))%len(sortedNodes)]
+			if _, ok := seen[nodeID]; ok {
+				continue
+			}
+			nodes = append(nodes, nodeID)
+			seen[nodeID] = struct{}{}
+		}
+		return nodes, nil
+	}
+}
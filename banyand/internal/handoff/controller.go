@@ -15,11 +15,11 @@
 // specific language governing permissions and limitations
 // under the License.
 
-package trace
+// Package handoff persists the parts destined for offline data nodes on the liaison and replays them once the nodes are back.
+package handoff
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -28,28 +28,76 @@ import (
 	"time"
 
 	"github.com/apache/skywalking-banyandb/api/common"
-	"github.com/apache/skywalking-banyandb/api/data"
-	"github.com/apache/skywalking-banyandb/banyand/internal/sidx"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/banyand/observability"
 	"github.com/apache/skywalking-banyandb/banyand/queue"
 	"github.com/apache/skywalking-banyandb/pkg/bytes"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
+	"github.com/apache/skywalking-banyandb/pkg/run"
 )
 
-// handoffController manages handoff queues for multiple data nodes.
-type handoffController struct {
+var bigValuePool = bytes.NewBufferPool("handoff-big-value")
+
+// Config holds the limits of a handoff controller.
+type Config struct {
+	// MaxTotalSizeBytes is the maximum size of the parts queued for all the nodes. It is unlimited if zero.
+	MaxTotalSizeBytes uint64
+	// MaxNodeSizeBytes is the maximum size of the parts queued for a single node. It is unlimited if zero.
+	MaxNodeSizeBytes uint64
+	// Expiry is how long a part stays in the queue before it is dropped. The parts never expire if it is zero.
+	Expiry time.Duration
+	// ReplayRate is the maximum number of bytes per second replayed to all the nodes.
+	// The replay is not throttled if it is zero.
+	ReplayRate run.Bytes
+}
+
+// PartCodec adapts the controller to the part layout of a storage module.
+type PartCodec interface {
+	// Topic returns the part sync topic the queued parts are replayed to.
+	Topic() string
+	// PartSize returns the compressed size of the part stored in partPath.
+	PartSize(fileSystem fs.FileSystem, partPath, partType string) uint64
+	// StreamingFileName maps a file of a queued part to its name in the part sync stream.
+	// The file is not sent if it returns false.
+	StreamingFileName(partType, fileName string) (string, bool)
+	// FillPartMetadata copies the metadata of the queued part in partPath to the streaming part.
+	FillPartMetadata(fileSystem fs.FileSystem, partPath string, streamingPart *queue.StreamingPartData)
+}
+
+// QueueClient combines health checking and sync client creation.
+type QueueClient interface {
+	HealthyNodes() []string
+	NewChunkedSyncClient(node string, chunkSize uint32) (queue.ChunkedSyncClient, error)
+}
+
+// PartInfo describes a part to be enqueued.
+type PartInfo struct {
+	Path     string
+	Group    string
+	PartType string
+	PartID   uint64
+	ShardID  common.ShardID
+}
+
+// Controller manages handoff queues for multiple data nodes.
+type Controller struct {
 	fileSystem              fs.FileSystem
-	tire2Client             queueClient
+	tire2Client             QueueClient
+	codec                   PartCodec
+	replayNext              time.Time
 	resolveShardAssignments func(group string, shardID uint32) ([]string, error)
 	inFlightSends           map[string]map[uint64]struct{}
 	l                       *logger.Logger
+	metrics                 *metrics
 	replayTriggerChan       chan string
-	nodeQueues              map[string]*handoffNodeQueue
+	nodeQueues              map[string]*nodeQueue
 	replayStopChan          chan struct{}
 	healthyNodes            map[string]struct{}
 	statusChangeChan        chan nodeStatusChange
 	stopMonitor             chan struct{}
+	nodeSizes               map[string]uint64
+	nodeParts               map[string]int64
 	root                    string
 	allDataNodes            []string
 	monitorWg               sync.WaitGroup
@@ -57,11 +105,16 @@ type handoffController struct {
 	checkInterval           time.Duration
 	replayBatchSize         int
 	replayPollInterval      time.Duration
+	expiryCheckInterval     time.Duration
+	expiry                  time.Duration
+	replayRate              uint64
 	maxTotalSizeBytes       uint64
+	maxNodeSizeBytes        uint64
 	currentTotalSize        uint64
 	mu                      sync.RWMutex
 	inFlightMu              sync.RWMutex
 	sizeMu                  sync.RWMutex
+	replayMu                sync.Mutex
 }
 
 // nodeStatusChange represents a node status transition.
@@ -70,20 +123,18 @@ type nodeStatusChange struct {
 	isOnline bool
 }
 
-// queueClient interface combines health checking and sync client creation.
-type queueClient interface {
-	HealthyNodes() []string
-	NewChunkedSyncClient(node string, chunkSize uint32) (queue.ChunkedSyncClient, error)
-}
-
-// newHandoffController creates a new handoff controller.
-func newHandoffController(fileSystem fs.FileSystem, root string, tire2Client queueClient,
-	dataNodeList []string, maxTotalSizeBytes uint64, l *logger.Logger,
+// NewController creates a new handoff controller whose queues live in <root>/handoff/nodes.
+// The metrics are not collected if factory is nil.
+func NewController(fileSystem fs.FileSystem, root string, tire2Client QueueClient, codec PartCodec,
+	dataNodeList []string, cfg Config, l *logger.Logger, factory observability.Factory,
 	resolveShardAssignments func(group string, shardID uint32) ([]string, error),
-) (*handoffController, error) {
+) (*Controller, error) {
 	if fileSystem == nil {
 		return nil, fmt.Errorf("fileSystem is nil")
 	}
+	if codec == nil {
+		return nil, fmt.Errorf("part codec is nil")
+	}
 	if l == nil {
 		return nil, fmt.Errorf("logger is nil")
 	}
@@ -93,10 +144,12 @@ func newHandoffController(fileSystem fs.FileSystem, root string, tire2Client que
 
 	handoffRoot := filepath.Join(root, "handoff", "nodes")
 
-	hc := &handoffController{
+	hc := &Controller{
 		l:                       l,
 		fileSystem:              fileSystem,
-		nodeQueues:              make(map[string]*handoffNodeQueue),
+		codec:                   codec,
+		metrics:                 newMetrics(factory),
+		nodeQueues:              make(map[string]*nodeQueue),
 		root:                    handoffRoot,
 		tire2Client:             tire2Client,
 		allDataNodes:            dataNodeList,
@@ -109,8 +162,13 @@ func newHandoffController(fileSystem fs.FileSystem, root string, tire2Client que
 		inFlightSends:           make(map[string]map[uint64]struct{}),
 		replayBatchSize:         10,
 		replayPollInterval:      1 * time.Second,
-		maxTotalSizeBytes:       maxTotalSizeBytes,
-		currentTotalSize:        0,
+		expiryCheckInterval:     time.Minute,
+		expiry:                  cfg.Expiry,
+		replayRate:              uint64(cfg.ReplayRate),
+		maxTotalSizeBytes:       cfg.MaxTotalSizeBytes,
+		maxNodeSizeBytes:        cfg.MaxNodeSizeBytes,
+		nodeSizes:               make(map[string]uint64),
+		nodeParts:               make(map[string]int64),
 		resolveShardAssignments: resolveShardAssignments,
 	}
 
@@ -132,7 +190,7 @@ func newHandoffController(fileSystem fs.FileSystem, root string, tire2Client que
 }
 
 // loadExistingQueues scans the handoff directory and loads existing node queues.
-func (hc *handoffController) loadExistingQueues() error {
+func (hc *Controller) loadExistingQueues() error {
 	if hc == nil || hc.fileSystem == nil {
 		return fmt.Errorf("handoff controller is not initialized")
 	}
@@ -156,7 +214,7 @@ func (hc *handoffController) loadExistingQueues() error {
 			continue
 		}
 
-		nodeQueue, err := newHandoffNodeQueue(nodeAddr, nodeRoot, hc.fileSystem, hc.l)
+		nodeQueue, err := newNodeQueue(nodeAddr, nodeRoot, hc.fileSystem, hc.l)
 		if err != nil {
 			hc.l.Warn().Err(err).Str("node", nodeAddr).Msg("failed to load node queue")
 			errs = append(errs, fmt.Errorf("load node queue for %s: %w", nodeAddr, err))
@@ -183,11 +241,11 @@ func (hc *handoffController) loadExistingQueues() error {
 					Msg("failed to read part metadata")
 				errs = append(errs, fmt.Errorf("metadata for node %s part %x (%s): %w",
 					nodeAddr, ptp.PartID, ptp.PartType, err))
+				hc.addPart(nodeAddr, 0)
 				continue
 			}
-			if meta.PartSizeBytes > 0 {
-				queueSize += meta.PartSizeBytes
-			}
+			queueSize += meta.PartSizeBytes
+			hc.addPart(nodeAddr, meta.PartSizeBytes)
 		}
 		totalRecoveredSize += queueSize
 
@@ -201,8 +259,6 @@ func (hc *handoffController) loadExistingQueues() error {
 		}
 	}
 
-	// Update current total size
-	hc.currentTotalSize = totalRecoveredSize
 	if totalRecoveredSize > 0 {
 		hc.l.Info().
 			Uint64("totalSizeMB", totalRecoveredSize/1024/1024).
@@ -217,23 +273,22 @@ func (hc *handoffController) loadExistingQueues() error {
 }
 
 // enqueueForNode adds a part to the handoff queue for a specific node.
-func (hc *handoffController) enqueueForNode(nodeAddr string, partID uint64, partType string, sourcePath string,
+func (hc *Controller) enqueueForNode(nodeAddr string, partID uint64, partType string, sourcePath string,
 	group string, shardID uint32,
 ) error {
 	// Read part size from metadata
-	partSize := hc.readPartSizeFromMetadata(sourcePath, partType)
+	partSize := hc.codec.PartSize(hc.fileSystem, sourcePath, partType)
 
-	// Check if enqueue would exceed limit
-	if !hc.canEnqueue(partSize) {
-		currentSize := hc.getTotalSize()
-		return fmt.Errorf("handoff queue full: current=%d MB, limit=%d MB, part=%d MB",
-			currentSize/1024/1024, hc.maxTotalSizeBytes/1024/1024, partSize/1024/1024)
+	// Check if enqueue would exceed the total or the per-node limit
+	if err := hc.checkCapacity(nodeAddr, partSize); err != nil {
+		hc.metrics.incRejected(nodeAddr)
+		return err
 	}
 
 	hc.mu.Lock()
 	defer hc.mu.Unlock()
 
-	meta := &handoffMetadata{
+	meta := &queueMetadata{
 		EnqueueTimestamp: time.Now().UnixNano(),
 		Group:            group,
 		ShardID:          shardID,
@@ -246,45 +301,30 @@ func (hc *handoffController) enqueueForNode(nodeAddr string, partID uint64, part
 		return fmt.Errorf("failed to get node queue for %s: %w", nodeAddr, err)
 	}
 
+	// A part queued before is not counted twice
+	if _, err := nodeQueue.getMetadata(partID, partType); err == nil {
+		return nil
+	}
 	if err := nodeQueue.enqueue(partID, partType, sourcePath, meta); err != nil {
 		return err
 	}
 
-	// Update total size after successful enqueue
-	hc.updateTotalSize(int64(partSize))
+	// Update sizes after successful enqueue
+	hc.addPart(nodeAddr, partSize)
+	hc.metrics.incEnqueued(nodeAddr)
 
 	return nil
 }
 
 // enqueueForNodes adds a part to the handoff queues for multiple offline nodes.
-func (hc *handoffController) enqueueForNodes(offlineNodes []string, partID uint64, partType string, sourcePath string,
+func (hc *Controller) enqueueForNodes(offlineNodes []string, partID uint64, partType string, sourcePath string,
 	group string, shardID uint32,
 ) error {
-	meta := &handoffMetadata{
-		EnqueueTimestamp: time.Now().UnixNano(),
-		Group:            group,
-		ShardID:          shardID,
-		PartType:         partType,
-	}
-
-	hc.mu.Lock()
-	defer hc.mu.Unlock()
-
 	var firstErr error
 	successCount := 0
 
-	// For each offline node, create hard-linked copy
 	for _, nodeAddr := range offlineNodes {
-		nodeQueue, err := hc.getOrCreateNodeQueue(nodeAddr)
-		if err != nil {
-			hc.l.Error().Err(err).Str("node", nodeAddr).Msg("failed to get node queue")
-			if firstErr == nil {
-				firstErr = err
-			}
-			continue
-		}
-
-		if err := nodeQueue.enqueue(partID, partType, sourcePath, meta); err != nil {
+		if err := hc.enqueueForNode(nodeAddr, partID, partType, sourcePath, group, shardID); err != nil {
 			hc.l.Error().Err(err).Str("node", nodeAddr).Uint64("partId", partID).Str("partType", partType).
 				Msg("failed to enqueue part")
 			if firstErr == nil {
@@ -292,7 +332,6 @@ func (hc *handoffController) enqueueForNodes(offlineNodes []string, partID uint6
 			}
 			continue
 		}
-
 		successCount++
 	}
 
@@ -315,7 +354,7 @@ func (hc *handoffController) enqueueForNodes(offlineNodes []string, partID uint6
 
 // getOrCreateNodeQueue gets an existing node queue or creates a new one.
 // Caller must hold hc.mu lock.
-func (hc *handoffController) getOrCreateNodeQueue(nodeAddr string) (*handoffNodeQueue, error) {
+func (hc *Controller) getOrCreateNodeQueue(nodeAddr string) (*nodeQueue, error) {
 	// Check if queue already exists
 	if queue, exists := hc.nodeQueues[nodeAddr]; exists {
 		return queue, nil
@@ -325,7 +364,7 @@ func (hc *handoffController) getOrCreateNodeQueue(nodeAddr string) (*handoffNode
 	sanitizedAddr := sanitizeNodeAddr(nodeAddr)
 	nodeRoot := filepath.Join(hc.root, sanitizedAddr)
 
-	nodeQueue, err := newHandoffNodeQueue(nodeAddr, nodeRoot, hc.fileSystem, hc.l)
+	nodeQueue, err := newNodeQueue(nodeAddr, nodeRoot, hc.fileSystem, hc.l)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create node queue: %w", err)
 	}
@@ -335,7 +374,7 @@ func (hc *handoffController) getOrCreateNodeQueue(nodeAddr string) (*handoffNode
 }
 
 // listPendingForNode returns all pending parts with their types for a specific node.
-func (hc *handoffController) listPendingForNode(nodeAddr string) ([]partTypePair, error) {
+func (hc *Controller) listPendingForNode(nodeAddr string) ([]partTypePair, error) {
 	hc.mu.RLock()
 	defer hc.mu.RUnlock()
 
@@ -348,7 +387,7 @@ func (hc *handoffController) listPendingForNode(nodeAddr string) ([]partTypePair
 }
 
 // getPartPath returns the path to a specific part type directory in a node's handoff queue.
-func (hc *handoffController) getPartPath(nodeAddr string, partID uint64, partType string) string {
+func (hc *Controller) getPartPath(nodeAddr string, partID uint64, partType string) string {
 	hc.mu.RLock()
 	defer hc.mu.RUnlock()
 
@@ -361,7 +400,7 @@ func (hc *handoffController) getPartPath(nodeAddr string, partID uint64, partTyp
 }
 
 // getPartMetadata returns the handoff metadata for a specific part type.
-func (hc *handoffController) getPartMetadata(nodeAddr string, partID uint64, partType string) (*handoffMetadata, error) {
+func (hc *Controller) getPartMetadata(nodeAddr string, partID uint64, partType string) (*queueMetadata, error) {
 	hc.mu.RLock()
 	defer hc.mu.RUnlock()
 
@@ -374,13 +413,9 @@ func (hc *handoffController) getPartMetadata(nodeAddr string, partID uint64, par
 }
 
 // completeSend removes a specific part type from a node's handoff queue after successful delivery.
-func (hc *handoffController) completeSend(nodeAddr string, partID uint64, partType string) error {
-	// Get part size before removing
-	var partSize uint64
-	meta, err := hc.getPartMetadata(nodeAddr, partID, partType)
-	if err == nil && meta.PartSizeBytes > 0 {
-		partSize = meta.PartSizeBytes
-	}
+func (hc *Controller) completeSend(nodeAddr string, partID uint64, partType string) error {
+	// Get part size before removing. A part without metadata was removed before.
+	meta, metaErr := hc.getPartMetadata(nodeAddr, partID, partType)
 
 	hc.mu.RLock()
 	nodeQueue, exists := hc.nodeQueues[nodeAddr]
@@ -394,16 +429,16 @@ func (hc *handoffController) completeSend(nodeAddr string, partID uint64, partTy
 		return err
 	}
 
-	// Update total size after successful removal
-	if partSize > 0 {
-		hc.updateTotalSize(-int64(partSize))
+	// Update sizes after successful removal
+	if metaErr == nil {
+		hc.removePart(nodeAddr, meta.PartSizeBytes)
 	}
 
 	return nil
 }
 
 // completeSendAll removes all part types for a given partID from a node's handoff queue.
-func (hc *handoffController) completeSendAll(nodeAddr string, partID uint64) error {
+func (hc *Controller) completeSendAll(nodeAddr string, partID uint64) error {
 	hc.mu.RLock()
 	defer hc.mu.RUnlock()
 
@@ -412,11 +447,30 @@ func (hc *handoffController) completeSendAll(nodeAddr string, partID uint64) err
 		return fmt.Errorf("node queue not found for %s", nodeAddr)
 	}
 
-	return nodeQueue.completeAll(partID)
+	pending, err := nodeQueue.listPending()
+	if err != nil {
+		return err
+	}
+	var removed []uint64
+	for _, ptp := range pending {
+		if ptp.PartID != partID {
+			continue
+		}
+		if meta, metaErr := nodeQueue.getMetadata(ptp.PartID, ptp.PartType); metaErr == nil {
+			removed = append(removed, meta.PartSizeBytes)
+		}
+	}
+	if err := nodeQueue.completeAll(partID); err != nil {
+		return err
+	}
+	for _, partSize := range removed {
+		hc.removePart(nodeAddr, partSize)
+	}
+	return nil
 }
 
 // getNodeQueueSize returns the total size of pending parts for a specific node.
-func (hc *handoffController) getNodeQueueSize(nodeAddr string) (uint64, error) {
+func (hc *Controller) getNodeQueueSize(nodeAddr string) (uint64, error) {
 	hc.mu.RLock()
 	defer hc.mu.RUnlock()
 
@@ -429,7 +483,7 @@ func (hc *handoffController) getNodeQueueSize(nodeAddr string) (uint64, error) {
 }
 
 // getAllNodeQueues returns a snapshot of all node addresses with handoff queues.
-func (hc *handoffController) getAllNodeQueues() []string {
+func (hc *Controller) getAllNodeQueues() []string {
 	hc.mu.RLock()
 	defer hc.mu.RUnlock()
 
@@ -441,16 +495,8 @@ func (hc *handoffController) getAllNodeQueues() []string {
 	return nodes
 }
 
-// partInfo contains information about a part to be enqueued.
-type partInfo struct {
-	path    string
-	group   string
-	partID  uint64
-	shardID common.ShardID
-}
-
-// calculateOfflineNodes returns the list of offline nodes responsible for the shard.
-func (hc *handoffController) calculateOfflineNodes(onlineNodes []string, group string, shardID common.ShardID) []string {
+// CalculateOfflineNodes returns the list of offline nodes responsible for the shard.
+func (hc *Controller) CalculateOfflineNodes(onlineNodes []string, group string, shardID common.ShardID) []string {
 	if hc == nil {
 		return nil
 	}
@@ -486,64 +532,40 @@ func (hc *handoffController) calculateOfflineNodes(onlineNodes []string, group s
 	return offlineNodes
 }
 
-// enqueueForOfflineNodes enqueues the provided core and sidx parts for each offline node.
-func (hc *handoffController) enqueueForOfflineNodes(
-	offlineNodes []string,
-	coreParts []partInfo,
-	sidxParts map[string][]partInfo,
-) error {
-	if hc == nil || len(offlineNodes) == 0 {
+// EnqueueForOfflineNodes enqueues the provided parts for each offline node responsible for their shard.
+func (hc *Controller) EnqueueForOfflineNodes(offlineNodes []string, parts []PartInfo) error {
+	if hc == nil || len(offlineNodes) == 0 || len(parts) == 0 {
 		return nil
 	}
 
-	group, shardID, hasShardInfo := extractShardDetails(coreParts, sidxParts)
-	if hasShardInfo {
-		filtered := hc.filterNodesForShard(offlineNodes, group, shardID)
-		if len(filtered) == 0 {
-			hc.l.Debug().
-				Str("group", group).
-				Uint32("shardID", shardID).
-				Msg("no offline shard owners to enqueue")
-			return nil
-		}
-		offlineNodes = filtered
+	group, shardID := parts[0].Group, uint32(parts[0].ShardID)
+	filtered := hc.filterNodesForShard(offlineNodes, group, shardID)
+	if len(filtered) == 0 {
+		hc.l.Debug().
+			Str("group", group).
+			Uint32("shardID", shardID).
+			Msg("no offline shard owners to enqueue")
+		return nil
 	}
+	offlineNodes = filtered
 
 	// Track enqueue statistics
-	totalCoreEnqueued := 0
-	totalSidxEnqueued := 0
+	totalEnqueued := 0
 	var lastErr error
 
 	// For each offline node, enqueue all parts
 	for _, nodeAddr := range offlineNodes {
-		// Enqueue core parts
-		for _, coreInfo := range coreParts {
-			err := hc.enqueueForNode(nodeAddr, coreInfo.partID, PartTypeCore, coreInfo.path, coreInfo.group, uint32(coreInfo.shardID))
+		for _, info := range parts {
+			err := hc.enqueueForNode(nodeAddr, info.PartID, info.PartType, info.Path, info.Group, uint32(info.ShardID))
 			if err != nil {
 				hc.l.Warn().Err(err).
 					Str("node", nodeAddr).
-					Uint64("partID", coreInfo.partID).
-					Msg("failed to enqueue core part")
+					Str("partType", info.PartType).
+					Uint64("partID", info.PartID).
+					Msg("failed to enqueue part")
 				lastErr = err
 			} else {
-				totalCoreEnqueued++
-			}
-		}
-
-		// Enqueue sidx parts
-		for sidxName, parts := range sidxParts {
-			for _, sidxInfo := range parts {
-				err := hc.enqueueForNode(nodeAddr, sidxInfo.partID, sidxName, sidxInfo.path, sidxInfo.group, uint32(sidxInfo.shardID))
-				if err != nil {
-					hc.l.Warn().Err(err).
-						Str("node", nodeAddr).
-						Str("sidx", sidxName).
-						Uint64("partID", sidxInfo.partID).
-						Msg("failed to enqueue sidx part")
-					lastErr = err
-				} else {
-					totalSidxEnqueued++
-				}
+				totalEnqueued++
 			}
 		}
 	}
@@ -553,27 +575,13 @@ func (hc *handoffController) enqueueForOfflineNodes(
 		Int("offlineNodes", len(offlineNodes)).
 		Str("group", group).
 		Uint32("shardID", shardID).
-		Int("corePartsEnqueued", totalCoreEnqueued).
-		Int("sidxPartsEnqueued", totalSidxEnqueued).
+		Int("partsEnqueued", totalEnqueued).
 		Msg("enqueued parts for offline nodes")
 
 	return lastErr
 }
 
-func extractShardDetails(coreParts []partInfo, sidxParts map[string][]partInfo) (string, uint32, bool) {
-	if len(coreParts) > 0 {
-		return coreParts[0].group, uint32(coreParts[0].shardID), true
-	}
-	for _, parts := range sidxParts {
-		if len(parts) == 0 {
-			continue
-		}
-		return parts[0].group, uint32(parts[0].shardID), true
-	}
-	return "", 0, false
-}
-
-func (hc *handoffController) nodesForShard(group string, shardID uint32) []string {
+func (hc *Controller) nodesForShard(group string, shardID uint32) []string {
 	if hc == nil {
 		return nil
 	}
@@ -597,7 +605,7 @@ func (hc *handoffController) nodesForShard(group string, shardID uint32) []strin
 	return nodes
 }
 
-func (hc *handoffController) filterNodesForShard(nodes []string, group string, shardID uint32) []string {
+func (hc *Controller) filterNodesForShard(nodes []string, group string, shardID uint32) []string {
 	candidates := hc.nodesForShard(group, shardID)
 	if len(candidates) == 0 {
 		return nil
@@ -620,7 +628,8 @@ func (hc *handoffController) filterNodesForShard(nodes []string, group string, s
 	return filtered
 }
 
-func (hc *handoffController) deletePartsByGroup(group string) {
+// DeletePartsByGroup drops the queued parts of a deleted group.
+func (hc *Controller) DeletePartsByGroup(group string) {
 	hc.mu.Lock()
 	defer hc.mu.Unlock()
 
@@ -652,9 +661,7 @@ func (hc *handoffController) deletePartsByGroup(group string) {
 					Msg("failed to remove part during group cleanup")
 				continue
 			}
-			if meta.PartSizeBytes > 0 {
-				hc.updateTotalSize(-int64(meta.PartSizeBytes))
-			}
+			hc.removePart(nodeAddr, meta.PartSizeBytes)
 			totalRemoved++
 		}
 	}
@@ -666,8 +673,8 @@ func (hc *handoffController) deletePartsByGroup(group string) {
 	}
 }
 
-// close closes the handoff controller.
-func (hc *handoffController) close() error {
+// Close stops the monitor and the replay worker of the handoff controller.
+func (hc *Controller) Close() error {
 	// Stop the monitor
 	if hc.stopMonitor != nil {
 		close(hc.stopMonitor)
@@ -693,88 +700,138 @@ func (hc *handoffController) close() error {
 }
 
 // getTotalSize returns the current total size across all node queues.
-func (hc *handoffController) getTotalSize() uint64 {
+func (hc *Controller) getTotalSize() uint64 {
 	hc.sizeMu.RLock()
 	defer hc.sizeMu.RUnlock()
 	return hc.currentTotalSize
 }
 
-// stats returns handoff statistics including part count and total size.
-func (hc *handoffController) stats() (partCount int64, totalSize int64) {
-	hc.mu.RLock()
-	defer hc.mu.RUnlock()
-	var count int64
-	for _, nodeQueue := range hc.nodeQueues {
-		pending, listErr := nodeQueue.listPending()
-		if listErr != nil {
-			continue
-		}
-		count += int64(len(pending))
+// Stats returns the number and the total size of the queued parts.
+func (hc *Controller) Stats() (partCount int64, totalSize int64) {
+	hc.sizeMu.RLock()
+	defer hc.sizeMu.RUnlock()
+	for _, parts := range hc.nodeParts {
+		partCount += parts
 	}
-	return count, int64(hc.getTotalSize())
+	return partCount, int64(hc.currentTotalSize)
 }
 
-// canEnqueue checks if adding a part of the given size would exceed the total size limit.
-func (hc *handoffController) canEnqueue(partSize uint64) bool {
-	if hc.maxTotalSizeBytes == 0 {
-		return true // No limit configured
-	}
-
+// checkCapacity checks if adding a part of the given size would exceed the total or the per-node size limit.
+func (hc *Controller) checkCapacity(nodeAddr string, partSize uint64) error {
 	hc.sizeMu.RLock()
 	defer hc.sizeMu.RUnlock()
-	return hc.currentTotalSize+partSize <= hc.maxTotalSizeBytes
+	if hc.maxTotalSizeBytes > 0 && hc.currentTotalSize+partSize > hc.maxTotalSizeBytes {
+		return fmt.Errorf("handoff queue full: current=%d MB, limit=%d MB, part=%d MB",
+			hc.currentTotalSize/1024/1024, hc.maxTotalSizeBytes/1024/1024, partSize/1024/1024)
+	}
+	if hc.maxNodeSizeBytes > 0 && hc.nodeSizes[nodeAddr]+partSize > hc.maxNodeSizeBytes {
+		return fmt.Errorf("handoff queue of node %s full: current=%d MB, limit=%d MB, part=%d MB",
+			nodeAddr, hc.nodeSizes[nodeAddr]/1024/1024, hc.maxNodeSizeBytes/1024/1024, partSize/1024/1024)
+	}
+	return nil
 }
 
-// readPartSizeFromMetadata reads the CompressedSizeBytes from the part's metadata file.
-func (hc *handoffController) readPartSizeFromMetadata(sourcePath, partType string) uint64 {
-	var metadataPath string
+// addPart accounts a part queued for a node.
+func (hc *Controller) addPart(nodeAddr string, partSize uint64) {
+	hc.sizeMu.Lock()
+	defer hc.sizeMu.Unlock()
+	hc.currentTotalSize += partSize
+	hc.nodeSizes[nodeAddr] += partSize
+	hc.nodeParts[nodeAddr]++
+	hc.metrics.setPending(nodeAddr, hc.nodeParts[nodeAddr], hc.nodeSizes[nodeAddr])
+}
 
-	// Core parts use metadata.json, sidx parts use manifest.json
-	if partType == PartTypeCore {
-		metadataPath = filepath.Join(sourcePath, metadataFilename) // "metadata.json"
-	} else {
-		metadataPath = filepath.Join(sourcePath, "manifest.json")
+// removePart releases the accounting of a part removed from the queue of a node.
+func (hc *Controller) removePart(nodeAddr string, partSize uint64) {
+	hc.sizeMu.Lock()
+	defer hc.sizeMu.Unlock()
+	if partSize > hc.currentTotalSize || partSize > hc.nodeSizes[nodeAddr] {
+		hc.l.Warn().
+			Str("node", nodeAddr).
+			Uint64("current", hc.currentTotalSize).
+			Uint64("nodeCurrent", hc.nodeSizes[nodeAddr]).
+			Uint64("toSubtract", partSize).
+			Msg("attempted to subtract more than current size, resetting to 0")
 	}
+	hc.currentTotalSize -= min(partSize, hc.currentTotalSize)
+	hc.nodeSizes[nodeAddr] -= min(partSize, hc.nodeSizes[nodeAddr])
+	if hc.nodeParts[nodeAddr] > 0 {
+		hc.nodeParts[nodeAddr]--
+	}
+	hc.metrics.setPending(nodeAddr, hc.nodeParts[nodeAddr], hc.nodeSizes[nodeAddr])
+}
 
-	// Read metadata file
-	data, err := hc.fileSystem.Read(metadataPath)
-	if err != nil {
-		hc.l.Warn().Err(err).Str("path", metadataPath).Msg("failed to read metadata file")
+// expireParts drops the parts queued longer than the expiry and returns how many are dropped.
+func (hc *Controller) expireParts(now time.Time) int {
+	if hc.expiry <= 0 {
 		return 0
 	}
+	deadline := now.Add(-hc.expiry).UnixNano()
 
-	// Parse metadata to get CompressedSizeBytes
-	var metadata struct {
-		CompressedSizeBytes uint64 `json:"compressedSizeBytes"`
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	var expired int
+	for nodeAddr, nodeQueue := range hc.nodeQueues {
+		pending, listErr := nodeQueue.listPending()
+		if listErr != nil {
+			hc.l.Warn().Err(listErr).Str("node", nodeAddr).Msg("failed to list pending parts for expiry")
+			continue
+		}
+		for _, ptp := range pending {
+			if hc.isInFlight(nodeAddr, ptp.PartID) {
+				continue
+			}
+			meta, metaErr := nodeQueue.getMetadata(ptp.PartID, ptp.PartType)
+			if metaErr != nil || meta.EnqueueTimestamp > deadline {
+				continue
+			}
+			if completeErr := nodeQueue.complete(ptp.PartID, ptp.PartType); completeErr != nil {
+				hc.l.Warn().Err(completeErr).
+					Str("node", nodeAddr).
+					Uint64("partID", ptp.PartID).
+					Str("partType", ptp.PartType).
+					Msg("failed to remove expired part")
+				continue
+			}
+			hc.removePart(nodeAddr, meta.PartSizeBytes)
+			hc.metrics.incExpired(nodeAddr)
+			expired++
+		}
 	}
-	if err := json.Unmarshal(data, &metadata); err != nil {
-		hc.l.Warn().Err(err).Str("path", metadataPath).Msg("failed to parse metadata")
-		return 0
+	if expired > 0 {
+		hc.l.Warn().
+			Int("expiredParts", expired).
+			Dur("expiry", hc.expiry).
+			Msg("dropped expired handoff parts, the offline nodes have to be repaired")
 	}
-
-	return metadata.CompressedSizeBytes
+	return expired
 }
 
-// updateTotalSize atomically updates the current total size.
-func (hc *handoffController) updateTotalSize(delta int64) {
-	hc.sizeMu.Lock()
-	defer hc.sizeMu.Unlock()
-
-	if delta > 0 {
-		// Enqueue: add to total
-		hc.currentTotalSize += uint64(delta)
-	} else if delta < 0 {
-		// Complete: subtract from total with underflow check
-		toSubtract := uint64(-delta)
-		if toSubtract > hc.currentTotalSize {
-			hc.l.Warn().
-				Uint64("current", hc.currentTotalSize).
-				Uint64("toSubtract", toSubtract).
-				Msg("attempted to subtract more than current size, resetting to 0")
-			hc.currentTotalSize = 0
-		} else {
-			hc.currentTotalSize -= toSubtract
-		}
+// pace blocks until replaying n more bytes doesn't exceed the replay rate.
+// It returns false if the replay worker is stopped while waiting.
+func (hc *Controller) pace(n uint64) bool {
+	if hc.replayRate == 0 {
+		return true
+	}
+	hc.replayMu.Lock()
+	now := time.Now()
+	if hc.replayNext.Before(now) {
+		hc.replayNext = now
+	}
+	hc.replayNext = hc.replayNext.Add(time.Duration(n * uint64(time.Second) / hc.replayRate))
+	wait := hc.replayNext.Sub(now)
+	hc.replayMu.Unlock()
+	if wait <= 0 {
+		return true
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-hc.replayStopChan:
+		return false
 	}
 }
 
@@ -789,7 +846,7 @@ func sanitizeNodeAddr(addr string) string {
 }
 
 // startMonitor starts the background node status monitoring goroutine.
-func (hc *handoffController) startMonitor() {
+func (hc *Controller) startMonitor() {
 	if hc.tire2Client == nil || len(hc.allDataNodes) == 0 {
 		hc.l.Info().Msg("node status monitor disabled (no tire2 client or data nodes)")
 		return
@@ -816,7 +873,7 @@ func (hc *handoffController) startMonitor() {
 }
 
 // pollNodeStatus periodically polls the pub client for healthy nodes.
-func (hc *handoffController) pollNodeStatus() {
+func (hc *Controller) pollNodeStatus() {
 	defer hc.monitorWg.Done()
 
 	ticker := time.NewTicker(hc.checkInterval)
@@ -833,7 +890,7 @@ func (hc *handoffController) pollNodeStatus() {
 }
 
 // checkAndNotifyStatusChanges compares current vs previous health status.
-func (hc *handoffController) checkAndNotifyStatusChanges() {
+func (hc *Controller) checkAndNotifyStatusChanges() {
 	if hc.tire2Client == nil {
 		return
 	}
@@ -875,7 +932,7 @@ func (hc *handoffController) checkAndNotifyStatusChanges() {
 }
 
 // handleStatusChanges processes node status changes.
-func (hc *handoffController) handleStatusChanges() {
+func (hc *Controller) handleStatusChanges() {
 	defer hc.monitorWg.Done()
 
 	for {
@@ -893,7 +950,7 @@ func (hc *handoffController) handleStatusChanges() {
 }
 
 // onNodeOnline handles a node coming online.
-func (hc *handoffController) onNodeOnline(nodeName string) {
+func (hc *Controller) onNodeOnline(nodeName string) {
 	hc.mu.RLock()
 	_, hasQueue := hc.nodeQueues[nodeName]
 	hc.mu.RUnlock()
@@ -913,13 +970,13 @@ func (hc *handoffController) onNodeOnline(nodeName string) {
 }
 
 // onNodeOffline handles a node going offline.
-func (hc *handoffController) onNodeOffline(nodeName string) {
+func (hc *Controller) onNodeOffline(nodeName string) {
 	hc.l.Info().Str("node", nodeName).Msg("node went offline")
 	// No immediate action needed - syncer will detect send failures
 }
 
 // isNodeHealthy checks if a specific node is currently healthy.
-func (hc *handoffController) isNodeHealthy(nodeName string) bool {
+func (hc *Controller) isNodeHealthy(nodeName string) bool {
 	hc.mu.RLock()
 	defer hc.mu.RUnlock()
 	_, healthy := hc.healthyNodes[nodeName]
@@ -927,7 +984,7 @@ func (hc *handoffController) isNodeHealthy(nodeName string) bool {
 }
 
 // startReplayWorker starts the background replay worker goroutine.
-func (hc *handoffController) startReplayWorker() {
+func (hc *Controller) startReplayWorker() {
 	if hc.tire2Client == nil {
 		hc.l.Info().Msg("replay worker disabled (no tire2 client)")
 		return
@@ -943,7 +1000,7 @@ func (hc *handoffController) startReplayWorker() {
 }
 
 // replayWorkerLoop is the main replay worker loop.
-func (hc *handoffController) replayWorkerLoop() {
+func (hc *Controller) replayWorkerLoop() {
 	defer hc.replayWg.Done()
 
 	ticker := time.NewTicker(hc.replayPollInterval)
@@ -951,6 +1008,7 @@ func (hc *handoffController) replayWorkerLoop() {
 
 	// Track nodes that have been triggered for replay
 	triggeredNodes := make(map[string]struct{})
+	lastExpiry := time.Now()
 
 	for {
 		select {
@@ -959,7 +1017,13 @@ func (hc *handoffController) replayWorkerLoop() {
 			triggeredNodes[nodeAddr] = struct{}{}
 			hc.l.Debug().Str("node", nodeAddr).Msg("node marked for replay")
 
-		case <-ticker.C:
+		case now := <-ticker.C:
+			// Drop the parts queued longer than the expiry
+			if now.Sub(lastExpiry) >= hc.expiryCheckInterval {
+				hc.expireParts(now)
+				lastExpiry = now
+			}
+
 			// Periodic check for work
 			nodesWithWork := hc.getNodesWithPendingParts()
 
@@ -1013,7 +1077,7 @@ func (hc *handoffController) replayWorkerLoop() {
 
 // replayBatchForNode processes a batch of parts for a specific node.
 // Returns the number of parts successfully replayed and any error.
-func (hc *handoffController) replayBatchForNode(nodeAddr string, maxParts int) (int, error) {
+func (hc *Controller) replayBatchForNode(nodeAddr string, maxParts int) (int, error) {
 	// Get pending parts for this node
 	pending, err := hc.listPendingForNode(nodeAddr)
 	if err != nil {
@@ -1058,6 +1122,17 @@ func (hc *handoffController) replayBatchForNode(nodeAddr string, maxParts int) (
 			continue
 		}
 
+		// Throttle the replay before sending
+		var partSize uint64
+		if meta, metaErr := hc.getPartMetadata(nodeAddr, ptp.PartID, ptp.PartType); metaErr == nil {
+			partSize = meta.PartSizeBytes
+		}
+		if !hc.pace(partSize) {
+			release()
+			hc.markInFlight(nodeAddr, ptp.PartID, false)
+			return successCount, nil
+		}
+
 		// Send part to node
 		err = hc.sendPartToNode(ctx, nodeAddr, streamingPart)
 		release()
@@ -1067,9 +1142,11 @@ func (hc *handoffController) replayBatchForNode(nodeAddr string, maxParts int) (
 				Uint64("partID", ptp.PartID).
 				Str("partType", ptp.PartType).
 				Msg("failed to send part during replay")
+			hc.metrics.incReplayErr(nodeAddr)
 			hc.markInFlight(nodeAddr, ptp.PartID, false)
 			continue
 		}
+		hc.metrics.incReplayed(nodeAddr, partSize)
 
 		// Mark as complete
 		if err := hc.completeSend(nodeAddr, ptp.PartID, ptp.PartType); err != nil {
@@ -1096,7 +1173,7 @@ func (hc *handoffController) replayBatchForNode(nodeAddr string, maxParts int) (
 }
 
 // markInFlight marks a part as being sent (or removes the mark).
-func (hc *handoffController) markInFlight(nodeAddr string, partID uint64, inFlight bool) {
+func (hc *Controller) markInFlight(nodeAddr string, partID uint64, inFlight bool) {
 	hc.inFlightMu.Lock()
 	defer hc.inFlightMu.Unlock()
 
@@ -1118,7 +1195,7 @@ func (hc *handoffController) markInFlight(nodeAddr string, partID uint64, inFlig
 }
 
 // isInFlight checks if a part is currently being sent to a node.
-func (hc *handoffController) isInFlight(nodeAddr string, partID uint64) bool {
+func (hc *Controller) isInFlight(nodeAddr string, partID uint64) bool {
 	hc.inFlightMu.RLock()
 	defer hc.inFlightMu.RUnlock()
 
@@ -1130,7 +1207,7 @@ func (hc *handoffController) isInFlight(nodeAddr string, partID uint64) bool {
 }
 
 // getNodesWithPendingParts returns all node addresses that have pending parts in their queues.
-func (hc *handoffController) getNodesWithPendingParts() []string {
+func (hc *Controller) getNodesWithPendingParts() []string {
 	hc.mu.RLock()
 	defer hc.mu.RUnlock()
 
@@ -1146,7 +1223,7 @@ func (hc *handoffController) getNodesWithPendingParts() []string {
 }
 
 // readPartFromHandoff reads a part from the handoff queue and prepares it for sending.
-func (hc *handoffController) readPartFromHandoff(nodeAddr string, partID uint64, partType string) (*queue.StreamingPartData, func(), error) {
+func (hc *Controller) readPartFromHandoff(nodeAddr string, partID uint64, partType string) (*queue.StreamingPartData, func(), error) {
 	// Get the path to the hard-linked part
 	partPath := hc.getPartPath(nodeAddr, partID, partType)
 	if partPath == "" {
@@ -1170,7 +1247,7 @@ func (hc *handoffController) readPartFromHandoff(nodeAddr string, partID uint64,
 			continue
 		}
 
-		streamName, include := mapStreamingFileName(partType, entry.Name())
+		streamName, include := hc.codec.StreamingFileName(partType, entry.Name())
 		if !include {
 			continue
 		}
@@ -1205,26 +1282,13 @@ func (hc *handoffController) readPartFromHandoff(nodeAddr string, partID uint64,
 		ID:       partID,
 		Group:    meta.Group,
 		ShardID:  meta.ShardID,
-		Topic:    data.TopicTracePartSync.String(),
+		Topic:    hc.codec.Topic(),
 		Files:    files,
 		PartType: partType,
 	}
 
-	// For core parts, read additional metadata from metadata.json if present
-	if partType == PartTypeCore {
-		metadataPath := filepath.Join(partPath, metadataFilename)
-		if metadataBytes, err := hc.fileSystem.Read(metadataPath); err == nil {
-			var pm partMetadata
-			if err := json.Unmarshal(metadataBytes, &pm); err == nil {
-				streamingPart.CompressedSizeBytes = pm.CompressedSizeBytes
-				streamingPart.UncompressedSizeBytes = pm.UncompressedSpanSizeBytes
-				streamingPart.TotalCount = pm.TotalCount
-				streamingPart.BlocksCount = pm.BlocksCount
-				streamingPart.MinTimestamp = pm.MinTimestamp
-				streamingPart.MaxTimestamp = pm.MaxTimestamp
-			}
-		}
-	}
+	// Read the summary of the part, such as its size and time range, from its metadata
+	hc.codec.FillPartMetadata(hc.fileSystem, partPath, streamingPart)
 
 	release := func() {
 		for _, buf := range buffers {
@@ -1235,64 +1299,8 @@ func (hc *handoffController) readPartFromHandoff(nodeAddr string, partID uint64,
 	return streamingPart, release, nil
 }
 
-func mapStreamingFileName(partType, fileName string) (string, bool) {
-	if partType == PartTypeCore {
-		switch fileName {
-		case primaryFilename:
-			return tracePrimaryName, true
-		case spansFilename:
-			return traceSpansName, true
-		case metaFilename:
-			return traceMetaName, true
-		case traceIDFilterFilename, tagTypeFilename:
-			return fileName, true
-		case metadataFilename:
-			return "", false
-		}
-
-		if strings.HasSuffix(fileName, tagsFilenameExt) {
-			tagName := strings.TrimSuffix(fileName, tagsFilenameExt)
-			return traceTagsPrefix + tagName, true
-		}
-		if strings.HasSuffix(fileName, tagsMetadataFilenameExt) {
-			tagName := strings.TrimSuffix(fileName, tagsMetadataFilenameExt)
-			return traceTagMetadataPrefix + tagName, true
-		}
-
-		return "", false
-	}
-
-	switch fileName {
-	case sidx.SidxPrimaryName + ".bin":
-		return sidx.SidxPrimaryName, true
-	case sidx.SidxDataName + ".bin":
-		return sidx.SidxDataName, true
-	case sidx.SidxKeysName + ".bin":
-		return sidx.SidxKeysName, true
-	case sidx.SidxMetaName + ".bin":
-		return sidx.SidxMetaName, true
-	case "manifest.json":
-		return "", false
-	}
-
-	if strings.HasSuffix(fileName, ".td") {
-		tagName := strings.TrimSuffix(fileName, ".td")
-		return sidx.TagDataPrefix + tagName, true
-	}
-	if strings.HasSuffix(fileName, ".tm") {
-		tagName := strings.TrimSuffix(fileName, ".tm")
-		return sidx.TagMetadataPrefix + tagName, true
-	}
-	if strings.HasSuffix(fileName, ".tf") {
-		tagName := strings.TrimSuffix(fileName, ".tf")
-		return sidx.TagFilterPrefix + tagName, true
-	}
-
-	return "", false
-}
-
 // sendPartToNode sends a single part to a node using ChunkedSyncClient.
-func (hc *handoffController) sendPartToNode(ctx context.Context, nodeAddr string, streamingPart *queue.StreamingPartData) error {
+func (hc *Controller) sendPartToNode(ctx context.Context, nodeAddr string, streamingPart *queue.StreamingPartData) error {
 	// Create chunked sync client
 	chunkedClient, err := hc.tire2Client.NewChunkedSyncClient(nodeAddr, 1024*1024)
 	if err != nil {
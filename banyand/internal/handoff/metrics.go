@@ -0,0 +1,94 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handoff
+
+import (
+	"github.com/apache/skywalking-banyandb/banyand/observability"
+	"github.com/apache/skywalking-banyandb/pkg/meter"
+)
+
+type metrics struct {
+	pendingParts       meter.Gauge
+	pendingBytes       meter.Gauge
+	totalEnqueued      meter.Counter
+	totalRejected      meter.Counter
+	totalReplayed      meter.Counter
+	totalReplayedBytes meter.Counter
+	totalReplayErr     meter.Counter
+	totalExpired       meter.Counter
+}
+
+func newMetrics(factory observability.Factory) *metrics {
+	if factory == nil {
+		return nil
+	}
+	return &metrics{
+		pendingParts:       factory.NewGauge("pending_parts", "node"),
+		pendingBytes:       factory.NewGauge("pending_bytes", "node"),
+		totalEnqueued:      factory.NewCounter("total_enqueued", "node"),
+		totalRejected:      factory.NewCounter("total_rejected", "node"),
+		totalReplayed:      factory.NewCounter("total_replayed", "node"),
+		totalReplayedBytes: factory.NewCounter("total_replayed_bytes", "node"),
+		totalReplayErr:     factory.NewCounter("total_replay_err", "node"),
+		totalExpired:       factory.NewCounter("total_expired", "node"),
+	}
+}
+
+func (m *metrics) setPending(node string, parts int64, size uint64) {
+	if m == nil {
+		return
+	}
+	m.pendingParts.Set(float64(parts), node)
+	m.pendingBytes.Set(float64(size), node)
+}
+
+func (m *metrics) incEnqueued(node string) {
+	if m == nil {
+		return
+	}
+	m.totalEnqueued.Inc(1, node)
+}
+
+func (m *metrics) incRejected(node string) {
+	if m == nil {
+		return
+	}
+	m.totalRejected.Inc(1, node)
+}
+
+func (m *metrics) incReplayed(node string, size uint64) {
+	if m == nil {
+		return
+	}
+	m.totalReplayed.Inc(1, node)
+	m.totalReplayedBytes.Inc(float64(size), node)
+}
+
+func (m *metrics) incReplayErr(node string) {
+	if m == nil {
+		return
+	}
+	m.totalReplayErr.Inc(1, node)
+}
+
+func (m *metrics) incExpired(node string) {
+	if m == nil {
+		return
+	}
+	m.totalExpired.Inc(1, node)
+}
@@ -15,13 +15,14 @@
 // specific language governing permissions and limitations
 // under the License.
 
-package trace
+package handoff
 
 import (
 	"context"
 	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -30,6 +31,7 @@ import (
 	"github.com/apache/skywalking-banyandb/banyand/queue"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
+	"github.com/apache/skywalking-banyandb/pkg/run"
 	"github.com/apache/skywalking-banyandb/pkg/test"
 )
 
@@ -113,9 +115,9 @@ func TestHandoffController_InFlightTracking(t *testing.T) {
 	l := logger.GetLogger("test")
 
 	nodeAddr := testNodeAddrPrimary
-	controller, err := newHandoffController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
+	controller, err := newTestController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
 	require.NoError(t, err)
-	defer controller.close()
+	defer controller.Close()
 	partID := uint64(0x10)
 
 	// Initially not in-flight
@@ -158,16 +160,16 @@ func TestHandoffController_GetNodesWithPendingParts(t *testing.T) {
 
 	nodeAddr1 := testNodeAddrPrimary
 	nodeAddr2 := testNodeAddrSecondary
-	controller, err := newHandoffController(fileSystem, tempDir, nil, []string{nodeAddr1, nodeAddr2}, 0, l, nil)
+	controller, err := newTestController(fileSystem, tempDir, nil, []string{nodeAddr1, nodeAddr2}, 0, l, nil)
 	require.NoError(t, err)
-	defer controller.close()
+	defer controller.Close()
 
 	// Initially no nodes with pending parts
 	nodes := controller.getNodesWithPendingParts()
 	assert.Empty(t, nodes)
 
 	// Enqueue for one node
-	err = controller.enqueueForNode(nodeAddr1, partID, PartTypeCore, sourcePath, "default", 0)
+	err = controller.enqueueForNode(nodeAddr1, partID, partTypeCore, sourcePath, "default", 0)
 	require.NoError(t, err)
 
 	nodes = controller.getNodesWithPendingParts()
@@ -177,7 +179,7 @@ func TestHandoffController_GetNodesWithPendingParts(t *testing.T) {
 	// Enqueue for another node
 	partID2 := uint64(0x13)
 	sourcePath2 := createTestPart(t, fileSystem, sourceRoot, partID2)
-	err = controller.enqueueForNode(nodeAddr2, partID2, PartTypeCore, sourcePath2, "default", 0)
+	err = controller.enqueueForNode(nodeAddr2, partID2, partTypeCore, sourcePath2, "default", 0)
 	require.NoError(t, err)
 
 	nodes = controller.getNodesWithPendingParts()
@@ -186,7 +188,7 @@ func TestHandoffController_GetNodesWithPendingParts(t *testing.T) {
 	assert.Contains(t, nodes, nodeAddr2)
 
 	// Complete one node's parts
-	err = controller.completeSend(nodeAddr1, partID, PartTypeCore)
+	err = controller.completeSend(nodeAddr1, partID, partTypeCore)
 	require.NoError(t, err)
 
 	nodes = controller.getNodesWithPendingParts()
@@ -209,16 +211,16 @@ func TestHandoffController_ReadPartFromHandoff(t *testing.T) {
 	sourcePath := createTestPart(t, fileSystem, sourceRoot, partID)
 
 	nodeAddr := testNodeAddrPrimary
-	controller, err := newHandoffController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
+	controller, err := newTestController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
 	require.NoError(t, err)
-	defer controller.close()
+	defer controller.Close()
 
 	// Enqueue a part
-	err = controller.enqueueForNode(nodeAddr, partID, PartTypeCore, sourcePath, "default", 0)
+	err = controller.enqueueForNode(nodeAddr, partID, partTypeCore, sourcePath, "default", 0)
 	require.NoError(t, err)
 
 	// Read the part back
-	streamingPart, release, err := controller.readPartFromHandoff(nodeAddr, partID, PartTypeCore)
+	streamingPart, release, err := controller.readPartFromHandoff(nodeAddr, partID, partTypeCore)
 	require.NoError(t, err)
 	require.NotNil(t, streamingPart)
 	release()
@@ -226,7 +228,7 @@ func TestHandoffController_ReadPartFromHandoff(t *testing.T) {
 	// Verify basic fields
 	assert.Equal(t, partID, streamingPart.ID)
 	assert.Equal(t, "default", streamingPart.Group)
-	assert.Equal(t, PartTypeCore, streamingPart.PartType)
+	assert.Equal(t, partTypeCore, streamingPart.PartType)
 	assert.NotEmpty(t, streamingPart.Files)
 }
 
@@ -246,16 +248,16 @@ func TestHandoffController_ReplayBatchForNode(t *testing.T) {
 	sourceRoot := filepath.Join(tempDir, "source")
 	fileSystem.MkdirIfNotExist(sourceRoot, storage.DirPerm)
 
-	controller, err := newHandoffController(fileSystem, tempDir, mockClient, []string{nodeAddr}, 0, l, nil)
+	controller, err := newTestController(fileSystem, tempDir, mockClient, []string{nodeAddr}, 0, l, nil)
 	require.NoError(t, err)
-	defer controller.close()
+	defer controller.Close()
 
 	// Enqueue multiple parts
 	numParts := 3
 	for i := 0; i < numParts; i++ {
 		partID := uint64(0x20 + i)
 		sourcePath := createTestPart(t, fileSystem, sourceRoot, partID)
-		err = controller.enqueueForNode(nodeAddr, partID, PartTypeCore, sourcePath, "default", 0)
+		err = controller.enqueueForNode(nodeAddr, partID, partTypeCore, sourcePath, "default", 0)
 		require.NoError(t, err)
 	}
 
@@ -292,16 +294,16 @@ func TestHandoffController_ReplayBatchForNode_WithBatchLimit(t *testing.T) {
 	sourceRoot := filepath.Join(tempDir, "source")
 	fileSystem.MkdirIfNotExist(sourceRoot, storage.DirPerm)
 
-	controller, err := newHandoffController(fileSystem, tempDir, mockClient, []string{nodeAddr}, 0, l, nil)
+	controller, err := newTestController(fileSystem, tempDir, mockClient, []string{nodeAddr}, 0, l, nil)
 	require.NoError(t, err)
-	defer controller.close()
+	defer controller.Close()
 
 	// Enqueue 5 parts
 	numParts := 5
 	for i := 0; i < numParts; i++ {
 		partID := uint64(0x30 + i)
 		sourcePath := createTestPart(t, fileSystem, sourceRoot, partID)
-		err = controller.enqueueForNode(nodeAddr, partID, PartTypeCore, sourcePath, "default", 0)
+		err = controller.enqueueForNode(nodeAddr, partID, partTypeCore, sourcePath, "default", 0)
 		require.NoError(t, err)
 	}
 
@@ -344,9 +346,9 @@ func TestHandoffController_ReplayBatchForNode_SkipsInFlight(t *testing.T) {
 	sourceRoot := filepath.Join(tempDir, "source")
 	fileSystem.MkdirIfNotExist(sourceRoot, storage.DirPerm)
 
-	controller, err := newHandoffController(fileSystem, tempDir, mockClient, []string{nodeAddr}, 0, l, nil)
+	controller, err := newTestController(fileSystem, tempDir, mockClient, []string{nodeAddr}, 0, l, nil)
 	require.NoError(t, err)
-	defer controller.close()
+	defer controller.Close()
 
 	// Enqueue parts
 	partID1 := uint64(0x40)
@@ -354,9 +356,9 @@ func TestHandoffController_ReplayBatchForNode_SkipsInFlight(t *testing.T) {
 	sourcePath1 := createTestPart(t, fileSystem, sourceRoot, partID1)
 	sourcePath2 := createTestPart(t, fileSystem, sourceRoot, partID2)
 
-	err = controller.enqueueForNode(nodeAddr, partID1, PartTypeCore, sourcePath1, "default", 0)
+	err = controller.enqueueForNode(nodeAddr, partID1, partTypeCore, sourcePath1, "default", 0)
 	require.NoError(t, err)
-	err = controller.enqueueForNode(nodeAddr, partID2, PartTypeCore, sourcePath2, "default", 0)
+	err = controller.enqueueForNode(nodeAddr, partID2, partTypeCore, sourcePath2, "default", 0)
 	require.NoError(t, err)
 
 	// Mark first part as in-flight
@@ -386,16 +388,16 @@ func TestHandoffController_SendPartToNode(t *testing.T) {
 	// Create mock client
 	mockClient := newSimpleMockClient([]string{nodeAddr})
 
-	controller, err := newHandoffController(fileSystem, tempDir, mockClient, []string{nodeAddr}, 0, l, nil)
+	controller, err := newTestController(fileSystem, tempDir, mockClient, []string{nodeAddr}, 0, l, nil)
 	require.NoError(t, err)
-	defer controller.close()
+	defer controller.Close()
 
 	// Create a streaming part
 	streamingPart := &queue.StreamingPartData{
 		ID:       0x50,
 		Group:    "default",
 		ShardID:  0,
-		PartType: PartTypeCore,
+		PartType: partTypeCore,
 		Files:    []queue.FileInfo{},
 	}
 
@@ -404,3 +406,34 @@ func TestHandoffController_SendPartToNode(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1, mockClient.getSendCount())
 }
+
+// TestHandoffController_ReplayThrottling tests that the replay doesn't exceed the replay rate.
+func TestHandoffController_ReplayThrottling(t *testing.T) {
+	tempDir, defFn := test.Space(require.New(t))
+	defer defFn()
+
+	fileSystem := fs.NewLocalFileSystem()
+	l := logger.GetLogger("test")
+
+	nodeAddr := testNodeAddrPrimary
+	mockClient := newSimpleMockClient([]string{nodeAddr})
+
+	// 1MB per second
+	cfg := Config{ReplayRate: run.Bytes(megabyte)}
+	controller, err := NewController(fileSystem, tempDir, mockClient, testCodec{}, []string{nodeAddr}, cfg, l, nil, nil)
+	require.NoError(t, err)
+	defer controller.Close()
+
+	// Two parts of 512KB each should take about half a second to replay
+	for i := 0; i < 2; i++ {
+		partID := uint64(0x60 + i)
+		sourcePath := createSizedTestPart(t, tempDir, partID, megabyte/2)
+		require.NoError(t, controller.enqueueForNode(nodeAddr, partID, partTypeCore, sourcePath, "default", 0))
+	}
+
+	start := time.Now()
+	count, err := controller.replayBatchForNode(nodeAddr, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+}
@@ -15,7 +15,7 @@
 // specific language governing permissions and limitations
 // under the License.
 
-package trace
+package handoff
 
 import (
 	"encoding/json"
@@ -35,8 +35,8 @@ const (
 	nodeInfoFilename    = ".node_info"
 )
 
-// handoffMetadata contains metadata for a handoff queue entry.
-type handoffMetadata struct {
+// queueMetadata contains metadata for a handoff queue entry.
+type queueMetadata struct {
 	Group            string `json:"group"`
 	PartType         string `json:"part_type"`
 	EnqueueTimestamp int64  `json:"enqueue_timestamp"`
@@ -44,9 +44,9 @@ type handoffMetadata struct {
 	ShardID          uint32 `json:"shard_id"`
 }
 
-// handoffNodeQueue manages the handoff queue for a single data node.
+// nodeQueue manages the handoff queue for a single data node.
 // It uses a per-node directory with hard-linked part directories.
-type handoffNodeQueue struct {
+type nodeQueue struct {
 	fileSystem fs.FileSystem
 	l          *logger.Logger
 	nodeAddr   string
@@ -54,8 +54,8 @@ type handoffNodeQueue struct {
 	mu         sync.RWMutex
 }
 
-// newHandoffNodeQueue creates a new handoff queue for a specific node.
-func newHandoffNodeQueue(nodeAddr, root string, fileSystem fs.FileSystem, l *logger.Logger) (*handoffNodeQueue, error) {
+// newNodeQueue creates a new handoff queue for a specific node.
+func newNodeQueue(nodeAddr, root string, fileSystem fs.FileSystem, l *logger.Logger) (*nodeQueue, error) {
 	if fileSystem == nil {
 		return nil, fmt.Errorf("fileSystem is nil")
 	}
@@ -69,7 +69,7 @@ func newHandoffNodeQueue(nodeAddr, root string, fileSystem fs.FileSystem, l *log
 		return nil, fmt.Errorf("queue root path is empty")
 	}
 
-	hnq := &handoffNodeQueue{
+	hnq := &nodeQueue{
 		nodeAddr:   nodeAddr,
 		root:       root,
 		fileSystem: fileSystem,
@@ -88,7 +88,7 @@ func newHandoffNodeQueue(nodeAddr, root string, fileSystem fs.FileSystem, l *log
 }
 
 // writeNodeInfo writes the original node address to a metadata file.
-func (hnq *handoffNodeQueue) writeNodeInfo() error {
+func (hnq *nodeQueue) writeNodeInfo() error {
 	nodeInfoPath := filepath.Join(hnq.root, nodeInfoFilename)
 
 	// Check if already exists
@@ -117,7 +117,7 @@ func readNodeInfo(fileSystem fs.FileSystem, root string) (string, error) {
 
 // enqueue adds a part to the handoff queue by creating hard links and writing metadata.
 // Uses nested structure: <nodeRoot>/<partId>/<partType>/.
-func (hnq *handoffNodeQueue) enqueue(partID uint64, partType string, sourcePath string, meta *handoffMetadata) error {
+func (hnq *nodeQueue) enqueue(partID uint64, partType string, sourcePath string, meta *queueMetadata) error {
 	hnq.mu.Lock()
 	defer hnq.mu.Unlock()
 
@@ -179,7 +179,7 @@ type partTypePair struct {
 }
 
 // listPending returns a sorted list of all pending part IDs with their types.
-func (hnq *handoffNodeQueue) listPending() ([]partTypePair, error) {
+func (hnq *nodeQueue) listPending() ([]partTypePair, error) {
 	hnq.mu.RLock()
 	defer hnq.mu.RUnlock()
 
@@ -228,7 +228,7 @@ func (hnq *handoffNodeQueue) listPending() ([]partTypePair, error) {
 }
 
 // getMetadata reads the handoff metadata for a specific part type.
-func (hnq *handoffNodeQueue) getMetadata(partID uint64, partType string) (*handoffMetadata, error) {
+func (hnq *nodeQueue) getMetadata(partID uint64, partType string) (*queueMetadata, error) {
 	hnq.mu.RLock()
 	defer hnq.mu.RUnlock()
 
@@ -238,7 +238,7 @@ func (hnq *handoffNodeQueue) getMetadata(partID uint64, partType string) (*hando
 		return nil, fmt.Errorf("failed to read metadata: %w", err)
 	}
 
-	var meta handoffMetadata
+	var meta queueMetadata
 	if err := json.Unmarshal(data, &meta); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
 	}
@@ -247,17 +247,17 @@ func (hnq *handoffNodeQueue) getMetadata(partID uint64, partType string) (*hando
 }
 
 // getPartIDDir returns the directory path for a partID (contains all part types).
-func (hnq *handoffNodeQueue) getPartIDDir(partID uint64) string {
+func (hnq *nodeQueue) getPartIDDir(partID uint64) string {
 	return filepath.Join(hnq.root, partName(partID))
 }
 
 // getPartTypePath returns the full path to a specific part type directory.
-func (hnq *handoffNodeQueue) getPartTypePath(partID uint64, partType string) string {
+func (hnq *nodeQueue) getPartTypePath(partID uint64, partType string) string {
 	return filepath.Join(hnq.getPartIDDir(partID), partType)
 }
 
 // complete removes a specific part type from the handoff queue after successful delivery.
-func (hnq *handoffNodeQueue) complete(partID uint64, partType string) error {
+func (hnq *nodeQueue) complete(partID uint64, partType string) error {
 	hnq.mu.Lock()
 	defer hnq.mu.Unlock()
 
@@ -290,7 +290,7 @@ func (hnq *handoffNodeQueue) complete(partID uint64, partType string) error {
 }
 
 // completeAll removes all part types for a given partID.
-func (hnq *handoffNodeQueue) completeAll(partID uint64) error {
+func (hnq *nodeQueue) completeAll(partID uint64) error {
 	hnq.mu.Lock()
 	defer hnq.mu.Unlock()
 
@@ -308,7 +308,7 @@ func (hnq *handoffNodeQueue) completeAll(partID uint64) error {
 }
 
 // size returns the total size of all pending parts in bytes.
-func (hnq *handoffNodeQueue) size() (uint64, error) {
+func (hnq *nodeQueue) size() (uint64, error) {
 	hnq.mu.RLock()
 	defer hnq.mu.RUnlock()
 
@@ -363,6 +363,11 @@ func copyPartDirectory(fileSystem fs.FileSystem, srcDir, dstDir string) error {
 	return nil
 }
 
+// partName returns the hex directory name of a part ID.
+func partName(partID uint64) string {
+	return fmt.Sprintf("%016x", partID)
+}
+
 // parsePartID parses a part ID from a hex string directory name.
 func parsePartID(name string) (uint64, error) {
 	partID, err := strconv.ParseUint(name, 16, 64)
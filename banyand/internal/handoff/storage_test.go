@@ -15,7 +15,7 @@
 // specific language governing permissions and limitations
 // under the License.
 
-package trace
+package handoff
 
 import (
 	"encoding/json"
@@ -36,7 +36,43 @@ import (
 	"github.com/apache/skywalking-banyandb/pkg/test"
 )
 
-const megabyte = 1024 * 1024
+const (
+	megabyte     = 1024 * 1024
+	partTypeCore = "core"
+)
+
+// testCodec sends the files of the test parts under their own names.
+type testCodec struct{}
+
+func (testCodec) Topic() string {
+	return "test-part-sync"
+}
+
+func (testCodec) PartSize(fileSystem fs.FileSystem, partPath, _ string) uint64 {
+	data, err := fileSystem.Read(filepath.Join(partPath, "metadata.json"))
+	if err != nil {
+		return 0
+	}
+	var pm struct {
+		CompressedSizeBytes uint64 `json:"compressedSizeBytes"`
+	}
+	if err := json.Unmarshal(data, &pm); err != nil {
+		return 0
+	}
+	return pm.CompressedSizeBytes
+}
+
+func (testCodec) StreamingFileName(_, fileName string) (string, bool) {
+	return fileName, fileName != "metadata.json"
+}
+
+func (testCodec) FillPartMetadata(fs.FileSystem, string, *queue.StreamingPartData) {}
+
+func newTestController(fileSystem fs.FileSystem, root string, client QueueClient, dataNodes []string, maxTotalSizeBytes uint64,
+	l *logger.Logger, resolveShardAssignments func(group string, shardID uint32) ([]string, error),
+) (*Controller, error) {
+	return NewController(fileSystem, root, client, testCodec{}, dataNodes, Config{MaxTotalSizeBytes: maxTotalSizeBytes}, l, nil, resolveShardAssignments)
+}
 
 type fakeQueueClient struct {
 	healthy []string
@@ -96,22 +132,22 @@ func TestHandoffNodeQueue_EnqueueCore(t *testing.T) {
 
 	// Create handoff node queue
 	queueRoot := filepath.Join(tempDir, "handoff", "node1")
-	nodeQueue, err := newHandoffNodeQueue("node1.example.com:17912", queueRoot, fileSystem, l)
+	nodeQueue, err := newNodeQueue("node1.example.com:17912", queueRoot, fileSystem, l)
 	require.NoError(t, err)
 
 	// Test enqueue core part
-	meta := &handoffMetadata{
+	meta := &queueMetadata{
 		EnqueueTimestamp: time.Now().UnixNano(),
 		Group:            "default",
 		ShardID:          0,
-		PartType:         PartTypeCore,
+		PartType:         partTypeCore,
 	}
 
-	err = nodeQueue.enqueue(partID, PartTypeCore, sourcePath, meta)
+	err = nodeQueue.enqueue(partID, partTypeCore, sourcePath, meta)
 	require.NoError(t, err)
 
 	// Verify nested structure: <partID>/core/
-	dstPath := nodeQueue.getPartTypePath(partID, PartTypeCore)
+	dstPath := nodeQueue.getPartTypePath(partID, partTypeCore)
 	entries := fileSystem.ReadDir(dstPath)
 	assert.NotEmpty(t, entries)
 
@@ -133,17 +169,17 @@ func TestHandoffNodeQueue_EnqueueMultiplePartTypes(t *testing.T) {
 
 	// Create handoff node queue
 	queueRoot := filepath.Join(tempDir, "handoff", "node1")
-	nodeQueue, err := newHandoffNodeQueue("node1.example.com:17912", queueRoot, fileSystem, l)
+	nodeQueue, err := newNodeQueue("node1.example.com:17912", queueRoot, fileSystem, l)
 	require.NoError(t, err)
 
 	// Enqueue core part
-	meta := &handoffMetadata{
+	meta := &queueMetadata{
 		EnqueueTimestamp: time.Now().UnixNano(),
 		Group:            "default",
 		ShardID:          0,
-		PartType:         PartTypeCore,
+		PartType:         partTypeCore,
 	}
-	err = nodeQueue.enqueue(partID, PartTypeCore, sourcePath, meta)
+	err = nodeQueue.enqueue(partID, partTypeCore, sourcePath, meta)
 	require.NoError(t, err)
 
 	// Enqueue sidx part (simulated with same source)
@@ -171,7 +207,7 @@ func TestHandoffNodeQueue_EnqueueMultiplePartTypes(t *testing.T) {
 	for _, pair := range pending {
 		partTypes[pair.PartType] = true
 	}
-	assert.True(t, partTypes[PartTypeCore])
+	assert.True(t, partTypes[partTypeCore])
 	assert.True(t, partTypes["sidx_trace_id"])
 	assert.True(t, partTypes["sidx_service"])
 }
@@ -187,17 +223,17 @@ func TestHandoffNodeQueue_Complete(t *testing.T) {
 
 	// Create handoff node queue
 	queueRoot := filepath.Join(tempDir, "handoff", "node1")
-	nodeQueue, err := newHandoffNodeQueue("node1.example.com:17912", queueRoot, fileSystem, l)
+	nodeQueue, err := newNodeQueue("node1.example.com:17912", queueRoot, fileSystem, l)
 	require.NoError(t, err)
 
 	// Enqueue core and sidx parts
-	meta := &handoffMetadata{
+	meta := &queueMetadata{
 		EnqueueTimestamp: time.Now().UnixNano(),
 		Group:            "default",
 		ShardID:          0,
-		PartType:         PartTypeCore,
+		PartType:         partTypeCore,
 	}
-	err = nodeQueue.enqueue(partID, PartTypeCore, sourcePath, meta)
+	err = nodeQueue.enqueue(partID, partTypeCore, sourcePath, meta)
 	require.NoError(t, err)
 
 	meta.PartType = "sidx_trace_id"
@@ -210,7 +246,7 @@ func TestHandoffNodeQueue_Complete(t *testing.T) {
 	assert.Len(t, pending, 2)
 
 	// Complete core part only
-	err = nodeQueue.complete(partID, PartTypeCore)
+	err = nodeQueue.complete(partID, partTypeCore)
 	require.NoError(t, err)
 
 	// Verify only sidx remains
@@ -245,17 +281,17 @@ func TestHandoffNodeQueue_CompleteAll(t *testing.T) {
 
 	// Create handoff node queue
 	queueRoot := filepath.Join(tempDir, "handoff", "node1")
-	nodeQueue, err := newHandoffNodeQueue("node1.example.com:17912", queueRoot, fileSystem, l)
+	nodeQueue, err := newNodeQueue("node1.example.com:17912", queueRoot, fileSystem, l)
 	require.NoError(t, err)
 
 	// Enqueue multiple part types
-	meta := &handoffMetadata{
+	meta := &queueMetadata{
 		EnqueueTimestamp: time.Now().UnixNano(),
 		Group:            "default",
 		ShardID:          0,
-		PartType:         PartTypeCore,
+		PartType:         partTypeCore,
 	}
-	err = nodeQueue.enqueue(partID, PartTypeCore, sourcePath, meta)
+	err = nodeQueue.enqueue(partID, partTypeCore, sourcePath, meta)
 	require.NoError(t, err)
 	err = nodeQueue.enqueue(partID, "sidx_trace_id", sourcePath, meta)
 	require.NoError(t, err)
@@ -288,11 +324,11 @@ func TestHandoffController_EnqueueForNodes(t *testing.T) {
 
 	// Create handoff controller
 	offlineNodes := []string{"node1.example.com:17912", "node2.example.com:17912"}
-	controller, err := newHandoffController(fileSystem, tempDir, nil, offlineNodes, 0, l, nil)
+	controller, err := newTestController(fileSystem, tempDir, nil, offlineNodes, 0, l, nil)
 	require.NoError(t, err)
 
 	// Enqueue for multiple nodes
-	err = controller.enqueueForNodes(offlineNodes, partID, PartTypeCore, sourcePath, "default", 0)
+	err = controller.enqueueForNodes(offlineNodes, partID, partTypeCore, sourcePath, "default", 0)
 	require.NoError(t, err)
 
 	// Verify both nodes have the part
@@ -301,7 +337,7 @@ func TestHandoffController_EnqueueForNodes(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, pending, 1)
 		assert.Equal(t, partID, pending[0].PartID)
-		assert.Equal(t, PartTypeCore, pending[0].PartType)
+		assert.Equal(t, partTypeCore, pending[0].PartType)
 	}
 }
 
@@ -316,15 +352,15 @@ func TestHandoffController_GetPartPath(t *testing.T) {
 
 	// Create handoff controller
 	nodeAddr := "node1.example.com:17912"
-	controller, err := newHandoffController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
+	controller, err := newTestController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
 	require.NoError(t, err)
 
 	// Enqueue for node
-	err = controller.enqueueForNode(nodeAddr, partID, PartTypeCore, sourcePath, "default", 0)
+	err = controller.enqueueForNode(nodeAddr, partID, partTypeCore, sourcePath, "default", 0)
 	require.NoError(t, err)
 
 	// Get part path
-	partPath := controller.getPartPath(nodeAddr, partID, PartTypeCore)
+	partPath := controller.getPartPath(nodeAddr, partID, partTypeCore)
 	assert.NotEmpty(t, partPath)
 
 	// Verify path exists
@@ -333,7 +369,7 @@ func TestHandoffController_GetPartPath(t *testing.T) {
 
 	// Verify it's the nested structure
 	assert.Contains(t, partPath, partName(partID))
-	assert.Contains(t, partPath, PartTypeCore)
+	assert.Contains(t, partPath, partTypeCore)
 }
 
 func TestHandoffController_LoadExistingQueues(t *testing.T) {
@@ -348,18 +384,18 @@ func TestHandoffController_LoadExistingQueues(t *testing.T) {
 	nodeAddr := "node1.example.com:17912"
 
 	// Create first controller and enqueue part
-	controller1, err := newHandoffController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
+	controller1, err := newTestController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
 	require.NoError(t, err)
 
-	err = controller1.enqueueForNode(nodeAddr, partID, PartTypeCore, sourcePath, "default", 0)
+	err = controller1.enqueueForNode(nodeAddr, partID, partTypeCore, sourcePath, "default", 0)
 	require.NoError(t, err)
 
 	// Close first controller
-	err = controller1.close()
+	err = controller1.Close()
 	require.NoError(t, err)
 
 	// Create second controller (should load existing queues)
-	controller2, err := newHandoffController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
+	controller2, err := newTestController(fileSystem, tempDir, nil, []string{nodeAddr}, 0, l, nil)
 	require.NoError(t, err)
 
 	// Verify part is still pending
@@ -367,7 +403,7 @@ func TestHandoffController_LoadExistingQueues(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, pending, 1)
 	assert.Equal(t, partID, pending[0].PartID)
-	assert.Equal(t, PartTypeCore, pending[0].PartType)
+	assert.Equal(t, partTypeCore, pending[0].PartType)
 }
 
 func TestSanitizeNodeAddr(t *testing.T) {
@@ -446,12 +482,12 @@ func TestHandoffController_SizeEnforcement(t *testing.T) {
 	lfs := fs.NewLocalFileSystem()
 	l := logger.GetLogger("test")
 	dataNodes := []string{"node1:17912", "node2:17912"}
-	hc, err := newHandoffController(lfs, tempDir, nil, dataNodes, 10*megabyte, l, nil) // 10MB limit
+	hc, err := newTestController(lfs, tempDir, nil, dataNodes, 10*megabyte, l, nil) // 10MB limit
 	tester.NoError(err)
-	defer hc.close()
+	defer hc.Close()
 
 	// First enqueue should succeed (5MB < 10MB)
-	err = hc.enqueueForNode("node1:17912", partID, PartTypeCore, partPath, "group1", 1)
+	err = hc.enqueueForNode("node1:17912", partID, partTypeCore, partPath, "group1", 1)
 	tester.NoError(err)
 
 	// Check total size
@@ -459,7 +495,7 @@ func TestHandoffController_SizeEnforcement(t *testing.T) {
 	tester.Equal(uint64(5*1024*1024), totalSize)
 
 	// Second enqueue should succeed (10MB = 10MB)
-	err = hc.enqueueForNode("node1:17912", partID+1, PartTypeCore, partPath, "group1", 1)
+	err = hc.enqueueForNode("node1:17912", partID+1, partTypeCore, partPath, "group1", 1)
 	tester.NoError(err)
 
 	// Check total size
@@ -467,7 +503,7 @@ func TestHandoffController_SizeEnforcement(t *testing.T) {
 	tester.Equal(uint64(10*1024*1024), totalSize)
 
 	// Third enqueue should fail (15MB > 10MB)
-	err = hc.enqueueForNode("node1:17912", partID+2, PartTypeCore, partPath, "group1", 1)
+	err = hc.enqueueForNode("node1:17912", partID+2, partTypeCore, partPath, "group1", 1)
 	tester.Error(err)
 	tester.Contains(err.Error(), "handoff queue full")
 
@@ -505,30 +541,30 @@ func TestHandoffController_SizeTracking(t *testing.T) {
 	lfs := fs.NewLocalFileSystem()
 	l := logger.GetLogger("test")
 	dataNodes := []string{"node1:17912"}
-	hc, err := newHandoffController(lfs, tempDir, nil, dataNodes, 100*megabyte, l, nil) // 100MB limit
+	hc, err := newTestController(lfs, tempDir, nil, dataNodes, 100*megabyte, l, nil) // 100MB limit
 	tester.NoError(err)
-	defer hc.close()
+	defer hc.Close()
 
 	// Initial size should be 0
 	tester.Equal(uint64(0), hc.getTotalSize())
 
 	// Enqueue first part
-	err = hc.enqueueForNode("node1:17912", partID, PartTypeCore, partPath, "group1", 1)
+	err = hc.enqueueForNode("node1:17912", partID, partTypeCore, partPath, "group1", 1)
 	tester.NoError(err)
 	tester.Equal(uint64(3*1024*1024), hc.getTotalSize())
 
 	// Enqueue second part
-	err = hc.enqueueForNode("node1:17912", partID+1, PartTypeCore, partPath, "group1", 1)
+	err = hc.enqueueForNode("node1:17912", partID+1, partTypeCore, partPath, "group1", 1)
 	tester.NoError(err)
 	tester.Equal(uint64(6*1024*1024), hc.getTotalSize())
 
 	// Complete first part
-	err = hc.completeSend("node1:17912", partID, PartTypeCore)
+	err = hc.completeSend("node1:17912", partID, partTypeCore)
 	tester.NoError(err)
 	tester.Equal(uint64(3*1024*1024), hc.getTotalSize())
 
 	// Complete second part
-	err = hc.completeSend("node1:17912", partID+1, PartTypeCore)
+	err = hc.completeSend("node1:17912", partID+1, partTypeCore)
 	tester.NoError(err)
 	tester.Equal(uint64(0), hc.getTotalSize())
 }
@@ -560,11 +596,11 @@ func TestHandoffController_NodeQueueHelpers(t *testing.T) {
 	lfs := fs.NewLocalFileSystem()
 	l := logger.GetLogger("test")
 
-	hc, err := newHandoffController(lfs, tempDir, nil, []string{node}, 10*megabyte, l, nil)
+	hc, err := newTestController(lfs, tempDir, nil, []string{node}, 10*megabyte, l, nil)
 	tester.NoError(err)
-	defer hc.close()
+	defer hc.Close()
 
-	err = hc.enqueueForNode(node, partID, PartTypeCore, partPath, "group1", 1)
+	err = hc.enqueueForNode(node, partID, partTypeCore, partPath, "group1", 1)
 	tester.NoError(err)
 
 	nodes := hc.getAllNodeQueues()
@@ -600,24 +636,25 @@ func TestHandoffController_FiltersNonOwningOfflineNodes(t *testing.T) {
 	}
 
 	queueClient := &fakeQueueClient{healthy: dataNodes}
-	hc, err := newHandoffController(lfs, tempDir, queueClient, dataNodes, 0, l, resolver)
+	hc, err := newTestController(lfs, tempDir, queueClient, dataNodes, 0, l, resolver)
 	tester.NoError(err)
-	defer hc.close()
+	defer hc.Close()
 
-	offline := hc.calculateOfflineNodes([]string{"node2:17912"}, groupName, common.ShardID(shardID))
+	offline := hc.CalculateOfflineNodes([]string{"node2:17912"}, groupName, common.ShardID(shardID))
 	tester.Len(offline, 0, "expected no offline nodes when shard owner is online")
 
 	partDir := filepath.Join(tempDir, "part-core")
 	tester.NoError(os.MkdirAll(partDir, 0o755))
 
-	coreParts := []partInfo{{
-		partID:  uint64(1),
-		path:    partDir,
-		group:   groupName,
-		shardID: common.ShardID(shardID),
+	parts := []PartInfo{{
+		PartID:   uint64(1),
+		Path:     partDir,
+		Group:    groupName,
+		PartType: partTypeCore,
+		ShardID:  common.ShardID(shardID),
 	}}
 
-	err = hc.enqueueForOfflineNodes([]string{"node1:17912"}, coreParts, nil)
+	err = hc.EnqueueForOfflineNodes([]string{"node1:17912"}, parts)
 	tester.NoError(err)
 
 	nodeDir := filepath.Join(hc.root, sanitizeNodeAddr("node1:17912"))
@@ -656,12 +693,12 @@ func TestHandoffController_SizeRecovery(t *testing.T) {
 	dataNodes := []string{"node1:17912", "node2:17912"}
 
 	// First controller: enqueue some parts
-	hc1, err := newHandoffController(lfs, tempDir, nil, dataNodes, 100*megabyte, l, nil)
+	hc1, err := newTestController(lfs, tempDir, nil, dataNodes, 100*megabyte, l, nil)
 	tester.NoError(err)
 
-	err = hc1.enqueueForNode("node1:17912", partID, PartTypeCore, partPath, "group1", 1)
+	err = hc1.enqueueForNode("node1:17912", partID, partTypeCore, partPath, "group1", 1)
 	tester.NoError(err)
-	err = hc1.enqueueForNode("node2:17912", partID+1, PartTypeCore, partPath, "group1", 1)
+	err = hc1.enqueueForNode("node2:17912", partID+1, partTypeCore, partPath, "group1", 1)
 	tester.NoError(err)
 
 	// Total size should be 14MB (7MB * 2 parts)
@@ -669,13 +706,13 @@ func TestHandoffController_SizeRecovery(t *testing.T) {
 	tester.Equal(expectedSize, hc1.getTotalSize())
 
 	// Close first controller
-	err = hc1.close()
+	err = hc1.Close()
 	tester.NoError(err)
 
 	// Second controller: should recover the same size
-	hc2, err := newHandoffController(lfs, tempDir, nil, dataNodes, 100*megabyte, l, nil)
+	hc2, err := newTestController(lfs, tempDir, nil, dataNodes, 100*megabyte, l, nil)
 	tester.NoError(err)
-	defer hc2.close()
+	defer hc2.Close()
 
 	// Recovered size should match
 	recoveredSize := hc2.getTotalSize()
@@ -690,3 +727,75 @@ func TestHandoffController_SizeRecovery(t *testing.T) {
 	tester.NoError(err)
 	tester.Len(node2Pending, 1)
 }
+
+func createSizedTestPart(t *testing.T, root string, partID uint64, size uint64) string {
+	partPath := filepath.Join(root, "source", partName(partID))
+	require.NoError(t, os.MkdirAll(partPath, 0o755))
+	metadataBytes, err := json.Marshal(map[string]interface{}{
+		"compressedSizeBytes": size,
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(partPath, "metadata.json"), metadataBytes, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(partPath, "data.bin"), []byte("test data"), 0o600))
+	return partPath
+}
+
+// TestHandoffController_NodeSizeEnforcement verifies that a full node queue doesn't block the queues of other nodes.
+func TestHandoffController_NodeSizeEnforcement(t *testing.T) {
+	tester := require.New(t)
+	tempDir, fileSystem, l := setupHandoffTest(t)
+	partPath := createSizedTestPart(t, tempDir, 100, 4*megabyte)
+
+	cfg := Config{MaxTotalSizeBytes: 100 * megabyte, MaxNodeSizeBytes: 8 * megabyte}
+	hc, err := NewController(fileSystem, tempDir, nil, testCodec{}, []string{"node1:17912", "node2:17912"}, cfg, l, nil, nil)
+	tester.NoError(err)
+	defer hc.Close()
+
+	tester.NoError(hc.enqueueForNode("node1:17912", 100, partTypeCore, partPath, "group1", 1))
+	tester.NoError(hc.enqueueForNode("node1:17912", 101, partTypeCore, partPath, "group1", 1))
+	err = hc.enqueueForNode("node1:17912", 102, partTypeCore, partPath, "group1", 1)
+	tester.Error(err)
+	tester.Contains(err.Error(), "handoff queue of node node1:17912 full")
+
+	// The other node still has room
+	tester.NoError(hc.enqueueForNode("node2:17912", 102, partTypeCore, partPath, "group1", 1))
+
+	// Enqueuing a queued part again doesn't count it twice
+	tester.NoError(hc.enqueueForNode("node2:17912", 102, partTypeCore, partPath, "group1", 1))
+
+	partCount, totalSize := hc.Stats()
+	tester.Equal(int64(3), partCount)
+	tester.Equal(int64(12*megabyte), totalSize)
+}
+
+// TestHandoffController_Expiry verifies that the parts queued longer than the expiry are dropped.
+func TestHandoffController_Expiry(t *testing.T) {
+	tester := require.New(t)
+	tempDir, fileSystem, l := setupHandoffTest(t)
+	partPath := createSizedTestPart(t, tempDir, 200, megabyte)
+	const node = "node1:17912"
+
+	hc, err := NewController(fileSystem, tempDir, nil, testCodec{}, []string{node}, Config{Expiry: time.Hour}, l, nil, nil)
+	tester.NoError(err)
+	defer hc.Close()
+
+	tester.NoError(hc.enqueueForNode(node, 200, partTypeCore, partPath, "group1", 1))
+	tester.NoError(hc.enqueueForNode(node, 201, partTypeCore, partPath, "group1", 1))
+
+	// Nothing expires within the expiry
+	tester.Equal(0, hc.expireParts(time.Now().Add(30*time.Minute)))
+
+	// An in-flight part is kept until its send finishes
+	hc.markInFlight(node, 201, true)
+	tester.Equal(1, hc.expireParts(time.Now().Add(2*time.Hour)))
+	pending, err := hc.listPendingForNode(node)
+	tester.NoError(err)
+	tester.Len(pending, 1)
+	tester.Equal(uint64(201), pending[0].PartID)
+
+	hc.markInFlight(node, 201, false)
+	tester.Equal(1, hc.expireParts(time.Now().Add(2*time.Hour)))
+	partCount, totalSize := hc.Stats()
+	tester.Zero(partCount)
+	tester.Zero(totalSize)
+}
@@ -20,6 +20,7 @@ package sidx
 import (
 	"fmt"
 
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
 )
@@ -104,6 +105,7 @@ func (s *sidx) mergeParts(fileSystem fs.FileSystem, closeCh <-chan struct{}, par
 		return nil, err
 	}
 	pm.mustWriteMetadata(fileSystem, dstPath)
+	storage.MustWritePartChecksums(fileSystem, dstPath)
 	fileSystem.SyncPath(dstPath)
 	p := mustOpenPart(partID, dstPath, fileSystem)
 
@@ -797,6 +797,7 @@ func (mp *memPart) mustFlush(fileSystem fs.FileSystem, partPath string) {
 	if mp.partMetadata != nil {
 		mp.partMetadata.mustWriteMetadata(fileSystem, partPath)
 	}
+	storage.MustWritePartChecksums(fileSystem, partPath)
 
 	fileSystem.SyncPath(partPath)
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/bytes"
 	"github.com/apache/skywalking-banyandb/pkg/compress/zstd"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
@@ -99,8 +100,8 @@ func (pi *partIter) loadNextBlockMetadata() bool {
 		var err error
 		pi.bms, err = pi.readPrimaryBlock(pi.bms[:0], pbm)
 		if err != nil {
-			pi.err = fmt.Errorf("cannot read primary block for part %q at key range [%d, %d]: %w",
-				pi.p.String(), pbm.minKey, pbm.maxKey, err)
+			pi.err = fmt.Errorf("cannot read primary block for part %q at key range [%d, %d]: %w: %w",
+				pi.p.String(), pbm.minKey, pbm.maxKey, storage.ErrPartCorrupted, err)
 			return false
 		}
 		return true
@@ -100,6 +100,28 @@ func (s *sidx) StreamingParts(partIDsToSync map[uint64]struct{}, group string, s
 	return streamingParts, releaseFuncs
 }
 
+// StreamingMemPart returns the streaming part of the memory part, which indexes the core part with the id.
+// The memory part must outlive the returned release function.
+func StreamingMemPart(mp *MemPart, id uint64, group string, shardID uint32, name string) (queue.StreamingPartData, func()) {
+	part := openMemPart(mp)
+	files, release := createPartFileReaders(part)
+	return queue.StreamingPartData{
+		ID:                    id,
+		Group:                 group,
+		ShardID:               shardID,
+		Topic:                 data.TopicTracePartSync.String(),
+		Files:                 files,
+		CompressedSizeBytes:   part.partMetadata.CompressedSizeBytes,
+		UncompressedSizeBytes: part.partMetadata.UncompressedSizeBytes,
+		TotalCount:            part.partMetadata.TotalCount,
+		BlocksCount:           part.partMetadata.BlocksCount,
+		MinTimestamp:          part.partMetadata.SegmentID,
+		MinKey:                part.partMetadata.MinKey,
+		MaxKey:                part.partMetadata.MaxKey,
+		PartType:              name,
+	}, release
+}
+
 func createPartFileReaders(part *part) ([]queue.FileInfo, func()) {
 	var files []queue.FileInfo
 	var buffersToRelease []*bytes.Buffer
@@ -0,0 +1,441 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	clusterv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/cluster/v1"
+	"github.com/apache/skywalking-banyandb/pkg/bus"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const (
+	antiEntropySeriesSlots = 16
+	antiEntropyTimeSlices  = 16
+	// AntiEntropyBuckets is the number of buckets a shard in a segment is split into, by series and by time.
+	AntiEntropyBuckets = antiEntropySeriesSlots * antiEntropyTimeSlices
+	// AntiEntropySettleTime is how long the rows are left out of the comparison after they are written,
+	// because the writes in flight may not have reached all the replicas yet.
+	AntiEntropySettleTime = time.Hour
+	// antiEntropyBatchRows caps the rows of the buckets repaired by a request, on either replica.
+	antiEntropyBatchRows = 512 * 1024
+	// antiEntropyDigestAttempts is how many times the digest of a replica is requested.
+	// A replica caches the digests of its parts, so a retry is cheaper than the first request.
+	antiEntropyDigestAttempts = 3
+)
+
+// AntiEntropyResult is the outcome of comparing a shard in a segment with its replicas.
+type AntiEntropyResult struct {
+	// MismatchedBuckets is the number of buckets that differ from the ones of a replica.
+	MismatchedBuckets int
+	// PulledRows is the number of rows pulled from the replicas.
+	PulledRows uint64
+}
+
+// AntiEntropyDigest holds the number of rows and the sum of the row hashes of every bucket of a shard in a segment.
+// The sums don't depend on how the rows are split into parts,
+// so two replicas holding the same rows have the same digests.
+type AntiEntropyDigest struct {
+	Rows [AntiEntropyBuckets]uint64
+	Sums [AntiEntropyBuckets]uint64
+}
+
+// Add adds the hash of a row to its bucket.
+func (d *AntiEntropyDigest) Add(bucket int, hash uint64) {
+	d.Rows[bucket]++
+	d.Sums[bucket] += hash
+}
+
+// Merge adds the rows of another digest.
+func (d *AntiEntropyDigest) Merge(other *AntiEntropyDigest) {
+	for i := range d.Rows {
+		d.Rows[i] += other.Rows[i]
+		d.Sums[i] += other.Sums[i]
+	}
+}
+
+// AntiEntropyBucket returns the bucket of a row in the segment of the time range, or -1 if the row is out of the segment.
+func AntiEntropyBucket(timeRange timestamp.TimeRange, seriesID common.SeriesID, ts int64) int {
+	start, end := timeRange.Start.UnixNano(), timeRange.End.UnixNano()
+	if ts < start || ts >= end {
+		return -1
+	}
+	slice := int(uint64(ts-start) * antiEntropyTimeSlices / uint64(end-start))
+	return slice*antiEntropySeriesSlots + int(uint64(seriesID)%antiEntropySeriesSlots)
+}
+
+// AntiEntropyBucketSet marks the buckets to repair.
+type AntiEntropyBucketSet [AntiEntropyBuckets]bool
+
+// NewAntiEntropyBucketSet returns the set of the buckets, ignoring the ones out of range.
+func NewAntiEntropyBucketSet(buckets []uint32) *AntiEntropyBucketSet {
+	set := &AntiEntropyBucketSet{}
+	for _, b := range buckets {
+		if b < AntiEntropyBuckets {
+			set[b] = true
+		}
+	}
+	return set
+}
+
+// Overlaps reports whether the rows of the series between minTS and maxTS may fall into a bucket of the set.
+func (s *AntiEntropyBucketSet) Overlaps(timeRange timestamp.TimeRange, seriesID common.SeriesID, minTS, maxTS int64) bool {
+	first, last, ok := s.slices(timeRange, seriesID, minTS, maxTS)
+	if !ok {
+		return false
+	}
+	for b := first; b <= last; b += antiEntropySeriesSlots {
+		if s[b] {
+			return true
+		}
+	}
+	return false
+}
+
+// OverlapsTime reports whether the rows of any series between minTS and maxTS may fall into a bucket of the set.
+func (s *AntiEntropyBucketSet) OverlapsTime(timeRange timestamp.TimeRange, minTS, maxTS int64) bool {
+	first, last, ok := s.slices(timeRange, 0, minTS, maxTS)
+	if !ok {
+		return false
+	}
+	for b := first; b < last+antiEntropySeriesSlots; b++ {
+		if s[b] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *AntiEntropyBucketSet) slices(timeRange timestamp.TimeRange, seriesID common.SeriesID, minTS, maxTS int64) (int, int, bool) {
+	minTS = max(minTS, timeRange.Start.UnixNano())
+	maxTS = min(maxTS, timeRange.End.UnixNano()-1)
+	if minTS > maxTS {
+		return 0, 0, false
+	}
+	return AntiEntropyBucket(timeRange, seriesID, minTS), AntiEntropyBucket(timeRange, seriesID, maxTS), true
+}
+
+// Contains reports whether the row falls into a bucket of the set.
+func (s *AntiEntropyBucketSet) Contains(timeRange timestamp.TimeRange, seriesID common.SeriesID, ts int64) bool {
+	b := AntiEntropyBucket(timeRange, seriesID, ts)
+	return b >= 0 && s[b]
+}
+
+// AntiEntropyBatches returns the settled buckets which differ between the local and the remote digests,
+// split into the batches to repair, and the number of the differing buckets.
+// The buckets in which the remote replica holds no rows are counted but not repaired.
+func AntiEntropyBatches(local, remote *AntiEntropyDigest, timeRange timestamp.TimeRange, now time.Time) ([][]uint32, int) {
+	var batches [][]uint32
+	var batch []uint32
+	var localRows, remoteRows uint64
+	var mismatched int
+	for b := range AntiEntropyBuckets {
+		if local.Rows[b] == remote.Rows[b] && local.Sums[b] == remote.Sums[b] {
+			continue
+		}
+		if !AntiEntropySettled(timeRange, b, now) {
+			continue
+		}
+		mismatched++
+		if remote.Rows[b] == 0 {
+			continue
+		}
+		if len(batch) > 0 && (localRows+local.Rows[b] > antiEntropyBatchRows || remoteRows+remote.Rows[b] > antiEntropyBatchRows) {
+			batches = append(batches, batch)
+			batch, localRows, remoteRows = nil, 0, 0
+		}
+		batch = append(batch, uint32(b))
+		localRows += local.Rows[b]
+		remoteRows += remote.Rows[b]
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, mismatched
+}
+
+// ToBuckets converts the non-empty buckets of the digest to their protobuf messages.
+func (d *AntiEntropyDigest) ToBuckets() []*clusterv1.AntiEntropyBucket {
+	var buckets []*clusterv1.AntiEntropyBucket
+	for i := range d.Rows {
+		if d.Rows[i] == 0 {
+			continue
+		}
+		buckets = append(buckets, &clusterv1.AntiEntropyBucket{Index: uint32(i), Rows: d.Rows[i], Digest: d.Sums[i]})
+	}
+	return buckets
+}
+
+// AntiEntropyDigestFromBuckets converts the protobuf messages of the buckets to a digest.
+func AntiEntropyDigestFromBuckets(buckets []*clusterv1.AntiEntropyBucket) *AntiEntropyDigest {
+	d := &AntiEntropyDigest{}
+	for _, b := range buckets {
+		if b.Index < AntiEntropyBuckets {
+			d.Rows[b.Index] = b.Rows
+			d.Sums[b.Index] = b.Digest
+		}
+	}
+	return d
+}
+
+// AntiEntropyBucketTimeRange returns the time slice of the segment covered by a bucket.
+func AntiEntropyBucketTimeRange(timeRange timestamp.TimeRange, bucket int) (int64, int64) {
+	start, end := timeRange.Start.UnixNano(), timeRange.End.UnixNano()
+	slice := uint64(bucket / antiEntropySeriesSlots)
+	span := uint64(end - start)
+	return start + int64(slice*span/antiEntropyTimeSlices), start + int64((slice+1)*span/antiEntropyTimeSlices)
+}
+
+// AntiEntropySettled reports whether the time slice of the bucket ended before the settle time.
+func AntiEntropySettled(timeRange timestamp.TimeRange, bucket int, now time.Time) bool {
+	_, end := AntiEntropyBucketTimeRange(timeRange, bucket)
+	return end <= now.Add(-AntiEntropySettleTime).UnixNano()
+}
+
+// AntiEntropyRowHash hashes the identity of a row: its series, its timestamp,
+// and its version or element ID, which tells the rows of the same timestamp apart.
+func AntiEntropyRowHash(seriesID common.SeriesID, ts int64, discriminator uint64) uint64 {
+	return mix64(uint64(seriesID) ^ mix64(uint64(ts)^mix64(discriminator)))
+}
+
+// mix64 is the finalizer of SplitMix64, which spreads every input bit over the output.
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// AntiEntropy compares the settled rows of a shard in a segment with the ones of the replicas,
+// and pulls the rows missing from the local shard.
+func AntiEntropy(client ReplicaClient, catalog ReplicaCatalog, digestTopic, repairTopic bus.Topic, nodeID, group string,
+	shardID common.ShardID, timeRange timestamp.TimeRange,
+) (AntiEntropyResult, error) {
+	var result AntiEntropyResult
+	shard, err := catalog.LoadReplicaShard(group, shardID, timeRange.Start)
+	if err != nil {
+		return result, err
+	}
+	if shard == nil {
+		return result, fmt.Errorf("shard %d of the segment %s is not found", shardID, timeRange)
+	}
+	defer shard.Release()
+	var errs []error
+	for _, node := range client.HealthyNodes() {
+		if node == nodeID {
+			continue
+		}
+		mismatched, pulled, err := antiEntropyWith(client, shard, digestTopic, repairTopic, node, nodeID, group, shardID, timeRange)
+		result.MismatchedBuckets += mismatched
+		result.PulledRows += pulled
+		if err != nil {
+			errs = append(errs, fmt.Errorf("replica %s: %w", node, err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+func antiEntropyWith(client ReplicaClient, shard ReplicaShard, digestTopic, repairTopic bus.Topic, node, nodeID, group string,
+	shardID common.ShardID, timeRange timestamp.TimeRange,
+) (int, uint64, error) {
+	resp, err := requestAntiEntropyDigest(client, digestTopic, node, &clusterv1.AntiEntropyDigestRequest{
+		Group:        group,
+		ShardId:      uint32(shardID),
+		SegmentStart: timeRange.Start.UnixNano(),
+		SegmentEnd:   timeRange.End.UnixNano(),
+	})
+	if err != nil || !resp.Found {
+		return 0, 0, err
+	}
+	// The digest is taken after the rows pulled from the former replicas are introduced.
+	local, err := shard.Digest()
+	if err != nil {
+		return 0, 0, err
+	}
+	now := time.Now()
+	batches, mismatched := AntiEntropyBatches(local, AntiEntropyDigestFromBuckets(resp.Buckets), timeRange, now)
+	pulled, err := pullRows(client, repairTopic, node, &clusterv1.RepairShardRequest{
+		RepairId:     fmt.Sprintf("%s/%s/%d/%d", nodeID, group, shardID, now.UnixNano()),
+		Group:        group,
+		ShardId:      uint32(shardID),
+		SegmentStart: timeRange.Start.UnixNano(),
+		SegmentEnd:   timeRange.End.UnixNano(),
+		Node:         nodeID,
+	}, batches, func(set *AntiEntropyBucketSet) ([]uint64, error) {
+		return shard.RowHashes(set, nil)
+	})
+	if errors.Is(err, errNoReplica) {
+		err = nil
+	}
+	return mismatched, pulled, err
+}
+
+// requestAntiEntropyDigest asks the node for the digest of a shard in a segment.
+func requestAntiEntropyDigest(client ReplicaClient, topic bus.Topic, node string, req *clusterv1.AntiEntropyDigestRequest) (
+	resp *clusterv1.AntiEntropyDigestResponse, err error,
+) {
+	for range antiEntropyDigestAttempts {
+		var f bus.Future
+		f, err = client.Publish(context.Background(), topic, bus.NewMessageWithNode(bus.MessageID(time.Now().UnixNano()), node, req))
+		if err != nil {
+			continue
+		}
+		var msg bus.Message
+		if msg, err = f.Get(); err != nil {
+			continue
+		}
+		var ok bool
+		if resp, ok = msg.Data().(*clusterv1.AntiEntropyDigestResponse); !ok {
+			return nil, fmt.Errorf("unexpected response %T", msg.Data())
+		}
+		if resp.Error != "" {
+			return nil, errors.New(resp.Error)
+		}
+		return resp, nil
+	}
+	return nil, err
+}
+
+// antiEntropyDigestListener returns the digest of a shard in a segment to the data node comparing its replicas.
+type antiEntropyDigestListener struct {
+	*bus.UnImplementedHealthyListener
+	catalog ReplicaCatalog
+}
+
+// NewAntiEntropyDigestListener returns the listener sending the digests of the shards of the catalog to the data nodes comparing them.
+func NewAntiEntropyDigestListener(catalog ReplicaCatalog) bus.MessageListener {
+	return &antiEntropyDigestListener{catalog: catalog}
+}
+
+func (l *antiEntropyDigestListener) Rev(_ context.Context, message bus.Message) bus.Message {
+	req, ok := message.Data().(*clusterv1.AntiEntropyDigestRequest)
+	if !ok {
+		return bus.NewMessage(message.ID(), common.NewError("invalid data type for anti-entropy digest request"))
+	}
+	resp := &clusterv1.AntiEntropyDigestResponse{}
+	shard, err := l.catalog.LoadReplicaShard(req.Group, common.ShardID(req.ShardId), time.Unix(0, req.SegmentStart))
+	if err != nil || shard == nil {
+		return bus.NewMessage(message.ID(), resp)
+	}
+	defer shard.Release()
+	// The buckets of the segments with different ends don't match.
+	if shard.TimeRange().End.UnixNano() != req.SegmentEnd {
+		return bus.NewMessage(message.ID(), resp)
+	}
+	digest, err := shard.Digest()
+	if err != nil {
+		resp.Error = err.Error()
+		return bus.NewMessage(message.ID(), resp)
+	}
+	resp.Found = true
+	resp.Buckets = digest.ToBuckets()
+	return bus.NewMessage(message.ID(), resp)
+}
+
+func (d *database[T, O]) startAntiEntropyTask(expr string) error {
+	if d.antiEntropy == nil || expr == "" {
+		return nil
+	}
+	return d.scheduler.Register("anti-entropy", cron.Minute|cron.Hour|cron.Dom|cron.Month|cron.Dow|cron.Descriptor,
+		expr, func(time.Time, *logger.Logger) bool {
+			if !d.AntiEntropy() {
+				d.logger.Debug().Msg("the previous anti-entropy repair is still running, skip it")
+			}
+			return true
+		})
+}
+
+// AntiEntropy compares the settled rows of all the local shards with their replicas in the background,
+// and pulls the rows missing from the local shards.
+// It returns false if it's disabled or a round is already running.
+func (d *database[T, O]) AntiEntropy() bool {
+	if d.antiEntropy == nil || d.closed.Load() {
+		return false
+	}
+	if !d.antiEntropyRunning.CompareAndSwap(false, true) {
+		return false
+	}
+	d.antiEntropyWG.Add(1)
+	go func() {
+		defer d.antiEntropyWG.Done()
+		defer d.antiEntropyRunning.Store(false)
+		d.runAntiEntropy(time.Now())
+	}()
+	return true
+}
+
+func (d *database[T, O]) runAntiEntropy(now time.Time) {
+	type shardSegment struct {
+		timeRange timestamp.TimeRange
+		shardID   common.ShardID
+	}
+	ss, err := d.segmentController.segments(true)
+	if err != nil {
+		d.logger.Error().Err(err).Msg("failed to list the segments for the anti-entropy repair")
+		return
+	}
+	var targets []shardSegment
+	settled := now.Add(-AntiEntropySettleTime)
+	for _, s := range ss {
+		if s.Start.Before(settled) {
+			_, shardIDs, _ := s.TablesWithShardIDs()
+			for _, id := range shardIDs {
+				targets = append(targets, shardSegment{timeRange: s.TimeRange, shardID: id})
+			}
+		}
+		s.DecRef()
+	}
+	d.logger.Info().Int("shards", len(targets)).Msg("start the anti-entropy repair")
+	var mismatched int
+	var pulled uint64
+	for _, t := range targets {
+		if d.closed.Load() {
+			return
+		}
+		d.incTotalAntiEntropyStarted(1)
+		start := time.Now()
+		result, err := d.antiEntropy(t.shardID, t.timeRange)
+		d.incTotalAntiEntropyLatency(time.Since(start).Seconds())
+		if err != nil {
+			d.incTotalAntiEntropyErr(1)
+			d.logger.Error().Err(err).Uint32("shard", uint32(t.shardID)).Time("segment_start", t.timeRange.Start).
+				Msg("failed to compare the shard with its replicas")
+			continue
+		}
+		d.incTotalAntiEntropyFinished(1)
+		d.incTotalAntiEntropyMismatchedBuckets(result.MismatchedBuckets)
+		d.incTotalAntiEntropyPulledRows(float64(result.PulledRows))
+		mismatched += result.MismatchedBuckets
+		pulled += result.PulledRows
+		if result.MismatchedBuckets > 0 {
+			d.logger.Info().Uint32("shard", uint32(t.shardID)).Time("segment_start", t.timeRange.Start).
+				Int("mismatched_buckets", result.MismatchedBuckets).Uint64("pulled_rows", result.PulledRows).
+				Msg("repaired the shard from its replicas")
+		}
+	}
+	d.logger.Info().Int("shards", len(targets)).Int("mismatched_buckets", mismatched).Uint64("pulled_rows", pulled).
+		Dur("duration", time.Since(now)).Msg("finished the anti-entropy repair")
+}
@@ -0,0 +1,148 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+func TestAntiEntropyBucket(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := timestamp.NewSectionTimeRange(start, start.Add(24*time.Hour))
+	slice := 24 * time.Hour / antiEntropyTimeSlices
+
+	assert.Equal(t, -1, AntiEntropyBucket(tr, 1, start.Add(-time.Nanosecond).UnixNano()))
+	assert.Equal(t, -1, AntiEntropyBucket(tr, 1, start.Add(24*time.Hour).UnixNano()))
+	assert.Equal(t, 3, AntiEntropyBucket(tr, 19, start.UnixNano()))
+	b := AntiEntropyBucket(tr, 19, start.Add(2*slice).UnixNano())
+	assert.Equal(t, 2*antiEntropySeriesSlots+3, b)
+	begin, end := AntiEntropyBucketTimeRange(tr, b)
+	assert.Equal(t, start.Add(2*slice).UnixNano(), begin)
+	assert.Equal(t, start.Add(3*slice).UnixNano(), end)
+
+	assert.False(t, AntiEntropySettled(tr, b, start.Add(3*slice)))
+	assert.True(t, AntiEntropySettled(tr, b, start.Add(3*slice+AntiEntropySettleTime)))
+}
+
+func TestAntiEntropyDigestIsOrderIndependent(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := timestamp.NewSectionTimeRange(start, start.Add(time.Hour))
+	add := func(d *AntiEntropyDigest, sid common.SeriesID, ts int64, v uint64) {
+		d.Add(AntiEntropyBucket(tr, sid, ts), AntiEntropyRowHash(sid, ts, v))
+	}
+	ts := start.UnixNano()
+
+	var whole, left, right AntiEntropyDigest
+	add(&whole, 1, ts, 1)
+	add(&whole, 2, ts+1, 1)
+	add(&whole, 1, ts+2, 2)
+	add(&right, 1, ts+2, 2)
+	add(&left, 2, ts+1, 1)
+	add(&left, 1, ts, 1)
+	left.Merge(&right)
+	assert.Equal(t, whole, left)
+
+	var other AntiEntropyDigest
+	add(&other, 1, ts, 2)
+	add(&other, 2, ts+1, 1)
+	add(&other, 1, ts+2, 2)
+	assert.Equal(t, whole.Rows, other.Rows)
+	assert.NotEqual(t, whole.Sums, other.Sums)
+	assert.Equal(t, whole, *AntiEntropyDigestFromBuckets(whole.ToBuckets()))
+}
+
+func TestAntiEntropyBatches(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := timestamp.NewSectionTimeRange(start, start.Add(24*time.Hour))
+	now := start.Add(48 * time.Hour)
+	var local, remote AntiEntropyDigest
+	// The same rows.
+	local.Rows[0], local.Sums[0] = 10, 100
+	remote.Rows[0], remote.Sums[0] = 10, 100
+	// The local replica misses some rows.
+	local.Rows[1], local.Sums[1] = 10, 100
+	remote.Rows[1], remote.Sums[1] = antiEntropyBatchRows, 200
+	// The remote replica holds no rows.
+	local.Rows[2], local.Sums[2] = 10, 100
+	// The rows differ.
+	local.Rows[3], local.Sums[3] = 10, 100
+	remote.Rows[3], remote.Sums[3] = 10, 300
+	remote.Rows[4], remote.Sums[4] = 1, 300
+
+	batches, mismatched := AntiEntropyBatches(&local, &remote, tr, now)
+	assert.Equal(t, 4, mismatched)
+	require.Len(t, batches, 2)
+	assert.Equal(t, []uint32{1}, batches[0])
+	assert.Equal(t, []uint32{3, 4}, batches[1])
+
+	batches, mismatched = AntiEntropyBatches(&local, &remote, tr, start.Add(time.Hour))
+	assert.Zero(t, mismatched)
+	assert.Empty(t, batches)
+}
+
+func TestAntiEntropyBucketSet(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := timestamp.NewSectionTimeRange(start, start.Add(16*time.Hour))
+	set := NewAntiEntropyBucketSet([]uint32{2*antiEntropySeriesSlots + 5, AntiEntropyBuckets})
+
+	assert.True(t, set.Contains(tr, 5, start.Add(2*time.Hour).UnixNano()))
+	assert.False(t, set.Contains(tr, 6, start.Add(2*time.Hour).UnixNano()))
+	assert.True(t, set.Overlaps(tr, 21, start.UnixNano(), start.Add(5*time.Hour).UnixNano()))
+	assert.False(t, set.Overlaps(tr, 21, start.UnixNano(), start.Add(time.Hour).UnixNano()))
+	assert.False(t, set.Overlaps(tr, 20, start.UnixNano(), start.Add(5*time.Hour).UnixNano()))
+	assert.True(t, set.OverlapsTime(tr, start.Add(2*time.Hour).UnixNano(), start.Add(30*time.Hour).UnixNano()))
+	assert.False(t, set.OverlapsTime(tr, start.Add(3*time.Hour).UnixNano(), start.Add(30*time.Hour).UnixNano()))
+	assert.False(t, set.OverlapsTime(tr, start.Add(-2*time.Hour).UnixNano(), start.Add(-time.Hour).UnixNano()))
+}
+
+func TestAntiEntropy(t *testing.T) {
+	start := time.Now().Truncate(time.Hour).Add(-48 * time.Hour)
+	tr := timestamp.NewSectionTimeRange(start, start.Add(16*time.Hour))
+	row := func(sid common.SeriesID, h time.Duration) fakeRow {
+		return fakeRow{part: 1, seriesID: sid, ts: start.Add(h).UnixNano(), version: 1}
+	}
+	a, b, c, d := row(1, time.Hour), row(2, 2*time.Hour), row(3, 3*time.Hour), row(4, 4*time.Hour)
+	client, shards := newFakeReplicas(tr, map[string][]fakeRow{
+		"local":    {a, b},
+		"empty":    nil,
+		"replica1": {a, b, c},
+		"replica2": {a, d},
+	})
+	catalog := &fakeReplicaCatalog{shard: shards["local"]}
+
+	result, err := AntiEntropy(client, catalog, testDigestTopic, testRepairTopic, "local", "g", 0, tr)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), result.PulledRows)
+	hashes := shards["local"].hashes()
+	assert.Len(t, hashes, 4)
+	assert.Contains(t, hashes, c.hash())
+	assert.Contains(t, hashes, d.hash())
+
+	// The buckets in which a replica holds no rows differ, but nothing is pulled or removed.
+	result, err = AntiEntropy(client, catalog, testDigestTopic, testRepairTopic, "local", "g", 0, tr)
+	require.NoError(t, err)
+	assert.Equal(t, AntiEntropyResult{MismatchedBuckets: 3}, result)
+	assert.Len(t, shards["local"].hashes(), 4)
+}
@@ -0,0 +1,150 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+)
+
+const (
+	// PartChecksumFilename is the name of the file holding the checksums of the files of a part.
+	PartChecksumFilename = "checksums.json"
+
+	checksumAlgorithmCRC32C = "crc32c"
+	checksumBufferSize      = 64 << 10
+)
+
+var (
+	// ErrPartCorrupted indicates the files of a part don't match their checksums.
+	ErrPartCorrupted = errors.New("part corrupted")
+
+	errNoPartChecksums = errors.New("part has no checksums")
+
+	crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+type partChecksums struct {
+	Files     map[string]fileChecksum `json:"files"`
+	Algorithm string                  `json:"algorithm"`
+}
+
+type fileChecksum struct {
+	Size   uint64 `json:"size"`
+	CRC32C uint32 `json:"crc32c"`
+}
+
+// MustWritePartChecksums records the size and the CRC32C checksum of every file of the part at partPath.
+// It must be called after all the other files of the part are written.
+func MustWritePartChecksums(fileSystem fs.FileSystem, partPath string) {
+	pc := partChecksums{
+		Algorithm: checksumAlgorithmCRC32C,
+		Files:     make(map[string]fileChecksum),
+	}
+	for _, e := range fileSystem.ReadDir(partPath) {
+		if e.IsDir() || e.Name() == PartChecksumFilename {
+			continue
+		}
+		name := filepath.Join(partPath, e.Name())
+		fc, err := checksumFile(fileSystem, name, nil)
+		if err != nil {
+			logger.Panicf("cannot compute the checksum of %s: %s", name, err)
+		}
+		pc.Files[e.Name()] = fc
+	}
+	data, err := json.Marshal(pc)
+	if err != nil {
+		logger.Panicf("cannot marshal the checksums of %s: %s", partPath, err)
+	}
+	fs.MustFlush(fileSystem, data, filepath.Join(partPath, PartChecksumFilename), FilePerm)
+}
+
+// VerifyPartChecksums re-reads the files of the part at partPath and compares them with their recorded checksums.
+// It returns the number of bytes read, and an error wrapping ErrPartCorrupted if any file is missing or doesn't match.
+// pace, if not nil, is called after each read with the number of bytes read, and stops the verification by returning an error.
+func VerifyPartChecksums(fileSystem fs.FileSystem, partPath string, pace func(n int) error) (uint64, error) {
+	data, err := fileSystem.Read(filepath.Join(partPath, PartChecksumFilename))
+	if err != nil {
+		return 0, errNoPartChecksums
+	}
+	var pc partChecksums
+	if err = json.Unmarshal(data, &pc); err != nil {
+		return 0, errors.Wrapf(ErrPartCorrupted, "cannot parse %s: %s", PartChecksumFilename, err)
+	}
+	if pc.Algorithm != checksumAlgorithmCRC32C {
+		return 0, errors.Wrapf(ErrPartCorrupted, "unknown checksum algorithm %q", pc.Algorithm)
+	}
+	var total uint64
+	for name, expected := range pc.Files {
+		actual, err := checksumFile(fileSystem, filepath.Join(partPath, name), pace)
+		total += actual.Size
+		if err != nil {
+			if errors.Is(err, errScrubStopped) {
+				return total, err
+			}
+			return total, errors.Wrapf(ErrPartCorrupted, "cannot read %s: %s", name, err)
+		}
+		if actual.Size != expected.Size {
+			return total, errors.Wrapf(ErrPartCorrupted, "the size of %s is %d, expected %d", name, actual.Size, expected.Size)
+		}
+		if actual.CRC32C != expected.CRC32C {
+			return total, errors.Wrapf(ErrPartCorrupted, "the checksum of %s is %08x, expected %08x", name, actual.CRC32C, expected.CRC32C)
+		}
+	}
+	return total, nil
+}
+
+func checksumFile(fileSystem fs.FileSystem, name string, pace func(n int) error) (fileChecksum, error) {
+	var fc fileChecksum
+	f, err := fileSystem.OpenFile(name)
+	if err != nil {
+		return fc, err
+	}
+	defer fs.MustClose(f)
+	buf := make([]byte, checksumBufferSize)
+	var offset int64
+	for {
+		n, err := f.Read(offset, buf)
+		if n > 0 {
+			fc.CRC32C = crc32.Update(fc.CRC32C, crc32cTable, buf[:n])
+			fc.Size += uint64(n)
+			offset += int64(n)
+			if pace != nil {
+				if paceErr := pace(n); paceErr != nil {
+					return fc, paceErr
+				}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fc, nil
+			}
+			return fc, err
+		}
+		if n == 0 {
+			return fc, nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+)
+
+func writeTestPart(t *testing.T, fileSystem fs.FileSystem, partPath string) {
+	fileSystem.MkdirIfNotExist(partPath, DirPerm)
+	fs.MustFlush(fileSystem, []byte(`{"totalCount":1}`), filepath.Join(partPath, partMetadataFilename), FilePerm)
+	fs.MustFlush(fileSystem, make([]byte, 3*checksumBufferSize+7), filepath.Join(partPath, "primary.bin"), FilePerm)
+	fs.MustFlush(fileSystem, []byte("tag family"), filepath.Join(partPath, "default.tf"), FilePerm)
+	MustWritePartChecksums(fileSystem, partPath)
+	require.True(t, fileSystem.IsExist(filepath.Join(partPath, PartChecksumFilename)))
+}
+
+func TestVerifyPartChecksums(t *testing.T) {
+	fileSystem := fs.NewLocalFileSystem()
+
+	t.Run("intact part", func(t *testing.T) {
+		partPath := filepath.Join(t.TempDir(), "0000000000000001")
+		writeTestPart(t, fileSystem, partPath)
+		var paced int
+		n, err := VerifyPartChecksums(fileSystem, partPath, func(n int) error {
+			paced += n
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3*checksumBufferSize+7+len(`{"totalCount":1}`)+len("tag family")), n)
+		assert.Equal(t, int(n), paced)
+	})
+
+	t.Run("flipped byte", func(t *testing.T) {
+		partPath := filepath.Join(t.TempDir(), "0000000000000001")
+		writeTestPart(t, fileSystem, partPath)
+		f, err := os.OpenFile(filepath.Join(partPath, "primary.bin"), os.O_WRONLY, 0)
+		require.NoError(t, err)
+		_, err = f.WriteAt([]byte{1}, checksumBufferSize+3)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		_, err = VerifyPartChecksums(fileSystem, partPath, nil)
+		assert.ErrorIs(t, err, ErrPartCorrupted)
+	})
+
+	t.Run("truncated file", func(t *testing.T) {
+		partPath := filepath.Join(t.TempDir(), "0000000000000001")
+		writeTestPart(t, fileSystem, partPath)
+		require.NoError(t, os.Truncate(filepath.Join(partPath, "default.tf"), 3))
+		_, err := VerifyPartChecksums(fileSystem, partPath, nil)
+		assert.ErrorIs(t, err, ErrPartCorrupted)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		partPath := filepath.Join(t.TempDir(), "0000000000000001")
+		writeTestPart(t, fileSystem, partPath)
+		require.NoError(t, os.Remove(filepath.Join(partPath, partMetadataFilename)))
+		_, err := VerifyPartChecksums(fileSystem, partPath, nil)
+		assert.ErrorIs(t, err, ErrPartCorrupted)
+	})
+
+	t.Run("part without checksums", func(t *testing.T) {
+		partPath := filepath.Join(t.TempDir(), "0000000000000001")
+		writeTestPart(t, fileSystem, partPath)
+		require.NoError(t, os.Remove(filepath.Join(partPath, PartChecksumFilename)))
+		_, err := VerifyPartChecksums(fileSystem, partPath, nil)
+		assert.ErrorIs(t, err, errNoPartChecksums)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		partPath := filepath.Join(t.TempDir(), "0000000000000001")
+		writeTestPart(t, fileSystem, partPath)
+		_, err := VerifyPartChecksums(fileSystem, partPath, func(int) error { return errScrubStopped })
+		assert.True(t, errors.Is(err, errScrubStopped))
+	})
+}
@@ -35,18 +35,20 @@ import (
 // RetentionService defines the interface that services must implement
 // to support forced retention cleanup.
 type RetentionService interface {
-	// GetDataPath returns the service's data directory path
-	GetDataPath() string
+	// GetDataPaths returns the service's data directory paths, one per data volume
+	GetDataPaths() []string
 	// GetSnapshotDir returns the service's snapshot directory path
 	GetSnapshotDir() string
 	// LoadAllGroups returns all groups managed by this service
 	LoadAllGroups() []resourceSchema.Group
 	// PeekOldestSegmentEndTimeInGroup returns the end time of the oldest segment in the specified group
+	// which holds data on the volume. An empty volume matches every segment.
 	// Returns zero time and false if no segments exist or group not found
-	PeekOldestSegmentEndTimeInGroup(group string) (time.Time, bool)
-	// DeleteOldestSegmentInGroup deletes the oldest segment in the specified group
+	PeekOldestSegmentEndTimeInGroup(group, volume string) (time.Time, bool)
+	// DeleteOldestSegmentInGroup deletes the oldest segment in the specified group which holds data on the volume.
+	// An empty volume matches every segment.
 	// Returns true if a segment was deleted, false if no segments to delete
-	DeleteOldestSegmentInGroup(group string) (bool, error)
+	DeleteOldestSegmentInGroup(group, volume string) (bool, error)
 	// CleanupOldSnapshots removes snapshots older than the specified duration
 	CleanupOldSnapshots(maxAge time.Duration) error
 	// GetServiceName returns the service name for metrics and logging
@@ -67,14 +69,26 @@ type RetentionConfig struct {
 	ForceCleanupEnabled bool
 }
 
+// EvictableCache is a disk-resident cache which can release space under disk pressure.
+type EvictableCache interface {
+	// Size returns the bytes held by the cache
+	Size() uint64
+	// Evict drops entries until the cache holds at most target bytes and returns the bytes released
+	Evict(target uint64) uint64
+}
+
 // DiskMonitor monitors disk usage and orchestrates forced retention cleanup
 // for a service when disk usage exceeds configured watermarks.
+// Each data volume is watched on its own, so the cleanup of a full volume
+// only deletes segments which keep data on it.
 type DiskMonitor struct {
 	service        RetentionService
+	remoteCache    EvictableCache
 	logger         *logger.Logger
 	ticker         *time.Ticker
 	stopCh         chan struct{}
 	metrics        *diskMonitorMetrics
+	activeVolumes  map[string]struct{}
 	config         RetentionConfig
 	snapshotMaxAge time.Duration
 	isActive       atomic.Bool
@@ -87,7 +101,10 @@ type diskMonitorMetrics struct {
 	forcedRetentionLastRunSeconds  meter.Gauge
 	forcedRetentionCooldownSeconds meter.Gauge
 	diskUsagePercent               meter.Gauge
+	volumeDiskUsagePercent         meter.Gauge
 	snapshotsDeletedTotal          meter.Counter
+	remoteCacheSizeBytes           meter.Gauge
+	remoteCacheEvictedBytesTotal   meter.Counter
 }
 
 // getRealTimeDiskUsagePercent calculates real-time disk usage percentage for the given path.
@@ -124,7 +141,10 @@ func NewDiskMonitor(service RetentionService, config RetentionConfig, omr observ
 		forcedRetentionLastRunSeconds:  factory.NewGauge("forced_retention_last_run_seconds", "service"),
 		forcedRetentionCooldownSeconds: factory.NewGauge("forced_retention_cooldown_seconds", "service"),
 		diskUsagePercent:               factory.NewGauge("disk_usage_percent", "service"),
+		volumeDiskUsagePercent:         factory.NewGauge("volume_disk_usage_percent", "service", "volume"),
 		snapshotsDeletedTotal:          factory.NewCounter("snapshots_deleted_total", "service"),
+		remoteCacheSizeBytes:           factory.NewGauge("remote_cache_size_bytes", "service"),
+		remoteCacheEvictedBytesTotal:   factory.NewCounter("remote_cache_evicted_bytes_total", "service"),
 	}
 
 	// Initialize cooldown metric
@@ -136,10 +156,17 @@ func NewDiskMonitor(service RetentionService, config RetentionConfig, omr observ
 		logger:         logger,
 		stopCh:         make(chan struct{}),
 		metrics:        metrics,
+		activeVolumes:  make(map[string]struct{}),
 		snapshotMaxAge: 24 * time.Hour, // Always keep snapshots newer than 24h
 	}
 }
 
+// SetRemoteCache registers the read-through cache of offloaded data. The cache is emptied
+// before any segment is deleted by the forced cleanup.
+func (dm *DiskMonitor) SetRemoteCache(cache EvictableCache) {
+	dm.remoteCache = cache
+}
+
 // Start begins monitoring disk usage and starts the forced retention process.
 func (dm *DiskMonitor) Start() {
 	if dm.config.CheckInterval <= 0 {
@@ -194,52 +221,91 @@ func (dm *DiskMonitor) monitorLoop(serviceName string) {
 }
 
 func (dm *DiskMonitor) checkAndCleanup(serviceName string) {
-	// Check disk usage using real-time calculation for responsive forced cleanup
-	diskPercent, err := getRealTimeDiskUsagePercent(dm.service.GetDataPath())
-	if err != nil {
-		dm.logger.Error().Err(err).Msg("failed to get real-time disk usage")
-		// Fall back to cached metrics if real-time calculation fails
-		diskPercent = obsservice.GetPathUsedPercent(dm.service.GetDataPath())
+	volumes := dm.service.GetDataPaths()
+	usage := make([]int, len(volumes))
+	maxPercent := 0
+	for i, volume := range volumes {
+		// Check disk usage using real-time calculation for responsive forced cleanup
+		usage[i] = dm.diskUsagePercent(volume)
+		dm.metrics.volumeDiskUsagePercent.Set(float64(usage[i]), serviceName, volume)
+		if usage[i] > maxPercent {
+			maxPercent = usage[i]
+		}
+	}
+	dm.metrics.diskUsagePercent.Set(float64(maxPercent), serviceName)
+	if dm.remoteCache != nil {
+		dm.metrics.remoteCacheSizeBytes.Set(float64(dm.remoteCache.Size()), serviceName)
 	}
-	dm.metrics.diskUsagePercent.Set(float64(diskPercent), serviceName)
 
-	dm.logger.Debug().Int("disk_percent", diskPercent).Bool("force_cleanup_enabled", dm.config.ForceCleanupEnabled).Msg("checking disk usage")
+	dm.logger.Debug().Ints("disk_percent", usage).Strs("volumes", volumes).
+		Bool("force_cleanup_enabled", dm.config.ForceCleanupEnabled).Msg("checking disk usage")
 
 	// If force cleanup is disabled, only monitor disk usage but don't trigger cleanup
 	if !dm.config.ForceCleanupEnabled {
 		// If cleanup was somehow active (shouldn't happen), deactivate it
 		if dm.isActive.Load() {
 			dm.logger.Info().Msg("force cleanup disabled, stopping any active cleanup")
-			dm.isActive.Store(false)
-			dm.metrics.forcedRetentionActive.Set(0, serviceName)
+			for volume := range dm.activeVolumes {
+				dm.deactivate(serviceName, volume)
+			}
 		}
 		return
 	}
 
+	for i, volume := range volumes {
+		dm.checkVolume(serviceName, volume, usage[i])
+	}
+}
+
+func (dm *DiskMonitor) checkVolume(serviceName, volume string, diskPercent int) {
+	_, active := dm.activeVolumes[volume]
+
 	// If usage is below high watermark and no cleanup is active, nothing to do
-	if float64(diskPercent) < dm.config.HighWatermark && !dm.isActive.Load() {
+	if float64(diskPercent) < dm.config.HighWatermark && !active {
 		return
 	}
 
 	// If usage is above high watermark, start forced cleanup
-	if float64(diskPercent) >= dm.config.HighWatermark && !dm.isActive.Load() {
+	if float64(diskPercent) >= dm.config.HighWatermark && !active {
 		dm.logger.Info().
+			Str("volume", volume).
 			Int("disk_percent", diskPercent).
 			Float64("high_watermark", dm.config.HighWatermark).
 			Msg("disk usage above high watermark, starting forced cleanup")
 
+		dm.activeVolumes[volume] = struct{}{}
 		dm.isActive.Store(true)
 		dm.metrics.forcedRetentionActive.Set(1, serviceName)
 		dm.metrics.forcedRetentionRunsTotal.Inc(1, serviceName)
 	}
 
-	// If cleanup is active, continue until below low watermark
-	if dm.isActive.Load() {
-		dm.runForcedCleanup(serviceName, diskPercent)
+	// Cleanup is active, continue until below low watermark
+	dm.runForcedCleanup(serviceName, volume)
+}
+
+// deactivate stops the forced cleanup of the volume. The service stays active
+// as long as any of its volumes is being cleaned up.
+func (dm *DiskMonitor) deactivate(serviceName, volume string) {
+	delete(dm.activeVolumes, volume)
+	if len(dm.activeVolumes) > 0 {
+		return
+	}
+	dm.isActive.Store(false)
+	dm.metrics.forcedRetentionActive.Set(0, serviceName)
+}
+
+// diskUsagePercent returns the real-time disk usage percentage of the volume.
+func (dm *DiskMonitor) diskUsagePercent(volume string) int {
+	diskPercent, err := getRealTimeDiskUsagePercent(volume)
+	if err != nil {
+		dm.logger.Error().Err(err).Str("volume", volume).Msg("failed to get real-time disk usage")
+		// Fall back to cached metrics if real-time calculation fails
+		diskPercent = obsservice.GetPathUsedPercent(volume)
 	}
+	return diskPercent
 }
 
-func (dm *DiskMonitor) runForcedCleanup(serviceName string, _ int) {
+func (dm *DiskMonitor) runForcedCleanup(serviceName, volume string) {
 	startTime := time.Now()
 	defer func() {
 		dm.metrics.forcedRetentionLastRunSeconds.Set(time.Since(startTime).Seconds(), serviceName)
@@ -251,41 +317,48 @@ func (dm *DiskMonitor) runForcedCleanup(serviceName string, _ int) {
 	}
 
 	// Check if snapshot cleanup was enough
-	diskPercent, err := getRealTimeDiskUsagePercent(dm.service.GetDataPath())
-	if err != nil {
-		dm.logger.Error().Err(err).Msg("failed to get real-time disk usage after snapshot cleanup")
-		diskPercent = obsservice.GetPathUsedPercent(dm.service.GetDataPath())
-	}
+	diskPercent := dm.diskUsagePercent(volume)
 	if float64(diskPercent) <= dm.config.LowWatermark {
 		dm.logger.Info().
+			Str("volume", volume).
 			Int("disk_percent", diskPercent).
 			Float64("low_watermark", dm.config.LowWatermark).
 			Msg("disk usage below low watermark after snapshot cleanup, stopping forced cleanup")
 
-		dm.isActive.Store(false)
-		dm.metrics.forcedRetentionActive.Set(0, serviceName)
+		dm.deactivate(serviceName, volume)
 		return
 	}
 
+	// Then, drop the cached remote data which can be fetched again
+	if dm.evictRemoteCache(serviceName) {
+		diskPercent = dm.diskUsagePercent(volume)
+		if float64(diskPercent) <= dm.config.LowWatermark {
+			dm.logger.Info().
+				Str("volume", volume).
+				Int("disk_percent", diskPercent).
+				Float64("low_watermark", dm.config.LowWatermark).
+				Msg("disk usage below low watermark after remote cache eviction, stopping forced cleanup")
+
+			dm.deactivate(serviceName, volume)
+			return
+		}
+	}
+
 	// Delete segments iteratively
-	deleted := dm.deleteOldestSegment(serviceName)
+	deleted := dm.deleteOldestSegment(serviceName, volume)
 	if deleted {
 		dm.metrics.forcedRetentionSegmentsDeleted.Inc(1, serviceName)
 
 		// Check if we're now below low watermark
-		diskPercent, err = getRealTimeDiskUsagePercent(dm.service.GetDataPath())
-		if err != nil {
-			dm.logger.Error().Err(err).Msg("failed to get real-time disk usage after segment deletion")
-			diskPercent = obsservice.GetPathUsedPercent(dm.service.GetDataPath())
-		}
+		diskPercent = dm.diskUsagePercent(volume)
 		if float64(diskPercent) <= dm.config.LowWatermark {
 			dm.logger.Info().
+				Str("volume", volume).
 				Int("disk_percent", diskPercent).
 				Float64("low_watermark", dm.config.LowWatermark).
 				Msg("disk usage below low watermark, stopping forced cleanup")
 
-			dm.isActive.Store(false)
-			dm.metrics.forcedRetentionActive.Set(0, serviceName)
+			dm.deactivate(serviceName, volume)
 			return
 		}
 
@@ -294,9 +367,8 @@ func (dm *DiskMonitor) runForcedCleanup(serviceName string, _ int) {
 		time.Sleep(dm.config.Cooldown)
 	} else {
 		// No more segments to delete, stop cleanup
-		dm.logger.Warn().Msg("no more segments available for deletion, stopping forced cleanup")
-		dm.isActive.Store(false)
-		dm.metrics.forcedRetentionActive.Set(0, serviceName)
+		dm.logger.Warn().Str("volume", volume).Msg("no more segments available for deletion, stopping forced cleanup")
+		dm.deactivate(serviceName, volume)
 	}
 }
 
@@ -308,33 +380,47 @@ func (dm *DiskMonitor) cleanupSnapshots(serviceName string) error {
 	return err
 }
 
-func (dm *DiskMonitor) deleteOldestSegment(_ string) bool {
+func (dm *DiskMonitor) evictRemoteCache(serviceName string) bool {
+	if dm.remoteCache == nil {
+		return false
+	}
+	freed := dm.remoteCache.Evict(0)
+	if freed == 0 {
+		return false
+	}
+	dm.metrics.remoteCacheEvictedBytesTotal.Inc(float64(freed), serviceName)
+	dm.metrics.remoteCacheSizeBytes.Set(float64(dm.remoteCache.Size()), serviceName)
+	dm.logger.Info().Uint64("bytes", freed).Msg("evicted the remote cache")
+	return true
+}
+
+func (dm *DiskMonitor) deleteOldestSegment(_, volume string) bool {
 	groups := dm.service.LoadAllGroups()
 	if len(groups) == 0 {
 		return false
 	}
 
-	// Find the group with the oldest segment globally
-	oldestGroup := dm.findGroupWithOldestSegment(groups)
+	// Find the group with the oldest segment on the volume
+	oldestGroup := dm.findGroupWithOldestSegment(groups, volume)
 	if oldestGroup == "" {
 		return false
 	}
 
 	// Delete the oldest segment from that group
-	deleted, err := dm.service.DeleteOldestSegmentInGroup(oldestGroup)
+	deleted, err := dm.service.DeleteOldestSegmentInGroup(oldestGroup, volume)
 	if err != nil {
-		dm.logger.Error().Err(err).Str("group", oldestGroup).Msg("failed to delete oldest segment")
+		dm.logger.Error().Err(err).Str("group", oldestGroup).Str("volume", volume).Msg("failed to delete oldest segment")
 		return false
 	}
 
 	if deleted {
-		dm.logger.Info().Str("group", oldestGroup).Msg("deleted oldest segment")
+		dm.logger.Info().Str("group", oldestGroup).Str("volume", volume).Msg("deleted oldest segment")
 	}
 
 	return deleted
 }
 
-func (dm *DiskMonitor) findGroupWithOldestSegment(groups []resourceSchema.Group) string {
+func (dm *DiskMonitor) findGroupWithOldestSegment(groups []resourceSchema.Group, volume string) string {
 	type groupSegmentTime struct {
 		endTime   time.Time
 		groupName string
@@ -345,7 +431,7 @@ func (dm *DiskMonitor) findGroupWithOldestSegment(groups []resourceSchema.Group)
 	// Query each group's oldest segment end time
 	for _, group := range groups {
 		groupName := group.GetSchema().Metadata.Name
-		endTime, hasSegments := dm.service.PeekOldestSegmentEndTimeInGroup(groupName)
+		endTime, hasSegments := dm.service.PeekOldestSegmentEndTimeInGroup(groupName, volume)
 
 		// Only consider groups that have segments
 		if hasSegments {
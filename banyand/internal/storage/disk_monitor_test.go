@@ -39,6 +39,7 @@ type MockRetentionService struct {
 	hasSegments    map[string]bool
 	deleteResponse map[string]bool
 	deleteError    map[string]error
+	volumes        map[string]string
 	dataPath       string
 	snapshotDir    string
 	serviceName    string
@@ -55,11 +56,12 @@ func NewMockRetentionService() *MockRetentionService {
 		hasSegments:    make(map[string]bool),
 		deleteResponse: make(map[string]bool),
 		deleteError:    make(map[string]error),
+		volumes:        make(map[string]string),
 	}
 }
 
-func (m *MockRetentionService) GetDataPath() string {
-	return m.dataPath
+func (m *MockRetentionService) GetDataPaths() []string {
+	return []string{m.dataPath}
 }
 
 func (m *MockRetentionService) GetSnapshotDir() string {
@@ -70,13 +72,19 @@ func (m *MockRetentionService) LoadAllGroups() []resourceSchema.Group {
 	return m.groups
 }
 
-func (m *MockRetentionService) PeekOldestSegmentEndTimeInGroup(group string) (time.Time, bool) {
+func (m *MockRetentionService) PeekOldestSegmentEndTimeInGroup(group, volume string) (time.Time, bool) {
+	if !m.onVolume(group, volume) {
+		return time.Time{}, false
+	}
 	endTime, exists := m.segmentTimes[group]
 	hasSegs := m.hasSegments[group]
 	return endTime, hasSegs && exists
 }
 
-func (m *MockRetentionService) DeleteOldestSegmentInGroup(group string) (bool, error) {
+func (m *MockRetentionService) DeleteOldestSegmentInGroup(group, volume string) (bool, error) {
+	if !m.onVolume(group, volume) {
+		return false, nil
+	}
 	if err, exists := m.deleteError[group]; exists && err != nil {
 		return false, err
 	}
@@ -110,6 +118,15 @@ func (m *MockRetentionService) SetSnapshotError(err error) {
 	m.snapshotError = err
 }
 
+func (m *MockRetentionService) SetVolume(group, volume string) {
+	m.volumes[group] = volume
+}
+
+func (m *MockRetentionService) onVolume(group, volume string) bool {
+	v, ok := m.volumes[group]
+	return volume == "" || !ok || v == volume
+}
+
 // Mock metrics types.
 type mockMetricsRegistry struct{}
 
@@ -210,6 +227,34 @@ func TestNewDiskMonitor(t *testing.T) {
 	assert.False(t, dm.isActive.Load())
 }
 
+type mockEvictableCache struct {
+	size uint64
+}
+
+func (c *mockEvictableCache) Size() uint64 {
+	return c.size
+}
+
+func (c *mockEvictableCache) Evict(target uint64) uint64 {
+	if c.size <= target {
+		return 0
+	}
+	freed := c.size - target
+	c.size = target
+	return freed
+}
+
+func TestDiskMonitor_EvictRemoteCache(t *testing.T) {
+	dm := NewDiskMonitor(NewMockRetentionService(), RetentionConfig{Cooldown: time.Second}, createMockMetricsRegistry())
+	assert.False(t, dm.evictRemoteCache("test"))
+
+	cache := &mockEvictableCache{size: 1 << 20}
+	dm.SetRemoteCache(cache)
+	assert.True(t, dm.evictRemoteCache("test"))
+	assert.Equal(t, uint64(0), cache.Size())
+	assert.False(t, dm.evictRemoteCache("test"), "an empty cache releases nothing")
+}
+
 func TestDiskMonitor_StartStop(t *testing.T) {
 	service := NewMockRetentionService()
 	config := RetentionConfig{
@@ -272,7 +317,7 @@ func TestDiskMonitor_findGroupWithOldestSegment(t *testing.T) {
 
 	t.Run("no groups", func(t *testing.T) {
 		groups := []resourceSchema.Group{}
-		result := dm.findGroupWithOldestSegment(groups)
+		result := dm.findGroupWithOldestSegment(groups, "")
 		assert.Empty(t, result)
 	})
 
@@ -284,7 +329,7 @@ func TestDiskMonitor_findGroupWithOldestSegment(t *testing.T) {
 		service.SetSegmentTime("group1", time.Time{}, false)
 		service.SetSegmentTime("group2", time.Time{}, false)
 
-		result := dm.findGroupWithOldestSegment(groups)
+		result := dm.findGroupWithOldestSegment(groups, "")
 		assert.Empty(t, result)
 	})
 
@@ -295,7 +340,7 @@ func TestDiskMonitor_findGroupWithOldestSegment(t *testing.T) {
 		endTime := time.Now().Add(-time.Hour)
 		service.SetSegmentTime("group1", endTime, true)
 
-		result := dm.findGroupWithOldestSegment(groups)
+		result := dm.findGroupWithOldestSegment(groups, "")
 		assert.Equal(t, "group1", result)
 	})
 
@@ -310,7 +355,7 @@ func TestDiskMonitor_findGroupWithOldestSegment(t *testing.T) {
 		service.SetSegmentTime("group2", now.Add(-2*time.Hour), true)    // 2 hours ago (oldest)
 		service.SetSegmentTime("group3", now.Add(-30*time.Minute), true) // 30 minutes ago
 
-		result := dm.findGroupWithOldestSegment(groups)
+		result := dm.findGroupWithOldestSegment(groups, "")
 		assert.Equal(t, "group2", result)
 	})
 
@@ -325,7 +370,7 @@ func TestDiskMonitor_findGroupWithOldestSegment(t *testing.T) {
 		service.SetSegmentTime("group2", now.Add(-time.Hour), true) // Has segments
 		service.SetSegmentTime("group3", time.Time{}, false)        // No segments
 
-		result := dm.findGroupWithOldestSegment(groups)
+		result := dm.findGroupWithOldestSegment(groups, "")
 		assert.Equal(t, "group2", result)
 	})
 }
@@ -346,7 +391,7 @@ func TestDiskMonitor_deleteOldestSegment(t *testing.T) {
 	t.Run("no groups available", func(t *testing.T) {
 		service.SetGroups([]resourceSchema.Group{})
 
-		result := dm.deleteOldestSegment("test-service")
+		result := dm.deleteOldestSegment("test-service", "")
 		assert.False(t, result)
 	})
 
@@ -358,7 +403,7 @@ func TestDiskMonitor_deleteOldestSegment(t *testing.T) {
 		service.SetSegmentTime("group1", endTime, true)
 		service.SetDeleteResponse("group1", true, nil)
 
-		result := dm.deleteOldestSegment("test-service")
+		result := dm.deleteOldestSegment("test-service", "")
 		assert.True(t, result)
 	})
 
@@ -370,7 +415,7 @@ func TestDiskMonitor_deleteOldestSegment(t *testing.T) {
 		service.SetSegmentTime("group1", endTime, true)
 		service.SetDeleteResponse("group1", false, assert.AnError)
 
-		result := dm.deleteOldestSegment("test-service")
+		result := dm.deleteOldestSegment("test-service", "")
 		assert.False(t, result)
 	})
 
@@ -382,11 +427,45 @@ func TestDiskMonitor_deleteOldestSegment(t *testing.T) {
 		service.SetSegmentTime("group1", endTime, true)
 		service.SetDeleteResponse("group1", false, nil) // No error, but nothing deleted
 
-		result := dm.deleteOldestSegment("test-service")
+		result := dm.deleteOldestSegment("test-service", "")
 		assert.False(t, result)
 	})
 }
 
+func TestDiskMonitor_deleteOldestSegmentOnVolume(t *testing.T) {
+	service := NewMockRetentionService()
+	dm := NewDiskMonitor(service, RetentionConfig{Cooldown: time.Second}, createMockMetricsRegistry())
+
+	group1 := createMockGroup("group1")
+	group2 := createMockGroup("group2")
+	service.SetGroups([]resourceSchema.Group{group1, group2})
+	service.SetSegmentTime("group1", time.Now().Add(-2*time.Hour), true)
+	service.SetSegmentTime("group2", time.Now().Add(-time.Hour), true)
+	service.SetVolume("group1", "/data1")
+	service.SetVolume("group2", "/data2")
+	service.SetDeleteResponse("group1", true, nil)
+	service.SetDeleteResponse("group2", true, nil)
+
+	assert.Equal(t, "group1", dm.findGroupWithOldestSegment(service.LoadAllGroups(), ""))
+	assert.Equal(t, "group2", dm.findGroupWithOldestSegment(service.LoadAllGroups(), "/data2"),
+		"only the segments on the full volume are candidates")
+	assert.Equal(t, "", dm.findGroupWithOldestSegment(service.LoadAllGroups(), "/data3"))
+	assert.True(t, dm.deleteOldestSegment("test-service", "/data2"))
+	assert.False(t, dm.deleteOldestSegment("test-service", "/data3"))
+}
+
+func TestDiskMonitor_deactivateVolume(t *testing.T) {
+	dm := NewDiskMonitor(NewMockRetentionService(), RetentionConfig{Cooldown: time.Second}, createMockMetricsRegistry())
+	dm.activeVolumes["/data1"] = struct{}{}
+	dm.activeVolumes["/data2"] = struct{}{}
+	dm.isActive.Store(true)
+
+	dm.deactivate("test-service", "/data1")
+	assert.True(t, dm.isActive.Load(), "the cleanup of /data2 is still running")
+	dm.deactivate("test-service", "/data2")
+	assert.False(t, dm.isActive.Load())
+}
+
 func TestDiskMonitor_cleanupSnapshots(t *testing.T) {
 	service := NewMockRetentionService()
 	config := RetentionConfig{
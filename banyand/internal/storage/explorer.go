@@ -0,0 +1,297 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+)
+
+// SearchAllSeries returns all the series of the subject in the series index.
+func SearchAllSeries(ctx context.Context, db IndexDB, subject string, entitySize int) (pbv1.SeriesList, error) {
+	entityValues := make([]*modelv1.TagValue, entitySize)
+	for i := range entityValues {
+		entityValues[i] = pbv1.AnyTagValue
+	}
+	sd, _, err := db.Search(ctx, []*pbv1.Series{{Subject: subject, EntityValues: entityValues}}, IndexSearchOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return sd.SeriesList, nil
+}
+
+// ListSeries lists the series of the subject in the segments ordered by their encoded entity values.
+// It returns at most limit series whose encoded entity values are greater than after,
+// and whether there are more series.
+func ListSeries[T TSTable, O any](ctx context.Context, segments []Segment[T, O], subject string, entitySize int,
+	after []byte, limit int,
+) (pbv1.SeriesList, bool, error) {
+	seen := make(map[string]*pbv1.Series)
+	for _, segment := range segments {
+		seriesList, err := SearchAllSeries(ctx, segment.IndexDB(), subject, entitySize)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, s := range seriesList {
+			s.Buffer = nil
+			if err = s.Marshal(); err != nil {
+				return nil, false, errors.WithMessagef(err, "failed to marshal series %s", s.Subject)
+			}
+			if bytes.Compare(s.Buffer, after) <= 0 {
+				continue
+			}
+			seen[string(s.Buffer)] = s
+		}
+	}
+	result := make(pbv1.SeriesList, 0, len(seen))
+	for _, s := range seen {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return bytes.Compare(result[i].Buffer, result[j].Buffer) < 0 })
+	if limit > 0 && len(result) > limit {
+		return result[:limit], true, nil
+	}
+	return result, false, nil
+}
+
+// SeriesCardinality breaks down the series of the subject in every segment by the entity tags.
+// entity is the entity tag names, and tags are the ones to break down.
+func SeriesCardinality[T TSTable, O any](ctx context.Context, segments []Segment[T, O], subject string,
+	entity []string, tags []string, topN int,
+) ([]*databasev1.SeriesCardinalityBucket, error) {
+	positions := make([]int, len(tags))
+	for i, t := range tags {
+		positions[i] = -1
+		for j, e := range entity {
+			if e == t {
+				positions[i] = j
+				break
+			}
+		}
+		if positions[i] < 0 {
+			return nil, errors.Errorf("tag %s is not an entity tag", t)
+		}
+	}
+	buckets := make([]*databasev1.SeriesCardinalityBucket, 0, len(segments))
+	for _, segment := range segments {
+		seriesList, err := SearchAllSeries(ctx, segment.IndexDB(), subject, len(entity))
+		if err != nil {
+			return nil, err
+		}
+		sketch := hyperloglog.New()
+		valueCounts := make([]map[string]int64, len(tags))
+		valueSeries := make([]map[string]*hyperloglog.Sketch, len(tags))
+		valueSketches := make([]*hyperloglog.Sketch, len(tags))
+		for i := range tags {
+			valueCounts[i] = make(map[string]int64)
+			valueSeries[i] = make(map[string]*hyperloglog.Sketch)
+			valueSketches[i] = hyperloglog.New()
+		}
+		for _, s := range seriesList {
+			sketch.InsertHash(uint64(s.ID))
+			for i, pos := range positions {
+				if pos >= len(s.EntityValues) {
+					continue
+				}
+				v, ok := TagValueString(s.EntityValues[pos])
+				if !ok {
+					continue
+				}
+				valueCounts[i][v]++
+				vs, ok := valueSeries[i][v]
+				if !ok {
+					vs = hyperloglog.New()
+					valueSeries[i][v] = vs
+				}
+				vs.InsertHash(uint64(s.ID))
+				valueSketches[i].Insert([]byte(v))
+			}
+		}
+		timeRange := segment.GetTimeRange()
+		bucket := &databasev1.SeriesCardinalityBucket{
+			Begin:       timestamppb.New(timeRange.Start),
+			End:         timestamppb.New(timeRange.End),
+			SeriesCount: int64(len(seriesList)),
+		}
+		if bucket.Sketch, err = sketch.MarshalBinary(); err != nil {
+			return nil, err
+		}
+		for i, t := range tags {
+			tc := &databasev1.TagCardinality{
+				Tag:            t,
+				DistinctValues: int64(len(valueCounts[i])),
+				TopValues:      topValueCardinalities(valueCounts[i], topN),
+			}
+			if tc.Sketch, err = valueSketches[i].MarshalBinary(); err != nil {
+				return nil, err
+			}
+			for _, tv := range tc.TopValues {
+				if tv.Sketch, err = valueSeries[i][tv.Value].MarshalBinary(); err != nil {
+					return nil, err
+				}
+			}
+			bucket.Tags = append(bucket.Tags, tc)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+// MergeSeriesCardinality merges the buckets from the data nodes.
+// The series, the distinct values and the series of every top value in the buckets of the same segment
+// are estimated by merging their sketches, so the series stored by the replicas are counted once.
+// A top value without a sketch keeps the largest count reported by the nodes.
+// The sketches are cleared in the merged buckets.
+func MergeSeriesCardinality(bucketLists [][]*databasev1.SeriesCardinalityBucket, topN int) ([]*databasev1.SeriesCardinalityBucket, error) {
+	type mergedTag struct {
+		sketch         *hyperloglog.Sketch
+		valueCounts    map[string]int64
+		valueSketches  map[string]*hyperloglog.Sketch
+		distinctValues int64
+	}
+	type mergedBucket struct {
+		bucket      *databasev1.SeriesCardinalityBucket
+		sketch      *hyperloglog.Sketch
+		tags        map[string]*mergedTag
+		tagNames    []string
+		seriesCount int64
+		merges      int
+	}
+	merged := make(map[int64]*mergedBucket)
+	for _, bl := range bucketLists {
+		for _, b := range bl {
+			key := b.GetBegin().AsTime().UnixNano()
+			mb, ok := merged[key]
+			if !ok {
+				mb = &mergedBucket{
+					bucket: &databasev1.SeriesCardinalityBucket{Begin: b.GetBegin(), End: b.GetEnd()},
+					sketch: hyperloglog.New(),
+					tags:   make(map[string]*mergedTag),
+				}
+				merged[key] = mb
+			}
+			mb.merges++
+			mb.seriesCount = b.GetSeriesCount()
+			if err := mergeSketch(mb.sketch, b.GetSketch()); err != nil {
+				return nil, err
+			}
+			for _, tc := range b.GetTags() {
+				mt, ok := mb.tags[tc.GetTag()]
+				if !ok {
+					mt = &mergedTag{
+						sketch:        hyperloglog.New(),
+						valueCounts:   make(map[string]int64),
+						valueSketches: make(map[string]*hyperloglog.Sketch),
+					}
+					mb.tags[tc.GetTag()] = mt
+					mb.tagNames = append(mb.tagNames, tc.GetTag())
+				}
+				mt.distinctValues = tc.GetDistinctValues()
+				if err := mergeSketch(mt.sketch, tc.GetSketch()); err != nil {
+					return nil, err
+				}
+				for _, tv := range tc.GetTopValues() {
+					v := tv.GetValue()
+					if tv.GetSeriesCount() > mt.valueCounts[v] {
+						mt.valueCounts[v] = tv.GetSeriesCount()
+					}
+					if len(tv.GetSketch()) == 0 {
+						continue
+					}
+					vs, ok := mt.valueSketches[v]
+					if !ok {
+						vs = hyperloglog.New()
+						mt.valueSketches[v] = vs
+					}
+					if err := mergeSketch(vs, tv.GetSketch()); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	result := make([]*databasev1.SeriesCardinalityBucket, 0, len(merged))
+	for _, mb := range merged {
+		// A bucket from a single node keeps its exact counts.
+		mb.bucket.SeriesCount = mb.seriesCount
+		if mb.merges > 1 {
+			mb.bucket.SeriesCount = int64(mb.sketch.Estimate())
+		}
+		for _, t := range mb.tagNames {
+			mt := mb.tags[t]
+			if mb.merges > 1 {
+				for v, vs := range mt.valueSketches {
+					if estimate := int64(vs.Estimate()); estimate > mt.valueCounts[v] {
+						mt.valueCounts[v] = estimate
+					}
+				}
+			}
+			tc := &databasev1.TagCardinality{
+				Tag:            t,
+				DistinctValues: mt.distinctValues,
+				TopValues:      topValueCardinalities(mt.valueCounts, topN),
+			}
+			if mb.merges > 1 {
+				tc.DistinctValues = int64(mt.sketch.Estimate())
+			}
+			mb.bucket.Tags = append(mb.bucket.Tags, tc)
+		}
+		result = append(result, mb.bucket)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetBegin().AsTime().Before(result[j].GetBegin().AsTime())
+	})
+	return result, nil
+}
+
+func mergeSketch(dst *hyperloglog.Sketch, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	src := hyperloglog.New()
+	if err := src.UnmarshalBinary(data); err != nil {
+		return errors.WithMessage(err, "failed to unmarshal the sketch")
+	}
+	return dst.Merge(src)
+}
+
+func topValueCardinalities(valueCounts map[string]int64, topN int) []*databasev1.TagValueCardinality {
+	result := make([]*databasev1.TagValueCardinality, 0, len(valueCounts))
+	for v, c := range valueCounts {
+		result = append(result, &databasev1.TagValueCardinality{Value: v, SeriesCount: c})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].SeriesCount != result[j].SeriesCount {
+			return result[i].SeriesCount > result[j].SeriesCount
+		}
+		return result[i].Value < result[j].Value
+	})
+	if topN > 0 && len(result) > topN {
+		result = result[:topN]
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
+)
+
+func TestMergeSeriesCardinality(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	marshal := func(ids []uint64) []byte {
+		sketch := hyperloglog.New()
+		for _, id := range ids {
+			sketch.InsertHash(convert.Hash(convert.Uint64ToBytes(id)))
+		}
+		data, err := sketch.MarshalBinary()
+		require.NoError(t, err)
+		return data
+	}
+	// values maps every value of the tag to the series with the value.
+	bucket := func(begin time.Time, values map[string][]uint64) *databasev1.SeriesCardinalityBucket {
+		var ids []uint64
+		valueSketch := hyperloglog.New()
+		tc := &databasev1.TagCardinality{Tag: "svc", DistinctValues: int64(len(values))}
+		for v, vIDs := range values {
+			ids = append(ids, vIDs...)
+			valueSketch.Insert([]byte(v))
+			tc.TopValues = append(tc.TopValues, &databasev1.TagValueCardinality{
+				Value:       v,
+				SeriesCount: int64(len(vIDs)),
+				Sketch:      marshal(vIDs),
+			})
+		}
+		var err error
+		tc.Sketch, err = valueSketch.MarshalBinary()
+		require.NoError(t, err)
+		return &databasev1.SeriesCardinalityBucket{
+			Begin:       timestamppb.New(begin),
+			End:         timestamppb.New(begin.Add(24 * time.Hour)),
+			SeriesCount: int64(len(ids)),
+			Tags:        []*databasev1.TagCardinality{tc},
+			Sketch:      marshal(ids),
+		}
+	}
+	// The first day is stored by two replicas, the second day only by the second node,
+	// and the third day is split into two shards stored by different nodes.
+	node1 := []*databasev1.SeriesCardinalityBucket{
+		bucket(day, map[string][]uint64{"a": {1, 2}, "b": {3}}),
+		bucket(day.Add(48*time.Hour), map[string][]uint64{"d": {5, 6}}),
+	}
+	node2 := []*databasev1.SeriesCardinalityBucket{
+		bucket(day.Add(24*time.Hour), map[string][]uint64{"c": {4}}),
+		bucket(day, map[string][]uint64{"a": {1, 2}, "b": {3}}),
+		bucket(day.Add(48*time.Hour), map[string][]uint64{"d": {7}}),
+	}
+	result, err := MergeSeriesCardinality([][]*databasev1.SeriesCardinalityBucket{node1, node2}, 1)
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+
+	assert.True(t, result[0].GetBegin().AsTime().Equal(day))
+	assert.Equal(t, int64(3), result[0].GetSeriesCount())
+	assert.Empty(t, result[0].GetSketch())
+	require.Len(t, result[0].GetTags(), 1)
+	assert.Equal(t, int64(2), result[0].GetTags()[0].GetDistinctValues())
+	assert.Empty(t, result[0].GetTags()[0].GetSketch())
+	require.Len(t, result[0].GetTags()[0].GetTopValues(), 1)
+	assert.Equal(t, "a", result[0].GetTags()[0].GetTopValues()[0].GetValue())
+	assert.Equal(t, int64(2), result[0].GetTags()[0].GetTopValues()[0].GetSeriesCount())
+	assert.Empty(t, result[0].GetTags()[0].GetTopValues()[0].GetSketch())
+
+	assert.Equal(t, int64(1), result[1].GetSeriesCount())
+	assert.Equal(t, "c", result[1].GetTags()[0].GetTopValues()[0].GetValue())
+
+	assert.Equal(t, int64(3), result[2].GetSeriesCount())
+	assert.Equal(t, "d", result[2].GetTags()[0].GetTopValues()[0].GetValue())
+	assert.Equal(t, int64(3), result[2].GetTags()[0].GetTopValues()[0].GetSeriesCount())
+
+	// Merging the merged buckets keeps the counts.
+	again, err := MergeSeriesCardinality([][]*databasev1.SeriesCardinalityBucket{result}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), again[0].GetSeriesCount())
+	assert.Equal(t, int64(2), again[0].GetTags()[0].GetDistinctValues())
+	assert.Equal(t, int64(2), again[0].GetTags()[0].GetTopValues()[0].GetSeriesCount())
+}
+
+func TestTopValueCardinalities(t *testing.T) {
+	result := topValueCardinalities(map[string]int64{"b": 2, "a": 2, "c": 5, "d": 1}, 3)
+	require.Len(t, result, 3)
+	assert.Equal(t, "c", result[0].GetValue())
+	assert.Equal(t, "a", result[1].GetValue())
+	assert.Equal(t, "b", result[2].GetValue())
+	assert.Len(t, topValueCardinalities(map[string]int64{"a": 1, "b": 1}, 0), 2)
+}
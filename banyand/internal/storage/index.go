@@ -18,14 +18,17 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"maps"
 	"path"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
 	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/index/inverted"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
@@ -45,6 +48,25 @@ func (s *segment[T, O]) Lookup(ctx context.Context, series []*pbv1.Series) (pbv1
 	return sl.SeriesList, err
 }
 
+// AdmitSeries checks whether a series could be inserted into the series index under the series limits.
+// It returns ErrSeriesLimitExceeded if the series is new and exceeds the limits.
+// The series index is never searched on the write path, the known series are loaded in the background instead.
+func (s *segment[T, O]) AdmitSeries(series *pbv1.Series, entitySize int, limits SeriesLimits) error {
+	return s.limiter.admit(series, entitySize, limits, time.Now(), s.loadSeries)
+}
+
+func (s *segment[T, O]) loadSeries(ctx context.Context, subject string, entitySize int) (pbv1.SeriesList, error) {
+	if err := s.incRef(ctx); err != nil {
+		return nil, err
+	}
+	defer s.DecRef()
+	sl, err := SearchAllSeries(ctx, s.index, subject, entitySize)
+	if err != nil {
+		s.l.Warn().Err(err).Str("subject", subject).Msg("failed to load the series under the series limits, retry on the next write")
+	}
+	return sl, err
+}
+
 type seriesIndex struct {
 	store   index.SeriesStore
 	l       *logger.Logger
@@ -98,6 +120,37 @@ func (s *seriesIndex) Stats() (dataCount int64, dataSizeBytes int64) {
 	return s.store.Stats()
 }
 
+func (s *seriesIndex) FieldSize(fieldKey index.FieldKey) int64 {
+	return s.store.FieldSize(fieldKey)
+}
+
+func (s *seriesIndex) Terms(fieldKey index.FieldKey, prefix []byte) ([][]byte, error) {
+	return s.store.Terms(fieldKey, prefix)
+}
+
+func (s *seriesIndex) SeriesDocuments(ctx context.Context, seriesIDs map[common.SeriesID]struct{}) (index.Documents, error) {
+	if len(seriesIDs) == 0 {
+		return nil, nil
+	}
+	iter, err := s.store.SeriesIterator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var docs index.Documents
+	for iter.Next() && len(docs) < len(seriesIDs) {
+		entityValues := iter.Val().EntityValues
+		id := common.SeriesID(convert.Hash(entityValues))
+		if _, ok := seriesIDs[id]; !ok {
+			continue
+		}
+		docs = append(docs, index.Document{
+			DocID:        uint64(id),
+			EntityValues: bytes.Clone(entityValues),
+		})
+	}
+	return docs, iter.Close()
+}
+
 func (s *seriesIndex) filter(ctx context.Context, series []*pbv1.Series,
 	projection []index.FieldKey, secondaryQuery index.Query, timeRange *timestamp.TimeRange,
 ) (data SeriesData, err error) {
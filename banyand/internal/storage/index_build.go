@@ -0,0 +1,320 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const indexBuildFilename = "index-build.json"
+
+// IndexBuildState records the index rules of every resource which are built on all the data of a shard.
+// The rules bound after the data of a shard was written stay missing until they are built.
+// The catalogs which cannot build index rules on the existing data mark the missing rules unsupported.
+type IndexBuildState struct {
+	fileSystem  fs.FileSystem
+	l           *logger.Logger
+	built       map[string]map[uint32]struct{}
+	building    map[string]*IndexBuildProgress
+	unsupported map[string][]string
+	path        string
+	mu          sync.RWMutex
+}
+
+// IndexBuildProgress is the progress of building the missing index rules of a resource in a shard.
+type IndexBuildProgress struct {
+	err        atomic.Value
+	rules      []string
+	TotalParts atomic.Int64
+	BuiltParts atomic.Int64
+}
+
+// SetError records the last error of the build, which is retried later.
+func (p *IndexBuildProgress) SetError(err error) {
+	p.err.Store(err.Error())
+}
+
+// LoadIndexBuildState loads the index build state persisted in the root of a shard.
+func LoadIndexBuildState(fileSystem fs.FileSystem, root string, l *logger.Logger) *IndexBuildState {
+	st := &IndexBuildState{
+		fileSystem:  fileSystem,
+		l:           l,
+		path:        filepath.Join(root, indexBuildFilename),
+		built:       make(map[string]map[uint32]struct{}),
+		building:    make(map[string]*IndexBuildProgress),
+		unsupported: make(map[string][]string),
+	}
+	data, err := fileSystem.Read(st.path)
+	if err != nil {
+		return st
+	}
+	var persisted map[string][]uint32
+	if err = json.Unmarshal(data, &persisted); err != nil {
+		l.Warn().Err(err).Str("path", st.path).Msg("cannot parse the index build state, the index rules are rebuilt")
+		return st
+	}
+	for resource, ids := range persisted {
+		built := make(map[uint32]struct{}, len(ids))
+		for _, id := range ids {
+			built[id] = struct{}{}
+		}
+		st.built[resource] = built
+	}
+	return st
+}
+
+// Sync drops the removed rules of the resource and returns the rules to build.
+// A resource unknown to the shard adopts its rules except the added ones,
+// because the data written before were indexed by them.
+func (st *IndexBuildState) Sync(resource string, current, added map[uint32]struct{}) []uint32 {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	built, ok := st.built[resource]
+	changed := !ok
+	if !ok {
+		built = make(map[uint32]struct{}, len(current))
+		for id := range current {
+			if _, isAdded := added[id]; !isAdded {
+				built[id] = struct{}{}
+			}
+		}
+		st.built[resource] = built
+	}
+	for id := range built {
+		if _, ok := current[id]; !ok {
+			delete(built, id)
+			changed = true
+		}
+	}
+	if changed {
+		st.persistLocked()
+	}
+	return missingRules(current, built)
+}
+
+// Missing returns the rules of the resource which are not built yet.
+func (st *IndexBuildState) Missing(resource string, current map[uint32]struct{}) []uint32 {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	built, ok := st.built[resource]
+	if !ok {
+		return nil
+	}
+	return missingRules(current, built)
+}
+
+// StartBuilding returns the progress of building the missing rules of the resource.
+func (st *IndexBuildState) StartBuilding(resource string, rules []string) *IndexBuildProgress {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if p, ok := st.building[resource]; ok {
+		return p
+	}
+	p := &IndexBuildProgress{rules: rules}
+	st.building[resource] = p
+	return p
+}
+
+// MarkBuilt records the rules of the resource as built on all the data of the shard.
+func (st *IndexBuildState) MarkBuilt(resource string, ids []uint32) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.building, resource)
+	built, ok := st.built[resource]
+	if !ok {
+		built = make(map[uint32]struct{}, len(ids))
+		st.built[resource] = built
+	}
+	for _, id := range ids {
+		built[id] = struct{}{}
+	}
+	st.persistLocked()
+}
+
+// MarkUnsupported reports the missing rules of the resource which are never built on the existing data.
+func (st *IndexBuildState) MarkUnsupported(resource string, rules []string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if len(rules) == 0 {
+		delete(st.unsupported, resource)
+		return
+	}
+	st.unsupported[resource] = rules
+}
+
+func (st *IndexBuildState) persistLocked() {
+	persisted := make(map[string][]uint32, len(st.built))
+	for resource, built := range st.built {
+		ids := make([]uint32, 0, len(built))
+		for id := range built {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		persisted[resource] = ids
+	}
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		st.l.Error().Err(err).Msg("cannot marshal the index build state")
+		return
+	}
+	if _, err = st.fileSystem.Write(data, st.path, FilePerm); err != nil {
+		st.l.Error().Err(err).Str("path", st.path).Msg("cannot write the index build state")
+	}
+}
+
+// Info returns the rules being built and the unsupported ones of every resource in the shard.
+// The write queues of liaisons have no state, which report nothing.
+func (st *IndexBuildState) Info() []*databasev1.IndexBuildInfo {
+	if st == nil {
+		return nil
+	}
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	result := make([]*databasev1.IndexBuildInfo, 0, len(st.building)+len(st.unsupported))
+	for resource, p := range st.building {
+		info := &databasev1.IndexBuildInfo{
+			Resource:   resource,
+			IndexRules: p.rules,
+			TotalParts: p.TotalParts.Load(),
+			BuiltParts: p.BuiltParts.Load(),
+			State:      databasev1.IndexBuildInfo_STATE_BUILDING,
+		}
+		if err, ok := p.err.Load().(string); ok {
+			info.Error = err
+		}
+		result = append(result, info)
+	}
+	for resource, rules := range st.unsupported {
+		result = append(result, &databasev1.IndexBuildInfo{
+			Resource:   resource,
+			IndexRules: rules,
+			State:      databasev1.IndexBuildInfo_STATE_UNSUPPORTED,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Resource < result[j].Resource })
+	return result
+}
+
+func missingRules(current, built map[uint32]struct{}) []uint32 {
+	var result []uint32
+	for id := range current {
+		if _, ok := built[id]; !ok {
+			result = append(result, id)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// IndexRuleIDs returns the IDs of the rules accepted by the filter. A nil filter accepts all the rules.
+func IndexRuleIDs(rules []*databasev1.IndexRule, filter func(*databasev1.IndexRule) bool) map[uint32]struct{} {
+	result := make(map[uint32]struct{}, len(rules))
+	for _, r := range rules {
+		if filter == nil || filter(r) {
+			result[r.GetMetadata().GetId()] = struct{}{}
+		}
+	}
+	return result
+}
+
+// IndexRuleNames returns the names of the rules with the IDs.
+func IndexRuleNames(rules []*databasev1.IndexRule, ids []uint32) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		for _, r := range rules {
+			if r.GetMetadata().GetId() == id {
+				result = append(result, r.GetMetadata().GetName())
+				break
+			}
+		}
+	}
+	return result
+}
+
+// IndexRuleTracker remembers the index rules of the resources to find the ones bound since the last update.
+type IndexRuleTracker struct {
+	last map[string]map[uint32]struct{}
+	mu   sync.Mutex
+}
+
+// NewIndexRuleTracker returns a new IndexRuleTracker.
+func NewIndexRuleTracker() *IndexRuleTracker {
+	return &IndexRuleTracker{last: make(map[string]map[uint32]struct{})}
+}
+
+// Added records the current rules of the resource and returns the ones absent from its last update.
+// Nothing is added on the first update of a resource, since its rules are loaded rather than bound.
+func (t *IndexRuleTracker) Added(resource string, current map[uint32]struct{}) map[uint32]struct{} {
+	t.mu.Lock()
+	prev, seen := t.last[resource]
+	t.last[resource] = current
+	t.mu.Unlock()
+	added := make(map[uint32]struct{})
+	if !seen {
+		return added
+	}
+	for id := range current {
+		if _, ok := prev[id]; !ok {
+			added[id] = struct{}{}
+		}
+	}
+	return added
+}
+
+// FlagUnbuiltIndexRules marks the rules bound to the resource after the data of a shard was written as unsupported.
+// It is used by the catalogs which cannot build index rules on the existing data,
+// so the group inspection reports the segments in which the conditions on the rules miss the data written before.
+// It returns the IDs of the unsupported rules of all the shards.
+func FlagUnbuiltIndexRules[T TSTable, O any](db TSDB[T, O], resource string, rules []*databasev1.IndexRule,
+	current, added map[uint32]struct{}, stateOf func(T) *IndexBuildState,
+) ([]uint32, error) {
+	segments, err := db.SelectSegments(timestamp.TimeRange{
+		Start: time.Unix(0, 0),
+		End:   time.Unix(0, timestamp.MaxNanoTime),
+	})
+	if err != nil {
+		return nil, err
+	}
+	unsupported := make(map[uint32]struct{})
+	for _, segment := range segments {
+		tables, _ := segment.Tables()
+		for _, tst := range tables {
+			st := stateOf(tst)
+			if st == nil {
+				continue
+			}
+			missing := st.Sync(resource, current, added)
+			st.MarkUnsupported(resource, IndexRuleNames(rules, missing))
+			for _, id := range missing {
+				unsupported[id] = struct{}{}
+			}
+		}
+		segment.DecRef()
+	}
+	return missingRules(unsupported, nil), nil
+}
@@ -0,0 +1,82 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	"github.com/apache/skywalking-banyandb/pkg/test"
+)
+
+func TestIndexBuildState(t *testing.T) {
+	tmpPath, defFn := test.Space(require.New(t))
+	defer defFn()
+	fileSystem := fs.NewLocalFileSystem()
+	l := logger.GetLogger("test")
+	rules := func(ids ...uint32) map[uint32]struct{} {
+		m := make(map[uint32]struct{}, len(ids))
+		for _, id := range ids {
+			m[id] = struct{}{}
+		}
+		return m
+	}
+
+	st := LoadIndexBuildState(fileSystem, tmpPath, l)
+	require.Nil(t, st.Missing("sw", rules(1, 2)))
+	// A resource unknown to the shard adopts its rules except the added ones.
+	require.Equal(t, []uint32{2}, st.Sync("sw", rules(1, 2), rules(2)))
+	require.Equal(t, []uint32{2}, st.Missing("sw", rules(1, 2)))
+
+	st.StartBuilding("sw", []string{"rule-2"})
+	info := st.Info()
+	require.Len(t, info, 1)
+	require.Equal(t, "sw", info[0].GetResource())
+	require.Equal(t, []string{"rule-2"}, info[0].GetIndexRules())
+	require.Equal(t, databasev1.IndexBuildInfo_STATE_BUILDING, info[0].GetState())
+
+	st.MarkBuilt("sw", []uint32{2})
+	require.Empty(t, st.Missing("sw", rules(1, 2)))
+	require.Empty(t, st.Info())
+
+	// The state survives restarts, and the removed rules are dropped.
+	st = LoadIndexBuildState(fileSystem, tmpPath, l)
+	require.Empty(t, st.Missing("sw", rules(1, 2)))
+	require.Empty(t, st.Sync("sw", rules(1), nil))
+	require.Equal(t, []uint32{3}, st.Sync("sw", rules(1, 3), rules(3)))
+
+	// The rules never built on the existing data are reported until they are removed.
+	st.MarkUnsupported("sw", []string{"rule-3"})
+	info = st.Info()
+	require.Len(t, info, 1)
+	require.Equal(t, []string{"rule-3"}, info[0].GetIndexRules())
+	require.Equal(t, databasev1.IndexBuildInfo_STATE_UNSUPPORTED, info[0].GetState())
+	st.MarkUnsupported("sw", nil)
+	require.Empty(t, st.Info())
+}
+
+func TestIndexRuleTracker(t *testing.T) {
+	tracker := NewIndexRuleTracker()
+	require.Empty(t, tracker.Added("sw", map[uint32]struct{}{1: {}}))
+	require.Equal(t, map[uint32]struct{}{2: {}}, tracker.Added("sw", map[uint32]struct{}{1: {}, 2: {}}))
+	require.Empty(t, tracker.Added("sw", map[uint32]struct{}{2: {}}))
+}
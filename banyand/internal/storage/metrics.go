@@ -18,6 +18,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/apache/skywalking-banyandb/banyand/observability"
 	obsservice "github.com/apache/skywalking-banyandb/banyand/observability/services"
 	"github.com/apache/skywalking-banyandb/pkg/meter"
@@ -37,6 +39,25 @@ type metrics struct {
 	totalRetentionErr            meter.Counter
 	totalRetentionHasDataLatency meter.Counter
 
+	totalOffloadedBytes meter.Counter
+	totalOffloadErr     meter.Counter
+
+	totalScrubbedParts  meter.Counter
+	totalScrubbedBytes  meter.Counter
+	totalCorruptedParts meter.Counter
+	lastScrubTime       meter.Gauge
+
+	totalRepairStarted  meter.Counter
+	totalRepairFinished meter.Counter
+	totalRepairErr      meter.Counter
+
+	totalAntiEntropyStarted           meter.Counter
+	totalAntiEntropyFinished          meter.Counter
+	totalAntiEntropyErr               meter.Counter
+	totalAntiEntropyLatency           meter.Counter
+	totalAntiEntropyMismatchedBuckets meter.Counter
+	totalAntiEntropyPulledRows        meter.Counter
+
 	schedulerMetrics *obsservice.SchedulerMetrics
 }
 
@@ -45,17 +66,32 @@ func newMetrics(factory observability.Factory) *metrics {
 		return nil
 	}
 	return &metrics{
-		lastTickTime:                 factory.NewGauge("last_tick_time"),
-		totalSegRefs:                 factory.NewGauge("total_segment_refs"),
-		totalRotationStarted:         factory.NewCounter("total_rotation_started"),
-		totalRotationFinished:        factory.NewCounter("total_rotation_finished"),
-		totalRotationErr:             factory.NewCounter("total_rotation_err"),
-		totalRetentionStarted:        factory.NewCounter("total_retention_started"),
-		totalRetentionFinished:       factory.NewCounter("total_retention_finished"),
-		totalRetentionErr:            factory.NewCounter("total_retention_err"),
-		totalRetentionHasDataLatency: factory.NewCounter("total_retention_has_data_latency"),
-		totalRetentionHasData:        factory.NewCounter("total_retention_has_data"),
-		schedulerMetrics:             obsservice.NewSchedulerMetrics(factory),
+		lastTickTime:                      factory.NewGauge("last_tick_time"),
+		totalSegRefs:                      factory.NewGauge("total_segment_refs"),
+		totalRotationStarted:              factory.NewCounter("total_rotation_started"),
+		totalRotationFinished:             factory.NewCounter("total_rotation_finished"),
+		totalRotationErr:                  factory.NewCounter("total_rotation_err"),
+		totalRetentionStarted:             factory.NewCounter("total_retention_started"),
+		totalRetentionFinished:            factory.NewCounter("total_retention_finished"),
+		totalRetentionErr:                 factory.NewCounter("total_retention_err"),
+		totalRetentionHasDataLatency:      factory.NewCounter("total_retention_has_data_latency"),
+		totalRetentionHasData:             factory.NewCounter("total_retention_has_data"),
+		totalOffloadedBytes:               factory.NewCounter("total_offloaded_bytes"),
+		totalOffloadErr:                   factory.NewCounter("total_offload_err"),
+		totalScrubbedParts:                factory.NewCounter("total_scrubbed_parts"),
+		totalScrubbedBytes:                factory.NewCounter("total_scrubbed_bytes"),
+		totalCorruptedParts:               factory.NewCounter("total_corrupted_parts"),
+		lastScrubTime:                     factory.NewGauge("last_scrub_time"),
+		totalRepairStarted:                factory.NewCounter("total_repair_started"),
+		totalRepairFinished:               factory.NewCounter("total_repair_finished"),
+		totalRepairErr:                    factory.NewCounter("total_repair_err"),
+		totalAntiEntropyStarted:           factory.NewCounter("total_anti_entropy_started"),
+		totalAntiEntropyFinished:          factory.NewCounter("total_anti_entropy_finished"),
+		totalAntiEntropyErr:               factory.NewCounter("total_anti_entropy_err"),
+		totalAntiEntropyLatency:           factory.NewCounter("total_anti_entropy_latency"),
+		totalAntiEntropyMismatchedBuckets: factory.NewCounter("total_anti_entropy_mismatched_buckets"),
+		totalAntiEntropyPulledRows:        factory.NewCounter("total_anti_entropy_pulled_rows"),
+		schedulerMetrics:                  obsservice.NewSchedulerMetrics(factory),
 	}
 }
 
@@ -114,3 +150,108 @@ func (d *database[T, O]) incTotalRetentionHasDataLatency(delta float64) {
 	}
 	d.metrics.totalRetentionHasDataLatency.Inc(delta)
 }
+
+func (d *database[T, O]) incTotalOffloadedBytes(delta float64) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalOffloadedBytes.Inc(delta)
+}
+
+func (d *database[T, O]) incTotalOffloadErr(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalOffloadErr.Inc(float64(delta))
+}
+
+func (d *database[T, O]) incTotalScrubbedParts(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalScrubbedParts.Inc(float64(delta))
+}
+
+func (d *database[T, O]) incTotalScrubbedBytes(delta float64) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalScrubbedBytes.Inc(delta)
+}
+
+func (d *database[T, O]) incTotalCorruptedParts(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalCorruptedParts.Inc(float64(delta))
+}
+
+func (d *database[T, O]) setLastScrubTime(t time.Time) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.lastScrubTime.Set(float64(t.Unix()))
+}
+
+func (d *database[T, O]) incTotalRepairStarted(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalRepairStarted.Inc(float64(delta))
+}
+
+func (d *database[T, O]) incTotalRepairFinished(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalRepairFinished.Inc(float64(delta))
+}
+
+func (d *database[T, O]) incTotalRepairErr(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalRepairErr.Inc(float64(delta))
+}
+
+func (d *database[T, O]) incTotalAntiEntropyStarted(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalAntiEntropyStarted.Inc(float64(delta))
+}
+
+func (d *database[T, O]) incTotalAntiEntropyFinished(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalAntiEntropyFinished.Inc(float64(delta))
+}
+
+func (d *database[T, O]) incTotalAntiEntropyErr(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalAntiEntropyErr.Inc(float64(delta))
+}
+
+func (d *database[T, O]) incTotalAntiEntropyLatency(delta float64) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalAntiEntropyLatency.Inc(delta)
+}
+
+func (d *database[T, O]) incTotalAntiEntropyMismatchedBuckets(delta int) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalAntiEntropyMismatchedBuckets.Inc(float64(delta))
+}
+
+func (d *database[T, O]) incTotalAntiEntropyPulledRows(delta float64) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.totalAntiEntropyPulledRows.Inc(delta)
+}
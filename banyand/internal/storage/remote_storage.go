@@ -0,0 +1,186 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/config"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/provider"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/tiered"
+	"github.com/apache/skywalking-banyandb/pkg/run"
+)
+
+const (
+	partMetadataFilename = "metadata.json"
+	offloadCheckInterval = 10 * time.Minute
+)
+
+var partDirPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+// RemoteStorageConfig holds the configuration of the object storage backing remote lifecycle stages.
+type RemoteStorageConfig struct {
+	// Dest is the URL of the object storage, e.g. s3:///bucket/path. Remote stages are disabled if it is empty.
+	Dest string
+	// ConfigFile is an optional JSON or YAML file with the credentials of the provider.
+	ConfigFile string
+	// CachePath is the directory of the local read-through cache.
+	CachePath string
+	// CacheSize is the capacity of the local read-through cache.
+	CacheSize run.Bytes
+	// OffloadDelay is how long a segment stays local after its end time.
+	OffloadDelay time.Duration
+}
+
+// RemoteStorage is the object storage shared by the remote-backed groups of a service.
+type RemoteStorage struct {
+	fs           remote.FS
+	cache        *tiered.Cache
+	root         string
+	offloadDelay time.Duration
+}
+
+// OpenRemoteStorage connects to the object storage configured by cfg.
+// Objects are keyed by their paths relative to root. It returns nil if no destination is configured.
+func OpenRemoteStorage(cfg RemoteStorageConfig, root string) (*RemoteStorage, error) {
+	if cfg.Dest == "" {
+		return nil, nil
+	}
+	fsCfg := &config.FsConfig{S3: &config.S3Config{}, Azure: &config.AzureConfig{}, GCP: &config.GCPConfig{}}
+	if cfg.ConfigFile != "" {
+		var err error
+		if fsCfg, err = config.Load(cfg.ConfigFile); err != nil {
+			return nil, errors.WithMessagef(err, "failed to load the remote storage config %s", cfg.ConfigFile)
+		}
+	}
+	rfs, err := provider.NewFS(cfg.Dest, fsCfg)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to open the remote storage %s", cfg.Dest)
+	}
+	cache, err := tiered.NewCache(cfg.CachePath, uint64(cfg.CacheSize))
+	if err != nil {
+		_ = rfs.Close()
+		return nil, err
+	}
+	return &RemoteStorage{
+		fs:           rfs,
+		cache:        cache,
+		root:         filepath.Clean(root),
+		offloadDelay: cfg.OffloadDelay,
+	}, nil
+}
+
+// Cache returns the local read-through cache.
+func (r *RemoteStorage) Cache() *tiered.Cache {
+	return r.cache
+}
+
+// Close releases the connection to the object storage.
+func (r *RemoteStorage) Close() error {
+	return r.fs.Close()
+}
+
+// offload moves the data files of the complete parts in segments, whose end time is older than
+// the offload delay, to the remote storage. Each part is offloaded under the retention gate,
+// so segment removal never observes a half-offloaded part.
+func (d *database[T, O]) offload(now time.Time) {
+	if d.remote == nil {
+		return
+	}
+	opts := d.segmentController.getOptions()
+	deadline := now.Add(-opts.RemoteStorage.offloadDelay)
+	d.segmentController.RLock()
+	var locations []string
+	for _, s := range d.segmentController.lst {
+		if !s.End.Before(deadline) {
+			continue
+		}
+		// Only the primary data volume is mirrored to the remote storage.
+		for _, location := range s.locations {
+			if onVolume(location, opts.RemoteStorage.root) {
+				locations = append(locations, location)
+			}
+		}
+	}
+	d.segmentController.RUnlock()
+	for _, location := range locations {
+		for _, partDir := range d.offloadCandidates(location) {
+			if d.closed.Load() {
+				return
+			}
+			select {
+			case d.retentionGate <- struct{}{}:
+			default:
+				d.logger.Debug().Msg("retention gate busy, postpone offloading")
+				return
+			}
+			if !d.lfs.IsExist(partDir) {
+				<-d.retentionGate
+				continue
+			}
+			n, err := d.remote.OffloadDir(context.Background(), partDir, opts.KeepLocal)
+			<-d.retentionGate
+			if err != nil {
+				d.logger.Error().Err(err).Str("part", partDir).Msg("failed to offload the part")
+				d.incTotalOffloadErr(1)
+				continue
+			}
+			d.incTotalOffloadedBytes(float64(n))
+			d.logger.Info().Str("part", partDir).Uint64("bytes", n).Msg("offloaded the part to the remote storage")
+		}
+	}
+}
+
+// offloadCandidates lists the parts of a segment which are not offloaded yet. The directories are read
+// without panicking because retention may remove the segment concurrently.
+func (d *database[T, O]) offloadCandidates(segLocation string) (dirs []string) {
+	shardEntries, err := os.ReadDir(segLocation)
+	if err != nil {
+		return nil
+	}
+	for _, shardEntry := range shardEntries {
+		if !shardEntry.IsDir() || !strings.HasPrefix(shardEntry.Name(), shardPathPrefix) {
+			continue
+		}
+		shardDir := filepath.Join(segLocation, shardEntry.Name())
+		partEntries, err := os.ReadDir(shardDir)
+		if err != nil {
+			continue
+		}
+		for _, partEntry := range partEntries {
+			if !partEntry.IsDir() || !partDirPattern.MatchString(partEntry.Name()) {
+				continue
+			}
+			partDir := filepath.Join(shardDir, partEntry.Name())
+			// Parts without the metadata file are still being flushed or merged.
+			if d.remote.IsOffloaded(partDir) || !d.lfs.IsExist(filepath.Join(partDir, partMetadataFilename)) {
+				continue
+			}
+			dirs = append(dirs, partDir)
+		}
+	}
+	return dirs
+}
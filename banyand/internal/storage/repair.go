@@ -0,0 +1,339 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	clusterv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/cluster/v1"
+	"github.com/apache/skywalking-banyandb/pkg/bus"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const (
+	// RepairTimeout is how long a replica is given to push the rows of a repair.
+	RepairTimeout      = time.Hour
+	repairPollInterval = time.Second
+	// repairSessionTTL is how long a replica keeps the result of a finished repair for the requesting node.
+	repairSessionTTL = 10 * time.Minute
+)
+
+// errNoReplica is returned if the replica doesn't hold the shard.
+var errNoReplica = errors.New("no replica holds the shard")
+
+// ReplicaClient sends the requests to the other data nodes holding the replicas of the shards.
+type ReplicaClient interface {
+	bus.Publisher
+	HealthyNodes() []string
+}
+
+// RepairPushResult is what a replica pushed to the data node repairing a shard.
+type RepairPushResult struct {
+	Bytes uint64
+	Rows  uint64
+	Parts uint32
+}
+
+// ReplicaShard is a local shard in a segment, which is compared with its replicas and repaired from them, and repairs them.
+type ReplicaShard interface {
+	// TimeRange returns the time range of the segment.
+	TimeRange() timestamp.TimeRange
+	// Digest returns the digest of the rows of the shard.
+	Digest() (*AntiEntropyDigest, error)
+	// PartTimeRange returns the time range of the rows of a flushed part, or false if the part isn't found.
+	PartTimeRange(partID uint64) (minTimestamp, maxTimestamp int64, ok bool)
+	// RowHashes returns the hashes of the rows in the buckets of the set, leaving out the rows of the excluded parts.
+	RowHashes(set *AntiEntropyBucketSet, excludedParts map[uint64]struct{}) ([]uint64, error)
+	// RemoveParts drops the parts from the shard.
+	RemoveParts(partIDs map[uint64]struct{}) error
+	// PushMissingRows pushes the rows in the requested buckets which the requesting node doesn't hold to it.
+	PushMissingRows(req *clusterv1.RepairShardRequest) (RepairPushResult, error)
+	// Release releases the shard.
+	Release()
+}
+
+// ReplicaCatalog loads the local shards of a catalog for the comparisons and the repairs.
+type ReplicaCatalog interface {
+	// LoadReplicaShard returns the shard of the group in the segment starting at segmentStart,
+	// or nil if the node doesn't hold it.
+	LoadReplicaShard(group string, shardID common.ShardID, segmentStart time.Time) (ReplicaShard, error)
+}
+
+// RepairPart replaces the rows of a corrupted part of the shard with the ones of a replica.
+// The first replica holding the shard pushes the rows in the time range of the part which the other parts of the shard don't hold,
+// then the part is removed. The other parts are kept, so the data written to the shard in the meantime isn't lost.
+// The part is kept if no replica pushes its rows.
+func RepairPart(client ReplicaClient, catalog ReplicaCatalog, topic bus.Topic, nodeID, group string, shardID common.ShardID,
+	timeRange timestamp.TimeRange, partID uint64,
+) error {
+	shard, err := catalog.LoadReplicaShard(group, shardID, timeRange.Start)
+	if err != nil {
+		return err
+	}
+	if shard == nil {
+		return fmt.Errorf("shard %d of the segment %s is not found", shardID, timeRange)
+	}
+	defer shard.Release()
+	minTS, maxTS, ok := shard.PartTimeRange(partID)
+	if !ok {
+		return fmt.Errorf("part %016x is not found in shard %d of the segment %s", partID, shardID, timeRange)
+	}
+	corrupted := map[uint64]struct{}{partID: {}}
+	req := &clusterv1.RepairShardRequest{
+		RepairId:     fmt.Sprintf("%s/%s/%d/%016x/%d", nodeID, group, shardID, partID, time.Now().UnixNano()),
+		Group:        group,
+		ShardId:      uint32(shardID),
+		SegmentStart: timeRange.Start.UnixNano(),
+		SegmentEnd:   timeRange.End.UnixNano(),
+		Node:         nodeID,
+	}
+	batches := repairBatches(timeRange, minTS, maxTS)
+	var errs []error
+	for _, node := range client.HealthyNodes() {
+		if node == nodeID {
+			continue
+		}
+		_, pullErr := pullRows(client, topic, node, req, batches, func(set *AntiEntropyBucketSet) ([]uint64, error) {
+			return shard.RowHashes(set, corrupted)
+		})
+		if errors.Is(pullErr, errNoReplica) {
+			continue
+		}
+		if pullErr != nil {
+			errs = append(errs, fmt.Errorf("replica %s: %w", node, pullErr))
+			continue
+		}
+		return shard.RemoveParts(corrupted)
+	}
+	return errors.Join(append([]error{errNoReplica}, errs...)...)
+}
+
+// repairBatches returns the buckets overlapping the time range between minTS and maxTS, batched by their time slices.
+func repairBatches(timeRange timestamp.TimeRange, minTS, maxTS int64) [][]uint32 {
+	minTS = max(minTS, timeRange.Start.UnixNano())
+	maxTS = min(maxTS, timeRange.End.UnixNano()-1)
+	if minTS > maxTS {
+		return nil
+	}
+	first := AntiEntropyBucket(timeRange, 0, minTS) / antiEntropySeriesSlots
+	last := AntiEntropyBucket(timeRange, 0, maxTS) / antiEntropySeriesSlots
+	batches := make([][]uint32, 0, last-first+1)
+	for slice := first; slice <= last; slice++ {
+		batch := make([]uint32, antiEntropySeriesSlots)
+		for i := range batch {
+			batch[i] = uint32(slice*antiEntropySeriesSlots + i)
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// pullRows asks the node to push the rows of the batches of buckets which the local shard doesn't hold.
+// The hashes of the local rows of a batch are taken right before it is requested, so the rows pulled by the former batches are known.
+// It returns the number of the pulled rows, and errNoReplica if the node doesn't hold the shard.
+func pullRows(client ReplicaClient, topic bus.Topic, node string, template *clusterv1.RepairShardRequest, batches [][]uint32,
+	rowHashes func(set *AntiEntropyBucketSet) ([]uint64, error),
+) (uint64, error) {
+	var pulled uint64
+	for i, buckets := range batches {
+		hashes, err := rowHashes(NewAntiEntropyBucketSet(buckets))
+		if err != nil {
+			return pulled, err
+		}
+		resp, _, err := requestRepair(client, topic, node, &clusterv1.RepairShardRequest{
+			RepairId:     fmt.Sprintf("%s/%d", template.RepairId, i),
+			Group:        template.Group,
+			ShardId:      template.ShardId,
+			SegmentStart: template.SegmentStart,
+			SegmentEnd:   template.SegmentEnd,
+			Node:         template.Node,
+			Buckets:      buckets,
+			RowHashes:    hashes,
+		})
+		if err != nil {
+			return pulled, err
+		}
+		pulled += resp.RowsSent
+	}
+	return pulled, nil
+}
+
+// requestRepair asks the node to push the missing rows and polls it until they are pushed.
+// started reports whether the node might have pushed some rows.
+func requestRepair(client ReplicaClient, topic bus.Topic, node string, req *clusterv1.RepairShardRequest) (
+	resp *clusterv1.RepairShardResponse, started bool, err error,
+) {
+	deadline := time.Now().Add(RepairTimeout)
+	// The polls don't carry the row hashes, and don't start the repair again if the node lost it.
+	poll := &clusterv1.RepairShardRequest{
+		RepairId: req.RepairId,
+		Group:    req.Group,
+		ShardId:  req.ShardId,
+		Node:     req.Node,
+		Poll:     true,
+	}
+	for {
+		var f bus.Future
+		f, err = client.Publish(context.Background(), topic, bus.NewMessageWithNode(bus.MessageID(time.Now().UnixNano()), node, req))
+		if err != nil {
+			return nil, started, err
+		}
+		var msg bus.Message
+		if msg, err = f.Get(); err != nil {
+			return nil, started, err
+		}
+		var ok bool
+		if resp, ok = msg.Data().(*clusterv1.RepairShardResponse); !ok {
+			return nil, started, fmt.Errorf("unexpected response %T", msg.Data())
+		}
+		if resp.Done && resp.PartsSent == 0 && resp.Error == "" && !started {
+			return nil, false, errNoReplica
+		}
+		started = true
+		if resp.Error != "" {
+			return nil, started, errors.New(resp.Error)
+		}
+		if resp.Done {
+			return resp, started, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, started, fmt.Errorf("the repair isn't finished in %s", RepairTimeout)
+		}
+		req = poll
+		time.Sleep(repairPollInterval)
+	}
+}
+
+// PushSeriesDocuments sends the documents of the series in the segment to the series index of the node repairing the shard,
+// so the rows pushed along with them can be queried.
+func PushSeriesDocuments[T TSTable, O any](client bus.Publisher, topic bus.Topic, l *logger.Logger, req *clusterv1.RepairShardRequest,
+	segment Segment[T, O], seriesIDs map[common.SeriesID]struct{},
+) error {
+	docs, err := segment.IndexDB().SeriesDocuments(context.Background(), seriesIDs)
+	if err != nil || len(docs) == 0 {
+		return err
+	}
+	docData, err := docs.Marshal()
+	if err != nil {
+		return err
+	}
+	payload := make([]byte, 0, len(docData)+len(req.Group)+16)
+	payload = encoding.EncodeBytes(payload, convert.StringToBytes(req.Group))
+	payload = encoding.Int64ToBytes(payload, req.SegmentStart)
+	payload = append(payload, docData...)
+	f, err := client.Publish(context.Background(), topic, bus.NewMessageWithNode(bus.MessageID(time.Now().UnixNano()), req.Node, payload))
+	if err != nil {
+		return fmt.Errorf("failed to push the series documents: %w", err)
+	}
+	if _, err = f.Get(); err != nil {
+		l.Warn().Err(err).Str("group", req.Group).Uint32("shard", req.ShardId).Str("node", req.Node).
+			Int("series", len(docs)).Msg("the node didn't index all the series of the missing rows")
+	}
+	return nil
+}
+
+type repairSession struct {
+	finishedAt time.Time
+	err        error
+	result     RepairPushResult
+	done       bool
+}
+
+// repairShardListener pushes the rows of the local shards to the data nodes repairing them.
+type repairShardListener struct {
+	*bus.UnImplementedHealthyListener
+	catalog  ReplicaCatalog
+	sessions map[string]*repairSession
+	mu       sync.Mutex
+}
+
+// NewRepairShardListener returns the listener pushing the rows of the shards of the catalog to the data nodes repairing them.
+func NewRepairShardListener(catalog ReplicaCatalog) bus.MessageListener {
+	return &repairShardListener{
+		catalog:  catalog,
+		sessions: make(map[string]*repairSession),
+	}
+}
+
+func (r *repairShardListener) Rev(_ context.Context, message bus.Message) bus.Message {
+	req, ok := message.Data().(*clusterv1.RepairShardRequest)
+	if !ok {
+		return bus.NewMessage(message.ID(), common.NewError("invalid data type for repair shard request"))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, s := range r.sessions {
+		if s.done && time.Since(s.finishedAt) > repairSessionTTL {
+			delete(r.sessions, id)
+		}
+	}
+	session, ok := r.sessions[req.RepairId]
+	if !ok {
+		if req.Poll {
+			session = &repairSession{done: true, err: fmt.Errorf("the repair %s is not found", req.RepairId)}
+		} else {
+			session = r.start(req)
+		}
+	}
+	resp := &clusterv1.RepairShardResponse{
+		RepairId:  req.RepairId,
+		Done:      session.done,
+		PartsSent: session.result.Parts,
+		BytesSent: session.result.Bytes,
+		RowsSent:  session.result.Rows,
+	}
+	if session.err != nil {
+		resp.Error = session.err.Error()
+	}
+	return bus.NewMessage(message.ID(), resp)
+}
+
+// start pushes the missing rows of the shard in the background.
+// A finished session is returned if the node doesn't hold the shard.
+func (r *repairShardListener) start(req *clusterv1.RepairShardRequest) *repairSession {
+	shard, err := r.catalog.LoadReplicaShard(req.Group, common.ShardID(req.ShardId), time.Unix(0, req.SegmentStart))
+	if err != nil || shard == nil {
+		return &repairSession{done: true}
+	}
+	// The buckets of the segments with different ends don't match.
+	if shard.TimeRange().End.UnixNano() != req.SegmentEnd {
+		shard.Release()
+		return &repairSession{done: true}
+	}
+	session := &repairSession{}
+	r.sessions[req.RepairId] = session
+	go func() {
+		defer shard.Release()
+		result, pushErr := shard.PushMissingRows(req)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		session.done = true
+		session.finishedAt = time.Now()
+		session.err = pushErr
+		session.result = result
+	}()
+	return session
+}
@@ -0,0 +1,263 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	clusterv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/cluster/v1"
+	"github.com/apache/skywalking-banyandb/pkg/bus"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const (
+	testRepairTopic = bus.Topic("repair")
+	testDigestTopic = bus.Topic("digest")
+)
+
+type fakeRow struct {
+	part     uint64
+	seriesID common.SeriesID
+	ts       int64
+	version  uint64
+}
+
+func (r fakeRow) hash() uint64 {
+	return AntiEntropyRowHash(r.seriesID, r.ts, r.version)
+}
+
+// fakeReplicaShard keeps the rows of a shard in memory, and pushes the missing rows to the shard of the requesting node.
+type fakeReplicaShard struct {
+	nodes     map[string]*fakeReplicaShard
+	timeRange timestamp.TimeRange
+	rows      []fakeRow
+	mu        sync.Mutex
+}
+
+func (s *fakeReplicaShard) TimeRange() timestamp.TimeRange {
+	return s.timeRange
+}
+
+func (s *fakeReplicaShard) Digest() (*AntiEntropyDigest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	digest := &AntiEntropyDigest{}
+	for _, r := range s.rows {
+		if b := AntiEntropyBucket(s.timeRange, r.seriesID, r.ts); b >= 0 {
+			digest.Add(b, r.hash())
+		}
+	}
+	return digest, nil
+}
+
+func (s *fakeReplicaShard) PartTimeRange(partID uint64) (int64, int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var minTS, maxTS int64
+	found := false
+	for _, r := range s.rows {
+		if r.part != partID {
+			continue
+		}
+		if !found || r.ts < minTS {
+			minTS = r.ts
+		}
+		if !found || r.ts > maxTS {
+			maxTS = r.ts
+		}
+		found = true
+	}
+	return minTS, maxTS, found
+}
+
+func (s *fakeReplicaShard) RowHashes(set *AntiEntropyBucketSet, excludedParts map[uint64]struct{}) ([]uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var hashes []uint64
+	for _, r := range s.rows {
+		if _, ok := excludedParts[r.part]; ok || !set.Contains(s.timeRange, r.seriesID, r.ts) {
+			continue
+		}
+		hashes = append(hashes, r.hash())
+	}
+	return hashes, nil
+}
+
+func (s *fakeReplicaShard) RemoveParts(partIDs map[uint64]struct{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.rows[:0]
+	for _, r := range s.rows {
+		if _, ok := partIDs[r.part]; !ok {
+			kept = append(kept, r)
+		}
+	}
+	s.rows = kept
+	return nil
+}
+
+func (s *fakeReplicaShard) PushMissingRows(req *clusterv1.RepairShardRequest) (RepairPushResult, error) {
+	known := make(map[uint64]struct{}, len(req.RowHashes))
+	for _, h := range req.RowHashes {
+		known[h] = struct{}{}
+	}
+	set := NewAntiEntropyBucketSet(req.Buckets)
+	var missing []fakeRow
+	s.mu.Lock()
+	for _, r := range s.rows {
+		if _, ok := known[r.hash()]; !ok && set.Contains(s.timeRange, r.seriesID, r.ts) {
+			missing = append(missing, fakeRow{part: 100, seriesID: r.seriesID, ts: r.ts, version: r.version})
+		}
+	}
+	s.mu.Unlock()
+	if len(missing) == 0 {
+		return RepairPushResult{}, nil
+	}
+	target := s.nodes[req.Node]
+	target.mu.Lock()
+	target.rows = append(target.rows, missing...)
+	target.mu.Unlock()
+	return RepairPushResult{Parts: 1, Rows: uint64(len(missing))}, nil
+}
+
+func (s *fakeReplicaShard) Release() {}
+
+func (s *fakeReplicaShard) hashes() map[uint64]uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hashes := make(map[uint64]uint64, len(s.rows))
+	for _, r := range s.rows {
+		hashes[r.hash()] = r.part
+	}
+	return hashes
+}
+
+type fakeReplicaCatalog struct {
+	shard *fakeReplicaShard
+}
+
+func (c *fakeReplicaCatalog) LoadReplicaShard(_ string, _ common.ShardID, _ time.Time) (ReplicaShard, error) {
+	if c.shard == nil {
+		return nil, nil
+	}
+	return c.shard, nil
+}
+
+// fakeReplicaClient delivers the requests to the listeners of the nodes.
+type fakeReplicaClient struct {
+	listeners map[bus.Topic]map[string]bus.MessageListener
+	nodes     []string
+}
+
+func (c *fakeReplicaClient) Publish(ctx context.Context, topic bus.Topic, messages ...bus.Message) (bus.Future, error) {
+	return &fakeFuture{msg: c.listeners[topic][messages[0].Node()].Rev(ctx, messages[0])}, nil
+}
+
+func (c *fakeReplicaClient) HealthyNodes() []string {
+	return c.nodes
+}
+
+type fakeFuture struct {
+	msg bus.Message
+}
+
+func (f *fakeFuture) Get() (bus.Message, error) {
+	return f.msg, nil
+}
+
+func (f *fakeFuture) GetAll() ([]bus.Message, error) {
+	return []bus.Message{f.msg}, nil
+}
+
+// newFakeReplicas returns the client reaching the shards of the nodes, which are nil if the node doesn't hold the shard.
+func newFakeReplicas(timeRange timestamp.TimeRange, rows map[string][]fakeRow) (*fakeReplicaClient, map[string]*fakeReplicaShard) {
+	client := &fakeReplicaClient{listeners: map[bus.Topic]map[string]bus.MessageListener{testRepairTopic: {}, testDigestTopic: {}}}
+	shards := make(map[string]*fakeReplicaShard)
+	for node, nodeRows := range rows {
+		client.nodes = append(client.nodes, node)
+		catalog := &fakeReplicaCatalog{}
+		if nodeRows != nil {
+			catalog.shard = &fakeReplicaShard{timeRange: timeRange, rows: nodeRows, nodes: shards}
+			shards[node] = catalog.shard
+		}
+		client.listeners[testRepairTopic][node] = NewRepairShardListener(catalog)
+		client.listeners[testDigestTopic][node] = NewAntiEntropyDigestListener(catalog)
+	}
+	return client, shards
+}
+
+func TestRepairPart(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := timestamp.NewSectionTimeRange(start, start.Add(16*time.Hour))
+	ts := func(h time.Duration) int64 {
+		return start.Add(h).UnixNano()
+	}
+	a := fakeRow{part: 1, seriesID: 1, ts: ts(time.Hour), version: 1}
+	b := fakeRow{part: 1, seriesID: 2, ts: ts(2 * time.Hour), version: 1}
+	c := fakeRow{part: 2, seriesID: 3, ts: ts(2 * time.Hour), version: 1}
+	d := fakeRow{part: 3, seriesID: 4, ts: ts(90 * time.Minute), version: 1}
+	e := fakeRow{part: 3, seriesID: 5, ts: ts(10 * time.Hour), version: 1}
+	client, shards := newFakeReplicas(tr, map[string][]fakeRow{
+		"local":   {a, b, c},
+		"empty":   nil,
+		"replica": {a, b, c, d, e},
+	})
+
+	require.NoError(t, RepairPart(client, &fakeReplicaCatalog{shard: shards["local"]}, testRepairTopic, "local", "g", 0, tr, 1))
+	hashes := shards["local"].hashes()
+	// The rows of the corrupted part and the ones in its time range missing from the other parts are pulled.
+	assert.Len(t, hashes, 4)
+	assert.Equal(t, uint64(100), hashes[a.hash()])
+	assert.Equal(t, uint64(100), hashes[b.hash()])
+	assert.Equal(t, uint64(100), hashes[d.hash()])
+	// The other parts are kept.
+	assert.Equal(t, uint64(2), hashes[c.hash()])
+	assert.NotContains(t, hashes, e.hash())
+
+	assert.ErrorContains(t, RepairPart(client, &fakeReplicaCatalog{shard: shards["local"]}, testRepairTopic, "local", "g", 0, tr, 1),
+		"is not found")
+
+	// The part is kept if no replica holds the shard.
+	client, shards = newFakeReplicas(tr, map[string][]fakeRow{
+		"local": {a, c},
+		"empty": nil,
+	})
+	assert.ErrorIs(t, RepairPart(client, &fakeReplicaCatalog{shard: shards["local"]}, testRepairTopic, "local", "g", 0, tr, 1), errNoReplica)
+	assert.Equal(t, uint64(1), shards["local"].hashes()[a.hash()])
+}
+
+func TestRepairBatches(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := timestamp.NewSectionTimeRange(start, start.Add(16*time.Hour))
+
+	batches := repairBatches(tr, start.Add(90*time.Minute).UnixNano(), start.Add(2*time.Hour).UnixNano())
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], antiEntropySeriesSlots)
+	assert.Equal(t, uint32(antiEntropySeriesSlots), batches[0][0])
+	assert.Equal(t, uint32(3*antiEntropySeriesSlots-1), batches[1][antiEntropySeriesSlots-1])
+
+	assert.Len(t, repairBatches(tr, start.Add(-time.Hour).UnixNano(), start.Add(20*time.Hour).UnixNano()), antiEntropyTimeSlices)
+	assert.Empty(t, repairBatches(tr, start.Add(-2*time.Hour).UnixNano(), start.Add(-time.Hour).UnixNano()))
+}
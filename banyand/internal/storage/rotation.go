@@ -62,6 +62,18 @@ func (d *database[T, O]) startRotationTask() error {
 				}
 			}()
 		}
+		var offloadC <-chan time.Time
+		if d.remote != nil {
+			offloadTicker := time.NewTicker(offloadCheckInterval)
+			offloadC = offloadTicker.C
+			defer offloadTicker.Stop()
+		}
+		var scrubC <-chan time.Time
+		if d.scrubber != nil && d.scrubber.interval > 0 {
+			scrubTicker := time.NewTicker(d.scrubber.interval)
+			scrubC = scrubTicker.C
+			defer scrubTicker.Stop()
+		}
 
 		for {
 			select {
@@ -122,6 +134,12 @@ func (d *database[T, O]) startRotationTask() error {
 						d.logger.Info().Int("count", closedCount).Msg("closed idle segments")
 					}
 				}()
+			case <-offloadC:
+				d.offload(time.Now())
+			case <-scrubC:
+				if !d.Scrub() {
+					d.logger.Debug().Msg("the previous scrub is still running, skip scrubbing")
+				}
 			}
 		}
 	}(rt)
@@ -0,0 +1,341 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/run"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const (
+	// scrubPartTypeCore is the type of the core parts handed to the failed parts handler.
+	scrubPartTypeCore = "core"
+	// sidxDirName is the directory of the secondary indexes in a shard.
+	sidxDirName = "sidx"
+	// scrubRecheckDelay is how long the scrubber waits before telling a failed part from a removed one.
+	scrubRecheckDelay = time.Second
+	// readFailureScrubInterval is the minimum period between two scrubs started by failed reads.
+	readFailureScrubInterval = 10 * time.Minute
+)
+
+var errScrubStopped = errors.New("scrub stopped")
+
+// ScrubConfig holds the configuration of the background scrubber.
+type ScrubConfig struct {
+	// Interval is the period between two scrubs of a group. The periodic scrub is disabled if it is zero.
+	Interval time.Duration
+	// Rate is the maximum number of bytes per second read by the scrubs of all the groups of a service.
+	// The scrubs are not throttled if it is zero.
+	Rate run.Bytes
+}
+
+// Scrubber schedules and throttles the scrubs of the groups of a service.
+type Scrubber struct {
+	next     time.Time
+	interval time.Duration
+	rate     uint64
+	mu       sync.Mutex
+}
+
+// NewScrubber returns a scrubber shared by the groups of a service.
+func NewScrubber(cfg ScrubConfig) *Scrubber {
+	return &Scrubber{
+		interval: cfg.Interval,
+		rate:     uint64(cfg.Rate),
+	}
+}
+
+// pace blocks until reading n more bytes doesn't exceed the rate.
+func (s *Scrubber) pace(n int) {
+	if s == nil || s.rate == 0 {
+		return
+	}
+	s.mu.Lock()
+	now := time.Now()
+	if s.next.Before(now) {
+		s.next = now
+	}
+	s.next = s.next.Add(time.Duration(uint64(n) * uint64(time.Second) / s.rate))
+	wait := s.next.Sub(now)
+	s.mu.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+type scrubPart struct {
+	segment   timestamp.TimeRange
+	path      string
+	shardPath string
+	partType  string
+	id        uint64
+	shardID   common.ShardID
+}
+
+type scrubState struct {
+	startedAt      time.Time
+	finishedAt     time.Time
+	corruptedParts []string
+	partsTotal     int
+	partsScrubbed  int
+	partsSkipped   int
+	bytesScrubbed  uint64
+	running        bool
+}
+
+// Scrub starts verifying the checksums of all the local parts in the background.
+// It returns false if a scrub is already running.
+func (d *database[T, O]) Scrub() bool {
+	if d.closed.Load() {
+		return false
+	}
+	d.scrubMu.Lock()
+	defer d.scrubMu.Unlock()
+	if d.scrub.running {
+		return false
+	}
+	d.scrub = scrubState{
+		running:   true,
+		startedAt: time.Now(),
+	}
+	d.scrubWG.Add(1)
+	go func() {
+		defer d.scrubWG.Done()
+		d.runScrub()
+	}()
+	return true
+}
+
+// ReportReadFailure starts a scrub after a read failed, so the corrupted part is found and repaired.
+// It returns false if a scrub is already running or a failed read started one recently.
+func (d *database[T, O]) ReportReadFailure() bool {
+	d.scrubMu.Lock()
+	if time.Since(d.readFailureScrubAt) < readFailureScrubInterval {
+		d.scrubMu.Unlock()
+		return false
+	}
+	d.readFailureScrubAt = time.Now()
+	d.scrubMu.Unlock()
+	d.logger.Warn().Msg("a read failed, start scrubbing parts")
+	return d.Scrub()
+}
+
+// ScrubStatus returns the progress of the latest scrub, or nil if the database has never been scrubbed.
+func (d *database[T, O]) ScrubStatus() *databasev1.ScrubStatus {
+	d.scrubMu.Lock()
+	defer d.scrubMu.Unlock()
+	if d.scrub.startedAt.IsZero() {
+		return nil
+	}
+	status := &databasev1.ScrubStatus{
+		Running:        d.scrub.running,
+		StartedAt:      timestamppb.New(d.scrub.startedAt),
+		PartsTotal:     int64(d.scrub.partsTotal),
+		PartsScrubbed:  int64(d.scrub.partsScrubbed),
+		PartsSkipped:   int64(d.scrub.partsSkipped),
+		BytesScrubbed:  int64(d.scrub.bytesScrubbed),
+		CorruptedParts: append([]string(nil), d.scrub.corruptedParts...),
+	}
+	if !d.scrub.finishedAt.IsZero() {
+		status.FinishedAt = timestamppb.New(d.scrub.finishedAt)
+	}
+	return status
+}
+
+func (d *database[T, O]) runScrub() {
+	parts := d.scrubCandidates()
+	d.scrubMu.Lock()
+	d.scrub.partsTotal = len(parts)
+	d.scrubMu.Unlock()
+	d.logger.Info().Int("parts", len(parts)).Msg("start scrubbing parts")
+	defer func() {
+		d.scrubMu.Lock()
+		d.scrub.running = false
+		d.scrub.finishedAt = time.Now()
+		d.logger.Info().Int("scrubbed", d.scrub.partsScrubbed).Int("skipped", d.scrub.partsSkipped).
+			Int("corrupted", len(d.scrub.corruptedParts)).Uint64("bytes", d.scrub.bytesScrubbed).Msg("finished scrubbing parts")
+		d.scrubMu.Unlock()
+		d.setLastScrubTime(time.Now())
+	}()
+	pace := func(n int) error {
+		if d.closed.Load() {
+			return errScrubStopped
+		}
+		d.scrubber.pace(n)
+		return nil
+	}
+	for _, p := range parts {
+		if d.closed.Load() {
+			return
+		}
+		n, err := VerifyPartChecksums(d.lfs, p.path, pace)
+		if errors.Is(err, errScrubStopped) {
+			return
+		}
+		skipped := errors.Is(err, errNoPartChecksums)
+		if err != nil && !skipped {
+			// A part merged away during the scrub is removed in the meantime, it isn't corrupted.
+			time.Sleep(scrubRecheckDelay)
+			skipped = !d.lfs.IsExist(p.path)
+		}
+		corrupted := err != nil && !skipped
+		if corrupted {
+			d.quarantine(p, err)
+			d.repair(p)
+		}
+		d.scrubMu.Lock()
+		d.scrub.bytesScrubbed += n
+		if skipped {
+			d.scrub.partsSkipped++
+		} else {
+			d.scrub.partsScrubbed++
+		}
+		if corrupted {
+			d.scrub.corruptedParts = append(d.scrub.corruptedParts, p.path)
+		}
+		d.scrubMu.Unlock()
+		d.incTotalScrubbedBytes(float64(n))
+		if !skipped {
+			d.incTotalScrubbedParts(1)
+		}
+	}
+}
+
+// quarantine hands a corrupted part to the failed parts handler of its shard, which keeps a copy for investigation.
+func (d *database[T, O]) quarantine(p scrubPart, cause error) {
+	d.incTotalCorruptedParts(1)
+	d.logger.Error().Err(cause).Str("part", p.path).Str("type", p.partType).Msg("found a corrupted part")
+	if d.closed.Load() {
+		return
+	}
+	handler := NewFailedPartsHandler(d.lfs, p.shardPath, d.logger, 0)
+	if err := handler.CopyToFailedPartsDir(p.id, p.path, fmt.Sprintf("%016x_%s", p.id, p.partType)); err != nil {
+		d.logger.Error().Err(err).Str("part", p.path).Msg("failed to copy the corrupted part to the failed-parts directory")
+	}
+}
+
+// repair replaces the rows of a corrupted part with the ones of a replica.
+// The part is removed, and only the rows in its time range missing from the other parts of the shard are pulled.
+func (d *database[T, O]) repair(p scrubPart) {
+	if d.repairPart == nil || p.partType != scrubPartTypeCore || d.closed.Load() {
+		return
+	}
+	d.incTotalRepairStarted(1)
+	start := time.Now()
+	d.logger.Info().Str("part", p.path).Uint32("shard", uint32(p.shardID)).
+		Time("segment_start", p.segment.Start).Time("segment_end", p.segment.End).Msg("start repairing the part from a replica")
+	if err := d.repairPart(p.shardID, p.segment, p.id); err != nil {
+		d.incTotalRepairErr(1)
+		d.logger.Error().Err(err).Str("part", p.path).Uint32("shard", uint32(p.shardID)).
+			Time("segment_start", p.segment.Start).Msg("failed to repair the part from a replica")
+		return
+	}
+	d.incTotalRepairFinished(1)
+	d.logger.Info().Str("part", p.path).Uint32("shard", uint32(p.shardID)).Time("segment_start", p.segment.Start).
+		Dur("duration", time.Since(start)).Msg("repaired the part from a replica")
+}
+
+// scrubCandidates lists the local parts of all the segments, including the parts of the secondary indexes.
+// The directories are read without panicking because retention may remove the segments concurrently.
+func (d *database[T, O]) scrubCandidates() []scrubPart {
+	type segmentLocation struct {
+		timeRange timestamp.TimeRange
+		location  string
+	}
+	d.segmentController.RLock()
+	var locations []segmentLocation
+	for _, s := range d.segmentController.lst {
+		for _, location := range s.locations {
+			locations = append(locations, segmentLocation{timeRange: s.TimeRange, location: location})
+		}
+	}
+	d.segmentController.RUnlock()
+	var parts []scrubPart
+	for _, sl := range locations {
+		shardEntries, err := os.ReadDir(sl.location)
+		if err != nil {
+			continue
+		}
+		for _, shardEntry := range shardEntries {
+			if !shardEntry.IsDir() || !strings.HasPrefix(shardEntry.Name(), shardPathPrefix+"-") {
+				continue
+			}
+			shardID, err := strconv.ParseUint(strings.TrimPrefix(shardEntry.Name(), shardPathPrefix+"-"), 10, 32)
+			if err != nil {
+				continue
+			}
+			shardPart := scrubPart{
+				segment:   sl.timeRange,
+				shardPath: filepath.Join(sl.location, shardEntry.Name()),
+				shardID:   common.ShardID(shardID),
+			}
+			parts = d.appendScrubParts(parts, shardPart, shardPart.shardPath, scrubPartTypeCore)
+			sidxEntries, err := os.ReadDir(filepath.Join(shardPart.shardPath, sidxDirName))
+			if err != nil {
+				continue
+			}
+			for _, sidxEntry := range sidxEntries {
+				if sidxEntry.IsDir() {
+					parts = d.appendScrubParts(parts, shardPart, filepath.Join(shardPart.shardPath, sidxDirName, sidxEntry.Name()), sidxEntry.Name())
+				}
+			}
+		}
+	}
+	return parts
+}
+
+// appendScrubParts appends the parts in the dir, which inherit the segment and the shard of shardPart.
+func (d *database[T, O]) appendScrubParts(parts []scrubPart, shardPart scrubPart, dir, partType string) []scrubPart {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return parts
+	}
+	for _, e := range entries {
+		if !e.IsDir() || !partDirPattern.MatchString(e.Name()) {
+			continue
+		}
+		partPath := filepath.Join(dir, e.Name())
+		// The data files of offloaded parts are verified by the object storage.
+		if d.remote != nil && d.remote.IsOffloaded(partPath) {
+			continue
+		}
+		id, err := strconv.ParseUint(e.Name(), 16, 64)
+		if err != nil {
+			continue
+		}
+		p := shardPart
+		p.path = partPath
+		p.partType = partType
+		p.id = id
+		parts = append(parts, p)
+	}
+	return parts
+}
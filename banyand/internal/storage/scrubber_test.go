@@ -0,0 +1,213 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+type repairedPart struct {
+	shardID common.ShardID
+	partID  uint64
+}
+
+func newScrubTestDB(fileSystem fs.FileSystem, segPath string, scrubber *Scrubber) *database[mockTSTable, mockTSTableOpener] {
+	db := &database[mockTSTable, mockTSTableOpener]{
+		lfs:               fileSystem,
+		logger:            logger.GetLogger("test"),
+		segmentController: &segmentController[mockTSTable, mockTSTableOpener]{},
+		scrubber:          scrubber,
+	}
+	db.segmentController.lst = []*segment[mockTSTable, mockTSTableOpener]{{locations: []string{segPath}}}
+	return db
+}
+
+func TestScrub(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fileSystem := fs.NewLocalFileSystem()
+	segPath := filepath.Join(t.TempDir(), "seg-20240101")
+	shardPath := filepath.Join(segPath, "shard-0")
+	intact := filepath.Join(shardPath, "0000000000000001")
+	corrupted := filepath.Join(shardPath, "0000000000000002")
+	sidxPart := filepath.Join(shardPath, sidxDirName, "duration", "0000000000000003")
+	for _, p := range []string{intact, corrupted, sidxPart} {
+		writeTestPart(t, fileSystem, p)
+	}
+	legacy := filepath.Join(shardPath, "0000000000000004")
+	writeTestPart(t, fileSystem, legacy)
+	require.NoError(t, os.Remove(filepath.Join(legacy, PartChecksumFilename)))
+	require.NoError(t, os.Truncate(filepath.Join(corrupted, "primary.bin"), 10))
+
+	db := newScrubTestDB(fileSystem, segPath, NewScrubber(ScrubConfig{}))
+	scrubbedParts, scrubbedBytes, corruptedParts := NewMockCounter(ctrl), NewMockCounter(ctrl), NewMockCounter(ctrl)
+	repairStarted, repairFinished, repairErr := NewMockCounter(ctrl), NewMockCounter(ctrl), NewMockCounter(ctrl)
+	lastScrubTime := NewMockGauge(ctrl)
+	db.metrics = &metrics{
+		totalScrubbedParts:  scrubbedParts,
+		totalScrubbedBytes:  scrubbedBytes,
+		totalCorruptedParts: corruptedParts,
+		lastScrubTime:       lastScrubTime,
+		totalRepairStarted:  repairStarted,
+		totalRepairFinished: repairFinished,
+		totalRepairErr:      repairErr,
+	}
+	var repairMu sync.Mutex
+	var repaired []repairedPart
+	db.repairPart = func(shardID common.ShardID, _ timestamp.TimeRange, partID uint64) error {
+		repairMu.Lock()
+		defer repairMu.Unlock()
+		repaired = append(repaired, repairedPart{shardID: shardID, partID: partID})
+		return nil
+	}
+
+	assert.Nil(t, db.ScrubStatus())
+	require.True(t, db.Scrub())
+	db.scrubWG.Wait()
+	status := db.ScrubStatus()
+	assert.False(t, status.GetRunning())
+	assert.Equal(t, int64(4), status.GetPartsTotal())
+	assert.Equal(t, int64(3), status.GetPartsScrubbed())
+	assert.Equal(t, int64(1), status.GetPartsSkipped())
+	assert.Equal(t, []string{corrupted}, status.GetCorruptedParts())
+	assert.NotNil(t, status.GetFinishedAt())
+
+	// The corrupted part is handed to the failed parts handler and repaired, the parts of the secondary indexes aren't repaired.
+	assert.True(t, fileSystem.IsExist(filepath.Join(shardPath, FailedPartsDirName, "0000000000000002_core", "primary.bin")))
+	assert.Equal(t, []repairedPart{{shardID: 0, partID: 2}}, repaired)
+
+	assert.Equal(t, float64(3), scrubbedParts.GetValue())
+	assert.Equal(t, float64(status.GetBytesScrubbed()), scrubbedBytes.GetValue())
+	assert.Equal(t, float64(1), corruptedParts.GetValue())
+	assert.Equal(t, float64(1), repairStarted.GetValue())
+	assert.Equal(t, float64(1), repairFinished.GetValue())
+	assert.Equal(t, float64(0), repairErr.GetValue())
+	assert.Greater(t, lastScrubTime.GetValue(), float64(0))
+
+	// A second scrub counts the corrupted part again.
+	require.True(t, db.Scrub())
+	db.scrubWG.Wait()
+	assert.Equal(t, float64(2), corruptedParts.GetValue())
+	assert.Equal(t, float64(6), scrubbedParts.GetValue())
+}
+
+func TestScrubRepairError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fileSystem := fs.NewLocalFileSystem()
+	segPath := filepath.Join(t.TempDir(), "seg-20240101")
+	corrupted := filepath.Join(segPath, "shard-1", "0000000000000002")
+	writeTestPart(t, fileSystem, corrupted)
+	require.NoError(t, os.Truncate(filepath.Join(corrupted, "primary.bin"), 10))
+
+	db := newScrubTestDB(fileSystem, segPath, NewScrubber(ScrubConfig{}))
+	repairStarted, repairFinished, repairErr := NewMockCounter(ctrl), NewMockCounter(ctrl), NewMockCounter(ctrl)
+	db.metrics = &metrics{
+		totalScrubbedParts:  NewMockCounter(ctrl),
+		totalScrubbedBytes:  NewMockCounter(ctrl),
+		totalCorruptedParts: NewMockCounter(ctrl),
+		lastScrubTime:       NewMockGauge(ctrl),
+		totalRepairStarted:  repairStarted,
+		totalRepairFinished: repairFinished,
+		totalRepairErr:      repairErr,
+	}
+	db.repairPart = func(common.ShardID, timestamp.TimeRange, uint64) error {
+		return errors.New("no replica")
+	}
+
+	require.True(t, db.Scrub())
+	db.scrubWG.Wait()
+	assert.Equal(t, []string{corrupted}, db.ScrubStatus().GetCorruptedParts())
+	assert.True(t, fileSystem.IsExist(filepath.Join(segPath, "shard-1", FailedPartsDirName, "0000000000000002_core", "primary.bin")))
+	assert.Equal(t, float64(1), repairStarted.GetValue())
+	assert.Equal(t, float64(0), repairFinished.GetValue())
+	assert.Equal(t, float64(1), repairErr.GetValue())
+}
+
+func TestScrubThrottled(t *testing.T) {
+	fileSystem := fs.NewLocalFileSystem()
+	segPath := filepath.Join(t.TempDir(), "seg-20240101")
+	shardPath := filepath.Join(segPath, "shard-0")
+	for _, p := range []string{"0000000000000001", "0000000000000002"} {
+		writeTestPart(t, fileSystem, filepath.Join(shardPath, p))
+	}
+
+	// Each part has about 192KiB, so the scrub reads for about 1.5 seconds.
+	db := newScrubTestDB(fileSystem, segPath, NewScrubber(ScrubConfig{Rate: 256 << 10}))
+	start := time.Now()
+	require.True(t, db.Scrub())
+	require.Eventually(t, func() bool {
+		status := db.ScrubStatus()
+		return status.GetRunning() && status.GetPartsScrubbed() == 1
+	}, 10*time.Second, 10*time.Millisecond, "the progress of the running scrub should be reported")
+	status := db.ScrubStatus()
+	assert.Equal(t, int64(2), status.GetPartsTotal())
+	assert.Nil(t, status.GetFinishedAt())
+	assert.False(t, db.Scrub(), "a scrub shouldn't start while another is running")
+	db.scrubWG.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	status = db.ScrubStatus()
+	assert.False(t, status.GetRunning())
+	assert.Equal(t, int64(2), status.GetPartsScrubbed())
+	assert.Empty(t, status.GetCorruptedParts())
+	assert.NotNil(t, status.GetFinishedAt())
+}
+
+func TestReportReadFailure(t *testing.T) {
+	fileSystem := fs.NewLocalFileSystem()
+	segPath := filepath.Join(t.TempDir(), "seg-20240101")
+	writeTestPart(t, fileSystem, filepath.Join(segPath, "shard-0", "0000000000000001"))
+
+	db := newScrubTestDB(fileSystem, segPath, NewScrubber(ScrubConfig{}))
+	require.True(t, db.ReportReadFailure())
+	db.scrubWG.Wait()
+	assert.Equal(t, int64(1), db.ScrubStatus().GetPartsScrubbed())
+	// The failed reads in a short period start a single scrub.
+	assert.False(t, db.ReportReadFailure())
+}
+
+func TestScrubberPace(t *testing.T) {
+	s := NewScrubber(ScrubConfig{Rate: 1 << 20})
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		s.pace(128 << 10)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	// A scrubber without a rate doesn't throttle.
+	for _, unthrottled := range []*Scrubber{nil, NewScrubber(ScrubConfig{})} {
+		start = time.Now()
+		unthrottled.pace(1 << 30)
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	}
+}
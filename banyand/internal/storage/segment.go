@@ -68,11 +68,13 @@ type segment[T TSTable, O any] struct {
 	sLst     atomic.Pointer[[]*shard[T]]
 	*segmentCache
 	indexMetrics *inverted.Metrics
+	limiter      *seriesLimiter
 	lfs          banyanfs.FileSystem
 	position     common.Position
 	timestamp.TimeRange
 	suffix        string
 	location      string
+	locations     []string
 	lastAccessed  atomic.Int64
 	mu            sync.RWMutex
 	refCount      int32
@@ -80,7 +82,7 @@ type segment[T TSTable, O any] struct {
 	id            segmentID
 }
 
-func (sc *segmentController[T, O]) openSegment(ctx context.Context, startTime, endTime time.Time, path, suffix string, groupCache *groupCache,
+func (sc *segmentController[T, O]) openSegment(ctx context.Context, startTime, endTime time.Time, location, suffix string, groupCache *groupCache,
 ) (s *segment[T, O], err error) {
 	suffixInteger, err := strconv.Atoi(suffix)
 	if err != nil {
@@ -93,15 +95,25 @@ func (sc *segmentController[T, O]) openSegment(ctx context.Context, startTime, e
 	})
 	options := sc.getOptions()
 	id := generateSegID(options.SegmentInterval.Unit, suffixInteger)
+	// The segment keeps its metadata and series index in the home location,
+	// while its shards might be placed on any data volume.
+	locations := []string{location}
+	for _, root := range sc.locations {
+		if l := path.Join(root, filepath.Base(location)); l != location {
+			locations = append(locations, l)
+		}
+	}
 
 	s = &segment[T, O]{
 		id:           id,
-		location:     path,
+		location:     location,
+		locations:    locations,
 		suffix:       suffix,
 		TimeRange:    timestamp.NewSectionTimeRange(startTime, endTime),
 		position:     p,
 		metrics:      sc.metrics,
 		indexMetrics: sc.indexMetrics,
+		limiter:      newSeriesLimiter(sc.seriesLimitMetrics),
 		tsdbOpts:     options,
 		lfs:          sc.lfs,
 		segmentCache: &segmentCache{groupCache: groupCache, segmentID: id},
@@ -112,18 +124,40 @@ func (sc *segmentController[T, O]) openSegment(ctx context.Context, startTime, e
 }
 
 func (s *segment[T, O]) loadShards(shardNum int) error {
-	return walkDir(s.location, shardPathPrefix, func(suffix string) error {
-		shardID, err := strconv.Atoi(suffix)
-		if err != nil {
+	for _, location := range s.locations {
+		if location != s.location && !s.lfs.IsExist(location) {
+			continue
+		}
+		if err := walkDir(location, shardPathPrefix, func(suffix string) error {
+			shardID, err := strconv.Atoi(suffix)
+			if err != nil {
+				return err
+			}
+			if shardID >= shardNum {
+				return nil
+			}
+			s.l.Info().Int("shard_id", shardID).Str("path", location).Msg("loaded a existed shard")
+			_, err = s.createShardIfNotExist(common.ShardID(shardID))
+			return err
+		}); err != nil {
 			return err
 		}
-		if shardID >= shardNum {
-			return nil
+	}
+	return nil
+}
+
+// hasDataOn reports whether the segment keeps any files on the data volume.
+// An empty volume matches every segment.
+func (s *segment[T, O]) hasDataOn(volume string) bool {
+	if volume == "" {
+		return true
+	}
+	for _, location := range s.locations {
+		if onVolume(location, volume) && s.lfs.IsExist(location) {
+			return true
 		}
-		s.l.Info().Int("shard_id", shardID).Msg("loaded a existed shard")
-		_, err = s.createShardIfNotExist(common.ShardID(shardID))
-		return err
-	})
+	}
+	return false
 }
 
 func (s *segment[T, O]) GetTimeRange() timestamp.TimeRange {
@@ -234,9 +268,9 @@ func (s *segment[T, O]) performCleanup() {
 		return
 	}
 
-	deletePath := ""
+	var deletePaths []string
 	if atomic.LoadUint32(&s.mustBeDeleted) != 0 {
-		deletePath = s.location
+		deletePaths = s.locations
 	}
 
 	if s.index != nil {
@@ -251,12 +285,12 @@ func (s *segment[T, O]) performCleanup() {
 		for _, shard := range *sLst {
 			shard.close()
 		}
-		if deletePath == "" {
+		if deletePaths == nil {
 			s.sLst.Store(&[]*shard[T]{})
 		}
 	}
 
-	if deletePath != "" {
+	for _, deletePath := range deletePaths {
 		s.lfs.MustRMAll(deletePath)
 	}
 }
@@ -321,25 +355,26 @@ type segmentController[T TSTable, O any] struct {
 	l            *logger.Logger
 	indexMetrics *inverted.Metrics
 	*groupCache
-	lfs         banyanfs.FileSystem
-	position    common.Position
-	db          string
-	stage       string
-	location    string
-	lst         []*segment[T, O]
-	idleTimeout time.Duration
-	optsMutex   sync.RWMutex
+	seriesLimitMetrics *seriesLimitMetrics
+	lfs                banyanfs.FileSystem
+	position           common.Position
+	db                 string
+	stage              string
+	locations          []string
+	lst                []*segment[T, O]
+	idleTimeout        time.Duration
+	optsMutex          sync.RWMutex
 	sync.RWMutex
 }
 
-func newSegmentController[T TSTable, O any](ctx context.Context, location string,
+func newSegmentController[T TSTable, O any](ctx context.Context, locations []string,
 	l *logger.Logger, opts TSDBOpts[T, O], indexMetrics *inverted.Metrics, metrics Metrics,
 	idleTimeout time.Duration, lfs banyanfs.FileSystem, cache Cache, group string,
 ) *segmentController[T, O] {
 	clock, _ := timestamp.GetClock(ctx)
 	p := common.GetPosition(ctx)
 	return &segmentController[T, O]{
-		location:     location,
+		locations:    locations,
 		opts:         &opts,
 		l:            l,
 		clock:        clock,
@@ -475,26 +510,36 @@ func (sc *segmentController[T, O]) open() error {
 	sc.Lock()
 	defer sc.Unlock()
 	emptySegments := make([]string, 0)
-	err := loadSegments(sc.location, segPathPrefix, sc, sc.getOptions().SegmentInterval, func(start, end time.Time) error {
-		suffix := sc.format(start)
-		segmentPath := path.Join(sc.location, fmt.Sprintf(segTemplate, suffix))
-		metadataPath := path.Join(segmentPath, metadataFilename)
-		version, err := sc.lfs.Read(metadataPath)
-		if err != nil {
-			if errors.Is(err, fs.ErrNotExist) {
-				emptySegments = append(emptySegments, segmentPath)
-				return nil
+	err := loadSegments(sc.locations, segPathPrefix, sc, sc.getOptions().SegmentInterval, func(start, end time.Time) error {
+		segDir := fmt.Sprintf(segTemplate, sc.format(start))
+		// The home location of a segment is the only one holding the metadata file.
+		home := ""
+		for _, location := range sc.locations {
+			version, err := sc.lfs.Read(path.Join(location, segDir, metadataFilename))
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return err
 			}
-			return err
+			if len(version) == 0 {
+				continue
+			}
+			if err = checkVersion(convert.BytesToString(version)); err != nil {
+				return err
+			}
+			home = location
+			break
 		}
-		if len(version) == 0 {
-			emptySegments = append(emptySegments, segmentPath)
+		if home == "" {
+			for _, location := range sc.locations {
+				if segmentPath := path.Join(location, segDir); sc.lfs.IsExist(segmentPath) {
+					emptySegments = append(emptySegments, segmentPath)
+				}
+			}
 			return nil
 		}
-		if err = checkVersion(convert.BytesToString(version)); err != nil {
-			return err
-		}
-		_, err = sc.load(start, end, sc.location)
+		_, err := sc.load(start, end, home)
 		return err
 	})
 	if len(emptySegments) > 0 {
@@ -534,7 +579,8 @@ func (sc *segmentController[T, O]) create(start time.Time) (*segment[T, O], erro
 	} else {
 		end = stdEnd
 	}
-	segPath := path.Join(sc.location, fmt.Sprintf(segTemplate, sc.format(start)))
+	location := sc.locations[pickVolume(sc.lfs, sc.locations, nil)]
+	segPath := path.Join(location, fmt.Sprintf(segTemplate, sc.format(start)))
 	sc.lfs.MkdirPanicIfExist(segPath, DirPerm)
 	data := []byte(currentVersion)
 	metadataPath := filepath.Join(segPath, metadataFilename)
@@ -549,7 +595,7 @@ func (sc *segmentController[T, O]) create(start time.Time) (*segment[T, O], erro
 	if n != len(data) {
 		logger.Panicf("unexpected number of bytes written to %s; got %d; want %d", metadataPath, n, len(data))
 	}
-	return sc.load(start, end, sc.location)
+	return sc.load(start, end, location)
 }
 
 func (sc *segmentController[T, O]) sortLst() {
@@ -654,20 +700,19 @@ func (sc *segmentController[T, O]) removeSeg(segID segmentID) {
 	}
 }
 
-// peekOldestSegmentEndTime returns the end time of the oldest segment.
+// peekOldestSegmentEndTime returns the end time of the oldest segment holding data on the volume.
 // It returns the zero time and false if no segments exist or all segments have refCount <= 0.
-func (sc *segmentController[T, O]) peekOldestSegmentEndTime() (time.Time, bool) {
+func (sc *segmentController[T, O]) peekOldestSegmentEndTime(volume string) (time.Time, bool) {
 	sc.RLock()
 	defer sc.RUnlock()
 
-	if len(sc.lst) <= 1 {
+	// Segments are sorted by ID (which correlates with start time),
+	// so the first one on the volume is the oldest
+	oldest := sc.oldestOnVolume(volume)
+	if oldest == nil {
 		return time.Time{}, false
 	}
 
-	// Segments are sorted by ID (which correlates with start time),
-	// so the first one is the oldest
-	oldest := sc.lst[0]
-
 	// Only return segments that are still active (have references > 0)
 	if atomic.LoadInt32(&oldest.refCount) > 0 {
 		return oldest.End, true
@@ -676,20 +721,17 @@ func (sc *segmentController[T, O]) peekOldestSegmentEndTime() (time.Time, bool)
 	return time.Time{}, false
 }
 
-// removeOldest removes exactly one oldest segment if it exists and meets the keep-one rule.
+// removeOldest removes exactly one oldest segment holding data on the volume if it exists and meets the keep-one rule.
 // Returns true if a segment was deleted, false if no segments to delete or keep-one rule prevents deletion.
-func (sc *segmentController[T, O]) removeOldest() (bool, error) {
+func (sc *segmentController[T, O]) removeOldest(volume string) (bool, error) {
 	sc.Lock()
 	defer sc.Unlock()
 
-	if len(sc.lst) <= 1 {
-		// Keep-one rule: never delete the last remaining segment
+	oldest := sc.oldestOnVolume(volume)
+	if oldest == nil {
 		return false, nil
 	}
 
-	// Find the oldest segment (first in sorted list)
-	oldest := sc.lst[0]
-
 	// Delete the segment and remove from list
 	oldest.delete()
 	sc.removeSeg(oldest.id)
@@ -698,6 +740,20 @@ func (sc *segmentController[T, O]) removeOldest() (bool, error) {
 	return true, nil
 }
 
+// oldestOnVolume returns the oldest segment holding data on the volume.
+// The latest segment is never returned to honor the keep-one rule.
+func (sc *segmentController[T, O]) oldestOnVolume(volume string) *segment[T, O] {
+	if len(sc.lst) <= 1 {
+		return nil
+	}
+	for _, s := range sc.lst[:len(sc.lst)-1] {
+		if s.hasDataOn(volume) {
+			return s
+		}
+	}
+	return nil
+}
+
 func (sc *segmentController[T, O]) close() {
 	sc.Lock()
 	defer sc.Unlock()
@@ -712,20 +768,29 @@ func (sc *segmentController[T, O]) close() {
 	}
 }
 
-func loadSegments[T TSTable, O any](root, prefix string, parser *segmentController[T, O], intervalRule IntervalRule, loadFn func(start, end time.Time) error) error {
+func loadSegments[T TSTable, O any](roots []string, prefix string, parser *segmentController[T, O], intervalRule IntervalRule,
+	loadFn func(start, end time.Time) error,
+) error {
 	var startTimeLst []time.Time
-	if err := walkDir(
-		root,
-		prefix,
-		func(suffix string) error {
-			startTime, err := parser.parse(suffix)
-			if err != nil {
-				return err
-			}
-			startTimeLst = append(startTimeLst, startTime)
-			return nil
-		}); err != nil {
-		return err
+	seen := make(map[int64]struct{})
+	for _, root := range roots {
+		if err := walkDir(
+			root,
+			prefix,
+			func(suffix string) error {
+				startTime, err := parser.parse(suffix)
+				if err != nil {
+					return err
+				}
+				if _, ok := seen[startTime.UnixNano()]; ok {
+					return nil
+				}
+				seen[startTime.UnixNano()] = struct{}{}
+				startTimeLst = append(startTimeLst, startTime)
+				return nil
+			}); err != nil {
+			return err
+		}
 	}
 	sort.Slice(startTimeLst, func(i, j int) bool { return startTimeLst[i].Before(startTimeLst[j]) })
 	for i, start := range startTimeLst {
@@ -104,7 +104,7 @@ func TestSegmentOpenAndReopen(t *testing.T) {
 	serviceCache := NewServiceCache().(*serviceCache)
 	sc := newSegmentController[mockTSTable, mockTSTableOpener](
 		ctx,
-		tempDir,
+		[]string{tempDir},
 		l,
 		opts,
 		nil,           // indexMetrics
@@ -198,7 +198,7 @@ func TestSegmentCloseIfIdle(t *testing.T) {
 	serviceCache := NewServiceCache().(*serviceCache)
 	sc := newSegmentController[mockTSTable, mockTSTableOpener](
 		ctx,
-		tempDir,
+		[]string{tempDir},
 		l,
 		opts,
 		nil,         // indexMetrics
@@ -284,7 +284,7 @@ func TestCloseIdleAndSelectSegments(t *testing.T) {
 	serviceCache := NewServiceCache().(*serviceCache)
 	sc := newSegmentController[mockTSTable, mockTSTableOpener](
 		ctx,
-		tempDir,
+		[]string{tempDir},
 		l,
 		opts,
 		nil,         // indexMetrics
@@ -419,7 +419,7 @@ func TestOpenExistingSegmentWithShards(t *testing.T) {
 	serviceCache := NewServiceCache().(*serviceCache)
 	sc := newSegmentController[mockTSTable, mockTSTableOpener](
 		ctx,
-		tempDir,
+		[]string{tempDir},
 		l,
 		opts,
 		nil,           // indexMetrics
@@ -531,7 +531,7 @@ func TestDeleteExpiredSegmentsWithClosedSegments(t *testing.T) {
 	serviceCache := NewServiceCache().(*serviceCache)
 	sc := newSegmentController[mockTSTable, mockTSTableOpener](
 		ctx,
-		tempDir,
+		[]string{tempDir},
 		l,
 		opts,
 		nil,         // indexMetrics
@@ -641,3 +641,81 @@ func TestDeleteExpiredSegmentsWithClosedSegments(t *testing.T) {
 			"Remaining segment %d should be from the expected date", i)
 	}
 }
+
+func TestSegmentControllerMultipleVolumes(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	volume1, volume2 := t.TempDir(), t.TempDir()
+
+	l := logger.GetLogger("test-segment")
+	ctx := context.WithValue(context.Background(), logger.ContextKey, l)
+	ctx = common.SetPosition(ctx, func(_ common.Position) common.Position {
+		return common.Position{
+			Database: "test-db",
+			Stage:    "test-stage",
+		}
+	})
+	opts := TSDBOpts[mockTSTable, mockTSTableOpener]{
+		TSTableCreator: func(_ fs.FileSystem, _ string, _ common.Position, _ *logger.Logger,
+			_ timestamp.TimeRange, _ mockTSTableOpener, _ any,
+		) (mockTSTable, error) {
+			return mockTSTable{}, nil
+		},
+		ShardNum:                       2,
+		SegmentInterval:                IntervalRule{Unit: DAY, Num: 1},
+		TTL:                            IntervalRule{Unit: DAY, Num: 7},
+		SeriesIndexFlushTimeoutSeconds: 10,
+		SeriesIndexCacheMaxBytes:       1024 * 1024,
+	}
+	newController := func() *segmentController[mockTSTable, mockTSTableOpener] {
+		return newSegmentController[mockTSTable, mockTSTableOpener](ctx, []string{volume1, volume2}, l, opts, nil, nil, 5*time.Minute,
+			fs.NewLocalFileSystemWithLoggerAndLimit(logger.GetLogger("storage"), opts.MemoryLimit), NewServiceCache(), group)
+	}
+
+	sc := newController()
+	require.NoError(t, sc.open())
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	seg, err := sc.create(day1)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = seg.CreateTSTableIfNotExist(common.ShardID(i))
+		require.NoError(t, err)
+	}
+	shard0, ok := seg.getShard(0)
+	require.True(t, ok)
+	shard1, ok := seg.getShard(1)
+	require.True(t, ok)
+	assert.NotEqual(t, filepath.Dir(filepath.Dir(shard0.location)), filepath.Dir(filepath.Dir(shard1.location)),
+		"the shards of a segment should be spread across the volumes")
+	assert.True(t, seg.hasDataOn(volume1))
+	assert.True(t, seg.hasDataOn(volume2))
+	sc.close()
+
+	// Reopening finds the segment and all of its shards whichever volume they live on.
+	sc = newController()
+	require.NoError(t, sc.open())
+	require.Len(t, sc.lst, 1)
+	seg = sc.lst[0]
+	_, ok = seg.getShard(0)
+	assert.True(t, ok)
+	_, ok = seg.getShard(1)
+	assert.True(t, ok)
+
+	_, err = sc.create(day1.Add(24 * time.Hour))
+	require.NoError(t, err)
+	_, ok = sc.peekOldestSegmentEndTime(filepath.Join(t.TempDir(), "unknown"))
+	assert.False(t, ok, "no segment keeps data on an unknown volume")
+	end, ok := sc.peekOldestSegmentEndTime(volume2)
+	require.True(t, ok)
+	assert.Equal(t, seg.End, end)
+	deleted, err := sc.removeOldest(volume2)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	for _, location := range seg.locations {
+		assert.NoDirExists(t, location)
+	}
+	deleted, err = sc.removeOldest("")
+	require.NoError(t, err)
+	assert.False(t, deleted, "the latest segment is always kept")
+	sc.close()
+}
@@ -0,0 +1,299 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	clusterv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/cluster/v1"
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/banyand/observability"
+	"github.com/apache/skywalking-banyandb/pkg/bus"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+	"github.com/apache/skywalking-banyandb/pkg/meter"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const (
+	defaultSeriesLimitInterval = time.Minute
+
+	seriesLimitReasonSegment = "max_segment_series_per_node"
+	seriesLimitReasonNew     = "max_new_series_per_node"
+)
+
+// ErrSeriesLimitExceeded indicates that a new series exceeds the series limits of its resource.
+var ErrSeriesLimitExceeded = errors.New("series limit exceeded")
+
+// SeriesLimits bounds the series created by a resource in a segment of a data node. Zero means no limit.
+type SeriesLimits struct {
+	MaxSegmentSeriesPerNode uint64
+	MaxNewSeriesPerNode     uint64
+	Interval                time.Duration
+}
+
+// NewSeriesLimits merges the non-zero limits of a resource into the limits of its group.
+func NewSeriesLimits(group, resource *commonv1.SeriesLimits) SeriesLimits {
+	limits := SeriesLimits{
+		MaxSegmentSeriesPerNode: group.GetMaxSegmentSeriesPerNode(),
+		MaxNewSeriesPerNode:     group.GetMaxNewSeriesPerNode(),
+		Interval:                defaultSeriesLimitInterval,
+	}
+	interval := group.GetInterval()
+	if resource.GetMaxSegmentSeriesPerNode() > 0 {
+		limits.MaxSegmentSeriesPerNode = resource.GetMaxSegmentSeriesPerNode()
+	}
+	if resource.GetMaxNewSeriesPerNode() > 0 {
+		limits.MaxNewSeriesPerNode = resource.GetMaxNewSeriesPerNode()
+	}
+	if resource.GetInterval() != "" {
+		interval = resource.GetInterval()
+	}
+	if interval != "" {
+		if d, err := timestamp.ParseDuration(interval); err == nil && d > 0 {
+			limits.Interval = d
+		}
+	}
+	return limits
+}
+
+// Enabled returns whether any limit is set.
+func (sl SeriesLimits) Enabled() bool {
+	return sl.MaxSegmentSeriesPerNode > 0 || sl.MaxNewSeriesPerNode > 0
+}
+
+type seriesLimitMetrics struct {
+	totalRejected meter.Counter
+	totalSeries   meter.Gauge
+}
+
+// newSeriesLimitMetrics creates the metrics of the series limits of a group.
+// The factory of the storage of a group carries its "group" constant label, so the metrics are labelled by both the group and the resource.
+func newSeriesLimitMetrics(factory observability.Factory) *seriesLimitMetrics {
+	if factory == nil {
+		return nil
+	}
+	return &seriesLimitMetrics{
+		totalRejected: factory.NewCounter("total_series_rejected", "name", "reason"),
+		totalSeries:   factory.NewGauge("total_limited_series", "name"),
+	}
+}
+
+// seriesLimiter tracks the series of the resources having series limits in a segment.
+type seriesLimiter struct {
+	metrics   *seriesLimitMetrics
+	resources map[string]*limitedSeries
+	mu        sync.Mutex
+}
+
+type limitedSeries struct {
+	known       map[common.SeriesID]struct{}
+	windowStart time.Time
+	windowNew   uint64
+	seeded      bool
+	seeding     bool
+}
+
+// seriesLoader loads all the series of a resource from the series index.
+type seriesLoader func(ctx context.Context, subject string, entitySize int) (pbv1.SeriesList, error)
+
+func newSeriesLimiter(metrics *seriesLimitMetrics) *seriesLimiter {
+	return &seriesLimiter{
+		metrics:   metrics,
+		resources: make(map[string]*limitedSeries),
+	}
+}
+
+// admit checks whether the series could be inserted into the series index.
+// The known series of a resource are loaded by the loader in the background when the resource is checked for the first time.
+// Until they are loaded, the new series are tracked but not limited, since the existing ones can't be told apart from them.
+func (sl *seriesLimiter) admit(series *pbv1.Series, entitySize int, limits SeriesLimits, now time.Time, load seriesLoader) error {
+	if !limits.Enabled() {
+		return nil
+	}
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	ls, ok := sl.resources[series.Subject]
+	if !ok {
+		ls = &limitedSeries{
+			known:       make(map[common.SeriesID]struct{}),
+			windowStart: now,
+		}
+		sl.resources[series.Subject] = ls
+	}
+	if !ls.seeded && !ls.seeding && load != nil {
+		ls.seeding = true
+		go sl.seed(load, series.Subject, entitySize)
+	}
+	if _, ok = ls.known[series.ID]; ok {
+		return nil
+	}
+	if !ls.seeded {
+		ls.known[series.ID] = struct{}{}
+		return nil
+	}
+	if limits.MaxSegmentSeriesPerNode > 0 && uint64(len(ls.known)) >= limits.MaxSegmentSeriesPerNode {
+		sl.reject(series.Subject, seriesLimitReasonSegment)
+		return errors.WithMessagef(ErrSeriesLimitExceeded, "%s has %d series in the segment, reaching the max segment series per node %d",
+			series.Subject, len(ls.known), limits.MaxSegmentSeriesPerNode)
+	}
+	if now.Sub(ls.windowStart) >= limits.Interval {
+		ls.windowStart = now
+		ls.windowNew = 0
+	}
+	if limits.MaxNewSeriesPerNode > 0 && ls.windowNew >= limits.MaxNewSeriesPerNode {
+		sl.reject(series.Subject, seriesLimitReasonNew)
+		return errors.WithMessagef(ErrSeriesLimitExceeded, "%s created %d new series in %s, reaching the max new series per node %d",
+			series.Subject, ls.windowNew, limits.Interval, limits.MaxNewSeriesPerNode)
+	}
+	ls.known[series.ID] = struct{}{}
+	ls.windowNew++
+	if sl.metrics != nil {
+		sl.metrics.totalSeries.Set(float64(len(ls.known)), series.Subject)
+	}
+	return nil
+}
+
+// seed merges the series of the resource in the series index into the known ones.
+// A failed load is retried on the next admission of the resource.
+func (sl *seriesLimiter) seed(load seriesLoader, subject string, entitySize int) {
+	existing, err := load(context.Background(), subject, entitySize)
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	ls := sl.resources[subject]
+	ls.seeding = false
+	if err != nil {
+		return
+	}
+	for _, s := range existing {
+		ls.known[s.ID] = struct{}{}
+	}
+	ls.seeded = true
+	if sl.metrics != nil {
+		sl.metrics.totalSeries.Set(float64(len(ls.known)), subject)
+	}
+}
+
+func (sl *seriesLimiter) reject(subject, reason string) {
+	if sl.metrics == nil {
+		return
+	}
+	sl.metrics.totalRejected.Inc(1, subject, reason)
+}
+
+// AdmitSeriesDocuments drops the series documents exceeding the series limits of their resources.
+// The limits function returns the series limits and the entity size of a resource.
+// It returns the admitted documents and the IDs of the rejected series, whose data should be dropped as well.
+// A document is kept if its series fails to be checked.
+func AdmitSeriesDocuments[T TSTable, O any](segment Segment[T, O], docs index.Documents,
+	limits func(subject string) (SeriesLimits, int),
+) (index.Documents, []common.SeriesID, error) {
+	type resourceLimits struct {
+		limits     SeriesLimits
+		entitySize int
+	}
+	resources := make(map[string]resourceLimits)
+	admitted := docs[:0]
+	var checkErr error
+	var rejected []common.SeriesID
+	for _, doc := range docs {
+		series := &pbv1.Series{}
+		if err := series.Unmarshal(doc.EntityValues); err != nil {
+			checkErr = multierr.Append(checkErr, err)
+			admitted = append(admitted, doc)
+			continue
+		}
+		rl, ok := resources[series.Subject]
+		if !ok {
+			rl.limits, rl.entitySize = limits(series.Subject)
+			resources[series.Subject] = rl
+		}
+		if !rl.limits.Enabled() {
+			admitted = append(admitted, doc)
+			continue
+		}
+		if err := segment.AdmitSeries(series, rl.entitySize, rl.limits); err != nil {
+			if errors.Is(err, ErrSeriesLimitExceeded) {
+				rejected = append(rejected, series.ID)
+				continue
+			}
+			checkErr = multierr.Append(checkErr, err)
+		}
+		admitted = append(admitted, doc)
+	}
+	return admitted, rejected, checkErr
+}
+
+// NewSeriesIndexWriteResponse reports the rejected series to the liaison writing the series index.
+func NewSeriesIndexWriteResponse(rejected []common.SeriesID) *clusterv1.SeriesIndexWriteResponse {
+	resp := &clusterv1.SeriesIndexWriteResponse{RejectedSeriesIds: make([]uint64, len(rejected))}
+	for i, id := range rejected {
+		resp.RejectedSeriesIds[i] = uint64(id)
+	}
+	return resp
+}
+
+// CollectRejectedSeries adds the series rejected by a data node in the response of writing the series index to the set.
+func CollectRejectedSeries(rejected map[common.SeriesID]struct{}, resp bus.Message) map[common.SeriesID]struct{} {
+	r, ok := resp.Data().(*clusterv1.SeriesIndexWriteResponse)
+	if !ok || len(r.GetRejectedSeriesIds()) == 0 {
+		return rejected
+	}
+	if rejected == nil {
+		rejected = make(map[common.SeriesID]struct{}, len(r.GetRejectedSeriesIds()))
+	}
+	for _, id := range r.GetRejectedSeriesIds() {
+		rejected[common.SeriesID(id)] = struct{}{}
+	}
+	return rejected
+}
+
+// CollectRejectedMessages adds the write messages of the rejected series to the statuses.
+// The messages are the write messages of each series in a batch.
+func CollectRejectedMessages(statuses map[uint64]modelv1.Status, messages map[common.SeriesID][]uint64,
+	rejected map[common.SeriesID]struct{},
+) map[uint64]modelv1.Status {
+	for id := range rejected {
+		for _, messageID := range messages[id] {
+			if statuses == nil {
+				statuses = make(map[uint64]modelv1.Status)
+			}
+			statuses[messageID] = modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED
+		}
+	}
+	return statuses
+}
+
+// DropRejectedDocuments drops the documents of the rejected series.
+func DropRejectedDocuments(docs index.Documents, rejected map[common.SeriesID]struct{}) index.Documents {
+	kept := docs[:0]
+	for _, doc := range docs {
+		if _, ok := rejected[common.SeriesID(doc.DocID)]; ok {
+			continue
+		}
+		kept = append(kept, doc)
+	}
+	return kept
+}
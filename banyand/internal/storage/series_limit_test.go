@@ -0,0 +1,137 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+)
+
+func TestNewSeriesLimits(t *testing.T) {
+	limits := NewSeriesLimits(nil, nil)
+	assert.False(t, limits.Enabled())
+	assert.Equal(t, defaultSeriesLimitInterval, limits.Interval)
+
+	limits = NewSeriesLimits(&commonv1.SeriesLimits{MaxSegmentSeriesPerNode: 100, MaxNewSeriesPerNode: 10, Interval: "1h"},
+		&commonv1.SeriesLimits{MaxNewSeriesPerNode: 5})
+	assert.True(t, limits.Enabled())
+	assert.Equal(t, uint64(100), limits.MaxSegmentSeriesPerNode)
+	assert.Equal(t, uint64(5), limits.MaxNewSeriesPerNode)
+	assert.Equal(t, time.Hour, limits.Interval)
+
+	limits = NewSeriesLimits(&commonv1.SeriesLimits{Interval: "1h"}, &commonv1.SeriesLimits{Interval: "2d"})
+	assert.False(t, limits.Enabled())
+	assert.Equal(t, 48*time.Hour, limits.Interval)
+}
+
+func TestSeriesLimiterAdmit(t *testing.T) {
+	now := time.Now()
+	sl := newSeriesLimiter(nil)
+	// The known series are loaded from the series index.
+	sl.resources["svc"] = &limitedSeries{
+		known:       map[common.SeriesID]struct{}{1: {}},
+		windowStart: now,
+		seeded:      true,
+	}
+	admit := func(id common.SeriesID, limits SeriesLimits, at time.Time) error {
+		return sl.admit(&pbv1.Series{Subject: "svc", ID: id}, 1, limits, at, nil)
+	}
+
+	rateLimits := SeriesLimits{MaxNewSeriesPerNode: 2, Interval: time.Minute}
+	require.NoError(t, admit(1, rateLimits, now))
+	require.NoError(t, admit(2, rateLimits, now))
+	require.NoError(t, admit(3, rateLimits, now))
+	assert.ErrorIs(t, admit(4, rateLimits, now), ErrSeriesLimitExceeded)
+	// The known series are always admitted.
+	require.NoError(t, admit(2, rateLimits, now))
+	// The new series are admitted in the next interval.
+	require.NoError(t, admit(4, rateLimits, now.Add(time.Minute)))
+
+	totalLimits := SeriesLimits{MaxSegmentSeriesPerNode: 4, Interval: time.Minute}
+	assert.ErrorIs(t, admit(5, totalLimits, now.Add(time.Minute)), ErrSeriesLimitExceeded)
+	require.NoError(t, admit(3, totalLimits, now.Add(time.Minute)))
+
+	// No series is tracked without limits.
+	require.NoError(t, admit(6, SeriesLimits{Interval: time.Minute}, now))
+	assert.Len(t, sl.resources["svc"].known, 4)
+}
+
+func TestSeriesLimiterSeed(t *testing.T) {
+	now := time.Now()
+	sl := newSeriesLimiter(nil)
+	loading := make(chan struct{})
+	var loads atomic.Int32
+	load := func(_ context.Context, subject string, _ int) (pbv1.SeriesList, error) {
+		loads.Add(1)
+		<-loading
+		return pbv1.SeriesList{{Subject: subject, ID: 1}, {Subject: subject, ID: 2}}, nil
+	}
+	limits := SeriesLimits{MaxSegmentSeriesPerNode: 3, Interval: time.Minute}
+	admit := func(id common.SeriesID) error {
+		return sl.admit(&pbv1.Series{Subject: "svc", ID: id}, 1, limits, now, load)
+	}
+
+	// The new series aren't limited until the existing ones are loaded.
+	require.NoError(t, admit(3))
+	require.NoError(t, admit(4))
+	close(loading)
+	require.Eventually(t, func() bool {
+		sl.mu.Lock()
+		defer sl.mu.Unlock()
+		return sl.resources["svc"].seeded
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), loads.Load())
+	assert.Len(t, sl.resources["svc"].known, 4)
+
+	require.NoError(t, admit(1))
+	assert.ErrorIs(t, admit(5), ErrSeriesLimitExceeded)
+}
+
+func TestCollectRejectedMessages(t *testing.T) {
+	messages := map[common.SeriesID][]uint64{1: {10, 11}, 2: {20}, 3: {30}}
+	statuses := CollectRejectedMessages(nil, messages, map[common.SeriesID]struct{}{1: {}, 4: {}})
+	assert.Equal(t, map[uint64]modelv1.Status{
+		10: modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED,
+		11: modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED,
+	}, statuses)
+
+	// The accepted writes of the batch take the status of waiting for the replicas.
+	rejectedErr := common.NewErrorWithMessageStatuses(modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED, "rejected", statuses)
+	degradedErr := common.NewErrorWithStatus(modelv1.Status_STATUS_WRITE_DEGRADED, "degraded")
+	merged := common.MergeErrors([]uint64{10, 11, 20, 30}, rejectedErr, degradedErr)
+	require.NotNil(t, merged)
+	assert.Equal(t, modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED, merged.MessageStatus(10))
+	assert.Equal(t, modelv1.Status_STATUS_WRITE_DEGRADED, merged.MessageStatus(20))
+	assert.Equal(t, modelv1.Status_STATUS_WRITE_DEGRADED, merged.MessageStatus(30))
+
+	merged = common.MergeErrors([]uint64{10, 11, 20, 30}, rejectedErr, nil)
+	require.NotNil(t, merged)
+	assert.Equal(t, modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED, merged.MessageStatus(11))
+	assert.Equal(t, modelv1.Status_STATUS_SUCCEED, merged.MessageStatus(20))
+	assert.Nil(t, common.MergeErrors([]uint64{10}, nil, nil))
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"path/filepath"
 	"strconv"
 
 	"github.com/apache/skywalking-banyandb/api/common"
@@ -72,7 +73,7 @@ type shard[T TSTable] struct {
 }
 
 func (s *segment[T, O]) openShard(ctx context.Context, id common.ShardID) (*shard[T], error) {
-	location := path.Join(s.location, fmt.Sprintf(shardTemplate, int(id)))
+	location := s.shardLocation(id)
 	s.lfs.MkdirIfNotExist(location, DirPerm)
 	l := logger.Fetch(ctx, "shard"+strconv.Itoa(int(id)))
 	l.Info().Int("shard_id", int(id)).Str("path", location).Msg("loading a shard")
@@ -96,6 +97,36 @@ func (s *segment[T, O]) openShard(ctx context.Context, id common.ShardID) (*shar
 	}, nil
 }
 
+// shardLocation returns the directory of the shard. An existing directory is reused,
+// otherwise the shard is placed on the data volume which has the most free space.
+func (s *segment[T, O]) shardLocation(id common.ShardID) string {
+	name := fmt.Sprintf(shardTemplate, int(id))
+	if len(s.locations) < 2 {
+		return path.Join(s.location, name)
+	}
+	for _, location := range s.locations {
+		if p := path.Join(location, name); s.lfs.IsExist(p) {
+			return p
+		}
+	}
+	roots := make([]string, len(s.locations))
+	placed := make([]int, len(s.locations))
+	for i, location := range s.locations {
+		// The segment directory is created along with the shard, so measure its parent.
+		roots[i] = filepath.Dir(location)
+	}
+	if sLst := s.sLst.Load(); sLst != nil {
+		for _, so := range *sLst {
+			for i, location := range s.locations {
+				if onVolume(so.location, location) {
+					placed[i]++
+				}
+			}
+		}
+	}
+	return path.Join(s.locations[pickVolume(s.lfs, roots, placed)], name)
+}
+
 func (s *shard[T]) Table() T {
 	return s.table
 }
@@ -32,6 +32,7 @@ import (
 
 	"github.com/apache/skywalking-banyandb/api/common"
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
 	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
@@ -57,6 +58,8 @@ const (
 	RepairDir = "repairs"
 	// DataDir is the directory for data.
 	DataDir = "data"
+	// RemoteCacheDir is the directory for the read-through cache of offloaded data.
+	RemoteCacheDir = "remote-cache"
 	// FilePerm is the permission of the file.
 	FilePerm = 0o600
 )
@@ -103,6 +106,10 @@ type IndexDB interface {
 	SearchWithoutSeries(ctx context.Context, opts IndexSearchOpts) (sd SeriesData, sortedValues [][]byte, err error)
 	EnableExternalSegments() (index.ExternalSegmentStreamer, error)
 	Stats() (dataCount int64, dataSizeBytes int64)
+	FieldSize(fieldKey index.FieldKey) int64
+	Terms(fieldKey index.FieldKey, prefix []byte) ([][]byte, error)
+	// SeriesDocuments returns the documents of the series, which only carry the entity values.
+	SeriesDocuments(ctx context.Context, seriesIDs map[common.SeriesID]struct{}) (index.Documents, error)
 }
 
 // TSDB allows listing and getting shard details.
@@ -115,14 +122,24 @@ type TSDB[T TSTable, O any] interface {
 	TakeFileSnapshot(dst string) (bool, error)
 	GetExpiredSegmentsTimeRange() *timestamp.TimeRange
 	DeleteExpiredSegments(segmentSuffixes []string) int64
-	// PeekOldestSegmentEndTime returns the end time of the oldest segment.
+	// PeekOldestSegmentEndTime returns the end time of the oldest segment holding data on the volume.
+	// An empty volume matches every segment.
 	// Returns the zero time and false if no segments exist or retention gate cannot be acquired.
-	PeekOldestSegmentEndTime() (time.Time, bool)
-	// DeleteOldestSegment deletes exactly one oldest segment if it exists and meets safety rules.
+	PeekOldestSegmentEndTime(volume string) (time.Time, bool)
+	// DeleteOldestSegment deletes exactly one oldest segment holding data on the volume if it exists
+	// and meets safety rules. An empty volume matches every segment.
 	// Returns true if a segment was deleted, false otherwise.
-	DeleteOldestSegment() (bool, error)
+	DeleteOldestSegment(volume string) (bool, error)
 	// Drop closes the database and removes all data files from disk.
 	Drop() error
+	// Scrub starts verifying the checksums of all the local parts in the background.
+	// It returns false if a scrub is already running.
+	Scrub() bool
+	// ScrubStatus returns the progress of the latest scrub, or nil if the database has never been scrubbed.
+	ScrubStatus() *databasev1.ScrubStatus
+	// ReportReadFailure starts a scrub after a read failed, so the corrupted part is found and repaired.
+	// It returns false if a scrub is already running or a failed read started one recently.
+	ReportReadFailure() bool
 }
 
 // Segment is a time range of data.
@@ -134,6 +151,7 @@ type Segment[T TSTable, O any] interface {
 	TablesWithShardIDs() ([]T, []common.ShardID, []Cache)
 	Lookup(ctx context.Context, series []*pbv1.Series) (pbv1.SeriesList, error)
 	IndexDB() IndexDB
+	AdmitSeries(series *pbv1.Series, entitySize int, limits SeriesLimits) error
 }
 
 // TSTable is time series table.
@@ -0,0 +1,95 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+)
+
+// TagValueSet is a set of distinct tag values.
+type TagValueSet map[string]struct{}
+
+// Sorted returns the values in ascending order, truncated to the limit if it is positive.
+func (tvs TagValueSet) Sorted(limit int) []string {
+	values := make([]string, 0, len(tvs))
+	for v := range tvs {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
+
+// CollectEntityTagValues collects the values of the entity tag at pos starting with the prefix from the series index.
+func (tvs TagValueSet) CollectEntityTagValues(ctx context.Context, db IndexDB, subject string, entitySize, pos int, prefix string) error {
+	seriesList, err := SearchAllSeries(ctx, db, subject, entitySize)
+	if err != nil {
+		return err
+	}
+	for _, s := range seriesList {
+		if pos >= len(s.EntityValues) {
+			continue
+		}
+		if v, ok := TagValueString(s.EntityValues[pos]); ok && strings.HasPrefix(v, prefix) {
+			tvs[v] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// CollectTerms collects the terms of the field starting with the prefix from the term dictionary of an index.
+// decode converts a term to the tag value, and returns false if the term is not a value of the tag.
+func (tvs TagValueSet) CollectTerms(terms func(index.FieldKey, []byte) ([][]byte, error), fieldKey index.FieldKey,
+	prefix []byte, decode func([]byte) (string, bool),
+) error {
+	tt, err := terms(fieldKey, prefix)
+	if err != nil {
+		return err
+	}
+	for _, t := range tt {
+		if v, ok := decode(t); ok {
+			tvs[v] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// TagValueString returns the string form of a string or an int tag value.
+func TagValueString(tv *modelv1.TagValue) (string, bool) {
+	switch v := tv.GetValue().(type) {
+	case *modelv1.TagValue_Str:
+		return v.Str.GetValue(), true
+	case *modelv1.TagValue_Int:
+		return strconv.FormatInt(v.Int.GetValue(), 10), true
+	default:
+		return "", false
+	}
+}
+
+// StringTerm decodes a term of a string tag.
+func StringTerm(term []byte) (string, bool) {
+	return string(term), true
+}
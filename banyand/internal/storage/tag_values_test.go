@@ -0,0 +1,62 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+)
+
+func TestTagValueSetSorted(t *testing.T) {
+	tvs := TagValueSet{"svc-b": {}, "svc-c": {}, "svc-a": {}}
+	assert.Equal(t, []string{"svc-a", "svc-b", "svc-c"}, tvs.Sorted(0))
+	assert.Equal(t, []string{"svc-a", "svc-b"}, tvs.Sorted(2))
+	assert.Empty(t, TagValueSet{}.Sorted(10))
+}
+
+func TestTagValueSetCollectTerms(t *testing.T) {
+	terms := func(_ index.FieldKey, prefix []byte) ([][]byte, error) {
+		assert.Equal(t, []byte("svc"), prefix)
+		return [][]byte{[]byte("svc-a"), []byte("svc-b")}, nil
+	}
+	tvs := make(TagValueSet)
+	require.NoError(t, tvs.CollectTerms(terms, index.FieldKey{IndexRuleID: 1}, []byte("svc"), StringTerm))
+	assert.Equal(t, []string{"svc-a", "svc-b"}, tvs.Sorted(0))
+
+	filtered := make(TagValueSet)
+	require.NoError(t, filtered.CollectTerms(terms, index.FieldKey{IndexRuleID: 1}, []byte("svc"), func(term []byte) (string, bool) {
+		return string(term), string(term) != "svc-b"
+	}))
+	assert.Equal(t, []string{"svc-a"}, filtered.Sorted(0))
+}
+
+func TestTagValueString(t *testing.T) {
+	v, ok := TagValueString(&modelv1.TagValue{Value: &modelv1.TagValue_Str{Str: &modelv1.Str{Value: "svc"}}})
+	assert.True(t, ok)
+	assert.Equal(t, "svc", v)
+	v, ok = TagValueString(&modelv1.TagValue{Value: &modelv1.TagValue_Int{Int: &modelv1.Int{Value: -10}}})
+	assert.True(t, ok)
+	assert.Equal(t, "-10", v)
+	_, ok = TagValueString(&modelv1.TagValue{Value: &modelv1.TagValue_BinaryData{BinaryData: []byte("svc")}})
+	assert.False(t, ok)
+}
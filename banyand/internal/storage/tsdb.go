@@ -32,6 +32,7 @@ import (
 	"github.com/apache/skywalking-banyandb/banyand/observability"
 	obsservice "github.com/apache/skywalking-banyandb/banyand/observability/services"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/tiered"
 	"github.com/apache/skywalking-banyandb/pkg/index/inverted"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 	"github.com/apache/skywalking-banyandb/pkg/timestamp"
@@ -52,11 +53,23 @@ const (
 
 // TSDBOpts wraps options to create a tsdb.
 type TSDBOpts[T TSTable, O any] struct {
-	Option                         O
-	TableMetrics                   Metrics
-	TSTableCreator                 TSTableCreator[T, O]
-	StorageMetricsFactory          observability.Factory
+	Option                O
+	TableMetrics          Metrics
+	TSTableCreator        TSTableCreator[T, O]
+	StorageMetricsFactory observability.Factory
+	RemoteStorage         *RemoteStorage
+	Scrubber              *Scrubber
+	KeepLocal             func(name string) bool
+	// RepairPart replaces the rows of a corrupted part of a shard in the segment of the time range with the ones of a replica.
+	// The parts found corrupted by the scrubber are kept in place if it is nil.
+	RepairPart func(shardID common.ShardID, timeRange timestamp.TimeRange, partID uint64) error
+	// AntiEntropy compares the shard in the segment of the time range with its replicas and pulls the missing rows.
+	// The anti-entropy repair is disabled if it is nil.
+	AntiEntropy func(shardID common.ShardID, timeRange timestamp.TimeRange) (AntiEntropyResult, error)
+	// AntiEntropyCron is the cron expression scheduling the anti-entropy repair. It's disabled if it is empty.
+	AntiEntropyCron                string
 	Location                       string
+	ExtraLocations                 []string
 	SegmentInterval                IntervalRule
 	TTL                            IntervalRule
 	SeriesIndexFlushTimeoutSeconds int64
@@ -143,19 +156,30 @@ func (gc *groupCache) put(key EntryKey, value Sizable) {
 type database[T TSTable, O any] struct {
 	lock              fs.File
 	lfs               fs.FileSystem
+	remote            *tiered.FileSystem
+	scrubber          *Scrubber
+	repairPart        func(shardID common.ShardID, timeRange timestamp.TimeRange, partID uint64) error
+	antiEntropy       func(shardID common.ShardID, timeRange timestamp.TimeRange) (AntiEntropyResult, error)
 	tsEventCh         chan int64
 	scheduler         *timestamp.Scheduler
 	segmentController *segmentController[T, O]
 	*metrics
-	logger         *logger.Logger
-	retentionGate  chan struct{}
-	p              common.Position
-	location       string
-	latestTickTime atomic.Int64
+	logger             *logger.Logger
+	retentionGate      chan struct{}
+	p                  common.Position
+	location           string
+	extraLocations     []string
+	readFailureScrubAt time.Time
+	scrub              scrubState
+	latestTickTime     atomic.Int64
+	scrubWG            sync.WaitGroup
+	antiEntropyWG      sync.WaitGroup
 	sync.RWMutex
-	rotationProcessOn atomic.Bool
-	closed            atomic.Bool
-	disableRetention  bool
+	scrubMu            sync.Mutex
+	rotationProcessOn  atomic.Bool
+	antiEntropyRunning atomic.Bool
+	closed             atomic.Bool
+	disableRetention   bool
 }
 
 func (d *database[T, O]) Close() error {
@@ -163,6 +187,8 @@ func (d *database[T, O]) Close() error {
 		return nil
 	}
 	d.closed.Store(true)
+	d.scrubWG.Wait()
+	d.antiEntropyWG.Wait()
 	d.Lock()
 	defer d.Unlock()
 	d.scheduler.Close()
@@ -186,6 +212,9 @@ func (d *database[T, O]) Drop() (err error) {
 		}
 	}()
 	d.lfs.MustRMAll(d.location)
+	for _, location := range d.extraLocations {
+		d.lfs.MustRMAll(location)
+	}
 	return nil
 }
 
@@ -202,7 +231,18 @@ func OpenTSDB[T TSTable, O any](ctx context.Context, opts TSDBOpts[T, O], cache
 	location := filepath.Clean(opts.Location)
 	tsdbLfs := fs.NewLocalFileSystemWithLoggerAndLimit(logger.GetLogger("storage"), opts.MemoryLimit)
 	tsdbLfs.MkdirIfNotExist(location, DirPerm)
+	extraLocations := make([]string, 0, len(opts.ExtraLocations))
+	for _, extra := range opts.ExtraLocations {
+		extra = filepath.Clean(extra)
+		tsdbLfs.MkdirIfNotExist(extra, DirPerm)
+		extraLocations = append(extraLocations, extra)
+	}
 	l := logger.Fetch(ctx, p.Database)
+	var tieredFS *tiered.FileSystem
+	if opts.RemoteStorage != nil {
+		tieredFS = tiered.NewFileSystem(tsdbLfs, opts.RemoteStorage.fs, opts.RemoteStorage.cache, opts.RemoteStorage.root, l)
+		tsdbLfs = tieredFS
+	}
 	clock, _ := timestamp.GetClock(ctx)
 	scheduler := timestamp.NewScheduler(l, clock)
 
@@ -215,19 +255,25 @@ func OpenTSDB[T TSTable, O any](ctx context.Context, opts TSDBOpts[T, O], cache
 		sc = cache
 	}
 	db := &database[T, O]{
-		location:  location,
-		scheduler: scheduler,
-		logger:    l,
-		tsEventCh: make(chan int64),
-		p:         p,
-		segmentController: newSegmentController(ctx, location,
+		location:       location,
+		extraLocations: extraLocations,
+		scheduler:      scheduler,
+		logger:         l,
+		tsEventCh:      make(chan int64),
+		p:              p,
+		segmentController: newSegmentController(ctx, append([]string{location}, extraLocations...),
 			l, opts, indexMetrics, opts.TableMetrics, opts.SegmentIdleTimeout, tsdbLfs, sc, group),
 		metrics:          newMetrics(opts.StorageMetricsFactory),
 		disableRetention: opts.DisableRetention,
 		lfs:              tsdbLfs,
+		remote:           tieredFS,
+		scrubber:         opts.Scrubber,
+		repairPart:       opts.RepairPart,
+		antiEntropy:      opts.AntiEntropy,
 		retentionGate:    make(chan struct{}, 1),
 	}
-	db.logger.Info().Str("path", opts.Location).Msg("initialized")
+	db.segmentController.seriesLimitMetrics = newSeriesLimitMetrics(opts.StorageMetricsFactory)
+	db.logger.Info().Str("path", opts.Location).Strs("extra_paths", extraLocations).Msg("initialized")
 	lockPath := filepath.Join(opts.Location, lockFilename)
 	lock, err := tsdbLfs.CreateLockFile(lockPath, FilePerm)
 	if err != nil {
@@ -238,7 +284,10 @@ func OpenTSDB[T TSTable, O any](ctx context.Context, opts TSDBOpts[T, O], cache
 		return nil, err
 	}
 	obsservice.MetricsCollector.Register(location, db.collect)
-	return db, db.startRotationTask()
+	if err = db.startRotationTask(); err != nil {
+		return db, err
+	}
+	return db, db.startAntiEntropyTask(opts.AntiEntropyCron)
 }
 
 func (d *database[T, O]) CreateSegmentIfNotExist(ts time.Time) (Segment[T, O], error) {
@@ -325,11 +374,12 @@ func (d *database[T, O]) DeleteExpiredSegments(segmentSuffixes []string) int64 {
 	return d.segmentController.deleteExpiredSegments(segmentSuffixes)
 }
 
-// PeekOldestSegmentEndTime returns the end time of the oldest segment.
+// PeekOldestSegmentEndTime returns the end time of the oldest segment holding data on the volume.
+// An empty volume matches every segment.
 // It acquires the retention gate to ensure exclusivity with TTL operations.
 // Returns the zero time and false if no segments exist, database is closed,
 // or the retention gate cannot be acquired.
-func (d *database[T, O]) PeekOldestSegmentEndTime() (time.Time, bool) {
+func (d *database[T, O]) PeekOldestSegmentEndTime(volume string) (time.Time, bool) {
 	if d.closed.Load() {
 		return time.Time{}, false
 	}
@@ -338,18 +388,19 @@ func (d *database[T, O]) PeekOldestSegmentEndTime() (time.Time, bool) {
 	select {
 	case d.retentionGate <- struct{}{}:
 		defer func() { <-d.retentionGate }()
-		return d.segmentController.peekOldestSegmentEndTime()
+		return d.segmentController.peekOldestSegmentEndTime(volume)
 	default:
 		// Retention gate is busy (TTL is running), return false
 		return time.Time{}, false
 	}
 }
 
-// DeleteOldestSegment deletes exactly one oldest segment if it exists and meets safety rules.
+// DeleteOldestSegment deletes exactly one oldest segment holding data on the volume if it exists
+// and meets safety rules. An empty volume matches every segment.
 // It acquires the retention gate to ensure exclusivity with TTL operations.
 // Returns true if a segment was deleted, false if no segments to delete,
 // keep-one rule prevents deletion, database is closed, or retention gate cannot be acquired.
-func (d *database[T, O]) DeleteOldestSegment() (bool, error) {
+func (d *database[T, O]) DeleteOldestSegment(volume string) (bool, error) {
 	if d.closed.Load() {
 		return false, nil
 	}
@@ -358,7 +409,7 @@ func (d *database[T, O]) DeleteOldestSegment() (bool, error) {
 	select {
 	case d.retentionGate <- struct{}{}:
 		defer func() { <-d.retentionGate }()
-		return d.segmentController.removeOldest()
+		return d.segmentController.removeOldest(volume)
 	default:
 		// Retention gate is busy (TTL is running), skip deletion
 		return false, nil
@@ -0,0 +1,71 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+)
+
+// pickVolume returns the index of the root which offers the most free space for a new
+// segment or shard. placed holds how many items of the same kind each root already hosts,
+// so that items created in a burst, such as the shards of a new segment, are spread across
+// volumes of similar size instead of all landing on the emptiest one.
+func pickVolume(lfs fs.FileSystem, roots []string, placed []int) int {
+	if len(roots) < 2 {
+		return 0
+	}
+	picked := 0
+	var best uint64
+	for i, root := range roots {
+		free := lfs.MustGetFreeSpace(root)
+		if placed != nil {
+			free /= uint64(placed[i] + 1)
+		}
+		if i == 0 || free > best {
+			picked, best = i, free
+		}
+	}
+	return picked
+}
+
+// onVolume reports whether the path is under the volume directory.
+func onVolume(path, volume string) bool {
+	if volume == "" {
+		return true
+	}
+	volume = filepath.Clean(volume)
+	path = filepath.Clean(path)
+	return path == volume || strings.HasPrefix(path, volume+string(filepath.Separator))
+}
+
+// CheckDataPaths makes sure that no data directory is configured twice or nested in another one,
+// otherwise segments would be loaded more than once.
+func CheckDataPaths(paths ...string) error {
+	for i := range paths {
+		for j := i + 1; j < len(paths); j++ {
+			if onVolume(paths[i], paths[j]) || onVolume(paths[j], paths[i]) {
+				return fmt.Errorf("data paths %s and %s overlap", paths[i], paths[j])
+			}
+		}
+	}
+	return nil
+}
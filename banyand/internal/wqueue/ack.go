@@ -0,0 +1,193 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package wqueue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+)
+
+// RequiredAcks returns the number of the copies which must persist a write of the consistency.
+// Zero means the write is acknowledged without waiting for the copies.
+func RequiredAcks(consistency commonv1.WriteConsistency, copies uint32) int {
+	switch consistency {
+	case commonv1.WriteConsistency_WRITE_CONSISTENCY_ONE:
+		return 1
+	case commonv1.WriteConsistency_WRITE_CONSISTENCY_QUORUM:
+		return int(copies)/2 + 1
+	case commonv1.WriteConsistency_WRITE_CONSISTENCY_ALL:
+		return int(copies)
+	default:
+		return 0
+	}
+}
+
+// AckTracker tracks the writes of a sub-queue until the parts holding them are synced to the data nodes.
+//
+// A write is identified by the mem part it's introduced as, and the snapshot epoch introducing it.
+// The write is tracked along with the introduction, before any sync reads a snapshot holding the mem part.
+// So the first sync of a snapshot at or after that epoch without the mem part is the one covering the write,
+// since the mem part was flushed or merged into the synced parts, and the write takes the acknowledgements of that sync.
+type AckTracker struct {
+	waiters map[*AckWaiter]struct{}
+	mu      sync.Mutex
+	closed  bool
+}
+
+// AckWaiter waits for a write to be synced.
+type AckWaiter struct {
+	t      *AckTracker
+	done   chan struct{}
+	partID uint64
+	epoch  uint64
+	acked  int
+}
+
+// Track starts tracking the write introduced as the mem part partID in the snapshot epoch.
+// It must be called before the snapshot is visible to the syncs.
+func (t *AckTracker) Track(partID, epoch uint64) *AckWaiter {
+	w := &AckWaiter{
+		t:      t,
+		done:   make(chan struct{}),
+		partID: partID,
+		epoch:  epoch,
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		close(w.done)
+		return w
+	}
+	if t.waiters == nil {
+		t.waiters = make(map[*AckWaiter]struct{})
+	}
+	t.waiters[w] = struct{}{}
+	return w
+}
+
+// Synced acknowledges the writes covered by the sync of the snapshot epoch, which reached acked data nodes.
+// The pending are the mem parts of the snapshot, which aren't synced yet.
+func (t *AckTracker) Synced(epoch uint64, pending map[uint64]struct{}, acked int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.release(epoch, pending, acked)
+}
+
+// Skipped marks the snapshot epoch which had no part to sync.
+// A write it covers left no part to sync, so the write is released without any acknowledgement.
+func (t *AckTracker) Skipped(epoch uint64, pending map[uint64]struct{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.release(epoch, pending, 0)
+}
+
+func (t *AckTracker) release(epoch uint64, pending map[uint64]struct{}, acked int) {
+	for w := range t.waiters {
+		if !w.coveredBy(epoch, pending) {
+			continue
+		}
+		w.acked = acked
+		close(w.done)
+		delete(t.waiters, w)
+	}
+}
+
+// Close releases all the waiting writes without any acknowledgement.
+func (t *AckTracker) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for w := range t.waiters {
+		close(w.done)
+		delete(t.waiters, w)
+	}
+}
+
+// Wait blocks until the write is synced or the context is done.
+// It returns the number of the data nodes which acknowledged the write.
+func (w *AckWaiter) Wait(ctx context.Context) int {
+	select {
+	case <-w.done:
+		w.t.mu.Lock()
+		defer w.t.mu.Unlock()
+		return w.acked
+	case <-ctx.Done():
+		w.t.mu.Lock()
+		defer w.t.mu.Unlock()
+		select {
+		case <-w.done:
+			return w.acked
+		default:
+		}
+		delete(w.t.waiters, w)
+		return 0
+	}
+}
+
+func (w *AckWaiter) coveredBy(epoch uint64, pending map[uint64]struct{}) bool {
+	if epoch < w.epoch {
+		return false
+	}
+	_, ok := pending[w.partID]
+	return !ok
+}
+
+// PendingAck is a write waiting for the acknowledgements of the replicas.
+type PendingAck struct {
+	Waiter *AckWaiter
+	Group  string
+	// MessageIDs are the messages of the batch written by the write.
+	MessageIDs []uint64
+	Required   int
+}
+
+// WaitAcks waits for the replicas to acknowledge the writes. It returns a degraded error
+// reporting the messages of the writes which aren't persisted by the required replicas before the timeout.
+func WaitAcks(ctx context.Context, acks []PendingAck, timeout time.Duration) *common.Error {
+	if len(acks) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var statuses map[uint64]modelv1.Status
+	var msgs []string
+	for _, a := range acks {
+		acked := a.Waiter.Wait(ctx)
+		if acked >= a.Required {
+			continue
+		}
+		if statuses == nil {
+			statuses = make(map[uint64]modelv1.Status)
+		}
+		for _, id := range a.MessageIDs {
+			statuses[id] = modelv1.Status_STATUS_WRITE_DEGRADED
+		}
+		msgs = append(msgs, fmt.Sprintf("group %s: %d replicas acknowledged the write, want %d", a.Group, acked, a.Required))
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return common.NewErrorWithMessageStatuses(modelv1.Status_STATUS_WRITE_DEGRADED, strings.Join(msgs, "; "), statuses)
+}
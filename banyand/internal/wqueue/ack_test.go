@@ -0,0 +1,137 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package wqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+)
+
+func TestRequiredAcks(t *testing.T) {
+	tests := []struct {
+		consistency commonv1.WriteConsistency
+		copies      uint32
+		expected    int
+	}{
+		{commonv1.WriteConsistency_WRITE_CONSISTENCY_UNSPECIFIED, 3, 0},
+		{commonv1.WriteConsistency_WRITE_CONSISTENCY_ONE, 3, 1},
+		{commonv1.WriteConsistency_WRITE_CONSISTENCY_QUORUM, 1, 1},
+		{commonv1.WriteConsistency_WRITE_CONSISTENCY_QUORUM, 2, 2},
+		{commonv1.WriteConsistency_WRITE_CONSISTENCY_QUORUM, 3, 2},
+		{commonv1.WriteConsistency_WRITE_CONSISTENCY_ALL, 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.consistency.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, RequiredAcks(tt.consistency, tt.copies))
+		})
+	}
+}
+
+func TestAckTracker(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var tracker AckTracker
+	w1 := tracker.Track(1, 1)
+	w2 := tracker.Track(2, 2)
+	w3 := tracker.Track(3, 3)
+
+	// The mem part 2 isn't flushed and the snapshot 2 doesn't hold the mem part 3.
+	tracker.Synced(2, map[uint64]struct{}{2: {}}, 2)
+	assert.Equal(t, 2, w1.Wait(ctx))
+	assertPending(t, w2)
+	assertPending(t, w3)
+
+	tracker.Synced(4, map[uint64]struct{}{}, 3)
+	assert.Equal(t, 3, w2.Wait(ctx))
+	assert.Equal(t, 3, w3.Wait(ctx))
+
+	// A write takes the acknowledgements of the sync covering it rather than the ones of an earlier sync.
+	w4 := tracker.Track(4, 5)
+	tracker.Synced(5, map[uint64]struct{}{4: {}}, 3)
+	assertPending(t, w4)
+	tracker.Synced(6, map[uint64]struct{}{}, 1)
+	assert.Equal(t, 1, w4.Wait(ctx))
+
+	// A write covered by a snapshot without any part to sync isn't acknowledged.
+	w5 := tracker.Track(5, 7)
+	tracker.Skipped(7, map[uint64]struct{}{})
+	assert.Equal(t, 0, w5.Wait(ctx))
+}
+
+func TestAckTracker_Timeout(t *testing.T) {
+	var tracker AckTracker
+	w := tracker.Track(1, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, 0, w.Wait(ctx))
+	assert.Empty(t, tracker.waiters)
+}
+
+func TestAckTracker_Close(t *testing.T) {
+	var tracker AckTracker
+	w := tracker.Track(1, 1)
+	tracker.Close()
+	assert.Equal(t, 0, w.Wait(context.Background()))
+	assert.Equal(t, 0, tracker.Track(2, 2).Wait(context.Background()))
+}
+
+func TestWaitAcks(t *testing.T) {
+	var tracker AckTracker
+	w1 := tracker.Track(1, 1)
+	w2 := tracker.Track(2, 1)
+	tracker.Synced(1, nil, 2)
+	assert.Nil(t, WaitAcks(context.Background(), []PendingAck{
+		{Waiter: w1, Group: "g", Required: 2},
+		{Waiter: w2, Group: "g", Required: 1},
+	}, time.Second))
+
+	// Only the messages of the degraded writes are reported.
+	w3 := tracker.Track(3, 2)
+	w4 := tracker.Track(4, 2)
+	tracker.Synced(2, nil, 1)
+	err := WaitAcks(context.Background(), []PendingAck{
+		{Waiter: w3, Group: "g", Required: 2, MessageIDs: []uint64{1, 2}},
+		{Waiter: w4, Group: "g", Required: 1, MessageIDs: []uint64{3}},
+	}, time.Second)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, modelv1.Status_STATUS_WRITE_DEGRADED, err.Status())
+		assert.Equal(t, modelv1.Status_STATUS_WRITE_DEGRADED, err.MessageStatus(1))
+		assert.Equal(t, modelv1.Status_STATUS_WRITE_DEGRADED, err.MessageStatus(2))
+		assert.Equal(t, modelv1.Status_STATUS_SUCCEED, err.MessageStatus(3))
+	}
+
+	err = WaitAcks(context.Background(), []PendingAck{{Waiter: tracker.Track(5, 3), Group: "g", Required: 1, MessageIDs: []uint64{4}}}, 10*time.Millisecond)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, modelv1.Status_STATUS_WRITE_DEGRADED, err.MessageStatus(4))
+	}
+}
+
+func assertPending(t *testing.T, w *AckWaiter) {
+	select {
+	case <-w.done:
+		t.Fatalf("the write of the mem part %d is acknowledged", w.partID)
+	default:
+	}
+}
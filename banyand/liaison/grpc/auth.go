@@ -26,6 +26,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/apache/skywalking-banyandb/banyand/liaison/pkg/auth"
+	"github.com/apache/skywalking-banyandb/banyand/metadata/schema"
 )
 
 func authInterceptor(authReloader *auth.Reloader) grpc.UnaryServerInterceptor {
@@ -91,3 +92,18 @@ func validateUser(ctx context.Context, authReloader *auth.Reloader) error {
 	}
 	return nil
 }
+
+// authorInterceptor records the user of a request as the author of the schema changes made by it.
+func authorInterceptor(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if usernames := md.Get("username"); len(usernames) > 0 {
+			ctx = schema.WithAuthor(ctx, usernames[0])
+		}
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,525 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/metadata/schema"
+)
+
+// schemaBundleVersion is the format version of the bundles produced by Export.
+const schemaBundleVersion = "v1"
+
+func (rs *groupRegistryServer) Export(ctx context.Context, req *databasev1.GroupRegistryServiceExportRequest) (
+	*databasev1.GroupRegistryServiceExportResponse, error,
+) {
+	g := req.GetGroup()
+	rs.metrics.totalRegistryStarted.Inc(1, g, "group", "export")
+	start := time.Now()
+	defer func() {
+		rs.metrics.totalRegistryFinished.Inc(1, g, "group", "export")
+		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "group", "export")
+	}()
+	bundle, err := rs.exportBundle(ctx, g)
+	if err != nil {
+		rs.metrics.totalRegistryErr.Inc(1, g, "group", "export")
+		return nil, err
+	}
+	return &databasev1.GroupRegistryServiceExportResponse{
+		Bundle: bundle,
+	}, nil
+}
+
+func (rs *groupRegistryServer) exportBundle(ctx context.Context, g string) (*databasev1.SchemaBundle, error) {
+	group, err := rs.schemaRegistry.GroupRegistry().GetGroup(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	group = proto.Clone(group).(*commonv1.Group)
+	clearServerMaintained(group.Metadata)
+	group.UpdatedAt = nil
+	bundle := &databasev1.SchemaBundle{
+		Version:    schemaBundleVersion,
+		ExportedAt: timestamppb.Now(),
+		Group:      group,
+	}
+	opt := schema.ListOpt{Group: g}
+	streams, err := rs.schemaRegistry.StreamRegistry().ListStream(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range streams {
+		if isServerMaintained(s.GetMetadata()) {
+			continue
+		}
+		s = proto.Clone(s).(*databasev1.Stream)
+		clearServerMaintained(s.Metadata)
+		s.UpdatedAt = nil
+		bundle.Streams = append(bundle.Streams, s)
+	}
+	measures, err := rs.schemaRegistry.MeasureRegistry().ListMeasure(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range measures {
+		if isServerMaintained(m.GetMetadata()) {
+			continue
+		}
+		m = proto.Clone(m).(*databasev1.Measure)
+		clearServerMaintained(m.Metadata)
+		m.UpdatedAt = nil
+		bundle.Measures = append(bundle.Measures, m)
+	}
+	traces, err := rs.schemaRegistry.TraceRegistry().ListTrace(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range traces {
+		if isServerMaintained(t.GetMetadata()) {
+			continue
+		}
+		t = proto.Clone(t).(*databasev1.Trace)
+		clearServerMaintained(t.Metadata)
+		t.UpdatedAt = nil
+		bundle.Traces = append(bundle.Traces, t)
+	}
+	properties, err := rs.schemaRegistry.PropertyRegistry().ListProperty(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range properties {
+		if isServerMaintained(p.GetMetadata()) {
+			continue
+		}
+		p = proto.Clone(p).(*databasev1.Property)
+		clearServerMaintained(p.Metadata)
+		p.UpdatedAt = nil
+		bundle.Properties = append(bundle.Properties, p)
+	}
+	indexRules, err := rs.schemaRegistry.IndexRuleRegistry().ListIndexRule(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, ir := range indexRules {
+		if isServerMaintained(ir.GetMetadata()) {
+			continue
+		}
+		ir = proto.Clone(ir).(*databasev1.IndexRule)
+		clearServerMaintained(ir.Metadata)
+		ir.UpdatedAt = nil
+		bundle.IndexRules = append(bundle.IndexRules, ir)
+	}
+	bindings, err := rs.schemaRegistry.IndexRuleBindingRegistry().ListIndexRuleBinding(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, irb := range bindings {
+		if isServerMaintained(irb.GetMetadata()) {
+			continue
+		}
+		irb = proto.Clone(irb).(*databasev1.IndexRuleBinding)
+		clearServerMaintained(irb.Metadata)
+		irb.UpdatedAt = nil
+		bundle.IndexRuleBindings = append(bundle.IndexRuleBindings, irb)
+	}
+	topNAggs, err := rs.schemaRegistry.TopNAggregationRegistry().ListTopNAggregation(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, tn := range topNAggs {
+		if isServerMaintained(tn.GetMetadata()) {
+			continue
+		}
+		tn = proto.Clone(tn).(*databasev1.TopNAggregation)
+		clearServerMaintained(tn.Metadata)
+		tn.UpdatedAt = nil
+		bundle.TopnAggregations = append(bundle.TopnAggregations, tn)
+	}
+	return bundle, nil
+}
+
+// isServerMaintained returns whether the object is created and maintained by the server, like the "_top_n_result" measure.
+// They are created along with their groups, so they are neither exported nor imported.
+func isServerMaintained(md *commonv1.Metadata) bool {
+	return strings.HasPrefix(md.GetName(), "_")
+}
+
+func clearServerMaintained(md *commonv1.Metadata) {
+	if md == nil {
+		return
+	}
+	md.Id = 0
+	md.CreateRevision = 0
+	md.ModRevision = 0
+}
+
+func (rs *groupRegistryServer) Import(ctx context.Context, req *databasev1.GroupRegistryServiceImportRequest) (
+	*databasev1.GroupRegistryServiceImportResponse, error,
+) {
+	g := req.GetTargetGroup()
+	if g == "" {
+		g = req.GetBundle().GetGroup().GetMetadata().GetName()
+	}
+	rs.metrics.totalRegistryStarted.Inc(1, g, "group", "import")
+	start := time.Now()
+	defer func() {
+		rs.metrics.totalRegistryFinished.Inc(1, g, "group", "import")
+		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "group", "import")
+	}()
+	resp, err := rs.importBundle(ctx, req.GetBundle(), g, req.GetOverwrite())
+	if err != nil {
+		rs.metrics.totalRegistryErr.Inc(1, g, "group", "import")
+		return nil, err
+	}
+	return resp, nil
+}
+
+// bundleObject is a schema object of a bundle to be imported.
+type bundleObject struct {
+	// get loads the existing object, which is restored if the import fails.
+	get    func(ctx context.Context) error
+	create func(ctx context.Context) error
+	update func(ctx context.Context) error
+	// remove deletes the object created by the import.
+	remove func(ctx context.Context) error
+	// restore reverts the object updated by the import.
+	restore func(ctx context.Context) error
+	record  func(info *databasev1.SchemaInfo)
+	key     string
+}
+
+// newBundleObject builds the bundleObject of obj from the registry functions of its kind.
+func newBundleObject[T proto.Message](key string, md *commonv1.Metadata, obj T,
+	get func(context.Context, *commonv1.Metadata) (T, error),
+	create, update func(context.Context, T) error,
+	remove func(context.Context, *commonv1.Metadata) (bool, error),
+	record func(info *databasev1.SchemaInfo),
+) bundleObject {
+	var prev T
+	return bundleObject{
+		key: key,
+		get: func(ctx context.Context) error {
+			cur, err := get(ctx, md)
+			if err == nil {
+				prev = cur
+			}
+			return err
+		},
+		create: func(ctx context.Context) error { return create(ctx, obj) },
+		update: func(ctx context.Context) error { return update(ctx, obj) },
+		remove: func(ctx context.Context) error {
+			_, err := remove(ctx, md)
+			return err
+		},
+		restore: func(ctx context.Context) error { return update(ctx, prev) },
+		record:  record,
+	}
+}
+
+// ignoreRevision adapts the registry functions returning the revision.
+func ignoreRevision[T any](f func(context.Context, T) (int64, error)) func(context.Context, T) error {
+	return func(ctx context.Context, obj T) error {
+		_, err := f(ctx, obj)
+		return err
+	}
+}
+
+func (rs *groupRegistryServer) importBundle(ctx context.Context, bundle *databasev1.SchemaBundle, g string, overwrite bool) (
+	*databasev1.GroupRegistryServiceImportResponse, error,
+) {
+	if bundle == nil || bundle.GetGroup() == nil {
+		return nil, schema.BadRequest("bundle", "bundle should contain a group")
+	}
+	if bundle.GetVersion() != schemaBundleVersion {
+		return nil, schema.BadRequest("bundle.version", "unsupported bundle version "+bundle.GetVersion())
+	}
+	if g == "" {
+		return nil, schema.BadRequest("target_group", "target group should not be empty")
+	}
+	if g == internalDeletionTaskGroup {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot import into internal system group %s", g)
+	}
+	bundle = proto.Clone(bundle).(*databasev1.SchemaBundle)
+	objects := rs.bundleObjects(bundle, g)
+	if err := rs.validateBundle(ctx, bundle, g); err != nil {
+		return nil, err
+	}
+	exists := make([]bool, len(objects))
+	var conflicts []string
+	for i, o := range objects {
+		err := o.get(ctx)
+		switch {
+		case err == nil:
+			exists[i] = true
+			conflicts = append(conflicts, o.key)
+		case errors.Is(err, schema.ErrGRPCResourceNotFound):
+		default:
+			return nil, err
+		}
+	}
+	if !overwrite && len(conflicts) > 0 {
+		return nil, status.Errorf(codes.AlreadyExists,
+			"%s already exist, use overwrite=true to update them", strings.Join(conflicts, ", "))
+	}
+	resp := &databasev1.GroupRegistryServiceImportResponse{
+		Created: &databasev1.SchemaInfo{},
+		Updated: &databasev1.SchemaInfo{},
+	}
+	for i, o := range objects {
+		var err error
+		if exists[i] {
+			err = o.update(ctx)
+		} else {
+			err = o.create(ctx)
+		}
+		if err != nil {
+			return nil, rs.rollbackImport(ctx, objects[:i], exists[:i], o.key, err)
+		}
+		if exists[i] {
+			o.record(resp.Updated)
+		} else {
+			o.record(resp.Created)
+		}
+	}
+	return resp, nil
+}
+
+// validateBundle checks the objects of the bundle, and the objects they refer to, before any of them is imported.
+func (rs *groupRegistryServer) validateBundle(ctx context.Context, bundle *databasev1.SchemaBundle, g string) error {
+	if err := bundle.ValidateAll(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	subjects := make(map[string]struct{})
+	for _, s := range bundle.GetStreams() {
+		subjects[commonv1.Catalog_CATALOG_STREAM.String()+"/"+s.GetMetadata().GetName()] = struct{}{}
+	}
+	measures := make(map[string]struct{})
+	for _, m := range bundle.GetMeasures() {
+		subjects[commonv1.Catalog_CATALOG_MEASURE.String()+"/"+m.GetMetadata().GetName()] = struct{}{}
+		measures[m.GetMetadata().GetName()] = struct{}{}
+	}
+	for _, t := range bundle.GetTraces() {
+		subjects[commonv1.Catalog_CATALOG_TRACE.String()+"/"+t.GetMetadata().GetName()] = struct{}{}
+	}
+	rules := make(map[string]struct{})
+	for _, ir := range bundle.GetIndexRules() {
+		rules[ir.GetMetadata().GetName()] = struct{}{}
+	}
+	// exists checks an object referred to by the bundle, but not included in it, is in the target group.
+	exists := func(key string, get func() error) error {
+		err := get()
+		if errors.Is(err, schema.ErrGRPCResourceNotFound) {
+			return schema.BadRequest("bundle", key+" is neither in the bundle nor in group "+g)
+		}
+		return err
+	}
+	repo := rs.schemaRegistry
+	for _, irb := range bundle.GetIndexRuleBindings() {
+		for _, r := range irb.GetRules() {
+			if _, ok := rules[r]; ok {
+				continue
+			}
+			if err := exists("index rule "+r, func() error {
+				_, err := repo.IndexRuleRegistry().GetIndexRule(ctx, &commonv1.Metadata{Group: g, Name: r})
+				return err
+			}); err != nil {
+				return err
+			}
+		}
+		sub := irb.GetSubject()
+		if _, ok := subjects[sub.GetCatalog().String()+"/"+sub.GetName()]; ok {
+			continue
+		}
+		md := &commonv1.Metadata{Group: g, Name: sub.GetName()}
+		if err := exists(strings.ToLower(strings.TrimPrefix(sub.GetCatalog().String(), "CATALOG_"))+" "+sub.GetName(), func() error {
+			var err error
+			switch sub.GetCatalog() {
+			case commonv1.Catalog_CATALOG_STREAM:
+				_, err = repo.StreamRegistry().GetStream(ctx, md)
+			case commonv1.Catalog_CATALOG_MEASURE:
+				_, err = repo.MeasureRegistry().GetMeasure(ctx, md)
+			case commonv1.Catalog_CATALOG_TRACE:
+				_, err = repo.TraceRegistry().GetTrace(ctx, md)
+			default:
+				return schema.BadRequest("bundle", "unsupported subject catalog "+sub.GetCatalog().String())
+			}
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	for _, tn := range bundle.GetTopnAggregations() {
+		sm := tn.GetSourceMeasure()
+		if sm.GetGroup() != g {
+			continue
+		}
+		if _, ok := measures[sm.GetName()]; ok {
+			continue
+		}
+		if err := exists("measure "+sm.GetName(), func() error {
+			_, err := repo.MeasureRegistry().GetMeasure(ctx, sm)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackImport removes the objects created by a failed import and restores the ones it updated, in the reverse order.
+// The returned error lists the objects which are left changed if the rollback fails too.
+func (rs *groupRegistryServer) rollbackImport(ctx context.Context, done []bundleObject, updated []bool, failed string, cause error) error {
+	var left []string
+	for i := len(done) - 1; i >= 0; i-- {
+		o := done[i]
+		var err error
+		if updated[i] {
+			err = o.restore(ctx)
+		} else {
+			err = o.remove(ctx)
+		}
+		if err != nil {
+			left = append(left, fmt.Sprintf("%s (%v)", o.key, err))
+		}
+	}
+	if len(left) > 0 {
+		return status.Errorf(status.Code(cause), "failed to import %s: %v, and failed to roll back %s",
+			failed, cause, strings.Join(left, ", "))
+	}
+	return status.Errorf(status.Code(cause), "failed to import %s: %v, the imported objects are rolled back", failed, cause)
+}
+
+// bundleObjects renames the objects of the bundle into the group g,
+// and lists them in the order of their dependencies.
+func (rs *groupRegistryServer) bundleObjects(bundle *databasev1.SchemaBundle, g string) []bundleObject {
+	source := bundle.GetGroup().GetMetadata().GetName()
+	repo := rs.schemaRegistry
+	group := bundle.GetGroup()
+	if group.Metadata == nil {
+		group.Metadata = &commonv1.Metadata{}
+	}
+	group.Metadata.Name = g
+	groups := repo.GroupRegistry()
+	objects := []bundleObject{newBundleObject("group "+g, group.Metadata, group,
+		func(ctx context.Context, md *commonv1.Metadata) (*commonv1.Group, error) {
+			return groups.GetGroup(ctx, md.GetName())
+		},
+		groups.CreateGroup, groups.UpdateGroup,
+		func(ctx context.Context, md *commonv1.Metadata) (bool, error) {
+			return groups.DeleteGroup(ctx, md.GetName())
+		},
+		func(*databasev1.SchemaInfo) {},
+	)}
+	rename := func(md *commonv1.Metadata) *commonv1.Metadata {
+		if md == nil {
+			md = &commonv1.Metadata{}
+		}
+		md.Group = g
+		return md
+	}
+	for _, s := range bundle.GetStreams() {
+		if isServerMaintained(s.GetMetadata()) {
+			continue
+		}
+		s.Metadata = rename(s.Metadata)
+		r := repo.StreamRegistry()
+		objects = append(objects, newBundleObject("stream "+s.Metadata.GetName(), s.Metadata, s,
+			r.GetStream, ignoreRevision(r.CreateStream), ignoreRevision(r.UpdateStream), r.DeleteStream,
+			func(info *databasev1.SchemaInfo) { info.Streams = append(info.Streams, s.Metadata.GetName()) }))
+	}
+	for _, m := range bundle.GetMeasures() {
+		if isServerMaintained(m.GetMetadata()) {
+			continue
+		}
+		m.Metadata = rename(m.Metadata)
+		r := repo.MeasureRegistry()
+		objects = append(objects, newBundleObject("measure "+m.Metadata.GetName(), m.Metadata, m,
+			r.GetMeasure, ignoreRevision(r.CreateMeasure), ignoreRevision(r.UpdateMeasure), r.DeleteMeasure,
+			func(info *databasev1.SchemaInfo) { info.Measures = append(info.Measures, m.Metadata.GetName()) }))
+	}
+	for _, t := range bundle.GetTraces() {
+		if isServerMaintained(t.GetMetadata()) {
+			continue
+		}
+		t.Metadata = rename(t.Metadata)
+		r := repo.TraceRegistry()
+		objects = append(objects, newBundleObject("trace "+t.Metadata.GetName(), t.Metadata, t,
+			r.GetTrace, ignoreRevision(r.CreateTrace), ignoreRevision(r.UpdateTrace), r.DeleteTrace,
+			func(info *databasev1.SchemaInfo) { info.Traces = append(info.Traces, t.Metadata.GetName()) }))
+	}
+	for _, p := range bundle.GetProperties() {
+		if isServerMaintained(p.GetMetadata()) {
+			continue
+		}
+		p.Metadata = rename(p.Metadata)
+		r := repo.PropertyRegistry()
+		objects = append(objects, newBundleObject("property "+p.Metadata.GetName(), p.Metadata, p,
+			r.GetProperty, r.CreateProperty, r.UpdateProperty, r.DeleteProperty,
+			func(info *databasev1.SchemaInfo) { info.Properties = append(info.Properties, p.Metadata.GetName()) }))
+	}
+	for _, ir := range bundle.GetIndexRules() {
+		if isServerMaintained(ir.GetMetadata()) {
+			continue
+		}
+		ir.Metadata = rename(ir.Metadata)
+		r := repo.IndexRuleRegistry()
+		objects = append(objects, newBundleObject("index rule "+ir.Metadata.GetName(), ir.Metadata, ir,
+			r.GetIndexRule, r.CreateIndexRule, r.UpdateIndexRule, r.DeleteIndexRule,
+			func(info *databasev1.SchemaInfo) { info.IndexRules = append(info.IndexRules, ir.Metadata.GetName()) }))
+	}
+	for _, irb := range bundle.GetIndexRuleBindings() {
+		if isServerMaintained(irb.GetMetadata()) {
+			continue
+		}
+		irb.Metadata = rename(irb.Metadata)
+		r := repo.IndexRuleBindingRegistry()
+		objects = append(objects, newBundleObject("index rule binding "+irb.Metadata.GetName(), irb.Metadata, irb,
+			r.GetIndexRuleBinding, r.CreateIndexRuleBinding, r.UpdateIndexRuleBinding, r.DeleteIndexRuleBinding,
+			func(info *databasev1.SchemaInfo) {
+				info.IndexRuleBindings = append(info.IndexRuleBindings, irb.Metadata.GetName())
+			}))
+	}
+	for _, tn := range bundle.GetTopnAggregations() {
+		if isServerMaintained(tn.GetMetadata()) {
+			continue
+		}
+		tn.Metadata = rename(tn.Metadata)
+		if sm := tn.GetSourceMeasure(); sm != nil && sm.GetGroup() == source {
+			sm.Group = g
+		}
+		r := repo.TopNAggregationRegistry()
+		objects = append(objects, newBundleObject("topn aggregation "+tn.Metadata.GetName(), tn.Metadata, tn,
+			r.GetTopNAggregation, r.CreateTopNAggregation, r.UpdateTopNAggregation, r.DeleteTopNAggregation,
+			func(info *databasev1.SchemaInfo) {
+				info.TopnAggregations = append(info.TopnAggregations, tn.Metadata.GetName())
+			}))
+	}
+	return objects
+}
@@ -0,0 +1,320 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/api/validate"
+	"github.com/apache/skywalking-banyandb/banyand/metadata"
+	"github.com/apache/skywalking-banyandb/banyand/metadata/schema"
+)
+
+// checkCreate reports whether a schema object can be created without creating it.
+// The object is rejected if it's invalid, its group is absent or it already exists.
+func checkCreate(ctx context.Context, repo metadata.Repo, kind, group string, validErr error, get func() error) (*databasev1.CompatibilityReport, error) {
+	c := schema.NewCompatibility()
+	if validErr != nil {
+		c.Reject(validErr)
+	}
+	if group != "" {
+		if _, err := repo.GroupRegistry().GetGroup(ctx, group); err != nil {
+			if !errors.Is(err, schema.ErrGRPCResourceNotFound) {
+				return nil, err
+			}
+			c.Reject(fmt.Errorf("group %s is not found", group))
+		}
+	}
+	if err := get(); err == nil {
+		c.Reject(fmt.Errorf("%s already exists", kind))
+	} else if !errors.Is(err, schema.ErrGRPCResourceNotFound) {
+		return nil, err
+	}
+	c.Report.Changes = append(c.Report.Changes, &databasev1.SchemaChange{Description: kind + " is created"})
+	return c.Report, nil
+}
+
+// checkUpdate compares a schema object with the stored one without updating it.
+func checkUpdate[T any](validErr error, get func() (T, error), check func(prev T) *schema.Compatibility) (*schema.Compatibility, error) {
+	prev, err := get()
+	if err != nil {
+		if !errors.Is(err, schema.ErrGRPCResourceNotFound) {
+			return nil, err
+		}
+		c := schema.NewCompatibility()
+		c.Reject(err)
+		return c, nil
+	}
+	c := check(prev)
+	if validErr != nil {
+		c.Reject(validErr)
+	}
+	return c, nil
+}
+
+// affectedIndexRuleBindings lists the bindings of the resource whose index rules are affected by the changes.
+func affectedIndexRuleBindings(ctx context.Context, repo metadata.Repo, catalog commonv1.Catalog,
+	md *commonv1.Metadata, c *schema.Compatibility,
+) ([]string, error) {
+	if len(c.ChangedTags) == 0 {
+		return nil, nil
+	}
+	rules, err := repo.IndexRuleRegistry().ListIndexRule(ctx, schema.ListOpt{Group: md.GetGroup()})
+	if err != nil {
+		return nil, err
+	}
+	affectedRules := make(map[string]struct{})
+	for _, r := range rules {
+		if c.AffectIndexRule(r) {
+			affectedRules[r.GetMetadata().GetName()] = struct{}{}
+		}
+	}
+	if len(affectedRules) == 0 {
+		return nil, nil
+	}
+	bindings, err := repo.IndexRuleBindingRegistry().ListIndexRuleBinding(ctx, schema.ListOpt{Group: md.GetGroup()})
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, b := range bindings {
+		if b.GetSubject().GetCatalog() != catalog || b.GetSubject().GetName() != md.GetName() {
+			continue
+		}
+		for _, r := range b.GetRules() {
+			if _, ok := affectedRules[r]; ok {
+				result = append(result, b.GetMetadata().GetName())
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func hasBreakingChange(report *databasev1.CompatibilityReport) bool {
+	for _, change := range report.GetChanges() {
+		if change.GetBreaking() {
+			return true
+		}
+	}
+	return false
+}
+
+func (rs *streamRegistryServer) dryRunCreate(ctx context.Context, stream *databasev1.Stream) (*databasev1.CompatibilityReport, error) {
+	return checkCreate(ctx, rs.schemaRegistry, "stream", stream.GetMetadata().GetGroup(), validate.Stream(stream), func() error {
+		_, err := rs.schemaRegistry.StreamRegistry().GetStream(ctx, stream.GetMetadata())
+		return err
+	})
+}
+
+func (rs *streamRegistryServer) dryRunUpdate(ctx context.Context, stream *databasev1.Stream) (*databasev1.CompatibilityReport, error) {
+	c, err := checkUpdate(validate.Stream(stream), func() (*databasev1.Stream, error) {
+		return rs.schemaRegistry.StreamRegistry().GetStream(ctx, stream.GetMetadata())
+	}, func(prev *databasev1.Stream) *schema.Compatibility {
+		return schema.CheckStreamUpdate(prev, stream)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.Report.AffectedIndexRuleBindings, err = affectedIndexRuleBindings(ctx, rs.schemaRegistry,
+		commonv1.Catalog_CATALOG_STREAM, stream.GetMetadata(), c); err != nil {
+		return nil, err
+	}
+	return c.Report, nil
+}
+
+func (rs *measureRegistryServer) dryRunCreate(ctx context.Context, measure *databasev1.Measure) (*databasev1.CompatibilityReport, error) {
+	return checkCreate(ctx, rs.schemaRegistry, "measure", measure.GetMetadata().GetGroup(), validate.Measure(measure), func() error {
+		_, err := rs.schemaRegistry.MeasureRegistry().GetMeasure(ctx, measure.GetMetadata())
+		return err
+	})
+}
+
+func (rs *measureRegistryServer) dryRunUpdate(ctx context.Context, measure *databasev1.Measure) (*databasev1.CompatibilityReport, error) {
+	c, err := checkUpdate(validate.Measure(measure), func() (*databasev1.Measure, error) {
+		return rs.schemaRegistry.MeasureRegistry().GetMeasure(ctx, measure.GetMetadata())
+	}, func(prev *databasev1.Measure) *schema.Compatibility {
+		return schema.CheckMeasureUpdate(prev, measure)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.Report.AffectedIndexRuleBindings, err = affectedIndexRuleBindings(ctx, rs.schemaRegistry,
+		commonv1.Catalog_CATALOG_MEASURE, measure.GetMetadata(), c); err != nil {
+		return nil, err
+	}
+	if len(c.ChangedTags) == 0 && len(c.ChangedFields) == 0 {
+		return c.Report, nil
+	}
+	topNs, err := rs.schemaRegistry.MeasureRegistry().TopNAggregations(ctx, measure.GetMetadata())
+	if err != nil {
+		return nil, err
+	}
+	for _, topN := range topNs {
+		if c.AffectTopNAggregation(topN) {
+			c.Report.AffectedTopnAggregations = append(c.Report.AffectedTopnAggregations, topN.GetMetadata().GetName())
+		}
+	}
+	return c.Report, nil
+}
+
+func (rs *traceRegistryServer) dryRunCreate(ctx context.Context, trace *databasev1.Trace) (*databasev1.CompatibilityReport, error) {
+	return checkCreate(ctx, rs.schemaRegistry, "trace", trace.GetMetadata().GetGroup(), validate.Trace(trace), func() error {
+		_, err := rs.schemaRegistry.TraceRegistry().GetTrace(ctx, trace.GetMetadata())
+		return err
+	})
+}
+
+func (rs *traceRegistryServer) dryRunUpdate(ctx context.Context, trace *databasev1.Trace) (*databasev1.CompatibilityReport, error) {
+	c, err := checkUpdate(validate.Trace(trace), func() (*databasev1.Trace, error) {
+		return rs.schemaRegistry.TraceRegistry().GetTrace(ctx, trace.GetMetadata())
+	}, func(prev *databasev1.Trace) *schema.Compatibility {
+		return schema.CheckTraceUpdate(prev, trace)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.Report.AffectedIndexRuleBindings, err = affectedIndexRuleBindings(ctx, rs.schemaRegistry,
+		commonv1.Catalog_CATALOG_TRACE, trace.GetMetadata(), c); err != nil {
+		return nil, err
+	}
+	return c.Report, nil
+}
+
+func (rs *indexRuleRegistryServer) dryRunCreate(ctx context.Context, indexRule *databasev1.IndexRule) (*databasev1.CompatibilityReport, error) {
+	return checkCreate(ctx, rs.schemaRegistry, "index rule", indexRule.GetMetadata().GetGroup(), validate.IndexRule(indexRule), func() error {
+		_, err := rs.schemaRegistry.IndexRuleRegistry().GetIndexRule(ctx, indexRule.GetMetadata())
+		return err
+	})
+}
+
+func (rs *indexRuleRegistryServer) dryRunUpdate(ctx context.Context, indexRule *databasev1.IndexRule) (*databasev1.CompatibilityReport, error) {
+	c, err := checkUpdate(validate.IndexRule(indexRule), func() (*databasev1.IndexRule, error) {
+		return rs.schemaRegistry.IndexRuleRegistry().GetIndexRule(ctx, indexRule.GetMetadata())
+	}, func(prev *databasev1.IndexRule) *schema.Compatibility {
+		return schema.CheckUpdate(schema.KindIndexRule, prev, indexRule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !hasBreakingChange(c.Report) {
+		return c.Report, nil
+	}
+	bindings, err := rs.schemaRegistry.IndexRuleBindingRegistry().ListIndexRuleBinding(ctx, schema.ListOpt{Group: indexRule.GetMetadata().GetGroup()})
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bindings {
+		for _, r := range b.GetRules() {
+			if r == indexRule.GetMetadata().GetName() {
+				c.Report.AffectedIndexRuleBindings = append(c.Report.AffectedIndexRuleBindings, b.GetMetadata().GetName())
+				break
+			}
+		}
+	}
+	return c.Report, nil
+}
+
+func (rs *indexRuleBindingRegistryServer) dryRunCreate(ctx context.Context,
+	indexRuleBinding *databasev1.IndexRuleBinding,
+) (*databasev1.CompatibilityReport, error) {
+	return checkCreate(ctx, rs.schemaRegistry, "index rule binding", indexRuleBinding.GetMetadata().GetGroup(),
+		validate.IndexRuleBinding(indexRuleBinding), func() error {
+			_, err := rs.schemaRegistry.IndexRuleBindingRegistry().GetIndexRuleBinding(ctx, indexRuleBinding.GetMetadata())
+			return err
+		})
+}
+
+func (rs *indexRuleBindingRegistryServer) dryRunUpdate(ctx context.Context,
+	indexRuleBinding *databasev1.IndexRuleBinding,
+) (*databasev1.CompatibilityReport, error) {
+	c, err := checkUpdate(validate.IndexRuleBinding(indexRuleBinding), func() (*databasev1.IndexRuleBinding, error) {
+		return rs.schemaRegistry.IndexRuleBindingRegistry().GetIndexRuleBinding(ctx, indexRuleBinding.GetMetadata())
+	}, func(prev *databasev1.IndexRuleBinding) *schema.Compatibility {
+		return schema.CheckUpdate(schema.KindIndexRuleBinding, prev, indexRuleBinding)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.Report, nil
+}
+
+func (ts *topNAggregationRegistryServer) dryRunCreate(ctx context.Context,
+	topN *databasev1.TopNAggregation,
+) (*databasev1.CompatibilityReport, error) {
+	return checkCreate(ctx, ts.schemaRegistry, "topN aggregation", topN.GetMetadata().GetGroup(), validate.TopNAggregation(topN), func() error {
+		_, err := ts.schemaRegistry.TopNAggregationRegistry().GetTopNAggregation(ctx, topN.GetMetadata())
+		return err
+	})
+}
+
+func (ts *topNAggregationRegistryServer) dryRunUpdate(ctx context.Context,
+	topN *databasev1.TopNAggregation,
+) (*databasev1.CompatibilityReport, error) {
+	c, err := checkUpdate(validate.TopNAggregation(topN), func() (*databasev1.TopNAggregation, error) {
+		return ts.schemaRegistry.TopNAggregationRegistry().GetTopNAggregation(ctx, topN.GetMetadata())
+	}, func(prev *databasev1.TopNAggregation) *schema.Compatibility {
+		return schema.CheckUpdate(schema.KindTopNAggregation, prev, topN)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.Report, nil
+}
+
+func (rs *groupRegistryServer) dryRunCreate(ctx context.Context, group *commonv1.Group) (*databasev1.CompatibilityReport, error) {
+	return checkCreate(ctx, rs.schemaRegistry, "group", "", validate.Group(group), func() error {
+		_, err := rs.schemaRegistry.GroupRegistry().GetGroup(ctx, group.GetMetadata().GetName())
+		return err
+	})
+}
+
+func (rs *groupRegistryServer) dryRunUpdate(ctx context.Context, group *commonv1.Group) (*databasev1.CompatibilityReport, error) {
+	c, err := checkUpdate(validate.Group(group), func() (*commonv1.Group, error) {
+		return rs.schemaRegistry.GroupRegistry().GetGroup(ctx, group.GetMetadata().GetName())
+	}, func(prev *commonv1.Group) *schema.Compatibility {
+		return schema.CheckUpdate(schema.KindGroup, prev, group)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.Report, nil
+}
+
+func (ps *propertyRegistryServer) dryRunCreate(ctx context.Context, property *databasev1.Property) (*databasev1.CompatibilityReport, error) {
+	return checkCreate(ctx, ps.schemaRegistry, "property", property.GetMetadata().GetGroup(), nil, func() error {
+		_, err := ps.schemaRegistry.PropertyRegistry().GetProperty(ctx, property.GetMetadata())
+		return err
+	})
+}
+
+func (ps *propertyRegistryServer) dryRunUpdate(ctx context.Context, property *databasev1.Property) (*databasev1.CompatibilityReport, error) {
+	c, err := checkUpdate(nil, func() (*databasev1.Property, error) {
+		return ps.schemaRegistry.PropertyRegistry().GetProperty(ctx, property.GetMetadata())
+	}, func(prev *databasev1.Property) *schema.Compatibility {
+		return schema.CheckUpdate(schema.KindProperty, prev, property)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.Report, nil
+}
@@ -0,0 +1,165 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/api/data"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/banyand/queue"
+	"github.com/apache/skywalking-banyandb/pkg/bus"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const (
+	defaultTagValuesLimit = 100
+	maxTagValuesLimit     = 10000
+
+	defaultSeriesPageSize  = 100
+	maxSeriesPageSize      = 10000
+	defaultCardinalityTopN = 10
+)
+
+type seriesExplorerServer struct {
+	databasev1.UnimplementedSeriesExplorerServiceServer
+	broadcaster queue.Client
+	groupRepo   *groupRepo
+}
+
+func (es *seriesExplorerServer) TagValues(ctx context.Context, req *databasev1.SeriesExplorerServiceTagValuesRequest) (
+	*databasev1.SeriesExplorerServiceTagValuesResponse, error,
+) {
+	if req.GetGroup() == "" || req.GetName() == "" || req.GetTag() == "" {
+		return nil, status.Error(codes.InvalidArgument, "group, name and tag are required")
+	}
+	if req.GetTimeRange() == nil {
+		req.TimeRange = timestamp.DefaultTimeRange
+	}
+	if err := timestamp.CheckTimeRange(req.GetTimeRange()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v is invalid :%s", req.GetTimeRange(), err)
+	}
+	if req.GetLimit() == 0 {
+		req.Limit = defaultTagValuesLimit
+	}
+	if req.GetLimit() > maxTagValuesLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit %d exceeds the max %d", req.GetLimit(), maxTagValuesLimit)
+	}
+	d, err := es.publish(ctx, data.TopicTagValues, req.GetGroup(), req)
+	if err != nil {
+		return nil, err
+	}
+	if resp, ok := d.(*databasev1.SeriesExplorerServiceTagValuesResponse); ok {
+		return resp, nil
+	}
+	return &databasev1.SeriesExplorerServiceTagValuesResponse{}, nil
+}
+
+func (es *seriesExplorerServer) ListSeries(ctx context.Context, req *databasev1.SeriesExplorerServiceListSeriesRequest) (
+	*databasev1.SeriesExplorerServiceListSeriesResponse, error,
+) {
+	if req.GetGroup() == "" || req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "group and name are required")
+	}
+	if req.GetTimeRange() == nil {
+		req.TimeRange = timestamp.DefaultTimeRange
+	}
+	if err := timestamp.CheckTimeRange(req.GetTimeRange()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v is invalid :%s", req.GetTimeRange(), err)
+	}
+	if req.GetPageSize() == 0 {
+		req.PageSize = defaultSeriesPageSize
+	}
+	if req.GetPageSize() > maxSeriesPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page size %d exceeds the max %d", req.GetPageSize(), maxSeriesPageSize)
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(req.GetPageToken()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "page token %s is invalid", req.GetPageToken())
+	}
+	d, err := es.publish(ctx, data.TopicListSeries, req.GetGroup(), req)
+	if err != nil {
+		return nil, err
+	}
+	if resp, ok := d.(*databasev1.SeriesExplorerServiceListSeriesResponse); ok {
+		return resp, nil
+	}
+	return &databasev1.SeriesExplorerServiceListSeriesResponse{}, nil
+}
+
+func (es *seriesExplorerServer) SeriesCardinality(ctx context.Context, req *databasev1.SeriesExplorerServiceSeriesCardinalityRequest) (
+	*databasev1.SeriesExplorerServiceSeriesCardinalityResponse, error,
+) {
+	if req.GetGroup() == "" || req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "group and name are required")
+	}
+	if req.GetTimeRange() == nil {
+		req.TimeRange = timestamp.DefaultTimeRange
+	}
+	if err := timestamp.CheckTimeRange(req.GetTimeRange()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v is invalid :%s", req.GetTimeRange(), err)
+	}
+	if req.GetTopN() == 0 {
+		req.TopN = defaultCardinalityTopN
+	}
+	d, err := es.publish(ctx, data.TopicSeriesCardinality, req.GetGroup(), req)
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := d.(*databasev1.SeriesExplorerServiceSeriesCardinalityResponse)
+	if !ok {
+		return &databasev1.SeriesExplorerServiceSeriesCardinalityResponse{}, nil
+	}
+	// Merging the buckets of a standalone server strips the sketches, which are internal to the cluster.
+	buckets, err := storage.MergeSeriesCardinality([][]*databasev1.SeriesCardinalityBucket{resp.GetBuckets()}, int(req.GetTopN()))
+	if err != nil {
+		return nil, err
+	}
+	return &databasev1.SeriesExplorerServiceSeriesCardinalityResponse{Buckets: buckets}, nil
+}
+
+// publish sends the request to the query pipeline. It returns nil if there is no data to explore.
+func (es *seriesExplorerServer) publish(ctx context.Context, topic bus.Topic, group string, req any) (any, error) {
+	if acquireErr := es.groupRepo.acquireRequest(group); acquireErr != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "group %s is pending deletion", group)
+	}
+	defer es.groupRepo.releaseRequest(group)
+	feat, err := es.broadcaster.Publish(ctx, topic, bus.NewMessage(bus.MessageID(time.Now().UnixNano()), req))
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	msg, err := feat.Get()
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := msg.Data().(*common.Error); ok {
+		return nil, errors.WithMessage(errQueryMsg, d.Error())
+	}
+	return msg.Data(), nil
+}
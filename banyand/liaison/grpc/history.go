@@ -0,0 +1,154 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/metadata"
+	"github.com/apache/skywalking-banyandb/banyand/metadata/schema"
+)
+
+var schemaKinds = map[databasev1.SchemaKind]schema.Kind{
+	databasev1.SchemaKind_SCHEMA_KIND_GROUP:              schema.KindGroup,
+	databasev1.SchemaKind_SCHEMA_KIND_STREAM:             schema.KindStream,
+	databasev1.SchemaKind_SCHEMA_KIND_MEASURE:            schema.KindMeasure,
+	databasev1.SchemaKind_SCHEMA_KIND_TRACE:              schema.KindTrace,
+	databasev1.SchemaKind_SCHEMA_KIND_INDEX_RULE:         schema.KindIndexRule,
+	databasev1.SchemaKind_SCHEMA_KIND_INDEX_RULE_BINDING: schema.KindIndexRuleBinding,
+	databasev1.SchemaKind_SCHEMA_KIND_TOPN_AGGREGATION:   schema.KindTopNAggregation,
+	databasev1.SchemaKind_SCHEMA_KIND_PROPERTY:           schema.KindProperty,
+}
+
+type schemaHistoryServer struct {
+	databasev1.UnimplementedSchemaHistoryServiceServer
+	schemaRegistry metadata.Repo
+	metrics        *metrics
+}
+
+func (hs *schemaHistoryServer) History(ctx context.Context, req *databasev1.SchemaHistoryServiceHistoryRequest) (
+	*databasev1.SchemaHistoryServiceHistoryResponse, error,
+) {
+	kind, ok := schemaKinds[req.GetKind()]
+	if !ok {
+		return nil, schema.BadRequest("kind", "unsupported schema kind "+req.GetKind().String())
+	}
+	g := req.GetMetadata().GetGroup()
+	hs.metrics.totalRegistryStarted.Inc(1, g, kind.String(), "history")
+	start := time.Now()
+	defer func() {
+		hs.metrics.totalRegistryFinished.Inc(1, g, kind.String(), "history")
+		hs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, kind.String(), "history")
+	}()
+	revisions, err := hs.schemaRegistry.HistoryRegistry().ListHistory(ctx, kind, req.GetMetadata())
+	if err != nil {
+		hs.metrics.totalRegistryErr.Inc(1, g, kind.String(), "history")
+		return nil, err
+	}
+	return &databasev1.SchemaHistoryServiceHistoryResponse{
+		Revisions: revisions,
+	}, nil
+}
+
+func (hs *schemaHistoryServer) Rollback(ctx context.Context, req *databasev1.SchemaHistoryServiceRollbackRequest) (
+	*databasev1.SchemaHistoryServiceRollbackResponse, error,
+) {
+	kind, ok := schemaKinds[req.GetKind()]
+	if !ok {
+		return nil, schema.BadRequest("kind", "unsupported schema kind "+req.GetKind().String())
+	}
+	g := req.GetMetadata().GetGroup()
+	hs.metrics.totalRegistryStarted.Inc(1, g, kind.String(), "rollback")
+	start := time.Now()
+	defer func() {
+		hs.metrics.totalRegistryFinished.Inc(1, g, kind.String(), "rollback")
+		hs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, kind.String(), "rollback")
+	}()
+	modRevision, err := hs.rollback(ctx, kind, req.GetMetadata(), req.GetModRevision())
+	if err != nil {
+		hs.metrics.totalRegistryErr.Inc(1, g, kind.String(), "rollback")
+		return nil, err
+	}
+	return &databasev1.SchemaHistoryServiceRollbackResponse{
+		ModRevision: modRevision,
+	}, nil
+}
+
+func (hs *schemaHistoryServer) rollback(ctx context.Context, kind schema.Kind, md *commonv1.Metadata, modRevision int64) (int64, error) {
+	history := hs.schemaRegistry.HistoryRegistry()
+	revisions, err := history.ListHistory(ctx, kind, md)
+	if err != nil {
+		return 0, err
+	}
+	var target schema.HasMetadata
+	for _, rev := range revisions {
+		if rev.GetModRevision() == modRevision {
+			target = schema.RevisionSpec(rev)
+			break
+		}
+	}
+	if target == nil {
+		return 0, status.Errorf(codes.NotFound, "revision %d of %s %s is not in the history", modRevision, kind, md.GetName())
+	}
+	spec := proto.Clone(target).(schema.HasMetadata)
+	// The version is written as a normal update, so it goes through the same compatibility checks.
+	spec.GetMetadata().CreateRevision = 0
+	spec.GetMetadata().ModRevision = 0
+	if err = hs.update(ctx, kind, spec); err != nil {
+		return 0, err
+	}
+	if revisions, err = history.ListHistory(ctx, kind, md); err != nil {
+		return 0, err
+	}
+	if len(revisions) == 0 {
+		return 0, nil
+	}
+	return revisions[0].GetModRevision(), nil
+}
+
+func (hs *schemaHistoryServer) update(ctx context.Context, kind schema.Kind, spec schema.HasMetadata) (err error) {
+	repo := hs.schemaRegistry
+	switch kind {
+	case schema.KindGroup:
+		return repo.GroupRegistry().UpdateGroup(ctx, spec.(*commonv1.Group))
+	case schema.KindStream:
+		_, err = repo.StreamRegistry().UpdateStream(ctx, spec.(*databasev1.Stream))
+	case schema.KindMeasure:
+		_, err = repo.MeasureRegistry().UpdateMeasure(ctx, spec.(*databasev1.Measure))
+	case schema.KindTrace:
+		_, err = repo.TraceRegistry().UpdateTrace(ctx, spec.(*databasev1.Trace))
+	case schema.KindIndexRule:
+		return repo.IndexRuleRegistry().UpdateIndexRule(ctx, spec.(*databasev1.IndexRule))
+	case schema.KindIndexRuleBinding:
+		return repo.IndexRuleBindingRegistry().UpdateIndexRuleBinding(ctx, spec.(*databasev1.IndexRuleBinding))
+	case schema.KindTopNAggregation:
+		return repo.TopNAggregationRegistry().UpdateTopNAggregation(ctx, spec.(*databasev1.TopNAggregation))
+	case schema.KindProperty:
+		return repo.PropertyRegistry().UpdateProperty(ctx, spec.(*databasev1.Property))
+	default:
+		return schema.ErrUnsupportedEntityType
+	}
+	return err
+}
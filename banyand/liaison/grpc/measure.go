@@ -339,19 +339,7 @@ func (ms *measureService) handleWriteCleanup(publisher queue.BatchPublisher, suc
 ) {
 	cee, err := publisher.Close()
 	for _, s := range *succeedSent {
-		code := modelv1.Status_STATUS_SUCCEED
-		if cee != nil {
-			for _, node := range s.nodes {
-				if ce, ok := cee[node]; ok {
-					code = ce.Status()
-					if ce.Status() == modelv1.Status_STATUS_SUCCEED {
-						code = modelv1.Status_STATUS_SUCCEED
-						break
-					}
-				}
-			}
-		}
-		ms.sendReply(s.metadata, code, s.messageID, measure)
+		ms.sendReply(s.metadata, s.status(cee), s.messageID, measure)
 	}
 	if err != nil {
 		ms.l.Error().Err(err).Msg("failed to close the publisher")
@@ -513,3 +501,14 @@ type succeedSentMessage struct {
 	nodes     []string
 	messageID uint64
 }
+
+// status returns the status of the message by the errors of the nodes closing the batch.
+// An error of a node applies to the message only if the node doesn't report the messages of the batch separately.
+func (ssm succeedSentMessage) status(cee map[string]*common.Error) modelv1.Status {
+	for _, node := range ssm.nodes {
+		if ce, ok := cee[node]; ok {
+			return ce.MessageStatus(ssm.messageID)
+		}
+	}
+	return modelv1.Status_STATUS_SUCCEED
+}
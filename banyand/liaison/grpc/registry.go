@@ -47,6 +47,14 @@ func (rs *streamRegistryServer) Create(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "stream", "create")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "stream", "create")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunCreate(ctx, req.GetStream())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "stream", "create")
+			return nil, err
+		}
+		return &databasev1.StreamRegistryServiceCreateResponse{Report: report}, nil
+	}
 	modRevision, err := rs.schemaRegistry.StreamRegistry().CreateStream(ctx, req.GetStream())
 	if err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "stream", "create")
@@ -67,6 +75,14 @@ func (rs *streamRegistryServer) Update(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "stream", "update")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "stream", "update")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunUpdate(ctx, req.GetStream())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "stream", "update")
+			return nil, err
+		}
+		return &databasev1.StreamRegistryServiceUpdateResponse{Report: report}, nil
+	}
 	modRevision, err := rs.schemaRegistry.StreamRegistry().UpdateStream(ctx, req.GetStream())
 	if err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "stream", "update")
@@ -194,6 +210,14 @@ func (rs *indexRuleBindingRegistryServer) Create(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "indexRuleBinding", "create")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "indexRuleBinding", "create")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunCreate(ctx, req.GetIndexRuleBinding())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "indexRuleBinding", "create")
+			return nil, err
+		}
+		return &databasev1.IndexRuleBindingRegistryServiceCreateResponse{Report: report}, nil
+	}
 	if err := rs.schemaRegistry.IndexRuleBindingRegistry().CreateIndexRuleBinding(ctx, req.GetIndexRuleBinding()); err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "indexRuleBinding", "create")
 		return nil, err
@@ -212,6 +236,14 @@ func (rs *indexRuleBindingRegistryServer) Update(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "indexRuleBinding", "update")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "indexRuleBinding", "update")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunUpdate(ctx, req.GetIndexRuleBinding())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "indexRuleBinding", "update")
+			return nil, err
+		}
+		return &databasev1.IndexRuleBindingRegistryServiceUpdateResponse{Report: report}, nil
+	}
 	if err := rs.schemaRegistry.IndexRuleBindingRegistry().UpdateIndexRuleBinding(ctx, req.GetIndexRuleBinding()); err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "indexRuleBinding", "update")
 		return nil, err
@@ -325,6 +357,14 @@ func (rs *indexRuleRegistryServer) Create(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "indexRule", "create")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "indexRule", "create")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunCreate(ctx, req.GetIndexRule())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "indexRule", "create")
+			return nil, err
+		}
+		return &databasev1.IndexRuleRegistryServiceCreateResponse{Report: report}, nil
+	}
 	if err := rs.schemaRegistry.IndexRuleRegistry().CreateIndexRule(ctx, req.GetIndexRule()); err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "indexRule", "create")
 		return nil, err
@@ -343,6 +383,14 @@ func (rs *indexRuleRegistryServer) Update(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "indexRule", "update")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "indexRule", "update")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunUpdate(ctx, req.GetIndexRule())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "indexRule", "update")
+			return nil, err
+		}
+		return &databasev1.IndexRuleRegistryServiceUpdateResponse{Report: report}, nil
+	}
 	if err := rs.schemaRegistry.IndexRuleRegistry().UpdateIndexRule(ctx, req.GetIndexRule()); err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "indexRule", "update")
 		return nil, err
@@ -455,6 +503,14 @@ func (rs *measureRegistryServer) Create(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "measure", "create")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "measure", "create")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunCreate(ctx, req.GetMeasure())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "measure", "create")
+			return nil, err
+		}
+		return &databasev1.MeasureRegistryServiceCreateResponse{Report: report}, nil
+	}
 	modRevision, err := rs.schemaRegistry.MeasureRegistry().CreateMeasure(ctx, req.GetMeasure())
 	if err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "measure", "create")
@@ -476,6 +532,14 @@ func (rs *measureRegistryServer) Update(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "measure", "update")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "measure", "update")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunUpdate(ctx, req.GetMeasure())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "measure", "update")
+			return nil, err
+		}
+		return &databasev1.MeasureRegistryServiceUpdateResponse{Report: report}, nil
+	}
 	modRevision, err := rs.schemaRegistry.MeasureRegistry().UpdateMeasure(ctx, req.GetMeasure())
 	if err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "measure", "update")
@@ -592,6 +656,14 @@ func (rs *groupRegistryServer) Create(ctx context.Context, req *databasev1.Group
 		rs.metrics.totalRegistryFinished.Inc(1, g, "group", "create")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "group", "create")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunCreate(ctx, req.GetGroup())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "group", "create")
+			return nil, err
+		}
+		return &databasev1.GroupRegistryServiceCreateResponse{Report: report}, nil
+	}
 	if err := rs.schemaRegistry.GroupRegistry().CreateGroup(ctx, req.GetGroup()); err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "group", "create")
 		return nil, err
@@ -609,6 +681,14 @@ func (rs *groupRegistryServer) Update(ctx context.Context, req *databasev1.Group
 		rs.metrics.totalRegistryFinished.Inc(1, g, "group", "update")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "group", "update")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunUpdate(ctx, req.GetGroup())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "group", "update")
+			return nil, err
+		}
+		return &databasev1.GroupRegistryServiceUpdateResponse{Report: report}, nil
+	}
 	if err := rs.schemaRegistry.GroupRegistry().UpdateGroup(ctx, req.GetGroup()); err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "group", "update")
 		return nil, err
@@ -754,6 +834,12 @@ func (rs *groupRegistryServer) Inspect(ctx context.Context, req *databasev1.Grou
 		rs.metrics.totalRegistryErr.Inc(1, g, "group", "inspect")
 		return nil, schemaErr
 	}
+	if req.GetScrub() {
+		if scrubErr := rs.schemaRegistry.ScrubGroup(ctx, g); scrubErr != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "group", "inspect")
+			return nil, scrubErr
+		}
+	}
 	dataInfo, dataErr := rs.schemaRegistry.CollectDataInfo(ctx, g)
 	if dataErr != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "group", "inspect")
@@ -877,6 +963,14 @@ func (ts *topNAggregationRegistryServer) Create(ctx context.Context,
 		ts.metrics.totalRegistryFinished.Inc(1, g, "topn_aggregation", "create")
 		ts.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "topn_aggregation", "create")
 	}()
+	if req.GetDryRun() {
+		report, err := ts.dryRunCreate(ctx, req.GetTopNAggregation())
+		if err != nil {
+			ts.metrics.totalRegistryErr.Inc(1, g, "topn_aggregation", "create")
+			return nil, err
+		}
+		return &databasev1.TopNAggregationRegistryServiceCreateResponse{Report: report}, nil
+	}
 	if err := ts.schemaRegistry.TopNAggregationRegistry().CreateTopNAggregation(ctx, req.GetTopNAggregation()); err != nil {
 		ts.metrics.totalRegistryErr.Inc(1, g, "topn_aggregation", "create")
 		return nil, err
@@ -894,6 +988,14 @@ func (ts *topNAggregationRegistryServer) Update(ctx context.Context,
 		ts.metrics.totalRegistryFinished.Inc(1, g, "topn_aggregation", "update")
 		ts.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "topn_aggregation", "update")
 	}()
+	if req.GetDryRun() {
+		report, err := ts.dryRunUpdate(ctx, req.GetTopNAggregation())
+		if err != nil {
+			ts.metrics.totalRegistryErr.Inc(1, g, "topn_aggregation", "update")
+			return nil, err
+		}
+		return &databasev1.TopNAggregationRegistryServiceUpdateResponse{Report: report}, nil
+	}
 	if err := ts.schemaRegistry.TopNAggregationRegistry().UpdateTopNAggregation(ctx, req.GetTopNAggregation()); err != nil {
 		ts.metrics.totalRegistryErr.Inc(1, g, "topn_aggregation", "update")
 		return nil, err
@@ -1002,6 +1104,14 @@ func (ps *propertyRegistryServer) Create(ctx context.Context, req *databasev1.Pr
 		ps.metrics.totalRegistryFinished.Inc(1, g, "property", "create")
 		ps.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "property", "create")
 	}()
+	if req.GetDryRun() {
+		report, err := ps.dryRunCreate(ctx, req.GetProperty())
+		if err != nil {
+			ps.metrics.totalRegistryErr.Inc(1, g, "property", "create")
+			return nil, err
+		}
+		return &databasev1.PropertyRegistryServiceCreateResponse{Report: report}, nil
+	}
 	if err := ps.schemaRegistry.PropertyRegistry().CreateProperty(ctx, req.GetProperty()); err != nil {
 		ps.metrics.totalRegistryErr.Inc(1, g, "property", "create")
 		return nil, err
@@ -1019,6 +1129,14 @@ func (ps *propertyRegistryServer) Update(ctx context.Context, req *databasev1.Pr
 		ps.metrics.totalRegistryFinished.Inc(1, g, "property", "update")
 		ps.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "property", "update")
 	}()
+	if req.GetDryRun() {
+		report, err := ps.dryRunUpdate(ctx, req.GetProperty())
+		if err != nil {
+			ps.metrics.totalRegistryErr.Inc(1, g, "property", "update")
+			return nil, err
+		}
+		return &databasev1.PropertyRegistryServiceUpdateResponse{Report: report}, nil
+	}
 	if err := ps.schemaRegistry.PropertyRegistry().UpdateProperty(ctx, req.GetProperty()); err != nil {
 		ps.metrics.totalRegistryErr.Inc(1, g, "property", "update")
 		return nil, err
@@ -1127,6 +1245,14 @@ func (rs *traceRegistryServer) Create(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "trace", "create")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "trace", "create")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunCreate(ctx, req.GetTrace())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "trace", "create")
+			return nil, err
+		}
+		return &databasev1.TraceRegistryServiceCreateResponse{Report: report}, nil
+	}
 	modRevision, err := rs.schemaRegistry.TraceRegistry().CreateTrace(ctx, req.GetTrace())
 	if err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "trace", "create")
@@ -1147,6 +1273,14 @@ func (rs *traceRegistryServer) Update(ctx context.Context,
 		rs.metrics.totalRegistryFinished.Inc(1, g, "trace", "update")
 		rs.metrics.totalRegistryLatency.Inc(time.Since(start).Seconds(), g, "trace", "update")
 	}()
+	if req.GetDryRun() {
+		report, err := rs.dryRunUpdate(ctx, req.GetTrace())
+		if err != nil {
+			rs.metrics.totalRegistryErr.Inc(1, g, "trace", "update")
+			return nil, err
+		}
+		return &databasev1.TraceRegistryServiceUpdateResponse{Report: report}, nil
+	}
 	modRevision, err := rs.schemaRegistry.TraceRegistry().UpdateTrace(ctx, req.GetTrace())
 	if err != nil {
 		rs.metrics.totalRegistryErr.Inc(1, g, "trace", "update")
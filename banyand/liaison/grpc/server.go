@@ -113,6 +113,8 @@ type server struct {
 	*topNAggregationRegistryServer
 	*groupRegistryServer
 	*traceRegistryServer
+	*schemaHistoryServer
+	*seriesExplorerServer
 	authReloader *auth.Reloader
 	groupRepo    *groupRepo
 	*indexRuleBindingRegistryServer
@@ -208,6 +210,13 @@ func NewServer(_ context.Context, tir1Client, tir2Client, broadcaster queue.Clie
 		traceRegistryServer: &traceRegistryServer{
 			schemaRegistry: schemaRegistry,
 		},
+		schemaHistoryServer: &schemaHistoryServer{
+			schemaRegistry: schemaRegistry,
+		},
+		seriesExplorerServer: &seriesExplorerServer{
+			broadcaster: broadcaster,
+			groupRepo:   gr,
+		},
 		schemaRepo:          schemaRegistry,
 		authReloader:        auth.InitAuthReloader(),
 		protector:           protectorService,
@@ -305,6 +314,7 @@ func (s *server) PreRun(ctx context.Context) error {
 	s.topNAggregationRegistryServer.metrics = metrics
 	s.propertyRegistryServer.metrics = metrics
 	s.traceRegistryServer.metrics = metrics
+	s.schemaHistoryServer.metrics = metrics
 
 	if s.tls {
 		var err error
@@ -445,6 +455,7 @@ func (s *server) Serve() run.StopNotify {
 		streamChain = append(streamChain, authStreamInterceptor(s.authReloader))
 		unaryChain = append(unaryChain, authInterceptor(s.authReloader))
 	}
+	unaryChain = append(unaryChain, authorInterceptor)
 	if s.protector != nil {
 		streamChain = append(streamChain, s.protectorLoadSheddingInterceptor)
 	}
@@ -481,6 +492,8 @@ func (s *server) Serve() run.StopNotify {
 	databasev1.RegisterSnapshotServiceServer(s.ser, s)
 	databasev1.RegisterPropertyRegistryServiceServer(s.ser, s.propertyRegistryServer)
 	databasev1.RegisterTraceRegistryServiceServer(s.ser, s.traceRegistryServer)
+	databasev1.RegisterSchemaHistoryServiceServer(s.ser, s.schemaHistoryServer)
+	databasev1.RegisterSeriesExplorerServiceServer(s.ser, s.seriesExplorerServer)
 	databasev1.RegisterClusterStateServiceServer(s.ser, s)
 	databasev1.RegisterNodeQueryServiceServer(s.ser, s)
 	grpc_health_v1.RegisterHealthServer(s.ser, health.NewServer())
@@ -219,16 +219,7 @@ func (s *streamService) Write(stream streamv1.StreamService_WriteServer) error {
 	defer func() {
 		cee, err := publisher.Close()
 		for _, ssm := range succeedSent {
-			code := modelv1.Status_STATUS_SUCCEED
-			if cee != nil {
-				for _, node := range ssm.nodes {
-					if ce, ok := cee[node]; ok {
-						code = ce.Status()
-						break
-					}
-				}
-			}
-			s.sendReply(ssm.metadata, code, ssm.messageID, stream)
+			s.sendReply(ssm.metadata, ssm.status(cee), ssm.messageID, stream)
 		}
 		if err != nil {
 			s.l.Error().Err(err).Msg("failed to close the publisher")
@@ -98,6 +98,7 @@ func (b *block) mustInitFromTagsAndFields(tagFamilies [][]nameValues, fields []n
 			columns[j].name = t.name
 			columns[j].resizeValues(dataPointsLen)
 			columns[j].valueType = t.valueType
+			columns[j].codec = t.codec
 			columns[j].values[i] = t.marshal()
 		}
 	}
@@ -883,6 +884,7 @@ NEXT_FIELD:
 		c := column{
 			name:      tmpBlock.field.columns[i].name,
 			valueType: tmpBlock.field.columns[i].valueType,
+			codec:     tmpBlock.field.columns[i].codec,
 		}
 
 		c.values = append(c.values, tmpBlock.field.columns[i].values[start:end+1]...)
@@ -1085,7 +1087,7 @@ func fastFieldAppend(bi, b *blockPointer, offset int) error {
 func fullFieldAppend(bi, b *blockPointer, offset int) {
 	existDataSize := len(bi.timestamps)
 	appendFields := func(c column) {
-		col := column{name: c.name, valueType: c.valueType}
+		col := column{name: c.name, valueType: c.valueType, codec: c.codec}
 		for j := 0; j < existDataSize; j++ {
 			col.values = append(col.values, nil)
 		}
@@ -1146,6 +1148,7 @@ func (bi *blockPointer) mergeAndAppendTopN(left *blockPointer, leftIdx int, righ
 			bi.field.columns = append(bi.field.columns, column{
 				name:      c.name,
 				valueType: c.valueType,
+				codec:     c.codec,
 				values:    make([][]byte, 0),
 			})
 		}
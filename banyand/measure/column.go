@@ -18,6 +18,9 @@
 package measure
 
 import (
+	"math"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	"github.com/apache/skywalking-banyandb/pkg/bytes"
 	"github.com/apache/skywalking-banyandb/pkg/convert"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
@@ -88,10 +91,12 @@ type column struct {
 	name      string
 	values    [][]byte
 	valueType pbv1.ValueType
+	codec     columnCodec
 }
 
 func (c *column) reset() {
 	c.name = ""
+	c.codec = columnCodec{}
 
 	values := c.values
 	for i := range values {
@@ -115,6 +120,7 @@ func (c *column) mustWriteTo(cm *columnMetadata, columnWriter *writer) {
 
 	cm.name = c.name
 	cm.valueType = c.valueType
+	cm.codec = c.codec
 
 	bb := bigValuePool.Generate()
 	defer bigValuePool.Release(bb)
@@ -125,15 +131,62 @@ func (c *column) mustWriteTo(cm *columnMetadata, columnWriter *writer) {
 		c.encodeInt64Column(bb)
 	case pbv1.ValueTypeFloat64:
 		c.encodeFloat64Column(bb)
+	case pbv1.ValueTypeHistogram:
+		c.encodeHistogramColumn(bb)
 	default:
 		c.encodeDefault(bb)
 	}
+	if ct := c.codec.compressType(); ct.IsBlockCompressed() {
+		compressed := bigValuePool.Generate()
+		defer bigValuePool.Release(compressed)
+		var err error
+		compressed.Buf, err = encoding.CompressBlockWith(compressed.Buf[:0], bb.Buf, ct)
+		if err != nil {
+			logger.Panicf("cannot compress the column %q: %v", c.name, err)
+		}
+		bb.Buf, compressed.Buf = compressed.Buf, bb.Buf
+	}
 	cm.size = uint64(len(bb.Buf))
 	if cm.size > maxValuesBlockSize {
 		logger.Panicf("too large valuesSize: %d bytes; mustn't exceed %d bytes", cm.size, maxValuesBlockSize)
 	}
 	cm.offset = columnWriter.bytesWritten
 	columnWriter.MustWrite(bb.Buf)
+
+	if supportsZoneMap(c.valueType) {
+		c.fillZoneMap(cm)
+	}
+}
+
+// fillZoneMap records the range and the null count of the numeric values,
+// so that the queries skip the blocks out of the range without decoding them.
+func (c *column) fillZoneMap(cm *columnMetadata) {
+	var minValue, maxValue []byte
+	for _, v := range c.values {
+		if len(v) != 8 {
+			cm.nullCount++
+			continue
+		}
+		if c.valueType == pbv1.ValueTypeFloat64 && math.IsNaN(convert.BytesToFloat64(v)) {
+			// NaN never matches a range, so it doesn't widen the zone map.
+			continue
+		}
+		if minValue == nil || c.less(v, minValue) {
+			minValue = v
+		}
+		if maxValue == nil || c.less(maxValue, v) {
+			maxValue = v
+		}
+	}
+	cm.min = append(cm.min[:0], minValue...)
+	cm.max = append(cm.max[:0], maxValue...)
+}
+
+func (c *column) less(a, b []byte) bool {
+	if c.valueType == pbv1.ValueTypeFloat64 {
+		return convert.BytesToFloat64(a) < convert.BytesToFloat64(b)
+	}
+	return convert.BytesToInt64(a) < convert.BytesToInt64(b)
 }
 
 func (c *column) encodeInt64Column(bb *bytes.Buffer) {
@@ -172,26 +225,15 @@ func (c *column) encodeInt64Column(bb *bytes.Buffer) {
 
 func (c *column) encodeFloat64Column(bb *bytes.Buffer) {
 	// convert byte array to float64 array
-	intValuesPtr := generateInt64Slice(len(c.values))
-	intValues := *intValuesPtr
-	defer releaseInt64Slice(intValuesPtr)
-
 	floatValuesPtr := generateFloat64Slice(len(c.values))
 	floatValues := *floatValuesPtr
 	defer releaseFloat64Slice(floatValuesPtr)
 
-	var encodeType encoding.EncodeType
-
-	doEncodeDefault := func() {
-		c.encodeDefault(bb)
-		encodeType = encoding.EncodeTypePlain
-		// Prepend encodeType (1 byte) to the beginning
-		bb.Buf = append([]byte{byte(encodeType)}, bb.Buf...)
-	}
-
 	for i, v := range c.values {
 		if v == nil || string(v) == "null" {
-			doEncodeDefault()
+			c.encodeDefault(bb)
+			// Prepend encodeType (1 byte) to the beginning
+			bb.Buf = append([]byte{byte(encoding.EncodeTypePlain)}, bb.Buf...)
 			return
 		}
 		if len(v) != 8 {
@@ -199,43 +241,58 @@ func (c *column) encodeFloat64Column(bb *bytes.Buffer) {
 		}
 		floatValues[i] = convert.BytesToFloat64(v)
 	}
-	intValues, exp, err := encoding.Float64ListToDecimalIntList(intValues[:0], floatValues)
-	if err != nil {
-		logger.Errorf("cannot convert Float64List to DecimalIntList : %v", err)
-		doEncodeDefault()
+	if c.codec.encoding == databasev1.EncodingMethod_ENCODING_METHOD_GORILLA {
+		var err error
+		bb.Buf, err = encoding.EncodeFloat64List(bb.Buf[:0], floatValues, encoding.EncodeTypeGorilla)
+		if err != nil {
+			logger.Panicf("cannot encode float values with gorilla: %v", err)
+		}
 		return
 	}
-	var firstValue int64
-	bb.Buf, encodeType, firstValue = encoding.Int64ListToBytes(bb.Buf[:0], intValues)
-	if encodeType == encoding.EncodeTypeUnknown {
-		logger.Panicf("invalid encode type for int64 values")
+	// the encoding is chosen among the decimal conversion, ALP, Chimp128 and Gorilla on a sample of the values
+	bb.Buf, _ = encoding.Float64ListToBytes(bb.Buf[:0], floatValues)
+}
+
+func (c *column) encodeHistogramColumn(bb *bytes.Buffer) {
+	var err error
+	bb.Buf, err = encoding.EncodeHistogramBlock(bb.Buf[:0], c.values)
+	if err != nil {
+		// fall back to the default encoding for the null values and the histograms with different buckets
+		c.encodeDefault(bb)
+		bb.Buf = append([]byte{byte(encoding.EncodeTypePlain)}, bb.Buf...)
+		return
 	}
-	firstValueBytes := convert.Int64ToBytes(firstValue)
-	expBytes := convert.Int16ToBytes(exp)
-	// Prepend encodeType (1 byte), exp (2 bytes) and firstValue (8 bytes) to the beginning
-	bb.Buf = append(
-		append(append([]byte{byte(encodeType)}, expBytes...), firstValueBytes...),
-		bb.Buf...,
-	)
+	bb.Buf = append([]byte{byte(encoding.EncodeTypeHistogram)}, bb.Buf...)
 }
 
 func (c *column) encodeDefault(bb *bytes.Buffer) {
+	// the values are left uncompressed if the field declares its own compression
+	uncompressed := c.codec.compression != databasev1.CompressionMethod_COMPRESSION_METHOD_UNSPECIFIED
 	dict := generateDictionary()
 	defer releaseDictionary(dict)
 	for _, v := range c.values {
 		if !dict.Add(v) {
-			bb.Buf = encoding.EncodeBytesBlock(bb.Buf[:0], c.values)
+			if uncompressed {
+				bb.Buf = encoding.EncodeBytesBlockUncompressed(bb.Buf[:0], c.values)
+			} else {
+				bb.Buf = encoding.EncodeBytesBlock(bb.Buf[:0], c.values)
+			}
 			bb.Buf = append([]byte{byte(encoding.EncodeTypePlain)}, bb.Buf...)
 			return
 		}
 	}
-	bb.Buf = dict.Encode(bb.Buf[:0])
+	if uncompressed {
+		bb.Buf = dict.EncodeUncompressed(bb.Buf[:0])
+	} else {
+		bb.Buf = dict.Encode(bb.Buf[:0])
+	}
 	bb.Buf = append([]byte{byte(encoding.EncodeTypeDictionary)}, bb.Buf...)
 }
 
 func (c *column) mustReadValues(decoder *encoding.BytesBlockDecoder, reader fs.Reader, cm columnMetadata, count uint64) {
 	c.name = cm.name
 	c.valueType = cm.valueType
+	c.codec = cm.codec
 	if c.valueType == pbv1.ValueTypeUnknown {
 		for i := uint64(0); i < count; i++ {
 			c.values = append(c.values, nil)
@@ -251,12 +308,14 @@ func (c *column) mustReadValues(decoder *encoding.BytesBlockDecoder, reader fs.R
 	}
 	bb.Buf = bytes.ResizeOver(bb.Buf, int(valuesSize))
 	fs.MustReadData(reader, int64(cm.offset), bb.Buf)
+	c.mustDecompress(reader.Path(), bb)
 	c.decodeColumnValues(decoder, reader.Path(), count, bb)
 }
 
 func (c *column) mustSeqReadValues(decoder *encoding.BytesBlockDecoder, reader *seqReader, cm columnMetadata, count uint64) {
 	c.name = cm.name
 	c.valueType = cm.valueType
+	c.codec = cm.codec
 	if cm.offset != reader.bytesRead {
 		logger.Panicf("%s: offset mismatch: %d vs %d", reader.Path(), cm.offset, reader.bytesRead)
 	}
@@ -270,15 +329,33 @@ func (c *column) mustSeqReadValues(decoder *encoding.BytesBlockDecoder, reader *
 
 	bb.Buf = bytes.ResizeOver(bb.Buf, int(valuesSize))
 	reader.mustReadFull(bb.Buf)
+	c.mustDecompress(reader.Path(), bb)
 	c.decodeColumnValues(decoder, reader.Path(), count, bb)
 }
 
+func (c *column) mustDecompress(path string, bb *bytes.Buffer) {
+	ct := c.codec.compressType()
+	if !ct.IsBlockCompressed() {
+		return
+	}
+	decompressed := bigValuePool.Generate()
+	defer bigValuePool.Release(decompressed)
+	var err error
+	decompressed.Buf, err = encoding.DecompressBlockWith(decompressed.Buf[:0], bb.Buf, ct)
+	if err != nil {
+		logger.Panicf("%s: cannot decompress the column %q with %s: %v", path, c.name, ct, err)
+	}
+	bb.Buf, decompressed.Buf = decompressed.Buf, bb.Buf
+}
+
 func (c *column) decodeColumnValues(decoder *encoding.BytesBlockDecoder, path string, count uint64, bb *bytes.Buffer) {
 	switch c.valueType {
 	case pbv1.ValueTypeInt64:
 		c.decodeInt64Column(decoder, path, count, bb)
 	case pbv1.ValueTypeFloat64:
 		c.decodeFloat64Column(decoder, path, count, bb)
+	case pbv1.ValueTypeHistogram:
+		c.decodeHistogramColumn(decoder, path, count, bb)
 	default:
 		c.decodeDefault(decoder, bb, count, path)
 	}
@@ -320,9 +397,6 @@ func (c *column) decodeInt64Column(decoder *encoding.BytesBlockDecoder, path str
 
 func (c *column) decodeFloat64Column(decoder *encoding.BytesBlockDecoder, path string, count uint64, bb *bytes.Buffer) {
 	// decode float type
-	intValuesPtr := generateInt64Slice(int(count))
-	intValues := *intValuesPtr
-	defer releaseInt64Slice(intValuesPtr)
 	floatValuesPtr := generateFloat64Slice(int(count))
 	floatValues := *floatValuesPtr
 	defer releaseFloat64Slice(floatValuesPtr)
@@ -337,21 +411,10 @@ func (c *column) decodeFloat64Column(decoder *encoding.BytesBlockDecoder, path s
 		return
 	}
 
-	const expectedLen = 11
-	if len(bb.Buf) < expectedLen {
-		logger.Panicf("bb.Buf length too short: expect at least %d bytes, but got %d bytes", expectedLen, len(bb.Buf))
-	}
-	exp := convert.BytesToInt16(bb.Buf[1:3])
-	firstValue := convert.BytesToInt64(bb.Buf[3:11])
-	bb.Buf = bb.Buf[11:]
 	var err error
-	intValues, err = encoding.BytesToInt64List(intValues[:0], bb.Buf, encodeType, firstValue, int(count))
-	if err != nil {
-		logger.Panicf("%s: cannot decode int values: %v", path, err)
-	}
-	floatValues, err = encoding.DecimalIntListToFloat64List(floatValues[:0], intValues, exp, int(count))
+	floatValues, err = encoding.BytesToFloat64List(floatValues[:0], bb.Buf, int(count))
 	if err != nil {
-		logger.Panicf("cannot convert DecimalIntList to Float64List: %v", err)
+		logger.Panicf("%s: cannot decode float values: %v", path, err)
 	}
 	if uint64(len(floatValues)) != count {
 		logger.Panicf("unexpected floatValues length: got %d, expected %d", len(floatValues), count)
@@ -363,6 +426,23 @@ func (c *column) decodeFloat64Column(decoder *encoding.BytesBlockDecoder, path s
 	}
 }
 
+func (c *column) decodeHistogramColumn(decoder *encoding.BytesBlockDecoder, path string, count uint64, bb *bytes.Buffer) {
+	if len(bb.Buf) < 1 {
+		logger.Panicf("bb.Buf length too short: expect at least %d bytes, but got %d bytes", 1, len(bb.Buf))
+	}
+	encodeType := encoding.EncodeType(bb.Buf[0])
+	if encodeType == encoding.EncodeTypePlain {
+		bb.Buf = bb.Buf[1:]
+		c.decodeDefault(decoder, bb, count, path)
+		return
+	}
+	var err error
+	c.values, err = encoding.DecodeHistogramBlock(c.values[:0], bb.Buf[1:], count)
+	if err != nil {
+		logger.Panicf("%s: cannot decode histogram values: %v", path, err)
+	}
+}
+
 func (c *column) decodeDefault(decoder *encoding.BytesBlockDecoder, bb *bytes.Buffer, count uint64, path string) {
 	encodeType := encoding.EncodeType(bb.Buf[0])
 	var err error
@@ -20,33 +20,112 @@ package measure
 import (
 	"fmt"
 
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	"github.com/apache/skywalking-banyandb/pkg/convert"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
 	"github.com/apache/skywalking-banyandb/pkg/pool"
 )
 
+// columnCodecFlag marks the valueType byte of the column metadata followed by a codec byte.
+// The metadata written before the codec was introduced never sets it, so the readers decode them as the default codec.
+const columnCodecFlag = 0x80
+
+// columnZoneMapFlag marks the valueType byte of the column metadata followed by the zone map.
+// Only the int and float columns written after the zone map was introduced set it.
+const columnZoneMapFlag = 0x40
+
+// columnCodec records the encoding and compression methods declared by the field spec.
+type columnCodec struct {
+	encoding    databasev1.EncodingMethod
+	compression databasev1.CompressionMethod
+}
+
+func newColumnCodec(spec *databasev1.FieldSpec) columnCodec {
+	return columnCodec{
+		encoding:    spec.GetEncodingMethod(),
+		compression: spec.GetCompressionMethod(),
+	}
+}
+
+func (cc columnCodec) isDefault() bool {
+	return cc.encoding == databasev1.EncodingMethod_ENCODING_METHOD_UNSPECIFIED &&
+		cc.compression == databasev1.CompressionMethod_COMPRESSION_METHOD_UNSPECIFIED
+}
+
+// compressType converts the compression method, whose values are aligned with encoding.CompressType.
+func (cc columnCodec) compressType() encoding.CompressType {
+	return encoding.CompressType(cc.compression)
+}
+
+func (cc columnCodec) marshal() byte {
+	return byte(cc.encoding)<<4 | byte(cc.compression)
+}
+
+func (cc *columnCodec) unmarshal(b byte) {
+	cc.encoding = databasev1.EncodingMethod(b >> 4)
+	cc.compression = databasev1.CompressionMethod(b & 0x0F)
+}
+
 type columnMetadata struct {
 	name string
+	min  []byte
+	max  []byte
 	dataBlock
+	nullCount uint64
 	valueType pbv1.ValueType
+	codec     columnCodec
 }
 
 func (cm *columnMetadata) reset() {
 	cm.name = ""
 	cm.valueType = 0
+	cm.codec = columnCodec{}
+	// min and max might be shared by the copies of the metadata, so they are never reused.
+	cm.min = nil
+	cm.max = nil
+	cm.nullCount = 0
 	cm.dataBlock.reset()
 }
 
 func (cm *columnMetadata) copyFrom(src *columnMetadata) {
 	cm.name = src.name
 	cm.valueType = src.valueType
+	cm.codec = src.codec
+	cm.min = append(cm.min[:0], src.min...)
+	cm.max = append(cm.max[:0], src.max...)
+	cm.nullCount = src.nullCount
 	cm.dataBlock.copyFrom(&src.dataBlock)
 }
 
+// hasZoneMap reports whether min and max cover the non-null values of the block.
+func (cm *columnMetadata) hasZoneMap() bool {
+	return len(cm.min) > 0 && len(cm.max) > 0
+}
+
+func supportsZoneMap(valueType pbv1.ValueType) bool {
+	return valueType == pbv1.ValueTypeInt64 || valueType == pbv1.ValueTypeFloat64
+}
+
 func (cm *columnMetadata) marshal(dst []byte) []byte {
 	dst = encoding.EncodeBytes(dst, convert.StringToBytes(cm.name))
-	dst = append(dst, byte(cm.valueType))
+	flags := byte(0)
+	if !cm.codec.isDefault() {
+		flags |= columnCodecFlag
+	}
+	hasZoneMap := supportsZoneMap(cm.valueType)
+	if hasZoneMap {
+		flags |= columnZoneMapFlag
+	}
+	dst = append(dst, byte(cm.valueType)|flags)
+	if !cm.codec.isDefault() {
+		dst = append(dst, cm.codec.marshal())
+	}
+	if hasZoneMap {
+		dst = encoding.EncodeBytes(dst, cm.min)
+		dst = encoding.EncodeBytes(dst, cm.max)
+		dst = encoding.VarUint64ToBytes(dst, cm.nullCount)
+	}
 	dst = cm.dataBlock.marshal(dst)
 	return dst
 }
@@ -60,8 +139,31 @@ func (cm *columnMetadata) unmarshal(src []byte) ([]byte, error) {
 	if len(src) < 1 {
 		return nil, fmt.Errorf("cannot unmarshal columnMetadata.valueType: src is too short")
 	}
-	cm.valueType = pbv1.ValueType(src[0])
+	cm.valueType = pbv1.ValueType(src[0] &^ (columnCodecFlag | columnZoneMapFlag))
+	hasCodec := src[0]&columnCodecFlag != 0
+	hasZoneMap := src[0]&columnZoneMapFlag != 0
 	src = src[1:]
+	if hasCodec {
+		if len(src) < 1 {
+			return nil, fmt.Errorf("cannot unmarshal columnMetadata.codec: src is too short")
+		}
+		cm.codec.unmarshal(src[0])
+		src = src[1:]
+	}
+	if hasZoneMap {
+		var b []byte
+		src, b, err = encoding.DecodeBytes(src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot unmarshal columnMetadata.min: %w", err)
+		}
+		cm.min = append(cm.min[:0], b...)
+		src, b, err = encoding.DecodeBytes(src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot unmarshal columnMetadata.max: %w", err)
+		}
+		cm.max = append(cm.max[:0], b...)
+		src, cm.nullCount = encoding.BytesToVarUint64(src)
+	}
 	src = cm.dataBlock.unmarshal(src)
 	return src, nil
 }
@@ -22,6 +22,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
 )
 
@@ -70,6 +73,71 @@ func Test_columnMetadata_marshal(t *testing.T) {
 	assert.Equal(t, original, unmarshaled)
 }
 
+func Test_columnMetadata_marshalCodec(t *testing.T) {
+	original := &columnMetadata{
+		name:      "test",
+		valueType: pbv1.ValueTypeFloat64,
+		dataBlock: dataBlock{offset: 1, size: 10},
+		codec: columnCodec{
+			encoding:    databasev1.EncodingMethod_ENCODING_METHOD_GORILLA,
+			compression: databasev1.CompressionMethod_COMPRESSION_METHOD_LZ4,
+		},
+	}
+
+	marshaled := original.marshal(nil)
+	unmarshaled := &columnMetadata{}
+	tail, err := unmarshaled.unmarshal(marshaled)
+	assert.NoError(t, err)
+	assert.Empty(t, tail)
+	assert.Equal(t, original, unmarshaled)
+
+	// the metadata with the default codec keeps the layout written before the codec was recorded
+	original.codec = columnCodec{}
+	original.valueType = pbv1.ValueTypeStr
+	legacy := encoding.EncodeBytes(nil, []byte(original.name))
+	legacy = append(legacy, byte(original.valueType))
+	legacy = original.dataBlock.marshal(legacy)
+	assert.Equal(t, legacy, original.marshal(nil))
+	unmarshaled.reset()
+	_, err = unmarshaled.unmarshal(legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, original, unmarshaled)
+}
+
+func Test_columnMetadata_marshalZoneMap(t *testing.T) {
+	original := &columnMetadata{
+		name:      "test",
+		valueType: pbv1.ValueTypeInt64,
+		dataBlock: dataBlock{offset: 1, size: 10},
+		min:       convert.Int64ToBytes(-3),
+		max:       convert.Int64ToBytes(42),
+		nullCount: 2,
+		codec: columnCodec{
+			compression: databasev1.CompressionMethod_COMPRESSION_METHOD_LZ4,
+		},
+	}
+
+	marshaled := original.marshal(nil)
+	unmarshaled := &columnMetadata{}
+	tail, err := unmarshaled.unmarshal(marshaled)
+	assert.NoError(t, err)
+	assert.Empty(t, tail)
+	assert.Equal(t, original, unmarshaled)
+	assert.True(t, unmarshaled.hasZoneMap())
+
+	// the int columns written before the zone map was recorded have no zone map
+	legacy := encoding.EncodeBytes(nil, []byte(original.name))
+	legacy = append(legacy, byte(original.valueType))
+	legacy = original.dataBlock.marshal(legacy)
+	unmarshaled.reset()
+	tail, err = unmarshaled.unmarshal(legacy)
+	assert.NoError(t, err)
+	assert.Empty(t, tail)
+	assert.False(t, unmarshaled.hasZoneMap())
+	assert.Equal(t, uint64(0), unmarshaled.nullCount)
+	assert.Equal(t, original.dataBlock, unmarshaled.dataBlock)
+}
+
 func Test_columnFamilyMetadata_reset(t *testing.T) {
 	cfm := &columnFamilyMetadata{
 		columnMetadata: []columnMetadata{
@@ -23,6 +23,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	"github.com/apache/skywalking-banyandb/pkg/bytes"
 	"github.com/apache/skywalking-banyandb/pkg/convert"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
@@ -115,6 +116,58 @@ func TestColumn_mustWriteTo_mustReadValues(t *testing.T) {
 	}
 }
 
+func TestColumn_mustWriteTo_mustReadValues_codec(t *testing.T) {
+	floatValues := make([][]byte, 0, 100)
+	strValues := make([][]byte, 0, 100)
+	for i := 0; i < 100; i++ {
+		floatValues = append(floatValues, convert.Float64ToBytes(float64(i)*0.3))
+		strValues = append(strValues, []byte(fmt.Sprintf("service_instance_%d", i)))
+	}
+	compressions := []databasev1.CompressionMethod{
+		databasev1.CompressionMethod_COMPRESSION_METHOD_UNSPECIFIED,
+		databasev1.CompressionMethod_COMPRESSION_METHOD_ZSTD,
+		databasev1.CompressionMethod_COMPRESSION_METHOD_LZ4,
+		databasev1.CompressionMethod_COMPRESSION_METHOD_SNAPPY,
+		databasev1.CompressionMethod_COMPRESSION_METHOD_NONE,
+	}
+	for _, compression := range compressions {
+		for _, tt := range []struct {
+			name      string
+			values    [][]byte
+			valueType pbv1.ValueType
+		}{
+			{name: "float", valueType: pbv1.ValueTypeFloat64, values: floatValues},
+			{name: "string", valueType: pbv1.ValueTypeStr, values: strValues},
+		} {
+			t.Run(fmt.Sprintf("%s_%s", tt.name, compression), func(t *testing.T) {
+				original := &column{
+					name:      "test",
+					valueType: tt.valueType,
+					values:    tt.values,
+					codec: columnCodec{
+						encoding:    databasev1.EncodingMethod_ENCODING_METHOD_GORILLA,
+						compression: compression,
+					},
+				}
+
+				cm := &columnMetadata{}
+				buf := &bytes.Buffer{}
+				w := &writer{}
+				w.init(buf)
+				original.mustWriteTo(cm, w)
+				assert.Equal(t, original.codec, cm.codec)
+
+				// the codec is read from the metadata of the part
+				decoder := &encoding.BytesBlockDecoder{}
+				unmarshaled := &column{}
+				unmarshaled.mustReadValues(decoder, buf, *cm, uint64(len(original.values)))
+				assert.Equal(t, original.codec, unmarshaled.codec)
+				assert.Equal(t, original.values, unmarshaled.values)
+			})
+		}
+	}
+}
+
 func TestColumnFamily_reset(t *testing.T) {
 	cf := &columnFamily{
 		name: "test",
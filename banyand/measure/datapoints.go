@@ -36,12 +36,14 @@ type nameValue struct {
 	value     []byte
 	valueArr  [][]byte
 	valueType pbv1.ValueType
+	codec     columnCodec
 }
 
 func (n *nameValue) reset() {
 	n.name = ""
 	n.value = nil
 	n.valueArr = nil
+	n.codec = columnCodec{}
 }
 
 func generateNameValue() *nameValue {
@@ -183,6 +185,25 @@ func (d *dataPoints) skip(i int) {
 	}
 }
 
+// dropSeries removes the data points of the rejected series and keeps the order of the others.
+func (d *dataPoints) dropSeries(rejected map[common.SeriesID]struct{}) {
+	n := 0
+	for i := range d.seriesIDs {
+		if _, ok := rejected[d.seriesIDs[i]]; ok {
+			continue
+		}
+		if n != i {
+			d.Swap(n, i)
+		}
+		n++
+	}
+	d.seriesIDs = d.seriesIDs[:n]
+	d.timestamps = d.timestamps[:n]
+	d.versions = d.versions[:n]
+	d.tagFamilies = d.tagFamilies[:n]
+	d.fields = d.fields[:n]
+}
+
 func (d *dataPoints) Len() int {
 	return len(d.seriesIDs)
 }
@@ -226,10 +247,28 @@ type dataPointsInTable struct {
 	dataPoints      *dataPoints
 	metadataDocMap  map[uint64]int
 	indexModeDocMap map[uint64]int
-	timeRange       timestamp.TimeRange
-	metadataDocs    index.Documents
-	indexModeDocs   index.Documents
-	shardID         common.ShardID
+	// messageIDs are the write messages of each series, which a liaison reports if the series is rejected.
+	messageIDs    map[common.SeriesID][]uint64
+	timeRange     timestamp.TimeRange
+	metadataDocs  index.Documents
+	indexModeDocs index.Documents
+	shardID       common.ShardID
+}
+
+func (dpt *dataPointsInTable) addMessage(sid common.SeriesID, messageID uint64) {
+	if dpt.messageIDs == nil {
+		dpt.messageIDs = make(map[common.SeriesID][]uint64)
+	}
+	dpt.messageIDs[sid] = append(dpt.messageIDs[sid], messageID)
+}
+
+// messages returns the write messages of all the series.
+func (dpt *dataPointsInTable) messages() []uint64 {
+	var ids []uint64
+	for _, sidIDs := range dpt.messageIDs {
+		ids = append(ids, sidIDs...)
+	}
+	return ids
 }
 
 type dataPointsInGroup struct {
@@ -0,0 +1,144 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package measure
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+func (m *measure) TagValues(ctx context.Context, tagName string, timeRange timestamp.TimeRange, prefix string, limit int) ([]string, error) {
+	entity := m.schema.GetEntity().GetTagNames()
+	entityPos := slices.Index(entity, tagName)
+	var fieldKey index.FieldKey
+	var termPrefix []byte
+	var decode func([]byte) (string, bool)
+	if entityPos < 0 {
+		var rule *databasev1.IndexRule
+		for _, r := range m.GetIndexRules() {
+			if r.GetType() != databasev1.IndexRule_TYPE_SKIPPING && slices.Contains(r.GetTags(), tagName) {
+				rule = r
+				break
+			}
+		}
+		if rule == nil {
+			return nil, errors.Errorf("tag %s is neither an entity tag nor indexed", tagName)
+		}
+		fieldKey.IndexRuleID = rule.GetMetadata().GetId()
+		switch t := m.tagSpec(tagName); t.GetType() {
+		case databasev1.TagType_TAG_TYPE_STRING, databasev1.TagType_TAG_TYPE_STRING_ARRAY:
+			termPrefix = []byte(prefix)
+			decode = storage.StringTerm
+		case databasev1.TagType_TAG_TYPE_INT, databasev1.TagType_TAG_TYPE_INT_ARRAY:
+			decode = func(term []byte) (string, bool) {
+				if len(term) != 8 {
+					return "", false
+				}
+				v := strconv.FormatInt(convert.BytesToInt64(term), 10)
+				return v, strings.HasPrefix(v, prefix)
+			}
+		default:
+			return nil, errors.Errorf("the values of the indexed tag %s with type %s are not listable", tagName, t.GetType())
+		}
+	}
+	tsdb, err := m.getTSDB()
+	if err != nil {
+		return nil, err
+	}
+	segments, err := tsdb.SelectSegments(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, segment := range segments {
+			segment.DecRef()
+		}
+	}()
+	values := make(storage.TagValueSet)
+	for _, segment := range segments {
+		if entityPos >= 0 {
+			err = values.CollectEntityTagValues(ctx, segment.IndexDB(), m.name, len(entity), entityPos, prefix)
+		} else {
+			err = values.CollectTerms(segment.IndexDB().Terms, fieldKey, termPrefix, decode)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values.Sorted(limit), nil
+}
+
+func (m *measure) tagSpec(tagName string) *databasev1.TagSpec {
+	for _, tf := range m.schema.GetTagFamilies() {
+		for _, t := range tf.GetTags() {
+			if t.GetName() == tagName {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+func (m *measure) ListSeries(ctx context.Context, timeRange timestamp.TimeRange, after []byte, limit int) (pbv1.SeriesList, bool, error) {
+	tsdb, err := m.getTSDB()
+	if err != nil {
+		return nil, false, err
+	}
+	segments, err := tsdb.SelectSegments(timeRange)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		for _, segment := range segments {
+			segment.DecRef()
+		}
+	}()
+	return storage.ListSeries(ctx, segments, m.name, len(m.schema.GetEntity().GetTagNames()), after, limit)
+}
+
+func (m *measure) SeriesCardinality(ctx context.Context, timeRange timestamp.TimeRange, tags []string, topN int) ([]*databasev1.SeriesCardinalityBucket, error) {
+	entity := m.schema.GetEntity().GetTagNames()
+	if len(tags) == 0 {
+		tags = entity
+	}
+	tsdb, err := m.getTSDB()
+	if err != nil {
+		return nil, err
+	}
+	segments, err := tsdb.SelectSegments(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, segment := range segments {
+			segment.DecRef()
+		}
+	}()
+	return storage.SeriesCardinality(ctx, segments, m.name, entity, tags, topN)
+}
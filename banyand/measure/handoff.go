@@ -0,0 +1,90 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package measure
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/skywalking-banyandb/api/data"
+	"github.com/apache/skywalking-banyandb/banyand/queue"
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+)
+
+// handoffCodec maps the measure parts to the handoff queue.
+type handoffCodec struct{}
+
+func (handoffCodec) Topic() string {
+	return data.TopicMeasurePartSync.String()
+}
+
+// PartSize reads the CompressedSizeBytes from the part's metadata file.
+func (handoffCodec) PartSize(fileSystem fs.FileSystem, partPath, _ string) uint64 {
+	pm, ok := readHandoffPartMetadata(fileSystem, partPath)
+	if !ok {
+		return 0
+	}
+	return pm.CompressedSizeBytes
+}
+
+func (handoffCodec) StreamingFileName(_, fileName string) (string, bool) {
+	switch fileName {
+	case primaryFilename:
+		return measurePrimaryName, true
+	case metaFilename:
+		return measureMetaName, true
+	case timestampsFilename:
+		return measureTimestampsName, true
+	case fieldValuesFilename:
+		return measureFieldValuesName, true
+	}
+	if strings.HasSuffix(fileName, tagFamiliesMetadataFilenameExt) {
+		return measureTagMetadataPrefix + removeExt(fileName, tagFamiliesMetadataFilenameExt), true
+	}
+	if strings.HasSuffix(fileName, tagFamiliesFilenameExt) {
+		return measureTagFamiliesPrefix + removeExt(fileName, tagFamiliesFilenameExt), true
+	}
+	// metadata.json travels in the part metadata and the series metadata isn't synced.
+	return "", false
+}
+
+func (handoffCodec) FillPartMetadata(fileSystem fs.FileSystem, partPath string, streamingPart *queue.StreamingPartData) {
+	pm, ok := readHandoffPartMetadata(fileSystem, partPath)
+	if !ok {
+		return
+	}
+	streamingPart.CompressedSizeBytes = pm.CompressedSizeBytes
+	streamingPart.UncompressedSizeBytes = pm.UncompressedSizeBytes
+	streamingPart.TotalCount = pm.TotalCount
+	streamingPart.BlocksCount = pm.BlocksCount
+	streamingPart.MinTimestamp = pm.MinTimestamp
+	streamingPart.MaxTimestamp = pm.MaxTimestamp
+}
+
+func readHandoffPartMetadata(fileSystem fs.FileSystem, partPath string) (partMetadata, bool) {
+	var pm partMetadata
+	metadataBytes, err := fileSystem.Read(filepath.Join(partPath, metadataFilename))
+	if err != nil {
+		return pm, false
+	}
+	if err := json.Unmarshal(metadataBytes, &pm); err != nil {
+		return pm, false
+	}
+	return pm, true
+}
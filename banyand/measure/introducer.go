@@ -18,6 +18,7 @@
 package measure
 
 import (
+	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	"github.com/apache/skywalking-banyandb/pkg/pool"
 	"github.com/apache/skywalking-banyandb/pkg/watcher"
 )
@@ -25,11 +26,15 @@ import (
 type introduction struct {
 	memPart *partWrapper
 	applied chan struct{}
+	waiter  *wqueue.AckWaiter
+	track   bool
 }
 
 func (i *introduction) reset() {
 	i.memPart = nil
 	i.applied = nil
+	i.waiter = nil
+	i.track = false
 }
 
 var introductionPool = pool.Register[*introduction]("measure-introduction")
@@ -196,6 +201,10 @@ func (tst *tsTable) introduceMemPart(nextIntroduction *introduction, epoch uint6
 	nextSnp := cur.copyAllTo(epoch)
 	nextSnp.parts = append(nextSnp.parts, next)
 	nextSnp.creator = snapshotCreatorMemPart
+	if nextIntroduction.track && tst.acks != nil {
+		// The write is tracked before any sync reads the snapshot holding the mem part.
+		nextIntroduction.waiter = tst.acks.Track(next.ID(), epoch)
+	}
 	tst.replaceSnapshot(&nextSnp, false)
 	if nextIntroduction.applied != nil {
 		close(nextIntroduction.applied)
@@ -224,7 +233,9 @@ func (tst *tsTable) introduceMerged(nextIntroduction *mergerIntroduction, epoch
 	}
 	defer cur.decRef()
 	nextSnp := cur.remove(epoch, nextIntroduction.merged)
-	nextSnp.parts = append(nextSnp.parts, nextIntroduction.newPart)
+	if nextIntroduction.newPart != nil {
+		nextSnp.parts = append(nextSnp.parts, nextIntroduction.newPart)
+	}
 	nextSnp.creator = nextIntroduction.creator
 	tst.replaceSnapshot(&nextSnp, true)
 	if nextIntroduction.applied != nil {
@@ -44,12 +44,19 @@ const (
 
 	defaultFlushTimeout = 5 * time.Second
 	defaultSyncInterval = 30 * time.Second
+	// defaultWriteAckTimeout covers a flush and a sync of the written data points.
+	defaultWriteAckTimeout = 20 * time.Second
 )
 
 type option struct {
 	protector                    protector.Memory
+	remoteStorage                *storage.RemoteStorage
+	scrubber                     *storage.Scrubber
 	tire2Client                  queue.Client
+	peerClient                   queue.Client
 	mergePolicy                  *mergePolicy
+	extraDataPaths               []string
+	antiEntropyCron              string
 	seriesCacheMaxSize           run.Bytes
 	flushTimeout                 time.Duration
 	syncInterval                 time.Duration
@@ -90,6 +97,22 @@ func (m *measure) GetSchema() *databasev1.Measure {
 	return m.schema
 }
 
+func (m *measure) getTSDB() (storage.TSDB[*tsTable, option], error) {
+	var tsdb storage.TSDB[*tsTable, option]
+	db := m.tsdb.Load()
+	if db == nil {
+		var err error
+		tsdb, err = m.schemaRepo.loadTSDB(m.group)
+		if err != nil {
+			return nil, err
+		}
+		m.tsdb.Store(tsdb)
+	} else {
+		tsdb = db.(storage.TSDB[*tsTable, option])
+	}
+	return tsdb, nil
+}
+
 func (m *measure) GetIndexRules() []*databasev1.IndexRule {
 	is := m.indexSchema.Load()
 	if is == nil {
@@ -103,6 +126,37 @@ func (m *measure) OnIndexUpdate(index []*databasev1.IndexRule) {
 	is.indexRules = index
 	is.parse(m.schema)
 	m.indexSchema.Store(is)
+	m.flagUnbuiltIndexRules(index)
+}
+
+// flagUnbuiltIndexRules reports the index rules bound after the data of a shard was written,
+// since the series index is not rebuilt on the existing data.
+func (m *measure) flagUnbuiltIndexRules(rules []*databasev1.IndexRule) {
+	if m.schemaRepo == nil || m.schemaRepo.ruleTracker == nil {
+		return
+	}
+	current := storage.IndexRuleIDs(rules, isSeriesIndexRule)
+	added := m.schemaRepo.ruleTracker.Added(m.group+"/"+m.name, current)
+	tsdb, err := m.getTSDB()
+	if err != nil {
+		m.schemaRepo.l.Debug().Err(err).Str("measure", m.group+"/"+m.name).Msg("skip checking indices before the group is loaded")
+		return
+	}
+	unsupported, err := storage.FlagUnbuiltIndexRules(tsdb, m.name, rules, current, added, func(tst *tsTable) *storage.IndexBuildState {
+		return tst.buildState
+	})
+	if err != nil {
+		m.schemaRepo.l.Error().Err(err).Str("measure", m.group+"/"+m.name).Msg("cannot check the indices of the existing data")
+		return
+	}
+	if len(added) > 0 && len(unsupported) > 0 {
+		m.schemaRepo.l.Warn().Strs("indexRules", storage.IndexRuleNames(rules, unsupported)).Str("measure", m.group+"/"+m.name).
+			Msg("the index rules are not built on the data written before they were bound")
+	}
+}
+
+func isSeriesIndexRule(r *databasev1.IndexRule) bool {
+	return r.GetType() != databasev1.IndexRule_TYPE_SKIPPING
 }
 
 func (m *measure) parseSpec() (err error) {
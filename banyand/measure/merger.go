@@ -26,6 +26,7 @@ import (
 	"github.com/dustin/go-humanize"
 
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/cgroups"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
@@ -50,6 +51,13 @@ func (tst *tsTable) mergeLoop(merges chan *mergerIntroduction, flusherNotifier w
 		select {
 		case <-tst.loopCloser.CloseNotify():
 			return
+		case removal := <-tst.removals:
+			// The removal is introduced between two merges, so no merged part brings the removed data back.
+			select {
+			case merges <- removal:
+			case <-tst.loopCloser.CloseNotify():
+				return
+			}
 		case <-ew.Watch():
 			if func() bool {
 				curSnapshot := tst.currentSnapshot()
@@ -264,6 +272,7 @@ func (tst *tsTable) mergeParts(fileSystem fs.FileSystem, closeCh <-chan struct{}
 		return nil, err
 	}
 	pm.mustWriteMetadata(fileSystem, dstPath)
+	storage.MustWritePartChecksums(fileSystem, dstPath)
 	fileSystem.SyncPath(dstPath)
 	p := mustOpenFilePart(partID, root, fileSystem)
 	return newPartWrapper(nil, p), nil
@@ -31,6 +31,7 @@ import (
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
 	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
 	"github.com/apache/skywalking-banyandb/pkg/test"
@@ -746,6 +747,51 @@ func Test_mergeTwoBlocks_edgeCase(t *testing.T) {
 	require.Equal(t, int64(40), target.bm.timestamps.max, "max timestamp should be 40")
 }
 
+func Test_mergeTwoBlocks_histogram(t *testing.T) {
+	histogram := func(counts ...uint64) []byte {
+		h := encoding.Histogram{Kind: encoding.HistogramKindExplicit, Bounds: []float64{1, 10}, Counts: counts}
+		for _, c := range counts {
+			h.Count += c
+		}
+		return h.Marshal(nil)
+	}
+	left := &blockPointer{
+		block: block{
+			timestamps: []int64{1, 2, 3},
+			versions:   []int64{1, 2, 3},
+			field: columnFamily{
+				columns: []column{
+					{name: "strField", valueType: pbv1.ValueTypeStr, values: [][]byte{[]byte("field1"), []byte("field2"), []byte("field3")}},
+					{name: "latency", valueType: pbv1.ValueTypeHistogram, values: [][]byte{histogram(1, 0, 0), histogram(1, 2, 3), histogram(0, 0, 1)}},
+				},
+			},
+		},
+		bm: blockMetadata{timestamps: timestampsMetadata{min: 1, max: 3}},
+	}
+	right := &blockPointer{
+		block: block{
+			timestamps: []int64{2, 3},
+			versions:   []int64{4, 3},
+			field: columnFamily{
+				columns: []column{
+					{name: "strField", valueType: pbv1.ValueTypeStr, values: [][]byte{[]byte("field2-new"), []byte("field3-dup")}},
+					{name: "latency", valueType: pbv1.ValueTypeHistogram, values: [][]byte{histogram(4, 5, 6), histogram(0, 0, 1)}},
+				},
+			},
+		},
+		bm: blockMetadata{timestamps: timestampsMetadata{min: 2, max: 3}},
+	}
+
+	target := &blockPointer{}
+	mergeTwoBlocks(target, left, right)
+
+	require.Equal(t, []int64{1, 2, 3}, target.timestamps)
+	require.Equal(t, []int64{1, 4, 3}, target.versions)
+	require.Equal(t, []byte("field2-new"), target.field.columns[0].values[1])
+	// the histogram of the newest version wins like the other fields, and the duplicated ones of the same version are kept once
+	require.Equal(t, [][]byte{histogram(1, 0, 0), histogram(4, 5, 6), histogram(0, 0, 1)}, target.field.columns[1].values)
+}
+
 var mergedBlock = block{
 	timestamps: []int64{1, 2, 3, 4},
 	versions:   []int64{1, 4, 5, 6},
@@ -31,9 +31,11 @@ import (
 	"go.uber.org/multierr"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/api/data"
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	"github.com/apache/skywalking-banyandb/api/validate"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	"github.com/apache/skywalking-banyandb/banyand/liaison/grpc"
@@ -43,6 +45,8 @@ import (
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/banyand/queue"
 	"github.com/apache/skywalking-banyandb/banyand/queue/pub"
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 	"github.com/apache/skywalking-banyandb/pkg/meter"
 	resourceSchema "github.com/apache/skywalking-banyandb/pkg/schema"
@@ -80,9 +84,11 @@ type schemaRepo struct {
 	resourceSchema.Repository
 	metadata         metadata.Repo
 	pipeline         queue.Client
+	onGroupDelete    func(groupName string)
 	l                *logger.Logger
 	closingGroups    map[string]struct{}
 	topNProcessorMap sync.Map
+	ruleTracker      *storage.IndexRuleTracker
 	nodeID           string
 	path             string
 	closingGroupsMu  sync.RWMutex
@@ -97,6 +103,7 @@ func newSchemaRepo(path string, svc *standalone, nodeLabels map[string]string, n
 		pipeline:      svc.localPipeline,
 		nodeID:        nodeID,
 		closingGroups: make(map[string]struct{}),
+		ruleTracker:   storage.NewIndexRuleTracker(),
 		role:          databasev1.Role_ROLE_DATA,
 	}
 	sr.Repository = resourceSchema.NewRepository(
@@ -124,6 +131,9 @@ func newLiaisonSchemaRepo(path string, svc *liaison, measureDataNodeRegistry grp
 		newQueueSupplier(path, svc, measureDataNodeRegistry),
 		resourceSchema.NewMetrics(svc.omr.With(metadataScope)),
 	)
+	if svc.handoffCtrl != nil {
+		sr.onGroupDelete = svc.handoffCtrl.DeletePartsByGroup
+	}
 	sr.start()
 	return sr
 }
@@ -279,6 +289,9 @@ func (sr *schemaRepo) OnDelete(metadata schema.Metadata) {
 		if g.Catalog != commonv1.Catalog_CATALOG_MEASURE {
 			return
 		}
+		if sr.onGroupDelete != nil {
+			sr.onGroupDelete(g.Metadata.Name)
+		}
 		// Mark group as closing to prevent new processors from being created during deletion
 		sr.markGroupClosing(g.Metadata.Name)
 		sr.SendMetadataEvent(resourceSchema.MetadataEvent{
@@ -310,6 +323,7 @@ func (sr *schemaRepo) OnDelete(metadata schema.Metadata) {
 
 	case schema.KindIndexRule:
 		if rule, ok := metadata.Spec.(*databasev1.IndexRule); ok {
+			index.RemoveUsage(rule.GetMetadata().GetId())
 			sr.SendMetadataEvent(resourceSchema.MetadataEvent{
 				Typ:      resourceSchema.EventDelete,
 				Kind:     resourceSchema.EventKindIndexRule,
@@ -362,6 +376,25 @@ func (sr *schemaRepo) loadTSDB(groupName string) (storage.TSDB[*tsTable, option]
 	return db.(storage.TSDB[*tsTable, option]), nil
 }
 
+// seriesLimits returns the series limits of the measure merged into the ones of its group, and the size of its entity.
+func (sr *schemaRepo) seriesLimits(m *databasev1.Measure) (storage.SeriesLimits, int) {
+	var groupLimits *commonv1.SeriesLimits
+	if g, ok := sr.LoadGroup(m.GetMetadata().GetGroup()); ok {
+		groupLimits = g.GetSchema().GetResourceOpts().GetSeriesLimits()
+	}
+	return storage.NewSeriesLimits(groupLimits, m.GetSeriesLimits()), len(m.GetEntity().GetTagNames())
+}
+
+// requiredAcks returns the number of the replicas which must persist the writes to the group before the liaison acknowledges them.
+func (sr *schemaRepo) requiredAcks(groupName string) int {
+	g, ok := sr.LoadGroup(groupName)
+	if !ok {
+		return 0
+	}
+	opts := g.GetSchema().GetResourceOpts()
+	return wqueue.RequiredAcks(opts.GetWriteConsistency(), opts.GetReplicas()+1)
+}
+
 func (sr *schemaRepo) loadQueue(groupName string) (*wqueue.Queue[*tsTable, option], error) {
 	g, ok := sr.LoadGroup(groupName)
 	if !ok {
@@ -397,6 +430,11 @@ func (sr *schemaRepo) CollectDataInfo(ctx context.Context, group string) (*datab
 	if segmentsErr != nil {
 		return nil, segmentsErr
 	}
+	indexRules, listErr := sr.metadata.IndexRuleRegistry().ListIndexRule(ctx, schema.ListOpt{Group: group})
+	if listErr != nil {
+		sr.l.Warn().Err(listErr).Str("group", group).Msg("failed to list index rules to collect their usages")
+	}
+	indexRuleSizes := make(map[uint32]int64, len(indexRules))
 	var segmentInfoList []*databasev1.SegmentInfo
 	var totalDataSize int64
 	for _, segment := range segments {
@@ -410,6 +448,11 @@ func (sr *schemaRepo) CollectDataInfo(ctx context.Context, group string) (*datab
 		}
 		seriesIndexInfo := sr.collectSeriesIndexInfo(segment)
 		totalDataSize += seriesIndexInfo.DataSizeBytes
+		if indexDB := segment.IndexDB(); indexDB != nil {
+			for _, r := range indexRules {
+				indexRuleSizes[r.GetMetadata().GetId()] += indexDB.FieldSize(index.FieldKey{IndexRuleID: r.GetMetadata().GetId()})
+			}
+		}
 		segmentInfo := &databasev1.SegmentInfo{
 			SegmentId:       fmt.Sprintf("%d-%d", timeRange.Start.UnixNano(), timeRange.End.UnixNano()),
 			TimeRangeStart:  timeRange.Start.Format(time.RFC3339Nano),
@@ -421,13 +464,36 @@ func (sr *schemaRepo) CollectDataInfo(ctx context.Context, group string) (*datab
 		segment.DecRef()
 	}
 	dataInfo := &databasev1.DataInfo{
-		Node:          node,
-		SegmentInfo:   segmentInfoList,
-		DataSizeBytes: totalDataSize,
+		Node:            node,
+		SegmentInfo:     segmentInfoList,
+		DataSizeBytes:   totalDataSize,
+		IndexRuleUsages: index.CollectUsages(indexRules, indexRuleSizes),
+		ScrubStatus:     tsdb.ScrubStatus(),
 	}
 	return dataInfo, nil
 }
 
+// ScrubGroup starts verifying the checksums of the group's parts in the background, unless a scrub is running.
+func (sr *schemaRepo) ScrubGroup(_ context.Context, group string) error {
+	tsdb, err := sr.loadTSDB(group)
+	if err != nil {
+		return err
+	}
+	if tsdb != nil && !tsdb.Scrub() {
+		sr.l.Info().Str("group", group).Msg("the group is being scrubbed")
+	}
+	return nil
+}
+
+// ReportReadFailure starts a scrub of the group after a read failed.
+func (sr *schemaRepo) ReportReadFailure(group string) {
+	tsdb, err := sr.loadTSDB(group)
+	if err != nil || tsdb == nil {
+		return
+	}
+	tsdb.ReportReadFailure()
+}
+
 func (sr *schemaRepo) collectSeriesIndexInfo(segment storage.Segment[*tsTable, option]) *databasev1.SeriesIndexInfo {
 	indexDB := segment.IndexDB()
 	if indexDB == nil {
@@ -473,6 +539,7 @@ func (sr *schemaRepo) collectShardInfo(table any, shardID uint32) *databasev1.Sh
 		PartCount:         int64(partCount),
 		InvertedIndexInfo: &databasev1.InvertedIndexInfo{},
 		SidxInfo:          &databasev1.SIDXInfo{},
+		IndexBuilds:       tst.buildState.Info(),
 	}
 }
 
@@ -679,6 +746,7 @@ func (s *supplier) OpenDB(groupSchema *commonv1.Group) (resourceSchema.DB, error
 	segInterval := ro.SegmentInterval
 	segmentIdleTimeout := time.Duration(0)
 	disableRetention := false
+	remoteStage := false
 	if len(ro.Stages) > 0 && len(s.nodeLabels) > 0 {
 		var ttlNum uint32
 		foundMatched := false
@@ -702,6 +770,7 @@ func (s *supplier) OpenDB(groupSchema *commonv1.Group) (resourceSchema.DB, error
 				segmentIdleTimeout = 5 * time.Minute
 			}
 			disableRetention = i+1 < len(ro.Stages)
+			remoteStage = st.Remote
 			break
 		}
 		if !foundMatched {
@@ -723,6 +792,29 @@ func (s *supplier) OpenDB(groupSchema *commonv1.Group) (resourceSchema.DB, error
 		SegmentIdleTimeout:             segmentIdleTimeout,
 		DisableRetention:               disableRetention,
 		MemoryLimit:                    s.pm.GetLimit(),
+		Scrubber:                       s.option.scrubber,
+	}
+	for _, dataPath := range s.option.extraDataPaths {
+		opts.ExtraLocations = append(opts.ExtraLocations, path.Join(dataPath, group))
+	}
+	if s.option.peerClient != nil {
+		catalog := &replicaCatalog{schemaRepo: s.schemaRepo, client: s.option.peerClient, l: s.l}
+		opts.RepairPart = func(shardID common.ShardID, timeRange timestamp.TimeRange, partID uint64) error {
+			return storage.RepairPart(s.option.peerClient, catalog, data.TopicMeasureRepairShard, s.schemaRepo.nodeID, group, shardID, timeRange, partID)
+		}
+		opts.AntiEntropy = func(shardID common.ShardID, timeRange timestamp.TimeRange) (storage.AntiEntropyResult, error) {
+			return storage.AntiEntropy(s.option.peerClient, catalog, data.TopicMeasureAntiEntropyDigest, data.TopicMeasureRepairShard,
+				s.schemaRepo.nodeID, group, shardID, timeRange)
+		}
+		opts.AntiEntropyCron = s.option.antiEntropyCron
+	}
+	if remoteStage {
+		if s.option.remoteStorage == nil {
+			s.l.Warn().Str("group", group).Msg("the stage is remote-backed but no remote storage is configured, keep its data on the local disk")
+		} else {
+			opts.RemoteStorage = s.option.remoteStorage
+			opts.KeepLocal = keepPartFileLocally
+		}
 	}
 	return storage.OpenTSDB(
 		common.SetPosition(context.Background(), func(_ common.Position) common.Position {
@@ -738,6 +830,7 @@ type queueSupplier struct {
 	measureDataNodeRegistry grpc.NodeRegistry
 	l                       *logger.Logger
 	schemaRepo              *schemaRepo
+	handoffCtrl             *handoff.Controller
 	path                    string
 	option                  option
 }
@@ -755,6 +848,7 @@ func newQueueSupplier(path string, svc *liaison, measureDataNodeRegistry grpc.No
 		pm:                      svc.pm,
 		measureDataNodeRegistry: measureDataNodeRegistry,
 		l:                       svc.l,
+		handoffCtrl:             svc.handoffCtrl,
 		path:                    path,
 		option:                  opt,
 	}
@@ -792,7 +886,11 @@ func (s *queueSupplier) OpenDB(groupSchema *commonv1.Group) (resourceSchema.DB,
 		Location:        path.Join(s.path, group),
 		Option:          s.option,
 		Metrics:         s.newMetrics(p),
-		SubQueueCreator: newWriteQueue,
+		SubQueueCreator: func(fileSystem fs.FileSystem, root string, position common.Position,
+			l *logger.Logger, option option, metrics any, group string, shardID common.ShardID, getNodes func() []string,
+		) (*tsTable, error) {
+			return newWriteQueue(fileSystem, root, position, l, option, metrics, group, shardID, getNodes, s.handoffCtrl)
+		},
 		GetNodes: func(shardID common.ShardID) []string {
 			copies := ro.Replicas + 1
 			nodeSet := make(map[string]struct{}, copies)
@@ -224,6 +224,7 @@ func (mp *memPart) mustFlush(fileSystem fs.FileSystem, path string) {
 	}
 
 	mp.partMetadata.mustWriteMetadata(fileSystem, path)
+	storage.MustWritePartChecksums(fileSystem, path)
 
 	fileSystem.SyncPath(path)
 }
@@ -364,6 +365,17 @@ func mustOpenReader(name string, fileSystem fs.FileSystem) fs.Reader {
 	return f
 }
 
+// keepPartFileLocally reports whether a part file stays on the local disk when the part is offloaded.
+// Metadata and block indexes are needed to plan queries, while timestamps, field values and
+// tag family blocks are fetched from the remote storage on demand.
+func keepPartFileLocally(name string) bool {
+	switch name {
+	case metadataFilename, metaFilename, primaryFilename, seriesMetadataFilename:
+		return true
+	}
+	return filepath.Ext(name) == tagFamiliesMetadataFilenameExt
+}
+
 func removeExt(nameWithExt, ext string) string {
 	return nameWithExt[:len(nameWithExt)-len(ext)]
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/apache/skywalking-banyandb/pkg/compress/zstd"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 	"github.com/apache/skywalking-banyandb/pkg/pool"
 )
@@ -37,6 +38,7 @@ type partIter struct {
 	err                  error
 	p                    *part
 	c                    storage.Cache
+	blockFilter          index.Filter
 	curBlock             *blockMetadata
 	sids                 []common.SeriesID
 	primaryBlockMetadata []primaryBlockMetadata
@@ -52,6 +54,7 @@ func (pi *partIter) reset() {
 	pi.curBlock = nil
 	pi.p = nil
 	pi.c = nil
+	pi.blockFilter = nil
 	pi.sids = nil
 	pi.sidIdx = 0
 	pi.primaryBlockMetadata = nil
@@ -61,11 +64,12 @@ func (pi *partIter) reset() {
 	pi.err = nil
 }
 
-func (pi *partIter) init(p *part, sids []common.SeriesID, minTimestamp, maxTimestamp int64) {
+func (pi *partIter) init(p *part, sids []common.SeriesID, minTimestamp, maxTimestamp int64, blockFilter index.Filter) {
 	pi.reset()
 	pi.curBlock = &blockMetadata{}
 	pi.p = p
 	pi.c = p.cache
+	pi.blockFilter = blockFilter
 
 	pi.sids = sids
 	pi.minTimestamp = minTimestamp
@@ -152,8 +156,8 @@ func (pi *partIter) loadNextBlockMetadata() bool {
 
 		err := pi.readPrimaryBlock(pbm)
 		if err != nil {
-			pi.err = fmt.Errorf("cannot read primary block for part %q at offset %d with size %d: %w",
-				&pi.p.partMetadata, pbm.offset, pbm.size, err)
+			pi.err = fmt.Errorf("cannot read primary block for part %q at offset %d with size %d: %w: %w",
+				&pi.p.partMetadata, pbm.offset, pbm.size, storage.ErrPartCorrupted, err)
 			return false
 		}
 		return true
@@ -240,6 +244,24 @@ func (pi *partIter) findBlock() bool {
 			continue
 		}
 
+		if pi.blockFilter != nil {
+			shouldSkip, err := func() (bool, error) {
+				zm := generateZoneMaps()
+				defer releaseZoneMaps(zm)
+				zm.init(bm, pi.p)
+				return pi.blockFilter.ShouldSkip(zm)
+			}()
+			if err != nil {
+				pi.err = err
+				return false
+			}
+			if shouldSkip {
+				// The later blocks of the series might still match.
+				bhs = bhs[1:]
+				continue
+			}
+		}
+
 		pi.curBlock.copyFrom(bm)
 
 		pi.bms = bhs[1:]
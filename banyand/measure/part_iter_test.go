@@ -19,6 +19,7 @@ package measure
 
 import (
 	"errors"
+	"math"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -29,6 +30,8 @@ import (
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+	"github.com/apache/skywalking-banyandb/pkg/index/posting"
 	"github.com/apache/skywalking-banyandb/pkg/test"
 )
 
@@ -115,7 +118,7 @@ func Test_partIter_nextBlock(t *testing.T) {
 			verifyPart := func(p *part) {
 				defer p.close()
 				pi := partIter{}
-				pi.init(p, tt.sids, tt.opt.minTimestamp, tt.opt.maxTimestamp)
+				pi.init(p, tt.sids, tt.opt.minTimestamp, tt.opt.maxTimestamp, nil)
 
 				var got []blockMetadata
 				for pi.nextBlock() {
@@ -162,6 +165,86 @@ func Test_partIter_nextBlock(t *testing.T) {
 	}
 }
 
+type intRangeFilter struct {
+	name string
+	opts index.RangeOpts
+}
+
+func (f *intRangeFilter) Execute(_ index.GetSearcher, _ common.SeriesID, _ *index.RangeOpts) (posting.List, posting.List, error) {
+	panic("not implemented")
+}
+
+func (f *intRangeFilter) ShouldSkip(op index.FilterOp) (bool, error) {
+	return op.Range(f.name, f.opts)
+}
+
+func (f *intRangeFilter) String() string {
+	return f.name
+}
+
+func Test_partIter_skipBlocksByZoneMaps(t *testing.T) {
+	tests := []struct {
+		filter index.Filter
+		name   string
+		want   []common.SeriesID
+	}{
+		{
+			name:   "Test with the values above the zone map",
+			filter: &intRangeFilter{name: "intTag", opts: index.NewIntRangeOpts(20, math.MaxInt64, false, false)},
+			want:   []common.SeriesID{2, 3},
+		},
+		{
+			name:   "Test with the values including the max of the zone map",
+			filter: &intRangeFilter{name: "intTag", opts: index.NewIntRangeOpts(20, math.MaxInt64, true, false)},
+			want:   []common.SeriesID{1, 2, 3},
+		},
+		{
+			name:   "Test with the values below the zone map",
+			filter: &intRangeFilter{name: "intTag", opts: index.NewIntRangeOpts(math.MinInt64, 10, false, false)},
+			want:   []common.SeriesID{2, 3},
+		},
+		{
+			name:   "Test with the value in the zone map",
+			filter: &intRangeFilter{name: "intTag", opts: index.NewIntRangeOpts(15, 15, true, true)},
+			want:   []common.SeriesID{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifyPart := func(p *part) {
+				defer p.close()
+				pi := partIter{}
+				pi.init(p, []common.SeriesID{1, 2, 3}, 1, 220, tt.filter)
+
+				var got []common.SeriesID
+				for pi.nextBlock() {
+					got = append(got, pi.curBlock.seriesID)
+				}
+				require.NoError(t, pi.error())
+				require.Equal(t, tt.want, got)
+			}
+			mp := generateMemPart()
+			releaseMemPart(mp)
+			mp.mustInitFromDataPoints(dps)
+
+			p := openMemPart(mp)
+			shardCache := storage.NewShardCache("test-group", 0, 0)
+			p.cache = shardCache
+			verifyPart(p)
+			tmpDir, defFn := test.Space(require.New(t))
+			defer defFn()
+			epoch := uint64(1)
+			partPath := partPath(tmpDir, epoch)
+			fileSystem := fs.NewLocalFileSystem()
+			mp.mustFlush(fileSystem, partPath)
+			p = mustOpenFilePart(epoch, tmpDir, fileSystem)
+			p.cache = shardCache
+			verifyPart(p)
+		})
+	}
+}
+
 func Test_partMergeIter_nextBlock(t *testing.T) {
 	tests := []struct {
 		wantErr error
@@ -18,6 +18,9 @@
 package measure
 
 import (
+	"context"
+	"io"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -27,7 +30,10 @@ import (
 	"github.com/apache/skywalking-banyandb/api/common"
 	"github.com/apache/skywalking-banyandb/pkg/convert"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/local"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/tiered"
 	"github.com/apache/skywalking-banyandb/pkg/index"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
 	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
 	"github.com/apache/skywalking-banyandb/pkg/test"
 )
@@ -339,3 +345,49 @@ func TestSeriesMetadataBackwardCompatibility(t *testing.T) {
 	assert.NotNil(t, p, "part should be opened successfully")
 	assert.Nil(t, p.seriesMetadata, "series metadata reader should be nil for old parts")
 }
+
+func TestOpenOffloadedFilePart(t *testing.T) {
+	tmpPath, defFn := test.Space(require.New(t))
+	defer defFn()
+
+	fileSystem := fs.NewLocalFileSystem()
+	epoch := uint64(54321)
+	path := partPath(tmpPath, epoch)
+	mp := generateMemPart()
+	mp.mustInitFromDataPoints(dps)
+	mp.mustFlush(fileSystem, path)
+	releaseMemPart(mp)
+
+	fieldValues, err := os.ReadFile(filepath.Join(path, fieldValuesFilename))
+	require.NoError(t, err)
+	localPart := mustOpenFilePart(epoch, tmpPath, fileSystem)
+	defer localPart.close()
+
+	remoteFS, err := local.NewFS(t.TempDir())
+	require.NoError(t, err)
+	cache, err := tiered.NewCache(t.TempDir(), 1<<20)
+	require.NoError(t, err)
+	tieredFS := tiered.NewFileSystem(fileSystem, remoteFS, cache, tmpPath, logger.GetLogger("test"))
+	n, err := tieredFS.OffloadDir(context.Background(), path, keepPartFileLocally)
+	require.NoError(t, err)
+	require.Positive(t, n)
+	for _, name := range []string{fieldValuesFilename, timestampsFilename} {
+		_, err = os.Stat(filepath.Join(path, name))
+		assert.True(t, os.IsNotExist(err), "%s should be offloaded", name)
+	}
+	for _, name := range []string{metadataFilename, metaFilename, primaryFilename} {
+		_, err = os.Stat(filepath.Join(path, name))
+		assert.NoError(t, err, "%s should stay local", name)
+	}
+
+	p := mustOpenFilePart(epoch, tmpPath, tieredFS)
+	defer p.close()
+	assert.Equal(t, localPart.partMetadata, p.partMetadata)
+	assert.Equal(t, localPart.primaryBlockMetadata, p.primaryBlockMetadata)
+	assert.Equal(t, len(localPart.tagFamilies), len(p.tagFamilies))
+	assert.Equal(t, len(localPart.tagFamilyMetadata), len(p.tagFamilyMetadata))
+	sr := p.fieldValues.SequentialRead()
+	data, err := io.ReadAll(sr)
+	require.NoError(t, err)
+	assert.Equal(t, fieldValues, data)
+}
@@ -60,6 +60,13 @@ type Measure interface {
 	Query(ctx context.Context, opts model.MeasureQueryOptions) (model.MeasureQueryResult, error)
 	GetSchema() *databasev1.Measure
 	GetIndexRules() []*databasev1.IndexRule
+	// TagValues returns the distinct values of an entity tag or an indexed tag starting with the prefix in the time range.
+	TagValues(ctx context.Context, tagName string, timeRange timestamp.TimeRange, prefix string, limit int) ([]string, error)
+	// ListSeries lists at most limit series in the time range whose encoded entity values are greater than after.
+	// It returns whether there are more series.
+	ListSeries(ctx context.Context, timeRange timestamp.TimeRange, after []byte, limit int) (pbv1.SeriesList, bool, error)
+	// SeriesCardinality breaks down the series in the time range by segments and the values of the entity tags.
+	SeriesCardinality(ctx context.Context, timeRange timestamp.TimeRange, tags []string, topN int) ([]*databasev1.SeriesCardinalityBucket, error)
 }
 
 var _ Measure = (*measure)(nil)
@@ -84,16 +91,9 @@ func (m *measure) Query(ctx context.Context, mqo model.MeasureQueryOptions) (mqr
 		return nil, errors.New("invalid query options: tagProjection or fieldProjection is required")
 	}
 
-	var tsdb storage.TSDB[*tsTable, option]
-	db := m.tsdb.Load()
-	if db == nil {
-		tsdb, err = m.schemaRepo.loadTSDB(m.group)
-		if err != nil {
-			return nil, err
-		}
-		m.tsdb.Store(tsdb)
-	} else {
-		tsdb = db.(storage.TSDB[*tsTable, option])
+	tsdb, err := m.getTSDB()
+	if err != nil {
+		return nil, err
 	}
 
 	segments, err := tsdb.SelectSegments(*mqo.TimeRange)
@@ -502,7 +502,7 @@ func (m *measure) searchBlocks(ctx context.Context, result *queryResult, sids []
 	originalSids := make([]common.SeriesID, len(sids))
 	copy(originalSids, sids)
 	sort.Slice(sids, func(i, j int) bool { return sids[i] < sids[j] })
-	tstIter.init(parts, sids, qo.minTimestamp, qo.maxTimestamp)
+	tstIter.init(parts, sids, qo.minTimestamp, qo.maxTimestamp, qo.SkippingFilter)
 	if tstIter.Error() != nil {
 		return fmt.Errorf("cannot init tstIter: %w", tstIter.Error())
 	}
@@ -646,6 +646,12 @@ func mustDecodeFieldValue(valueType pbv1.ValueType, value []byte) *modelv1.Field
 		return strFieldValue(string(value))
 	case pbv1.ValueTypeBinaryData:
 		return binaryDataFieldValue(value)
+	case pbv1.ValueTypeHistogram:
+		h, err := pbv1.UnmarshalHistogram(value)
+		if err != nil {
+			logger.Panicf("cannot decode histogram: %v", err)
+		}
+		return &modelv1.FieldValue{Value: &modelv1.FieldValue_Histogram{Histogram: h}}
 	default:
 		logger.Panicf("unsupported value type: %v", valueType)
 		return nil
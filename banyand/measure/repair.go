@@ -0,0 +1,242 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package measure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/api/data"
+	clusterv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/cluster/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/banyand/queue"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const repairChunkSize = 512 * 1024
+
+// replicaCatalog loads the local shards of the measures for the comparisons and the repairs.
+type replicaCatalog struct {
+	schemaRepo *schemaRepo
+	client     queue.Client
+	l          *logger.Logger
+}
+
+func (rc *replicaCatalog) LoadReplicaShard(group string, shardID common.ShardID, segmentStart time.Time) (storage.ReplicaShard, error) {
+	tst, segment, err := rc.schemaRepo.loadTable(group, shardID, segmentStart)
+	if err != nil {
+		return nil, err
+	}
+	if tst == nil {
+		segment.DecRef()
+		return nil, nil
+	}
+	return &replicaShard{tst: tst, segment: segment, client: rc.client, l: rc.l}, nil
+}
+
+// replicaShard implements storage.ReplicaShard for the table of a measure shard in a segment.
+type replicaShard struct {
+	tst     *tsTable
+	segment storage.Segment[*tsTable, option]
+	client  queue.Client
+	l       *logger.Logger
+}
+
+func (rs *replicaShard) TimeRange() timestamp.TimeRange {
+	return rs.segment.GetTimeRange()
+}
+
+func (rs *replicaShard) Digest() (*storage.AntiEntropyDigest, error) {
+	return rs.tst.antiEntropyDigest(rs.segment.GetTimeRange())
+}
+
+func (rs *replicaShard) PartTimeRange(partID uint64) (int64, int64, bool) {
+	snp := rs.tst.currentSnapshot()
+	if snp == nil {
+		return 0, 0, false
+	}
+	defer snp.decRef()
+	for _, pw := range snp.parts {
+		if pw.mp == nil && pw.ID() == partID {
+			return pw.p.partMetadata.MinTimestamp, pw.p.partMetadata.MaxTimestamp, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (rs *replicaShard) RowHashes(set *storage.AntiEntropyBucketSet, excludedParts map[uint64]struct{}) ([]uint64, error) {
+	return rs.tst.antiEntropyRowHashes(rs.segment.GetTimeRange(), set, excludedParts)
+}
+
+func (rs *replicaShard) RemoveParts(partIDs map[uint64]struct{}) error {
+	return rs.tst.removeParts(partIDs)
+}
+
+// PushMissingRows pushes the rows in the requested buckets which the requesting node doesn't hold as a new part,
+// along with the documents of their series.
+func (rs *replicaShard) PushMissingRows(req *clusterv1.RepairShardRequest) (storage.RepairPushResult, error) {
+	var result storage.RepairPushResult
+	snp := rs.tst.currentSnapshot()
+	if snp == nil {
+		return result, nil
+	}
+	defer snp.decRef()
+	// The rows of the memory parts are compared as well, so they are pushed if missing.
+	parts := make([]*part, 0, len(snp.parts))
+	for _, pw := range snp.parts {
+		if pw.p.partMetadata.TotalCount > 0 {
+			parts = append(parts, pw.p)
+		}
+	}
+	known := make(map[uint64]struct{}, len(req.RowHashes))
+	for _, h := range req.RowHashes {
+		known[h] = struct{}{}
+	}
+	mp, seriesIDs, err := buildAntiEntropyPart(parts, rs.segment.GetTimeRange(), storage.NewAntiEntropyBucketSet(req.Buckets), known)
+	if err != nil || mp == nil {
+		return result, err
+	}
+	defer releaseMemPart(mp)
+	// The series must be indexed before their rows can be queried.
+	if err = storage.PushSeriesDocuments(rs.client, data.TopicMeasureSeriesIndexInsert, rs.l, req, rs.segment, seriesIDs); err != nil {
+		return result, err
+	}
+	synced, err := rs.push(req, []*part{openMemPart(mp)})
+	if synced != nil {
+		result.Parts = synced.PartsCount
+		result.Bytes = synced.TotalBytes
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Rows = mp.partMetadata.TotalCount
+	return result, nil
+}
+
+func (rs *replicaShard) Release() {
+	rs.segment.DecRef()
+}
+
+// loadTable returns the table of the shard in the segment starting at segmentStart, which may be nil.
+// The caller must release the segment.
+func (sr *schemaRepo) loadTable(group string, shardID common.ShardID, segmentStart time.Time) (*tsTable, storage.Segment[*tsTable, option], error) {
+	tsdb, err := sr.loadTSDB(group)
+	if err != nil {
+		return nil, nil, err
+	}
+	segments, err := tsdb.SelectSegments(timestamp.NewInclusiveTimeRange(segmentStart, segmentStart))
+	if err != nil {
+		return nil, nil, err
+	}
+	var segment storage.Segment[*tsTable, option]
+	for _, s := range segments {
+		if segment == nil && s.GetTimeRange().Start.Equal(segmentStart) {
+			segment = s
+			continue
+		}
+		s.DecRef()
+	}
+	if segment == nil {
+		return nil, nil, fmt.Errorf("segment starting at %s is not found", segmentStart)
+	}
+	tables, shardIDs, _ := segment.TablesWithShardIDs()
+	for i := range tables {
+		if shardIDs[i] == shardID {
+			return tables[i], segment, nil
+		}
+	}
+	return nil, segment, nil
+}
+
+// removeParts drops the parts from the table and removes their files once no query reads them.
+func (tst *tsTable) removeParts(ids map[uint64]struct{}) error {
+	if tst.removals == nil {
+		return errors.New("the table doesn't support removing parts")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	ind := &mergerIntroduction{
+		merged:  ids,
+		applied: make(chan struct{}),
+		creator: snapshotCreatorRepairer,
+	}
+	select {
+	case tst.removals <- ind:
+	case <-tst.loopCloser.CloseNotify():
+		return errClosed
+	}
+	select {
+	case <-ind.applied:
+		return nil
+	case <-tst.loopCloser.CloseNotify():
+		return errClosed
+	}
+}
+
+// push pushes the parts to the node repairing the shard over the chunked part sync.
+func (rs *replicaShard) push(req *clusterv1.RepairShardRequest, parts []*part) (*queue.SyncResult, error) {
+	client, err := rs.client.NewChunkedSyncClient(req.Node, repairChunkSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chunked sync client for node %s: %w", req.Node, err)
+	}
+	defer client.Close()
+	streamingParts := make([]queue.StreamingPartData, 0, len(parts))
+	releaseFuncs := make([]func(), 0, len(parts))
+	defer func() {
+		for _, release := range releaseFuncs {
+			release()
+		}
+	}()
+	for _, p := range parts {
+		files, release := createPartFileReaders(p)
+		releaseFuncs = append(releaseFuncs, release)
+		streamingParts = append(streamingParts, queue.StreamingPartData{
+			ID:                    p.partMetadata.ID,
+			Group:                 req.Group,
+			ShardID:               req.ShardId,
+			Topic:                 data.TopicMeasurePartSync.String(),
+			Files:                 files,
+			CompressedSizeBytes:   p.partMetadata.CompressedSizeBytes,
+			UncompressedSizeBytes: p.partMetadata.UncompressedSizeBytes,
+			TotalCount:            p.partMetadata.TotalCount,
+			BlocksCount:           p.partMetadata.BlocksCount,
+			MinTimestamp:          p.partMetadata.MinTimestamp,
+			MaxTimestamp:          p.partMetadata.MaxTimestamp,
+			PartType:              PartTypeCore,
+		})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), storage.RepairTimeout)
+	defer cancel()
+	result, err := client.SyncStreamingParts(ctx, streamingParts)
+	if err != nil {
+		rs.l.Error().Err(err).Str("group", req.Group).Uint32("shard", req.ShardId).Str("node", req.Node).
+			Msg("failed to push the parts of the shard for repair")
+		return nil, err
+	}
+	if len(result.FailedParts) > 0 {
+		err = fmt.Errorf("%d of %d parts failed to be pushed", len(result.FailedParts), len(parts))
+	}
+	rs.l.Info().Err(err).Str("group", req.Group).Uint32("shard", req.ShardId).Str("node", req.Node).
+		Uint32("parts", result.PartsCount).Uint64("bytes", result.TotalBytes).Msg("pushed the parts of the shard for repair")
+	return result, err
+}
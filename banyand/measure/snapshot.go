@@ -59,6 +59,7 @@ const (
 	snapshotCreatorMerger
 	snapshotCreatorMergedFlusher
 	snapshotCreatorSyncer
+	snapshotCreatorRepairer
 )
 
 type snapshot struct {
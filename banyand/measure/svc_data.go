@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
 	"go.uber.org/multierr"
 
 	"github.com/apache/skywalking-banyandb/api/common"
@@ -73,6 +74,8 @@ type dataSVC struct {
 	dataPath           string
 	snapshotDir        string
 	option             option
+	remoteConfig       storage.RemoteStorageConfig
+	scrubConfig        storage.ScrubConfig
 	retentionConfig    storage.RetentionConfig
 	cc                 storage.CacheConfig
 	maxFileSnapshotNum int
@@ -101,8 +104,8 @@ func (s *dataSVC) GetRemovalSegmentsTimeRange(group string) *timestamp.TimeRange
 
 // RetentionService interface implementation.
 
-func (s *dataSVC) GetDataPath() string {
-	return s.dataPath
+func (s *dataSVC) GetDataPaths() []string {
+	return append([]string{s.dataPath}, s.option.extraDataPaths...)
 }
 
 func (s *dataSVC) GetSnapshotDir() string {
@@ -113,7 +116,7 @@ func (s *dataSVC) LoadAllGroups() []resourceSchema.Group {
 	return s.schemaRepo.LoadAllGroups()
 }
 
-func (s *dataSVC) PeekOldestSegmentEndTimeInGroup(group string) (time.Time, bool) {
+func (s *dataSVC) PeekOldestSegmentEndTimeInGroup(group, volume string) (time.Time, bool) {
 	g, ok := s.schemaRepo.LoadGroup(group)
 	if !ok {
 		return time.Time{}, false
@@ -125,14 +128,16 @@ func (s *dataSVC) PeekOldestSegmentEndTimeInGroup(group string) (time.Time, bool
 	}
 
 	// Type assert to the storage interface that has PeekOldestSegmentEndTime
-	if dbWithPeek, ok := db.(interface{ PeekOldestSegmentEndTime() (time.Time, bool) }); ok {
-		return dbWithPeek.PeekOldestSegmentEndTime()
+	if dbWithPeek, ok := db.(interface {
+		PeekOldestSegmentEndTime(volume string) (time.Time, bool)
+	}); ok {
+		return dbWithPeek.PeekOldestSegmentEndTime(volume)
 	}
 
 	return time.Time{}, false
 }
 
-func (s *dataSVC) DeleteOldestSegmentInGroup(group string) (bool, error) {
+func (s *dataSVC) DeleteOldestSegmentInGroup(group, volume string) (bool, error) {
 	g, ok := s.schemaRepo.LoadGroup(group)
 	if !ok {
 		return false, nil
@@ -144,8 +149,10 @@ func (s *dataSVC) DeleteOldestSegmentInGroup(group string) (bool, error) {
 	}
 
 	// Type assert to the storage interface that has DeleteOldestSegment
-	if dbWithDelete, ok := db.(interface{ DeleteOldestSegment() (bool, error) }); ok {
-		return dbWithDelete.DeleteOldestSegment()
+	if dbWithDelete, ok := db.(interface {
+		DeleteOldestSegment(volume string) (bool, error)
+	}); ok {
+		return dbWithDelete.DeleteOldestSegment(volume)
 	}
 
 	s.l.Debug().Str("group", group).Msg("database does not support DeleteOldestSegment")
@@ -169,6 +176,8 @@ func (s *dataSVC) FlagSet() *run.FlagSet {
 	flagS := run.NewFlagSet("storage")
 	flagS.StringVar(&s.root, "measure-root-path", "/tmp", "the root path of measure")
 	flagS.StringVar(&s.dataPath, "measure-data-path", "", "the data directory path of measure. If not set, <measure-root-path>/measure/data will be used")
+	flagS.StringSliceVar(&s.option.extraDataPaths, "measure-extra-data-paths", nil,
+		"the additional data directory paths of measure, usually one per disk. New segments and shards are spread across them and the data path by free space")
 	flagS.DurationVar(&s.option.flushTimeout, "measure-flush-timeout", defaultFlushTimeout, "the memory data timeout of measure")
 	s.option.mergePolicy = newDefaultMergePolicy()
 	flagS.VarP(&s.option.mergePolicy.maxFanOutSize, "measure-max-fan-out-size", "", "the upper bound of a single file size after merge of measure")
@@ -185,6 +194,23 @@ func (s *dataSVC) FlagSet() *run.FlagSet {
 
 	flagS.IntVar(&s.maxFileSnapshotNum, "measure-max-file-snapshot-num", 10, "the maximum number of file snapshots allowed")
 	flagS.DurationVar(&s.minFileSnapshotAge, "measure-min-file-snapshot-age", time.Hour, "minimum age for file snapshots to be eligible for deletion")
+
+	// Remote storage flags of remote-backed lifecycle stages
+	flagS.StringVar(&s.remoteConfig.Dest, "measure-remote-storage-dest", "", "the object storage URL of remote-backed stages (e.g., s3:///bucket/path)")
+	flagS.StringVar(&s.remoteConfig.ConfigFile, "measure-remote-storage-config", "", "the JSON or YAML file holding the credentials of the object storage")
+	flagS.StringVar(&s.remoteConfig.CachePath, "measure-remote-storage-cache-path", "",
+		"the read-through cache directory of offloaded data. If not set, <measure-root-path>/measure/remote-cache will be used")
+	s.remoteConfig.CacheSize = run.Bytes(10 << 30)
+	flagS.VarP(&s.remoteConfig.CacheSize, "measure-remote-storage-cache-size", "", "the capacity of the read-through cache of offloaded data")
+	flagS.DurationVar(&s.remoteConfig.OffloadDelay, "measure-remote-storage-offload-delay", time.Hour,
+		"how long a segment stays on the local disk after its end time before being offloaded")
+
+	// Scrub flags of the background verification of the part checksums
+	flagS.DurationVar(&s.scrubConfig.Interval, "measure-scrub-interval", 24*time.Hour, "the interval between two scrubs of a group. Periodic scrubs are disabled if it's 0")
+	s.scrubConfig.Rate = run.Bytes(16 << 20)
+	flagS.VarP(&s.scrubConfig.Rate, "measure-scrub-rate", "", "the maximum bytes per second read by the scrubs of all the groups. Scrubs are not throttled if it's 0")
+	flagS.StringVar(&s.option.antiEntropyCron, "measure-anti-entropy-cron", "@every 6h",
+		"the cron expression of comparing the settled data with the replicas and pulling the missing rows. The anti-entropy repair is disabled if it's empty")
 	s.cc.MaxCacheSize = run.Bytes(100 * 1024 * 1024)
 	flagS.VarP(&s.cc.MaxCacheSize, "service-cache-max-size", "", "maximum service cache size (e.g., 100M)")
 	flagS.DurationVar(&s.cc.CleanupInterval, "service-cache-cleanup-interval", 30*time.Second, "service cache cleanup interval")
@@ -214,6 +240,24 @@ func (s *dataSVC) Validate() error {
 		return errors.New("measure-retention-cooldown must be greater than 0")
 	}
 
+	if s.remoteConfig.CacheSize < 0 {
+		return errors.New("measure-remote-storage-cache-size must be greater than or equal to 0")
+	}
+	if s.remoteConfig.OffloadDelay < 0 {
+		return errors.New("measure-remote-storage-offload-delay must be greater than or equal to 0")
+	}
+	if s.scrubConfig.Interval < 0 {
+		return errors.New("measure-scrub-interval must be greater than or equal to 0")
+	}
+	if s.scrubConfig.Rate < 0 {
+		return errors.New("measure-scrub-rate must be greater than or equal to 0")
+	}
+	if s.option.antiEntropyCron != "" {
+		if _, err := cron.ParseStandard(s.option.antiEntropyCron); err != nil {
+			return errors.New("measure-anti-entropy-cron is not a valid cron expression")
+		}
+	}
+
 	if s.cc.MaxCacheSize < 0 {
 		return errors.New("service-cache-max-size must be greater than or equal to 0")
 	}
@@ -256,6 +300,15 @@ func (s *dataSVC) PreRun(ctx context.Context) error {
 	if !strings.HasPrefix(filepath.VolumeName(s.dataPath), filepath.VolumeName(path)) {
 		obsservice.UpdatePath(s.dataPath)
 	}
+	for i := range s.option.extraDataPaths {
+		if s.option.extraDataPaths[i], err = banyandbpath.Get(s.option.extraDataPaths[i]); err != nil {
+			return err
+		}
+		obsservice.UpdatePath(s.option.extraDataPaths[i])
+	}
+	if err = storage.CheckDataPaths(s.GetDataPaths()...); err != nil {
+		return err
+	}
 	val := ctx.Value(common.ContextNodeKey)
 	if val == nil {
 		return errors.New("node id is empty")
@@ -265,6 +318,13 @@ func (s *dataSVC) PreRun(ctx context.Context) error {
 	} else {
 		s.c = storage.NewServiceCacheWithConfig(s.cc)
 	}
+	if s.remoteConfig.CachePath == "" {
+		s.remoteConfig.CachePath = filepath.Join(path, storage.RemoteCacheDir)
+	}
+	if s.option.remoteStorage, err = storage.OpenRemoteStorage(s.remoteConfig, s.dataPath); err != nil {
+		return err
+	}
+	s.option.scrubber = storage.NewScrubber(s.scrubConfig)
 	node := val.(common.Node)
 	s.schemaRepo = newDataSchemaRepo(s.dataPath, s, node.Labels, node.NodeID)
 
@@ -282,6 +342,15 @@ func (s *dataSVC) PreRun(ctx context.Context) error {
 	if dropGroupErr := s.pipeline.Subscribe(data.TopicMeasureDropGroup, &dropGroupDataListener{s: s}); dropGroupErr != nil {
 		return fmt.Errorf("failed to subscribe to drop group topic: %w", dropGroupErr)
 	}
+	if s.option.peerClient != nil {
+		catalog := &replicaCatalog{schemaRepo: s.schemaRepo, client: s.option.peerClient, l: s.l}
+		if repairErr := s.pipeline.Subscribe(data.TopicMeasureRepairShard, storage.NewRepairShardListener(catalog)); repairErr != nil {
+			return fmt.Errorf("failed to subscribe to repair shard topic: %w", repairErr)
+		}
+		if digestErr := s.pipeline.Subscribe(data.TopicMeasureAntiEntropyDigest, storage.NewAntiEntropyDigestListener(catalog)); digestErr != nil {
+			return fmt.Errorf("failed to subscribe to anti-entropy digest topic: %w", digestErr)
+		}
+	}
 
 	if err = s.createDataNativeObservabilityGroup(ctx); err != nil {
 		return err
@@ -308,6 +377,9 @@ func (s *dataSVC) PreRun(ctx context.Context) error {
 
 	// Initialize disk monitor for forced retention
 	s.diskMonitor = storage.NewDiskMonitor(s, s.retentionConfig, s.omr)
+	if s.option.remoteStorage != nil {
+		s.diskMonitor.SetRemoteCache(s.option.remoteStorage.Cache())
+	}
 	s.diskMonitor.Start()
 
 	// For now, keep the original write throttling behavior based on high watermark
@@ -340,6 +412,11 @@ func (s *dataSVC) GracefulStop() {
 	obsservice.MetricsCollector.Unregister("measure_cache")
 	s.schemaRepo.Close()
 	s.c.Close()
+	if s.option.remoteStorage != nil {
+		if err := s.option.remoteStorage.Close(); err != nil {
+			s.l.Warn().Err(err).Msg("failed to close the remote storage")
+		}
+	}
 }
 
 func (s *dataSVC) collectCacheMetrics() {
@@ -409,13 +486,19 @@ func (s *dataSVC) takeGroupSnapshot(dstDir string, groupName string) (bool, erro
 }
 
 // NewDataSVC returns a new data service.
-func NewDataSVC(metadata metadata.Repo, pipeline queue.Server, metricPipeline queue.Server, omr observability.MetricsRegistry, pm protector.Memory) (Service, error) {
+// The peer client reaches the other data nodes to repair the corrupted parts from their replicas, which is disabled if it is nil.
+func NewDataSVC(metadata metadata.Repo, pipeline queue.Server, metricPipeline queue.Server, omr observability.MetricsRegistry, pm protector.Memory,
+	peerClient queue.Client,
+) (Service, error) {
 	return &dataSVC{
 		metadata:       metadata,
 		pipeline:       pipeline,
 		metricPipeline: metricPipeline,
 		omr:            omr,
 		pm:             pm,
+		option: option{
+			peerClient: peerClient,
+		},
 	}, nil
 }
 
@@ -575,6 +658,11 @@ func (s *dataSVC) CollectDataInfo(ctx context.Context, group string) (*databasev
 	return s.schemaRepo.CollectDataInfo(ctx, group)
 }
 
+// ReportReadFailure starts a scrub of the group after a read failed.
+func (s *dataSVC) ReportReadFailure(group string) {
+	s.schemaRepo.ReportReadFailure(group)
+}
+
 func (s *dataSVC) CollectLiaisonInfo(_ context.Context, _ string) (*databasev1.LiaisonInfo, error) {
 	return nil, errors.New("collect liaison info is not supported on data node")
 }
@@ -589,6 +677,11 @@ func (l *collectDataInfoListener) Rev(ctx context.Context, message bus.Message)
 	if !ok {
 		return bus.NewMessage(message.ID(), common.NewError("invalid data type for collect data info request"))
 	}
+	if req.Scrub {
+		if scrubErr := l.s.schemaRepo.ScrubGroup(ctx, req.Group); scrubErr != nil {
+			return bus.NewMessage(message.ID(), common.NewError("failed to scrub the group: %v", scrubErr))
+		}
+	}
 	dataInfo, collectErr := l.s.schemaRepo.CollectDataInfo(ctx, req.Group)
 	if collectErr != nil {
 		return bus.NewMessage(message.ID(), common.NewError("failed to collect data info: %v", collectErr))
@@ -23,6 +23,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/apache/skywalking-banyandb/api/data"
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/liaison/grpc"
 	"github.com/apache/skywalking-banyandb/banyand/metadata"
@@ -56,11 +58,18 @@ type liaison struct {
 	dataNodeSelector          node.Selector
 	l                         *logger.Logger
 	schemaRepo                *schemaRepo
+	handoffCtrl               *handoff.Controller
 	dataPath                  string
 	root                      string
+	dataNodeList              []string
 	option                    option
+	handoffExpiry             time.Duration
+	writeAckTimeout           time.Duration
+	handoffReplayRate         run.Bytes
 	maxDiskUsagePercent       int
 	failedPartsMaxSizePercent int
+	handoffMaxSizePercent     int
+	handoffMaxNodeSizePercent int
 }
 
 func (s *liaison) Measure(metadata *commonv1.Metadata) (Measure, error) {
@@ -101,6 +110,11 @@ func (s *liaison) CollectLiaisonInfo(_ context.Context, group string) (*database
 	}
 	info.PendingSyncPartCount = pendingSyncPartCount
 	info.PendingSyncDataSizeBytes = pendingSyncDataSizeBytes
+	if s.handoffCtrl != nil {
+		partCount, totalSize := s.handoffCtrl.Stats()
+		info.PendingHandoffPartCount = partCount
+		info.PendingHandoffDataSizeBytes = totalSize
+	}
 	return info, nil
 }
 
@@ -110,11 +124,24 @@ func (s *liaison) FlagSet() *run.FlagSet {
 	flagS.StringVar(&s.dataPath, "measure-data-path", "", "the data directory path of measure. If not set, <measure-root-path>/measure/data will be used")
 	flagS.DurationVar(&s.option.flushTimeout, "measure-flush-timeout", defaultFlushTimeout, "the memory data timeout of measure")
 	flagS.DurationVar(&s.option.syncInterval, "measure-sync-interval", defaultSyncInterval, "the periodic sync interval for measure data")
+	flagS.DurationVar(&s.writeAckTimeout, "measure-write-ack-timeout", defaultWriteAckTimeout,
+		"how long a write waits for the replicas required by the write consistency of its group. Set to 0 to acknowledge writes without waiting")
 	flagS.IntVar(&s.maxDiskUsagePercent, "measure-max-disk-usage-percent", 95, "the maximum disk usage percentage allowed")
 	flagS.IntVar(&s.failedPartsMaxSizePercent, "failed-parts-max-size-percent", 10,
 		"percentage of BanyanDB's allowed disk usage allocated to failed parts storage. "+
 			"Calculated as: totalDisk * measure-max-disk-usage-percent * failed-parts-max-size-percent / 10000. "+
 			"Set to 0 to disable copying failed parts. Valid range: 0-100")
+	flagS.StringSliceVar(&s.dataNodeList, "measure-data-node-list", nil, "comma-separated list of data node names to monitor for measure handoff")
+	flagS.IntVar(&s.handoffMaxSizePercent, "measure-handoff-max-size-percent", 10,
+		"percentage of BanyanDB's allowed disk usage allocated to measure handoff storage. "+
+			"Calculated as: totalDisk * measure-max-disk-usage-percent * measure-handoff-max-size-percent / 10000. "+
+			"Set to 0 to disable the measure handoff. Valid range: 0-100")
+	flagS.IntVar(&s.handoffMaxNodeSizePercent, "measure-handoff-max-node-size-percent", 0,
+		"percentage of the measure handoff storage a single offline node may take. Set to 0 to only limit the total size. Valid range: 0-100")
+	flagS.DurationVar(&s.handoffExpiry, "measure-handoff-expiry", 0,
+		"how long a measure part stays in the handoff queue before it's dropped. The parts never expire if it's 0")
+	flagS.VarP(&s.handoffReplayRate, "measure-handoff-replay-rate", "",
+		"the maximum bytes per second of measure parts replayed to the recovered nodes. The replay is not throttled if it's 0")
 	return flagS
 }
 
@@ -131,6 +158,21 @@ func (s *liaison) Validate() error {
 	if s.failedPartsMaxSizePercent < 0 || s.failedPartsMaxSizePercent > 100 {
 		return errors.New("failed-parts-max-size-percent must be between 0 and 100")
 	}
+	if s.handoffMaxSizePercent < 0 || s.handoffMaxSizePercent > 100 {
+		return errors.New("measure-handoff-max-size-percent must be between 0 and 100")
+	}
+	if s.handoffMaxNodeSizePercent < 0 || s.handoffMaxNodeSizePercent > 100 {
+		return errors.New("measure-handoff-max-node-size-percent must be between 0 and 100")
+	}
+	if s.handoffExpiry < 0 {
+		return errors.New("measure-handoff-expiry must be greater than or equal to 0")
+	}
+	if s.writeAckTimeout < 0 {
+		return errors.New("measure-write-ack-timeout must be greater than or equal to 0")
+	}
+	if s.handoffReplayRate < 0 {
+		return errors.New("measure-handoff-replay-rate must be greater than or equal to 0")
+	}
 	return nil
 }
 
@@ -183,10 +225,34 @@ func (s *liaison) PreRun(ctx context.Context) error {
 	} else {
 		s.l.Info().Msg("failed parts storage limit disabled (percent set to 0)")
 	}
+	if len(s.dataNodeList) > 0 && s.option.tire2Client != nil && s.handoffMaxSizePercent > 0 {
+		totalSpace := s.lfs.MustGetTotalSpace(s.dataPath)
+		// Divide after each multiplication to avoid overflow with large disk capacities
+		maxSizeBytes := totalSpace * uint64(s.maxDiskUsagePercent) / 100 * uint64(s.handoffMaxSizePercent) / 100
+		cfg := handoff.Config{
+			MaxTotalSizeBytes: maxSizeBytes,
+			MaxNodeSizeBytes:  maxSizeBytes * uint64(s.handoffMaxNodeSizePercent) / 100,
+			Expiry:            s.handoffExpiry,
+			ReplayRate:        s.handoffReplayRate,
+		}
+		var factory observability.Factory
+		if s.omr != nil {
+			factory = s.omr.With(measureScope.SubScope("handoff"))
+		}
+		if s.handoffCtrl, err = handoff.NewController(s.lfs, s.dataPath, s.option.tire2Client, handoffCodec{}, s.dataNodeList, cfg, s.l, factory,
+			handoff.NewShardAssignmentResolver(s.metadata, s.dataNodeList)); err != nil {
+			return err
+		}
+		s.l.Info().
+			Strs("dataNodes", s.dataNodeList).
+			Uint64("maxSize", maxSizeBytes).
+			Int("maxSizePercent", s.handoffMaxSizePercent).
+			Msg("handoff controller initialized")
+	}
 	topNResultPipeline := queue.Local()
 	measureDataNodeRegistry := grpc.NewClusterNodeRegistry(data.TopicMeasurePartSync, s.option.tire2Client, s.dataNodeSelector)
 	s.schemaRepo = newLiaisonSchemaRepo(s.dataPath, s, measureDataNodeRegistry, topNResultPipeline)
-	writeListener := setUpWriteQueueCallback(s.l, s.schemaRepo, s.maxDiskUsagePercent, s.option.tire2Client)
+	writeListener := setUpWriteQueueCallback(s.l, s.schemaRepo, s.maxDiskUsagePercent, s.option.tire2Client, s.writeAckTimeout)
 	if err := s.pipeline.Subscribe(data.TopicMeasureWrite, writeListener); err != nil {
 		return err
 	}
@@ -203,7 +269,8 @@ func (s *liaison) PreRun(ctx context.Context) error {
 		return fmt.Errorf("failed to subscribe to drop group topic: %w", subscribeErr)
 	}
 
-	return topNResultPipeline.Subscribe(data.TopicMeasureWrite, writeListener)
+	// The TopN results are written by the liaison itself, so they don't wait for the replicas.
+	return topNResultPipeline.Subscribe(data.TopicMeasureWrite, setUpWriteQueueCallback(s.l, s.schemaRepo, s.maxDiskUsagePercent, s.option.tire2Client, 0))
 }
 
 func (s *liaison) Serve() run.StopNotify {
@@ -211,6 +278,11 @@ func (s *liaison) Serve() run.StopNotify {
 }
 
 func (s *liaison) GracefulStop() {
+	if s.handoffCtrl != nil {
+		if err := s.handoffCtrl.Close(); err != nil {
+			s.l.Warn().Err(err).Msg("failed to close handoff controller")
+		}
+	}
 	s.schemaRepo.Close()
 }
 
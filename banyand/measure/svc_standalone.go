@@ -80,6 +80,8 @@ type standalone struct {
 	snapshotDir        string
 	dataPath           string
 	option             option
+	remoteConfig       storage.RemoteStorageConfig
+	scrubConfig        storage.ScrubConfig
 	retentionConfig    storage.RetentionConfig
 	cc                 storage.CacheConfig
 	maxFileSnapshotNum int
@@ -108,8 +110,8 @@ func (s *standalone) GetRemovalSegmentsTimeRange(group string) *timestamp.TimeRa
 
 // RetentionService interface implementation.
 
-func (s *standalone) GetDataPath() string {
-	return s.dataPath
+func (s *standalone) GetDataPaths() []string {
+	return append([]string{s.dataPath}, s.option.extraDataPaths...)
 }
 
 func (s *standalone) GetSnapshotDir() string {
@@ -120,7 +122,7 @@ func (s *standalone) LoadAllGroups() []resourceSchema.Group {
 	return s.schemaRepo.LoadAllGroups()
 }
 
-func (s *standalone) PeekOldestSegmentEndTimeInGroup(group string) (time.Time, bool) {
+func (s *standalone) PeekOldestSegmentEndTimeInGroup(group, volume string) (time.Time, bool) {
 	g, ok := s.schemaRepo.LoadGroup(group)
 	if !ok {
 		return time.Time{}, false
@@ -132,14 +134,16 @@ func (s *standalone) PeekOldestSegmentEndTimeInGroup(group string) (time.Time, b
 	}
 
 	// Type assert to the storage interface that has PeekOldestSegmentEndTime
-	if dbWithPeek, ok := db.(interface{ PeekOldestSegmentEndTime() (time.Time, bool) }); ok {
-		return dbWithPeek.PeekOldestSegmentEndTime()
+	if dbWithPeek, ok := db.(interface {
+		PeekOldestSegmentEndTime(volume string) (time.Time, bool)
+	}); ok {
+		return dbWithPeek.PeekOldestSegmentEndTime(volume)
 	}
 
 	return time.Time{}, false
 }
 
-func (s *standalone) DeleteOldestSegmentInGroup(group string) (bool, error) {
+func (s *standalone) DeleteOldestSegmentInGroup(group, volume string) (bool, error) {
 	g, ok := s.schemaRepo.LoadGroup(group)
 	if !ok {
 		return false, nil
@@ -151,8 +155,10 @@ func (s *standalone) DeleteOldestSegmentInGroup(group string) (bool, error) {
 	}
 
 	// Type assert to the storage interface that has DeleteOldestSegment
-	if dbWithDelete, ok := db.(interface{ DeleteOldestSegment() (bool, error) }); ok {
-		return dbWithDelete.DeleteOldestSegment()
+	if dbWithDelete, ok := db.(interface {
+		DeleteOldestSegment(volume string) (bool, error)
+	}); ok {
+		return dbWithDelete.DeleteOldestSegment(volume)
 	}
 
 	s.l.Debug().Str("group", group).Msg("database does not support DeleteOldestSegment")
@@ -176,6 +182,8 @@ func (s *standalone) FlagSet() *run.FlagSet {
 	flagS := run.NewFlagSet("storage")
 	flagS.StringVar(&s.root, "measure-root-path", "/tmp", "the root path of measure")
 	flagS.StringVar(&s.dataPath, "measure-data-path", "", "the data directory path of measure. If not set, <measure-root-path>/measure/data will be used")
+	flagS.StringSliceVar(&s.option.extraDataPaths, "measure-extra-data-paths", nil,
+		"the additional data directory paths of measure, usually one per disk. New segments and shards are spread across them and the data path by free space")
 	flagS.DurationVar(&s.option.flushTimeout, "measure-flush-timeout", defaultFlushTimeout, "the memory data timeout of measure")
 	s.option.mergePolicy = newDefaultMergePolicy()
 	flagS.VarP(&s.option.mergePolicy.maxFanOutSize, "measure-max-fan-out-size", "", "the upper bound of a single file size after merge of measure")
@@ -192,6 +200,21 @@ func (s *standalone) FlagSet() *run.FlagSet {
 
 	flagS.IntVar(&s.maxFileSnapshotNum, "measure-max-file-snapshot-num", 10, "the maximum number of file snapshots allowed")
 	flagS.DurationVar(&s.minFileSnapshotAge, "measure-min-file-snapshot-age", time.Hour, "minimum age for file snapshots to be eligible for deletion")
+
+	// Remote storage flags of remote-backed lifecycle stages
+	flagS.StringVar(&s.remoteConfig.Dest, "measure-remote-storage-dest", "", "the object storage URL of remote-backed stages (e.g., s3:///bucket/path)")
+	flagS.StringVar(&s.remoteConfig.ConfigFile, "measure-remote-storage-config", "", "the JSON or YAML file holding the credentials of the object storage")
+	flagS.StringVar(&s.remoteConfig.CachePath, "measure-remote-storage-cache-path", "",
+		"the read-through cache directory of offloaded data. If not set, <measure-root-path>/measure/remote-cache will be used")
+	s.remoteConfig.CacheSize = run.Bytes(10 << 30)
+	flagS.VarP(&s.remoteConfig.CacheSize, "measure-remote-storage-cache-size", "", "the capacity of the read-through cache of offloaded data")
+	flagS.DurationVar(&s.remoteConfig.OffloadDelay, "measure-remote-storage-offload-delay", time.Hour,
+		"how long a segment stays on the local disk after its end time before being offloaded")
+
+	// Scrub flags of the background verification of the part checksums
+	flagS.DurationVar(&s.scrubConfig.Interval, "measure-scrub-interval", 24*time.Hour, "the interval between two scrubs of a group. Periodic scrubs are disabled if it's 0")
+	s.scrubConfig.Rate = run.Bytes(16 << 20)
+	flagS.VarP(&s.scrubConfig.Rate, "measure-scrub-rate", "", "the maximum bytes per second read by the scrubs of all the groups. Scrubs are not throttled if it's 0")
 	s.cc.MaxCacheSize = run.Bytes(100 * 1024 * 1024)
 	flagS.VarP(&s.cc.MaxCacheSize, "service-cache-max-size", "", "maximum service cache size (e.g., 100M)")
 	flagS.DurationVar(&s.cc.CleanupInterval, "service-cache-cleanup-interval", 30*time.Second, "service cache cleanup interval")
@@ -221,6 +244,19 @@ func (s *standalone) Validate() error {
 		return errors.New("measure-retention-cooldown must be greater than 0")
 	}
 
+	if s.remoteConfig.CacheSize < 0 {
+		return errors.New("measure-remote-storage-cache-size must be greater than or equal to 0")
+	}
+	if s.remoteConfig.OffloadDelay < 0 {
+		return errors.New("measure-remote-storage-offload-delay must be greater than or equal to 0")
+	}
+	if s.scrubConfig.Interval < 0 {
+		return errors.New("measure-scrub-interval must be greater than or equal to 0")
+	}
+	if s.scrubConfig.Rate < 0 {
+		return errors.New("measure-scrub-rate must be greater than or equal to 0")
+	}
+
 	if s.cc.MaxCacheSize < 0 {
 		return errors.New("service-cache-max-size must be greater than or equal to 0")
 	}
@@ -263,6 +299,15 @@ func (s *standalone) PreRun(ctx context.Context) error {
 	if !strings.HasPrefix(filepath.VolumeName(s.dataPath), filepath.VolumeName(path)) {
 		obsservice.UpdatePath(s.dataPath)
 	}
+	for i := range s.option.extraDataPaths {
+		if s.option.extraDataPaths[i], err = banyandbpath.Get(s.option.extraDataPaths[i]); err != nil {
+			return err
+		}
+		obsservice.UpdatePath(s.option.extraDataPaths[i])
+	}
+	if err = storage.CheckDataPaths(s.GetDataPaths()...); err != nil {
+		return err
+	}
 	s.localPipeline = queue.Local()
 	val := ctx.Value(common.ContextNodeKey)
 	if val == nil {
@@ -273,6 +318,13 @@ func (s *standalone) PreRun(ctx context.Context) error {
 	} else {
 		s.c = storage.NewServiceCacheWithConfig(s.cc)
 	}
+	if s.remoteConfig.CachePath == "" {
+		s.remoteConfig.CachePath = filepath.Join(path, storage.RemoteCacheDir)
+	}
+	if s.option.remoteStorage, err = storage.OpenRemoteStorage(s.remoteConfig, s.dataPath); err != nil {
+		return err
+	}
+	s.option.scrubber = storage.NewScrubber(s.scrubConfig)
 	node := val.(common.Node)
 	s.schemaRepo = newSchemaRepo(s.dataPath, s, node.Labels, node.NodeID)
 	if metaSvc, ok := s.metadata.(metadata.Service); ok {
@@ -298,6 +350,9 @@ func (s *standalone) PreRun(ctx context.Context) error {
 
 	// Initialize disk monitor for forced retention
 	s.diskMonitor = storage.NewDiskMonitor(s, s.retentionConfig, s.omr)
+	if s.option.remoteStorage != nil {
+		s.diskMonitor.SetRemoteCache(s.option.remoteStorage.Cache())
+	}
 	s.diskMonitor.Start()
 
 	// For now, keep the original write throttling behavior based on high watermark
@@ -335,6 +390,11 @@ func (s *standalone) GracefulStop() {
 	}
 	s.schemaRepo.Close()
 	s.c.Close()
+	if s.option.remoteStorage != nil {
+		if err := s.option.remoteStorage.Close(); err != nil {
+			s.l.Warn().Err(err).Msg("failed to close the remote storage")
+		}
+	}
 }
 
 func (s *standalone) collectCacheMetrics() {
@@ -373,6 +433,11 @@ func (s *standalone) CollectDataInfo(ctx context.Context, group string) (*databa
 	return s.schemaRepo.CollectDataInfo(ctx, group)
 }
 
+// ReportReadFailure starts a scrub of the group after a read failed.
+func (s *standalone) ReportReadFailure(group string) {
+	s.schemaRepo.ReportReadFailure(group)
+}
+
 func (s *standalone) CollectLiaisonInfo(_ context.Context, group string) (*databasev1.LiaisonInfo, error) {
 	info := &databasev1.LiaisonInfo{}
 	pendingWriteCount, writeErr := s.schemaRepo.collectPendingWriteInfo(group)
@@ -248,6 +248,9 @@ func (tst *tsTable) syncSnapshot(curSnapshot *snapshot, syncCh chan *syncIntrodu
 
 	partsToSync := tst.collectPartsToSync(curSnapshot)
 	if len(partsToSync) == 0 {
+		if tst.acks != nil {
+			tst.acks.Skipped(curSnapshot.epoch, collectMemPartIDs(curSnapshot))
+		}
 		return nil
 	}
 
@@ -258,9 +261,13 @@ func (tst *tsTable) syncSnapshot(curSnapshot *snapshot, syncCh chan *syncIntrodu
 
 	tst.sortPartsByID(partsToSync)
 
-	if err := tst.executeSyncWithRetry(partsToSync, nodes); err != nil {
+	acked, err := tst.executeSyncWithRetry(partsToSync, nodes)
+	if err != nil {
 		return err
 	}
+	if tst.acks != nil {
+		tst.acks.Synced(curSnapshot.epoch, collectMemPartIDs(curSnapshot), acked)
+	}
 
 	return tst.sendSyncIntroduction(partsToSync, syncCh)
 }
@@ -275,6 +282,17 @@ func (tst *tsTable) collectPartsToSync(curSnapshot *snapshot) []*part {
 	return partsToSync
 }
 
+// collectMemPartIDs returns the IDs of the mem parts, which aren't flushed and synced yet.
+func collectMemPartIDs(curSnapshot *snapshot) map[uint64]struct{} {
+	ids := make(map[uint64]struct{})
+	for _, pw := range curSnapshot.parts {
+		if pw.mp != nil {
+			ids[pw.ID()] = struct{}{}
+		}
+	}
+	return ids
+}
+
 func (tst *tsTable) sortPartsByID(partsToSync []*part) {
 	for i := 0; i < len(partsToSync); i++ {
 		for j := i + 1; j < len(partsToSync); j++ {
@@ -285,7 +303,8 @@ func (tst *tsTable) sortPartsByID(partsToSync []*part) {
 	}
 }
 
-func (tst *tsTable) executeSyncWithRetry(partsToSync []*part, nodes []string) error {
+// executeSyncWithRetry syncs the parts to the nodes and returns the number of the nodes which received all of them.
+func (tst *tsTable) executeSyncWithRetry(partsToSync []*part, nodes []string) (int, error) {
 	failedPartsHandler := storage.NewFailedPartsHandler(tst.fileSystem, tst.root, tst.l, tst.option.failedPartsMaxTotalSizeBytes)
 	partsInfo := tst.buildPartsInfoMap(partsToSync)
 
@@ -298,11 +317,14 @@ func (tst *tsTable) executeSyncWithRetry(partsToSync []*part, nodes []string) er
 	}()
 
 	perNodeFailures := tst.performInitialSync(ctx, partsToSync, nodes, &releaseFuncs)
-	if len(perNodeFailures) > 0 {
-		tst.handleFailedPartsRetry(ctx, partsToSync, perNodeFailures, partsInfo, failedPartsHandler)
+	// After sync attempts, enqueue parts for offline nodes
+	tst.enqueueForOfflineNodes(nodes, partsToSync)
+	acked := len(nodes) - len(perNodeFailures)
+	if len(perNodeFailures) > 0 && tst.handleFailedPartsRetry(ctx, partsToSync, perNodeFailures, partsInfo, failedPartsHandler) {
+		acked = len(nodes)
 	}
 
-	return nil
+	return acked, nil
 }
 
 func (tst *tsTable) buildPartsInfoMap(partsToSync []*part) map[uint64][]*storage.PartInfo {
@@ -345,10 +367,11 @@ func (tst *tsTable) performInitialSync(
 	return perNodeFailures
 }
 
+// handleFailedPartsRetry retries the failed parts and reports whether all of them succeeded.
 func (tst *tsTable) handleFailedPartsRetry(
 	ctx context.Context, partsToSync []*part, perNodeFailures map[string][]queue.FailedPart,
 	partsInfo map[uint64][]*storage.PartInfo, failedPartsHandler *storage.FailedPartsHandler,
-) {
+) bool {
 	allFailedParts := tst.collectAllFailedParts(perNodeFailures)
 	syncFunc := tst.createRetrySyncFunc(ctx, partsToSync, perNodeFailures)
 
@@ -362,6 +385,7 @@ func (tst *tsTable) handleFailedPartsRetry(
 			Int("count", len(permanentlyFailedParts)).
 			Msg("parts permanently failed after all retries and have been copied to failed-parts directory")
 	}
+	return err == nil && len(permanentlyFailedParts) == 0
 }
 
 func (tst *tsTable) collectAllFailedParts(perNodeFailures map[string][]queue.FailedPart) []queue.FailedPart {
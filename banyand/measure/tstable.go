@@ -31,9 +31,12 @@ import (
 	"time"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 	"github.com/apache/skywalking-banyandb/pkg/pool"
 	"github.com/apache/skywalking-banyandb/pkg/run"
@@ -127,6 +130,7 @@ func newTSTable(fileSystem fs.FileSystem, rootPath string, p common.Position,
 	l *logger.Logger, _ timestamp.TimeRange, option option, m any,
 ) (*tsTable, error) {
 	t, epoch := initTSTable(fileSystem, rootPath, p, l, option, m)
+	t.buildState = storage.LoadIndexBuildState(fileSystem, rootPath, l)
 	t.startLoop(epoch)
 	return t, nil
 }
@@ -148,9 +152,14 @@ func (tst *tsTable) startLoopWithConditionalMerge(cur uint64) {
 type tsTable struct {
 	fileSystem    fs.FileSystem
 	pm            protector.Memory
+	handoffCtrl   *handoff.Controller
+	acks          *wqueue.AckTracker
 	loopCloser    *run.Closer
 	introductions chan *introduction
+	removals      chan *mergerIntroduction
 	snapshot      *snapshot
+	partDigests   map[uint64]*storage.AntiEntropyDigest
+	buildState    *storage.IndexBuildState
 	*metrics
 	getNodes         func() []string
 	l                *logger.Logger
@@ -161,10 +170,35 @@ type tsTable struct {
 	option           option
 	curPartID        uint64
 	pendingDataCount atomic.Int64
+	partDigestsMu    sync.Mutex
 	sync.RWMutex
 	shardID common.ShardID
 }
 
+// enqueueForOfflineNodes enqueues parts for offline nodes via the handoff controller.
+func (tst *tsTable) enqueueForOfflineNodes(onlineNodes []string, partsToSync []*part) {
+	if tst.handoffCtrl == nil {
+		return
+	}
+	offlineNodes := tst.handoffCtrl.CalculateOfflineNodes(onlineNodes, tst.group, tst.shardID)
+	if len(offlineNodes) == 0 {
+		return
+	}
+	parts := make([]handoff.PartInfo, 0, len(partsToSync))
+	for _, part := range partsToSync {
+		parts = append(parts, handoff.PartInfo{
+			PartID:   part.partMetadata.ID,
+			Path:     part.path,
+			Group:    tst.group,
+			PartType: PartTypeCore,
+			ShardID:  tst.shardID,
+		})
+	}
+	if err := tst.handoffCtrl.EnqueueForOfflineNodes(offlineNodes, parts); err != nil {
+		tst.l.Warn().Err(err).Msg("handoff enqueue completed with errors")
+	}
+}
+
 func (tst *tsTable) loadSnapshot(epoch uint64, loadedParts []uint64) {
 	parts := tst.mustReadSnapshot(epoch)
 	snp := snapshot{
@@ -212,6 +246,7 @@ func (tst *tsTable) loadSnapshot(epoch uint64, loadedParts []uint64) {
 func (tst *tsTable) startLoop(cur uint64) {
 	tst.loopCloser = run.NewCloser(1 + 3)
 	tst.introductions = make(chan *introduction)
+	tst.removals = make(chan *mergerIntroduction)
 	flushCh := make(chan *flusherIntroduction)
 	mergeCh := make(chan *mergerIntroduction)
 	introducerWatcher := make(watcher.Channel, 1)
@@ -274,6 +309,9 @@ func (tst *tsTable) Close() error {
 		tst.loopCloser.Done()
 		tst.loopCloser.CloseThenWait()
 	}
+	if tst.acks != nil {
+		tst.acks.Close()
+	}
 	tst.Lock()
 	defer tst.Unlock()
 	tst.deleteMetrics()
@@ -286,12 +324,14 @@ func (tst *tsTable) Close() error {
 }
 
 func (tst *tsTable) mustAddDataPoints(dps *dataPoints) {
-	tst.mustAddDataPointsWithSegmentID(dps, 0, nil)
+	tst.mustAddDataPointsWithSegmentID(dps, 0, nil, false)
 }
 
-func (tst *tsTable) mustAddDataPointsWithSegmentID(dps *dataPoints, segmentID int64, seriesMetadata []byte) {
+// mustAddDataPointsWithSegmentID adds the data points as a mem part.
+// If track is set, it returns the waiter of the write being synced to the data nodes, which is nil if the table doesn't sync.
+func (tst *tsTable) mustAddDataPointsWithSegmentID(dps *dataPoints, segmentID int64, seriesMetadata []byte, track bool) *wqueue.AckWaiter {
 	if len(dps.seriesIDs) == 0 {
-		return
+		return nil
 	}
 
 	mp := generateMemPart()
@@ -304,10 +344,16 @@ func (tst *tsTable) mustAddDataPointsWithSegmentID(dps *dataPoints, segmentID in
 			logger.Panicf("cannot write series metadata to buffer: %s", err)
 		}
 	}
-	tst.mustAddMemPart(mp)
+	return tst.mustAddTrackedMemPart(mp, track)
 }
 
 func (tst *tsTable) mustAddMemPart(mp *memPart) {
+	tst.mustAddTrackedMemPart(mp, false)
+}
+
+// mustAddTrackedMemPart adds the mem part. If track is set, it returns the waiter of the write being synced to the data nodes,
+// which is nil if the table doesn't sync. The introducer tracks the write, so no sync covers the mem part before the write is tracked.
+func (tst *tsTable) mustAddTrackedMemPart(mp *memPart, track bool) *wqueue.AckWaiter {
 	p := openMemPart(mp)
 
 	ind := generateIntroduction()
@@ -315,6 +361,7 @@ func (tst *tsTable) mustAddMemPart(mp *memPart) {
 	ind.applied = make(chan struct{})
 	ind.memPart = newPartWrapper(mp, p)
 	ind.memPart.p.partMetadata.ID = atomic.AddUint64(&tst.curPartID, 1)
+	ind.track = track
 	startTime := time.Now()
 	totalCount := mp.partMetadata.TotalCount
 	tst.addPendingDataCount(int64(totalCount))
@@ -322,15 +369,18 @@ func (tst *tsTable) mustAddMemPart(mp *memPart) {
 	case tst.introductions <- ind:
 	case <-tst.loopCloser.CloseNotify():
 		tst.addPendingDataCount(-int64(totalCount))
-		return
+		return nil
 	}
+	var waiter *wqueue.AckWaiter
 	select {
 	case <-ind.applied:
+		waiter = ind.waiter
 	case <-tst.loopCloser.CloseNotify():
 	}
 	tst.incTotalWritten(int(totalCount))
 	tst.incTotalBatch(1)
 	tst.incTotalBatchIntroLatency(time.Since(startTime).Seconds())
+	return waiter
 }
 
 type tstIter struct {
@@ -361,7 +411,7 @@ func (ti *tstIter) reset() {
 	ti.nextBlockNoop = false
 }
 
-func (ti *tstIter) init(parts []*part, sids []common.SeriesID, minTimestamp, maxTimestamp int64) {
+func (ti *tstIter) init(parts []*part, sids []common.SeriesID, minTimestamp, maxTimestamp int64, blockFilter index.Filter) {
 	ti.reset()
 	ti.parts = parts
 
@@ -370,7 +420,7 @@ func (ti *tstIter) init(parts []*part, sids []common.SeriesID, minTimestamp, max
 	}
 	ti.piPool = ti.piPool[:len(ti.parts)]
 	for i, p := range ti.parts {
-		ti.piPool[i].init(p, sids, minTimestamp, maxTimestamp)
+		ti.piPool[i].init(p, sids, minTimestamp, maxTimestamp, blockFilter)
 	}
 
 	ti.piHeap = ti.piHeap[:0]
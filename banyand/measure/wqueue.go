@@ -21,6 +21,8 @@ import (
 	"fmt"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
+	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 )
@@ -34,11 +36,14 @@ type NodeSelector interface {
 // newWriteQueue is like newTSTable but does not start the merge loop (or any background loops).
 func newWriteQueue(fileSystem fs.FileSystem, rootPath string, p common.Position,
 	l *logger.Logger, option option, m any, group string, shardID common.ShardID, getNodes func() []string,
+	handoffCtrl *handoff.Controller,
 ) (*tsTable, error) {
 	t, epoch := initTSTable(fileSystem, rootPath, p, l, option, m)
 	t.getNodes = getNodes
+	t.handoffCtrl = handoffCtrl
 	t.group = group
 	t.shardID = shardID
+	t.acks = &wqueue.AckTracker{}
 	t.startLoopWithConditionalMerge(epoch)
 	return t, nil
 }
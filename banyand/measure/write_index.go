@@ -23,6 +23,8 @@ import (
 
 	"github.com/apache/skywalking-banyandb/api/common"
 	"github.com/apache/skywalking-banyandb/api/data"
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/bus"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/index"
@@ -98,6 +100,25 @@ func (i *indexCallback) Rev(_ context.Context, message bus.Message) (resp bus.Me
 	}
 	defer segment.DecRef()
 
+	documents, rejected, checkErr := storage.AdmitSeriesDocuments(segment, documents, func(subject string) (storage.SeriesLimits, int) {
+		m, ok := i.schemaRepo.loadMeasure(&commonv1.Metadata{Group: group, Name: subject})
+		if !ok {
+			return storage.SeriesLimits{}, 0
+		}
+		return i.schemaRepo.seriesLimits(m.GetSchema())
+	})
+	if checkErr != nil {
+		i.l.Warn().Err(checkErr).Str("group", group).Msg("failed to check some series against the series limits")
+	}
+	if len(rejected) > 0 {
+		// The liaison drops the data of the rejected series before queuing them
+		i.l.Warn().Str("group", group).Int("rejected", len(rejected)).Msg("reject the series exceeding the series limits")
+		resp = bus.NewMessage(message.ID(), storage.NewSeriesIndexWriteResponse(rejected))
+	}
+	if len(documents) == 0 {
+		return
+	}
+
 	switch i.topic {
 	case data.TopicMeasureSeriesIndexInsert:
 		err = segment.IndexDB().Insert(documents)
@@ -29,6 +29,7 @@ import (
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	measurev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/measure/v1"
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	obsservice "github.com/apache/skywalking-banyandb/banyand/observability/services"
 	"github.com/apache/skywalking-banyandb/banyand/queue"
@@ -39,7 +40,9 @@ import (
 	"github.com/apache/skywalking-banyandb/pkg/timestamp"
 )
 
-func setUpWriteQueueCallback(l *logger.Logger, schemaRepo *schemaRepo, maxDiskUsagePercent int, tire2Client queue.Client) bus.MessageListener {
+func setUpWriteQueueCallback(l *logger.Logger, schemaRepo *schemaRepo, maxDiskUsagePercent int, tire2Client queue.Client,
+	ackTimeout time.Duration,
+) bus.MessageListener {
 	if maxDiskUsagePercent > 100 {
 		maxDiskUsagePercent = 100
 	}
@@ -48,6 +51,7 @@ func setUpWriteQueueCallback(l *logger.Logger, schemaRepo *schemaRepo, maxDiskUs
 		schemaRepo:          schemaRepo,
 		maxDiskUsagePercent: maxDiskUsagePercent,
 		tire2Client:         tire2Client,
+		ackTimeout:          ackTimeout,
 	}
 }
 
@@ -56,6 +60,7 @@ type writeQueueCallback struct {
 	l                   *logger.Logger
 	schemaRepo          *schemaRepo
 	maxDiskUsagePercent int
+	ackTimeout          time.Duration
 }
 
 func (w *writeQueueCallback) CheckHealth() *common.Error {
@@ -83,6 +88,10 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 	groups := make(map[string]*dataPointsInQueue)
 	var metadata *commonv1.Metadata
 	var spec *measurev1.DataPointSpec
+	var rejectedSeries int
+	var rejectedMessages map[uint64]modelv1.Status
+	var messageIDs []uint64
+	var acks []wqueue.PendingAck
 	for i := range events {
 		var writeEvent *measurev1.InternalWriteRequest
 		switch e := events[i].(type) {
@@ -111,11 +120,27 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 			continue
 		}
 		groups = newGroups
+		messageIDs = append(messageIDs, req.GetMessageId())
 	}
 	for groupName := range groups {
 		g := groups[groupName]
+		requiredAcks := 0
+		if w.ackTimeout > 0 {
+			requiredAcks = w.schemaRepo.requiredAcks(groupName)
+		}
 		for j := range g.tables {
 			es := g.tables[j]
+			// The data nodes check the series limits while writing the series index,
+			// so the series index is written before the data points are queued.
+			rejected := w.writeSeriesIndex(ctx, g, es)
+			if len(rejected) > 0 {
+				rejectedSeries += len(rejected)
+				rejectedMessages = storage.CollectRejectedMessages(rejectedMessages, es.messageIDs, rejected)
+				if es.dataPoints != nil {
+					es.dataPoints.dropSeries(rejected)
+				}
+				es.metadataDocs = storage.DropRejectedDocuments(es.metadataDocs, rejected)
+			}
 			// Marshal series metadata for persistence in part folder
 			var seriesMetadataBytes []byte
 			if len(es.metadataDocs) > 0 {
@@ -127,50 +152,86 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 				}
 			}
 			if es.tsTable != nil && es.dataPoints != nil {
-				es.tsTable.mustAddDataPointsWithSegmentID(es.dataPoints, es.timeRange.Start.UnixNano(), seriesMetadataBytes)
-				releaseDataPoints(es.dataPoints)
-			}
-			nodes := g.queue.GetNodes(es.shardID)
-			if len(nodes) == 0 {
-				w.l.Warn().Uint32("shardID", uint32(es.shardID)).Msg("no nodes found for shard")
-				continue
-			}
-			sendDocuments := func(topic bus.Topic, seriesDocData []byte) {
-				// Encode group name, start timestamp from timeRange, and prepend to docData
-				combinedData := make([]byte, 0, len(seriesDocData)+len(g.name)+8)
-				combinedData = encoding.EncodeBytes(combinedData, convert.StringToBytes(g.name))
-				combinedData = encoding.Int64ToBytes(combinedData, es.timeRange.Start.UnixNano())
-				combinedData = append(combinedData, seriesDocData...)
-
-				// Send to all nodes for this shard
-				for _, node := range nodes {
-					message := bus.NewMessageWithNode(bus.MessageID(time.Now().UnixNano()), node, combinedData)
-					future, publishErr := w.tire2Client.Publish(ctx, topic, message)
-					if publishErr != nil {
-						w.l.Error().Err(publishErr).Str("node", node).Uint32("shardID", uint32(es.shardID)).Msg("failed to publish series index to node")
-						continue
-					}
-					_, err := future.Get()
-					if err != nil {
-						w.l.Error().Err(err).Str("node", node).Uint32("shardID", uint32(es.shardID)).Msg("failed to get response from publish")
-						continue
+				if es.dataPoints.Len() > 0 {
+					waiter := es.tsTable.mustAddDataPointsWithSegmentID(es.dataPoints, es.timeRange.Start.UnixNano(), seriesMetadataBytes, requiredAcks > 0)
+					if waiter != nil {
+						acks = append(acks, wqueue.PendingAck{Waiter: waiter, Group: groupName, Required: requiredAcks, MessageIDs: es.messages()})
 					}
 				}
+				releaseDataPoints(es.dataPoints)
 			}
-			if len(seriesMetadataBytes) > 0 {
-				sendDocuments(data.TopicMeasureSeriesIndexInsert, seriesMetadataBytes)
+		}
+	}
+	// The data points of the rejected series are dropped before being queued,
+	// and the others are still waited for to report whether they are persisted by enough replicas.
+	var rejectedErr *common.Error
+	if rejectedSeries > 0 {
+		w.l.Warn().Int("series", rejectedSeries).Msg("data nodes reject the series exceeding the series limits")
+		rejectedErr = common.NewErrorWithMessageStatuses(modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED,
+			fmt.Sprintf("%d series are rejected by the series limits", rejectedSeries), rejectedMessages)
+	}
+	degradedErr := wqueue.WaitAcks(ctx, acks, w.ackTimeout)
+	if degradedErr != nil {
+		w.l.Warn().Err(degradedErr).Msg("the data points are persisted by fewer replicas than the write consistency")
+	}
+	if ce := common.MergeErrors(messageIDs, rejectedErr, degradedErr); ce != nil {
+		return bus.NewMessage(message.ID(), ce)
+	}
+	return
+}
+
+// writeSeriesIndex sends the series documents of the table to the data nodes of its shard,
+// and returns the series rejected by the series limits of any data node.
+func (w *writeQueueCallback) writeSeriesIndex(ctx context.Context, g *dataPointsInQueue, es *dataPointsInTable) map[common.SeriesID]struct{} {
+	if len(es.metadataDocs) == 0 && len(es.indexModeDocs) == 0 {
+		return nil
+	}
+	nodes := g.queue.GetNodes(es.shardID)
+	if len(nodes) == 0 {
+		w.l.Warn().Uint32("shardID", uint32(es.shardID)).Msg("no nodes found for shard")
+		return nil
+	}
+	var rejected map[common.SeriesID]struct{}
+	sendDocuments := func(topic bus.Topic, seriesDocData []byte) {
+		// Encode group name, start timestamp from timeRange, and prepend to docData
+		combinedData := make([]byte, 0, len(seriesDocData)+len(g.name)+8)
+		combinedData = encoding.EncodeBytes(combinedData, convert.StringToBytes(g.name))
+		combinedData = encoding.Int64ToBytes(combinedData, es.timeRange.Start.UnixNano())
+		combinedData = append(combinedData, seriesDocData...)
+
+		// Send to all nodes for this shard
+		for _, node := range nodes {
+			message := bus.NewMessageWithNode(bus.MessageID(time.Now().UnixNano()), node, combinedData)
+			future, publishErr := w.tire2Client.Publish(ctx, topic, message)
+			if publishErr != nil {
+				w.l.Error().Err(publishErr).Str("node", node).Uint32("shardID", uint32(es.shardID)).Msg("failed to publish series index to node")
+				continue
 			}
-			if len(es.indexModeDocs) > 0 {
-				seriesDocData, marshalErr := es.indexModeDocs.Marshal()
-				if marshalErr != nil {
-					w.l.Error().Err(marshalErr).Uint32("shardID", uint32(es.shardID)).Msg("failed to marshal index mode documents")
-				} else {
-					sendDocuments(data.TopicMeasureSeriesIndexUpdate, seriesDocData)
-				}
+			resp, err := future.Get()
+			if err != nil {
+				w.l.Error().Err(err).Str("node", node).Uint32("shardID", uint32(es.shardID)).Msg("failed to get response from publish")
+				continue
 			}
+			rejected = storage.CollectRejectedSeries(rejected, resp)
 		}
 	}
-	return
+	if len(es.metadataDocs) > 0 {
+		seriesDocData, marshalErr := es.metadataDocs.Marshal()
+		if marshalErr != nil {
+			w.l.Error().Err(marshalErr).Uint32("shardID", uint32(es.shardID)).Msg("failed to marshal series documents")
+		} else {
+			sendDocuments(data.TopicMeasureSeriesIndexInsert, seriesDocData)
+		}
+	}
+	if len(es.indexModeDocs) > 0 {
+		seriesDocData, marshalErr := es.indexModeDocs.Marshal()
+		if marshalErr != nil {
+			w.l.Error().Err(marshalErr).Uint32("shardID", uint32(es.shardID)).Msg("failed to marshal index mode documents")
+		} else {
+			sendDocuments(data.TopicMeasureSeriesIndexUpdate, seriesDocData)
+		}
+	}
+	return rejected
 }
 
 func (w *writeQueueCallback) handle(dst map[string]*dataPointsInQueue,
@@ -237,6 +298,7 @@ func (w *writeQueueCallback) handle(dst map[string]*dataPointsInQueue,
 	if err != nil {
 		return nil, err
 	}
+	dpt.addMessage(sid, req.GetMessageId())
 	w.schemaRepo.inFlow(stm.GetSchema(), sid, writeEvent.ShardId, writeEvent.EntityValues, req.DataPoint, spec)
 	return dst, nil
 }
@@ -20,6 +20,7 @@ package measure
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 
 	"google.golang.org/protobuf/proto"
@@ -195,6 +196,9 @@ func (w *writeCallback) handle(dst map[string]*dataPointsInGroup, writeEvent *me
 		dpg.tables = append(dpg.tables, dpt)
 	}
 
+	if err = w.admitSeries(dpt.segment, stm, writeEvent); err != nil {
+		return nil, err
+	}
 	sid, err := processDataPoint(dpt, req, writeEvent, stm, is, ts, metadata, spec)
 	if err != nil {
 		return nil, err
@@ -203,6 +207,22 @@ func (w *writeCallback) handle(dst map[string]*dataPointsInGroup, writeEvent *me
 	return dst, nil
 }
 
+// admitSeries checks the series of the data point against the series limits of the measure.
+func (w *writeCallback) admitSeries(segment storage.Segment[*tsTable, option], stm *measure, writeEvent *measurev1.InternalWriteRequest) error {
+	limits, entitySize := w.schemaRepo.seriesLimits(stm.GetSchema())
+	if !limits.Enabled() {
+		return nil
+	}
+	series := &pbv1.Series{
+		Subject:      stm.GetSchema().GetMetadata().GetName(),
+		EntityValues: writeEvent.EntityValues,
+	}
+	if err := series.Marshal(); err != nil {
+		return fmt.Errorf("cannot marshal series: %w", err)
+	}
+	return segment.AdmitSeries(series, entitySize, limits)
+}
+
 func appendDataPoints(dest *dataPointsInTable, ts int64, sid common.SeriesID, schema *databasev1.Measure,
 	req *measurev1.WriteRequest, locator partition.IndexRuleLocator, spec *measurev1.DataPointSpec,
 ) []index.Field {
@@ -242,11 +262,7 @@ func appendDataPoints(dest *dataPointsInTable, ts int64, sid common.SeriesID, sc
 				v = req.DataPoint.Fields[i]
 			}
 		}
-		field.values = append(field.values, encodeFieldValue(
-			schemaField.GetName(),
-			schemaField.FieldType,
-			v,
-		))
+		field.values = append(field.values, encodeFieldValue(schemaField, v))
 	}
 	dataPoints.fields = append(dataPoints.fields, field)
 
@@ -319,6 +335,7 @@ func handleTagFamily(schema *databasev1.Measure, req *measurev1.WriteRequest,
 					f.Index = true
 					f.NoSort = r.GetNoSort()
 					fields = append(fields, f)
+					fields = appendTreeFields(fields, r, t.Type, fieldKey, encodeTagValue.value)
 				} else {
 					for _, val := range encodeTagValue.valueArr {
 						f := index.NewBytesField(fieldKey, val)
@@ -392,6 +409,9 @@ func handleIndexMode(schema *databasev1.Measure, req *measurev1.WriteRequest,
 				f.Index = toIndex
 				f.NoSort = r.GetNoSort()
 				fields = append(fields, f)
+				if toIndex {
+					fields = appendTreeFields(fields, r, t.Type, fieldKey, encodeTagValue.value)
+				}
 			} else {
 				for _, val := range encodeTagValue.valueArr {
 					f := index.NewBytesField(fieldKey, val)
@@ -407,6 +427,14 @@ func handleIndexMode(schema *databasev1.Measure, req *measurev1.WriteRequest,
 	return fields
 }
 
+// appendTreeFields appends the ancestors and the parent of a path indexed by a tree index rule.
+func appendTreeFields(fields []index.Field, r *databasev1.IndexRule, tagType databasev1.TagType, fieldKey index.FieldKey, value []byte) []index.Field {
+	if r.GetType() != databasev1.IndexRule_TYPE_TREE || tagType != databasev1.TagType_TAG_TYPE_STRING {
+		return fields
+	}
+	return append(fields, index.NewTreeFields(fieldKey, string(value))...)
+}
+
 func appendEntityTagsToIndexFields(fields []index.Field, stm *measure, series *pbv1.Series) []index.Field {
 	f := index.NewStringField(subjectField, series.Subject)
 	f.Index = true
@@ -455,6 +483,8 @@ func (w *writeCallback) Rev(_ context.Context, message bus.Message) (resp bus.Me
 	groups := make(map[string]*dataPointsInGroup)
 	var metadata *commonv1.Metadata
 	var spec *measurev1.DataPointSpec
+	var limitErr error
+	var rejected map[uint64]modelv1.Status
 	for i := range events {
 		var writeEvent *measurev1.InternalWriteRequest
 		switch e := events[i].(type) {
@@ -478,6 +508,14 @@ func (w *writeCallback) Rev(_ context.Context, message bus.Message) (resp bus.Me
 			spec = req.GetDataPointSpec()
 		}
 		newGroups, handleErr := w.handle(groups, writeEvent, metadata, spec)
+		if errors.Is(handleErr, storage.ErrSeriesLimitExceeded) {
+			limitErr = handleErr
+			if rejected == nil {
+				rejected = make(map[uint64]modelv1.Status)
+			}
+			rejected[req.GetMessageId()] = modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED
+			continue
+		}
 		if handleErr != nil {
 			w.l.Error().Err(handleErr).RawJSON("written", logger.Proto(writeEvent)).Msg("cannot handle write event")
 			continue
@@ -510,12 +548,19 @@ func (w *writeCallback) Rev(_ context.Context, message bus.Message) (resp bus.Me
 		}
 		g.tsdb.Tick(g.latestTS)
 	}
+	if len(rejected) > 0 {
+		// Only the writes of the rejected data points are reported, the others in the batch succeed.
+		w.l.Warn().Err(limitErr).Int("rejected", len(rejected)).Msg("reject the data points exceeding the series limits")
+		return bus.NewMessage(message.ID(), common.NewErrorWithMessageStatuses(modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED,
+			fmt.Sprintf("%d data points are rejected: %v", len(rejected), limitErr), rejected))
+	}
 	return
 }
 
-func encodeFieldValue(name string, fieldType databasev1.FieldType, fieldValue *modelv1.FieldValue) *nameValue {
-	nv := &nameValue{name: name}
-	switch fieldType {
+func encodeFieldValue(spec *databasev1.FieldSpec, fieldValue *modelv1.FieldValue) *nameValue {
+	name := spec.GetName()
+	nv := &nameValue{name: name, codec: newColumnCodec(spec)}
+	switch spec.GetFieldType() {
 	case databasev1.FieldType_FIELD_TYPE_INT:
 		nv.valueType = pbv1.ValueTypeInt64
 		if fieldValue.GetInt() != nil {
@@ -536,6 +581,16 @@ func encodeFieldValue(name string, fieldType databasev1.FieldType, fieldValue *m
 		if fieldValue.GetBinaryData() != nil {
 			nv.value = bytes.Clone(fieldValue.GetBinaryData())
 		}
+	case databasev1.FieldType_FIELD_TYPE_HISTOGRAM:
+		nv.valueType = pbv1.ValueTypeHistogram
+		if fieldValue.GetHistogram() != nil {
+			value, err := pbv1.MarshalHistogram(nil, fieldValue.GetHistogram())
+			if err != nil {
+				log.Warn().Err(err).Str("field", name).Msg("drop the malformed histogram")
+			} else {
+				nv.value = value
+			}
+		}
 	default:
 		logger.Panicf("unsupported field value type: %T", fieldValue.GetValue())
 	}
@@ -0,0 +1,193 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package measure
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/blugelabs/bluge/numeric"
+
+	"github.com/apache/skywalking-banyandb/pkg/bytes"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+	"github.com/apache/skywalking-banyandb/pkg/pool"
+)
+
+// zoneMaps evaluates the skipping filter against the zone maps of a block.
+// The columns without a zone map never skip the block.
+type zoneMaps struct {
+	columns map[string]*columnMetadata
+	cfms    []*columnFamilyMetadata
+}
+
+func (zm *zoneMaps) reset() {
+	clear(zm.columns)
+	for _, cfm := range zm.cfms {
+		releaseColumnFamilyMetadata(cfm)
+	}
+	zm.cfms = zm.cfms[:0]
+}
+
+// init collects the zone maps of the fields and of the tags stored in the block.
+func (zm *zoneMaps) init(bm *blockMetadata, p *part) {
+	if zm.columns == nil {
+		zm.columns = make(map[string]*columnMetadata)
+	}
+	for i := range bm.field.columnMetadata {
+		zm.add(&bm.field.columnMetadata[i])
+	}
+	for name, db := range bm.tagFamilies {
+		metaReader, ok := p.tagFamilyMetadata[name]
+		if !ok || db.size == 0 {
+			continue
+		}
+		bb := bigValuePool.Generate()
+		bb.Buf = bytes.ResizeExact(bb.Buf, int(db.size))
+		fs.MustReadData(metaReader, int64(db.offset), bb.Buf)
+		cfm := generateColumnFamilyMetadata()
+		_, err := cfm.unmarshal(bb.Buf)
+		bigValuePool.Release(bb)
+		if err != nil {
+			logger.Panicf("%s: cannot unmarshal columnFamilyMetadata: %v", metaReader.Path(), err)
+		}
+		zm.cfms = append(zm.cfms, cfm)
+		for i := range cfm.columnMetadata {
+			zm.add(&cfm.columnMetadata[i])
+		}
+	}
+}
+
+func (zm *zoneMaps) add(cm *columnMetadata) {
+	if cm.hasZoneMap() {
+		zm.columns[cm.name] = cm
+	}
+}
+
+// Eq reports whether the value might be in the range of the column.
+func (zm *zoneMaps) Eq(name string, value string) bool {
+	cm, ok := zm.columns[name]
+	if !ok {
+		return true
+	}
+	switch cm.valueType {
+	case pbv1.ValueTypeInt64:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return true
+		}
+		return convert.BytesToInt64(cm.min) <= v && v <= convert.BytesToInt64(cm.max)
+	case pbv1.ValueTypeFloat64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return true
+		}
+		return convert.BytesToFloat64(cm.min) <= v && v <= convert.BytesToFloat64(cm.max)
+	}
+	return true
+}
+
+// Range reports whether the range of the column doesn't overlap the range of the options.
+// The bounds of an int column are the sortable float64 encoded by index.NewIntRangeOpts,
+// while the bounds of a float column are the float64 values themselves.
+func (zm *zoneMaps) Range(name string, rangeOpts index.RangeOpts) (bool, error) {
+	cm, ok := zm.columns[name]
+	if !ok {
+		return false, nil
+	}
+	if cm.valueType == pbv1.ValueTypeInt64 {
+		return rangeInt(cm, rangeOpts)
+	}
+	return rangeFloat(cm, rangeOpts)
+}
+
+func rangeFloat(cm *columnMetadata, rangeOpts index.RangeOpts) (bool, error) {
+	minValue, maxValue := convert.BytesToFloat64(cm.min), convert.BytesToFloat64(cm.max)
+	if rangeOpts.Lower != nil {
+		lower, ok := rangeOpts.Lower.(*index.FloatTermValue)
+		if !ok {
+			return false, fmt.Errorf("lower is not a float value: %v", rangeOpts.Lower)
+		}
+		if maxValue < lower.Value || !rangeOpts.IncludesLower && maxValue == lower.Value {
+			return true, nil
+		}
+	}
+	if rangeOpts.Upper != nil {
+		upper, ok := rangeOpts.Upper.(*index.FloatTermValue)
+		if !ok {
+			return false, fmt.Errorf("upper is not a float value: %v", rangeOpts.Upper)
+		}
+		if minValue > upper.Value || !rangeOpts.IncludesUpper && minValue == upper.Value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func rangeInt(cm *columnMetadata, rangeOpts index.RangeOpts) (bool, error) {
+	minValue, maxValue := convert.BytesToInt64(cm.min), convert.BytesToInt64(cm.max)
+	if rangeOpts.Lower != nil {
+		l, ok := rangeOpts.Lower.(*index.FloatTermValue)
+		if !ok {
+			return false, fmt.Errorf("lower is not a float value: %v", rangeOpts.Lower)
+		}
+		lower := numeric.Float64ToInt64(l.Value)
+		if maxValue < lower || !rangeOpts.IncludesLower && maxValue == lower {
+			return true, nil
+		}
+	}
+	if rangeOpts.Upper != nil {
+		u, ok := rangeOpts.Upper.(*index.FloatTermValue)
+		if !ok {
+			return false, fmt.Errorf("upper is not a float value: %v", rangeOpts.Upper)
+		}
+		upper := numeric.Float64ToInt64(u.Value)
+		if minValue > upper || !rangeOpts.IncludesUpper && minValue == upper {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Having reports whether any of the values might be in the range of the column.
+func (zm *zoneMaps) Having(name string, values []string) bool {
+	for _, v := range values {
+		if zm.Eq(name, v) {
+			return true
+		}
+	}
+	return len(values) == 0
+}
+
+func generateZoneMaps() *zoneMaps {
+	v := zoneMapsPool.Get()
+	if v == nil {
+		return &zoneMaps{}
+	}
+	return v
+}
+
+func releaseZoneMaps(zm *zoneMaps) {
+	zm.reset()
+	zoneMapsPool.Put(zm)
+}
+
+var zoneMapsPool = pool.Register[*zoneMaps]("measure-zoneMaps")
@@ -0,0 +1,143 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package measure
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/pkg/bytes"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+)
+
+func TestColumn_fillZoneMap(t *testing.T) {
+	tests := []struct {
+		name          string
+		valueType     pbv1.ValueType
+		values        [][]byte
+		wantMin       []byte
+		wantMax       []byte
+		wantNullCount uint64
+	}{
+		{
+			name:      "int",
+			valueType: pbv1.ValueTypeInt64,
+			values: [][]byte{
+				convert.Int64ToBytes(5), nil, convert.Int64ToBytes(-7),
+				convert.Int64ToBytes(100), []byte("null"),
+			},
+			wantMin:       convert.Int64ToBytes(-7),
+			wantMax:       convert.Int64ToBytes(100),
+			wantNullCount: 2,
+		},
+		{
+			name:      "float",
+			valueType: pbv1.ValueTypeFloat64,
+			values: [][]byte{
+				convert.Float64ToBytes(math.NaN()), convert.Float64ToBytes(-0.5),
+				convert.Float64ToBytes(2.25), convert.Float64ToBytes(-1.5),
+			},
+			wantMin: convert.Float64ToBytes(-1.5),
+			wantMax: convert.Float64ToBytes(2.25),
+		},
+		{
+			name:          "all null",
+			valueType:     pbv1.ValueTypeInt64,
+			values:        [][]byte{nil, nil},
+			wantNullCount: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &column{name: "test", valueType: tt.valueType, values: tt.values}
+			cm := &columnMetadata{}
+			w := &writer{}
+			w.init(&bytes.Buffer{})
+			c.mustWriteTo(cm, w)
+			assert.Equal(t, tt.wantMin, cm.min)
+			assert.Equal(t, tt.wantMax, cm.max)
+			assert.Equal(t, tt.wantNullCount, cm.nullCount)
+		})
+	}
+}
+
+func TestZoneMaps(t *testing.T) {
+	intColumn := &columnMetadata{
+		name:      "latency",
+		valueType: pbv1.ValueTypeInt64,
+		min:       convert.Int64ToBytes(100),
+		max:       convert.Int64ToBytes(200),
+	}
+	floatColumn := &columnMetadata{
+		name:      "ratio",
+		valueType: pbv1.ValueTypeFloat64,
+		min:       convert.Float64ToBytes(0.25),
+		max:       convert.Float64ToBytes(0.75),
+	}
+	legacyColumn := &columnMetadata{
+		name:      "legacy",
+		valueType: pbv1.ValueTypeInt64,
+	}
+	zm := generateZoneMaps()
+	defer releaseZoneMaps(zm)
+	zm.columns = make(map[string]*columnMetadata)
+	zm.add(intColumn)
+	zm.add(floatColumn)
+	zm.add(legacyColumn)
+
+	tests := []struct {
+		name     string
+		column   string
+		opts     index.RangeOpts
+		wantSkip bool
+	}{
+		{name: "int above", column: "latency", opts: index.NewIntRangeOpts(201, math.MaxInt64, true, false), wantSkip: true},
+		{name: "int above exclusive", column: "latency", opts: index.NewIntRangeOpts(200, math.MaxInt64, false, false), wantSkip: true},
+		{name: "int max inclusive", column: "latency", opts: index.NewIntRangeOpts(200, math.MaxInt64, true, false)},
+		{name: "int below", column: "latency", opts: index.NewIntRangeOpts(math.MinInt64, 99, false, true), wantSkip: true},
+		{name: "int overlap", column: "latency", opts: index.NewIntRangeOpts(150, 300, true, true)},
+		{
+			name:     "float above",
+			column:   "ratio",
+			opts:     index.RangeOpts{Lower: &index.FloatTermValue{Value: 0.8}, IncludesLower: true},
+			wantSkip: true,
+		},
+		{name: "float overlap", column: "ratio", opts: index.RangeOpts{Upper: &index.FloatTermValue{Value: 0.5}, IncludesUpper: true}},
+		{name: "no zone map", column: "legacy", opts: index.NewIntRangeOpts(201, math.MaxInt64, true, false)},
+		{name: "unknown column", column: "unknown", opts: index.NewIntRangeOpts(201, math.MaxInt64, true, false)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shouldSkip, err := zm.Range(tt.column, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSkip, shouldSkip)
+		})
+	}
+
+	assert.True(t, zm.Eq("latency", "150"))
+	assert.False(t, zm.Eq("latency", "250"))
+	assert.False(t, zm.Eq("ratio", "0.1"))
+	assert.True(t, zm.Eq("legacy", "250"))
+	assert.True(t, zm.Having("latency", []string{"1", "200"}))
+	assert.False(t, zm.Having("latency", []string{"1", "201"}))
+}
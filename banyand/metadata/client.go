@@ -131,6 +131,7 @@ type clientService struct {
 	fileRetryMaxInterval       time.Duration
 	propertySchemaMaxRecvSize  run.Bytes
 	fileRetryMultiplier        float64
+	schemaHistoryLimit         int
 	nodeInfoMux                sync.Mutex
 	forceRegisterNode          bool
 	toRegisterNode             bool
@@ -153,6 +154,8 @@ func (s *clientService) FlagSet() *run.FlagSet {
 	fs.StringVar(&s.etcdTLSKeyFile, flagEtcdTLSKeyFile, "", "Private key for the etcd client certificate.")
 	fs.DurationVar(&s.registryTimeout, "node-registry-timeout", 2*time.Minute, "The timeout for the node registry")
 	fs.DurationVar(&s.etcdFullSyncInterval, "etcd-full-sync-interval", 30*time.Minute, "The interval for full sync etcd")
+	fs.IntVar(&s.schemaHistoryLimit, "schema-history-limit", schema.DefaultHistoryLimit,
+		"The number of versions kept for every schema object, 0 disables the history")
 
 	// schema registry mode
 	fs.StringVar(&s.schemaRegistryMode, "schema-registry-mode", RegistryModeProperty,
@@ -379,6 +382,7 @@ func (s *clientService) initEtcdRegistry(l *logger.Logger, stopCh chan struct{})
 			schema.ConfigureEtcdTLSCAFile(s.etcdTLSCAFile),
 			schema.ConfigureEtcdTLSCertAndKey(s.etcdTLSCertFile, s.etcdTLSKeyFile),
 			schema.ConfigureWatchCheckInterval(s.etcdFullSyncInterval),
+			schema.ConfigureHistoryLimit(s.schemaHistoryLimit),
 		)
 		etcdErr = initErr
 		if errors.Is(etcdErr, context.DeadlineExceeded) || errors.Is(etcdErr, context.Canceled) {
@@ -434,6 +438,7 @@ func (s *clientService) initPropertySchemaRegistry(ctx context.Context, l *logge
 		MaxRecvMsgSize: int(s.propertySchemaMaxRecvSize),
 		TLSEnabled:     s.propertySchemaClientTLS,
 		CACertPath:     s.propertySchemaClientCACert,
+		HistoryLimit:   s.schemaHistoryLimit,
 	}
 	for attempt := 1; attempt <= propertyRegistryInitRetryCount; attempt++ {
 		registry, createErr := property.NewSchemaRegistryClient(cfg) //nolint:contextcheck // healthCheck uses its own 2s timeout via context.Background()
@@ -569,6 +574,10 @@ func (s *clientService) PropertyRegistry() schema.Property {
 	return s.schemaRegistry
 }
 
+func (s *clientService) HistoryRegistry() schema.History {
+	return s.schemaRegistry
+}
+
 func (s *clientService) SetMetricsRegistry(omr observability.MetricsRegistry) {
 	s.omr = omr
 }
@@ -669,6 +678,10 @@ func (s *clientService) CollectLiaisonInfo(ctx context.Context, group string) ([
 	return s.infoCollectorRegistry.CollectLiaisonInfo(ctx, group)
 }
 
+func (s *clientService) ScrubGroup(ctx context.Context, group string) error {
+	return s.infoCollectorRegistry.ScrubGroup(ctx, group)
+}
+
 func (s *clientService) DropGroup(ctx context.Context, catalog commonv1.Catalog, group string) error {
 	return s.infoCollectorRegistry.DropGroup(ctx, catalog, group)
 }
@@ -53,8 +53,10 @@ type Repo interface {
 	RegisterHandler(string, schema.Kind, schema.EventHandler)
 	NodeRegistry() schema.Node
 	PropertyRegistry() schema.Property
+	HistoryRegistry() schema.History
 	CollectDataInfo(context.Context, string) ([]*databasev1.DataInfo, error)
 	CollectLiaisonInfo(context.Context, string) ([]*databasev1.LiaisonInfo, error)
+	ScrubGroup(ctx context.Context, group string) error
 	DropGroup(ctx context.Context, catalog commonv1.Catalog, group string) error
 }
 
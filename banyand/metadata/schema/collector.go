@@ -137,6 +137,54 @@ func (icr *InfoCollectorRegistry) collectDataInfoLocal(ctx context.Context, cata
 	return nil, nil
 }
 
+// ScrubGroup starts verifying the checksums of the group's parts on both local and remote data nodes.
+// The nodes already scrubbing the group are left running.
+func (icr *InfoCollectorRegistry) ScrubGroup(ctx context.Context, group string) error {
+	g, getErr := icr.groupGetter.GetGroup(ctx, group)
+	if getErr != nil {
+		return getErr
+	}
+	icr.mux.RLock()
+	collector := icr.dataCollectors[g.Catalog]
+	icr.mux.RUnlock()
+	if scrubber, ok := collector.(DataScrubber); ok {
+		if scrubErr := scrubber.ScrubGroup(ctx, group); scrubErr != nil {
+			return fmt.Errorf("failed to scrub group locally: %w", scrubErr)
+		}
+	}
+	if icr.dataBroadcaster == nil {
+		return nil
+	}
+	var topic bus.Topic
+	switch g.Catalog {
+	case commonv1.Catalog_CATALOG_MEASURE:
+		topic = data.TopicMeasureCollectDataInfo
+	case commonv1.Catalog_CATALOG_STREAM:
+		topic = data.TopicStreamCollectDataInfo
+	case commonv1.Catalog_CATALOG_TRACE:
+		topic = data.TopicTraceCollectDataInfo
+	default:
+		return fmt.Errorf("unsupported catalog type: %v", g.Catalog)
+	}
+	message := bus.NewMessage(bus.MessageID(time.Now().UnixNano()), &databasev1.GroupRegistryServiceInspectRequest{Group: group, Scrub: true})
+	futures, broadcastErr := icr.dataBroadcaster.Broadcast(5*time.Second, topic, message)
+	if broadcastErr != nil {
+		return fmt.Errorf("failed to broadcast scrub request: %w", broadcastErr)
+	}
+	var errs []error
+	for _, future := range futures {
+		msg, getErr := future.Get()
+		if getErr != nil {
+			errs = append(errs, getErr)
+			continue
+		}
+		if errMsg, ok := msg.Data().(*common.Error); ok {
+			errs = append(errs, fmt.Errorf("node reported error scrubbing group: %s", errMsg.Error()))
+		}
+	}
+	return multierr.Combine(errs...)
+}
+
 // CollectLiaisonInfo collects liaison information from both local and remote liaison nodes.
 func (icr *InfoCollectorRegistry) CollectLiaisonInfo(ctx context.Context, group string) ([]*databasev1.LiaisonInfo, error) {
 	g, getErr := icr.groupGetter.GetGroup(ctx, group)
@@ -0,0 +1,294 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package schema
+
+import (
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/api/validate"
+)
+
+// Compatibility is the result of comparing a schema object with its previous version.
+type Compatibility struct {
+	Report *databasev1.CompatibilityReport
+	// ChangedTags holds the names of the removed tags and the tags whose types are changed.
+	ChangedTags map[string]struct{}
+	// ChangedFields holds the names of the removed fields and the fields whose specs are changed.
+	ChangedFields map[string]struct{}
+}
+
+// NewCompatibility returns an accepted Compatibility without changes.
+func NewCompatibility() *Compatibility {
+	return &Compatibility{
+		Report: &databasev1.CompatibilityReport{
+			Accepted:      true,
+			DataQueryable: true,
+		},
+		ChangedTags:   make(map[string]struct{}),
+		ChangedFields: make(map[string]struct{}),
+	}
+}
+
+// Reject marks the request as rejected by err.
+func (c *Compatibility) Reject(err error) {
+	c.Report.Accepted = false
+	c.Report.Errors = append(c.Report.Errors, err.Error())
+}
+
+// AffectIndexRule reports whether the changes affect the tags of the index rule.
+func (c *Compatibility) AffectIndexRule(rule *databasev1.IndexRule) bool {
+	for _, t := range rule.GetTags() {
+		if _, ok := c.ChangedTags[t]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// AffectTopNAggregation reports whether the changes affect the field or the group-by tags of the TopN aggregation.
+func (c *Compatibility) AffectTopNAggregation(topN *databasev1.TopNAggregation) bool {
+	if _, ok := c.ChangedFields[topN.GetFieldName()]; ok {
+		return true
+	}
+	for _, t := range topN.GetGroupByTagNames() {
+		if _, ok := c.ChangedTags[t]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Compatibility) safe(path, format string, args ...any) {
+	c.Report.Changes = append(c.Report.Changes, &databasev1.SchemaChange{Path: path, Description: fmt.Sprintf(format, args...)})
+}
+
+func (c *Compatibility) breaking(path, format string, args ...any) {
+	c.Report.Changes = append(c.Report.Changes, &databasev1.SchemaChange{Path: path, Description: fmt.Sprintf(format, args...), Breaking: true})
+}
+
+// unqueryable records a breaking change which makes the existing data unreadable by the new schema.
+func (c *Compatibility) unqueryable(path, format string, args ...any) {
+	c.breaking(path, format, args...)
+	c.Report.DataQueryable = false
+}
+
+// CheckStreamUpdate compares the stream with its previous version.
+func CheckStreamUpdate(prev, next *databasev1.Stream) *Compatibility {
+	c := NewCompatibility()
+	if err := validateStreamUpdate(prev, next); err != nil {
+		c.Reject(err)
+	}
+	c.checkEntity(prev.GetEntity().GetTagNames(), next.GetEntity().GetTagNames())
+	c.checkTagFamilies(prev.GetTagFamilies(), next.GetTagFamilies())
+	return c
+}
+
+// CheckMeasureUpdate compares the measure with its previous version.
+func CheckMeasureUpdate(prev, next *databasev1.Measure) *Compatibility {
+	c := NewCompatibility()
+	if err := validateMeasureUpdate(prev, next); err != nil {
+		c.Reject(err)
+	}
+	if prev.GetInterval() != next.GetInterval() {
+		c.unqueryable("interval", "interval changes from %q to %q", prev.GetInterval(), next.GetInterval())
+	}
+	if prev.GetIndexMode() != next.GetIndexMode() {
+		c.unqueryable("index_mode", "index mode changes from %v to %v", prev.GetIndexMode(), next.GetIndexMode())
+	}
+	if !slices.Equal(prev.GetShardingKey().GetTagNames(), next.GetShardingKey().GetTagNames()) {
+		c.breaking("sharding_key", "sharding key changes from %v to %v", prev.GetShardingKey().GetTagNames(), next.GetShardingKey().GetTagNames())
+	}
+	c.checkEntity(prev.GetEntity().GetTagNames(), next.GetEntity().GetTagNames())
+	c.checkTagFamilies(prev.GetTagFamilies(), next.GetTagFamilies())
+	c.checkFields(prev.GetFields(), next.GetFields())
+	return c
+}
+
+// CheckTraceUpdate compares the trace with its previous version.
+func CheckTraceUpdate(prev, next *databasev1.Trace) *Compatibility {
+	c := NewCompatibility()
+	if err := validate.TraceUpdate(prev, next); err != nil {
+		c.Reject(err)
+	}
+	reserved := []struct {
+		path       string
+		prev, next string
+	}{
+		{"trace_id_tag_name", prev.GetTraceIdTagName(), next.GetTraceIdTagName()},
+		{"timestamp_tag_name", prev.GetTimestampTagName(), next.GetTimestampTagName()},
+		{"span_id_tag_name", prev.GetSpanIdTagName(), next.GetSpanIdTagName()},
+	}
+	for _, r := range reserved {
+		if r.prev != r.next {
+			c.unqueryable(r.path, "%s changes from %q to %q", r.path, r.prev, r.next)
+		}
+	}
+	checkTags(c, "tags", prev.GetTags(), next.GetTags())
+	return c
+}
+
+// breakingFields are the top-level fields whose changes are breaking.
+// The changes of the fields mapped to true also make the existing data unqueryable.
+var breakingFields = map[Kind]map[protoreflect.Name]bool{
+	KindGroup:            {"catalog": true, "resource_opts": false},
+	KindIndexRule:        {"tags": true, "type": true, "analyzer": true, "no_sort": false},
+	KindIndexRuleBinding: {"subject": false, "rules": false},
+	KindTopNAggregation:  {"source_measure": true, "field_name": true, "group_by_tag_names": true, "field_value_sort": false},
+	KindProperty:         {"tags": false},
+}
+
+// CheckUpdate compares the group, index rule, index rule binding, TopN aggregation or
+// property schema with its previous version. The changes are reported by top-level fields.
+func CheckUpdate(kind Kind, prev, next proto.Message) *Compatibility {
+	c := NewCompatibility()
+	rules := breakingFields[kind]
+	pm, nm := prev.ProtoReflect(), next.ProtoReflect()
+	fields := nm.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		switch fd.Name() {
+		case "metadata", "updated_at":
+			continue
+		}
+		if pm.Get(fd).Equal(nm.Get(fd)) {
+			continue
+		}
+		path := string(fd.Name())
+		unqueryable, isBreaking := rules[fd.Name()]
+		switch {
+		case unqueryable:
+			c.unqueryable(path, "%s is changed", path)
+		case isBreaking:
+			c.breaking(path, "%s is changed", path)
+		default:
+			c.safe(path, "%s is changed", path)
+		}
+	}
+	return c
+}
+
+func (c *Compatibility) checkEntity(prev, next []string) {
+	if slices.Equal(prev, next) {
+		return
+	}
+	c.unqueryable("entity", "entity changes from %v to %v", prev, next)
+	for _, t := range prev {
+		c.ChangedTags[t] = struct{}{}
+	}
+}
+
+func (c *Compatibility) checkTagFamilies(prev, next []*databasev1.TagFamilySpec) {
+	nextIndex := make(map[string]int, len(next))
+	for i, tf := range next {
+		nextIndex[tf.GetName()] = i
+	}
+	prevNames := make(map[string]struct{}, len(prev))
+	for i, tf := range prev {
+		prevNames[tf.GetName()] = struct{}{}
+		path := "tag_families." + tf.GetName()
+		j, ok := nextIndex[tf.GetName()]
+		if !ok {
+			c.breaking(path, "tag family is removed")
+			for _, t := range tf.GetTags() {
+				c.ChangedTags[t.GetName()] = struct{}{}
+			}
+			continue
+		}
+		if i != j {
+			c.breaking(path, "tag family moves from position %d to %d", i, j)
+		}
+		checkTags(c, path+".tags", tf.GetTags(), next[j].GetTags())
+	}
+	for i, tf := range next {
+		if _, ok := prevNames[tf.GetName()]; !ok {
+			c.safe("tag_families."+tf.GetName(), "tag family is added at position %d", i)
+		}
+	}
+}
+
+type tagSpec interface {
+	GetName() string
+	GetType() databasev1.TagType
+}
+
+// checkTags compares the tags by their names. Writes carry tag values by position,
+// so a removed or moved tag breaks the clients even if the data stays queryable.
+func checkTags[T tagSpec](c *Compatibility, path string, prev, next []T) {
+	nextIndex := make(map[string]int, len(next))
+	for i, t := range next {
+		nextIndex[t.GetName()] = i
+	}
+	prevNames := make(map[string]struct{}, len(prev))
+	for i, t := range prev {
+		prevNames[t.GetName()] = struct{}{}
+		tagPath := path + "." + t.GetName()
+		j, ok := nextIndex[t.GetName()]
+		if !ok {
+			c.breaking(tagPath, "tag is removed")
+			c.ChangedTags[t.GetName()] = struct{}{}
+			continue
+		}
+		if t.GetType() != next[j].GetType() {
+			c.unqueryable(tagPath, "tag type changes from %v to %v", t.GetType(), next[j].GetType())
+			c.ChangedTags[t.GetName()] = struct{}{}
+		}
+		if i != j {
+			c.breaking(tagPath, "tag moves from position %d to %d", i, j)
+		}
+	}
+	for i, t := range next {
+		if _, ok := prevNames[t.GetName()]; !ok {
+			c.safe(path+"."+t.GetName(), "tag is added at position %d", i)
+		}
+	}
+}
+
+func (c *Compatibility) checkFields(prev, next []*databasev1.FieldSpec) {
+	nextIndex := make(map[string]int, len(next))
+	for i, f := range next {
+		nextIndex[f.GetName()] = i
+	}
+	prevNames := make(map[string]struct{}, len(prev))
+	for i, f := range prev {
+		prevNames[f.GetName()] = struct{}{}
+		path := "fields." + f.GetName()
+		j, ok := nextIndex[f.GetName()]
+		if !ok {
+			c.breaking(path, "field is removed")
+			c.ChangedFields[f.GetName()] = struct{}{}
+			continue
+		}
+		if !proto.Equal(f, next[j]) {
+			c.unqueryable(path, "field spec changes from {%s} to {%s}", f.String(), next[j].String())
+			c.ChangedFields[f.GetName()] = struct{}{}
+		}
+		if i != j {
+			c.breaking(path, "field moves from position %d to %d", i, j)
+		}
+	}
+	for i, f := range next {
+		if _, ok := prevNames[f.GetName()]; !ok {
+			c.safe("fields."+f.GetName(), "field is added at position %d", i)
+		}
+	}
+}
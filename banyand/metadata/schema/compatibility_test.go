@@ -0,0 +1,179 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/metadata/schema"
+)
+
+func testMeasure() *databasev1.Measure {
+	return &databasev1.Measure{
+		Metadata: &commonv1.Metadata{Group: "sw_metric", Name: "service_cpm_minute"},
+		TagFamilies: []*databasev1.TagFamilySpec{{
+			Name: "default",
+			Tags: []*databasev1.TagSpec{
+				{Name: "id", Type: databasev1.TagType_TAG_TYPE_STRING},
+				{Name: "layer", Type: databasev1.TagType_TAG_TYPE_STRING},
+			},
+		}},
+		Fields: []*databasev1.FieldSpec{{
+			Name:              "total",
+			FieldType:         databasev1.FieldType_FIELD_TYPE_INT,
+			EncodingMethod:    databasev1.EncodingMethod_ENCODING_METHOD_GORILLA,
+			CompressionMethod: databasev1.CompressionMethod_COMPRESSION_METHOD_ZSTD,
+		}},
+		Entity:   &databasev1.Entity{TagNames: []string{"id"}},
+		Interval: "1m",
+	}
+}
+
+func changePaths(report *databasev1.CompatibilityReport) map[string]bool {
+	paths := make(map[string]bool)
+	for _, c := range report.GetChanges() {
+		paths[c.GetPath()] = c.GetBreaking()
+	}
+	return paths
+}
+
+func Test_CheckMeasureUpdate(t *testing.T) {
+	prev := testMeasure()
+
+	t.Run("safe changes", func(t *testing.T) {
+		next := proto.Clone(prev).(*databasev1.Measure)
+		next.TagFamilies[0].Tags = append(next.TagFamilies[0].Tags, &databasev1.TagSpec{Name: "region", Type: databasev1.TagType_TAG_TYPE_STRING})
+		next.Fields = append(next.Fields, &databasev1.FieldSpec{Name: "value", FieldType: databasev1.FieldType_FIELD_TYPE_INT})
+		c := schema.CheckMeasureUpdate(prev, next)
+		assert.True(t, c.Report.GetAccepted())
+		assert.True(t, c.Report.GetDataQueryable())
+		assert.Equal(t, map[string]bool{
+			"tag_families.default.tags.region": false,
+			"fields.value":                     false,
+		}, changePaths(c.Report))
+		assert.Empty(t, c.ChangedTags)
+		assert.Empty(t, c.ChangedFields)
+	})
+
+	t.Run("removed tag", func(t *testing.T) {
+		next := proto.Clone(prev).(*databasev1.Measure)
+		next.TagFamilies[0].Tags = next.TagFamilies[0].Tags[:1]
+		c := schema.CheckMeasureUpdate(prev, next)
+		assert.True(t, c.Report.GetAccepted())
+		assert.True(t, c.Report.GetDataQueryable())
+		assert.Equal(t, map[string]bool{"tag_families.default.tags.layer": true}, changePaths(c.Report))
+		assert.True(t, c.AffectIndexRule(&databasev1.IndexRule{Tags: []string{"layer"}}))
+		assert.False(t, c.AffectIndexRule(&databasev1.IndexRule{Tags: []string{"id"}}))
+	})
+
+	t.Run("changed tag type and field", func(t *testing.T) {
+		next := proto.Clone(prev).(*databasev1.Measure)
+		next.TagFamilies[0].Tags[1].Type = databasev1.TagType_TAG_TYPE_INT
+		next.Fields[0].FieldType = databasev1.FieldType_FIELD_TYPE_FLOAT
+		c := schema.CheckMeasureUpdate(prev, next)
+		assert.False(t, c.Report.GetAccepted())
+		require.Len(t, c.Report.GetErrors(), 1)
+		assert.False(t, c.Report.GetDataQueryable())
+		assert.True(t, c.AffectTopNAggregation(&databasev1.TopNAggregation{FieldName: "total"}))
+		assert.True(t, c.AffectTopNAggregation(&databasev1.TopNAggregation{FieldName: "value", GroupByTagNames: []string{"layer"}}))
+	})
+
+	t.Run("changed interval", func(t *testing.T) {
+		next := proto.Clone(prev).(*databasev1.Measure)
+		next.Interval = "1h"
+		c := schema.CheckMeasureUpdate(prev, next)
+		assert.False(t, c.Report.GetAccepted())
+		assert.False(t, c.Report.GetDataQueryable())
+		assert.Equal(t, map[string]bool{"interval": true}, changePaths(c.Report))
+	})
+}
+
+func Test_CheckStreamUpdate(t *testing.T) {
+	prev := &databasev1.Stream{
+		Metadata: &commonv1.Metadata{Group: "default", Name: "sw"},
+		TagFamilies: []*databasev1.TagFamilySpec{
+			{Name: "searchable", Tags: []*databasev1.TagSpec{
+				{Name: "trace_id", Type: databasev1.TagType_TAG_TYPE_STRING},
+				{Name: "duration", Type: databasev1.TagType_TAG_TYPE_INT},
+			}},
+		},
+		Entity: &databasev1.Entity{TagNames: []string{"trace_id"}},
+	}
+	next := proto.Clone(prev).(*databasev1.Stream)
+	tags := next.TagFamilies[0].Tags
+	tags[0], tags[1] = tags[1], tags[0]
+	c := schema.CheckStreamUpdate(prev, next)
+	assert.True(t, c.Report.GetAccepted())
+	assert.True(t, c.Report.GetDataQueryable())
+	assert.Equal(t, map[string]bool{
+		"tag_families.searchable.tags.trace_id": true,
+		"tag_families.searchable.tags.duration": true,
+	}, changePaths(c.Report))
+
+	next = proto.Clone(prev).(*databasev1.Stream)
+	next.TagFamilies = append(next.TagFamilies, &databasev1.TagFamilySpec{Name: "data"})
+	c = schema.CheckStreamUpdate(prev, next)
+	assert.Equal(t, map[string]bool{"tag_families.data": false}, changePaths(c.Report))
+}
+
+func Test_CheckTraceUpdate(t *testing.T) {
+	prev := &databasev1.Trace{
+		Metadata: &commonv1.Metadata{Group: "default", Name: "sw"},
+		Tags: []*databasev1.TraceTagSpec{
+			{Name: "trace_id", Type: databasev1.TagType_TAG_TYPE_STRING},
+			{Name: "span_id", Type: databasev1.TagType_TAG_TYPE_STRING},
+			{Name: "timestamp", Type: databasev1.TagType_TAG_TYPE_TIMESTAMP},
+		},
+		TraceIdTagName:   "trace_id",
+		SpanIdTagName:    "span_id",
+		TimestampTagName: "timestamp",
+	}
+	next := proto.Clone(prev).(*databasev1.Trace)
+	next.Tags = next.Tags[:2]
+	c := schema.CheckTraceUpdate(prev, next)
+	assert.False(t, c.Report.GetAccepted())
+	assert.Equal(t, map[string]bool{"tags.timestamp": true}, changePaths(c.Report))
+}
+
+func Test_CheckUpdate(t *testing.T) {
+	prev := &databasev1.IndexRule{
+		Metadata: &commonv1.Metadata{Group: "default", Name: "duration", ModRevision: 1},
+		Tags:     []string{"duration"},
+		Type:     databasev1.IndexRule_TYPE_INVERTED,
+	}
+	next := proto.Clone(prev).(*databasev1.IndexRule)
+	next.Metadata.ModRevision = 2
+	c := schema.CheckUpdate(schema.KindIndexRule, prev, next)
+	assert.Empty(t, c.Report.GetChanges())
+
+	next.NoSort = true
+	c = schema.CheckUpdate(schema.KindIndexRule, prev, next)
+	assert.Equal(t, map[string]bool{"no_sort": true}, changePaths(c.Report))
+	assert.True(t, c.Report.GetDataQueryable())
+
+	next.Tags = []string{"latency"}
+	c = schema.CheckUpdate(schema.KindIndexRule, prev, next)
+	assert.Equal(t, map[string]bool{"no_sort": true, "tags": true}, changePaths(c.Report))
+	assert.False(t, c.Report.GetDataQueryable())
+}
@@ -55,6 +55,7 @@ var (
 	_ Measure          = (*etcdSchemaRegistry)(nil)
 	_ Trace            = (*etcdSchemaRegistry)(nil)
 	_ Group            = (*etcdSchemaRegistry)(nil)
+	_ History          = (*etcdSchemaRegistry)(nil)
 
 	errUnexpectedNumberOfEntities = errors.New("unexpected number of entities")
 	errConcurrentModification     = errors.New("concurrent modification of entities")
@@ -119,6 +120,14 @@ func ConfigureWatchCheckInterval(d time.Duration) RegistryOption {
 	}
 }
 
+// ConfigureHistoryLimit sets the number of versions kept for every schema object.
+// A non-positive limit disables the history.
+func ConfigureHistoryLimit(limit int) RegistryOption {
+	return func(config *etcdSchemaRegistryConfig) {
+		config.historyLimit = limit
+	}
+}
+
 // CheckInterval sets the interval to check the watcher.
 func CheckInterval(d time.Duration) WatcherOption {
 	return func(wc *watcherConfig) {
@@ -140,6 +149,7 @@ type etcdSchemaRegistry struct {
 	watchers      map[Kind]*watcher
 	namespace     string
 	checkInterval time.Duration
+	historyLimit  int
 	mux           sync.RWMutex
 	startOnce     sync.Once
 	closeOnce     sync.Once
@@ -154,6 +164,7 @@ type etcdSchemaRegistryConfig struct {
 	tlsKeyFile      string
 	serverEndpoints []string
 	checkInterval   time.Duration
+	historyLimit    int
 }
 
 func (e *etcdSchemaRegistry) RegisterHandler(name string, kind Kind, handler EventHandler) {
@@ -242,7 +253,7 @@ func (e *etcdSchemaRegistry) Close() error {
 
 // NewEtcdSchemaRegistry returns a Registry powered by Etcd.
 func NewEtcdSchemaRegistry(options ...RegistryOption) (Registry, error) {
-	registryConfig := &etcdSchemaRegistryConfig{}
+	registryConfig := &etcdSchemaRegistryConfig{historyLimit: DefaultHistoryLimit}
 	for _, opt := range options {
 		opt(registryConfig)
 	}
@@ -280,6 +291,7 @@ func NewEtcdSchemaRegistry(options ...RegistryOption) (Registry, error) {
 		closer:        run.NewCloser(1),
 		l:             schemaLogger,
 		checkInterval: registryConfig.checkInterval,
+		historyLimit:  registryConfig.historyLimit,
 		watchers:      make(map[Kind]*watcher),
 	}
 	return reg, nil
@@ -332,11 +344,11 @@ func (e *etcdSchemaRegistry) update(ctx context.Context, metadata Metadata) (int
 		return 0, ErrClosed
 	}
 	defer e.closer.Done()
-	key, err := metadata.key()
+	rawKey, err := metadata.key()
 	if err != nil {
 		return 0, err
 	}
-	key = e.prependNamespace(key)
+	key := e.prependNamespace(rawKey)
 	getResp, err := e.client.Get(ctx, key)
 	if err != nil {
 		return 0, err
@@ -375,8 +387,9 @@ func (e *etcdSchemaRegistry) update(ctx context.Context, metadata Metadata) (int
 	if !txnResp.Succeeded {
 		return 0, errConcurrentModification
 	}
-
-	return txnResp.Responses[0].GetResponsePut().Header.Revision, nil
+	revision := txnResp.Responses[0].GetResponsePut().Header.Revision
+	e.recordHistory(ctx, metadata, rawKey, revision)
+	return revision, nil
 }
 
 // create will first check existence of the entity with the metadata,
@@ -387,11 +400,11 @@ func (e *etcdSchemaRegistry) create(ctx context.Context, metadata Metadata) (int
 		return 0, ErrClosed
 	}
 	defer e.closer.Done()
-	key, err := metadata.key()
+	rawKey, err := metadata.key()
 	if err != nil {
 		return 0, err
 	}
-	key = e.prependNamespace(key)
+	key := e.prependNamespace(rawKey)
 	getResp, err := e.client.Get(ctx, key)
 	if err != nil {
 		return 0, err
@@ -415,7 +428,7 @@ func (e *etcdSchemaRegistry) create(ctx context.Context, metadata Metadata) (int
 		}
 		return 0, err
 	}
-
+	e.recordHistory(ctx, metadata, rawKey, putResp.Header.Revision)
 	return putResp.Header.Revision, nil
 }
 
@@ -445,16 +458,19 @@ func (e *etcdSchemaRegistry) delete(ctx context.Context, metadata Metadata) (boo
 		return false, ErrClosed
 	}
 	defer e.closer.Done()
-	key, err := metadata.key()
+	rawKey, err := metadata.key()
 	if err != nil {
 		return false, err
 	}
-	key = e.prependNamespace(key)
+	key := e.prependNamespace(rawKey)
 	resp, err := e.client.Delete(ctx, key, clientv3.WithPrevKV())
 	if err != nil {
 		return false, err
 	}
 	if resp.Deleted == 1 {
+		if _, err = e.client.Delete(ctx, e.prependNamespace(formatHistoryPrefix(rawKey)), clientv3.WithPrefix()); err != nil {
+			e.l.Warn().Err(err).Str("key", rawKey).Msg("failed to drop the schema revisions")
+		}
 		return true, nil
 	}
 	return false, nil
@@ -422,6 +422,39 @@ func Test_Etcd_Entity_Update(t *testing.T) {
 	}
 }
 
+func Test_Etcd_History(t *testing.T) {
+	req := require.New(t)
+	registry, closer := initServerAndRegister(t)
+	defer closer()
+	req.NoError(preloadSchema(registry))
+
+	ctx := schema.WithAuthor(context.Background(), "admin")
+	md := &commonv1.Metadata{Name: "sw", Group: "default"}
+	s, err := registry.GetStream(ctx, md)
+	req.NoError(err)
+	created := s.GetMetadata().GetModRevision()
+	s.TagFamilies[0].Tags = append(s.TagFamilies[0].Tags, &databasev1.TagSpec{Name: "extra", Type: databasev1.TagType_TAG_TYPE_STRING})
+	updated, err := registry.UpdateStream(ctx, s)
+	req.NoError(err)
+
+	revisions, err := registry.ListHistory(ctx, schema.KindStream, md)
+	req.NoError(err)
+	req.Len(revisions, 2)
+	req.Equal(updated, revisions[0].GetModRevision())
+	req.Equal("admin", revisions[0].GetAuthor())
+	req.Len(revisions[0].GetStream().GetTagFamilies()[0].GetTags(), 2)
+	req.Equal(created, revisions[1].GetModRevision())
+	req.Empty(revisions[1].GetAuthor())
+	req.Len(revisions[1].GetStream().GetTagFamilies()[0].GetTags(), 1)
+
+	deleted, err := registry.DeleteStream(ctx, md)
+	req.NoError(err)
+	req.True(deleted)
+	revisions, err = registry.ListHistory(ctx, schema.KindStream, md)
+	req.NoError(err)
+	req.Empty(revisions)
+}
+
 func Test_Etcd_Stream_GroupPrefixMatching(t *testing.T) {
 	tester := assert.New(t)
 	registry, closer := initServerAndRegister(t)
@@ -696,3 +729,49 @@ func Test_Etcd_GroupPrefixMatching_EdgeCases(t *testing.T) {
 	tester.Equal(1, len(testStreams), "Group 'test' should have exactly 1 stream")
 	tester.Equal("test", testStreams[0].Metadata.Group)
 }
+
+func Test_Etcd_Group_RemoteStage(t *testing.T) {
+	tester := assert.New(t)
+	registry, closer := initServerAndRegister(t)
+	defer closer()
+
+	newGroup := func(name string, catalog commonv1.Catalog) *commonv1.Group {
+		return &commonv1.Group{
+			Metadata: &commonv1.Metadata{
+				Name: name,
+			},
+			Catalog: catalog,
+			ResourceOpts: &commonv1.ResourceOpts{
+				ShardNum: 2,
+				SegmentInterval: &commonv1.IntervalRule{
+					Unit: commonv1.IntervalRule_UNIT_DAY,
+					Num:  1,
+				},
+				Ttl: &commonv1.IntervalRule{
+					Unit: commonv1.IntervalRule_UNIT_DAY,
+					Num:  7,
+				},
+				Stages: []*commonv1.LifecycleStage{
+					{
+						Name:            "cold",
+						ShardNum:        1,
+						SegmentInterval: &commonv1.IntervalRule{Unit: commonv1.IntervalRule_UNIT_DAY, Num: 3},
+						Ttl:             &commonv1.IntervalRule{Unit: commonv1.IntervalRule_UNIT_DAY, Num: 30},
+						NodeSelector:    "type=cold",
+						Remote:          true,
+					},
+				},
+			},
+		}
+	}
+
+	tester.NoError(registry.CreateGroup(context.TODO(), newGroup("remote_measure", commonv1.Catalog_CATALOG_MEASURE)))
+	tester.Error(registry.CreateGroup(context.TODO(), newGroup("remote_trace", commonv1.Catalog_CATALOG_TRACE)))
+
+	// A trace group can't turn a stage into a remote one either.
+	group := newGroup("local_trace", commonv1.Catalog_CATALOG_TRACE)
+	group.ResourceOpts.Stages[0].Remote = false
+	tester.NoError(registry.CreateGroup(context.TODO(), group))
+	group.ResourceOpts.Stages[0].Remote = true
+	tester.Error(registry.UpdateGroup(context.TODO(), group))
+}
@@ -20,6 +20,7 @@ package schema
 import (
 	"context"
 	"path"
+	"strings"
 
 	"github.com/pkg/errors"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -59,11 +60,14 @@ func (e *etcdSchemaRegistry) DeleteGroup(ctx context.Context, group string) (boo
 		return false, errors.Wrap(err, group)
 	}
 	keysToDelete := AllKeys()
-	deleteOPs := make([]clientv3.Op, 0, len(keysToDelete)+1)
+	deleteOPs := make([]clientv3.Op, 0, 2*len(keysToDelete)+2)
 	for _, key := range keysToDelete {
-		deleteOPs = append(deleteOPs, clientv3.OpDelete(e.prependNamespace(listPrefixesForEntity(group, key)), clientv3.WithPrefix()))
+		prefix := listPrefixesForEntity(group, key)
+		deleteOPs = append(deleteOPs, clientv3.OpDelete(e.prependNamespace(prefix), clientv3.WithPrefix()),
+			clientv3.OpDelete(e.prependNamespace(formatHistoryPrefix(strings.TrimSuffix(prefix, "/"))), clientv3.WithPrefix()))
 	}
-	deleteOPs = append(deleteOPs, clientv3.OpDelete(e.prependNamespace(formatGroupKey(group)), clientv3.WithPrefix()))
+	deleteOPs = append(deleteOPs, clientv3.OpDelete(e.prependNamespace(formatGroupKey(group)), clientv3.WithPrefix()),
+		clientv3.OpDelete(e.prependNamespace(formatHistoryPrefix(formatGroupKey(group))), clientv3.WithPrefix()))
 	txnResponse, err := e.client.Txn(ctx).Then(deleteOPs...).Commit()
 	if err != nil {
 		return false, err
@@ -0,0 +1,199 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+)
+
+const (
+	historyKeyPrefix = "/histories/"
+	// DefaultHistoryLimit is the default number of versions kept for every schema object.
+	DefaultHistoryLimit = 10
+)
+
+// History allows listing the kept versions of schema objects.
+type History interface {
+	// ListHistory returns the kept versions of a schema object, the latest first.
+	ListHistory(ctx context.Context, kind Kind, metadata *commonv1.Metadata) ([]*databasev1.SchemaRevision, error)
+}
+
+type authorKey struct{}
+
+// WithAuthor returns a copy of ctx which records author as the author of the schema changes.
+func WithAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, authorKey{}, author)
+}
+
+// AuthorFromContext returns the author of the schema changes recorded by WithAuthor.
+func AuthorFromContext(ctx context.Context) string {
+	if author, ok := ctx.Value(authorKey{}).(string); ok {
+		return author
+	}
+	return ""
+}
+
+// NewSchemaRevision wraps a version of a schema object into a SchemaRevision.
+func NewSchemaRevision(kind Kind, spec proto.Message, modRevision int64, author string, updatedAt time.Time) (*databasev1.SchemaRevision, error) {
+	rev := &databasev1.SchemaRevision{
+		ModRevision: modRevision,
+		Author:      author,
+		UpdatedAt:   timestamppb.New(updatedAt),
+	}
+	switch kind {
+	case KindGroup:
+		rev.Spec = &databasev1.SchemaRevision_Group{Group: spec.(*commonv1.Group)}
+	case KindStream:
+		rev.Spec = &databasev1.SchemaRevision_Stream{Stream: spec.(*databasev1.Stream)}
+	case KindMeasure:
+		rev.Spec = &databasev1.SchemaRevision_Measure{Measure: spec.(*databasev1.Measure)}
+	case KindTrace:
+		rev.Spec = &databasev1.SchemaRevision_Trace{Trace: spec.(*databasev1.Trace)}
+	case KindIndexRule:
+		rev.Spec = &databasev1.SchemaRevision_IndexRule{IndexRule: spec.(*databasev1.IndexRule)}
+	case KindIndexRuleBinding:
+		rev.Spec = &databasev1.SchemaRevision_IndexRuleBinding{IndexRuleBinding: spec.(*databasev1.IndexRuleBinding)}
+	case KindTopNAggregation:
+		rev.Spec = &databasev1.SchemaRevision_TopnAggregation{TopnAggregation: spec.(*databasev1.TopNAggregation)}
+	case KindProperty:
+		rev.Spec = &databasev1.SchemaRevision_Property{Property: spec.(*databasev1.Property)}
+	default:
+		return nil, ErrUnsupportedEntityType
+	}
+	return rev, nil
+}
+
+// RevisionSpec returns the schema object kept in a SchemaRevision.
+func RevisionSpec(rev *databasev1.SchemaRevision) HasMetadata {
+	switch s := rev.GetSpec().(type) {
+	case *databasev1.SchemaRevision_Group:
+		return s.Group
+	case *databasev1.SchemaRevision_Stream:
+		return s.Stream
+	case *databasev1.SchemaRevision_Measure:
+		return s.Measure
+	case *databasev1.SchemaRevision_Trace:
+		return s.Trace
+	case *databasev1.SchemaRevision_IndexRule:
+		return s.IndexRule
+	case *databasev1.SchemaRevision_IndexRuleBinding:
+		return s.IndexRuleBinding
+	case *databasev1.SchemaRevision_TopnAggregation:
+		return s.TopnAggregation
+	case *databasev1.SchemaRevision_Property:
+		return s.Property
+	default:
+		return nil
+	}
+}
+
+func formatHistoryPrefix(key string) string {
+	return historyKeyPrefix + strings.TrimPrefix(key, "/") + "/"
+}
+
+func formatHistoryKey(key string, modRevision int64) string {
+	return formatHistoryPrefix(key) + fmt.Sprintf("%020d", modRevision)
+}
+
+// recordHistory keeps the version of a schema object written at modRevision,
+// and drops the oldest versions beyond the history limit.
+// Failures are logged rather than returned since the version has been written.
+func (e *etcdSchemaRegistry) recordHistory(ctx context.Context, metadata Metadata, key string, modRevision int64) {
+	if e.historyLimit <= 0 {
+		return
+	}
+	spec, ok := proto.Clone(metadata.Spec.(proto.Message)).(HasMetadata)
+	if !ok {
+		return
+	}
+	spec.GetMetadata().CreateRevision = 0
+	spec.GetMetadata().ModRevision = modRevision
+	rev, err := NewSchemaRevision(metadata.Kind, spec, modRevision, AuthorFromContext(ctx), time.Now())
+	if err != nil {
+		e.l.Warn().Err(err).Str("key", key).Msg("failed to build the schema revision")
+		return
+	}
+	val, err := proto.Marshal(rev)
+	if err != nil {
+		e.l.Warn().Err(err).Str("key", key).Msg("failed to marshal the schema revision")
+		return
+	}
+	if _, err = e.client.Put(ctx, e.prependNamespace(formatHistoryKey(key, modRevision)), string(val)); err != nil {
+		e.l.Warn().Err(err).Str("key", key).Msg("failed to record the schema revision")
+		return
+	}
+	prefix := e.prependNamespace(formatHistoryPrefix(key))
+	resp, err := e.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		e.l.Warn().Err(err).Str("key", key).Msg("failed to list the schema revisions")
+		return
+	}
+	if len(resp.Kvs) <= e.historyLimit {
+		return
+	}
+	expired := resp.Kvs[:len(resp.Kvs)-e.historyLimit]
+	ops := make([]clientv3.Op, 0, len(expired))
+	for _, kv := range expired {
+		ops = append(ops, clientv3.OpDelete(string(kv.Key)))
+	}
+	if _, err = e.client.Txn(ctx).Then(ops...).Commit(); err != nil {
+		e.l.Warn().Err(err).Str("key", key).Msg("failed to drop the expired schema revisions")
+	}
+}
+
+func (e *etcdSchemaRegistry) ListHistory(ctx context.Context, kind Kind, metadata *commonv1.Metadata) ([]*databasev1.SchemaRevision, error) {
+	if !e.closer.AddRunning() {
+		return nil, ErrClosed
+	}
+	defer e.closer.Done()
+	key, err := Metadata{
+		TypeMeta: TypeMeta{
+			Kind:  kind,
+			Group: metadata.GetGroup(),
+			Name:  metadata.GetName(),
+		},
+	}.key()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.client.Get(ctx, e.prependNamespace(formatHistoryPrefix(key)), clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]*databasev1.SchemaRevision, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		rev := &databasev1.SchemaRevision{}
+		if err = proto.Unmarshal(kv.Value, rev); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
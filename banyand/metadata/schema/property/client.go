@@ -71,6 +71,7 @@ type ClientConfig struct {
 	InitWaitTime       time.Duration
 	FullReconcileEvery uint64
 	MaxRecvMsgSize     int
+	HistoryLimit       int
 	TLSEnabled         bool
 }
 
@@ -109,6 +110,7 @@ type SchemaRegistry struct {
 	syncInterval       time.Duration
 	fullReconcileEvery uint64
 	syncRound          uint64
+	historyLimit       int
 	mux                sync.RWMutex
 	watchMu            sync.Mutex
 }
@@ -156,6 +158,7 @@ func NewSchemaRegistryClient(cfg *ClientConfig) (*SchemaRegistry, error) {
 		watchSessions:      make(map[string]*watchSession),
 		syncInterval:       syncInterval,
 		fullReconcileEvery: fullReconcileEvery,
+		historyLimit:       cfg.HistoryLimit,
 	}
 	handler.registry = reg
 
@@ -453,6 +456,9 @@ func (r *SchemaRegistry) broadcastDelete(ctx context.Context, kind schema.Kind,
 		}
 		return nil
 	})
+	if found.Load() {
+		r.dropHistory(ctx, kind, group, name)
+	}
 	return found.Load(), writeErr
 }
 
@@ -538,7 +544,11 @@ func createResource[T proto.Message](ctx context.Context, r *SchemaRegistry,
 	if convErr != nil {
 		return convErr
 	}
-	return r.broadcastInsert(ctx, prop)
+	if insertErr := r.broadcastInsert(ctx, prop); insertErr != nil {
+		return insertErr
+	}
+	r.recordHistory(ctx, kind, spec)
+	return nil
 }
 
 func updateResource[T proto.Message](ctx context.Context, r *SchemaRegistry,
@@ -562,10 +572,14 @@ func updateResource[T proto.Message](ctx context.Context, r *SchemaRegistry,
 	if convErr != nil {
 		return convErr
 	}
-	return r.broadcastAll(func(_ string, c *schemaClient) error {
+	if updateErr := r.broadcastAll(func(_ string, c *schemaClient) error {
 		_, rpcErr := c.management.UpdateSchema(ctx, &schemav1.UpdateSchemaRequest{Property: prop})
 		return rpcErr
-	})
+	}); updateErr != nil {
+		return updateErr
+	}
+	r.recordHistory(ctx, kind, spec)
+	return nil
 }
 
 // GetStream retrieves a stream schema.
@@ -1147,6 +1161,9 @@ func (r *SchemaRegistry) processWatchSession(ctx context.Context,
 				}
 				continue
 			}
+			if isHistoryProperty(resp.GetProperty()) {
+				continue
+			}
 			if inSync && metadataOnly {
 				digests = append(digests, r.parseDigest(resp))
 			} else {
@@ -567,6 +567,90 @@ func TestPropertyCRUD(t *testing.T) {
 	require.Error(t, getErr)
 }
 
+func TestHistory(t *testing.T) {
+	addr := startTestSchemaServer(t)
+	reg := newTestRegistryWithConfig(t, &property.ClientConfig{HistoryLimit: schema.DefaultHistoryLimit}, addr)
+	ctx := schema.WithAuthor(context.Background(), "admin")
+	createTestGroup(t, reg)
+	md := &commonv1.Metadata{Group: "test-group", Name: testStreamName}
+	created, createErr := reg.CreateStream(context.Background(), testStream())
+	require.NoError(t, createErr)
+	s, getErr := reg.GetStream(ctx, md)
+	require.NoError(t, getErr)
+	s.TagFamilies[0].Tags = append(s.TagFamilies[0].Tags, &databasev1.TagSpec{Name: "endpoint", Type: databasev1.TagType_TAG_TYPE_STRING})
+	updated, updateErr := reg.UpdateStream(ctx, s)
+	require.NoError(t, updateErr)
+
+	revisions, listErr := reg.ListHistory(ctx, schema.KindStream, md)
+	require.NoError(t, listErr)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, updated, revisions[0].GetModRevision())
+	assert.Equal(t, "admin", revisions[0].GetAuthor())
+	assert.Len(t, revisions[0].GetStream().GetTagFamilies()[0].GetTags(), 2)
+	assert.Equal(t, created, revisions[1].GetModRevision())
+	assert.Empty(t, revisions[1].GetAuthor())
+	assert.Len(t, revisions[1].GetStream().GetTagFamilies()[0].GetTags(), 1)
+	streams, listErr := reg.ListStream(ctx, schema.ListOpt{Group: "test-group"})
+	require.NoError(t, listErr)
+	assert.Len(t, streams, 1)
+
+	groupRevisions, listErr := reg.ListHistory(ctx, schema.KindGroup, &commonv1.Metadata{Name: "test-group"})
+	require.NoError(t, listErr)
+	assert.Len(t, groupRevisions, 1)
+
+	deleted, deleteErr := reg.DeleteStream(ctx, md)
+	require.NoError(t, deleteErr)
+	assert.True(t, deleted)
+	revisions, listErr = reg.ListHistory(ctx, schema.KindStream, md)
+	require.NoError(t, listErr)
+	assert.Empty(t, revisions)
+}
+
+func TestHistory_Limit(t *testing.T) {
+	addr := startTestSchemaServer(t)
+	reg := newTestRegistryWithConfig(t, &property.ClientConfig{HistoryLimit: 2}, addr)
+	ctx := context.Background()
+	createTestGroup(t, reg)
+	md := &commonv1.Metadata{Group: "test-group", Name: testStreamName}
+	_, createErr := reg.CreateStream(ctx, testStream())
+	require.NoError(t, createErr)
+	var modRevisions []int64
+	for idx := 0; idx < 3; idx++ {
+		s, getErr := reg.GetStream(ctx, md)
+		require.NoError(t, getErr)
+		modRev, updateErr := reg.UpdateStream(ctx, s)
+		require.NoError(t, updateErr)
+		modRevisions = append(modRevisions, modRev)
+	}
+	revisions, listErr := reg.ListHistory(ctx, schema.KindStream, md)
+	require.NoError(t, listErr)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, modRevisions[2], revisions[0].GetModRevision())
+	assert.Equal(t, modRevisions[1], revisions[1].GetModRevision())
+
+	deleted, deleteErr := reg.DeleteGroup(ctx, "test-group")
+	require.NoError(t, deleteErr)
+	assert.True(t, deleted)
+	revisions, listErr = reg.ListHistory(ctx, schema.KindStream, md)
+	require.NoError(t, listErr)
+	assert.Empty(t, revisions)
+	revisions, listErr = reg.ListHistory(ctx, schema.KindGroup, &commonv1.Metadata{Name: "test-group"})
+	require.NoError(t, listErr)
+	assert.Empty(t, revisions)
+}
+
+func TestHistory_Disabled(t *testing.T) {
+	addr := startTestSchemaServer(t)
+	reg := newTestRegistry(t, addr)
+	ctx := context.Background()
+	createTestGroup(t, reg)
+	_, createErr := reg.CreateStream(ctx, testStream())
+	require.NoError(t, createErr)
+	revisions, listErr := reg.ListHistory(ctx, schema.KindStream, &commonv1.Metadata{Group: "test-group", Name: testStreamName})
+	require.NoError(t, listErr)
+	assert.Empty(t, revisions)
+}
+
 func TestCreateDuplicate(t *testing.T) {
 	addr := startTestSchemaServer(t)
 	reg := newTestRegistry(t, addr)
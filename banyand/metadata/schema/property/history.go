@@ -0,0 +1,209 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package property
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	propertyv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/property/v1"
+	schemav1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/schema/v1"
+	"github.com/apache/skywalking-banyandb/banyand/metadata/schema"
+)
+
+// HistoryPropertyName is the property name of the kept versions of schema objects.
+// They are stored in the schema group next to the schemas, but never loaded as schemas.
+const HistoryPropertyName = "_schema_history"
+
+// tagKeyObject holds the property ID of the schema object a version belongs to.
+const tagKeyObject = "object"
+
+func isHistoryProperty(prop *propertyv1.Property) bool {
+	return prop.GetMetadata().GetName() == HistoryPropertyName
+}
+
+func buildHistoryPropertyID(objectID string, modRevision int64) string {
+	return objectID + "@" + fmt.Sprintf("%020d", modRevision)
+}
+
+// historyToProperty wraps the version of a schema object written at its mod revision into a history property.
+func historyToProperty(ctx context.Context, kind schema.Kind, metadata *commonv1.Metadata, spec proto.Message) (*propertyv1.Property, error) {
+	cloned, ok := proto.Clone(spec).(schema.HasMetadata)
+	if !ok {
+		return nil, fmt.Errorf("unexpected spec type for kind %s", kind)
+	}
+	modRevision := metadata.GetModRevision()
+	cloned.GetMetadata().CreateRevision = 0
+	cloned.GetMetadata().ModRevision = modRevision
+	now := time.Now()
+	rev, revErr := schema.NewSchemaRevision(kind, cloned, modRevision, schema.AuthorFromContext(ctx), now)
+	if revErr != nil {
+		return nil, revErr
+	}
+	data, marshalErr := protojson.Marshal(rev)
+	if marshalErr != nil {
+		return nil, fmt.Errorf("failed to marshal schema revision to protojson: %w", marshalErr)
+	}
+	objectID := BuildPropertyID(kind, metadata)
+	return &propertyv1.Property{
+		Metadata: &commonv1.Metadata{
+			Name:        HistoryPropertyName,
+			ModRevision: modRevision,
+		},
+		Id: buildHistoryPropertyID(objectID, modRevision),
+		Tags: []*modelv1.Tag{
+			{Key: tagKeyObject, Value: &modelv1.TagValue{Value: &modelv1.TagValue_Str{Str: &modelv1.Str{Value: objectID}}}},
+			{Key: TagKeyGroup, Value: &modelv1.TagValue{Value: &modelv1.TagValue_Str{Str: &modelv1.Str{Value: metadata.GetGroup()}}}},
+			{Key: TagKeyName, Value: &modelv1.TagValue{Value: &modelv1.TagValue_Str{Str: &modelv1.Str{Value: metadata.GetName()}}}},
+			{Key: TagKeySource, Value: &modelv1.TagValue{Value: &modelv1.TagValue_Str{Str: &modelv1.Str{Value: string(data)}}}},
+			{Key: TagKeyKind, Value: &modelv1.TagValue{Value: &modelv1.TagValue_Str{Str: &modelv1.Str{Value: kind.String()}}}},
+			{Key: TagKeyUpdatedAt, Value: &modelv1.TagValue{Value: &modelv1.TagValue_Int{Int: &modelv1.Int{Value: modRevision}}}},
+		},
+		UpdatedAt: timestamppb.New(now),
+	}, nil
+}
+
+func buildHistoryQuery(objectID string) *propertyv1.QueryRequest {
+	return &propertyv1.QueryRequest{
+		Groups: []string{schema.SchemaGroup},
+		Name:   HistoryPropertyName,
+		Criteria: &modelv1.Criteria{
+			Exp: &modelv1.Criteria_Condition{
+				Condition: &modelv1.Condition{
+					Name: tagKeyObject,
+					Op:   modelv1.Condition_BINARY_OP_EQ,
+					Value: &modelv1.TagValue{
+						Value: &modelv1.TagValue_Str{Str: &modelv1.Str{Value: objectID}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// recordHistory keeps the version of a schema object just written,
+// and drops the oldest versions beyond the history limit.
+// Failures are logged rather than returned since the version has been written.
+func (r *SchemaRegistry) recordHistory(ctx context.Context, kind schema.Kind, spec proto.Message) {
+	if r.historyLimit <= 0 {
+		return
+	}
+	metadata, metaErr := getMetadataFromSpec(kind, spec)
+	if metaErr != nil {
+		r.l.Warn().Err(metaErr).Stringer("kind", kind).Msg("failed to build the schema revision")
+		return
+	}
+	prop, convErr := historyToProperty(ctx, kind, metadata, spec)
+	if convErr != nil {
+		r.l.Warn().Err(convErr).Stringer("kind", kind).Msg("failed to build the schema revision")
+		return
+	}
+	if insertErr := r.broadcastInsert(ctx, prop); insertErr != nil {
+		r.l.Warn().Err(insertErr).Str("propID", prop.GetId()).Msg("failed to record the schema revision")
+		return
+	}
+	objectID := BuildPropertyID(kind, metadata)
+	props, listErr := r.listHistoryProperties(ctx, objectID)
+	if listErr != nil {
+		r.l.Warn().Err(listErr).Str("propID", objectID).Msg("failed to list the schema revisions")
+		return
+	}
+	if len(props) <= r.historyLimit {
+		return
+	}
+	if dropErr := r.dropHistoryProperties(ctx, props[r.historyLimit:]); dropErr != nil {
+		r.l.Warn().Err(dropErr).Str("propID", objectID).Msg("failed to drop the expired schema revisions")
+	}
+}
+
+// listHistoryProperties returns the kept versions of a schema object, the latest first.
+func (r *SchemaRegistry) listHistoryProperties(ctx context.Context, objectID string) ([]*propertyv1.Property, error) {
+	propMap, queryErr := r.queryAndRepairSchemas(ctx, buildHistoryQuery(objectID))
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	props := make([]*propertyv1.Property, 0, len(propMap))
+	for _, info := range propMap {
+		if info.best != nil && info.best.deleteTime == 0 {
+			props = append(props, info.best.property)
+		}
+	}
+	sort.Slice(props, func(i, j int) bool {
+		return ParseTags(props[i].GetTags()).UpdatedAt > ParseTags(props[j].GetTags()).UpdatedAt
+	})
+	return props, nil
+}
+
+func (r *SchemaRegistry) dropHistoryProperties(ctx context.Context, props []*propertyv1.Property) error {
+	for _, prop := range props {
+		req := &schemav1.DeleteSchemaRequest{
+			Delete: &propertyv1.DeleteRequest{
+				Group: schema.SchemaGroup,
+				Name:  HistoryPropertyName,
+				Id:    prop.GetId(),
+			},
+			UpdateAt: timestamppb.Now(),
+		}
+		if deleteErr := r.broadcastAll(func(_ string, c *schemaClient) error {
+			_, rpcErr := c.management.DeleteSchema(ctx, req)
+			return rpcErr
+		}); deleteErr != nil {
+			return deleteErr
+		}
+	}
+	return nil
+}
+
+// dropHistory drops all the kept versions of a deleted schema object.
+func (r *SchemaRegistry) dropHistory(ctx context.Context, kind schema.Kind, group, name string) {
+	objectID := BuildPropertyID(kind, &commonv1.Metadata{Group: group, Name: name})
+	props, listErr := r.listHistoryProperties(ctx, objectID)
+	if listErr != nil {
+		r.l.Warn().Err(listErr).Str("propID", objectID).Msg("failed to list the schema revisions")
+		return
+	}
+	if dropErr := r.dropHistoryProperties(ctx, props); dropErr != nil {
+		r.l.Warn().Err(dropErr).Str("propID", objectID).Msg("failed to drop the schema revisions")
+	}
+}
+
+// ListHistory returns the kept versions of a schema object, the latest first.
+func (r *SchemaRegistry) ListHistory(ctx context.Context, kind schema.Kind, metadata *commonv1.Metadata) ([]*databasev1.SchemaRevision, error) {
+	props, listErr := r.listHistoryProperties(ctx, BuildPropertyID(kind, metadata))
+	if listErr != nil {
+		return nil, listErr
+	}
+	revisions := make([]*databasev1.SchemaRevision, 0, len(props))
+	for _, prop := range props {
+		rev := &databasev1.SchemaRevision{}
+		if unmarshalErr := protojson.Unmarshal([]byte(ParseTags(prop.GetTags()).Source), rev); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to unmarshal schema revision from property: %w", unmarshalErr)
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
@@ -47,6 +47,11 @@ type DataInfoCollector interface {
 	CollectDataInfo(ctx context.Context, group string) (*databasev1.DataInfo, error)
 }
 
+// DataScrubber provides methods to verify the data files on a data node.
+type DataScrubber interface {
+	ScrubGroup(ctx context.Context, group string) error
+}
+
 // LiaisonInfoCollector provides methods to collect liaison node info.
 type LiaisonInfoCollector interface {
 	CollectLiaisonInfo(ctx context.Context, group string) (*databasev1.LiaisonInfo, error)
@@ -79,6 +84,7 @@ type Registry interface {
 	Group
 	TopNAggregation
 	Property
+	History
 	RegisterHandler(string, Kind, EventHandler)
 	Start(context.Context) error
 }
@@ -28,10 +28,12 @@ import (
 
 	"github.com/apache/skywalking-banyandb/api/common"
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	measurev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/measure/v1"
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
 	streamv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/stream/v1"
 	tracev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/trace/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/measure"
 	"github.com/apache/skywalking-banyandb/banyand/stream"
 	"github.com/apache/skywalking-banyandb/banyand/trace"
@@ -45,6 +47,7 @@ import (
 	logical_stream "github.com/apache/skywalking-banyandb/pkg/query/logical/stream"
 	logical_trace "github.com/apache/skywalking-banyandb/pkg/query/logical/trace"
 	"github.com/apache/skywalking-banyandb/pkg/query/model"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
 )
 
 const (
@@ -80,6 +83,7 @@ func (p *streamQueryProcessor) Rev(ctx context.Context, message bus.Message) (re
 		if err := recover(); err != nil {
 			p.log.Error().Interface("err", err).RawJSON("req", logger.Proto(queryCriteria)).Str("stack", string(debug.Stack())).Msg("panic")
 			resp = bus.NewMessage(bus.MessageID(time.Now().UnixNano()), common.NewError("panic"))
+			reportReadFailure(p.streamService, queryCriteria.GetGroups())
 		}
 	}()
 	var metadata []*commonv1.Metadata
@@ -96,7 +100,7 @@ func (p *streamQueryProcessor) Rev(ctx context.Context, message bus.Message) (re
 			return
 		}
 		ecc = append(ecc, ec)
-		s, err := logical_stream.BuildSchema(ec.GetSchema(), ec.GetIndexRules())
+		s, err := logical_stream.BuildSchema(ec.GetSchema(), queryableIndexRules(ec, queryCriteria))
 		if err != nil {
 			resp = bus.NewMessage(bus.MessageID(now), common.NewError("fail to build schema for stream %s: %v", meta.GetName(), err))
 			return
@@ -140,6 +144,7 @@ func (p *streamQueryProcessor) Rev(ctx context.Context, message bus.Message) (re
 	defer se.Close()
 	entities, err := se.Execute(ctx)
 	if err != nil {
+		reportCorruptedRead(p.streamService, queryCriteria.GetGroups(), err)
 		p.log.Error().Err(err).RawJSON("req", logger.Proto(queryCriteria)).Msg("fail to execute the query plan")
 		resp = bus.NewMessage(bus.MessageID(now), common.NewError("execute the query plan for stream %s: %v", queryCriteria.GetName(), err))
 		return
@@ -156,6 +161,28 @@ func (p *streamQueryProcessor) Rev(ctx context.Context, message bus.Message) (re
 	return
 }
 
+// readFailureReporter is implemented by the services that scrub their groups after a failed read.
+type readFailureReporter interface {
+	ReportReadFailure(group string)
+}
+
+func reportReadFailure(service any, groups []string) {
+	r, ok := service.(readFailureReporter)
+	if !ok {
+		return
+	}
+	for _, g := range groups {
+		r.ReportReadFailure(g)
+	}
+}
+
+// reportCorruptedRead reports the read failure of the groups if err comes from a corrupted part.
+func reportCorruptedRead(service any, groups []string, err error) {
+	if errors.Is(err, storage.ErrPartCorrupted) {
+		reportReadFailure(service, groups)
+	}
+}
+
 type measureQueryProcessor struct {
 	measureService measure.Service
 	*queryService
@@ -267,6 +294,28 @@ func extractTagValuesFromDataPoint(dp *measurev1.DataPoint, groupByTags []string
 	}
 }
 
+// queryableIndexRules leaves out the index rules being built on the existing data of the queried time range,
+// so their conditions are evaluated by filtering the tags. The rule sorting the result is always kept.
+func queryableIndexRules(ec stream.Stream, queryCriteria *streamv1.QueryRequest) []*databasev1.IndexRule {
+	tr := queryCriteria.GetTimeRange()
+	rules := ec.GetQueryableIndexRules(timestamp.NewInclusiveTimeRange(tr.GetBegin().AsTime(), tr.GetEnd().AsTime()))
+	orderBy := queryCriteria.GetOrderBy().GetIndexRuleName()
+	if orderBy == "" {
+		return rules
+	}
+	for _, r := range rules {
+		if r.GetMetadata().GetName() == orderBy {
+			return rules
+		}
+	}
+	for _, r := range ec.GetIndexRules() {
+		if r.GetMetadata().GetName() == orderBy {
+			return append(rules, r)
+		}
+	}
+	return rules
+}
+
 // getGroupByTags extracts group by tag names from query criteria.
 func getGroupByTags(queryCriteria *measurev1.QueryRequest) []string {
 	groupByTags := make([]string, 0)
@@ -336,6 +385,7 @@ func (p *measureQueryProcessor) executeQuery(ctx context.Context, queryCriteria
 		if recoverErr := recover(); recoverErr != nil {
 			p.log.Error().Interface("err", recoverErr).RawJSON("req", logger.Proto(queryCriteria)).Str("stack", string(debug.Stack())).Msg("panic")
 			resp = bus.NewMessage(bus.MessageID(time.Now().UnixNano()), common.NewError("panic"))
+			reportReadFailure(p.measureService, queryCriteria.GetGroups())
 		}
 	}()
 
@@ -350,11 +400,13 @@ func (p *measureQueryProcessor) executeQuery(ctx context.Context, queryCriteria
 
 	mIterator, plan, execErr := executeMeasurePlan(ctx, queryCriteria, mctx, false)
 	if execErr != nil {
+		reportCorruptedRead(p.measureService, queryCriteria.GetGroups(), execErr)
 		resp = bus.NewMessage(bus.MessageID(now), common.NewError("%v", execErr))
 		return
 	}
 	defer func() {
 		if closeErr := mIterator.Close(); closeErr != nil {
+			reportCorruptedRead(p.measureService, queryCriteria.GetGroups(), closeErr)
 			mctx.ml.Error().Err(closeErr).Dur("latency", time.Since(n)).RawJSON("req", logger.Proto(queryCriteria)).Msg("fail to close the query plan")
 		}
 	}()
@@ -455,6 +507,7 @@ func (p *measureInternalQueryProcessor) Rev(ctx context.Context, message bus.Mes
 		if recoverErr := recover(); recoverErr != nil {
 			p.log.Error().Interface("err", recoverErr).RawJSON("req", logger.Proto(queryCriteria)).Str("stack", string(debug.Stack())).Msg("panic")
 			resp = bus.NewMessage(bus.MessageID(time.Now().UnixNano()), common.NewError("panic"))
+			reportReadFailure(p.measureService, queryCriteria.GetGroups())
 		}
 	}()
 
@@ -469,11 +522,13 @@ func (p *measureInternalQueryProcessor) Rev(ctx context.Context, message bus.Mes
 
 	mIterator, plan, execErr := executeMeasurePlan(ctx, queryCriteria, mctx, internalRequest.GetAggReturnPartial())
 	if execErr != nil {
+		reportCorruptedRead(p.measureService, queryCriteria.GetGroups(), execErr)
 		resp = bus.NewMessage(bus.MessageID(now), common.NewError("%v", execErr))
 		return
 	}
 	defer func() {
 		if closeErr := mIterator.Close(); closeErr != nil {
+			reportCorruptedRead(p.measureService, queryCriteria.GetGroups(), closeErr)
 			mctx.ml.Error().Err(closeErr).Dur("latency", time.Since(n)).RawJSON("req", logger.Proto(queryCriteria)).Msg("fail to close the query plan")
 		}
 	}()
@@ -514,10 +569,12 @@ func (p *measureInternalQueryProcessor) Rev(ctx context.Context, message bus.Mes
 			rewriteQueryCriteria := buildRewriteQueryCriteria(queryCriteria, rewrittenCriteria)
 			rewriteIterator, _, rewriteExecErr := executeMeasurePlan(ctx, rewriteQueryCriteria, mctx, false)
 			if rewriteExecErr != nil {
+				reportCorruptedRead(p.measureService, queryCriteria.GetGroups(), rewriteExecErr)
 				mctx.ml.Error().Err(rewriteExecErr).RawJSON("req", logger.Proto(rewriteQueryCriteria)).Msg("fail to execute the rewrite query plan")
 			} else {
 				defer func() {
 					if closeErr := rewriteIterator.Close(); closeErr != nil {
+						reportCorruptedRead(p.measureService, queryCriteria.GetGroups(), closeErr)
 						mctx.ml.Error().Err(closeErr).Msg("fail to close the rewrite query plan")
 					}
 				}()
@@ -886,6 +943,7 @@ func (p *traceQueryProcessor) executeQuery(ctx context.Context, queryCriteria *t
 		if err := recover(); err != nil {
 			p.log.Error().Interface("err", err).RawJSON("req", logger.Proto(queryCriteria)).Str("stack", string(debug.Stack())).Msg("panic")
 			resp = bus.NewMessage(bus.MessageID(time.Now().UnixNano()), common.NewError("panic"))
+			reportReadFailure(p.traceService, queryCriteria.GetGroups())
 		}
 	}()
 
@@ -918,6 +976,7 @@ func (p *traceQueryProcessor) executeQuery(ctx context.Context, queryCriteria *t
 	defer te.Close()
 	resultIterator, err := te.Execute(ctx)
 	if err != nil {
+		reportCorruptedRead(p.traceService, queryCriteria.GetGroups(), err)
 		p.log.Error().Err(err).RawJSON("req", logger.Proto(queryCriteria)).Msg("fail to execute the trace query plan")
 		resp = bus.NewMessage(bus.MessageID(now), common.NewError("execute the query plan for trace %s: %v", queryCriteria.GetName(), err))
 		return
@@ -925,6 +984,7 @@ func (p *traceQueryProcessor) executeQuery(ctx context.Context, queryCriteria *t
 
 	traces, err := p.processTraceResults(resultIterator, queryCriteria, execPlan)
 	if err != nil {
+		reportCorruptedRead(p.traceService, queryCriteria.GetGroups(), err)
 		p.log.Error().Err(err).RawJSON("req", logger.Proto(queryCriteria)).Msg("fail to process trace results")
 		resp = bus.NewMessage(bus.MessageID(now), common.NewError("process trace results for trace %s: %v", queryCriteria.GetName(), err))
 		return
@@ -0,0 +1,172 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package query
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/banyand/measure"
+	"github.com/apache/skywalking-banyandb/banyand/stream"
+	"github.com/apache/skywalking-banyandb/pkg/bus"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+var _ bus.MessageListener = (*seriesExplorerProcessor)(nil)
+
+// explorable is a stream or a measure whose series and tag values are explorable.
+type explorable interface {
+	TagValues(ctx context.Context, tagName string, timeRange timestamp.TimeRange, prefix string, limit int) ([]string, error)
+	ListSeries(ctx context.Context, timeRange timestamp.TimeRange, after []byte, limit int) (pbv1.SeriesList, bool, error)
+	SeriesCardinality(ctx context.Context, timeRange timestamp.TimeRange, tags []string, topN int) ([]*databasev1.SeriesCardinalityBucket, error)
+}
+
+type seriesExplorerProcessor struct {
+	streamService  stream.Service
+	measureService measure.Service
+	*queryService
+	*bus.UnImplementedHealthyListener
+}
+
+func (p *seriesExplorerProcessor) Rev(ctx context.Context, message bus.Message) (resp bus.Message) {
+	now := bus.MessageID(time.Now().UnixNano())
+	var data any
+	var err error
+	switch request := message.Data().(type) {
+	case *databasev1.SeriesExplorerServiceTagValuesRequest:
+		p.logRequest(request.GetGroup(), request.GetName(), "tag values")
+		data, err = p.tagValues(ctx, request)
+	case *databasev1.SeriesExplorerServiceListSeriesRequest:
+		p.logRequest(request.GetGroup(), request.GetName(), "list series")
+		data, err = p.listSeries(ctx, request)
+	case *databasev1.SeriesExplorerServiceSeriesCardinalityRequest:
+		p.logRequest(request.GetGroup(), request.GetName(), "series cardinality")
+		data, err = p.seriesCardinality(ctx, request)
+	default:
+		return bus.NewMessage(now, common.NewError("invalid event data type"))
+	}
+	if err != nil {
+		return bus.NewMessage(now, common.NewError("fail to explore the series: %v", err))
+	}
+	return bus.NewMessage(now, data)
+}
+
+func (p *seriesExplorerProcessor) logRequest(group, name, kind string) {
+	if e := p.log.Debug(); e.Enabled() {
+		e.Str("group", group).Str("name", name).Msgf("received a %s request", kind)
+	}
+}
+
+func (p *seriesExplorerProcessor) tagValues(ctx context.Context, request *databasev1.SeriesExplorerServiceTagValuesRequest) (
+	*databasev1.SeriesExplorerServiceTagValuesResponse, error,
+) {
+	r, _, err := p.loadResource(request.GetGroup(), request.GetName())
+	if err != nil {
+		return nil, err
+	}
+	values, err := r.TagValues(ctx, request.GetTag(), toTimeRange(request.GetTimeRange()), request.GetPrefix(), int(request.GetLimit()))
+	if err != nil {
+		return nil, fmt.Errorf("fail to list the values of the tag %s: %w", request.GetTag(), err)
+	}
+	return &databasev1.SeriesExplorerServiceTagValuesResponse{Values: values}, nil
+}
+
+func (p *seriesExplorerProcessor) listSeries(ctx context.Context, request *databasev1.SeriesExplorerServiceListSeriesRequest) (
+	*databasev1.SeriesExplorerServiceListSeriesResponse, error,
+) {
+	r, entity, err := p.loadResource(request.GetGroup(), request.GetName())
+	if err != nil {
+		return nil, err
+	}
+	after, err := base64.RawURLEncoding.DecodeString(request.GetPageToken())
+	if err != nil {
+		return nil, fmt.Errorf("invalid page token: %w", err)
+	}
+	seriesList, more, err := r.ListSeries(ctx, toTimeRange(request.GetTimeRange()), after, int(request.GetPageSize()))
+	if err != nil {
+		return nil, err
+	}
+	resp := &databasev1.SeriesExplorerServiceListSeriesResponse{
+		Series: make([]*databasev1.SeriesEntity, 0, len(seriesList)),
+	}
+	for _, s := range seriesList {
+		resp.Series = append(resp.Series, ToSeriesEntity(entity, s))
+	}
+	if more && len(seriesList) > 0 {
+		resp.NextPageToken = base64.RawURLEncoding.EncodeToString(seriesList[len(seriesList)-1].Buffer)
+	}
+	return resp, nil
+}
+
+func (p *seriesExplorerProcessor) seriesCardinality(ctx context.Context, request *databasev1.SeriesExplorerServiceSeriesCardinalityRequest) (
+	*databasev1.SeriesExplorerServiceSeriesCardinalityResponse, error,
+) {
+	r, _, err := p.loadResource(request.GetGroup(), request.GetName())
+	if err != nil {
+		return nil, err
+	}
+	buckets, err := r.SeriesCardinality(ctx, toTimeRange(request.GetTimeRange()), request.GetTags(), int(request.GetTopN()))
+	if err != nil {
+		return nil, err
+	}
+	return &databasev1.SeriesExplorerServiceSeriesCardinalityResponse{Buckets: buckets}, nil
+}
+
+// loadResource returns the stream or the measure, and its entity tag names.
+func (p *seriesExplorerProcessor) loadResource(group, name string) (explorable, []string, error) {
+	meta := &commonv1.Metadata{Name: name, Group: group}
+	if _, ok := p.streamService.LoadGroup(group); ok {
+		s, err := p.streamService.Stream(meta)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s.GetSchema().GetEntity().GetTagNames(), nil
+	}
+	if _, ok := p.measureService.LoadGroup(group); ok {
+		m, err := p.measureService.Measure(meta)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m, m.GetSchema().GetEntity().GetTagNames(), nil
+	}
+	return nil, nil, fmt.Errorf("group %s is not a stream or measure group", group)
+}
+
+func toTimeRange(tr *modelv1.TimeRange) timestamp.TimeRange {
+	return timestamp.NewInclusiveTimeRange(tr.GetBegin().AsTime(), tr.GetEnd().AsTime())
+}
+
+// ToSeriesEntity converts a series to the entity tags and their values.
+func ToSeriesEntity(entity []string, s *pbv1.Series) *databasev1.SeriesEntity {
+	se := &databasev1.SeriesEntity{Entity: make([]*modelv1.Tag, 0, len(s.EntityValues))}
+	for i, v := range s.EntityValues {
+		if i >= len(entity) {
+			break
+		}
+		se.Entity = append(se.Entity, &modelv1.Tag{Key: entity[i], Value: v})
+	}
+	return se
+}
@@ -45,6 +45,7 @@ type queryService struct {
 	imqp        *measureInternalQueryProcessor
 	nqp         *topNQueryProcessor
 	tqp         *traceQueryProcessor
+	sep         *seriesExplorerProcessor
 	nodeID      string
 	slowQuery   time.Duration
 }
@@ -82,6 +83,12 @@ func NewService(_ context.Context, streamService stream.Service, measureService
 		traceService: traceService,
 		queryService: svc,
 	}
+	// series explorer processor
+	svc.sep = &seriesExplorerProcessor{
+		streamService:  streamService,
+		measureService: measureService,
+		queryService:   svc,
+	}
 	return svc, nil
 }
 
@@ -103,6 +110,9 @@ func (q *queryService) PreRun(ctx context.Context) error {
 		q.pipeline.Subscribe(data.TopicInternalMeasureQuery, q.imqp),
 		q.pipeline.Subscribe(data.TopicTopNQuery, q.nqp),
 		q.pipeline.Subscribe(data.TopicTraceQuery, q.tqp),
+		q.pipeline.Subscribe(data.TopicTagValues, q.sep),
+		q.pipeline.Subscribe(data.TopicListSeries, q.sep),
+		q.pipeline.Subscribe(data.TopicSeriesCardinality, q.sep),
 	)
 }
 
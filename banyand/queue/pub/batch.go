@@ -196,6 +196,9 @@ func (bp *batchPublisher) Publish(ctx context.Context, topic bus.Topic, messages
 				bp.pub.connMgr.RecordFailure(curNode, ce)
 				bc <- batchEvent{n: curNode, e: ce}
 			}
+			if isRejectionStatus(resp.Status) || isDegradedStatus(resp.Status) {
+				bc <- batchEvent{n: curNode, e: common.NewErrorWithMessageStatuses(resp.Status, resp.Error, resp.MessageStatuses)}
+			}
 		}(stream, deferFn, bp.f.events[len(bp.f.events)-1], nodeName)
 	}
 	return nil, err
@@ -216,6 +219,9 @@ func (bp *batchPublisher) Close() (cee map[string]*common.Error, err error) {
 		go func() {
 			defer bp.pub.closer.Done()
 			for n, e := range batchEvents {
+				if isRejectionStatus(e.e.Status()) || isDegradedStatus(e.e.Status()) {
+					continue
+				}
 				// Record circuit breaker failure before failover
 				bp.pub.connMgr.RecordFailure(n, e.e)
 				if bp.topic == nil {
@@ -279,6 +285,17 @@ func isFailoverStatus(s modelv1.Status) bool {
 	return s == modelv1.Status_STATUS_DISK_FULL
 }
 
+// isRejectionStatus returns whether the node rejects the data while keeping healthy, so the node should not be failed over.
+func isRejectionStatus(s modelv1.Status) bool {
+	return s == modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED
+}
+
+// isDegradedStatus returns whether the node queues the data but fewer replicas than required persist it.
+// The status is reported to the client, and the node should not be failed over.
+func isDegradedStatus(s modelv1.Status) bool {
+	return s == modelv1.Status_STATUS_WRITE_DEGRADED
+}
+
 // retrySend implements bounded retries for client streaming sends with exponential backoff and jitter.
 func (bp *batchPublisher) retrySend(ctx context.Context, stream clusterv1.Service_SendClient, r *clusterv1.SendRequest, node string) error {
 	var lastErr error
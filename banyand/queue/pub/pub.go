@@ -469,6 +469,9 @@ func (l *future) Get() (bus.Message, error) {
 		return bus.Message{}, err
 	}
 	if resp.Error != "" {
+		if resp.Status != modelv1.Status_STATUS_UNSPECIFIED {
+			return bus.Message{}, common.NewErrorWithStatus(resp.Status, resp.Error)
+		}
 		return bus.Message{}, errors.New(resp.Error)
 	}
 	if resp.Body == nil {
@@ -53,9 +53,10 @@ func (s *server) handleEOF(stream clusterv1.Service_SendServer, topic *bus.Topic
 		switch d := data.(type) {
 		case *common.Error:
 			resp = &clusterv1.SendResponse{
-				MessageId: writeEntity.MessageId,
-				Error:     d.Error(),
-				Status:    d.Status(),
+				MessageId:       writeEntity.MessageId,
+				Error:           d.Error(),
+				Status:          d.Status(),
+				MessageStatuses: d.MessageStatuses(),
 			}
 		default:
 			resp = &clusterv1.SendResponse{
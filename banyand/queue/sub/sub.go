@@ -214,7 +214,7 @@ func (s *server) Send(stream clusterv1.Service_SendServer) error {
 				return ctx.Err()
 			default:
 			}
-			s.reply(stream, writeEntity, nil, d.Error())
+			s.reply(stream, writeEntity, d, d.Error())
 			continue
 		default:
 			s.reply(stream, writeEntity, nil, fmt.Sprintf("invalid response: %T", d))
@@ -270,13 +270,11 @@ func (s *server) reply(stream clusterv1.Service_SendServer, writeEntity *cluster
 	if errors.As(err, &ce) {
 		resp.Error = ce.Error()
 		resp.Status = ce.Status()
+		resp.MessageStatuses = ce.MessageStatuses()
 	} else {
 		resp.Error = message
 	}
-	if errResp := stream.Send(&clusterv1.SendResponse{
-		MessageId: writeEntity.MessageId,
-		Error:     message,
-	}); errResp != nil {
+	if errResp := stream.Send(resp); errResp != nil {
 		s.log.Error().Err(errResp).AnErr("original", err).Stringer("request", writeEntity).Msg("failed to send error response")
 		s.metrics.totalMsgSentErr.Inc(1, writeEntity.Topic)
 	}
@@ -0,0 +1,181 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+// antiEntropyDigest returns the digest of the rows of the table in the segment.
+// The digests of the parts are cached, since the parts are immutable.
+func (tst *tsTable) antiEntropyDigest(timeRange timestamp.TimeRange) (*storage.AntiEntropyDigest, error) {
+	digest := &storage.AntiEntropyDigest{}
+	snp := tst.currentSnapshot()
+	if snp == nil {
+		return digest, nil
+	}
+	defer snp.decRef()
+	tst.partDigestsMu.Lock()
+	defer tst.partDigestsMu.Unlock()
+	digests := make(map[uint64]*storage.AntiEntropyDigest, len(snp.parts))
+	for _, pw := range snp.parts {
+		d, ok := tst.partDigests[pw.ID()]
+		if !ok {
+			d = &storage.AntiEntropyDigest{}
+			if err := visitAntiEntropyRows(pw.p, timeRange, nil, func(bucket int, hash uint64) {
+				d.Add(bucket, hash)
+			}); err != nil {
+				return nil, fmt.Errorf("cannot compute the digest of %s: %w", pw.p, err)
+			}
+		}
+		digests[pw.ID()] = d
+		digest.Merge(d)
+	}
+	tst.partDigests = digests
+	return digest, nil
+}
+
+// antiEntropyRowHashes returns the hashes of the rows of the table in the buckets, leaving out the rows of the excluded parts.
+func (tst *tsTable) antiEntropyRowHashes(timeRange timestamp.TimeRange, set *storage.AntiEntropyBucketSet, excludedParts map[uint64]struct{}) (
+	[]uint64, error,
+) {
+	snp := tst.currentSnapshot()
+	if snp == nil {
+		return nil, nil
+	}
+	defer snp.decRef()
+	var hashes []uint64
+	for _, pw := range snp.parts {
+		if _, ok := excludedParts[pw.ID()]; ok {
+			continue
+		}
+		if err := visitAntiEntropyRows(pw.p, timeRange, set, func(_ int, hash uint64) {
+			hashes = append(hashes, hash)
+		}); err != nil {
+			return nil, fmt.Errorf("cannot hash the rows of %s: %w", pw.p, err)
+		}
+	}
+	return hashes, nil
+}
+
+// visitAntiEntropyRows visits the bucket and the hash of every row of the part in the buckets of the set.
+// All the buckets are visited if the set is nil.
+func visitAntiEntropyRows(p *part, timeRange timestamp.TimeRange, set *storage.AntiEntropyBucketSet, visit func(bucket int, hash uint64)) error {
+	if p.partMetadata.TotalCount == 0 {
+		return nil
+	}
+	start, end := timeRange.Start.UnixNano(), timeRange.End.UnixNano()
+	pmi := generatePartMergeIter()
+	defer releasePartMergeIter(pmi)
+	pmi.mustInitFromPart(p)
+	var timestamps []int64
+	var elementIDs []uint64
+	for pmi.nextBlockMetadata() {
+		bm := &pmi.block.bm
+		if bm.timestamps.max < start || bm.timestamps.min >= end {
+			continue
+		}
+		if set != nil && !set.Overlaps(timeRange, bm.seriesID, bm.timestamps.min, bm.timestamps.max) {
+			continue
+		}
+		timestamps, elementIDs = mustReadTimestampsFrom(timestamps[:0], elementIDs[:0], &bm.timestamps, int(bm.count), p.timestamps)
+		for i := range timestamps {
+			bucket := storage.AntiEntropyBucket(timeRange, bm.seriesID, timestamps[i])
+			if bucket < 0 || (set != nil && !set[bucket]) {
+				continue
+			}
+			visit(bucket, storage.AntiEntropyRowHash(bm.seriesID, timestamps[i], elementIDs[i]))
+		}
+	}
+	return pmi.error()
+}
+
+// buildAntiEntropyPart collects the rows of the parts in the buckets whose hashes are unknown into a memory part.
+// It returns a nil part if no row is missing.
+func buildAntiEntropyPart(parts []*part, timeRange timestamp.TimeRange, set *storage.AntiEntropyBucketSet, known map[uint64]struct{}) (
+	*memPart, map[common.SeriesID]struct{}, error,
+) {
+	es := generateElements()
+	defer releaseElements(es)
+	seriesIDs := make(map[common.SeriesID]struct{})
+	decoder := generateColumnValuesDecoder()
+	defer releaseColumnValuesDecoder(decoder)
+	b := generateBlock()
+	defer releaseBlock(b)
+	pmi := generatePartMergeIter()
+	defer releasePartMergeIter(pmi)
+	for _, p := range parts {
+		if !set.OverlapsTime(timeRange, p.partMetadata.MinTimestamp, p.partMetadata.MaxTimestamp) {
+			continue
+		}
+		pmi.mustInitFromPart(p)
+		for pmi.nextBlockMetadata() {
+			bm := &pmi.block.bm
+			// The sequential readers require every block to be read.
+			// The rows are copied, so the buffer of the decoder is reused by every block.
+			decoder.Reset()
+			b.mustSeqReadFrom(decoder, &pmi.seqReaders, *bm)
+			if !set.Overlaps(timeRange, bm.seriesID, bm.timestamps.min, bm.timestamps.max) {
+				continue
+			}
+			for i := range b.timestamps {
+				if !set.Contains(timeRange, bm.seriesID, b.timestamps[i]) {
+					continue
+				}
+				hash := storage.AntiEntropyRowHash(bm.seriesID, b.timestamps[i], b.elementIDs[i])
+				if _, ok := known[hash]; ok {
+					continue
+				}
+				// The same row in several parts is pushed once.
+				known[hash] = struct{}{}
+				appendAntiEntropyRow(es, bm.seriesID, b, i)
+				seriesIDs[bm.seriesID] = struct{}{}
+			}
+		}
+		if err := pmi.error(); err != nil {
+			return nil, nil, fmt.Errorf("cannot read the rows of %s: %w", p, err)
+		}
+	}
+	if len(es.timestamps) == 0 {
+		return nil, nil, nil
+	}
+	mp := generateMemPart()
+	mp.mustInitFromElements(es)
+	return mp, seriesIDs, nil
+}
+
+// appendAntiEntropyRow copies the i-th element of the block, which refers to the buffer of the decoder, to the elements.
+func appendAntiEntropyRow(es *elements, sid common.SeriesID, b *block, i int) {
+	es.seriesIDs = append(es.seriesIDs, sid)
+	es.timestamps = append(es.timestamps, b.timestamps[i])
+	es.elementIDs = append(es.elementIDs, b.elementIDs[i])
+	tagFamilies := make([]tagValues, len(b.tagFamilies))
+	for j := range b.tagFamilies {
+		tagFamilies[j].tag = b.tagFamilies[j].name
+		for k := range b.tagFamilies[j].tags {
+			t := &b.tagFamilies[j].tags[k]
+			tagFamilies[j].values = append(tagFamilies[j].values, &tagValue{tag: t.name, value: bytes.Clone(t.values[i]), valueType: t.valueType})
+		}
+	}
+	es.tagFamilies = append(es.tagFamilies, tagFamilies)
+}
@@ -0,0 +1,125 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+func TestBuildAntiEntropyPart(t *testing.T) {
+	timeRange := timestamp.NewSectionTimeRange(time.Unix(0, 0), time.Unix(0, 16))
+	all := allAntiEntropyBuckets()
+	p1, p2 := newAntiEntropyTestPart(t, esTS1), newAntiEntropyTestPart(t, esTS2)
+	assert.NotEqual(t, antiEntropyTestDigest(t, timeRange, p1), antiEntropyTestDigest(t, timeRange, p1, p2))
+
+	// The replica holding both parts pushes the rows missing from the one holding the first part.
+	mp, seriesIDs, err := buildAntiEntropyPart([]*part{p1, p2}, timeRange, all, antiEntropyTestHashes(t, timeRange, all, p1))
+	require.NoError(t, err)
+	require.NotNil(t, mp)
+	defer releaseMemPart(mp)
+	assert.Equal(t, uint64(3), mp.partMetadata.TotalCount)
+	assert.Len(t, seriesIDs, 3)
+	assert.Equal(t, antiEntropyTestDigest(t, timeRange, p1, p2), antiEntropyTestDigest(t, timeRange, p1, openMemPart(mp)))
+	assert.Equal(t, p2.partMetadata.MinTimestamp, mp.partMetadata.MinTimestamp)
+
+	// Only the rows in the requested buckets are pushed.
+	set := storage.NewAntiEntropyBucketSet([]uint32{uint32(storage.AntiEntropyBucket(timeRange, 1, 2))})
+	mp1, seriesIDs, err := buildAntiEntropyPart([]*part{p1, p2}, timeRange, set, antiEntropyTestHashes(t, timeRange, all, p1))
+	require.NoError(t, err)
+	require.NotNil(t, mp1)
+	defer releaseMemPart(mp1)
+	assert.Equal(t, uint64(1), mp1.partMetadata.TotalCount)
+	assert.Len(t, seriesIDs, 1)
+
+	// No row is missing.
+	mp, _, err = buildAntiEntropyPart([]*part{p1, p2}, timeRange, all, antiEntropyTestHashes(t, timeRange, all, p1, p2))
+	require.NoError(t, err)
+	assert.Nil(t, mp)
+}
+
+func TestTSTableAntiEntropy(t *testing.T) {
+	timeRange := timestamp.NewSectionTimeRange(time.Unix(0, 0), time.Unix(0, 16))
+	all := allAntiEntropyBuckets()
+	p1, p2 := newAntiEntropyTestPart(t, esTS1), newAntiEntropyTestPart(t, esTS2)
+	p1.partMetadata.ID, p2.partMetadata.ID = 1, 2
+	tst := &tsTable{snapshot: &snapshot{parts: []*partWrapper{newPartWrapper(nil, p1), newPartWrapper(nil, p2)}, ref: 1}}
+
+	digest, err := tst.antiEntropyDigest(timeRange)
+	require.NoError(t, err)
+	assert.Equal(t, antiEntropyTestDigest(t, timeRange, p1, p2), digest)
+	assert.Len(t, tst.partDigests, 2)
+
+	// The cached digests of the parts give the same digest.
+	cached, err := tst.antiEntropyDigest(timeRange)
+	require.NoError(t, err)
+	assert.Equal(t, digest, cached)
+
+	// The rows of the excluded parts are left out of the hashes.
+	hashes, err := tst.antiEntropyRowHashes(timeRange, all, map[uint64]struct{}{2: {}})
+	require.NoError(t, err)
+	assert.Len(t, hashes, len(antiEntropyTestHashes(t, timeRange, all, p1)))
+	for _, h := range hashes {
+		assert.Contains(t, antiEntropyTestHashes(t, timeRange, all, p1), h)
+	}
+
+	// Only the rows in the requested buckets are hashed.
+	set := storage.NewAntiEntropyBucketSet([]uint32{uint32(storage.AntiEntropyBucket(timeRange, 1, 2))})
+	hashes, err = tst.antiEntropyRowHashes(timeRange, set, nil)
+	require.NoError(t, err)
+	assert.Len(t, hashes, len(antiEntropyTestHashes(t, timeRange, set, p1, p2)))
+}
+
+func allAntiEntropyBuckets() *storage.AntiEntropyBucketSet {
+	all := &storage.AntiEntropyBucketSet{}
+	for i := range all {
+		all[i] = true
+	}
+	return all
+}
+
+func newAntiEntropyTestPart(t *testing.T, es *elements) *part {
+	mp := generateMemPart()
+	mp.mustInitFromElements(es)
+	t.Cleanup(func() { releaseMemPart(mp) })
+	return openMemPart(mp)
+}
+
+func antiEntropyTestDigest(t *testing.T, timeRange timestamp.TimeRange, parts ...*part) *storage.AntiEntropyDigest {
+	d := &storage.AntiEntropyDigest{}
+	for _, p := range parts {
+		require.NoError(t, visitAntiEntropyRows(p, timeRange, nil, d.Add))
+	}
+	return d
+}
+
+func antiEntropyTestHashes(t *testing.T, timeRange timestamp.TimeRange, set *storage.AntiEntropyBucketSet, parts ...*part) map[uint64]struct{} {
+	known := make(map[uint64]struct{})
+	for _, p := range parts {
+		require.NoError(t, visitAntiEntropyRows(p, timeRange, set, func(_ int, hash uint64) {
+			known[hash] = struct{}{}
+		}))
+	}
+	return known
+}
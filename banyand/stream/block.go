@@ -18,7 +18,6 @@
 package stream
 
 import (
-	"bytes"
 	"fmt"
 	"sort"
 
@@ -120,18 +119,6 @@ func (b *block) processTags(tf tagValues, tagFamilyIdx, i int, elementsLen int)
 		} else if t.value != nil {
 			tags[j].uniqueValues[convert.BytesToString(t.value)] = struct{}{}
 		}
-		if t.valueType == pbv1.ValueTypeInt64 {
-			if len(tags[j].min) == 0 {
-				tags[j].min = t.value
-			} else if bytes.Compare(t.value, tags[j].min) == -1 {
-				tags[j].min = t.value
-			}
-			if len(tags[j].max) == 0 {
-				tags[j].max = t.value
-			} else if bytes.Compare(t.value, tags[j].max) == 1 {
-				tags[j].max = t.value
-			}
-		}
 	}
 }
 
@@ -205,11 +205,58 @@ type elementsInTable struct {
 	segment    storage.Segment[*tsTable, option]
 	tsTable    *tsTable
 	elements   *elements
+	// messageIDs are the write messages of each series, which a liaison reports if the series is rejected.
+	messageIDs map[common.SeriesID][]uint64
 	timeRange  timestamp.TimeRange
 	docs       index.Documents
 	shardID    common.ShardID
 }
 
+func (et *elementsInTable) addMessage(sid common.SeriesID, messageID uint64) {
+	if et.messageIDs == nil {
+		et.messageIDs = make(map[common.SeriesID][]uint64)
+	}
+	et.messageIDs[sid] = append(et.messageIDs[sid], messageID)
+}
+
+// messages returns the write messages of all the series.
+func (et *elementsInTable) messages() []uint64 {
+	var ids []uint64
+	for _, sidIDs := range et.messageIDs {
+		ids = append(ids, sidIDs...)
+	}
+	return ids
+}
+
+// dropSeries removes the elements, the element documents and the series documents of the rejected series.
+func (et *elementsInTable) dropSeries(rejected map[common.SeriesID]struct{}) {
+	e := et.elements
+	n := 0
+	for i := range e.seriesIDs {
+		if _, ok := rejected[e.seriesIDs[i]]; ok {
+			continue
+		}
+		if n != i {
+			e.Swap(n, i)
+			if i < len(et.docs) {
+				et.docs[n] = et.docs[i]
+			}
+		}
+		n++
+	}
+	e.seriesIDs = e.seriesIDs[:n]
+	e.timestamps = e.timestamps[:n]
+	e.elementIDs = e.elementIDs[:n]
+	e.tagFamilies = e.tagFamilies[:n]
+	if n < len(et.docs) {
+		et.docs = et.docs[:n]
+	}
+	et.seriesDocs.docs = storage.DropRejectedDocuments(et.seriesDocs.docs, rejected)
+	for id := range rejected {
+		delete(et.seriesDocs.docIDsAdded, uint64(id))
+	}
+}
+
 type elementsInGroup struct {
 	tsdb     storage.TSDB[*tsTable, option]
 	tables   []*elementsInTable
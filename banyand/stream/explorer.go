@@ -0,0 +1,123 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pkg/errors"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+func (s *stream) TagValues(ctx context.Context, tagName string, timeRange timestamp.TimeRange, prefix string, limit int) ([]string, error) {
+	entity := s.schema.GetEntity().GetTagNames()
+	entityPos := slices.Index(entity, tagName)
+	var rule *databasev1.IndexRule
+	if entityPos < 0 {
+		for _, r := range s.GetQueryableIndexRules(timeRange) {
+			if isElementIndexRule(r) && slices.Contains(r.GetTags(), tagName) {
+				rule = r
+				break
+			}
+		}
+		if rule == nil {
+			return nil, errors.Errorf("tag %s is neither an entity tag nor indexed by an inverted or tree index rule", tagName)
+		}
+		// Int values are indexed as the prefix-coded numbers, which are not decodable from the term dictionary.
+		if t := s.indexSchema.Load().(indexSchema).tagMap[tagName]; t.GetType() != databasev1.TagType_TAG_TYPE_STRING {
+			return nil, errors.Errorf("the values of the indexed tag %s with type %s are not listable", tagName, t.GetType())
+		}
+	}
+	tsdb, err := s.getTSDB()
+	if err != nil {
+		return nil, err
+	}
+	segments, err := tsdb.SelectSegments(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, segment := range segments {
+			segment.DecRef()
+		}
+	}()
+	values := make(storage.TagValueSet)
+	for _, segment := range segments {
+		if entityPos >= 0 {
+			if err = values.CollectEntityTagValues(ctx, segment.IndexDB(), s.name, len(entity), entityPos, prefix); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		tables, _ := segment.Tables()
+		for _, tst := range tables {
+			if tst.index == nil {
+				continue
+			}
+			if err = values.CollectTerms(tst.index.Terms, index.FieldKey{IndexRuleID: rule.GetMetadata().GetId()},
+				[]byte(prefix), storage.StringTerm); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return values.Sorted(limit), nil
+}
+
+func (s *stream) ListSeries(ctx context.Context, timeRange timestamp.TimeRange, after []byte, limit int) (pbv1.SeriesList, bool, error) {
+	tsdb, err := s.getTSDB()
+	if err != nil {
+		return nil, false, err
+	}
+	segments, err := tsdb.SelectSegments(timeRange)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		for _, segment := range segments {
+			segment.DecRef()
+		}
+	}()
+	return storage.ListSeries(ctx, segments, s.name, len(s.schema.GetEntity().GetTagNames()), after, limit)
+}
+
+func (s *stream) SeriesCardinality(ctx context.Context, timeRange timestamp.TimeRange, tags []string, topN int) ([]*databasev1.SeriesCardinalityBucket, error) {
+	entity := s.schema.GetEntity().GetTagNames()
+	if len(tags) == 0 {
+		tags = entity
+	}
+	tsdb, err := s.getTSDB()
+	if err != nil {
+		return nil, err
+	}
+	segments, err := tsdb.SelectSegments(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, segment := range segments {
+			segment.DecRef()
+		}
+	}()
+	return storage.SeriesCardinality(ctx, segments, s.name, entity, tags, topN)
+}
@@ -0,0 +1,50 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandoffCodec_StreamingFileName(t *testing.T) {
+	tests := []struct {
+		fileName string
+		expected string
+		include  bool
+	}{
+		{primaryFilename, streamPrimaryName, true},
+		{metaFilename, streamMetaName, true},
+		{timestampsFilename, streamTimestampsName, true},
+		{"default" + tagFamiliesFilenameExt, streamTagFamiliesPrefix + "default", true},
+		{"default" + tagFamiliesMetadataFilenameExt, streamTagMetadataPrefix + "default", true},
+		{"default" + tagFamiliesFilterFilenameExt, streamTagFilterPrefix + "default", true},
+		{metadataFilename, "", false},
+		{elementIndexFilename, "", false},
+		{seriesMetadataFilename, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			name, include := handoffCodec{}.StreamingFileName(PartTypeCore, tt.fileName)
+			assert.Equal(t, tt.include, include)
+			assert.Equal(t, tt.expected, name)
+		})
+	}
+}
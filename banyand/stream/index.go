@@ -73,6 +73,14 @@ func (e *elementIndex) Write(docs index.Documents) error {
 	})
 }
 
+// Update replaces the documents of the elements, which is used to build new index rules on the existing elements.
+func (e *elementIndex) Update(docs index.Documents) error {
+	return e.store.Batch(index.Batch{
+		Documents: docs,
+		Update:    true,
+	})
+}
+
 func (e *elementIndex) Search(ctx context.Context, seriesList []uint64, filter index.Filter, tr *index.RangeOpts) (posting.List, posting.List, error) {
 	var result, resultTS posting.List
 	for i, id := range seriesList {
@@ -112,6 +120,14 @@ func (e *elementIndex) EnableExternalSegments() (index.ExternalSegmentStreamer,
 	return e.store.EnableExternalSegments()
 }
 
+func (e *elementIndex) FieldSize(fieldKey index.FieldKey) int64 {
+	return e.store.FieldSize(fieldKey)
+}
+
+func (e *elementIndex) Terms(fieldKey index.FieldKey, prefix []byte) ([][]byte, error) {
+	return e.store.Terms(fieldKey, prefix)
+}
+
 func (e *elementIndex) Close() error {
 	return e.store.Close()
 }
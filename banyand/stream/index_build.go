@@ -0,0 +1,355 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+	"github.com/apache/skywalking-banyandb/pkg/run"
+	resourceSchema "github.com/apache/skywalking-banyandb/pkg/schema"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const (
+	indexBuildBatchSize     = 1024
+	indexBuildRetryInterval = time.Minute
+)
+
+func elementIndexRuleIDs(rules []*databasev1.IndexRule) map[uint32]struct{} {
+	return storage.IndexRuleIDs(rules, isElementIndexRule)
+}
+
+type indexBuildTask struct {
+	metadata     *commonv1.Metadata
+	segmentStart time.Time
+	shardID      common.ShardID
+}
+
+func (t indexBuildTask) key() string {
+	return fmt.Sprintf("%s/%s/%d/%d", t.metadata.GetGroup(), t.metadata.GetName(), t.segmentStart.UnixNano(), t.shardID)
+}
+
+// indexBuilder builds the newly bound inverted and tree index rules on the data written before.
+// It scans the parts of every segment and shard, and rewrites the element index documents
+// with the fields of all the element index rules of the stream.
+type indexBuilder struct {
+	l       *logger.Logger
+	repo    resourceSchema.Repository
+	closer  *run.Closer
+	signal  chan struct{}
+	queued  map[string]struct{}
+	tracker *storage.IndexRuleTracker
+	pending []indexBuildTask
+	mu      sync.Mutex
+}
+
+func newIndexBuilder(l *logger.Logger, repo resourceSchema.Repository) *indexBuilder {
+	ib := &indexBuilder{
+		l:       l.Named("index-builder"),
+		repo:    repo,
+		closer:  run.NewCloser(1),
+		signal:  make(chan struct{}, 1),
+		queued:  make(map[string]struct{}),
+		tracker: storage.NewIndexRuleTracker(),
+	}
+	go ib.run()
+	return ib
+}
+
+// schedule finds the shards in which the element index rules of the stream are not built, and queues them.
+func (ib *indexBuilder) schedule(s *stream, rules []*databasev1.IndexRule) {
+	current := elementIndexRuleIDs(rules)
+	key := s.group + "/" + s.name
+	added := ib.tracker.Added(key, current)
+	tsdb, err := s.getTSDB()
+	if err != nil {
+		ib.l.Debug().Err(err).Str("stream", key).Msg("skip building indices before the group is loaded")
+		return
+	}
+	segments, err := tsdb.SelectSegments(timestamp.TimeRange{
+		Start: time.Unix(0, 0),
+		End:   time.Unix(0, timestamp.MaxNanoTime),
+	})
+	if err != nil {
+		ib.l.Error().Err(err).Str("stream", key).Msg("cannot select segments to build indices")
+		return
+	}
+	for _, segment := range segments {
+		tables, shardIDs, _ := segment.TablesWithShardIDs()
+		for i, tst := range tables {
+			missing := tst.buildState.Sync(s.name, current, added)
+			if len(missing) == 0 {
+				continue
+			}
+			tst.buildState.StartBuilding(s.name, storage.IndexRuleNames(rules, missing))
+			ib.enqueue(indexBuildTask{
+				metadata:     s.schema.GetMetadata(),
+				segmentStart: segment.GetTimeRange().Start,
+				shardID:      shardIDs[i],
+			})
+		}
+		segment.DecRef()
+	}
+}
+
+func (ib *indexBuilder) enqueue(task indexBuildTask) {
+	ib.mu.Lock()
+	if _, ok := ib.queued[task.key()]; ok {
+		ib.mu.Unlock()
+		return
+	}
+	ib.queued[task.key()] = struct{}{}
+	ib.pending = append(ib.pending, task)
+	ib.mu.Unlock()
+	select {
+	case ib.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (ib *indexBuilder) next() (indexBuildTask, bool) {
+	ib.mu.Lock()
+	defer ib.mu.Unlock()
+	if len(ib.pending) == 0 {
+		return indexBuildTask{}, false
+	}
+	task := ib.pending[0]
+	ib.pending = ib.pending[1:]
+	delete(ib.queued, task.key())
+	return task, true
+}
+
+func (ib *indexBuilder) run() {
+	defer ib.closer.Done()
+	for {
+		select {
+		case <-ib.closer.CloseNotify():
+			return
+		case <-ib.signal:
+		}
+		for {
+			task, ok := ib.next()
+			if !ok {
+				break
+			}
+			if err := ib.build(task); err != nil {
+				if errors.Is(err, errClosed) {
+					return
+				}
+				ib.l.Error().Err(err).Str("task", task.key()).Msg("cannot build indices, retry later")
+				time.AfterFunc(indexBuildRetryInterval, func() {
+					if !ib.closer.Closed() {
+						ib.enqueue(task)
+					}
+				})
+			}
+		}
+	}
+}
+
+func (ib *indexBuilder) close() {
+	ib.closer.CloseThenWait()
+}
+
+func (ib *indexBuilder) build(task indexBuildTask) error {
+	r, ok := ib.repo.LoadResource(task.metadata)
+	if !ok {
+		return nil
+	}
+	s, ok := r.Delegated().(*stream)
+	if !ok {
+		return nil
+	}
+	tsdb, err := s.getTSDB()
+	if err != nil {
+		return err
+	}
+	segments, err := tsdb.SelectSegments(timestamp.NewInclusiveTimeRange(task.segmentStart, task.segmentStart))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for i := range segments {
+			segments[i].DecRef()
+		}
+	}()
+	for _, segment := range segments {
+		if !segment.GetTimeRange().Start.Equal(task.segmentStart) {
+			continue
+		}
+		tables, shardIDs, _ := segment.TablesWithShardIDs()
+		for i, tst := range tables {
+			if shardIDs[i] == task.shardID {
+				return ib.buildTable(s, segment, tst)
+			}
+		}
+	}
+	return nil
+}
+
+func (ib *indexBuilder) buildTable(s *stream, segment storage.Segment[*tsTable, option], tst *tsTable) error {
+	is := s.indexSchema.Load().(indexSchema)
+	missing := tst.buildState.Missing(s.name, elementIndexRuleIDs(is.indexRules))
+	if len(missing) == 0 {
+		return nil
+	}
+	progress := tst.buildState.StartBuilding(s.name, storage.IndexRuleNames(is.indexRules, missing))
+	err := ib.buildParts(s, segment, tst, is, progress)
+	if err != nil {
+		progress.SetError(err)
+		return err
+	}
+	tst.buildState.MarkBuilt(s.name, missing)
+	ib.l.Info().Str("stream", s.group+"/"+s.name).Str("segment", segment.GetTimeRange().String()).
+		Int64("parts", progress.BuiltParts.Load()).Msg("indices are built")
+	return nil
+}
+
+func (ib *indexBuilder) buildParts(s *stream, segment storage.Segment[*tsTable, option], tst *tsTable, is indexSchema, progress *storage.IndexBuildProgress) error {
+	entity := make([]*modelv1.TagValue, len(s.schema.GetEntity().GetTagNames()))
+	for i := range entity {
+		entity[i] = pbv1.AnyTagValue
+	}
+	sl, err := segment.Lookup(context.Background(), []*pbv1.Series{{Subject: s.name, EntityValues: entity}})
+	if err != nil {
+		return err
+	}
+	series := make(map[common.SeriesID][]*modelv1.TagValue, len(sl))
+	for _, sr := range sl {
+		series[sr.ID] = sr.EntityValues
+	}
+	snp := tst.currentSnapshot()
+	if snp == nil {
+		return nil
+	}
+	defer snp.decRef()
+	progress.TotalParts.Store(int64(len(snp.parts)))
+	progress.BuiltParts.Store(0)
+	for _, pw := range snp.parts {
+		if len(series) > 0 {
+			if err = ib.buildPart(s, tst, pw.p, series, is); err != nil {
+				return err
+			}
+		}
+		progress.BuiltParts.Add(1)
+	}
+	return nil
+}
+
+func (ib *indexBuilder) buildPart(s *stream, tst *tsTable, p *part, series map[common.SeriesID][]*modelv1.TagValue, is indexSchema) error {
+	pmi := generatePartMergeIter()
+	defer releasePartMergeIter(pmi)
+	pmi.mustInitFromPart(p)
+	br := generateBlockReader()
+	defer releaseBlockReader(br)
+	br.init([]*partMergeIter{pmi})
+	decoder := generateColumnValuesDecoder()
+	defer releaseColumnValuesDecoder(decoder)
+	docs := make(index.Documents, 0, indexBuildBatchSize)
+	for br.nextBlockMetadata() {
+		select {
+		case <-ib.closer.CloseNotify():
+			return errClosed
+		default:
+		}
+		b := br.block
+		entityValues, ok := series[b.bm.seriesID]
+		if !ok {
+			continue
+		}
+		br.loadBlockData(decoder)
+		docs = appendIndexBuildDocs(docs, b, entityValues, s.schema.GetTagFamilies(), is)
+		if len(docs) < indexBuildBatchSize {
+			continue
+		}
+		if err := tst.Index().Update(docs); err != nil {
+			return err
+		}
+		docs = docs[:0]
+	}
+	if err := br.error(); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	return tst.Index().Update(docs)
+}
+
+// appendIndexBuildDocs appends the element index documents of the block.
+// The values of the entity tags are not stored in parts, so they come from the series.
+func appendIndexBuildDocs(docs index.Documents, b *blockPointer, entityValues []*modelv1.TagValue,
+	tagFamilies []*databasev1.TagFamilySpec, is indexSchema,
+) index.Documents {
+	tags := make(map[string]*tag)
+	for i := range b.tagFamilies {
+		for j := range b.tagFamilies[i].tags {
+			t := &b.tagFamilies[i].tags[j]
+			tags[t.name] = t
+		}
+	}
+	for k := range b.timestamps {
+		var fields []index.Field
+		for i, tf := range tagFamilies {
+			if i >= len(is.indexRuleLocators.TagFamilyTRule) {
+				break
+			}
+			tfr := is.indexRuleLocators.TagFamilyTRule[i]
+			for _, spec := range tf.GetTags() {
+				r, ok := tfr[spec.GetName()]
+				if !ok || !isElementIndexRule(r) {
+					continue
+				}
+				var tv *modelv1.TagValue
+				if pos := is.indexRuleLocators.EntitySet[spec.GetName()]; pos > 0 {
+					if pos > len(entityValues) {
+						continue
+					}
+					tv = entityValues[pos-1]
+				} else if t, ok := tags[spec.GetName()]; ok && k < len(t.values) {
+					// The decoder reuses its buffer across blocks, so the binary values are copied.
+					tv = mustDecodeTagValue(t.valueType, bytes.Clone(t.values[k]))
+				}
+				if tv == nil || tv == pbv1.NullTagValue {
+					continue
+				}
+				fields = appendElementIndexFields(fields, r, b.bm.seriesID, spec.GetType(), tv)
+			}
+		}
+		docs = append(docs, index.Document{
+			DocID:     b.elementIDs[k],
+			Fields:    fields,
+			Timestamp: b.timestamps[k],
+		})
+	}
+	return docs
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/dustin/go-humanize"
 
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/cgroups"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
@@ -49,6 +50,13 @@ func (tst *tsTable) mergeLoop(merges chan *mergerIntroduction, flusherNotifier w
 		select {
 		case <-tst.loopCloser.CloseNotify():
 			return
+		case removal := <-tst.removals:
+			// The removal is introduced between two merges, so no merged part brings the removed data back.
+			select {
+			case merges <- removal:
+			case <-tst.loopCloser.CloseNotify():
+				return
+			}
 		case <-ew.Watch():
 			if func() bool {
 				curSnapshot := tst.currentSnapshot()
@@ -263,6 +271,7 @@ func (tst *tsTable) mergeParts(fileSystem fs.FileSystem, closeCh <-chan struct{}
 		return nil, err
 	}
 	pm.mustWriteMetadata(fileSystem, dstPath)
+	storage.MustWritePartChecksums(fileSystem, dstPath)
 	fileSystem.SyncPath(dstPath)
 	p := mustOpenFilePart(partID, root, fileSystem)
 
@@ -26,9 +26,11 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/api/data"
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	"github.com/apache/skywalking-banyandb/api/validate"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	"github.com/apache/skywalking-banyandb/banyand/liaison/grpc"
@@ -37,7 +39,9 @@ import (
 	"github.com/apache/skywalking-banyandb/banyand/observability"
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/banyand/queue/pub"
+	"github.com/apache/skywalking-banyandb/pkg/fs"
 	"github.com/apache/skywalking-banyandb/pkg/idgen"
+	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 	"github.com/apache/skywalking-banyandb/pkg/meter"
 	resourceSchema "github.com/apache/skywalking-banyandb/pkg/schema"
@@ -53,12 +57,14 @@ type SchemaService interface {
 }
 type schemaRepo struct {
 	resourceSchema.Repository
-	l        *logger.Logger
-	metadata metadata.Repo
-	idGen    *idgen.Generator
-	path     string
-	nodeID   string
-	role     databasev1.Role
+	onGroupDelete func(groupName string)
+	l             *logger.Logger
+	metadata      metadata.Repo
+	idGen         *idgen.Generator
+	indexBuilder  *indexBuilder
+	path          string
+	nodeID        string
+	role          databasev1.Role
 }
 
 func newSchemaRepo(path string, svc *standalone, nodeLabels map[string]string, nodeID string) schemaRepo {
@@ -76,6 +82,7 @@ func newSchemaRepo(path string, svc *standalone, nodeLabels map[string]string, n
 			resourceSchema.NewMetrics(svc.omr.With(metadataScope)),
 		),
 	}
+	sr.indexBuilder = newIndexBuilder(sr.l, sr.Repository)
 	sr.start()
 	return sr
 }
@@ -95,6 +102,9 @@ func newLiaisonSchemaRepo(path string, svc *liaison, streamDataNodeRegistry grpc
 			resourceSchema.NewMetrics(svc.omr.With(metadataScope)),
 		),
 	}
+	if svc.handoffCtrl != nil {
+		sr.onGroupDelete = svc.handoffCtrl.DeletePartsByGroup
+	}
 	sr.start()
 	return sr
 }
@@ -198,6 +208,9 @@ func (sr *schemaRepo) OnDelete(metadata schema.Metadata) {
 		if g.Catalog != commonv1.Catalog_CATALOG_STREAM {
 			return
 		}
+		if sr.onGroupDelete != nil {
+			sr.onGroupDelete(g.Metadata.Name)
+		}
 		sr.SendMetadataEvent(resourceSchema.MetadataEvent{
 			Typ:      resourceSchema.EventDelete,
 			Kind:     resourceSchema.EventKindGroup,
@@ -221,6 +234,7 @@ func (sr *schemaRepo) OnDelete(metadata schema.Metadata) {
 		}
 	case schema.KindIndexRule:
 		if rule, ok := metadata.Spec.(*databasev1.IndexRule); ok {
+			index.RemoveUsage(rule.GetMetadata().GetId())
 			sr.SendMetadataEvent(resourceSchema.MetadataEvent{
 				Typ:      resourceSchema.EventDelete,
 				Kind:     resourceSchema.EventKindIndexRule,
@@ -240,6 +254,15 @@ func (sr *schemaRepo) loadStream(metadata *commonv1.Metadata) (*stream, bool) {
 	return s, ok
 }
 
+// seriesLimits returns the series limits of the stream merged into the ones of its group, and the size of its entity.
+func (sr *schemaRepo) seriesLimits(s *databasev1.Stream) (storage.SeriesLimits, int) {
+	var groupLimits *commonv1.SeriesLimits
+	if g, ok := sr.LoadGroup(s.GetMetadata().GetGroup()); ok {
+		groupLimits = g.GetSchema().GetResourceOpts().GetSeriesLimits()
+	}
+	return storage.NewSeriesLimits(groupLimits, s.GetSeriesLimits()), len(s.GetEntity().GetTagNames())
+}
+
 func (sr *schemaRepo) loadTSDB(groupName string) (storage.TSDB[*tsTable, option], error) {
 	if sr == nil {
 		return nil, fmt.Errorf("schemaRepo is nil")
@@ -278,6 +301,11 @@ func (sr *schemaRepo) CollectDataInfo(ctx context.Context, group string) (*datab
 	if segmentsErr != nil {
 		return nil, segmentsErr
 	}
+	indexRules, listErr := sr.metadata.IndexRuleRegistry().ListIndexRule(ctx, schema.ListOpt{Group: group})
+	if listErr != nil {
+		sr.l.Warn().Err(listErr).Str("group", group).Msg("failed to list index rules to collect their usages")
+	}
+	indexRuleSizes := make(map[uint32]int64, len(indexRules))
 	var segmentInfoList []*databasev1.SegmentInfo
 	var totalDataSize int64
 	for _, segment := range segments {
@@ -291,6 +319,16 @@ func (sr *schemaRepo) CollectDataInfo(ctx context.Context, group string) (*datab
 		}
 		seriesIndexInfo := sr.collectSeriesIndexInfo(segment)
 		totalDataSize += seriesIndexInfo.DataSizeBytes
+		for _, table := range tables {
+			if table.index == nil {
+				continue
+			}
+			for _, r := range indexRules {
+				if isElementIndexRule(r) {
+					indexRuleSizes[r.GetMetadata().GetId()] += table.index.FieldSize(index.FieldKey{IndexRuleID: r.GetMetadata().GetId()})
+				}
+			}
+		}
 		segmentInfo := &databasev1.SegmentInfo{
 			SegmentId:       fmt.Sprintf("%d-%d", timeRange.Start.UnixNano(), timeRange.End.UnixNano()),
 			TimeRangeStart:  timeRange.Start.Format(time.RFC3339Nano),
@@ -302,13 +340,36 @@ func (sr *schemaRepo) CollectDataInfo(ctx context.Context, group string) (*datab
 		segment.DecRef()
 	}
 	dataInfo := &databasev1.DataInfo{
-		Node:          node,
-		SegmentInfo:   segmentInfoList,
-		DataSizeBytes: totalDataSize,
+		Node:            node,
+		SegmentInfo:     segmentInfoList,
+		DataSizeBytes:   totalDataSize,
+		IndexRuleUsages: index.CollectUsages(indexRules, indexRuleSizes),
+		ScrubStatus:     tsdb.ScrubStatus(),
 	}
 	return dataInfo, nil
 }
 
+// ReportReadFailure starts a scrub of the group after a read failed.
+func (sr *schemaRepo) ReportReadFailure(group string) {
+	tsdb, err := sr.loadTSDB(group)
+	if err != nil || tsdb == nil {
+		return
+	}
+	tsdb.ReportReadFailure()
+}
+
+// ScrubGroup starts verifying the checksums of the group's parts in the background, unless a scrub is running.
+func (sr *schemaRepo) ScrubGroup(_ context.Context, group string) error {
+	tsdb, err := sr.loadTSDB(group)
+	if err != nil {
+		return err
+	}
+	if tsdb != nil && !tsdb.Scrub() {
+		sr.l.Info().Str("group", group).Msg("the group is being scrubbed")
+	}
+	return nil
+}
+
 func (sr *schemaRepo) collectSeriesIndexInfo(segment storage.Segment[*tsTable, option]) *databasev1.SeriesIndexInfo {
 	indexDB := segment.IndexDB()
 	if indexDB == nil {
@@ -366,6 +427,7 @@ func (sr *schemaRepo) collectShardInfo(table any, shardID uint32) *databasev1.Sh
 		PartCount:         int64(partCount),
 		InvertedIndexInfo: invertedIndexInfo,
 		SidxInfo:          &databasev1.SIDXInfo{},
+		IndexBuilds:       tst.buildState.Info(),
 	}
 }
 
@@ -461,6 +523,16 @@ func (sr *schemaRepo) collectPendingSyncInfo(groupName string) (partCount int64,
 	return partCount, totalSizeBytes, nil
 }
 
+// requiredAcks returns the number of the replicas which must persist the writes to the group before the liaison acknowledges them.
+func (sr *schemaRepo) requiredAcks(groupName string) int {
+	g, ok := sr.LoadGroup(groupName)
+	if !ok {
+		return 0
+	}
+	opts := g.GetSchema().GetResourceOpts()
+	return wqueue.RequiredAcks(opts.GetWriteConsistency(), opts.GetReplicas()+1)
+}
+
 func (sr *schemaRepo) loadQueue(groupName string) (*wqueue.Queue[*tsTable, option], error) {
 	g, ok := sr.LoadGroup(groupName)
 	if !ok {
@@ -537,6 +609,7 @@ func (s *supplier) OpenDB(groupSchema *commonv1.Group) (resourceSchema.DB, error
 	segInterval := ro.SegmentInterval
 	segmentIdleTimeout := time.Duration(0)
 	disableRetention := false
+	remoteStage := false
 	if len(ro.Stages) > 0 && len(s.nodeLabels) > 0 {
 		var ttlNum uint32
 		foundMatched := false
@@ -560,6 +633,7 @@ func (s *supplier) OpenDB(groupSchema *commonv1.Group) (resourceSchema.DB, error
 				segmentIdleTimeout = 5 * time.Minute
 			}
 			disableRetention = i+1 < len(ro.Stages)
+			remoteStage = st.Remote
 			break
 		}
 		if !foundMatched {
@@ -581,6 +655,29 @@ func (s *supplier) OpenDB(groupSchema *commonv1.Group) (resourceSchema.DB, error
 		SegmentIdleTimeout:             segmentIdleTimeout,
 		DisableRetention:               disableRetention,
 		MemoryLimit:                    s.pm.GetLimit(),
+		Scrubber:                       s.option.scrubber,
+	}
+	for _, dataPath := range s.option.extraDataPaths {
+		opts.ExtraLocations = append(opts.ExtraLocations, path.Join(dataPath, group))
+	}
+	if s.option.peerClient != nil {
+		catalog := &replicaCatalog{schemaRepo: s.schemaRepo, client: s.option.peerClient, l: s.l}
+		opts.RepairPart = func(shardID common.ShardID, timeRange timestamp.TimeRange, partID uint64) error {
+			return storage.RepairPart(s.option.peerClient, catalog, data.TopicStreamRepairShard, s.schemaRepo.nodeID, group, shardID, timeRange, partID)
+		}
+		opts.AntiEntropy = func(shardID common.ShardID, timeRange timestamp.TimeRange) (storage.AntiEntropyResult, error) {
+			return storage.AntiEntropy(s.option.peerClient, catalog, data.TopicStreamAntiEntropyDigest, data.TopicStreamRepairShard,
+				s.schemaRepo.nodeID, group, shardID, timeRange)
+		}
+		opts.AntiEntropyCron = s.option.antiEntropyCron
+	}
+	if remoteStage {
+		if s.option.remoteStorage == nil {
+			s.l.Warn().Str("group", group).Msg("the stage is remote-backed but no remote storage is configured, keep its data on the local disk")
+		} else {
+			opts.RemoteStorage = s.option.remoteStorage
+			opts.KeepLocal = keepPartFileLocally
+		}
 	}
 	return storage.OpenTSDB(
 		common.SetPosition(context.Background(), func(_ common.Position) common.Position {
@@ -599,6 +696,7 @@ type queueSupplier struct {
 	streamDataNodeRegistry grpc.NodeRegistry
 	l                      *logger.Logger
 	schemaRepo             *schemaRepo
+	handoffCtrl            *handoff.Controller
 	path                   string
 	option                 option
 }
@@ -622,6 +720,7 @@ func newQueueSupplier(path string, svc *liaison, streamDataNodeRegistry grpc.Nod
 		pm:                     svc.pm,
 		path:                   path,
 		schemaRepo:             &svc.schemaRepo,
+		handoffCtrl:            svc.handoffCtrl,
 		streamDataNodeRegistry: streamDataNodeRegistry,
 	}
 }
@@ -658,7 +757,11 @@ func (s *queueSupplier) OpenDB(groupSchema *commonv1.Group) (resourceSchema.DB,
 		Location:        path.Join(s.path, group),
 		Option:          s.option,
 		Metrics:         s.newMetrics(p),
-		SubQueueCreator: newWriteQueue,
+		SubQueueCreator: func(fileSystem fs.FileSystem, root string, position common.Position,
+			l *logger.Logger, option option, metrics any, group string, shardID common.ShardID, getNodes func() []string,
+		) (*tsTable, error) {
+			return newWriteQueue(fileSystem, root, position, l, option, metrics, group, shardID, getNodes, s.handoffCtrl)
+		},
 		GetNodes: func(shardID common.ShardID) []string {
 			copies := ro.Replicas + 1
 			nodeSet := make(map[string]struct{}, copies)
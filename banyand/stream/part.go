@@ -229,6 +229,7 @@ func (mp *memPart) mustFlush(fileSystem fs.FileSystem, path string) {
 	}
 
 	mp.partMetadata.mustWriteMetadata(fileSystem, path)
+	storage.MustWritePartChecksums(fileSystem, path)
 
 	fileSystem.SyncPath(path)
 }
@@ -370,6 +371,21 @@ func mustOpenReader(name string, fileSystem fs.FileSystem) fs.Reader {
 	return f
 }
 
+// keepPartFileLocally reports whether a part file stays on the local disk when the part is offloaded.
+// Metadata, block indexes and tag filters are needed to plan queries, while timestamps and
+// tag family blocks are fetched from the remote storage on demand.
+func keepPartFileLocally(name string) bool {
+	switch name {
+	case metadataFilename, metaFilename, primaryFilename, seriesMetadataFilename:
+		return true
+	}
+	switch filepath.Ext(name) {
+	case tagFamiliesMetadataFilenameExt, tagFamiliesFilterFilenameExt:
+		return true
+	}
+	return false
+}
+
 func removeExt(nameWithExt, ext string) string {
 	return nameWithExt[:len(nameWithExt)-len(ext)]
 }
@@ -24,6 +24,7 @@ import (
 	"sort"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/bytes"
 	"github.com/apache/skywalking-banyandb/pkg/compress/zstd"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
@@ -155,8 +156,8 @@ func (pi *partIter) loadNextBlockMetadata() bool {
 		var err error
 		pi.bms, err = pi.readPrimaryBlock(pi.bms[:0], pbm)
 		if err != nil {
-			pi.err = fmt.Errorf("cannot read primary block for part %q at offset %d with size %d: %w",
-				&pi.p.partMetadata, pbm.offset, pbm.size, err)
+			pi.err = fmt.Errorf("cannot read primary block for part %q at offset %d with size %d: %w: %w",
+				&pi.p.partMetadata, pbm.offset, pbm.size, storage.ErrPartCorrupted, err)
 			return false
 		}
 		return true
@@ -246,9 +247,8 @@ func (pi *partIter) findBlock() bool {
 				return false
 			}
 			if shouldSkip {
-				if !pi.nextSeriesID() {
-					return false
-				}
+				// The later blocks of the series might still match.
+				bhs = bhs[1:]
 				continue
 			}
 		}
@@ -26,10 +26,12 @@ import (
 
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/banyand/queue"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 	"github.com/apache/skywalking-banyandb/pkg/partition"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
 	"github.com/apache/skywalking-banyandb/pkg/query/model"
 	"github.com/apache/skywalking-banyandb/pkg/run"
 	"github.com/apache/skywalking-banyandb/pkg/schema"
@@ -44,12 +46,19 @@ const (
 
 	defaultFlushTimeout = time.Second
 	defaultSyncInterval = 30 * time.Second
+	// defaultWriteAckTimeout covers a flush and a sync of the written elements.
+	defaultWriteAckTimeout = 20 * time.Second
 )
 
 type option struct {
 	mergePolicy                  *mergePolicy
+	remoteStorage                *storage.RemoteStorage
+	scrubber                     *storage.Scrubber
 	protector                    protector.Memory
 	tire2Client                  queue.Client
+	peerClient                   queue.Client
+	extraDataPaths               []string
+	antiEntropyCron              string
 	seriesCacheMaxSize           run.Bytes
 	flushTimeout                 time.Duration
 	elementIndexFlushTimeout     time.Duration
@@ -68,7 +77,17 @@ type Query interface {
 type Stream interface {
 	GetSchema() *databasev1.Stream
 	GetIndexRules() []*databasev1.IndexRule
+	// GetQueryableIndexRules returns the index rules built on all the data in the time range.
+	// The index rules being built on the existing data are left out.
+	GetQueryableIndexRules(timeRange timestamp.TimeRange) []*databasev1.IndexRule
 	Query(ctx context.Context, opts model.StreamQueryOptions) (model.StreamQueryResult, error)
+	// TagValues returns the distinct values of an entity tag or an indexed tag starting with the prefix in the time range.
+	TagValues(ctx context.Context, tagName string, timeRange timestamp.TimeRange, prefix string, limit int) ([]string, error)
+	// ListSeries lists at most limit series in the time range whose encoded entity values are greater than after.
+	// It returns whether there are more series.
+	ListSeries(ctx context.Context, timeRange timestamp.TimeRange, after []byte, limit int) (pbv1.SeriesList, bool, error)
+	// SeriesCardinality breaks down the series in the time range by segments and the values of the entity tags.
+	SeriesCardinality(ctx context.Context, timeRange timestamp.TimeRange, tags []string, topN int) ([]*databasev1.SeriesCardinalityBucket, error)
 }
 
 type indexSchema struct {
@@ -112,11 +131,50 @@ func (s *stream) GetIndexRules() []*databasev1.IndexRule {
 	return is.(indexSchema).indexRules
 }
 
+func (s *stream) GetQueryableIndexRules(timeRange timestamp.TimeRange) []*databasev1.IndexRule {
+	rules := s.GetIndexRules()
+	tsdb, err := s.getTSDB()
+	if err != nil {
+		return rules
+	}
+	segments, err := tsdb.SelectSegments(timeRange)
+	if err != nil {
+		return rules
+	}
+	current := elementIndexRuleIDs(rules)
+	building := make(map[uint32]struct{})
+	for _, segment := range segments {
+		tables, _ := segment.Tables()
+		for _, tst := range tables {
+			if tst.buildState == nil {
+				continue
+			}
+			for _, id := range tst.buildState.Missing(s.name, current) {
+				building[id] = struct{}{}
+			}
+		}
+		segment.DecRef()
+	}
+	if len(building) == 0 {
+		return rules
+	}
+	result := make([]*databasev1.IndexRule, 0, len(rules))
+	for _, r := range rules {
+		if _, ok := building[r.GetMetadata().GetId()]; !ok {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
 func (s *stream) OnIndexUpdate(index []*databasev1.IndexRule) {
 	var is indexSchema
 	is.indexRules = index
 	is.parse(s.schema)
 	s.indexSchema.Store(is)
+	if s.schemaRepo != nil && s.schemaRepo.indexBuilder != nil {
+		s.schemaRepo.indexBuilder.schedule(s, index)
+	}
 }
 
 func (s *stream) parseSpec() {
@@ -23,6 +23,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/apache/skywalking-banyandb/api/data"
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/liaison/grpc"
 	"github.com/apache/skywalking-banyandb/banyand/metadata"
@@ -56,12 +58,19 @@ type liaison struct {
 	writeListener             bus.MessageListener
 	dataNodeSelector          node.Selector
 	l                         *logger.Logger
+	handoffCtrl               *handoff.Controller
 	schemaRepo                schemaRepo
 	dataPath                  string
 	root                      string
+	dataNodeList              []string
 	option                    option
+	handoffExpiry             time.Duration
+	writeAckTimeout           time.Duration
+	handoffReplayRate         run.Bytes
 	maxDiskUsagePercent       int
 	failedPartsMaxSizePercent int
+	handoffMaxSizePercent     int
+	handoffMaxNodeSizePercent int
 }
 
 func (s *liaison) Stream(metadata *commonv1.Metadata) (Stream, error) {
@@ -108,6 +117,11 @@ func (s *liaison) CollectLiaisonInfo(_ context.Context, group string) (*database
 	}
 	info.PendingSyncPartCount = pendingSyncPartCount
 	info.PendingSyncDataSizeBytes = pendingSyncDataSizeBytes
+	if s.handoffCtrl != nil {
+		partCount, totalSize := s.handoffCtrl.Stats()
+		info.PendingHandoffPartCount = partCount
+		info.PendingHandoffDataSizeBytes = totalSize
+	}
 	return info, nil
 }
 
@@ -116,12 +130,25 @@ func (s *liaison) FlagSet() *run.FlagSet {
 	flagS.StringVar(&s.root, "stream-root-path", "/tmp", "the root path of stream")
 	flagS.StringVar(&s.dataPath, "stream-data-path", "", "the data directory path of stream. If not set, <stream-root-path>/stream/data will be used")
 	flagS.DurationVar(&s.option.flushTimeout, "stream-flush-timeout", defaultFlushTimeout, "the memory data timeout of stream")
+	flagS.DurationVar(&s.writeAckTimeout, "stream-write-ack-timeout", defaultWriteAckTimeout,
+		"how long a write waits for the replicas required by the write consistency of its group. Set to 0 to acknowledge writes without waiting")
 	flagS.IntVar(&s.maxDiskUsagePercent, "stream-max-disk-usage-percent", 95, "the maximum disk usage percentage allowed")
 	flagS.DurationVar(&s.option.syncInterval, "stream-sync-interval", defaultSyncInterval, "the periodic sync interval for stream data")
 	flagS.IntVar(&s.failedPartsMaxSizePercent, "failed-parts-max-size-percent", 10,
 		"percentage of BanyanDB's allowed disk usage allocated to failed parts storage. "+
 			"Calculated as: totalDisk * stream-max-disk-usage-percent * failed-parts-max-size-percent / 10000. "+
 			"Set to 0 to disable copying failed parts. Valid range: 0-100")
+	flagS.StringSliceVar(&s.dataNodeList, "stream-data-node-list", nil, "comma-separated list of data node names to monitor for stream handoff")
+	flagS.IntVar(&s.handoffMaxSizePercent, "stream-handoff-max-size-percent", 10,
+		"percentage of BanyanDB's allowed disk usage allocated to stream handoff storage. "+
+			"Calculated as: totalDisk * stream-max-disk-usage-percent * stream-handoff-max-size-percent / 10000. "+
+			"Set to 0 to disable the stream handoff. Valid range: 0-100")
+	flagS.IntVar(&s.handoffMaxNodeSizePercent, "stream-handoff-max-node-size-percent", 0,
+		"percentage of the stream handoff storage a single offline node may take. Set to 0 to only limit the total size. Valid range: 0-100")
+	flagS.DurationVar(&s.handoffExpiry, "stream-handoff-expiry", 0,
+		"how long a stream part stays in the handoff queue before it's dropped. The parts never expire if it's 0")
+	flagS.VarP(&s.handoffReplayRate, "stream-handoff-replay-rate", "",
+		"the maximum bytes per second of stream parts replayed to the recovered nodes. The replay is not throttled if it's 0")
 	return flagS
 }
 
@@ -138,6 +165,21 @@ func (s *liaison) Validate() error {
 	if s.failedPartsMaxSizePercent < 0 || s.failedPartsMaxSizePercent > 100 {
 		return errors.New("failed-parts-max-size-percent must be between 0 and 100")
 	}
+	if s.handoffMaxSizePercent < 0 || s.handoffMaxSizePercent > 100 {
+		return errors.New("stream-handoff-max-size-percent must be between 0 and 100")
+	}
+	if s.handoffMaxNodeSizePercent < 0 || s.handoffMaxNodeSizePercent > 100 {
+		return errors.New("stream-handoff-max-node-size-percent must be between 0 and 100")
+	}
+	if s.handoffExpiry < 0 {
+		return errors.New("stream-handoff-expiry must be greater than or equal to 0")
+	}
+	if s.writeAckTimeout < 0 {
+		return errors.New("stream-write-ack-timeout must be greater than or equal to 0")
+	}
+	if s.handoffReplayRate < 0 {
+		return errors.New("stream-handoff-replay-rate must be greater than or equal to 0")
+	}
 	return nil
 }
 
@@ -190,10 +232,34 @@ func (s *liaison) PreRun(ctx context.Context) error {
 	} else {
 		s.l.Info().Msg("failed parts storage limit disabled (percent set to 0)")
 	}
+	if len(s.dataNodeList) > 0 && s.option.tire2Client != nil && s.handoffMaxSizePercent > 0 {
+		totalSpace := s.lfs.MustGetTotalSpace(s.dataPath)
+		// Divide after each multiplication to avoid overflow with large disk capacities
+		maxSizeBytes := totalSpace * uint64(s.maxDiskUsagePercent) / 100 * uint64(s.handoffMaxSizePercent) / 100
+		cfg := handoff.Config{
+			MaxTotalSizeBytes: maxSizeBytes,
+			MaxNodeSizeBytes:  maxSizeBytes * uint64(s.handoffMaxNodeSizePercent) / 100,
+			Expiry:            s.handoffExpiry,
+			ReplayRate:        s.handoffReplayRate,
+		}
+		var factory observability.Factory
+		if s.omr != nil {
+			factory = s.omr.With(streamScope.SubScope("handoff"))
+		}
+		if s.handoffCtrl, err = handoff.NewController(s.lfs, s.dataPath, s.option.tire2Client, handoffCodec{}, s.dataNodeList, cfg, s.l, factory,
+			handoff.NewShardAssignmentResolver(s.metadata, s.dataNodeList)); err != nil {
+			return err
+		}
+		s.l.Info().
+			Strs("dataNodes", s.dataNodeList).
+			Uint64("maxSize", maxSizeBytes).
+			Int("maxSizePercent", s.handoffMaxSizePercent).
+			Msg("handoff controller initialized")
+	}
 	node := val.(common.Node)
 	streamDataNodeRegistry := grpc.NewClusterNodeRegistry(data.TopicStreamPartSync, s.option.tire2Client, s.dataNodeSelector)
 	s.schemaRepo = newLiaisonSchemaRepo(s.dataPath, s, streamDataNodeRegistry, node.NodeID)
-	s.writeListener = setUpWriteQueueCallback(s.l, &s.schemaRepo, s.maxDiskUsagePercent, s.option.tire2Client)
+	s.writeListener = setUpWriteQueueCallback(s.l, &s.schemaRepo, s.maxDiskUsagePercent, s.option.tire2Client, s.writeAckTimeout)
 
 	// Register chunked sync handler for stream data
 	s.pipeline.RegisterChunkedSyncHandler(data.TopicStreamPartSync, setUpChunkedSyncCallback(s.l, &s.schemaRepo))
@@ -219,6 +285,11 @@ func (s *liaison) Serve() run.StopNotify {
 }
 
 func (s *liaison) GracefulStop() {
+	if s.handoffCtrl != nil {
+		if err := s.handoffCtrl.Close(); err != nil {
+			s.l.Warn().Err(err).Msg("failed to close handoff controller")
+		}
+	}
 	s.schemaRepo.Close()
 }
 
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
 
 	"github.com/apache/skywalking-banyandb/api/common"
 	"github.com/apache/skywalking-banyandb/api/data"
@@ -80,6 +81,8 @@ type standalone struct {
 	dataPath              string
 	snapshotDir           string
 	option                option
+	remoteConfig          storage.RemoteStorageConfig
+	scrubConfig           storage.ScrubConfig
 	retentionConfig       storage.RetentionConfig
 	maxFileSnapshotNum    int
 	minFileSnapshotAge    time.Duration
@@ -107,8 +110,8 @@ func (s *standalone) GetRemovalSegmentsTimeRange(group string) *timestamp.TimeRa
 
 // RetentionService interface implementation.
 
-func (s *standalone) GetDataPath() string {
-	return s.dataPath
+func (s *standalone) GetDataPaths() []string {
+	return append([]string{s.dataPath}, s.option.extraDataPaths...)
 }
 
 func (s *standalone) GetSnapshotDir() string {
@@ -119,7 +122,7 @@ func (s *standalone) LoadAllGroups() []resourceSchema.Group {
 	return s.schemaRepo.LoadAllGroups()
 }
 
-func (s *standalone) PeekOldestSegmentEndTimeInGroup(group string) (time.Time, bool) {
+func (s *standalone) PeekOldestSegmentEndTimeInGroup(group, volume string) (time.Time, bool) {
 	g, ok := s.schemaRepo.LoadGroup(group)
 	if !ok {
 		return time.Time{}, false
@@ -131,14 +134,16 @@ func (s *standalone) PeekOldestSegmentEndTimeInGroup(group string) (time.Time, b
 	}
 
 	// Type assert to the storage interface that has PeekOldestSegmentEndTime
-	if dbWithPeek, ok := db.(interface{ PeekOldestSegmentEndTime() (time.Time, bool) }); ok {
-		return dbWithPeek.PeekOldestSegmentEndTime()
+	if dbWithPeek, ok := db.(interface {
+		PeekOldestSegmentEndTime(volume string) (time.Time, bool)
+	}); ok {
+		return dbWithPeek.PeekOldestSegmentEndTime(volume)
 	}
 
 	return time.Time{}, false
 }
 
-func (s *standalone) DeleteOldestSegmentInGroup(group string) (bool, error) {
+func (s *standalone) DeleteOldestSegmentInGroup(group, volume string) (bool, error) {
 	g, ok := s.schemaRepo.LoadGroup(group)
 	if !ok {
 		return false, nil
@@ -150,8 +155,10 @@ func (s *standalone) DeleteOldestSegmentInGroup(group string) (bool, error) {
 	}
 
 	// Type assert to the storage interface that has DeleteOldestSegment
-	if dbWithDelete, ok := db.(interface{ DeleteOldestSegment() (bool, error) }); ok {
-		return dbWithDelete.DeleteOldestSegment()
+	if dbWithDelete, ok := db.(interface {
+		DeleteOldestSegment(volume string) (bool, error)
+	}); ok {
+		return dbWithDelete.DeleteOldestSegment(volume)
 	}
 
 	s.l.Debug().Str("group", group).Msg("database does not support DeleteOldestSegment")
@@ -175,6 +182,8 @@ func (s *standalone) FlagSet() *run.FlagSet {
 	flagS := run.NewFlagSet("storage")
 	flagS.StringVar(&s.root, "stream-root-path", "/tmp", "the root path of stream")
 	flagS.StringVar(&s.dataPath, "stream-data-path", "", "the data directory path of stream. If not set, <stream-root-path>/stream/data will be used")
+	flagS.StringSliceVar(&s.option.extraDataPaths, "stream-extra-data-paths", nil,
+		"the additional data directory paths of stream, usually one per disk. New segments and shards are spread across them and the data path by free space")
 	flagS.DurationVar(&s.option.flushTimeout, "stream-flush-timeout", defaultFlushTimeout, "the memory data timeout of stream")
 	flagS.DurationVar(&s.option.elementIndexFlushTimeout, "element-index-flush-timeout", defaultFlushTimeout, "the elementIndex timeout of stream")
 	s.option.mergePolicy = newDefaultMergePolicy()
@@ -192,6 +201,23 @@ func (s *standalone) FlagSet() *run.FlagSet {
 
 	flagS.IntVar(&s.maxFileSnapshotNum, "stream-max-file-snapshot-num", 10, "the maximum number of file snapshots allowed")
 	flagS.DurationVar(&s.minFileSnapshotAge, "stream-min-file-snapshot-age", time.Hour, "minimum age for file snapshots to be eligible for deletion")
+
+	// Remote storage flags of remote-backed lifecycle stages
+	flagS.StringVar(&s.remoteConfig.Dest, "stream-remote-storage-dest", "", "the object storage URL of remote-backed stages (e.g., s3:///bucket/path)")
+	flagS.StringVar(&s.remoteConfig.ConfigFile, "stream-remote-storage-config", "", "the JSON or YAML file holding the credentials of the object storage")
+	flagS.StringVar(&s.remoteConfig.CachePath, "stream-remote-storage-cache-path", "",
+		"the read-through cache directory of offloaded data. If not set, <stream-root-path>/stream/remote-cache will be used")
+	s.remoteConfig.CacheSize = run.Bytes(10 << 30)
+	flagS.VarP(&s.remoteConfig.CacheSize, "stream-remote-storage-cache-size", "", "the capacity of the read-through cache of offloaded data")
+	flagS.DurationVar(&s.remoteConfig.OffloadDelay, "stream-remote-storage-offload-delay", time.Hour,
+		"how long a segment stays on the local disk after its end time before being offloaded")
+
+	// Scrub flags of the background verification of the part checksums
+	flagS.DurationVar(&s.scrubConfig.Interval, "stream-scrub-interval", 24*time.Hour, "the interval between two scrubs of a group. Periodic scrubs are disabled if it's 0")
+	s.scrubConfig.Rate = run.Bytes(16 << 20)
+	flagS.VarP(&s.scrubConfig.Rate, "stream-scrub-rate", "", "the maximum bytes per second read by the scrubs of all the groups. Scrubs are not throttled if it's 0")
+	flagS.StringVar(&s.option.antiEntropyCron, "stream-anti-entropy-cron", "@every 6h",
+		"the cron expression of comparing the settled data with the replicas and pulling the missing elements. The anti-entropy repair is disabled if it's empty")
 	return flagS
 }
 
@@ -217,6 +243,24 @@ func (s *standalone) Validate() error {
 		return errors.New("stream-retention-cooldown must be greater than 0")
 	}
 
+	if s.remoteConfig.CacheSize < 0 {
+		return errors.New("stream-remote-storage-cache-size must be greater than or equal to 0")
+	}
+	if s.remoteConfig.OffloadDelay < 0 {
+		return errors.New("stream-remote-storage-offload-delay must be greater than or equal to 0")
+	}
+	if s.scrubConfig.Interval < 0 {
+		return errors.New("stream-scrub-interval must be greater than or equal to 0")
+	}
+	if s.scrubConfig.Rate < 0 {
+		return errors.New("stream-scrub-rate must be greater than or equal to 0")
+	}
+	if s.option.antiEntropyCron != "" {
+		if _, err := cron.ParseStandard(s.option.antiEntropyCron); err != nil {
+			return errors.New("stream-anti-entropy-cron is not a valid cron expression")
+		}
+	}
+
 	return nil
 }
 
@@ -255,6 +299,22 @@ func (s *standalone) PreRun(ctx context.Context) error {
 	if !strings.HasPrefix(filepath.VolumeName(s.dataPath), filepath.VolumeName(path)) {
 		obsservice.UpdatePath(s.dataPath)
 	}
+	for i := range s.option.extraDataPaths {
+		if s.option.extraDataPaths[i], err = banyandbpath.Get(s.option.extraDataPaths[i]); err != nil {
+			return err
+		}
+		obsservice.UpdatePath(s.option.extraDataPaths[i])
+	}
+	if err = storage.CheckDataPaths(s.GetDataPaths()...); err != nil {
+		return err
+	}
+	if s.remoteConfig.CachePath == "" {
+		s.remoteConfig.CachePath = filepath.Join(path, storage.RemoteCacheDir)
+	}
+	if s.option.remoteStorage, err = storage.OpenRemoteStorage(s.remoteConfig, s.dataPath); err != nil {
+		return err
+	}
+	s.option.scrubber = storage.NewScrubber(s.scrubConfig)
 	s.schemaRepo = newSchemaRepo(s.dataPath, s, node.Labels, node.NodeID)
 	if metaSvc, ok := s.metadata.(metadata.Service); ok {
 		metaSvc.RegisterDataCollector(commonv1.Catalog_CATALOG_STREAM, &s.schemaRepo)
@@ -272,6 +332,15 @@ func (s *standalone) PreRun(ctx context.Context) error {
 	if dropGroupErr := s.pipeline.Subscribe(data.TopicStreamDropGroup, &dropGroupDataListener{s: s}); dropGroupErr != nil {
 		return fmt.Errorf("failed to subscribe to drop group topic: %w", dropGroupErr)
 	}
+	if s.option.peerClient != nil {
+		catalog := &replicaCatalog{schemaRepo: &s.schemaRepo, client: s.option.peerClient, l: s.l}
+		if repairErr := s.pipeline.Subscribe(data.TopicStreamRepairShard, storage.NewRepairShardListener(catalog)); repairErr != nil {
+			return fmt.Errorf("failed to subscribe to repair shard topic: %w", repairErr)
+		}
+		if digestErr := s.pipeline.Subscribe(data.TopicStreamAntiEntropyDigest, storage.NewAntiEntropyDigestListener(catalog)); digestErr != nil {
+			return fmt.Errorf("failed to subscribe to anti-entropy digest topic: %w", digestErr)
+		}
+	}
 
 	s.localPipeline = queue.Local()
 	if err = s.pipeline.Subscribe(data.TopicSnapshot, &snapshotListener{s: s}); err != nil {
@@ -283,6 +352,9 @@ func (s *standalone) PreRun(ctx context.Context) error {
 
 	// Initialize disk monitor for forced retention
 	s.diskMonitor = storage.NewDiskMonitor(s, s.retentionConfig, s.omr)
+	if s.option.remoteStorage != nil {
+		s.diskMonitor.SetRemoteCache(s.option.remoteStorage.Cache())
+	}
 	s.diskMonitor.Start()
 
 	// For now, keep the original write throttling behavior based on high watermark
@@ -326,19 +398,29 @@ func (s *standalone) GracefulStop() {
 		s.diskMonitor.Stop()
 	}
 
+	if s.schemaRepo.indexBuilder != nil {
+		s.schemaRepo.indexBuilder.close()
+	}
 	s.schemaRepo.Close()
 	if s.localPipeline != nil {
 		s.localPipeline.GracefulStop()
 	}
+	if s.option.remoteStorage != nil {
+		if err := s.option.remoteStorage.Close(); err != nil {
+			s.l.Warn().Err(err).Msg("failed to close the remote storage")
+		}
+	}
 }
 
 // NewService returns a new service.
+// The peer client reaches the other data nodes to repair the corrupted parts from their replicas, which is disabled if it is nil.
 func NewService(
 	metadata metadata.Repo,
 	pipeline queue.Server,
 	omr observability.MetricsRegistry,
 	pm protector.Memory,
 	internalWritePipeline queue.Server,
+	peerClient queue.Client,
 ) (Service, error) {
 	return &standalone{
 		metadata:              metadata,
@@ -346,6 +428,9 @@ func NewService(
 		omr:                   omr,
 		pm:                    pm,
 		internalWritePipeline: internalWritePipeline,
+		option: option{
+			peerClient: peerClient,
+		},
 	}, nil
 }
 
@@ -382,6 +467,11 @@ func (s *standalone) CollectDataInfo(ctx context.Context, group string) (*databa
 	return s.schemaRepo.CollectDataInfo(ctx, group)
 }
 
+// ReportReadFailure starts a scrub of the group after a read failed.
+func (s *standalone) ReportReadFailure(group string) {
+	s.schemaRepo.ReportReadFailure(group)
+}
+
 func (s *standalone) CollectLiaisonInfo(_ context.Context, group string) (*databasev1.LiaisonInfo, error) {
 	info := &databasev1.LiaisonInfo{}
 	pendingWriteCount, writeErr := s.schemaRepo.collectPendingWriteInfo(group)
@@ -402,6 +492,11 @@ func (l *collectDataInfoListener) Rev(ctx context.Context, message bus.Message)
 	if !ok {
 		return bus.NewMessage(message.ID(), common.NewError("invalid data type for collect data info request"))
 	}
+	if req.Scrub {
+		if scrubErr := l.s.schemaRepo.ScrubGroup(ctx, req.Group); scrubErr != nil {
+			return bus.NewMessage(message.ID(), common.NewError("failed to scrub the group: %v", scrubErr))
+		}
+	}
 	dataInfo, collectErr := l.s.schemaRepo.CollectDataInfo(ctx, req.Group)
 	if collectErr != nil {
 		return bus.NewMessage(message.ID(), common.NewError("failed to collect data info: %v", collectErr))
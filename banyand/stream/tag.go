@@ -18,8 +18,10 @@
 package stream
 
 import (
+	"bytes"
+
 	internalencoding "github.com/apache/skywalking-banyandb/banyand/internal/encoding"
-	"github.com/apache/skywalking-banyandb/pkg/bytes"
+	pkgbytes "github.com/apache/skywalking-banyandb/pkg/bytes"
 	"github.com/apache/skywalking-banyandb/pkg/convert"
 	pkgencoding "github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/filter"
@@ -30,8 +32,6 @@ import (
 
 type tag struct {
 	uniqueValues map[string]struct{}
-	min          []byte
-	max          []byte
 	name         string
 	values       [][]byte
 	valueType    pbv1.ValueType
@@ -46,9 +46,6 @@ func (t *tag) reset() {
 	}
 	t.values = values[:0]
 
-	t.min = t.min[:0]
-	t.max = t.max[:0]
-
 	if t.uniqueValues != nil {
 		for v := range t.uniqueValues {
 			delete(t.uniqueValues, v)
@@ -88,9 +85,8 @@ func (t *tag) mustWriteTo(tm *tagMetadata, tagWriter *writer, tagFilterWriter *w
 	tm.offset = tagWriter.bytesWritten
 	tagWriter.MustWrite(bb.Buf)
 
-	if tm.valueType == pbv1.ValueTypeInt64 && (t.min != nil || t.max != nil) {
-		tm.min = t.min
-		tm.max = t.max
+	if tm.valueType == pbv1.ValueTypeInt64 {
+		t.fillZoneMap(tm)
 	}
 	isDictionaryEncoded := encodeType == pkgencoding.EncodeTypeDictionary
 	if len(t.uniqueValues) > 0 && !isDictionaryEncoded {
@@ -110,6 +106,23 @@ func (t *tag) mustWriteTo(tm *tagMetadata, tagWriter *writer, tagFilterWriter *w
 	}
 }
 
+// fillZoneMap records the range and the null count of the int values,
+// so that the queries skip the blocks out of the range without decoding them.
+func (t *tag) fillZoneMap(tm *tagMetadata) {
+	for _, v := range t.values {
+		if len(v) != 8 {
+			tm.nullCount++
+			continue
+		}
+		if len(tm.min) == 0 || bytes.Compare(v, tm.min) < 0 {
+			tm.min = v
+		}
+		if len(tm.max) == 0 || bytes.Compare(v, tm.max) > 0 {
+			tm.max = v
+		}
+	}
+}
+
 func (t *tag) mustReadValues(decoder *pkgencoding.BytesBlockDecoder, reader fs.Reader, cm tagMetadata, count uint64) {
 	t.name = cm.name
 	t.valueType = cm.valueType
@@ -128,7 +141,7 @@ func (t *tag) mustReadValues(decoder *pkgencoding.BytesBlockDecoder, reader fs.R
 	// Use shared decoding module
 	bb := bigValuePool.Generate()
 	defer bigValuePool.Release(bb)
-	bb.Buf = bytes.ResizeOver(bb.Buf[:0], int(valuesSize))
+	bb.Buf = pkgbytes.ResizeOver(bb.Buf[:0], int(valuesSize))
 	fs.MustReadData(reader, int64(cm.offset), bb.Buf)
 
 	var err error
@@ -151,7 +164,7 @@ func (t *tag) mustSeqReadValues(decoder *pkgencoding.BytesBlockDecoder, reader *
 
 	bb := bigValuePool.Generate()
 	defer bigValuePool.Release(bb)
-	bb.Buf = bytes.ResizeOver(bb.Buf[:0], int(valuesSize))
+	bb.Buf = pkgbytes.ResizeOver(bb.Buf[:0], int(valuesSize))
 	reader.mustReadFull(bb.Buf)
 
 	// Use shared decoding module
@@ -162,7 +175,7 @@ func (t *tag) mustSeqReadValues(decoder *pkgencoding.BytesBlockDecoder, reader *
 	}
 }
 
-var bigValuePool = bytes.NewBufferPool("stream-big-value")
+var bigValuePool = pkgbytes.NewBufferPool("stream-big-value")
 
 type tagFamily struct {
 	name string
@@ -30,7 +30,6 @@ import (
 	"github.com/apache/skywalking-banyandb/pkg/fs"
 	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
-	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
 	"github.com/apache/skywalking-banyandb/pkg/pool"
 )
 
@@ -144,11 +143,11 @@ func (tff tagFamilyFilter) unmarshal(tagFamilyMetadataBlock *dataBlock, metaRead
 	}
 	for _, tm := range tfm.tagMetadata {
 		tf := generateTagFilter()
-		hasMinMax := false
-		if tm.valueType == pbv1.ValueTypeInt64 {
-			tf.min = tm.min
-			tf.max = tm.max
-			hasMinMax = true
+		hasMinMax := tm.hasZoneMap()
+		if hasMinMax {
+			// tm refers to bb, which is reused below.
+			tf.min = append(tf.min[:0], tm.min...)
+			tf.max = append(tf.max[:0], tm.max...)
 		}
 		if tm.filterBlock.size > 0 {
 			bb.Buf = pkgbytes.ResizeExact(bb.Buf[:0], int(tm.filterBlock.size))
@@ -231,6 +230,10 @@ func (tfs *tagFamilyFilters) Eq(tagName string, tagValue string) bool {
 func (tfs *tagFamilyFilters) Range(tagName string, rangeOpts index.RangeOpts) (bool, error) {
 	for _, tff := range tfs.tagFamilyFilters {
 		if tf, ok := (*tff)[tagName]; ok {
+			if len(tf.min) == 0 || len(tf.max) == 0 {
+				// No zone map available, conservatively don't skip
+				continue
+			}
 			if rangeOpts.Lower != nil {
 				lower, ok := rangeOpts.Lower.(*index.FloatTermValue)
 				if !ok {
@@ -871,3 +871,39 @@ func TestTagFamilyFiltersRangeBoundaryCases(t *testing.T) {
 		})
 	}
 }
+
+func TestTagFamilyFiltersRangeWithoutZoneMap(t *testing.T) {
+	scenarios := []struct {
+		name       string
+		valueType  pbv1.ValueType
+		hasFilter  bool
+		hasMinMax  bool
+		encodeType encoding.EncodeType
+	}{
+		{
+			name:       "legacy-numeric-tag",
+			valueType:  pbv1.ValueTypeInt64,
+			hasFilter:  false,
+			hasMinMax:  false,
+			encodeType: encoding.EncodeTypePlain,
+		},
+	}
+
+	metaBuf, filterBuf, tagValueBuf := generateMetaAndFilterWithScenarios(scenarios)
+	tfs := generateTagFamilyFilters()
+	defer releaseTagFamilyFilters(tfs)
+	tfs.unmarshal(map[string]*dataBlock{
+		"default": {offset: 0, size: uint64(len(metaBuf))},
+	}, map[string]fs.Reader{
+		"default": &mockReader{data: metaBuf},
+	}, map[string]fs.Reader{
+		"default": &mockReader{data: filterBuf},
+	}, map[string]fs.Reader{
+		"default": &mockReader{data: tagValueBuf},
+	})
+
+	// the int tags without a zone map never skip the block
+	shouldSkip, err := tfs.Range("legacy-numeric-tag", index.NewIntRangeOpts(300, 400, true, true))
+	assert.NoError(t, err)
+	assert.False(t, shouldSkip)
+}
@@ -26,21 +26,33 @@ import (
 	"github.com/apache/skywalking-banyandb/pkg/pool"
 )
 
+// tagNullCountFlag marks the valueType byte of the tag metadata followed by the null count.
+// The metadata written before the null count was introduced never sets it.
+const tagNullCountFlag = 0x80
+
 type tagMetadata struct {
 	name string
 	min  []byte
 	max  []byte
 	dataBlock
+	nullCount   uint64
 	valueType   pbv1.ValueType
 	filterBlock dataBlock
 }
 
+// hasZoneMap reports whether min and max cover the non-null values of the block.
+// The int tags written before the null count was introduced only have them if they are indexed.
+func (tm *tagMetadata) hasZoneMap() bool {
+	return tm.valueType == pbv1.ValueTypeInt64 && len(tm.min) > 0 && len(tm.max) > 0
+}
+
 func (tm *tagMetadata) reset() {
 	tm.name = ""
 	tm.valueType = 0
 	tm.dataBlock.reset()
 	tm.min = nil
 	tm.max = nil
+	tm.nullCount = 0
 	tm.filterBlock.reset()
 }
 
@@ -50,12 +62,18 @@ func (tm *tagMetadata) copyFrom(src *tagMetadata) {
 	tm.dataBlock.copyFrom(&src.dataBlock)
 	tm.min = append(tm.min[:0], src.min...)
 	tm.max = append(tm.max[:0], src.max...)
+	tm.nullCount = src.nullCount
 	tm.filterBlock.copyFrom(&src.filterBlock)
 }
 
 func (tm *tagMetadata) marshal(dst []byte) []byte {
 	dst = encoding.EncodeBytes(dst, convert.StringToBytes(tm.name))
-	dst = append(dst, byte(tm.valueType))
+	if tm.valueType == pbv1.ValueTypeInt64 {
+		dst = append(dst, byte(tm.valueType)|tagNullCountFlag)
+		dst = encoding.VarUint64ToBytes(dst, tm.nullCount)
+	} else {
+		dst = append(dst, byte(tm.valueType))
+	}
 	dst = tm.dataBlock.marshal(dst)
 	dst = encoding.EncodeBytes(dst, tm.min)
 	dst = encoding.EncodeBytes(dst, tm.max)
@@ -72,8 +90,12 @@ func (tm *tagMetadata) unmarshal(src []byte) ([]byte, error) {
 	if len(src) < 1 {
 		return nil, fmt.Errorf("cannot unmarshal tagMetadata.valueType: src is too short")
 	}
-	tm.valueType = pbv1.ValueType(src[0])
+	tm.valueType = pbv1.ValueType(src[0] &^ tagNullCountFlag)
+	hasNullCount := src[0]&tagNullCountFlag != 0
 	src = src[1:]
+	if hasNullCount {
+		src, tm.nullCount = encoding.BytesToVarUint64(src)
+	}
 	src = tm.dataBlock.unmarshal(src)
 	src, tm.min, err = encoding.DecodeBytes(src)
 	if err != nil {
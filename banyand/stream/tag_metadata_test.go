@@ -22,6 +22,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
 )
 
@@ -82,6 +84,40 @@ func Test_tagMetadata_marshal(t *testing.T) {
 	assert.Equal(t, original, unmarshaled)
 }
 
+func Test_tagMetadata_marshalZoneMap(t *testing.T) {
+	original := &tagMetadata{
+		name:        "test",
+		valueType:   pbv1.ValueTypeInt64,
+		dataBlock:   dataBlock{offset: 1, size: 10},
+		filterBlock: dataBlock{offset: 2, size: 20},
+		min:         convert.Int64ToBytes(-3),
+		max:         convert.Int64ToBytes(42),
+		nullCount:   5,
+	}
+
+	unmarshaled := &tagMetadata{}
+	tail, err := unmarshaled.unmarshal(original.marshal(nil))
+	assert.NoError(t, err)
+	assert.Empty(t, tail)
+	assert.Equal(t, original, unmarshaled)
+	assert.True(t, unmarshaled.hasZoneMap())
+
+	// the non-indexed int tags written before the null count was recorded have no zone map
+	legacy := encoding.EncodeBytes(nil, []byte(original.name))
+	legacy = append(legacy, byte(original.valueType))
+	legacy = original.dataBlock.marshal(legacy)
+	legacy = encoding.EncodeBytes(legacy, nil)
+	legacy = encoding.EncodeBytes(legacy, nil)
+	legacy = original.filterBlock.marshal(legacy)
+	unmarshaled.reset()
+	tail, err = unmarshaled.unmarshal(legacy)
+	assert.NoError(t, err)
+	assert.Empty(t, tail)
+	assert.False(t, unmarshaled.hasZoneMap())
+	assert.Equal(t, original.dataBlock, unmarshaled.dataBlock)
+	assert.Equal(t, original.filterBlock, unmarshaled.filterBlock)
+}
+
 func Test_tagFamilyMetadata_reset(t *testing.T) {
 	tfm := &tagFamilyMetadata{
 		tagMetadata: []tagMetadata{
@@ -32,7 +32,9 @@ import (
 	"time"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
 	"github.com/apache/skywalking-banyandb/pkg/index"
@@ -51,13 +53,18 @@ const (
 type tsTable struct {
 	fileSystem       fs.FileSystem
 	pm               protector.Memory
+	handoffCtrl      *handoff.Controller
+	acks             *wqueue.AckTracker
 	metrics          *metrics
 	index            *elementIndex
+	buildState       *storage.IndexBuildState
 	snapshot         *snapshot
+	partDigests      map[uint64]*storage.AntiEntropyDigest
 	loopCloser       *run.Closer
 	getNodes         func() []string
 	l                *logger.Logger
 	introductions    chan *introduction
+	removals         chan *mergerIntroduction
 	p                common.Position
 	group            string
 	root             string
@@ -65,10 +72,35 @@ type tsTable struct {
 	option           option
 	curPartID        uint64
 	pendingDataCount atomic.Int64
+	partDigestsMu    sync.Mutex
 	sync.RWMutex
 	shardID common.ShardID
 }
 
+// enqueueForOfflineNodes enqueues parts for offline nodes via the handoff controller.
+func (tst *tsTable) enqueueForOfflineNodes(onlineNodes []string, partsToSync []*part) {
+	if tst.handoffCtrl == nil {
+		return
+	}
+	offlineNodes := tst.handoffCtrl.CalculateOfflineNodes(onlineNodes, tst.group, tst.shardID)
+	if len(offlineNodes) == 0 {
+		return
+	}
+	parts := make([]handoff.PartInfo, 0, len(partsToSync))
+	for _, part := range partsToSync {
+		parts = append(parts, handoff.PartInfo{
+			PartID:   part.partMetadata.ID,
+			Path:     part.path,
+			Group:    tst.group,
+			PartType: PartTypeCore,
+			ShardID:  tst.shardID,
+		})
+	}
+	if err := tst.handoffCtrl.EnqueueForOfflineNodes(offlineNodes, parts); err != nil {
+		tst.l.Warn().Err(err).Msg("handoff enqueue completed with errors")
+	}
+}
+
 func (tst *tsTable) loadSnapshot(epoch uint64, loadedParts []uint64) {
 	parts := tst.mustReadSnapshot(epoch)
 	snp := snapshot{
@@ -116,6 +148,7 @@ func (tst *tsTable) loadSnapshot(epoch uint64, loadedParts []uint64) {
 func (tst *tsTable) startLoop(cur uint64) {
 	tst.loopCloser = run.NewCloser(1 + 3)
 	tst.introductions = make(chan *introduction)
+	tst.removals = make(chan *mergerIntroduction)
 	flushCh := make(chan *flusherIntroduction)
 	mergeCh := make(chan *mergerIntroduction)
 	introducerWatcher := make(watcher.Channel, 1)
@@ -214,6 +247,7 @@ func initTSTable(fileSystem fs.FileSystem, rootPath string, p common.Position,
 			return nil, 0, err
 		}
 		tst.index = index
+		tst.buildState = storage.LoadIndexBuildState(fileSystem, rootPath, l)
 	}
 	tst.gc.init(&tst)
 	ee := fileSystem.ReadDir(rootPath)
@@ -301,6 +335,9 @@ func (tst *tsTable) Close() error {
 		tst.loopCloser.Done()
 		tst.loopCloser.CloseThenWait()
 	}
+	if tst.acks != nil {
+		tst.acks.Close()
+	}
 	tst.Lock()
 	defer tst.Unlock()
 	tst.deleteMetrics()
@@ -319,6 +356,12 @@ func (tst *tsTable) Close() error {
 }
 
 func (tst *tsTable) mustAddMemPart(mp *memPart) {
+	tst.mustAddTrackedMemPart(mp, false)
+}
+
+// mustAddTrackedMemPart adds the mem part. If track is set, it returns the waiter of the write being synced to the data nodes,
+// which is nil if the table doesn't sync. The introducer tracks the write, so no sync covers the mem part before the write is tracked.
+func (tst *tsTable) mustAddTrackedMemPart(mp *memPart, track bool) *wqueue.AckWaiter {
 	p := openMemPart(mp)
 
 	ind := generateIntroduction()
@@ -326,6 +369,7 @@ func (tst *tsTable) mustAddMemPart(mp *memPart) {
 	ind.applied = make(chan struct{})
 	ind.memPart = newPartWrapper(mp, p)
 	ind.memPart.p.partMetadata.ID = atomic.AddUint64(&tst.curPartID, 1)
+	ind.track = track
 	startTime := time.Now()
 	totalCount := mp.partMetadata.TotalCount
 	tst.addPendingDataCount(int64(totalCount))
@@ -333,24 +377,29 @@ func (tst *tsTable) mustAddMemPart(mp *memPart) {
 	case tst.introductions <- ind:
 	case <-tst.loopCloser.CloseNotify():
 		tst.addPendingDataCount(-int64(totalCount))
-		return
+		return nil
 	}
+	var waiter *wqueue.AckWaiter
 	select {
 	case <-ind.applied:
+		waiter = ind.waiter
 	case <-tst.loopCloser.CloseNotify():
 	}
 	tst.incTotalWritten(int(totalCount))
 	tst.incTotalBatch(1)
 	tst.incTotalBatchIntroLatency(time.Since(startTime).Seconds())
+	return waiter
 }
 
 func (tst *tsTable) mustAddElements(es *elements) {
-	tst.mustAddElementsWithSegmentID(es, 0, nil)
+	tst.mustAddElementsWithSegmentID(es, 0, nil, false)
 }
 
-func (tst *tsTable) mustAddElementsWithSegmentID(es *elements, segmentID int64, seriesMetadata []byte) {
+// mustAddElementsWithSegmentID adds the elements as a mem part.
+// If track is set, it returns the waiter of the write being synced to the data nodes, which is nil if the table doesn't sync.
+func (tst *tsTable) mustAddElementsWithSegmentID(es *elements, segmentID int64, seriesMetadata []byte, track bool) *wqueue.AckWaiter {
 	if len(es.seriesIDs) == 0 {
-		return
+		return nil
 	}
 
 	mp := generateMemPart()
@@ -363,7 +412,7 @@ func (tst *tsTable) mustAddElementsWithSegmentID(es *elements, segmentID int64,
 			logger.Panicf("cannot write series metadata to buffer: %s", err)
 		}
 	}
-	tst.mustAddMemPart(mp)
+	return tst.mustAddTrackedMemPart(mp, track)
 }
 
 type tstIter struct {
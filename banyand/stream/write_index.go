@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/bus"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/index"
@@ -95,6 +97,25 @@ func (s *seriesIndexCallback) Rev(_ context.Context, message bus.Message) (resp
 	}
 	defer segment.DecRef()
 
+	documents, rejected, checkErr := storage.AdmitSeriesDocuments(segment, documents, func(subject string) (storage.SeriesLimits, int) {
+		stm, ok := s.schemaRepo.loadStream(&commonv1.Metadata{Group: group, Name: subject})
+		if !ok {
+			return storage.SeriesLimits{}, 0
+		}
+		return s.schemaRepo.seriesLimits(stm.GetSchema())
+	})
+	if checkErr != nil {
+		s.l.Warn().Err(checkErr).Str("group", group).Msg("failed to check some series against the series limits")
+	}
+	if len(rejected) > 0 {
+		// The liaison drops the data of the rejected series before queuing them
+		s.l.Warn().Str("group", group).Int("rejected", len(rejected)).Msg("reject the series exceeding the series limits")
+		resp = bus.NewMessage(message.ID(), storage.NewSeriesIndexWriteResponse(rejected))
+	}
+	if len(documents) == 0 {
+		return
+	}
+
 	// Insert all documents into the segment's index
 	if err := segment.IndexDB().Insert(documents); err != nil {
 		s.l.Error().Err(err).Str("group", group).Int("documentCount", len(documents)).Msg("failed to insert documents to index")
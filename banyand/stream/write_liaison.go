@@ -29,6 +29,8 @@ import (
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
 	streamv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/stream/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	obsservice "github.com/apache/skywalking-banyandb/banyand/observability/services"
 	"github.com/apache/skywalking-banyandb/banyand/queue"
 	"github.com/apache/skywalking-banyandb/pkg/bus"
@@ -44,9 +46,12 @@ type writeQueueCallback struct {
 	schemaRepo          *schemaRepo
 	tire2Client         queue.Client
 	maxDiskUsagePercent int
+	ackTimeout          time.Duration
 }
 
-func setUpWriteQueueCallback(l *logger.Logger, schemaRepo *schemaRepo, maxDiskUsagePercent int, tire2Client queue.Client) bus.MessageListener {
+func setUpWriteQueueCallback(l *logger.Logger, schemaRepo *schemaRepo, maxDiskUsagePercent int, tire2Client queue.Client,
+	ackTimeout time.Duration,
+) bus.MessageListener {
 	if maxDiskUsagePercent > 100 {
 		maxDiskUsagePercent = 100
 	}
@@ -55,6 +60,7 @@ func setUpWriteQueueCallback(l *logger.Logger, schemaRepo *schemaRepo, maxDiskUs
 		schemaRepo:          schemaRepo,
 		maxDiskUsagePercent: maxDiskUsagePercent,
 		tire2Client:         tire2Client,
+		ackTimeout:          ackTimeout,
 	}
 }
 
@@ -70,6 +76,42 @@ func (w *writeQueueCallback) CheckHealth() *common.Error {
 	return common.NewErrorWithStatus(modelv1.Status_STATUS_DISK_FULL, "disk usage is too high, stop writing")
 }
 
+// writeSeriesIndex sends the series documents of the table to the data nodes of its shard,
+// and returns the series rejected by the series limits of any data node.
+func (w *writeQueueCallback) writeSeriesIndex(ctx context.Context, group string, nodes []string, es *elementsInTable) map[common.SeriesID]struct{} {
+	if len(nodes) == 0 || len(es.seriesDocs.docs) == 0 {
+		return nil
+	}
+	seriesDocData, marshalErr := es.seriesDocs.docs.Marshal()
+	if marshalErr != nil {
+		w.l.Error().Err(marshalErr).Uint32("shardID", uint32(es.shardID)).Msg("failed to marshal series documents")
+		return nil
+	}
+	// Encode group name, start timestamp from timeRange, and prepend to docData
+	combinedData := make([]byte, 0, len(seriesDocData)+len(group)+8)
+	combinedData = encoding.EncodeBytes(combinedData, convert.StringToBytes(group))
+	combinedData = encoding.Int64ToBytes(combinedData, es.timeRange.Start.UnixNano())
+	combinedData = append(combinedData, seriesDocData...)
+
+	// Send to all nodes for this shard
+	var rejected map[common.SeriesID]struct{}
+	for _, node := range nodes {
+		message := bus.NewMessageWithNode(bus.MessageID(time.Now().UnixNano()), node, combinedData)
+		future, publishErr := w.tire2Client.Publish(ctx, data.TopicStreamSeriesIndexWrite, message)
+		if publishErr != nil {
+			w.l.Error().Err(publishErr).Str("node", node).Uint32("shardID", uint32(es.shardID)).Msg("failed to publish series index to node")
+			continue
+		}
+		resp, err := future.Get()
+		if err != nil {
+			w.l.Error().Err(err).Str("node", node).Uint32("shardID", uint32(es.shardID)).Msg("failed to get response from publish")
+			continue
+		}
+		rejected = storage.CollectRejectedSeries(rejected, resp)
+	}
+	return rejected
+}
+
 func (w *writeQueueCallback) handle(dst map[string]*elementsInQueue, writeEvent *streamv1.InternalWriteRequest,
 	metadata *commonv1.Metadata, spec []*streamv1.TagFamilySpec,
 ) (map[string]*elementsInQueue, error) {
@@ -90,6 +132,7 @@ func (w *writeQueueCallback) handle(dst map[string]*elementsInQueue, writeEvent
 	if err != nil {
 		return nil, err
 	}
+	et.addMessage(et.elements.seriesIDs[len(et.elements.seriesIDs)-1], writeEvent.Request.GetMessageId())
 	return dst, nil
 }
 
@@ -160,6 +203,10 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 	groups := make(map[string]*elementsInQueue)
 	var metadata *commonv1.Metadata
 	var spec []*streamv1.TagFamilySpec
+	var rejectedSeries int
+	var rejectedMessages map[uint64]modelv1.Status
+	var messageIDs []uint64
+	var acks []wqueue.PendingAck
 	for i := range events {
 		var writeEvent *streamv1.InternalWriteRequest
 		switch e := events[i].(type) {
@@ -188,11 +235,29 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 			continue
 		}
 		groups = newGroups
+		messageIDs = append(messageIDs, req.GetMessageId())
 	}
 	for groupName := range groups {
 		g := groups[groupName]
+		requiredAcks := 0
+		if w.ackTimeout > 0 {
+			requiredAcks = w.schemaRepo.requiredAcks(groupName)
+		}
 		for j := range g.tables {
 			es := g.tables[j]
+			// The data nodes check the series limits while writing the series index,
+			// so the series index is written before the elements are queued.
+			nodes := g.queue.GetNodes(es.shardID)
+			if len(nodes) == 0 {
+				w.l.Warn().Uint32("shardID", uint32(es.shardID)).Msg("no nodes found for shard")
+			}
+			if rejected := w.writeSeriesIndex(ctx, g.name, nodes, es); len(rejected) > 0 {
+				rejectedSeries += len(rejected)
+				rejectedMessages = storage.CollectRejectedMessages(rejectedMessages, es.messageIDs, rejected)
+				if es.elements != nil {
+					es.dropSeries(rejected)
+				}
+			}
 			// Marshal series metadata for persistence in part folder
 			var seriesMetadataBytes []byte
 			if len(es.seriesDocs.docs) > 0 {
@@ -204,41 +269,17 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 				}
 			}
 			if es.tsTable != nil && es.elements != nil {
-				es.tsTable.mustAddElementsWithSegmentID(es.elements, es.timeRange.Start.UnixNano(), seriesMetadataBytes)
-				releaseElements(es.elements)
-			}
-			// Get nodes for this shard
-			nodes := g.queue.GetNodes(es.shardID)
-			if len(nodes) == 0 {
-				w.l.Warn().Uint32("shardID", uint32(es.shardID)).Msg("no nodes found for shard")
-				continue
-			}
-			// Process series documents independently
-			if len(seriesMetadataBytes) > 0 {
-				// Encode group name, start timestamp from timeRange, and prepend to docData
-				combinedData := make([]byte, 0, len(seriesMetadataBytes)+len(g.name)+8)
-				combinedData = encoding.EncodeBytes(combinedData, convert.StringToBytes(g.name))
-				combinedData = encoding.Int64ToBytes(combinedData, es.timeRange.Start.UnixNano())
-				combinedData = append(combinedData, seriesMetadataBytes...)
-
-				// Send to all nodes for this shard
-				for _, node := range nodes {
-					message := bus.NewMessageWithNode(bus.MessageID(time.Now().UnixNano()), node, combinedData)
-					future, publishErr := w.tire2Client.Publish(ctx, data.TopicStreamSeriesIndexWrite, message)
-					if publishErr != nil {
-						w.l.Error().Err(publishErr).Str("node", node).Uint32("shardID", uint32(es.shardID)).Msg("failed to publish series index to node")
-						continue
-					}
-					_, err := future.Get()
-					if err != nil {
-						w.l.Error().Err(err).Str("node", node).Uint32("shardID", uint32(es.shardID)).Msg("failed to get response from publish")
-						continue
+				if es.elements.Len() > 0 {
+					waiter := es.tsTable.mustAddElementsWithSegmentID(es.elements, es.timeRange.Start.UnixNano(), seriesMetadataBytes, requiredAcks > 0)
+					if waiter != nil {
+						acks = append(acks, wqueue.PendingAck{Waiter: waiter, Group: groupName, Required: requiredAcks, MessageIDs: es.messages()})
 					}
 				}
+				releaseElements(es.elements)
 			}
 
 			// Process documents independently
-			if len(es.docs) > 0 {
+			if len(nodes) > 0 && len(es.docs) > 0 {
 				docData, marshalErr := es.docs.Marshal()
 				if marshalErr != nil {
 					w.l.Error().Err(marshalErr).Uint32("shardID", uint32(es.shardID)).Msg("failed to marshal documents")
@@ -267,5 +308,20 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 			}
 		}
 	}
+	// The elements of the rejected series are dropped before being queued,
+	// and the others are still waited for to report whether they are persisted by enough replicas.
+	var rejectedErr *common.Error
+	if rejectedSeries > 0 {
+		w.l.Warn().Int("series", rejectedSeries).Msg("data nodes reject the series exceeding the series limits")
+		rejectedErr = common.NewErrorWithMessageStatuses(modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED,
+			fmt.Sprintf("%d series are rejected by the series limits", rejectedSeries), rejectedMessages)
+	}
+	degradedErr := wqueue.WaitAcks(ctx, acks, w.ackTimeout)
+	if degradedErr != nil {
+		w.l.Warn().Err(degradedErr).Msg("the elements are persisted by fewer replicas than the write consistency")
+	}
+	if ce := common.MergeErrors(messageIDs, rejectedErr, degradedErr); ce != nil {
+		return bus.NewMessage(message.ID(), ce)
+	}
 	return
 }
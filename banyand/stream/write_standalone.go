@@ -20,6 +20,7 @@ package stream
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -85,6 +86,9 @@ func (w *writeCallback) handle(dst map[string]*elementsInGroup, writeEvent *stre
 	if err != nil {
 		return nil, err
 	}
+	if err = w.admitSeries(et.segment, metadata, writeEvent); err != nil {
+		return nil, err
+	}
 	err = processElements(w.schemaRepo, et.elements, writeEvent, ts, &et.docs, &et.seriesDocs, metadata, spec)
 	if err != nil {
 		return nil, err
@@ -92,6 +96,28 @@ func (w *writeCallback) handle(dst map[string]*elementsInGroup, writeEvent *stre
 	return dst, nil
 }
 
+// admitSeries checks the series of the element against the series limits of the stream.
+func (w *writeCallback) admitSeries(segment storage.Segment[*tsTable, option], metadata *commonv1.Metadata,
+	writeEvent *streamv1.InternalWriteRequest,
+) error {
+	stm, ok := w.schemaRepo.loadStream(metadata)
+	if !ok {
+		return nil
+	}
+	limits, entitySize := w.schemaRepo.seriesLimits(stm.GetSchema())
+	if !limits.Enabled() {
+		return nil
+	}
+	series := &pbv1.Series{
+		Subject:      metadata.Name,
+		EntityValues: writeEvent.EntityValues,
+	}
+	if err := series.Marshal(); err != nil {
+		return fmt.Errorf("cannot marshal series: %w", err)
+	}
+	return segment.AdmitSeries(series, entitySize, limits)
+}
+
 func (w *writeCallback) prepareElementsInGroup(dst map[string]*elementsInGroup, metadata *commonv1.Metadata, ts int64) (*elementsInGroup, error) {
 	gn := metadata.Group
 	tsdb, err := w.schemaRepo.loadTSDB(gn)
@@ -241,12 +267,8 @@ func processElements(schemaRepo *schemaRepo, elements *elements, writeEvent *str
 
 			indexed := false
 			if r, ok := tfr[t.Name]; ok && tagValue != pbv1.NullTagValue {
-				if r.GetType() == databasev1.IndexRule_TYPE_INVERTED {
-					fields = appendField(fields, index.FieldKey{
-						IndexRuleID: r.GetMetadata().GetId(),
-						Analyzer:    r.Analyzer,
-						SeriesID:    series.ID,
-					}, t.Type, tagValue, r.GetNoSort())
+				if isElementIndexRule(r) {
+					fields = appendElementIndexFields(fields, r, series.ID, t.Type, tagValue)
 				} else if r.GetType() == databasev1.IndexRule_TYPE_SKIPPING {
 					indexed = true
 				}
@@ -296,6 +318,8 @@ func (w *writeCallback) Rev(_ context.Context, message bus.Message) (resp bus.Me
 	groups := make(map[string]*elementsInGroup)
 	var metadata *commonv1.Metadata
 	var spec []*streamv1.TagFamilySpec
+	var limitErr error
+	var rejected map[uint64]modelv1.Status
 	for i := range events {
 		var writeEvent *streamv1.InternalWriteRequest
 		switch e := events[i].(type) {
@@ -319,6 +343,14 @@ func (w *writeCallback) Rev(_ context.Context, message bus.Message) (resp bus.Me
 			spec = req.GetTagFamilySpec()
 		}
 		newGroups, handleErr := w.handle(groups, writeEvent, metadata, spec)
+		if errors.Is(handleErr, storage.ErrSeriesLimitExceeded) {
+			limitErr = handleErr
+			if rejected == nil {
+				rejected = make(map[uint64]modelv1.Status)
+			}
+			rejected[req.GetMessageId()] = modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED
+			continue
+		}
 		if handleErr != nil {
 			w.l.Error().Err(handleErr).Msg("cannot handle write event")
 			continue
@@ -350,6 +382,12 @@ func (w *writeCallback) Rev(_ context.Context, message bus.Message) (resp bus.Me
 		}
 		g.tsdb.Tick(g.latestTS)
 	}
+	if len(rejected) > 0 {
+		// Only the writes of the rejected elements are reported, the others in the batch succeed.
+		w.l.Warn().Err(limitErr).Int("rejected", len(rejected)).Msg("reject the elements exceeding the series limits")
+		return bus.NewMessage(message.ID(), common.NewErrorWithMessageStatuses(modelv1.Status_STATUS_SERIES_LIMIT_EXCEEDED,
+			fmt.Sprintf("%d elements are rejected: %v", len(rejected), limitErr), rejected))
+	}
 	return
 }
 
@@ -431,6 +469,26 @@ func encodeTagValue(name string, tagType databasev1.TagType, tagVal *modelv1.Tag
 	return tv
 }
 
+// isElementIndexRule reports whether the tags of the rule are indexed by the element index.
+func isElementIndexRule(r *databasev1.IndexRule) bool {
+	return r.GetType() == databasev1.IndexRule_TYPE_INVERTED || r.GetType() == databasev1.IndexRule_TYPE_TREE
+}
+
+func appendElementIndexFields(dest []index.Field, r *databasev1.IndexRule, seriesID common.SeriesID,
+	tagType databasev1.TagType, tagVal *modelv1.TagValue,
+) []index.Field {
+	fieldKey := index.FieldKey{
+		IndexRuleID: r.GetMetadata().GetId(),
+		Analyzer:    r.Analyzer,
+		SeriesID:    seriesID,
+	}
+	dest = appendField(dest, fieldKey, tagType, tagVal, r.GetNoSort())
+	if r.GetType() == databasev1.IndexRule_TYPE_TREE && tagType == databasev1.TagType_TAG_TYPE_STRING && tagVal.GetStr() != nil {
+		dest = append(dest, index.NewTreeFields(fieldKey, tagVal.GetStr().GetValue())...)
+	}
+	return dest
+}
+
 func appendField(dest []index.Field, fieldKey index.FieldKey, tagType databasev1.TagType, tagVal *modelv1.TagValue, noSort bool) []index.Field {
 	switch tagType {
 	case databasev1.TagType_TAG_TYPE_INT:
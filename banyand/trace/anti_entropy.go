@@ -0,0 +1,270 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	tracev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/trace/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/banyand/metadata/schema"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+	"github.com/apache/skywalking-banyandb/pkg/query/model"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+// antiEntropySeriesID returns the series placing the spans of the trace in the anti-entropy buckets.
+func antiEntropySeriesID(traceID string) common.SeriesID {
+	return common.SeriesID(convert.HashStr(traceID))
+}
+
+// loadAntiEntropyTraces returns the traces of the group ordered by name.
+// The spans carry neither their timestamps nor their schemas, so both are derived from the traces storing the tags of their blocks.
+func (sr *schemaRepo) loadAntiEntropyTraces(group string) ([]*trace, error) {
+	schemas, err := sr.metadata.TraceRegistry().ListTrace(context.Background(), schema.ListOpt{Group: group})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the traces of group %s: %w", group, err)
+	}
+	traces := make([]*trace, 0, len(schemas))
+	for _, s := range schemas {
+		if t, ok := sr.loadTrace(s.GetMetadata()); ok {
+			traces = append(traces, t)
+		}
+	}
+	sort.Slice(traces, func(i, j int) bool {
+		return traces[i].name < traces[j].name
+	})
+	return traces, nil
+}
+
+// matchAntiEntropyTrace returns the first trace storing every tag of the block, along with the index of its timestamp tag in the block.
+// Groups usually hold a single trace, so the replicas only pick the first one if several traces store the same tags.
+func matchAntiEntropyTrace(traces []*trace, b *block) (*trace, int) {
+	for _, t := range traces {
+		tsIdx := -1
+		matched := true
+		for i := range b.tags {
+			if b.tags[i].name == t.schema.TimestampTagName {
+				tsIdx = i
+			}
+			if !storesTag(t, b.tags[i].name) {
+				matched = false
+				break
+			}
+		}
+		if matched && tsIdx >= 0 {
+			return t, tsIdx
+		}
+	}
+	return nil, -1
+}
+
+// storesTag reports whether the blocks of the trace hold the tag, which excludes the trace and the span IDs.
+func storesTag(t *trace, name string) bool {
+	if name == t.schema.TraceIdTagName || name == t.schema.SpanIdTagName {
+		return false
+	}
+	for _, ts := range t.schema.GetTags() {
+		if ts.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// antiEntropySpan locates a span visited for the anti-entropy.
+type antiEntropySpan struct {
+	t       *trace
+	b       *block
+	traceID string
+	i       int
+}
+
+// antiEntropyDigest returns the digest of the spans of the table in the segment.
+// The digests of the parts are cached, since the parts are immutable.
+func (tst *tsTable) antiEntropyDigest(timeRange timestamp.TimeRange, traces []*trace) (*storage.AntiEntropyDigest, error) {
+	digest := &storage.AntiEntropyDigest{}
+	snp := tst.currentSnapshot()
+	if snp == nil {
+		return digest, nil
+	}
+	defer snp.decRef()
+	tst.partDigestsMu.Lock()
+	defer tst.partDigestsMu.Unlock()
+	digests := make(map[uint64]*storage.AntiEntropyDigest, len(snp.parts))
+	for _, pw := range snp.parts {
+		d, ok := tst.partDigests[pw.ID()]
+		if !ok {
+			d = &storage.AntiEntropyDigest{}
+			if err := visitAntiEntropyRows(pw.p, timeRange, traces, nil, func(bucket int, hash uint64, _ antiEntropySpan) {
+				d.Add(bucket, hash)
+			}); err != nil {
+				return nil, fmt.Errorf("cannot compute the digest of %s: %w", pw.p, err)
+			}
+		}
+		digests[pw.ID()] = d
+		digest.Merge(d)
+	}
+	tst.partDigests = digests
+	return digest, nil
+}
+
+// antiEntropyRowHashes returns the hashes of the spans of the table in the buckets, leaving out the spans of the excluded parts.
+func (tst *tsTable) antiEntropyRowHashes(timeRange timestamp.TimeRange, traces []*trace, set *storage.AntiEntropyBucketSet,
+	excludedParts map[uint64]struct{},
+) ([]uint64, error) {
+	snp := tst.currentSnapshot()
+	if snp == nil {
+		return nil, nil
+	}
+	defer snp.decRef()
+	var hashes []uint64
+	for _, pw := range snp.parts {
+		if _, ok := excludedParts[pw.ID()]; ok {
+			continue
+		}
+		if err := visitAntiEntropyRows(pw.p, timeRange, traces, set, func(_ int, hash uint64, _ antiEntropySpan) {
+			hashes = append(hashes, hash)
+		}); err != nil {
+			return nil, fmt.Errorf("cannot hash the spans of %s: %w", pw.p, err)
+		}
+	}
+	return hashes, nil
+}
+
+// visitAntiEntropyRows visits the bucket, the hash and the location of every span of the part in the buckets of the set.
+// All the buckets are visited if the set is nil. The spans following the schema of no trace are skipped.
+func visitAntiEntropyRows(p *part, timeRange timestamp.TimeRange, traces []*trace, set *storage.AntiEntropyBucketSet,
+	visit func(bucket int, hash uint64, span antiEntropySpan),
+) error {
+	if p.partMetadata.TotalCount == 0 {
+		return nil
+	}
+	start, end := timeRange.Start.UnixNano(), timeRange.End.UnixNano()
+	decoder := generateColumnValuesDecoder()
+	defer releaseColumnValuesDecoder(decoder)
+	b := generateBlock()
+	defer releaseBlock(b)
+	pmi := generatePartMergeIter()
+	defer releasePartMergeIter(pmi)
+	pmi.mustInitFromPart(p)
+	for pmi.nextBlockMetadata() {
+		bm := &pmi.block.bm
+		if bm.timestamps.max < start || bm.timestamps.min >= end {
+			continue
+		}
+		sid := antiEntropySeriesID(bm.traceID)
+		if set != nil && !set.Overlaps(timeRange, sid, bm.timestamps.min, bm.timestamps.max) {
+			continue
+		}
+		// The values of the block refer to the buffer of the decoder until the next block is read.
+		decoder.Reset()
+		mustReadAntiEntropyBlock(b, decoder, p, bm)
+		t, tsIdx := matchAntiEntropyTrace(traces, b)
+		if t == nil {
+			continue
+		}
+		for i := range b.spans {
+			v := b.tags[tsIdx].values[i]
+			if v == nil {
+				continue
+			}
+			ts := convert.BytesToInt64(v)
+			bucket := storage.AntiEntropyBucket(timeRange, sid, ts)
+			if bucket < 0 || (set != nil && !set[bucket]) {
+				continue
+			}
+			visit(bucket, storage.AntiEntropyRowHash(sid, ts, convert.HashStr(b.spanIDs[i])), antiEntropySpan{t: t, b: b, traceID: bm.traceID, i: i})
+		}
+	}
+	return pmi.error()
+}
+
+// mustReadAntiEntropyBlock reads the spans and all the tags of the block.
+func mustReadAntiEntropyBlock(b *block, decoder *encoding.BytesBlockDecoder, p *part, bm *blockMetadata) {
+	names := make([]string, 0, len(bm.tags))
+	for name := range bm.tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	projected := *bm
+	projected.tagProjection = &model.TagProjection{Names: names}
+	b.mustReadFrom(decoder, p, projected)
+}
+
+// buildAntiEntropyTraces rebuilds the spans of the parts in the buckets whose hashes are unknown into the table,
+// along with the requests of their secondary indexes and the documents of their series.
+func (sr *schemaRepo) buildAntiEntropyTraces(dst *tracesInTable, parts []*part, timeRange timestamp.TimeRange, traces []*trace,
+	set *storage.AntiEntropyBucketSet, known map[uint64]struct{},
+) error {
+	for _, p := range parts {
+		if !set.OverlapsTime(timeRange, p.partMetadata.MinTimestamp, p.partMetadata.MaxTimestamp) {
+			continue
+		}
+		var buildErr error
+		if err := visitAntiEntropyRows(p, timeRange, traces, set, func(_ int, hash uint64, span antiEntropySpan) {
+			if _, ok := known[hash]; ok || buildErr != nil {
+				return
+			}
+			// The same span in several parts is pushed once.
+			known[hash] = struct{}{}
+			req := &tracev1.InternalWriteRequest{Request: antiEntropyWriteRequest(span)}
+			buildErr = processTraces(sr, dst, req, span.t.schema.GetMetadata(), nil)
+		}); err != nil {
+			return fmt.Errorf("cannot read the spans of %s: %w", p, err)
+		}
+		if buildErr != nil {
+			return fmt.Errorf("cannot rebuild the spans of %s: %w", p, buildErr)
+		}
+	}
+	return nil
+}
+
+// antiEntropyWriteRequest rebuilds the write request of the span, whose tags follow the order of the trace's schema.
+func antiEntropyWriteRequest(span antiEntropySpan) *tracev1.WriteRequest {
+	t, b, i := span.t, span.b, span.i
+	tags := make([]*modelv1.TagValue, len(t.schema.GetTags()))
+	for j, ts := range t.schema.GetTags() {
+		switch ts.GetName() {
+		case t.schema.TraceIdTagName:
+			tags[j] = strTagValue(span.traceID)
+		case t.schema.SpanIdTagName:
+			tags[j] = strTagValue(b.spanIDs[i])
+		default:
+			tags[j] = pbv1.NullTagValue
+			for k := range b.tags {
+				if b.tags[k].name == ts.GetName() {
+					tags[j] = mustDecodeTagValue(b.tags[k].valueType, b.tags[k].values[i])
+					break
+				}
+			}
+		}
+	}
+	return &tracev1.WriteRequest{
+		Metadata: t.schema.GetMetadata(),
+		Tags:     tags,
+		Span:     bytes.Clone(b.spans[i]),
+	}
+}
@@ -0,0 +1,134 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package trace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+var antiEntropyTestTrace = &trace{
+	name: "sw",
+	schema: &databasev1.Trace{
+		Metadata: &commonv1.Metadata{Group: "default", Name: "sw"},
+		Tags: []*databasev1.TraceTagSpec{
+			{Name: "trace_id", Type: databasev1.TagType_TAG_TYPE_STRING},
+			{Name: "span_id", Type: databasev1.TagType_TAG_TYPE_STRING},
+			{Name: "timestamp", Type: databasev1.TagType_TAG_TYPE_TIMESTAMP},
+			{Name: "service", Type: databasev1.TagType_TAG_TYPE_STRING},
+			{Name: "duration", Type: databasev1.TagType_TAG_TYPE_INT},
+		},
+		TraceIdTagName:   "trace_id",
+		SpanIdTagName:    "span_id",
+		TimestampTagName: "timestamp",
+	},
+}
+
+func newAntiEntropyTestTraces(ts int64, spanIDs ...string) *traces {
+	tt := &traces{}
+	for i, spanID := range spanIDs {
+		tt.traceIDs = append(tt.traceIDs, "trace"+spanID)
+		tt.timestamps = append(tt.timestamps, ts)
+		tt.tags = append(tt.tags, []*tagValue{
+			{tag: "timestamp", valueType: pbv1.ValueTypeTimestamp, value: convert.Int64ToBytes(ts)},
+			{tag: "service", valueType: pbv1.ValueTypeStr, value: []byte("svc")},
+			{tag: "duration", valueType: pbv1.ValueTypeInt64, value: convert.Int64ToBytes(int64(i))},
+		})
+		tt.spans = append(tt.spans, []byte("span"+spanID))
+		tt.spanIDs = append(tt.spanIDs, spanID)
+	}
+	return tt
+}
+
+func newAntiEntropyTestPart(t *testing.T, tt *traces) *part {
+	mp := generateMemPart()
+	mp.mustInitFromTraces(tt)
+	t.Cleanup(func() { releaseMemPart(mp) })
+	return openMemPart(mp)
+}
+
+func TestTSTableAntiEntropy(t *testing.T) {
+	timeRange := timestamp.NewSectionTimeRange(time.Unix(0, 0), time.Unix(0, 16))
+	schemas := []*trace{antiEntropyTestTrace}
+	p1, p2 := newAntiEntropyTestPart(t, newAntiEntropyTestTraces(1, "a", "b")), newAntiEntropyTestPart(t, newAntiEntropyTestTraces(2, "c"))
+	p1.partMetadata.ID, p2.partMetadata.ID = 1, 2
+	tst := &tsTable{snapshot: &snapshot{parts: []*partWrapper{newPartWrapper(nil, p1), newPartWrapper(nil, p2)}, ref: 1}}
+
+	digest, err := tst.antiEntropyDigest(timeRange, schemas)
+	require.NoError(t, err)
+	assert.Len(t, tst.partDigests, 2)
+	want := &storage.AntiEntropyDigest{}
+	for _, p := range []*part{p1, p2} {
+		require.NoError(t, visitAntiEntropyRows(p, timeRange, schemas, nil, func(bucket int, hash uint64, _ antiEntropySpan) {
+			want.Add(bucket, hash)
+		}))
+	}
+	assert.Equal(t, want, digest)
+	assert.NotEqual(t, &storage.AntiEntropyDigest{}, digest)
+
+	// The spans are placed by the hash of their trace IDs and their timestamp tags.
+	set := storage.NewAntiEntropyBucketSet([]uint32{uint32(storage.AntiEntropyBucket(timeRange, antiEntropySeriesID("tracec"), 2))})
+	hashes, err := tst.antiEntropyRowHashes(timeRange, schemas, set, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{storage.AntiEntropyRowHash(antiEntropySeriesID("tracec"), 2, convert.HashStr("c"))}, hashes)
+
+	// The spans of the excluded parts are left out of the hashes.
+	hashes, err = tst.antiEntropyRowHashes(timeRange, schemas, set, map[uint64]struct{}{2: {}})
+	require.NoError(t, err)
+	assert.Empty(t, hashes)
+
+	// The spans following the schema of no trace are skipped.
+	other := &trace{name: "other", schema: &databasev1.Trace{
+		Tags:             []*databasev1.TraceTagSpec{{Name: "timestamp", Type: databasev1.TagType_TAG_TYPE_TIMESTAMP}},
+		TimestampTagName: "timestamp",
+	}}
+	require.NoError(t, visitAntiEntropyRows(p1, timeRange, []*trace{other}, nil, func(_ int, _ uint64, _ antiEntropySpan) {
+		assert.Fail(t, "the span of an unknown schema is visited")
+	}))
+}
+
+func TestAntiEntropyWriteRequest(t *testing.T) {
+	timeRange := timestamp.NewSectionTimeRange(time.Unix(0, 0), time.Unix(0, 16))
+	p := newAntiEntropyTestPart(t, newAntiEntropyTestTraces(3, "a"))
+	visited := 0
+	require.NoError(t, visitAntiEntropyRows(p, timeRange, []*trace{antiEntropyTestTrace}, nil, func(_ int, _ uint64, span antiEntropySpan) {
+		visited++
+		assert.Same(t, antiEntropyTestTrace, span.t)
+		req := antiEntropyWriteRequest(span)
+		// The tags of the rebuilt request follow the order of the schema, so the secondary index entries are rebuilt from them.
+		require.Len(t, req.Tags, 5)
+		assert.Equal(t, "tracea", req.Tags[0].GetStr().GetValue())
+		assert.Equal(t, "a", req.Tags[1].GetStr().GetValue())
+		assert.Equal(t, int64(3), req.Tags[2].GetTimestamp().AsTime().UnixNano())
+		assert.Equal(t, "svc", req.Tags[3].GetStr().GetValue())
+		assert.Equal(t, int64(0), req.Tags[4].GetInt().GetValue())
+		assert.Equal(t, []byte("spana"), req.Span)
+		assert.Equal(t, "sw", req.Metadata.GetName())
+	}))
+	assert.Equal(t, 1, visited)
+}
@@ -0,0 +1,135 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package trace
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/skywalking-banyandb/api/data"
+	"github.com/apache/skywalking-banyandb/banyand/internal/sidx"
+	"github.com/apache/skywalking-banyandb/banyand/queue"
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+)
+
+const sidxManifestFilename = "manifest.json"
+
+// handoffCodec maps the core and sidx parts of trace to the handoff queue.
+type handoffCodec struct{}
+
+func (handoffCodec) Topic() string {
+	return data.TopicTracePartSync.String()
+}
+
+// PartSize reads the CompressedSizeBytes from the part's metadata file.
+func (handoffCodec) PartSize(fileSystem fs.FileSystem, partPath, partType string) uint64 {
+	// Core parts use metadata.json, sidx parts use manifest.json
+	metadataPath := filepath.Join(partPath, sidxManifestFilename)
+	if partType == PartTypeCore {
+		metadataPath = filepath.Join(partPath, metadataFilename)
+	}
+	metadataBytes, err := fileSystem.Read(metadataPath)
+	if err != nil {
+		return 0
+	}
+	var metadata struct {
+		CompressedSizeBytes uint64 `json:"compressedSizeBytes"`
+	}
+	if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return 0
+	}
+	return metadata.CompressedSizeBytes
+}
+
+func (handoffCodec) StreamingFileName(partType, fileName string) (string, bool) {
+	if partType == PartTypeCore {
+		switch fileName {
+		case primaryFilename:
+			return tracePrimaryName, true
+		case spansFilename:
+			return traceSpansName, true
+		case metaFilename:
+			return traceMetaName, true
+		case traceIDFilterFilename, tagTypeFilename:
+			return fileName, true
+		case metadataFilename:
+			return "", false
+		}
+
+		if strings.HasSuffix(fileName, tagsFilenameExt) {
+			tagName := strings.TrimSuffix(fileName, tagsFilenameExt)
+			return traceTagsPrefix + tagName, true
+		}
+		if strings.HasSuffix(fileName, tagsMetadataFilenameExt) {
+			tagName := strings.TrimSuffix(fileName, tagsMetadataFilenameExt)
+			return traceTagMetadataPrefix + tagName, true
+		}
+
+		return "", false
+	}
+
+	switch fileName {
+	case sidx.SidxPrimaryName + ".bin":
+		return sidx.SidxPrimaryName, true
+	case sidx.SidxDataName + ".bin":
+		return sidx.SidxDataName, true
+	case sidx.SidxKeysName + ".bin":
+		return sidx.SidxKeysName, true
+	case sidx.SidxMetaName + ".bin":
+		return sidx.SidxMetaName, true
+	case sidxManifestFilename:
+		return "", false
+	}
+
+	if strings.HasSuffix(fileName, ".td") {
+		tagName := strings.TrimSuffix(fileName, ".td")
+		return sidx.TagDataPrefix + tagName, true
+	}
+	if strings.HasSuffix(fileName, ".tm") {
+		tagName := strings.TrimSuffix(fileName, ".tm")
+		return sidx.TagMetadataPrefix + tagName, true
+	}
+	if strings.HasSuffix(fileName, ".tf") {
+		tagName := strings.TrimSuffix(fileName, ".tf")
+		return sidx.TagFilterPrefix + tagName, true
+	}
+
+	return "", false
+}
+
+// FillPartMetadata reads the metadata.json of core parts. The sidx parts carry their own manifest.
+func (handoffCodec) FillPartMetadata(fileSystem fs.FileSystem, partPath string, streamingPart *queue.StreamingPartData) {
+	if streamingPart.PartType != PartTypeCore {
+		return
+	}
+	metadataBytes, err := fileSystem.Read(filepath.Join(partPath, metadataFilename))
+	if err != nil {
+		return
+	}
+	var pm partMetadata
+	if err := json.Unmarshal(metadataBytes, &pm); err != nil {
+		return
+	}
+	streamingPart.CompressedSizeBytes = pm.CompressedSizeBytes
+	streamingPart.UncompressedSizeBytes = pm.UncompressedSpanSizeBytes
+	streamingPart.TotalCount = pm.TotalCount
+	streamingPart.BlocksCount = pm.BlocksCount
+	streamingPart.MinTimestamp = pm.MinTimestamp
+	streamingPart.MaxTimestamp = pm.MaxTimestamp
+}
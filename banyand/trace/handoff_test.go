@@ -0,0 +1,55 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/skywalking-banyandb/banyand/internal/sidx"
+)
+
+func TestHandoffCodec_StreamingFileName(t *testing.T) {
+	tests := []struct {
+		partType string
+		fileName string
+		expected string
+		include  bool
+	}{
+		{PartTypeCore, primaryFilename, tracePrimaryName, true},
+		{PartTypeCore, spansFilename, traceSpansName, true},
+		{PartTypeCore, metaFilename, traceMetaName, true},
+		{PartTypeCore, traceIDFilterFilename, traceIDFilterFilename, true},
+		{PartTypeCore, "service" + tagsFilenameExt, traceTagsPrefix + "service", true},
+		{PartTypeCore, "service" + tagsMetadataFilenameExt, traceTagMetadataPrefix + "service", true},
+		{PartTypeCore, metadataFilename, "", false},
+		{"sidx_service", sidx.SidxPrimaryName + ".bin", sidx.SidxPrimaryName, true},
+		{"sidx_service", "service.td", sidx.TagDataPrefix + "service", true},
+		{"sidx_service", "service.tf", sidx.TagFilterPrefix + "service", true},
+		{"sidx_service", sidxManifestFilename, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.partType+"/"+tt.fileName, func(t *testing.T) {
+			name, include := handoffCodec{}.StreamingFileName(tt.partType, tt.fileName)
+			assert.Equal(t, tt.include, include)
+			assert.Equal(t, tt.expected, name)
+		})
+	}
+}
@@ -20,6 +20,7 @@ package trace
 import (
 	"github.com/apache/skywalking-banyandb/banyand/internal/sidx"
 	snapshotpkg "github.com/apache/skywalking-banyandb/banyand/internal/snapshot"
+	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	"github.com/apache/skywalking-banyandb/pkg/pool"
 	"github.com/apache/skywalking-banyandb/pkg/watcher"
 )
@@ -28,12 +29,16 @@ type introduction struct {
 	memPart     *partWrapper
 	applied     chan struct{}
 	sidxReqsMap map[string]*sidx.MemPart
+	waiter      *wqueue.AckWaiter
+	track       bool
 }
 
 func (i *introduction) reset() {
 	i.memPart = nil
 	i.applied = nil
 	i.sidxReqsMap = nil
+	i.waiter = nil
+	i.track = false
 }
 
 var introductionPool = pool.Register[*introduction]("trace-introduction")
@@ -260,6 +265,11 @@ func (tst *tsTable) introduceMemPart(nextIntroduction *introduction, epoch uint6
 		}
 	}()
 
+	if nextIntroduction.track && tst.acks != nil {
+		// The write is tracked before any sync reads the snapshot holding the mem part.
+		nextIntroduction.waiter = tst.acks.Track(partID, epoch)
+	}
+
 	// Commit all atomically under single transaction lock
 	txn.Commit()
 
@@ -376,7 +386,9 @@ func (tst *tsTable) introduceMerged(nextIntroduction *mergerIntroduction, epoch
 			tst.l.Panic().Msg("current snapshot is nil")
 		}
 		nextSnp := cur.remove(epoch, nextIntroduction.merged)
-		nextSnp.parts = append(nextSnp.parts, nextIntroduction.newPart)
+		if nextIntroduction.newPart != nil {
+			nextSnp.parts = append(nextSnp.parts, nextIntroduction.newPart)
+		}
 		nextSnp.creator = nextIntroduction.creator
 		return &nextSnp
 	})
@@ -392,6 +404,14 @@ func (tst *tsTable) introduceMerged(nextIntroduction *mergerIntroduction, epoch
 		sidxTransitions = append(sidxTransitions, sidxTransition)
 		snapshotpkg.AddTransition(txn, sidxTransition)
 	}
+	if nextIntroduction.newPart == nil {
+		// A removal drops the sidx parts of the removed parts as well, since they share the IDs.
+		for _, sidxInstance := range tst.getAllSidx() {
+			sidxTransition := snapshotpkg.NewTransition(sidxInstance, sidxInstance.PrepareSynced(nextIntroduction.merged))
+			sidxTransitions = append(sidxTransitions, sidxTransition)
+			snapshotpkg.AddTransition(txn, sidxTransition)
+		}
+	}
 	defer func() {
 		for _, t := range sidxTransitions {
 			t.Release()
@@ -27,6 +27,7 @@ import (
 	"github.com/dustin/go-humanize"
 
 	"github.com/apache/skywalking-banyandb/banyand/internal/sidx"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/cgroups"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
@@ -56,6 +57,13 @@ func (tst *tsTable) mergeLoop(merges chan *mergerIntroduction, flusherNotifier w
 		select {
 		case <-tst.loopCloser.CloseNotify():
 			return
+		case removal := <-tst.removals:
+			// The removal is introduced between two merges, so no merged part brings the removed data back.
+			select {
+			case merges <- removal:
+			case <-tst.loopCloser.CloseNotify():
+				return
+			}
 		case <-ew.Watch():
 			if func() bool {
 				curSnapshot := tst.currentSnapshot()
@@ -356,6 +364,7 @@ func (tst *tsTable) mergeParts(fileSystem fs.FileSystem, closeCh <-chan struct{}
 	tf.mustWriteTraceIDFilter(fileSystem, dstPath)
 	tf.reset()
 	tt.mustWriteTagType(fileSystem, dstPath)
+	storage.MustWritePartChecksums(fileSystem, dstPath)
 	fileSystem.SyncPath(dstPath)
 	p := mustOpenFilePart(partID, root, fileSystem)
 	return newPartWrapper(nil, p), nil
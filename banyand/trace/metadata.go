@@ -26,9 +26,11 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/api/data"
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	"github.com/apache/skywalking-banyandb/api/validate"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	"github.com/apache/skywalking-banyandb/banyand/liaison/grpc"
@@ -38,6 +40,7 @@ import (
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/banyand/queue/pub"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 	"github.com/apache/skywalking-banyandb/pkg/meter"
 	resourceSchema "github.com/apache/skywalking-banyandb/pkg/schema"
@@ -61,6 +64,7 @@ type schemaRepo struct {
 	onGroupDelete func(groupName string)
 	l             *logger.Logger
 	metadata      metadata.Repo
+	ruleTracker   *storage.IndexRuleTracker
 	path          string
 	nodeID        string
 	role          databasev1.Role
@@ -68,11 +72,12 @@ type schemaRepo struct {
 
 func newSchemaRepo(path string, svc *standalone, nodeLabels map[string]string, nodeID string) schemaRepo {
 	sr := schemaRepo{
-		l:        svc.l,
-		path:     path,
-		metadata: svc.metadata,
-		nodeID:   nodeID,
-		role:     databasev1.Role_ROLE_DATA,
+		l:           svc.l,
+		path:        path,
+		metadata:    svc.metadata,
+		ruleTracker: storage.NewIndexRuleTracker(),
+		nodeID:      nodeID,
+		role:        databasev1.Role_ROLE_DATA,
 		Repository: resourceSchema.NewRepository(
 			svc.metadata,
 			svc.l,
@@ -98,7 +103,7 @@ func newLiaisonSchemaRepo(path string, svc *liaison, traceDataNodeRegistry grpc.
 		),
 	}
 	if svc.handoffCtrl != nil {
-		sr.onGroupDelete = svc.handoffCtrl.deletePartsByGroup
+		sr.onGroupDelete = svc.handoffCtrl.DeletePartsByGroup
 	}
 	sr.start()
 	return sr
@@ -230,6 +235,7 @@ func (sr *schemaRepo) OnDelete(metadata schema.Metadata) {
 		}
 	case schema.KindIndexRule:
 		if rule, ok := metadata.Spec.(*databasev1.IndexRule); ok {
+			index.RemoveUsage(rule.GetMetadata().GetId())
 			sr.SendMetadataEvent(resourceSchema.MetadataEvent{
 				Typ:      resourceSchema.EventDelete,
 				Kind:     resourceSchema.EventKindIndexRule,
@@ -287,6 +293,11 @@ func (sr *schemaRepo) CollectDataInfo(ctx context.Context, group string) (*datab
 	if segmentsErr != nil {
 		return nil, segmentsErr
 	}
+	indexRules, listErr := sr.metadata.IndexRuleRegistry().ListIndexRule(ctx, schema.ListOpt{Group: group})
+	if listErr != nil {
+		sr.l.Warn().Err(listErr).Str("group", group).Msg("failed to list index rules to collect their usages")
+	}
+	sidxSizes := make(map[string]int64, len(indexRules))
 	var segmentInfoList []*databasev1.SegmentInfo
 	var totalDataSize int64
 	for _, segment := range segments {
@@ -294,7 +305,7 @@ func (sr *schemaRepo) CollectDataInfo(ctx context.Context, group string) (*datab
 		tables, _ := segment.Tables()
 		var shardInfoList []*databasev1.ShardInfo
 		for shardIdx, table := range tables {
-			shardInfo := sr.collectShardInfo(ctx, table, uint32(shardIdx))
+			shardInfo := sr.collectShardInfo(ctx, table, uint32(shardIdx), sidxSizes)
 			shardInfoList = append(shardInfoList, shardInfo)
 			totalDataSize += shardInfo.DataSizeBytes
 		}
@@ -310,14 +321,42 @@ func (sr *schemaRepo) CollectDataInfo(ctx context.Context, group string) (*datab
 		segmentInfoList = append(segmentInfoList, segmentInfo)
 		segment.DecRef()
 	}
+	// Every index rule of traces is stored by the secondary index named after it.
+	indexRuleSizes := make(map[uint32]int64, len(indexRules))
+	for _, r := range indexRules {
+		indexRuleSizes[r.GetMetadata().GetId()] = sidxSizes[r.GetMetadata().GetName()]
+	}
 	dataInfo := &databasev1.DataInfo{
-		Node:          node,
-		SegmentInfo:   segmentInfoList,
-		DataSizeBytes: totalDataSize,
+		Node:            node,
+		SegmentInfo:     segmentInfoList,
+		DataSizeBytes:   totalDataSize,
+		IndexRuleUsages: index.CollectUsages(indexRules, indexRuleSizes),
+		ScrubStatus:     tsdb.ScrubStatus(),
 	}
 	return dataInfo, nil
 }
 
+// ReportReadFailure starts a scrub of the group after a read failed.
+func (sr *schemaRepo) ReportReadFailure(group string) {
+	tsdb, err := sr.loadTSDB(group)
+	if err != nil || tsdb == nil {
+		return
+	}
+	tsdb.ReportReadFailure()
+}
+
+// ScrubGroup starts verifying the checksums of the group's parts in the background, unless a scrub is running.
+func (sr *schemaRepo) ScrubGroup(_ context.Context, group string) error {
+	tsdb, err := sr.loadTSDB(group)
+	if err != nil {
+		return err
+	}
+	if tsdb != nil && !tsdb.Scrub() {
+		sr.l.Info().Str("group", group).Msg("the group is being scrubbed")
+	}
+	return nil
+}
+
 func (sr *schemaRepo) collectSeriesIndexInfo(segment storage.Segment[*tsTable, option]) *databasev1.SeriesIndexInfo {
 	indexDB := segment.IndexDB()
 	if indexDB == nil {
@@ -333,7 +372,7 @@ func (sr *schemaRepo) collectSeriesIndexInfo(segment storage.Segment[*tsTable, o
 	}
 }
 
-func (sr *schemaRepo) collectShardInfo(ctx context.Context, table any, shardID uint32) *databasev1.ShardInfo {
+func (sr *schemaRepo) collectShardInfo(ctx context.Context, table any, shardID uint32, sidxSizes map[string]int64) *databasev1.ShardInfo {
 	tst, ok := table.(*tsTable)
 	if !ok {
 		return &databasev1.ShardInfo{
@@ -367,7 +406,7 @@ func (sr *schemaRepo) collectShardInfo(ctx context.Context, table any, shardID u
 			partCount++
 		}
 	}
-	sidxInfo := sr.collectSidxInfo(ctx, tst)
+	sidxInfo := sr.collectSidxInfo(ctx, tst, sidxSizes)
 	return &databasev1.ShardInfo{
 		ShardId:           shardID,
 		DataCount:         int64(totalCount),
@@ -375,10 +414,12 @@ func (sr *schemaRepo) collectShardInfo(ctx context.Context, table any, shardID u
 		PartCount:         int64(partCount),
 		InvertedIndexInfo: &databasev1.InvertedIndexInfo{},
 		SidxInfo:          sidxInfo,
+		IndexBuilds:       tst.buildState.Info(),
 	}
 }
 
-func (sr *schemaRepo) collectSidxInfo(ctx context.Context, tst *tsTable) *databasev1.SIDXInfo {
+// collectSidxInfo sums up the secondary indexes of the table, and adds the disk usage of every one to sidxSizes by its name.
+func (sr *schemaRepo) collectSidxInfo(ctx context.Context, tst *tsTable, sidxSizes map[string]int64) *databasev1.SIDXInfo {
 	sidxMap := tst.sidxMap
 	if len(sidxMap) == 0 {
 		return &databasev1.SIDXInfo{
@@ -390,7 +431,7 @@ func (sr *schemaRepo) collectSidxInfo(ctx context.Context, tst *tsTable) *databa
 	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 	var totalDataCount, totalDataSize, totalPartCount int64
-	for _, sidxInstance := range sidxMap {
+	for name, sidxInstance := range sidxMap {
 		stats, statsErr := sidxInstance.Stats(timeoutCtx)
 		if statsErr != nil {
 			continue
@@ -399,6 +440,7 @@ func (sr *schemaRepo) collectSidxInfo(ctx context.Context, tst *tsTable) *databa
 			totalDataCount += stats.ElementCount
 			totalDataSize += stats.DiskUsageBytes
 			totalPartCount += stats.PartCount
+			sidxSizes[name] += stats.DiskUsageBytes
 		}
 	}
 	return &databasev1.SIDXInfo{
@@ -486,6 +528,16 @@ func (sr *schemaRepo) collectPendingSyncInfo(groupName string) (partCount int64,
 	return partCount, totalSizeBytes, nil
 }
 
+// requiredAcks returns the number of the replicas which must persist the writes to the group before the liaison acknowledges them.
+func (sr *schemaRepo) requiredAcks(groupName string) int {
+	g, ok := sr.LoadGroup(groupName)
+	if !ok {
+		return 0
+	}
+	opts := g.GetSchema().GetResourceOpts()
+	return wqueue.RequiredAcks(opts.GetWriteConsistency(), opts.GetReplicas()+1)
+}
+
 func (sr *schemaRepo) loadQueue(groupName string) (*wqueue.Queue[*tsTable, option], error) {
 	g, ok := sr.LoadGroup(groupName)
 	if !ok {
@@ -604,6 +656,21 @@ func (s *supplier) OpenDB(groupSchema *commonv1.Group) (resourceSchema.DB, error
 		SegmentIdleTimeout:             segmentIdleTimeout,
 		DisableRetention:               disableRetention,
 		MemoryLimit:                    s.pm.GetLimit(),
+		Scrubber:                       s.option.scrubber,
+	}
+	for _, dataPath := range s.option.extraDataPaths {
+		opts.ExtraLocations = append(opts.ExtraLocations, path.Join(dataPath, group))
+	}
+	if s.option.peerClient != nil {
+		catalog := &replicaCatalog{schemaRepo: s.schemaRepo, client: s.option.peerClient, l: s.l}
+		opts.RepairPart = func(shardID common.ShardID, timeRange timestamp.TimeRange, partID uint64) error {
+			return storage.RepairPart(s.option.peerClient, catalog, data.TopicTraceRepairShard, s.schemaRepo.nodeID, group, shardID, timeRange, partID)
+		}
+		opts.AntiEntropy = func(shardID common.ShardID, timeRange timestamp.TimeRange) (storage.AntiEntropyResult, error) {
+			return storage.AntiEntropy(s.option.peerClient, catalog, data.TopicTraceAntiEntropyDigest, data.TopicTraceRepairShard,
+				s.schemaRepo.nodeID, group, shardID, timeRange)
+		}
+		opts.AntiEntropyCron = s.option.antiEntropyCron
 	}
 	return storage.OpenTSDB(
 		common.SetPosition(context.Background(), func(_ common.Position) common.Position {
@@ -621,7 +688,7 @@ type queueSupplier struct {
 	traceDataNodeRegistry grpc.NodeRegistry
 	l                     *logger.Logger
 	schemaRepo            *schemaRepo
-	handoffCtrl           *handoffController
+	handoffCtrl           *handoff.Controller
 	path                  string
 	option                option
 }
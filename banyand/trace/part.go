@@ -304,6 +304,7 @@ func (mp *memPart) mustFlush(fileSystem fs.FileSystem, path string) {
 	mp.partMetadata.mustWriteMetadata(fileSystem, path)
 	mp.tagType.mustWriteTagType(fileSystem, path)
 	mp.traceIDFilter.mustWriteTraceIDFilter(fileSystem, path)
+	storage.MustWritePartChecksums(fileSystem, path)
 
 	fileSystem.SyncPath(path)
 }
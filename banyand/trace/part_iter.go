@@ -23,6 +23,7 @@ import (
 	"io"
 	"sort"
 
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/pkg/bytes"
 	"github.com/apache/skywalking-banyandb/pkg/compress/zstd"
 	"github.com/apache/skywalking-banyandb/pkg/encoding"
@@ -176,8 +177,8 @@ func (pi *partIter) loadNextBlockMetadata() bool {
 		var err error
 		pi.bms, err = pi.readPrimaryBlock(pi.bms[:0], pbm)
 		if err != nil {
-			pi.err = fmt.Errorf("cannot read primary block for part %q at offset %d with size %d: %w",
-				&pi.p.partMetadata, pbm.offset, pbm.size, err)
+			pi.err = fmt.Errorf("cannot read primary block for part %q at offset %d with size %d: %w: %w",
+				&pi.p.partMetadata, pbm.offset, pbm.size, storage.ErrPartCorrupted, err)
 			return false
 		}
 		return true
@@ -0,0 +1,300 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/api/data"
+	clusterv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/cluster/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/sidx"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/banyand/queue"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+const repairChunkSize = 512 * 1024
+
+// replicaCatalog loads the local shards of the traces for the comparisons and the repairs.
+type replicaCatalog struct {
+	schemaRepo *schemaRepo
+	client     queue.Client
+	l          *logger.Logger
+}
+
+func (rc *replicaCatalog) LoadReplicaShard(group string, shardID common.ShardID, segmentStart time.Time) (storage.ReplicaShard, error) {
+	tst, segment, err := rc.schemaRepo.loadTable(group, shardID, segmentStart)
+	if err != nil {
+		return nil, err
+	}
+	if tst == nil {
+		segment.DecRef()
+		return nil, nil
+	}
+	traces, err := rc.schemaRepo.loadAntiEntropyTraces(group)
+	if err != nil {
+		segment.DecRef()
+		return nil, err
+	}
+	return &replicaShard{tst: tst, segment: segment, schemaRepo: rc.schemaRepo, traces: traces, client: rc.client, l: rc.l}, nil
+}
+
+// replicaShard implements storage.ReplicaShard for the table of a trace shard in a segment.
+// The secondary indexes of the table follow its parts, since their parts share the IDs of the trace parts.
+type replicaShard struct {
+	tst        *tsTable
+	segment    storage.Segment[*tsTable, option]
+	schemaRepo *schemaRepo
+	client     queue.Client
+	l          *logger.Logger
+	traces     []*trace
+}
+
+func (rs *replicaShard) TimeRange() timestamp.TimeRange {
+	return rs.segment.GetTimeRange()
+}
+
+func (rs *replicaShard) Digest() (*storage.AntiEntropyDigest, error) {
+	return rs.tst.antiEntropyDigest(rs.segment.GetTimeRange(), rs.traces)
+}
+
+func (rs *replicaShard) PartTimeRange(partID uint64) (int64, int64, bool) {
+	snp := rs.tst.currentSnapshot()
+	if snp == nil {
+		return 0, 0, false
+	}
+	defer snp.decRef()
+	for _, pw := range snp.parts {
+		if pw.mp == nil && pw.ID() == partID {
+			return pw.p.partMetadata.MinTimestamp, pw.p.partMetadata.MaxTimestamp, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (rs *replicaShard) RowHashes(set *storage.AntiEntropyBucketSet, excludedParts map[uint64]struct{}) ([]uint64, error) {
+	return rs.tst.antiEntropyRowHashes(rs.segment.GetTimeRange(), rs.traces, set, excludedParts)
+}
+
+func (rs *replicaShard) RemoveParts(partIDs map[uint64]struct{}) error {
+	return rs.tst.removeParts(partIDs)
+}
+
+// PushMissingRows pushes the spans in the requested buckets which the requesting node doesn't hold as a new part,
+// along with the parts of their secondary indexes and the documents of their series.
+func (rs *replicaShard) PushMissingRows(req *clusterv1.RepairShardRequest) (storage.RepairPushResult, error) {
+	var result storage.RepairPushResult
+	snp := rs.tst.currentSnapshot()
+	if snp == nil {
+		return result, nil
+	}
+	defer snp.decRef()
+	// The spans of the memory parts are compared as well, so they are pushed if missing.
+	parts := make([]*part, 0, len(snp.parts))
+	for _, pw := range snp.parts {
+		if pw.p.partMetadata.TotalCount > 0 {
+			parts = append(parts, pw.p)
+		}
+	}
+	known := make(map[uint64]struct{}, len(req.RowHashes))
+	for _, h := range req.RowHashes {
+		known[h] = struct{}{}
+	}
+	timeRange := rs.segment.GetTimeRange()
+	tit := &tracesInTable{
+		traces:      generateTraces(),
+		sidxReqsMap: make(map[string][]sidx.WriteRequest),
+		seriesDocs: seriesDoc{
+			docs:        make(index.Documents, 0),
+			docIDsAdded: make(map[uint64]struct{}),
+		},
+	}
+	defer releaseTraces(tit.traces)
+	tit.traces.reset()
+	if err := rs.schemaRepo.buildAntiEntropyTraces(tit, parts, timeRange, rs.traces, storage.NewAntiEntropyBucketSet(req.Buckets), known); err != nil {
+		return result, err
+	}
+	if len(tit.traces.traceIDs) == 0 {
+		return result, nil
+	}
+	mp := generateMemPart()
+	defer releaseMemPart(mp)
+	mp.mustInitFromTraces(tit.traces)
+	sidxMemParts := make(map[string]*sidx.MemPart, len(tit.sidxReqsMap))
+	defer func() {
+		for _, smp := range sidxMemParts {
+			sidx.ReleaseMemPart(smp)
+		}
+	}()
+	for name, reqs := range tit.sidxReqsMap {
+		if len(reqs) == 0 {
+			continue
+		}
+		sidxInstance, err := rs.tst.getOrCreateSidx(name)
+		if err != nil {
+			return result, fmt.Errorf("cannot get the secondary index %s: %w", name, err)
+		}
+		smp, err := sidxInstance.ConvertToMemPart(reqs, timeRange.Start.UnixNano())
+		if err != nil {
+			return result, fmt.Errorf("cannot rebuild the secondary index %s: %w", name, err)
+		}
+		sidxMemParts[name] = smp
+	}
+	seriesIDs := make(map[common.SeriesID]struct{}, len(tit.seriesDocs.docIDsAdded))
+	for id := range tit.seriesDocs.docIDsAdded {
+		seriesIDs[common.SeriesID(id)] = struct{}{}
+	}
+	// The series must be indexed before the spans can be queried through the secondary indexes.
+	if err := storage.PushSeriesDocuments(rs.client, data.TopicTraceSidxSeriesWrite, rs.l, req, rs.segment, seriesIDs); err != nil {
+		return result, err
+	}
+	synced, err := rs.push(req, openMemPart(mp), sidxMemParts)
+	if synced != nil {
+		result.Parts = synced.PartsCount
+		result.Bytes = synced.TotalBytes
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Rows = mp.partMetadata.TotalCount
+	return result, nil
+}
+
+func (rs *replicaShard) Release() {
+	rs.segment.DecRef()
+}
+
+// loadTable returns the table of the shard in the segment starting at segmentStart, which may be nil.
+// The caller must release the segment.
+func (sr *schemaRepo) loadTable(group string, shardID common.ShardID, segmentStart time.Time) (*tsTable, storage.Segment[*tsTable, option], error) {
+	tsdb, err := sr.loadTSDB(group)
+	if err != nil {
+		return nil, nil, err
+	}
+	segments, err := tsdb.SelectSegments(timestamp.NewInclusiveTimeRange(segmentStart, segmentStart))
+	if err != nil {
+		return nil, nil, err
+	}
+	var segment storage.Segment[*tsTable, option]
+	for _, s := range segments {
+		if segment == nil && s.GetTimeRange().Start.Equal(segmentStart) {
+			segment = s
+			continue
+		}
+		s.DecRef()
+	}
+	if segment == nil {
+		return nil, nil, fmt.Errorf("segment starting at %s is not found", segmentStart)
+	}
+	tables, shardIDs, _ := segment.TablesWithShardIDs()
+	for i := range tables {
+		if shardIDs[i] == shardID {
+			return tables[i], segment, nil
+		}
+	}
+	return nil, segment, nil
+}
+
+// removeParts drops the parts and the parts of the secondary indexes sharing their IDs from the table,
+// and removes their files once no query reads them.
+func (tst *tsTable) removeParts(ids map[uint64]struct{}) error {
+	if tst.removals == nil {
+		return errors.New("the table doesn't support removing parts")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	ind := &mergerIntroduction{
+		merged:  ids,
+		applied: make(chan struct{}),
+		creator: snapshotCreatorRepairer,
+	}
+	select {
+	case tst.removals <- ind:
+	case <-tst.loopCloser.CloseNotify():
+		return errClosed
+	}
+	select {
+	case <-ind.applied:
+		return nil
+	case <-tst.loopCloser.CloseNotify():
+		return errClosed
+	}
+}
+
+// push pushes the part and the parts of its secondary indexes to the node repairing the shard over the chunked part sync.
+func (rs *replicaShard) push(req *clusterv1.RepairShardRequest, p *part, sidxMemParts map[string]*sidx.MemPart) (*queue.SyncResult, error) {
+	client, err := rs.client.NewChunkedSyncClient(req.Node, repairChunkSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chunked sync client for node %s: %w", req.Node, err)
+	}
+	defer client.Close()
+	files, release := createPartFileReaders(p)
+	releaseFuncs := []func(){release}
+	defer func() {
+		for _, r := range releaseFuncs {
+			r()
+		}
+	}()
+	// The receiver groups the parts with the same ID, so the parts of the secondary indexes follow the trace part.
+	streamingParts := []queue.StreamingPartData{{
+		ID:                    p.partMetadata.ID,
+		Group:                 req.Group,
+		ShardID:               req.ShardId,
+		Topic:                 data.TopicTracePartSync.String(),
+		Files:                 files,
+		CompressedSizeBytes:   p.partMetadata.CompressedSizeBytes,
+		UncompressedSizeBytes: p.partMetadata.UncompressedSpanSizeBytes,
+		TotalCount:            p.partMetadata.TotalCount,
+		BlocksCount:           p.partMetadata.BlocksCount,
+		MinTimestamp:          p.partMetadata.MinTimestamp,
+		MaxTimestamp:          p.partMetadata.MaxTimestamp,
+		PartType:              PartTypeCore,
+	}}
+	names := make([]string, 0, len(sidxMemParts))
+	for name := range sidxMemParts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sp, sidxRelease := sidx.StreamingMemPart(sidxMemParts[name], p.partMetadata.ID, req.Group, req.ShardId, name)
+		releaseFuncs = append(releaseFuncs, sidxRelease)
+		streamingParts = append(streamingParts, sp)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), storage.RepairTimeout)
+	defer cancel()
+	result, err := client.SyncStreamingParts(ctx, streamingParts)
+	if err != nil {
+		rs.l.Error().Err(err).Str("group", req.Group).Uint32("shard", req.ShardId).Str("node", req.Node).
+			Msg("failed to push the parts of the shard for repair")
+		return nil, err
+	}
+	if len(result.FailedParts) > 0 {
+		err = fmt.Errorf("%d of %d parts failed to be pushed", len(result.FailedParts), len(streamingParts))
+	}
+	rs.l.Info().Err(err).Str("group", req.Group).Uint32("shard", req.ShardId).Str("node", req.Node).
+		Uint32("parts", result.PartsCount).Uint64("bytes", result.TotalBytes).Msg("pushed the parts of the shard for repair")
+	return result, err
+}
@@ -50,6 +50,7 @@ const (
 	snapshotCreatorMerger
 	snapshotCreatorMergedFlusher
 	snapshotCreatorSyncer
+	snapshotCreatorRepairer
 )
 
 type snapshot struct {
@@ -32,6 +32,7 @@ import (
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
 	"github.com/apache/skywalking-banyandb/banyand/internal/sidx"
 	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/query"
 )
 
@@ -116,11 +117,12 @@ func staticTraceBatchSource(ctx context.Context, traceIDs []string, maxTraceSize
 const defaultTraceBatchSize = 64
 
 type sidxStreamShard struct {
-	results  <-chan *sidx.QueryResponse
-	response *sidx.QueryResponse
-	id       int
-	idx      int
-	done     bool
+	results     <-chan *sidx.QueryResponse
+	response    *sidx.QueryResponse
+	id          int
+	idx         int
+	indexRuleID uint32
+	done        bool
 }
 
 func (sh *sidxStreamShard) prepare(ctx context.Context) error {
@@ -147,6 +149,9 @@ func (sh *sidxStreamShard) prepare(ctx context.Context) error {
 			if resp.Len() == 0 {
 				continue
 			}
+			if sh.indexRuleID > 0 {
+				index.RecordPostings(sh.indexRuleID, resp.Len())
+			}
 			sh.response = resp
 			sh.idx = 0
 			return nil
@@ -385,6 +390,11 @@ func (r *sidxStreamRunner) prepare(instances []sidx.SIDX) error {
 	}
 	allErrChannels := make([]errChannelInfo, 0, len(instances))
 
+	// The rows the secondary index of the ordering rule returns are the posting list of the rule.
+	var indexRuleID uint32
+	if r.req.Order != nil {
+		indexRuleID = r.req.Order.Index.GetMetadata().GetId()
+	}
 	sources := make([]shardSource, 0, len(instances))
 	for idx, instance := range instances {
 		resultsCh, errCh := instance.StreamingQuery(r.streamCtx, r.req)
@@ -395,8 +405,9 @@ func (r *sidxStreamRunner) prepare(instances []sidx.SIDX) error {
 		}
 
 		shard := &sidxStreamShard{
-			id:      idx,
-			results: resultsCh,
+			id:          idx,
+			results:     resultsCh,
+			indexRuleID: indexRuleID,
 		}
 		if err := shard.prepare(r.streamCtx); err != nil {
 			return fmt.Errorf("sidx[%d] prepare failed: %w", idx, err)
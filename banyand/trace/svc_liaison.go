@@ -23,7 +23,6 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
-	"sort"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -32,6 +31,7 @@ import (
 	"github.com/apache/skywalking-banyandb/api/data"
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/liaison/grpc"
 	"github.com/apache/skywalking-banyandb/banyand/metadata"
@@ -57,15 +57,19 @@ type liaison struct {
 	writeListener             bus.MessageListener
 	dataNodeSelector          node.Selector
 	pm                        protector.Memory
-	handoffCtrl               *handoffController
+	handoffCtrl               *handoff.Controller
 	l                         *logger.Logger
 	schemaRepo                schemaRepo
 	dataPath                  string
 	root                      string
 	dataNodeList              []string
 	option                    option
+	handoffExpiry             time.Duration
+	writeAckTimeout           time.Duration
+	handoffReplayRate         run.Bytes
 	maxDiskUsagePercent       int
 	handoffMaxSizePercent     int
+	handoffMaxNodeSizePercent int
 	failedPartsMaxSizePercent int
 }
 
@@ -132,12 +136,18 @@ func (l *liaison) FlagSet() *run.FlagSet {
 	fs.DurationVar(&l.option.flushTimeout, "trace-flush-timeout", 3*time.Second, "the timeout for trace data flush")
 	fs.IntVar(&l.maxDiskUsagePercent, "trace-max-disk-usage-percent", 95, "the maximum disk usage percentage")
 	fs.DurationVar(&l.option.syncInterval, "trace-sync-interval", defaultSyncInterval, "the periodic sync interval for trace data")
+	fs.DurationVar(&l.writeAckTimeout, "trace-write-ack-timeout", defaultWriteAckTimeout,
+		"how long a write waits for the replicas required by the write consistency of its group. Set to 0 to acknowledge writes without waiting")
 	fs.StringSliceVar(&l.dataNodeList, "data-node-list", nil, "comma-separated list of data node names to monitor for handoff")
 	fs.IntVar(&l.handoffMaxSizePercent, "handoff-max-size-percent", 10,
 		"percentage of BanyanDB's allowed disk usage allocated to handoff storage. "+
 			"Calculated as: totalDisk * trace-max-disk-usage-percent * handoff-max-size-percent / 10000. "+
 			"Example: 100GB disk with 95% max usage and 10% handoff = 9.5GB; 50% handoff = 47.5GB. "+
 			"Valid range: 0-100")
+	fs.IntVar(&l.handoffMaxNodeSizePercent, "handoff-max-node-size-percent", 0,
+		"percentage of the handoff storage a single offline node may take. Set to 0 to only limit the total size. Valid range: 0-100")
+	fs.DurationVar(&l.handoffExpiry, "handoff-expiry", 0, "how long a part stays in the handoff queue before it's dropped. The parts never expire if it's 0")
+	fs.VarP(&l.handoffReplayRate, "handoff-replay-rate", "", "the maximum bytes per second replayed to the recovered nodes. The replay is not throttled if it's 0")
 	fs.IntVar(&l.failedPartsMaxSizePercent, "failed-parts-max-size-percent", 10,
 		"percentage of BanyanDB's allowed disk usage allocated to failed parts storage. "+
 			"Calculated as: totalDisk * trace-max-disk-usage-percent * failed-parts-max-size-percent / 10000. "+
@@ -159,6 +169,18 @@ func (l *liaison) Validate() error {
 			"Example: 100GB disk with 95%% max usage and 50%% handoff = 100 * 95%% * 50%% = 47.5GB for handoff",
 			l.handoffMaxSizePercent)
 	}
+	if l.handoffMaxNodeSizePercent < 0 || l.handoffMaxNodeSizePercent > 100 {
+		return fmt.Errorf("invalid handoff-max-node-size-percent: %d%%. Must be between 0 and 100", l.handoffMaxNodeSizePercent)
+	}
+	if l.handoffExpiry < 0 {
+		return errors.New("handoff-expiry must be greater than or equal to 0")
+	}
+	if l.writeAckTimeout < 0 {
+		return errors.New("trace-write-ack-timeout must be greater than or equal to 0")
+	}
+	if l.handoffReplayRate < 0 {
+		return errors.New("handoff-replay-rate must be greater than or equal to 0")
+	}
 
 	if l.failedPartsMaxSizePercent < 0 || l.failedPartsMaxSizePercent > 100 {
 		return fmt.Errorf("invalid failed-parts-max-size-percent: %d%%. Must be between 0 and 100", l.failedPartsMaxSizePercent)
@@ -237,42 +259,18 @@ func (l *liaison) PreRun(ctx context.Context) error {
 			Int("diskUsagePercent", l.maxDiskUsagePercent).
 			Msg("handoff max size")
 
-		// nolint:contextcheck
-		resolveAssignments := func(group string, shardID uint32) ([]string, error) {
-			if l.metadata == nil {
-				return nil, fmt.Errorf("metadata repo is not initialized")
-			}
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			var groupSchema *commonv1.Group
-			groupSchema, err = l.metadata.GroupRegistry().GetGroup(ctx, group)
-			if err != nil {
-				return nil, err
-			}
-			if groupSchema == nil || groupSchema.ResourceOpts == nil {
-				return nil, fmt.Errorf("group %s missing resource options", group)
-			}
-			copies := groupSchema.ResourceOpts.Replicas + 1
-			if len(l.dataNodeList) == 0 {
-				return nil, fmt.Errorf("no data nodes configured for handoff")
-			}
-			sortedNodes := append([]string(nil), l.dataNodeList...)
-			sort.Strings(sortedNodes)
-			nodes := make([]string, 0, copies)
-			seen := make(map[string]struct{}, copies)
-			for replica := uint32(0); replica < copies; replica++ {
-				nodeID := sortedNodes[(int(shardID)+int(replica))%len(sortedNodes)]
-				if _, ok := seen[nodeID]; ok {
-					continue
-				}
-				nodes = append(nodes, nodeID)
-				seen[nodeID] = struct{}{}
-			}
-			return nodes, nil
+		cfg := handoff.Config{
+			MaxTotalSizeBytes: maxSizeBytes,
+			MaxNodeSizeBytes:  maxSizeBytes * uint64(l.handoffMaxNodeSizePercent) / 100,
+			Expiry:            l.handoffExpiry,
+			ReplayRate:        l.handoffReplayRate,
 		}
-
-		// nolint:contextcheck
-		l.handoffCtrl, err = newHandoffController(l.lfs, l.dataPath, l.option.tire2Client, l.dataNodeList, maxSizeBytes, l.l, resolveAssignments)
+		var factory observability.Factory
+		if l.omr != nil {
+			factory = l.omr.With(traceScope.SubScope("handoff"))
+		}
+		l.handoffCtrl, err = handoff.NewController(l.lfs, l.dataPath, l.option.tire2Client, handoffCodec{}, l.dataNodeList, cfg, l.l, factory,
+			handoff.NewShardAssignmentResolver(l.metadata, l.dataNodeList))
 		if err != nil {
 			return err
 		}
@@ -285,7 +283,7 @@ func (l *liaison) PreRun(ctx context.Context) error {
 	}
 
 	l.schemaRepo = newLiaisonSchemaRepo(l.dataPath, l, traceDataNodeRegistry)
-	l.writeListener = setUpWriteQueueCallback(l.l, &l.schemaRepo, l.maxDiskUsagePercent, l.option.tire2Client)
+	l.writeListener = setUpWriteQueueCallback(l.l, &l.schemaRepo, l.maxDiskUsagePercent, l.option.tire2Client, l.writeAckTimeout)
 
 	// Register chunked sync handler for trace and sidx data
 	l.pipeline.RegisterChunkedSyncHandler(data.TopicTracePartSync, setUpChunkedSyncCallback(l.l, &l.schemaRepo))
@@ -316,7 +314,7 @@ func (l *liaison) Serve() run.StopNotify {
 
 func (l *liaison) GracefulStop() {
 	if l.handoffCtrl != nil {
-		if err := l.handoffCtrl.close(); err != nil {
+		if err := l.handoffCtrl.Close(); err != nil {
 			l.l.Warn().Err(err).Msg("failed to close handoff controller")
 		}
 	}
@@ -371,7 +369,7 @@ func (l *liaison) CollectLiaisonInfo(_ context.Context, group string) (*database
 	info.PendingSyncPartCount = pendingSyncPartCount
 	info.PendingSyncDataSizeBytes = pendingSyncDataSizeBytes
 	if l.handoffCtrl != nil {
-		partCount, totalSize := l.handoffCtrl.stats()
+		partCount, totalSize := l.handoffCtrl.Stats()
 		info.PendingHandoffPartCount = partCount
 		info.PendingHandoffDataSizeBytes = totalSize
 	}
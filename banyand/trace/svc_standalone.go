@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
 	"go.uber.org/multierr"
 
 	"github.com/apache/skywalking-banyandb/api/common"
@@ -73,6 +74,7 @@ type standalone struct {
 	root               string
 	dataPath           string
 	option             option
+	scrubConfig        storage.ScrubConfig
 	retentionConfig    storage.RetentionConfig
 	maxFileSnapshotNum int
 	minFileSnapshotAge time.Duration
@@ -82,6 +84,8 @@ func (s *standalone) FlagSet() *run.FlagSet {
 	fs := run.NewFlagSet("trace")
 	fs.StringVar(&s.root, "trace-root-path", "/tmp", "the root path for trace data")
 	fs.StringVar(&s.dataPath, "trace-data-path", "", "the path for trace data (optional)")
+	fs.StringSliceVar(&s.option.extraDataPaths, "trace-extra-data-paths", nil,
+		"the additional data directory paths of trace, usually one per disk. New segments and shards are spread across them and the data path by free space")
 	fs.DurationVar(&s.option.flushTimeout, "trace-flush-timeout", defaultFlushTimeout, "the timeout for trace data flush")
 
 	// Retention configuration flags
@@ -96,6 +100,13 @@ func (s *standalone) FlagSet() *run.FlagSet {
 	fs.DurationVar(&s.minFileSnapshotAge, "trace-min-file-snapshot-age", time.Hour, "minimum age for file snapshots to be eligible for deletion")
 	s.option.mergePolicy = newDefaultMergePolicy()
 	fs.VarP(&s.option.mergePolicy.maxFanOutSize, "trace-max-fan-out-size", "", "the upper bound of a single file size after merge of trace")
+
+	// Scrub flags of the background verification of the part checksums
+	fs.DurationVar(&s.scrubConfig.Interval, "trace-scrub-interval", 24*time.Hour, "the interval between two scrubs of a group. Periodic scrubs are disabled if it's 0")
+	s.scrubConfig.Rate = run.Bytes(16 << 20)
+	fs.VarP(&s.scrubConfig.Rate, "trace-scrub-rate", "", "the maximum bytes per second read by the scrubs of all the groups. Scrubs are not throttled if it's 0")
+	fs.StringVar(&s.option.antiEntropyCron, "trace-anti-entropy-cron", "@every 6h",
+		"the cron expression of comparing the settled data with the replicas and pulling the missing spans. The anti-entropy repair is disabled if it's empty")
 	// Additional flags can be added here
 	return fs
 }
@@ -121,6 +132,17 @@ func (s *standalone) Validate() error {
 	if s.retentionConfig.Cooldown <= 0 {
 		return errors.New("trace-retention-cooldown must be greater than 0")
 	}
+	if s.scrubConfig.Interval < 0 {
+		return errors.New("trace-scrub-interval must be greater than or equal to 0")
+	}
+	if s.scrubConfig.Rate < 0 {
+		return errors.New("trace-scrub-rate must be greater than or equal to 0")
+	}
+	if s.option.antiEntropyCron != "" {
+		if _, err := cron.ParseStandard(s.option.antiEntropyCron); err != nil {
+			return errors.New("trace-anti-entropy-cron is not a valid cron expression")
+		}
+	}
 
 	return nil
 }
@@ -158,6 +180,16 @@ func (s *standalone) PreRun(ctx context.Context) error {
 	if !strings.HasPrefix(filepath.VolumeName(s.dataPath), filepath.VolumeName(path)) {
 		obsservice.UpdatePath(s.dataPath)
 	}
+	for i := range s.option.extraDataPaths {
+		if s.option.extraDataPaths[i], err = banyandbpath.Get(s.option.extraDataPaths[i]); err != nil {
+			return err
+		}
+		obsservice.UpdatePath(s.option.extraDataPaths[i])
+	}
+	if err = storage.CheckDataPaths(s.GetDataPaths()...); err != nil {
+		return err
+	}
+	s.option.scrubber = storage.NewScrubber(s.scrubConfig)
 	s.schemaRepo = newSchemaRepo(s.dataPath, s, node.Labels, node.NodeID)
 	if metaSvc, ok := s.metadata.(metadata.Service); ok {
 		metaSvc.RegisterDataCollector(commonv1.Catalog_CATALOG_TRACE, &s.schemaRepo)
@@ -172,6 +204,15 @@ func (s *standalone) PreRun(ctx context.Context) error {
 	if dropGroupErr != nil {
 		return fmt.Errorf("failed to subscribe to TopicTraceDropGroup: %w", dropGroupErr)
 	}
+	if s.option.peerClient != nil {
+		catalog := &replicaCatalog{schemaRepo: &s.schemaRepo, client: s.option.peerClient, l: s.l}
+		if repairErr := s.pipeline.Subscribe(data.TopicTraceRepairShard, storage.NewRepairShardListener(catalog)); repairErr != nil {
+			return fmt.Errorf("failed to subscribe to TopicTraceRepairShard: %w", repairErr)
+		}
+		if digestErr := s.pipeline.Subscribe(data.TopicTraceAntiEntropyDigest, storage.NewAntiEntropyDigestListener(catalog)); digestErr != nil {
+			return fmt.Errorf("failed to subscribe to TopicTraceAntiEntropyDigest: %w", digestErr)
+		}
+	}
 
 	// Initialize snapshot directory
 	s.snapshotDir = filepath.Join(path, "snapshots")
@@ -253,8 +294,8 @@ func (s *standalone) GetRemovalSegmentsTimeRange(group string) *timestamp.TimeRa
 
 // RetentionService interface implementation.
 
-func (s *standalone) GetDataPath() string {
-	return s.dataPath
+func (s *standalone) GetDataPaths() []string {
+	return append([]string{s.dataPath}, s.option.extraDataPaths...)
 }
 
 func (s *standalone) GetSnapshotDir() string {
@@ -265,7 +306,7 @@ func (s *standalone) LoadAllGroups() []resourceSchema.Group {
 	return s.schemaRepo.LoadAllGroups()
 }
 
-func (s *standalone) PeekOldestSegmentEndTimeInGroup(group string) (time.Time, bool) {
+func (s *standalone) PeekOldestSegmentEndTimeInGroup(group, volume string) (time.Time, bool) {
 	g, ok := s.schemaRepo.LoadGroup(group)
 	if !ok {
 		return time.Time{}, false
@@ -277,14 +318,16 @@ func (s *standalone) PeekOldestSegmentEndTimeInGroup(group string) (time.Time, b
 	}
 
 	// Type assert to the storage interface that has PeekOldestSegmentEndTime
-	if dbWithPeek, ok := db.(interface{ PeekOldestSegmentEndTime() (time.Time, bool) }); ok {
-		return dbWithPeek.PeekOldestSegmentEndTime()
+	if dbWithPeek, ok := db.(interface {
+		PeekOldestSegmentEndTime(volume string) (time.Time, bool)
+	}); ok {
+		return dbWithPeek.PeekOldestSegmentEndTime(volume)
 	}
 
 	return time.Time{}, false
 }
 
-func (s *standalone) DeleteOldestSegmentInGroup(group string) (bool, error) {
+func (s *standalone) DeleteOldestSegmentInGroup(group, volume string) (bool, error) {
 	g, ok := s.schemaRepo.LoadGroup(group)
 	if !ok {
 		return false, nil
@@ -296,8 +339,10 @@ func (s *standalone) DeleteOldestSegmentInGroup(group string) (bool, error) {
 	}
 
 	// Type assert to the storage interface that has DeleteOldestSegment
-	if dbWithDelete, ok := db.(interface{ DeleteOldestSegment() (bool, error) }); ok {
-		return dbWithDelete.DeleteOldestSegment()
+	if dbWithDelete, ok := db.(interface {
+		DeleteOldestSegment(volume string) (bool, error)
+	}); ok {
+		return dbWithDelete.DeleteOldestSegment(volume)
 	}
 
 	s.l.Debug().Str("group", group).Msg("database does not support DeleteOldestSegment")
@@ -545,6 +590,11 @@ func (s *standalone) CollectDataInfo(ctx context.Context, group string) (*databa
 	return s.schemaRepo.CollectDataInfo(ctx, group)
 }
 
+// ReportReadFailure starts a scrub of the group after a read failed.
+func (s *standalone) ReportReadFailure(group string) {
+	s.schemaRepo.ReportReadFailure(group)
+}
+
 func (s *standalone) CollectLiaisonInfo(_ context.Context, group string) (*databasev1.LiaisonInfo, error) {
 	info := &databasev1.LiaisonInfo{}
 	pendingWriteCount, writeErr := s.schemaRepo.collectPendingWriteInfo(group)
@@ -565,6 +615,11 @@ func (l *collectDataInfoListener) Rev(ctx context.Context, message bus.Message)
 	if !ok {
 		return bus.NewMessage(message.ID(), common.NewError("invalid data type for collect data info request"))
 	}
+	if req.Scrub {
+		if scrubErr := l.s.schemaRepo.ScrubGroup(ctx, req.Group); scrubErr != nil {
+			return bus.NewMessage(message.ID(), common.NewError("failed to scrub the group: %v", scrubErr))
+		}
+	}
 	dataInfo, collectErr := l.s.schemaRepo.CollectDataInfo(ctx, req.Group)
 	if collectErr != nil {
 		return bus.NewMessage(message.ID(), common.NewError("failed to collect data info: %v", collectErr))
@@ -589,11 +644,15 @@ func (l *dropGroupDataListener) Rev(ctx context.Context, message bus.Message) bu
 }
 
 // NewService returns a new service.
-func NewService(metadata metadata.Repo, pipeline queue.Server, omr observability.MetricsRegistry, pm protector.Memory) (Service, error) {
+// The peer client reaches the other data nodes to repair the corrupted parts from their replicas, which is disabled if it is nil.
+func NewService(metadata metadata.Repo, pipeline queue.Server, omr observability.MetricsRegistry, pm protector.Memory, peerClient queue.Client) (Service, error) {
 	return &standalone{
 		metadata: metadata,
 		pipeline: pipeline,
 		omr:      omr,
 		pm:       pm,
+		option: option{
+			peerClient: peerClient,
+		},
 	}, nil
 }
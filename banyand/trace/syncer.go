@@ -158,6 +158,15 @@ func createPartFileReaders(part *part) ([]queue.FileInfo, func()) {
 				Reader: filterBuf.SequentialRead(),
 			})
 		}
+	} else if part.traceIDFilter.filter != nil {
+		// The memory parts, which are pushed by the repairs, hold the filter in memory.
+		filterBuf := bigValuePool.Generate()
+		filterBuf.Buf = encodeBloomFilter(filterBuf.Buf[:0], part.traceIDFilter.filter)
+		buffersToRelease = append(buffersToRelease, filterBuf)
+		files = append(files, queue.FileInfo{
+			Name:   traceIDFilterFilename,
+			Reader: filterBuf.SequentialRead(),
+		})
 	}
 
 	// Tag type data
@@ -172,6 +181,14 @@ func createPartFileReaders(part *part) ([]queue.FileInfo, func()) {
 				Reader: tagTypeBuf.SequentialRead(),
 			})
 		}
+	} else if len(part.tagType) > 0 {
+		tagTypeBuf := bigValuePool.Generate()
+		tagTypeBuf.Buf = part.tagType.marshal(tagTypeBuf.Buf[:0])
+		buffersToRelease = append(buffersToRelease, tagTypeBuf)
+		files = append(files, queue.FileInfo{
+			Name:   tagTypeFilename,
+			Reader: tagTypeBuf.SequentialRead(),
+		})
 	}
 
 	return files, func() {
@@ -196,6 +213,9 @@ func (tst *tsTable) syncSnapshot(curSnapshot *snapshot, syncCh chan *syncIntrodu
 
 	// Validate sync preconditions
 	if !tst.needToSync(partsToSync) {
+		if len(partsToSync) == 0 && tst.acks != nil {
+			tst.acks.Skipped(curSnapshot.epoch, collectMemPartIDs(curSnapshot))
+		}
 		return nil
 	}
 
@@ -203,9 +223,13 @@ func (tst *tsTable) syncSnapshot(curSnapshot *snapshot, syncCh chan *syncIntrodu
 	failedPartsHandler := storage.NewFailedPartsHandler(tst.fileSystem, tst.root, tst.l, tst.option.failedPartsMaxTotalSizeBytes)
 
 	// Execute sync operation
-	if err := tst.executeSyncOperation(partsToSync, partIDsToSync, failedPartsHandler); err != nil {
+	acked, err := tst.executeSyncOperation(partsToSync, partIDsToSync, failedPartsHandler)
+	if err != nil {
 		return err
 	}
+	if tst.acks != nil {
+		tst.acks.Synced(curSnapshot.epoch, collectMemPartIDs(curSnapshot), acked)
+	}
 
 	// Handle sync introductions (includes both successful and permanently failed parts)
 	return tst.handleSyncIntroductions(partsToSync, syncCh)
@@ -225,6 +249,17 @@ func (tst *tsTable) collectPartsToSync(curSnapshot *snapshot) ([]*part, map[uint
 	return partsToSync, sidxPartsToSync
 }
 
+// collectMemPartIDs returns the IDs of the mem parts, which aren't flushed and synced yet.
+func collectMemPartIDs(curSnapshot *snapshot) map[uint64]struct{} {
+	ids := make(map[uint64]struct{})
+	for _, pw := range curSnapshot.parts {
+		if pw.mp != nil {
+			ids[pw.ID()] = struct{}{}
+		}
+	}
+	return ids
+}
+
 // needToSync validates that there are parts to sync and nodes available.
 func (tst *tsTable) needToSync(partsToSync []*part) bool {
 	nodes := tst.getNodes()
@@ -292,7 +327,10 @@ func (tst *tsTable) syncPartsToNodesHelper(
 }
 
 // executeSyncOperation performs the actual synchronization of parts to nodes.
-func (tst *tsTable) executeSyncOperation(partsToSync []*part, partIDsToSync map[uint64]struct{}, failedPartsHandler *storage.FailedPartsHandler) error {
+// It returns the number of the nodes which received all the parts.
+func (tst *tsTable) executeSyncOperation(partsToSync []*part, partIDsToSync map[uint64]struct{},
+	failedPartsHandler *storage.FailedPartsHandler,
+) (int, error) {
 	sort.Slice(partsToSync, func(i, j int) bool {
 		return partsToSync[i].partMetadata.ID < partsToSync[j].partMetadata.ID
 	})
@@ -307,7 +345,7 @@ func (tst *tsTable) executeSyncOperation(partsToSync []*part, partIDsToSync map[
 
 	nodes := tst.getNodes()
 	if tst.loopCloser != nil && tst.loopCloser.Closed() {
-		return errClosed
+		return 0, errClosed
 	}
 
 	sidxMap := tst.getAllSidx()
@@ -341,7 +379,7 @@ func (tst *tsTable) executeSyncOperation(partsToSync []*part, partIDsToSync map[
 	perNodeFailures := make(map[string][]queue.FailedPart) // node -> failed parts
 	for _, node := range nodes {
 		if tst.loopCloser != nil && tst.loopCloser.Closed() {
-			return errClosed
+			return 0, errClosed
 		}
 		failedParts, err := tst.syncPartsToNodesHelper(ctx, partsToSync, partIDsToSync, []string{node}, sidxMap, &releaseFuncs)
 		if err != nil {
@@ -364,6 +402,7 @@ func (tst *tsTable) executeSyncOperation(partsToSync []*part, partIDsToSync map[
 
 	// After sync attempts, enqueue parts for offline nodes
 	tst.enqueueForOfflineNodes(nodes, partsToSync, partIDsToSync)
+	acked := len(nodes) - len(perNodeFailures)
 
 	// If there are failed parts, use the retry handler
 	if len(perNodeFailures) > 0 {
@@ -450,9 +489,12 @@ func (tst *tsTable) executeSyncOperation(partsToSync []*part, partIDsToSync map[
 				Int("count", len(permanentlyFailedParts)).
 				Msg("parts permanently failed after all retries and have been copied to failed-parts directory")
 		}
+		if err == nil && len(permanentlyFailedParts) == 0 {
+			acked = len(nodes)
+		}
 	}
 
-	return nil
+	return acked, nil
 }
 
 // handleSyncIntroductions creates and processes sync introductions for both core and sidx parts.
@@ -28,6 +28,7 @@ import (
 
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
 	"github.com/apache/skywalking-banyandb/banyand/observability"
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/banyand/queue"
@@ -46,14 +47,20 @@ const (
 
 	defaultFlushTimeout = time.Second
 	defaultSyncInterval = 30 * time.Second
+	// defaultWriteAckTimeout covers a flush and a sync of the written spans.
+	defaultWriteAckTimeout = 20 * time.Second
 )
 
 var traceScope = observability.RootScope.SubScope("trace")
 
 type option struct {
 	mergePolicy                  *mergePolicy
+	scrubber                     *storage.Scrubber
 	protector                    protector.Memory
 	tire2Client                  queue.Client
+	peerClient                   queue.Client
+	extraDataPaths               []string
+	antiEntropyCron              string
 	seriesCacheMaxSize           run.Bytes
 	flushTimeout                 time.Duration
 	syncInterval                 time.Duration
@@ -129,6 +136,33 @@ func (t *trace) OnIndexUpdate(index []*databasev1.IndexRule) {
 	is.indexRules = index
 	is.parse(t.schema)
 	t.indexSchema.Store(is)
+	t.flagUnbuiltIndexRules(index)
+}
+
+// flagUnbuiltIndexRules reports the index rules bound after the data of a shard was written,
+// since their secondary indexes are not built on the existing spans.
+func (t *trace) flagUnbuiltIndexRules(rules []*databasev1.IndexRule) {
+	if t.schemaRepo == nil || t.schemaRepo.ruleTracker == nil {
+		return
+	}
+	current := storage.IndexRuleIDs(rules, nil)
+	added := t.schemaRepo.ruleTracker.Added(t.group+"/"+t.name, current)
+	tsdb, err := t.schemaRepo.loadTSDB(t.group)
+	if err != nil {
+		t.schemaRepo.l.Debug().Err(err).Str("trace", t.group+"/"+t.name).Msg("skip checking indices before the group is loaded")
+		return
+	}
+	unsupported, err := storage.FlagUnbuiltIndexRules(tsdb, t.name, rules, current, added, func(tst *tsTable) *storage.IndexBuildState {
+		return tst.buildState
+	})
+	if err != nil {
+		t.schemaRepo.l.Error().Err(err).Str("trace", t.group+"/"+t.name).Msg("cannot check the indices of the existing data")
+		return
+	}
+	if len(added) > 0 && len(unsupported) > 0 {
+		t.schemaRepo.l.Warn().Strs("indexRules", storage.IndexRuleNames(rules, unsupported)).Str("trace", t.group+"/"+t.name).
+			Msg("the index rules are not built on the data written before they were bound")
+	}
 }
 
 func (t *trace) parseSpec() {
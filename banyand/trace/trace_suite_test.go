@@ -78,7 +78,7 @@ func setUp() (*services, func()) {
 	metricSvc := obsservice.NewMetricService(metadataService, pipeline, "test", nil)
 	pm := protector.NewMemory(metricSvc)
 	// Init Trace Service
-	traceService, err := trace.NewService(metadataService, pipeline, metricSvc, pm)
+	traceService, err := trace.NewService(metadataService, pipeline, metricSvc, pm, nil)
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	preloadTraceSvc := &preloadTraceService{metaSvc: metadataService}
 	// Init Query Service for trace queries
@@ -134,9 +134,11 @@ type tracesInTable struct {
 	tsTable     *tsTable
 	traces      *traces
 	sidxReqsMap map[string][]sidx.WriteRequest
-	timeRange   timestamp.TimeRange
-	seriesDocs  seriesDoc
-	shardID     common.ShardID
+	// messageIDs are the write messages of the spans, which a liaison reports if they aren't persisted by enough replicas.
+	messageIDs []uint64
+	timeRange  timestamp.TimeRange
+	seriesDocs seriesDoc
+	shardID    common.ShardID
 }
 
 type tracesInGroup struct {
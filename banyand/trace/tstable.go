@@ -31,8 +31,10 @@ import (
 	"time"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	"github.com/apache/skywalking-banyandb/banyand/internal/handoff"
 	"github.com/apache/skywalking-banyandb/banyand/internal/sidx"
 	"github.com/apache/skywalking-banyandb/banyand/internal/storage"
+	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/pkg/fs"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
@@ -50,14 +52,18 @@ const (
 type tsTable struct {
 	pm               protector.Memory
 	fileSystem       fs.FileSystem
-	handoffCtrl      *handoffController
+	handoffCtrl      *handoff.Controller
+	acks             *wqueue.AckTracker
 	metrics          *metrics
+	buildState       *storage.IndexBuildState
 	snapshot         *snapshot
 	loopCloser       *run.Closer
 	getNodes         func() []string
 	l                *logger.Logger
 	sidxMap          map[string]sidx.SIDX
+	partDigests      map[uint64]*storage.AntiEntropyDigest
 	introductions    chan *introduction
+	removals         chan *mergerIntroduction
 	p                common.Position
 	root             string
 	group            string
@@ -65,6 +71,7 @@ type tsTable struct {
 	option           option
 	curPartID        uint64
 	pendingDataCount atomic.Int64
+	partDigestsMu    sync.Mutex
 	sync.RWMutex
 	shardID common.ShardID
 }
@@ -117,6 +124,7 @@ func (tst *tsTable) loadSnapshot(epoch uint64, loadedParts []uint64) {
 func (tst *tsTable) startLoop(cur uint64) {
 	tst.loopCloser = run.NewCloser(1 + 3)
 	tst.introductions = make(chan *introduction)
+	tst.removals = make(chan *mergerIntroduction)
 	flushCh := make(chan *flusherIntroduction)
 	mergeCh := make(chan *mergerIntroduction)
 	introducerWatcher := make(watcher.Channel, 1)
@@ -271,6 +279,7 @@ func newTSTable(fileSystem fs.FileSystem, rootPath string, p common.Position,
 	l *logger.Logger, _ timestamp.TimeRange, option option, m any,
 ) (*tsTable, error) {
 	t, epoch := initTSTable(fileSystem, rootPath, p, l, option, m)
+	t.buildState = storage.LoadIndexBuildState(fileSystem, rootPath, l)
 	t.startLoop(epoch)
 	return t, nil
 }
@@ -349,7 +358,7 @@ func (tst *tsTable) enqueueForOfflineNodes(onlineNodes []string, partsToSync []*
 	}
 
 	// Check if there are any offline nodes before doing expensive preparation work
-	offlineNodes := tst.handoffCtrl.calculateOfflineNodes(onlineNodes, tst.group, tst.shardID)
+	offlineNodes := tst.handoffCtrl.CalculateOfflineNodes(onlineNodes, tst.group, tst.shardID)
 	tst.l.Debug().
 		Str("group", tst.group).
 		Uint32("shardID", uint32(tst.shardID)).
@@ -361,39 +370,34 @@ func (tst *tsTable) enqueueForOfflineNodes(onlineNodes []string, partsToSync []*
 	}
 
 	// Prepare core parts info
-	coreParts := make([]partInfo, 0, len(partsToSync))
+	parts := make([]handoff.PartInfo, 0, len(partsToSync))
 	for _, part := range partsToSync {
-		coreParts = append(coreParts, partInfo{
-			partID:  part.partMetadata.ID,
-			path:    part.path,
-			group:   tst.group,
-			shardID: tst.shardID,
+		parts = append(parts, handoff.PartInfo{
+			PartID:   part.partMetadata.ID,
+			Path:     part.path,
+			Group:    tst.group,
+			PartType: PartTypeCore,
+			ShardID:  tst.shardID,
 		})
 	}
 
 	// Get sidx part paths from each sidx instance
 	sidxMap := tst.getAllSidx()
-	sidxParts := make(map[string][]partInfo)
 	for sidxName, sidxInstance := range sidxMap {
 		partPaths := sidxInstance.PartPaths(partIDsToSync)
-		if len(partPaths) == 0 {
-			continue
-		}
-
-		parts := make([]partInfo, 0, len(partPaths))
 		for partID, path := range partPaths {
-			parts = append(parts, partInfo{
-				partID:  partID,
-				path:    path,
-				group:   tst.group,
-				shardID: tst.shardID,
+			parts = append(parts, handoff.PartInfo{
+				PartID:   partID,
+				Path:     path,
+				Group:    tst.group,
+				PartType: sidxName,
+				ShardID:  tst.shardID,
 			})
 		}
-		sidxParts[sidxName] = parts
 	}
 
 	// Call handoff controller with offline nodes
-	if err := tst.handoffCtrl.enqueueForOfflineNodes(offlineNodes, coreParts, sidxParts); err != nil {
+	if err := tst.handoffCtrl.EnqueueForOfflineNodes(offlineNodes, parts); err != nil {
 		tst.l.Warn().Err(err).Msg("handoff enqueue completed with errors")
 	}
 }
@@ -449,6 +453,9 @@ func (tst *tsTable) Close() error {
 		tst.loopCloser.Done()
 		tst.loopCloser.CloseThenWait()
 	}
+	if tst.acks != nil {
+		tst.acks.Close()
+	}
 	tst.Lock()
 	defer tst.Unlock()
 	tst.deleteMetrics()
@@ -461,6 +468,12 @@ func (tst *tsTable) Close() error {
 }
 
 func (tst *tsTable) mustAddMemPart(mp *memPart, sidxReqsMap map[string]*sidx.MemPart) {
+	tst.mustAddTrackedMemPart(mp, sidxReqsMap, false)
+}
+
+// mustAddTrackedMemPart adds the mem part. If track is set, it returns the waiter of the write being synced to the data nodes,
+// which is nil if the table doesn't sync. The introducer tracks the write, so no sync covers the mem part before the write is tracked.
+func (tst *tsTable) mustAddTrackedMemPart(mp *memPart, sidxReqsMap map[string]*sidx.MemPart, track bool) *wqueue.AckWaiter {
 	p := openMemPart(mp)
 
 	ind := generateIntroduction()
@@ -469,6 +482,7 @@ func (tst *tsTable) mustAddMemPart(mp *memPart, sidxReqsMap map[string]*sidx.Mem
 	ind.memPart = newPartWrapper(mp, p)
 	ind.memPart.p.partMetadata.ID = atomic.AddUint64(&tst.curPartID, 1)
 	ind.sidxReqsMap = sidxReqsMap
+	ind.track = track
 	startTime := time.Now()
 	totalCount := mp.partMetadata.TotalCount
 	tst.addPendingDataCount(int64(totalCount))
@@ -477,24 +491,31 @@ func (tst *tsTable) mustAddMemPart(mp *memPart, sidxReqsMap map[string]*sidx.Mem
 	case <-tst.loopCloser.CloseNotify():
 		tst.addPendingDataCount(-int64(totalCount))
 		ind.memPart.decRef()
-		return
+		return nil
 	}
+	var waiter *wqueue.AckWaiter
 	select {
 	case <-ind.applied:
+		waiter = ind.waiter
 	case <-tst.loopCloser.CloseNotify():
 	}
 	tst.incTotalWritten(int(totalCount))
 	tst.incTotalBatch(1)
 	tst.incTotalBatchIntroLatency(time.Since(startTime).Seconds())
+	return waiter
 }
 
 func (tst *tsTable) mustAddTraces(ts *traces, sidxReqsMap map[string]*sidx.MemPart) {
-	tst.mustAddTracesWithSegmentID(ts, 0, sidxReqsMap, nil)
+	tst.mustAddTracesWithSegmentID(ts, 0, sidxReqsMap, nil, false)
 }
 
-func (tst *tsTable) mustAddTracesWithSegmentID(ts *traces, segmentID int64, sidxReqsMap map[string]*sidx.MemPart, seriesMetadata []byte) {
+// mustAddTracesWithSegmentID adds the traces as a mem part.
+// If track is set, it returns the waiter of the write being synced to the data nodes, which is nil if the table doesn't sync.
+func (tst *tsTable) mustAddTracesWithSegmentID(ts *traces, segmentID int64, sidxReqsMap map[string]*sidx.MemPart, seriesMetadata []byte,
+	track bool,
+) *wqueue.AckWaiter {
 	if len(ts.traceIDs) == 0 {
-		return
+		return nil
 	}
 
 	mp := generateMemPart()
@@ -508,7 +529,7 @@ func (tst *tsTable) mustAddTracesWithSegmentID(ts *traces, segmentID int64, sidx
 		}
 	}
 
-	tst.mustAddMemPart(mp, sidxReqsMap)
+	return tst.mustAddTrackedMemPart(mp, sidxReqsMap, track)
 }
 
 type tstIter struct {
@@ -30,6 +30,7 @@ import (
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
 	tracev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/trace/v1"
 	"github.com/apache/skywalking-banyandb/banyand/internal/sidx"
+	"github.com/apache/skywalking-banyandb/banyand/internal/wqueue"
 	obsservice "github.com/apache/skywalking-banyandb/banyand/observability/services"
 	"github.com/apache/skywalking-banyandb/banyand/queue"
 	"github.com/apache/skywalking-banyandb/pkg/bus"
@@ -45,9 +46,12 @@ type writeQueueCallback struct {
 	schemaRepo          *schemaRepo
 	tire2Client         queue.Client
 	maxDiskUsagePercent int
+	ackTimeout          time.Duration
 }
 
-func setUpWriteQueueCallback(l *logger.Logger, schemaRepo *schemaRepo, maxDiskUsagePercent int, tire2Client queue.Client) bus.MessageListener {
+func setUpWriteQueueCallback(l *logger.Logger, schemaRepo *schemaRepo, maxDiskUsagePercent int, tire2Client queue.Client,
+	ackTimeout time.Duration,
+) bus.MessageListener {
 	if maxDiskUsagePercent > 100 {
 		maxDiskUsagePercent = 100
 	}
@@ -56,6 +60,7 @@ func setUpWriteQueueCallback(l *logger.Logger, schemaRepo *schemaRepo, maxDiskUs
 		schemaRepo:          schemaRepo,
 		maxDiskUsagePercent: maxDiskUsagePercent,
 		tire2Client:         tire2Client,
+		ackTimeout:          ackTimeout,
 	}
 }
 
@@ -100,6 +105,7 @@ func (w *writeQueueCallback) handle(dst map[string]*tracesInQueue, writeEvent *t
 	if err != nil {
 		return nil, err
 	}
+	et.messageIDs = append(et.messageIDs, writeEvent.Request.GetVersion())
 	return dst, nil
 }
 
@@ -171,6 +177,7 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 	groups := make(map[string]*tracesInQueue)
 	var metadata *commonv1.Metadata
 	var spec *tracev1.TagSpec
+	var acks []wqueue.PendingAck
 	for i := range events {
 		var writeEvent *tracev1.InternalWriteRequest
 		switch e := events[i].(type) {
@@ -202,6 +209,10 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 	}
 	for groupName := range groups {
 		g := groups[groupName]
+		requiredAcks := 0
+		if w.ackTimeout > 0 {
+			requiredAcks = w.schemaRepo.requiredAcks(groupName)
+		}
 		for j := range g.tables {
 			es := g.tables[j]
 			// Marshal series metadata for persistence in part folder
@@ -234,8 +245,11 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 				}
 			}
 			if es.tsTable != nil && es.traces != nil {
-				es.tsTable.mustAddTracesWithSegmentID(es.traces, es.timeRange.Start.UnixNano(), sidxMemPartMap, seriesMetadataBytes)
+				waiter := es.tsTable.mustAddTracesWithSegmentID(es.traces, es.timeRange.Start.UnixNano(), sidxMemPartMap, seriesMetadataBytes, requiredAcks > 0)
 				releaseTraces(es.traces)
+				if waiter != nil {
+					acks = append(acks, wqueue.PendingAck{Waiter: waiter, Group: groupName, Required: requiredAcks, MessageIDs: es.messageIDs})
+				}
 			}
 
 			nodes := g.queue.GetNodes(es.shardID)
@@ -269,5 +283,9 @@ func (w *writeQueueCallback) Rev(ctx context.Context, message bus.Message) (resp
 			}
 		}
 	}
+	if degradedErr := wqueue.WaitAcks(ctx, acks, w.ackTimeout); degradedErr != nil {
+		w.l.Warn().Err(degradedErr).Msg("the spans are persisted by fewer replicas than the write consistency")
+		return bus.NewMessage(message.ID(), degradedErr)
+	}
 	return
 }
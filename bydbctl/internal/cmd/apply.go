@@ -0,0 +1,354 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/apache/skywalking-banyandb/bydbctl/pkg/file"
+	"github.com/apache/skywalking-banyandb/pkg/version"
+)
+
+const (
+	changeCreate    = "create"
+	changeUpdate    = "update"
+	changeDelete    = "delete"
+	changeUnchanged = "unchanged"
+	kindField       = "kind"
+	applyUsage      = `Every document in the files is a schema object with a "kind" node,
+		which is one of group, stream, measure, trace, property, index-rule, index-rule-binding and topn-aggregation.
+		The rest of the document is the object in the same format as the create command accepts.
+		The objects are applied in the order of groups, resources, index rules, index rule bindings and TopN aggregations.
+		With "--prune", the objects in the groups of the files but absent from the files are deleted.
+		Groups and the objects maintained by the server, whose names start with "_", are never pruned.`
+)
+
+// schemaChange is the change of a schema object between the files and the cluster.
+type schemaChange struct {
+	kind    *schemaKind
+	desired schemaObject
+	current schemaObject
+	action  string
+}
+
+func (c *schemaChange) key() string {
+	if c.desired != nil {
+		return c.kind.objectKey(c.desired.GetMetadata())
+	}
+	return c.kind.objectKey(c.current.GetMetadata())
+}
+
+func newApplyCmd() *cobra.Command {
+	var prune bool
+	applyCmd := &cobra.Command{
+		Use:     "apply -f [file|dir|-] [--prune]",
+		Version: version.Build(),
+		Short:   "Create or update the schema objects declared in files",
+		Long:    applyUsage,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			changes, err := planSchemaChanges(cmd, prune)
+			if err != nil {
+				return err
+			}
+			return applySchemaChanges(changes)
+		},
+	}
+	applyCmd.Flags().BoolVar(&prune, "prune", false, "Delete the objects absent from the files in the groups of the files")
+	bindFileFlag(applyCmd)
+	bindTLSRelatedFlag(applyCmd)
+	return applyCmd
+}
+
+func newDiffCmd() *cobra.Command {
+	var prune bool
+	diffCmd := &cobra.Command{
+		Use:     "diff -f [file|dir|-] [--prune]",
+		Version: version.Build(),
+		Short:   "Show the differences between the schema objects declared in files and the cluster",
+		Long:    applyUsage,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			changes, err := planSchemaChanges(cmd, prune)
+			if err != nil {
+				return err
+			}
+			printSchemaChanges(changes)
+			return nil
+		},
+	}
+	diffCmd.Flags().BoolVar(&prune, "prune", false, "Show the objects to be deleted by \"apply --prune\"")
+	bindFileFlag(diffCmd)
+	bindTLSRelatedFlag(diffCmd)
+	return diffCmd
+}
+
+// loadSchemaObjects parses the schema objects declared in the files.
+func loadSchemaObjects(cmd *cobra.Command) ([]*schemaChange, error) {
+	docs, err := file.ReadYAMLDocuments(filePath, cmd.InOrStdin())
+	if err != nil {
+		return nil, err
+	}
+	defaultGroup := viper.GetString("group")
+	seen := make(map[string]struct{}, len(docs))
+	objects := make([]*schemaChange, 0, len(docs))
+	for _, d := range docs {
+		var data map[string]any
+		if err = json.Unmarshal(d, &data); err != nil {
+			return nil, err
+		}
+		kindName, ok := data[kindField].(string)
+		if !ok {
+			return nil, errors.WithMessage(errMalformedInput, "absent node: kind")
+		}
+		delete(data, kindField)
+		kind, err := findSchemaKind(kindName)
+		if err != nil {
+			return nil, err
+		}
+		if metadata, ok := data["metadata"].(map[string]any); ok && kind.groupScoped {
+			if _, ok := metadata["group"]; !ok && defaultGroup != "" {
+				metadata["group"] = defaultGroup
+			}
+		}
+		j, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		o := kind.newObject()
+		if err = protojson.Unmarshal(j, o); err != nil {
+			return nil, errors.WithMessagef(err, "failed to parse a %s", kind.name)
+		}
+		md := o.GetMetadata()
+		if md.GetName() == "" || (kind.groupScoped && md.GetGroup() == "") {
+			return nil, errors.WithMessagef(errMalformedInput, "absent node: name or group in the metadata of a %s", kind.name)
+		}
+		change := &schemaChange{kind: kind, desired: o}
+		if _, ok := seen[change.key()]; ok {
+			return nil, fmt.Errorf("%s is declared more than once", change.key())
+		}
+		seen[change.key()] = struct{}{}
+		objects = append(objects, change)
+	}
+	sort.SliceStable(objects, func(i, j int) bool { return objects[i].kind.order < objects[j].kind.order })
+	return objects, nil
+}
+
+// planSchemaChanges compares the declared objects with the cluster.
+func planSchemaChanges(cmd *cobra.Command, prune bool) ([]*schemaChange, error) {
+	changes, err := loadSchemaObjects(cmd)
+	if err != nil {
+		return nil, err
+	}
+	declared := make(map[string]struct{}, len(changes))
+	var groups []string
+	groupSet := make(map[string]struct{})
+	for _, c := range changes {
+		declared[c.key()] = struct{}{}
+		g := c.desired.GetMetadata().GetGroup()
+		if !c.kind.groupScoped {
+			g = c.desired.GetMetadata().GetName()
+		}
+		if _, ok := groupSet[g]; !ok {
+			groupSet[g] = struct{}{}
+			groups = append(groups, g)
+		}
+		current, err := c.kind.get(c.desired.GetMetadata())
+		switch {
+		case status.Code(err) == codes.NotFound:
+			c.action = changeCreate
+		case err != nil:
+			return nil, errors.WithMessagef(err, "failed to get %s", c.key())
+		default:
+			c.current = current
+			c.action = changeUpdate
+			if proto.Equal(normalizeSchema(current), normalizeSchema(c.desired)) {
+				c.action = changeUnchanged
+			}
+		}
+	}
+	if !prune {
+		return changes, nil
+	}
+	var deletions []*schemaChange
+	for _, g := range groups {
+		for _, k := range schemaKinds {
+			if !k.groupScoped {
+				continue
+			}
+			objects, err := k.list(g)
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to list %s in %s", k.name, g)
+			}
+			for _, o := range objects {
+				// The objects prefixed with "_" are maintained by the server, like "_top_n_result".
+				if strings.HasPrefix(o.GetMetadata().GetName(), "_") {
+					continue
+				}
+				if _, ok := declared[k.objectKey(o.GetMetadata())]; ok {
+					continue
+				}
+				deletions = append(deletions, &schemaChange{kind: k, current: o, action: changeDelete})
+			}
+		}
+	}
+	// Dependents are deleted before the objects they depend on.
+	sort.SliceStable(deletions, func(i, j int) bool { return deletions[i].kind.order > deletions[j].kind.order })
+	return append(changes, deletions...), nil
+}
+
+func applySchemaChanges(changes []*schemaChange) error {
+	var created, updated, deleted, unchanged int
+	for _, c := range changes {
+		var err error
+		switch c.action {
+		case changeCreate:
+			err = c.kind.create(c.desired)
+			created++
+		case changeUpdate:
+			err = c.kind.update(c.desired)
+			updated++
+		case changeDelete:
+			err = c.kind.delete(c.current.GetMetadata())
+			deleted++
+		default:
+			unchanged++
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "failed to %s %s", c.action, c.key())
+		}
+		if c.action != changeUnchanged {
+			fmt.Printf("%s is %sd\n", c.key(), c.action)
+		}
+	}
+	fmt.Printf("%d created, %d updated, %d deleted, %d unchanged\n", created, updated, deleted, unchanged)
+	return nil
+}
+
+func printSchemaChanges(changes []*schemaChange) {
+	var changed bool
+	for _, c := range changes {
+		switch c.action {
+		case changeCreate:
+			fmt.Printf("+ %s\n", c.key())
+		case changeDelete:
+			fmt.Printf("- %s\n", c.key())
+		case changeUpdate:
+			fmt.Printf("~ %s\n", c.key())
+			for _, line := range diffSchemaObjects(c.current, c.desired) {
+				fmt.Printf("    %s\n", line)
+			}
+		default:
+			continue
+		}
+		changed = true
+	}
+	if !changed {
+		fmt.Println("No changes.")
+	}
+}
+
+// diffSchemaObjects lists the changed fields between two objects in the form of "path: from -> to".
+func diffSchemaObjects(from, to schemaObject) []string {
+	var lines []string
+	diffJSONValues("", toJSONValue(normalizeSchema(from)), toJSONValue(normalizeSchema(to)), &lines)
+	return lines
+}
+
+func toJSONValue(m proto.Message) any {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	var v any
+	if err = json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func diffJSONValues(path string, from, to any, lines *[]string) {
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+	fromMap, fromIsMap := from.(map[string]any)
+	toMap, toIsMap := to.(map[string]any)
+	if fromIsMap && toIsMap {
+		keys := make([]string, 0, len(fromMap)+len(toMap))
+		for k := range fromMap {
+			keys = append(keys, k)
+		}
+		for k := range toMap {
+			if _, ok := fromMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diffJSONValues(joinJSONPath(path, k), fromMap[k], toMap[k], lines)
+		}
+		return
+	}
+	fromSlice, fromIsSlice := from.([]any)
+	toSlice, toIsSlice := to.([]any)
+	if fromIsSlice && toIsSlice {
+		for i := 0; i < len(fromSlice) || i < len(toSlice); i++ {
+			var f, t any
+			if i < len(fromSlice) {
+				f = fromSlice[i]
+			}
+			if i < len(toSlice) {
+				t = toSlice[i]
+			}
+			diffJSONValues(fmt.Sprintf("%s[%d]", path, i), f, t, lines)
+		}
+		return
+	}
+	*lines = append(*lines, fmt.Sprintf("%s: %s -> %s", path, jsonValueString(from), jsonValueString(to)))
+}
+
+func joinJSONPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonValueString(v any) string {
+	if v == nil {
+		return "<absent>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,141 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/zenizh/go-capturer"
+
+	"github.com/apache/skywalking-banyandb/bydbctl/internal/cmd"
+	"github.com/apache/skywalking-banyandb/pkg/test/flags"
+	"github.com/apache/skywalking-banyandb/pkg/test/setup"
+)
+
+const (
+	applyGroupYAML = `
+kind: group
+metadata:
+  name: group1
+catalog: CATALOG_STREAM
+resource_opts:
+  shard_num: 2
+  segment_interval:
+    unit: UNIT_DAY
+    num: 1
+  ttl:
+    unit: UNIT_DAY
+    num: 7`
+	applyStreamYAML = `
+kind: stream
+metadata:
+  name: name1
+  group: group1
+tag_families:
+  - name: default
+    tags:
+      - name: id
+        type: TAG_TYPE_STRING
+entity:
+  tag_names: ["id"]`
+	applyIndexRuleYAML = `
+kind: index-rule
+metadata:
+  name: id
+  group: group1
+tags: ["id"]
+type: TYPE_INVERTED`
+	applyIndexRuleBindingYAML = `
+kind: index-rule-binding
+metadata:
+  name: binding1
+  group: group1
+rules: ["id"]
+subject:
+  catalog: CATALOG_STREAM
+  name: name1
+begin_at: "2021-04-15T01:30:15.01Z"
+expire_at: "2121-04-15T01:30:15.01Z"`
+)
+
+var _ = Describe("Schema Apply", func() {
+	var addr, dir string
+	var deferFunc func()
+	var rootCmd *cobra.Command
+
+	execute := func(args ...string) string {
+		rootCmd.SetArgs(append(args, "-a", addr, "-f", dir))
+		return capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	}
+
+	writeFile := func(name, content string) {
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		_, addr, deferFunc = setup.EmptyStandalone(nil)
+		addr = httpSchema + addr
+		rootCmd = &cobra.Command{Use: "root"}
+		cmd.RootCmdFlags(rootCmd)
+		dir = GinkgoT().TempDir()
+		// The documents are not ordered by their dependencies in the files.
+		writeFile("binding.yaml", applyIndexRuleBindingYAML)
+		writeFile("stream.yaml", applyStreamYAML+"\n---"+applyIndexRuleYAML)
+		writeFile("group.yaml", applyGroupYAML)
+	})
+
+	It("creates objects in the dependency order and skips unchanged ones", func() {
+		out := execute("diff")
+		Expect(out).To(ContainSubstring("+ group:group1"))
+		Expect(out).To(ContainSubstring("+ stream:group1/name1"))
+		Eventually(func() string { return execute("apply") }, flags.EventuallyTimeout).
+			Should(ContainSubstring("4 created, 0 updated, 0 deleted, 0 unchanged"))
+		Expect(execute("apply")).To(ContainSubstring("0 created, 0 updated, 0 deleted, 4 unchanged"))
+		Expect(execute("diff")).To(ContainSubstring("No changes."))
+	})
+
+	It("updates changed objects and prunes absent ones", func() {
+		Eventually(func() string { return execute("apply") }, flags.EventuallyTimeout).
+			Should(ContainSubstring("4 created"))
+		writeFile("stream.yaml", applyStreamYAML+`
+      - name: tag1
+        type: TAG_TYPE_INT`)
+		Expect(os.Remove(filepath.Join(dir, "binding.yaml"))).To(Succeed())
+
+		out := execute("diff", "--prune")
+		Expect(out).To(ContainSubstring("~ stream:group1/name1"))
+		Expect(out).To(ContainSubstring("tag_families[0].tags[1]: <absent> ->"))
+		Expect(out).To(ContainSubstring("- index-rule-binding:group1/binding1"))
+		Expect(out).To(ContainSubstring("- index-rule:group1/id"))
+
+		out = execute("apply", "--prune")
+		Expect(out).To(ContainSubstring("0 created, 1 updated, 2 deleted, 1 unchanged"))
+		Expect(execute("diff", "--prune")).To(ContainSubstring("No changes."))
+	})
+
+	AfterEach(func() {
+		deferFunc()
+	})
+})
@@ -20,9 +20,12 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
+
 	"github.com/go-resty/resty/v2"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/encoding/protojson"
+	"sigs.k8s.io/yaml"
 
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
@@ -49,7 +52,8 @@ func newGroupCmd() *cobra.Command {
 						return nil, err
 					}
 					cr := &databasev1.GroupRegistryServiceCreateRequest{
-						Group: g,
+						Group:  g,
+						DryRun: dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -57,11 +61,11 @@ func newGroupCmd() *cobra.Command {
 					}
 					return request.req.SetBody(b).Post(getPath("/api/v1/group/schema"))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("group %s is created", reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -78,7 +82,8 @@ func newGroupCmd() *cobra.Command {
 						return nil, err
 					}
 					cr := &databasev1.GroupRegistryServiceUpdateRequest{
-						Group: g,
+						Group:  g,
+						DryRun: dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -86,14 +91,15 @@ func newGroupCmd() *cobra.Command {
 					}
 					return request.req.SetBody(b).SetPathParam("group", request.name).Put(getPath("/api/v1/group/schema/{group}"))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("group %s is updated", reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 	bindFileFlag(createCmd, updateCmd)
+	bindDryRunFlag(createCmd, updateCmd)
 
 	getCmd := &cobra.Command{
 		Use:     "get [-g group]",
@@ -133,7 +139,93 @@ func newGroupCmd() *cobra.Command {
 		},
 	}
 
-	bindTLSRelatedFlag(createCmd, updateCmd, listCmd, getCmd, deleteCmd)
-	groupCmd.AddCommand(createCmd, updateCmd, listCmd, getCmd, deleteCmd)
+	var bundleFormat string
+	exportCmd := &cobra.Command{
+		Use:     "export [-g group] [--format yaml|json]",
+		Version: version.Build(),
+		Short:   "Export a group and all its schema objects as a bundle",
+		RunE: func(_ *cobra.Command, _ []string) (err error) {
+			if bundleFormat != "yaml" && bundleFormat != "json" {
+				return errors.Errorf("unsupported format %q, it should be yaml or json", bundleFormat)
+			}
+			return rest(parseGroupFromFlags, func(request request) (*resty.Response, error) {
+				return request.req.SetPathParam("group", request.group).Get(getPath("/api/v1/group/bundle/{group}"))
+			}, func(_ int, _ reqBody, body []byte) error {
+				return printBundle(body, bundleFormat)
+			}, enableTLS, insecure, cert)
+		},
+	}
+	exportCmd.Flags().StringVar(&bundleFormat, "format", "yaml", "The format of the bundle, yaml or json")
+
+	var targetGroup string
+	var overwrite bool
+	importCmd := &cobra.Command{
+		Use:     "import -f [file|-] [--target-group group] [--overwrite]",
+		Version: version.Build(),
+		Short:   "Import a bundle exported by the export command",
+		RunE: func(cmd *cobra.Command, _ []string) (err error) {
+			return rest(func() ([]reqBody, error) { return simpleParseFromYAML(cmd.InOrStdin()) },
+				func(request request) (*resty.Response, error) {
+					bundle := new(databasev1.SchemaBundle)
+					if err := protojson.Unmarshal(request.data, bundle); err != nil {
+						return nil, err
+					}
+					ir := &databasev1.GroupRegistryServiceImportRequest{
+						Bundle:      bundle,
+						TargetGroup: targetGroup,
+						Overwrite:   overwrite,
+					}
+					b, err := protojson.Marshal(ir)
+					if err != nil {
+						return nil, err
+					}
+					return request.req.SetBody(b).Post(getPath("/api/v1/group/bundle"))
+				},
+				func(_ int, _ reqBody, body []byte) error {
+					resp := new(databasev1.GroupRegistryServiceImportResponse)
+					if err := protojson.Unmarshal(body, resp); err != nil {
+						return err
+					}
+					fmt.Printf("%d objects are created, %d objects are updated", countSchemaInfo(resp.GetCreated()), countSchemaInfo(resp.GetUpdated()))
+					fmt.Println()
+					return nil
+				}, enableTLS, insecure, cert)
+		},
+	}
+	importCmd.Flags().StringVar(&targetGroup, "target-group", "", "Import the objects into this group instead of the one in the bundle")
+	importCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Update the objects that already exist")
+	bindFileFlag(importCmd)
+
+	scrubCmd := newGroupScrubCmd()
+
+	bindTLSRelatedFlag(createCmd, updateCmd, listCmd, getCmd, deleteCmd, exportCmd, importCmd, scrubCmd)
+	groupCmd.AddCommand(createCmd, updateCmd, listCmd, getCmd, deleteCmd, exportCmd, importCmd, scrubCmd)
+	groupCmd.AddCommand(newHistoryCmds(databasev1.SchemaKind_SCHEMA_KIND_GROUP, "group")...)
 	return groupCmd
 }
+
+func printBundle(body []byte, format string) error {
+	resp := new(databasev1.GroupRegistryServiceExportResponse)
+	if err := protojson.Unmarshal(body, resp); err != nil {
+		return err
+	}
+	b, err := protojson.MarshalOptions{Multiline: format == "json", Indent: "  "}.Marshal(resp.GetBundle())
+	if err != nil {
+		return err
+	}
+	if format == "yaml" {
+		if b, err = yaml.JSONToYAML(b); err != nil {
+			return err
+		}
+	}
+	fmt.Print(string(b))
+	if format == "json" {
+		fmt.Println()
+	}
+	return nil
+}
+
+func countSchemaInfo(info *databasev1.SchemaInfo) int {
+	return len(info.GetStreams()) + len(info.GetMeasures()) + len(info.GetTraces()) + len(info.GetProperties()) +
+		len(info.GetIndexRules()) + len(info.GetIndexRuleBindings()) + len(info.GetTopnAggregations())
+}
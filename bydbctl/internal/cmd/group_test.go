@@ -27,6 +27,7 @@ import (
 
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	"github.com/apache/skywalking-banyandb/bydbctl/internal/cmd"
+	"github.com/apache/skywalking-banyandb/pkg/test/flags"
 	"github.com/apache/skywalking-banyandb/pkg/test/helpers"
 	"github.com/apache/skywalking-banyandb/pkg/test/setup"
 )
@@ -136,6 +137,166 @@ resource_opts:
 		Expect(resp.Group).To(HaveLen(3)) // group1, group2, _deletion_task (internal group)
 	})
 
+	It("export and import group", func() {
+		rootCmd.SetArgs([]string{"stream", "create", "-f", "-"})
+		createStream := func() string {
+			rootCmd.SetIn(strings.NewReader(`
+metadata:
+  name: name1
+  group: group1
+tagFamilies:
+  - name: searchable
+    tags:
+      - name: trace_id
+        type: TAG_TYPE_STRING
+entity:
+  tagNames: ["trace_id"]`))
+			return capturer.CaptureStdout(func() {
+				err := rootCmd.Execute()
+				if err != nil {
+					GinkgoWriter.Printf("execution fails:%v", err)
+				}
+			})
+		}
+		Eventually(createStream, flags.EventuallyTimeout).Should(ContainSubstring("stream group1.name1 is created"))
+		// export
+		rootCmd.SetArgs([]string{"group", "export", "-g", "group1"})
+		bundle := capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		exported := new(databasev1.SchemaBundle)
+		helpers.UnmarshalYAML([]byte(bundle), exported)
+		Expect(exported.Version).To(Equal("v1"))
+		Expect(exported.Group.Metadata.Name).To(Equal("group1"))
+		Expect(exported.Streams).To(HaveLen(1))
+		Expect(exported.Streams[0].Metadata.ModRevision).To(BeZero())
+		// import under a new group name
+		rootCmd.SetArgs([]string{"group", "import", "-f", "-", "--target-group", "group3"})
+		rootCmd.SetIn(strings.NewReader(bundle))
+		out := capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		Expect(out).To(ContainSubstring("1 objects are created, 0 objects are updated"))
+		Eventually(func(g Gomega) {
+			rootCmd.SetArgs([]string{"stream", "get", "-g", "group3", "-n", "name1"})
+			out := capturer.CaptureStdout(func() {
+				g.Expect(rootCmd.Execute()).NotTo(HaveOccurred())
+			})
+			resp := new(databasev1.StreamRegistryServiceGetResponse)
+			helpers.UnmarshalYAML([]byte(out), resp)
+			g.Expect(resp.Stream.Metadata.Group).To(Equal("group3"))
+		}, flags.EventuallyTimeout).Should(Succeed())
+		// importing again fails without overwrite
+		rootCmd.SetArgs([]string{"group", "import", "-f", "-", "--target-group", "group3"})
+		rootCmd.SetIn(strings.NewReader(bundle))
+		Expect(rootCmd.Execute()).To(HaveOccurred())
+		rootCmd.SetArgs([]string{"group", "import", "-f", "-", "--target-group", "group3", "--overwrite"})
+		rootCmd.SetIn(strings.NewReader(bundle))
+		out = capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		Expect(out).To(ContainSubstring("0 objects are created, 1 objects are updated"))
+	})
+
+	It("export and import a measure group without the server maintained measures", func() {
+		rootCmd.SetArgs([]string{"group", "create", "-f", "-"})
+		rootCmd.SetIn(strings.NewReader(`
+metadata:
+  name: mgroup1
+catalog: CATALOG_MEASURE
+resource_opts:
+  shard_num: 1
+  segment_interval:
+    unit: UNIT_DAY
+    num: 1
+  ttl:
+    unit: UNIT_DAY
+    num: 7`))
+		capturer.CaptureStdout(func() {
+			Expect(rootCmd.Execute()).NotTo(HaveOccurred())
+		})
+		rootCmd.SetArgs([]string{"measure", "create", "-f", "-"})
+		createMeasure := func() string {
+			rootCmd.SetIn(strings.NewReader(`
+metadata:
+  name: name1
+  group: mgroup1
+tag_families:
+  - name: default
+    tags:
+      - name: id
+        type: TAG_TYPE_STRING
+entity:
+  tagNames: ["id"]`))
+			return capturer.CaptureStdout(func() {
+				err := rootCmd.Execute()
+				if err != nil {
+					GinkgoWriter.Printf("execution fails:%v", err)
+				}
+			})
+		}
+		Eventually(createMeasure, flags.EventuallyTimeout).Should(ContainSubstring("measure mgroup1.name1 is created"))
+		rootCmd.SetArgs([]string{"group", "export", "-g", "mgroup1"})
+		bundle := capturer.CaptureStdout(func() {
+			Expect(rootCmd.Execute()).NotTo(HaveOccurred())
+		})
+		exported := new(databasev1.SchemaBundle)
+		helpers.UnmarshalYAML([]byte(bundle), exported)
+		Expect(exported.Measures).To(HaveLen(1))
+		Expect(exported.Measures[0].Metadata.Name).To(Equal("name1"))
+		rootCmd.SetArgs([]string{"group", "import", "-f", "-", "--target-group", "mgroup2"})
+		rootCmd.SetIn(strings.NewReader(bundle))
+		out := capturer.CaptureStdout(func() {
+			Expect(rootCmd.Execute()).NotTo(HaveOccurred())
+		})
+		Expect(out).To(ContainSubstring("1 objects are created, 0 objects are updated"))
+	})
+
+	It("rejects a bundle referring to a missing index rule before importing any object", func() {
+		rootCmd.SetArgs([]string{"group", "import", "-f", "-"})
+		rootCmd.SetIn(strings.NewReader(`
+version: v1
+group:
+  metadata:
+    name: group4
+  catalog: CATALOG_STREAM
+  resource_opts:
+    shard_num: 1
+    segment_interval:
+      unit: UNIT_DAY
+      num: 1
+    ttl:
+      unit: UNIT_DAY
+      num: 7
+streams:
+  - metadata:
+      name: name1
+    tagFamilies:
+      - name: searchable
+        tags:
+          - name: trace_id
+            type: TAG_TYPE_STRING
+    entity:
+      tagNames: ["trace_id"]
+indexRuleBindings:
+  - metadata:
+      name: binding1
+    rules: ["missing_rule"]
+    subject:
+      catalog: CATALOG_STREAM
+      name: name1
+    beginAt: "2021-04-15T01:30:15.01Z"
+    expireAt: "2121-04-15T01:30:15.01Z"`))
+		err := rootCmd.Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("index rule missing_rule"))
+		rootCmd.SetArgs([]string{"group", "get", "-g", "group4"})
+		Expect(rootCmd.Execute()).To(HaveOccurred())
+	})
+
 	AfterEach(func() {
 		deferFunc()
 	})
@@ -0,0 +1,117 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/version"
+)
+
+const (
+	historyPath      = "/api/v1/schema/history/{kind}/{group}/{name}"
+	groupHistoryPath = "/api/v1/schema/history/{kind}/{name}"
+)
+
+// newHistoryCmds returns the history and rollback commands of a schema kind.
+// The name of a group is specified by the group flag, and the other kinds require the name flag.
+func newHistoryCmds(kind databasev1.SchemaKind, label string) []*cobra.Command {
+	isGroup := kind == databasev1.SchemaKind_SCHEMA_KIND_GROUP
+	usage := "[-g group] -n name"
+	if isGroup {
+		usage = "[-g group]"
+	}
+	params := func() ([]reqBody, error) {
+		if isGroup {
+			requests, err := parseGroupFromFlags()
+			if err != nil {
+				return nil, err
+			}
+			requests[0].name = requests[0].group
+			return requests, nil
+		}
+		return parseFromFlags()
+	}
+	objectName := func(reqBody reqBody) string {
+		if isGroup {
+			return reqBody.name
+		}
+		return reqBody.group + "." + reqBody.name
+	}
+
+	historyCmd := &cobra.Command{
+		Use:     "history " + usage,
+		Version: version.Build(),
+		Short:   fmt.Sprintf("List the previous versions of a %s", label),
+		RunE: func(_ *cobra.Command, _ []string) (err error) {
+			return rest(params, func(request request) (*resty.Response, error) {
+				req := request.req.SetPathParam("kind", kind.String()).SetPathParam("name", request.name)
+				if isGroup {
+					return req.Get(getPath(groupHistoryPath))
+				}
+				return req.SetPathParam("group", request.group).Get(getPath(historyPath))
+			}, yamlPrinter, enableTLS, insecure, cert)
+		},
+	}
+
+	var revision int64
+	rollbackCmd := &cobra.Command{
+		Use:     "rollback " + usage + " --revision revision",
+		Version: version.Build(),
+		Short:   fmt.Sprintf("Roll a %s back to a previous version", label),
+		RunE: func(_ *cobra.Command, _ []string) (err error) {
+			return rest(params, func(request request) (*resty.Response, error) {
+				md := &commonv1.Metadata{Name: request.name}
+				if !isGroup {
+					md.Group = request.group
+				}
+				b, err := protojson.Marshal(&databasev1.SchemaHistoryServiceRollbackRequest{
+					Kind:        kind,
+					Metadata:    md,
+					ModRevision: revision,
+				})
+				if err != nil {
+					return nil, err
+				}
+				return request.req.SetBody(b).Post(getPath("/api/v1/schema/rollback"))
+			}, func(_ int, reqBody reqBody, body []byte) error {
+				resp := new(databasev1.SchemaHistoryServiceRollbackResponse)
+				if err := protojson.Unmarshal(body, resp); err != nil {
+					return err
+				}
+				fmt.Printf("%s %s is rolled back to revision %d, the new revision is %d", label, objectName(reqBody), revision, resp.GetModRevision())
+				fmt.Println()
+				return nil
+			}, enableTLS, insecure, cert)
+		},
+	}
+	rollbackCmd.Flags().Int64Var(&revision, "revision", 0, "the mod revision of the version to roll back to")
+	_ = rollbackCmd.MarkFlagRequired("revision")
+
+	if !isGroup {
+		bindNameFlag(historyCmd, rollbackCmd)
+	}
+	bindTLSRelatedFlag(historyCmd, rollbackCmd)
+	return []*cobra.Command{historyCmd, rollbackCmd}
+}
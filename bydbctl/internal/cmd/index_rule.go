@@ -53,6 +53,7 @@ func newIndexRuleCmd() *cobra.Command {
 					}
 					cr := &databasev1.IndexRuleRegistryServiceCreateRequest{
 						IndexRule: s,
+						DryRun:    dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -60,11 +61,11 @@ func newIndexRuleCmd() *cobra.Command {
 					}
 					return request.req.SetBody(b).Post(getPath(indexRuleSchemaPath))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("indexRule %s.%s is created", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -82,6 +83,7 @@ func newIndexRuleCmd() *cobra.Command {
 					}
 					cr := &databasev1.IndexRuleRegistryServiceUpdateRequest{
 						IndexRule: s,
+						DryRun:    dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -91,11 +93,11 @@ func newIndexRuleCmd() *cobra.Command {
 						SetPathParam("name", request.name).SetPathParam("group", request.group).
 						Put(getPath(indexRuleSchemaPathWithParams))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("indexRule %s.%s is updated", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -138,8 +140,12 @@ func newIndexRuleCmd() *cobra.Command {
 	}
 
 	bindFileFlag(createCmd, updateCmd)
+	bindDryRunFlag(createCmd, updateCmd)
 
-	bindTLSRelatedFlag(getCmd, createCmd, deleteCmd, updateCmd, listCmd)
-	indexRuleCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd)
+	usageCmd := newIndexRuleUsageCmd()
+
+	bindTLSRelatedFlag(getCmd, createCmd, deleteCmd, updateCmd, listCmd, usageCmd)
+	indexRuleCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd, usageCmd)
+	indexRuleCmd.AddCommand(newHistoryCmds(databasev1.SchemaKind_SCHEMA_KIND_INDEX_RULE, "indexRule")...)
 	return indexRuleCmd
 }
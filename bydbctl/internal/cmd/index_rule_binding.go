@@ -53,6 +53,7 @@ func newIndexRuleBindingCmd() *cobra.Command {
 					}
 					cr := &databasev1.IndexRuleBindingRegistryServiceCreateRequest{
 						IndexRuleBinding: s,
+						DryRun:           dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -60,11 +61,11 @@ func newIndexRuleBindingCmd() *cobra.Command {
 					}
 					return request.req.SetBody(b).Post(getPath(indexRuleBindingSchemaPath))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("indexRuleBinding %s.%s is created", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -82,6 +83,7 @@ func newIndexRuleBindingCmd() *cobra.Command {
 					}
 					cr := &databasev1.IndexRuleBindingRegistryServiceUpdateRequest{
 						IndexRuleBinding: s,
+						DryRun:           dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -91,11 +93,11 @@ func newIndexRuleBindingCmd() *cobra.Command {
 						SetPathParam("name", request.name).SetPathParam("group", request.group).
 						Put(getPath(indexRuleBindingSchemaPathWithParams))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("indexRuleBinding %s.%s is updated", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -138,8 +140,10 @@ func newIndexRuleBindingCmd() *cobra.Command {
 	}
 
 	bindFileFlag(createCmd, updateCmd)
+	bindDryRunFlag(createCmd, updateCmd)
 
 	bindTLSRelatedFlag(getCmd, createCmd, deleteCmd, updateCmd, listCmd)
 	indexRuleBindingCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd)
+	indexRuleBindingCmd.AddCommand(newHistoryCmds(databasev1.SchemaKind_SCHEMA_KIND_INDEX_RULE_BINDING, "indexRuleBinding")...)
 	return indexRuleBindingCmd
 }
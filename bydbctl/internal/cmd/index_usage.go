@@ -0,0 +1,112 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/version"
+)
+
+const (
+	groupContentPath  = "/api/v1/group/content/{group}"
+	defaultUnusedDays = 7
+)
+
+type indexRuleUsage struct {
+	LastUsedAt      *time.Time `json:"lastUsedAt,omitempty"`
+	TrackedSince    time.Time  `json:"trackedSince"`
+	IndexRule       string     `json:"indexRule"`
+	QueryHits       int64      `json:"queryHits"`
+	PostingListSize *int64     `json:"postingListSize,omitempty"`
+	DiskSizeBytes   int64      `json:"diskSizeBytes"`
+	Unused          bool       `json:"unused"`
+}
+
+func newIndexRuleUsageCmd() *cobra.Command {
+	usageCmd := &cobra.Command{
+		Use:     "usage [-g group] [--unused-days days]",
+		Version: version.Build(),
+		Short:   "Report how the queries use the indexRules of a group and flag the unused ones",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return rest(parseGroupFromFlags, func(request request) (*resty.Response, error) {
+				return request.req.SetPathParam("group", request.group).Get(getPath(groupContentPath))
+			}, func(index int, reqBody reqBody, body []byte) error {
+				resp := new(databasev1.GroupRegistryServiceInspectResponse)
+				if err := protojson.Unmarshal(body, resp); err != nil {
+					return err
+				}
+				report, err := json.Marshal(buildIndexRuleUsages(resp.GetDataInfo(), time.Now(), unusedDays))
+				if err != nil {
+					return err
+				}
+				return yamlPrinter(index, reqBody, report)
+			}, enableTLS, insecure, cert)
+		},
+	}
+	usageCmd.Flags().IntVarP(&unusedDays, "unused-days", "", defaultUnusedDays, "Flag the indexRules which no query has used for the days")
+	return usageCmd
+}
+
+// buildIndexRuleUsages merges the usages of the indexRules on all the data nodes.
+// An indexRule is unused if no query has used it in the last days,
+// and all the nodes have recorded its usage longer than the days.
+func buildIndexRuleUsages(dataInfo []*databasev1.DataInfo, now time.Time, days int) []*indexRuleUsage {
+	usages := make(map[string]*indexRuleUsage)
+	for _, di := range dataInfo {
+		for _, u := range di.GetIndexRuleUsages() {
+			merged, ok := usages[u.GetIndexRule()]
+			if !ok {
+				merged = &indexRuleUsage{IndexRule: u.GetIndexRule()}
+				usages[u.GetIndexRule()] = merged
+			}
+			merged.QueryHits += u.GetQueryHits()
+			if u.PostingListSize != nil {
+				if merged.PostingListSize == nil {
+					merged.PostingListSize = new(int64)
+				}
+				*merged.PostingListSize += u.GetPostingListSize()
+			}
+			merged.DiskSizeBytes += u.GetDiskSizeBytes()
+			if u.GetLastUsedAt() != nil {
+				lastUsed := u.GetLastUsedAt().AsTime()
+				if merged.LastUsedAt == nil || lastUsed.After(*merged.LastUsedAt) {
+					merged.LastUsedAt = &lastUsed
+				}
+			}
+			if trackedSince := u.GetTrackedSince().AsTime(); trackedSince.After(merged.TrackedSince) {
+				merged.TrackedSince = trackedSince
+			}
+		}
+	}
+	threshold := now.Add(-time.Duration(days) * 24 * time.Hour)
+	result := make([]*indexRuleUsage, 0, len(usages))
+	for _, u := range usages {
+		u.Unused = !u.TrackedSince.After(threshold) && (u.LastUsedAt == nil || u.LastUsedAt.Before(threshold))
+		result = append(result, u)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].IndexRule < result[j].IndexRule })
+	return result
+}
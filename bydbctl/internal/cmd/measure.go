@@ -57,6 +57,7 @@ func newMeasureCmd() *cobra.Command {
 					}
 					cr := &databasev1.MeasureRegistryServiceCreateRequest{
 						Measure: s,
+						DryRun:  dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -64,11 +65,11 @@ func newMeasureCmd() *cobra.Command {
 					}
 					return request.req.SetBody(b).Post(getPath(measureSchemaPath))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("measure %s.%s is created", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -86,6 +87,7 @@ func newMeasureCmd() *cobra.Command {
 					}
 					cr := &databasev1.MeasureRegistryServiceUpdateRequest{
 						Measure: s,
+						DryRun:  dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -95,11 +97,11 @@ func newMeasureCmd() *cobra.Command {
 						SetPathParam("name", request.name).SetPathParam("group", request.group).
 						Put(getPath(measureSchemaPathWithParams))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("measure %s.%s is updated", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -154,9 +156,11 @@ func newMeasureCmd() *cobra.Command {
 		},
 	}
 	bindFileFlag(createCmd, updateCmd, queryCmd)
+	bindDryRunFlag(createCmd, updateCmd)
 	bindTimeRangeFlag(queryCmd)
 
 	bindTLSRelatedFlag(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd)
-	measureCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd)
+	measureCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd, newWriteCmd("measure", &measureWriter{}))
+	measureCmd.AddCommand(newHistoryCmds(databasev1.SchemaKind_SCHEMA_KIND_MEASURE, "measure")...)
 	return measureCmd
 }
@@ -0,0 +1,137 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	measurev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/measure/v1"
+)
+
+type measureWriter struct {
+	schema *databasev1.Measure
+}
+
+func (w *measureWriter) loadSchema(ctx context.Context, conn *grpc.ClientConn, md *commonv1.Metadata) (*commonv1.Metadata, error) {
+	resp, err := databasev1.NewMeasureRegistryServiceClient(conn).Get(ctx, &databasev1.MeasureRegistryServiceGetRequest{Metadata: md})
+	if err != nil {
+		return nil, err
+	}
+	w.schema = resp.GetMeasure()
+	return w.schema.GetMetadata(), nil
+}
+
+func (w *measureWriter) fromDocument(doc []byte, md *commonv1.Metadata) (writeItem, error) {
+	req := new(measurev1.WriteRequest)
+	if err := protojson.Unmarshal(doc, req); err != nil {
+		return writeItem{}, err
+	}
+	var err error
+	if req.Metadata, err = defaultMetadata(req.GetMetadata(), md); err != nil {
+		return writeItem{}, err
+	}
+	return writeItem{request: req}, nil
+}
+
+func (w *measureWriter) csvTargets() map[string]struct{} {
+	targets := map[string]struct{}{"timestamp": {}, "version": {}}
+	for _, tf := range w.schema.GetTagFamilies() {
+		for _, t := range tf.GetTags() {
+			targets[t.GetName()] = struct{}{}
+		}
+	}
+	for _, f := range w.schema.GetFields() {
+		targets[f.GetName()] = struct{}{}
+	}
+	return targets
+}
+
+func (w *measureWriter) fromCSV(record []string, columns map[string]int, md *commonv1.Metadata) (writeItem, error) {
+	ts, err := parseCSVTimestamp(csvValue(record, columns, "timestamp"))
+	if err != nil {
+		return writeItem{}, err
+	}
+	tagFamilies, err := csvTagFamilies(w.schema.GetTagFamilies(), record, columns)
+	if err != nil {
+		return writeItem{}, err
+	}
+	dp := &measurev1.DataPointValue{Timestamp: ts, TagFamilies: tagFamilies}
+	for _, f := range w.schema.GetFields() {
+		v, err := parseCSVFieldValue(f.GetFieldType(), csvValue(record, columns, f.GetName()))
+		if err != nil {
+			return writeItem{}, fmt.Errorf("field %s: %w", f.GetName(), err)
+		}
+		dp.Fields = append(dp.Fields, v)
+	}
+	if v := csvValue(record, columns, "version"); v != "" {
+		if dp.Version, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return writeItem{}, err
+		}
+	}
+	return writeItem{request: &measurev1.WriteRequest{Metadata: md, DataPoint: dp}}, nil
+}
+
+func (w *measureWriter) writeBatch(ctx context.Context, conn *grpc.ClientConn, items []writeItem) ([]writeFailure, error) {
+	client, err := measurev1.NewMeasureServiceClient(conn).Write(ctx)
+	if err != nil {
+		return nil, err
+	}
+	firstID, records := assignMessageIDs(items)
+	var last *commonv1.Metadata
+	for i, item := range items {
+		req := item.request.(*measurev1.WriteRequest)
+		if err = client.Send(&measurev1.WriteRequest{
+			Metadata:      metadataForBatch(req.GetMetadata(), last),
+			DataPoint:     req.GetDataPoint(),
+			MessageId:     firstID + uint64(i),
+			DataPointSpec: req.GetDataPointSpec(),
+		}); err != nil {
+			return nil, err
+		}
+		last = req.GetMetadata()
+	}
+	if err = client.CloseSend(); err != nil {
+		return nil, err
+	}
+	var failures []writeFailure
+	for {
+		resp, err := client.Recv()
+		if errors.Is(err, io.EOF) {
+			return failures, nil
+		}
+		if err != nil {
+			return failures, err
+		}
+		if !isSucceed(resp.GetStatus()) {
+			failures = append(failures, writeFailure{
+				record: records[resp.GetMessageId()],
+				id:     resp.GetMetadata().GetGroup() + "." + resp.GetMetadata().GetName(),
+				status: resp.GetStatus(),
+			})
+		}
+	}
+}
@@ -77,6 +77,7 @@ func newPropertyCmd() *cobra.Command {
 					}
 					cr := &databasev1.PropertyRegistryServiceCreateRequest{
 						Property: s,
+						DryRun:   dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -84,11 +85,11 @@ func newPropertyCmd() *cobra.Command {
 					}
 					return request.req.SetBody(b).Post(getPath(propertySchemaPath))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("property schema %s.%s is created", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -106,6 +107,7 @@ func newPropertyCmd() *cobra.Command {
 					}
 					cr := &databasev1.PropertyRegistryServiceUpdateRequest{
 						Property: s,
+						DryRun:   dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -115,11 +117,11 @@ func newPropertyCmd() *cobra.Command {
 						SetPathParam("name", request.name).SetPathParam("group", request.group).
 						Put(getPath(propertySchemaPathWithParams))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("property schema %s.%s is updated", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -161,6 +163,7 @@ func newPropertyCmd() *cobra.Command {
 		},
 	}
 	bindFileFlag(createSchemaCmd, updateSchemaCmd)
+	bindDryRunFlag(createSchemaCmd, updateSchemaCmd)
 
 	// Data commands
 	applyDataCmd := &cobra.Command{
@@ -222,6 +225,7 @@ func newPropertyCmd() *cobra.Command {
 
 	// Add schema commands to schema subcommand
 	schemaCmd.AddCommand(createSchemaCmd, updateSchemaCmd, getSchemaCmd, deleteSchemaCmd, listSchemaCmd)
+	schemaCmd.AddCommand(newHistoryCmds(databasev1.SchemaKind_SCHEMA_KIND_PROPERTY, "property schema")...)
 
 	// Add data commands to data subcommand
 	dataCmd.AddCommand(applyDataCmd, deleteDataCmd, queryDataCmd)
@@ -0,0 +1,271 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	bydbqlv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/bydbql/v1"
+	"github.com/apache/skywalking-banyandb/pkg/version"
+)
+
+const (
+	bydbqlQueryPath    = "/api/v1/bydbql/query"
+	qlPrompt           = "bydbql> "
+	qlContinuePrompt   = "     -> "
+	qlHistoryFileName  = ".bydbctl_history"
+	qlOutputTable      = "table"
+	qlOutputJSON       = "json"
+	qlOutputYAML       = "yaml"
+	qlOutputCSV        = "csv"
+	qlMetaCommandUsage = `Meta commands:
+  \timing [on|off]              toggle or set printing of the execution time
+  \format table|json|yaml|csv   change the output format
+  \history                      print the statement history
+  \help                         show this help
+  \quit                         exit the shell
+Statements end with ";" and may span multiple lines. Press Tab to complete keywords, groups, resources and tags.`
+)
+
+type qlShell struct {
+	editor    *lineEditor
+	completer *qlCompleter
+	out       io.Writer
+	format    string
+	timing    bool
+}
+
+func newQLCmd() *cobra.Command {
+	var statements, historyFile string
+	shell := &qlShell{}
+	qlCmd := &cobra.Command{
+		Use:     "ql [-e statements]",
+		Version: version.Build(),
+		Short:   "Interactive BydbQL shell",
+		Long: `Run BydbQL statements against the BydbQL service.
+Without "-e", statements are read from the standard input. When it is a terminal, an interactive shell
+with history, multi-line input and Tab completion is started.
+
+` + qlMetaCommandUsage,
+		RunE: func(cmd *cobra.Command, _ []string) (err error) {
+			if err = checkQLOutputFormat(shell.format); err != nil {
+				return err
+			}
+			shell.out = cmd.OutOrStdout()
+			if statements != "" {
+				return shell.runScript(statements)
+			}
+			shell.completer = newQLCompleter()
+			shell.editor = newLineEditor(cmd.InOrStdin(), cmd.OutOrStdout(), historyFile, shell.completer.complete)
+			return shell.repl()
+		},
+	}
+	qlCmd.Flags().StringVarP(&statements, "execute", "e", "", "Execute the statements separated by \";\" and exit")
+	qlCmd.Flags().StringVarP(&shell.format, "output", "o", qlOutputTable, "Output format: table, json, yaml or csv")
+	qlCmd.Flags().BoolVar(&shell.timing, "timing", false, "Print the execution time of each statement")
+	qlCmd.Flags().StringVar(&historyFile, "history-file", defaultQLHistoryFile(), "The file to persist the statement history")
+	bindTLSRelatedFlag(qlCmd)
+	return qlCmd
+}
+
+func defaultQLHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, qlHistoryFileName)
+}
+
+func checkQLOutputFormat(format string) error {
+	switch format {
+	case qlOutputTable, qlOutputJSON, qlOutputYAML, qlOutputCSV:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q, it should be one of table, json, yaml and csv", format)
+	}
+}
+
+// runScript executes the statements one by one and stops at the first failure.
+func (s *qlShell) runScript(script string) error {
+	statements, remaining := splitStatements(script)
+	if strings.TrimSpace(remaining) != "" {
+		statements = append(statements, remaining)
+	}
+	for _, stmt := range statements {
+		if err := s.execute(stmt); err != nil {
+			return fmt.Errorf("failed to execute %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+func (s *qlShell) repl() error {
+	if s.editor.interactive {
+		fmt.Fprintln(s.out, "Type \"\\help\" for help.")
+	}
+	var buffer strings.Builder
+	for {
+		prompt := qlPrompt
+		if buffer.Len() > 0 {
+			prompt = qlContinuePrompt
+		}
+		line, err := s.editor.readLine(prompt)
+		if errors.Is(err, errInterrupted) {
+			buffer.Reset()
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			if strings.TrimSpace(buffer.String()) != "" {
+				s.executeAndReport(buffer.String())
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		trimmed := strings.TrimSpace(line)
+		if buffer.Len() == 0 && strings.HasPrefix(trimmed, "\\") {
+			s.editor.addHistory(trimmed)
+			if quit := s.meta(trimmed); quit {
+				return nil
+			}
+			continue
+		}
+		if trimmed == "" && buffer.Len() == 0 {
+			continue
+		}
+		buffer.WriteString(line)
+		buffer.WriteByte('\n')
+		statements, remaining := splitStatements(buffer.String())
+		for _, stmt := range statements {
+			s.editor.addHistory(stmt + ";")
+			s.executeAndReport(stmt)
+		}
+		buffer.Reset()
+		if strings.TrimSpace(remaining) != "" {
+			buffer.WriteString(remaining)
+		}
+	}
+}
+
+func (s *qlShell) executeAndReport(stmt string) {
+	if err := s.execute(stmt); err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR:", err)
+	}
+}
+
+// meta handles a backslash command and reports whether the shell should exit.
+func (s *qlShell) meta(command string) bool {
+	fields := strings.Fields(command)
+	switch fields[0] {
+	case "\\q", "\\quit", "\\exit":
+		return true
+	case "\\timing":
+		switch {
+		case len(fields) == 1:
+			s.timing = !s.timing
+		case strings.EqualFold(fields[1], "on"):
+			s.timing = true
+		case strings.EqualFold(fields[1], "off"):
+			s.timing = false
+		default:
+			fmt.Fprintln(os.Stderr, "ERROR: \\timing accepts on or off")
+			return false
+		}
+		if s.timing {
+			fmt.Fprintln(s.out, "Timing is on.")
+		} else {
+			fmt.Fprintln(s.out, "Timing is off.")
+		}
+	case "\\format", "\\o":
+		if len(fields) != 2 {
+			fmt.Fprintf(s.out, "Output format is %s.\n", s.format)
+			return false
+		}
+		if err := checkQLOutputFormat(fields[1]); err != nil {
+			fmt.Fprintln(os.Stderr, "ERROR:", err)
+			return false
+		}
+		s.format = fields[1]
+		fmt.Fprintf(s.out, "Output format is %s.\n", s.format)
+	case "\\history":
+		for i, h := range s.editor.history {
+			fmt.Fprintf(s.out, "%5d  %s\n", i+1, h)
+		}
+	case "\\h", "\\help", "\\?":
+		fmt.Fprintln(s.out, qlMetaCommandUsage)
+	default:
+		fmt.Fprintf(os.Stderr, "ERROR: unknown command %s, type \\help for help\n", fields[0])
+	}
+	return false
+}
+
+func (s *qlShell) execute(stmt string) error {
+	begin := time.Now()
+	err := rest(func() ([]reqBody, error) {
+		b, err := protojson.Marshal(&bydbqlv1.QueryRequest{Query: stmt})
+		if err != nil {
+			return nil, err
+		}
+		return []reqBody{{data: b}}, nil
+	}, func(request request) (*resty.Response, error) {
+		return request.req.SetBody(request.data).Post(getPath(bydbqlQueryPath))
+	}, func(_ int, _ reqBody, body []byte) error {
+		return printQLResult(s.out, s.format, body)
+	}, enableTLS, insecure, cert)
+	if err != nil {
+		return err
+	}
+	if s.timing {
+		fmt.Fprintf(s.out, "Time: %.3f ms\n", float64(time.Since(begin).Microseconds())/1000)
+	}
+	return nil
+}
+
+// splitStatements splits the input on semicolons outside of quoted strings.
+// The remaining text after the last semicolon is returned separately.
+func splitStatements(input string) (statements []string, remaining string) {
+	var quote rune
+	start := 0
+	for i, r := range input {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ';':
+			if stmt := strings.TrimSpace(input[start:i]); stmt != "" {
+				statements = append(statements, stmt)
+			}
+			start = i + 1
+		}
+	}
+	return statements, input[start:]
+}
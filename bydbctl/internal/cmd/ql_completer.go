@@ -0,0 +1,208 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/bydbql"
+)
+
+// qlCompleter completes BydbQL keywords and the groups, resources and tags
+// fetched from the registry services. The schemas are loaded on the first completion.
+type qlCompleter struct {
+	// resources maps a catalog keyword, like MEASURE, to the resource names.
+	resources map[string][]string
+	// tags maps a resource name to its tag and field names.
+	tags     map[string][]string
+	keywords []string
+	groups   []string
+	once     sync.Once
+}
+
+func newQLCompleter() *qlCompleter {
+	return &qlCompleter{
+		keywords:  bydbql.Keywords(),
+		resources: make(map[string][]string),
+		tags:      make(map[string][]string),
+	}
+}
+
+func (c *qlCompleter) complete(head, word string) []string {
+	c.once.Do(c.load)
+	tokens := strings.Fields(strings.ToUpper(head))
+	if word != "" && len(tokens) > 0 {
+		tokens = tokens[:len(tokens)-1]
+	}
+	var candidates []string
+	switch prev := lastToken(tokens); prev {
+	case "STREAM", "MEASURE", "TRACE", "PROPERTY":
+		candidates = c.resources[prev]
+	case "IN", "(", ",":
+		if prev == "IN" || lastKeyword(tokens, c.keywords) == "IN" {
+			candidates = c.groups
+			break
+		}
+		fallthrough
+	default:
+		candidates = append(candidates, c.keywords...)
+		if resource := resourceInFrom(head); resource != "" {
+			candidates = append(candidates, c.tags[resource]...)
+		}
+	}
+	return filterByPrefix(candidates, word)
+}
+
+func (c *qlCompleter) load() {
+	groupResp := new(databasev1.GroupRegistryServiceListResponse)
+	if err := fetchSchema("/api/v1/group/schema/lists", "", groupResp); err != nil {
+		return
+	}
+	for _, g := range groupResp.GetGroup() {
+		group := g.GetMetadata().GetName()
+		c.groups = append(c.groups, group)
+		switch g.GetCatalog() {
+		case commonv1.Catalog_CATALOG_STREAM:
+			resp := new(databasev1.StreamRegistryServiceListResponse)
+			if fetchSchema(streamListPath, group, resp) != nil {
+				continue
+			}
+			for _, s := range resp.GetStream() {
+				c.addResource("STREAM", s.GetMetadata().GetName(), tagFamilyNames(s.GetTagFamilies())...)
+			}
+		case commonv1.Catalog_CATALOG_MEASURE:
+			resp := new(databasev1.MeasureRegistryServiceListResponse)
+			if fetchSchema(measureListPath, group, resp) != nil {
+				continue
+			}
+			for _, m := range resp.GetMeasure() {
+				names := tagFamilyNames(m.GetTagFamilies())
+				for _, f := range m.GetFields() {
+					names = append(names, f.GetName())
+				}
+				c.addResource("MEASURE", m.GetMetadata().GetName(), names...)
+			}
+		case commonv1.Catalog_CATALOG_TRACE:
+			resp := new(databasev1.TraceRegistryServiceListResponse)
+			if fetchSchema(traceListPath, group, resp) != nil {
+				continue
+			}
+			for _, t := range resp.GetTrace() {
+				names := make([]string, 0, len(t.GetTags()))
+				for _, tag := range t.GetTags() {
+					names = append(names, tag.GetName())
+				}
+				c.addResource("TRACE", t.GetMetadata().GetName(), names...)
+			}
+		case commonv1.Catalog_CATALOG_PROPERTY:
+			resp := new(databasev1.PropertyRegistryServiceListResponse)
+			if fetchSchema("/api/v1/property/schema/lists/{group}", group, resp) != nil {
+				continue
+			}
+			for _, p := range resp.GetProperties() {
+				names := make([]string, 0, len(p.GetTags()))
+				for _, tag := range p.GetTags() {
+					names = append(names, tag.GetName())
+				}
+				c.addResource("PROPERTY", p.GetMetadata().GetName(), names...)
+			}
+		}
+	}
+}
+
+func (c *qlCompleter) addResource(catalog, name string, tags ...string) {
+	c.resources[catalog] = append(c.resources[catalog], name)
+	c.tags[name] = append(c.tags[name], tags...)
+}
+
+func tagFamilyNames(tagFamilies []*databasev1.TagFamilySpec) []string {
+	var names []string
+	for _, tf := range tagFamilies {
+		for _, t := range tf.GetTags() {
+			names = append(names, t.GetName())
+		}
+	}
+	return names
+}
+
+// fetchSchema issues a GET request to a registry path and decodes the response into msg.
+func fetchSchema(path, group string, msg proto.Message) error {
+	return rest(nil, func(request request) (*resty.Response, error) {
+		if group != "" {
+			request.req.SetPathParam("group", group)
+		}
+		return request.req.Get(getPath(path))
+	}, func(_ int, _ reqBody, body []byte) error {
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, msg)
+	}, enableTLS, insecure, cert)
+}
+
+func lastToken(tokens []string) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return tokens[len(tokens)-1]
+}
+
+func lastKeyword(tokens, keywords []string) string {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		for _, k := range keywords {
+			if tokens[i] == k {
+				return k
+			}
+		}
+	}
+	return ""
+}
+
+// resourceInFrom returns the resource name following "FROM <catalog>" in the statement.
+func resourceInFrom(stmt string) string {
+	fields := strings.Fields(stmt)
+	for i := 0; i+2 < len(fields); i++ {
+		if strings.EqualFold(fields[i], "FROM") {
+			return strings.TrimRight(fields[i+2], ";,")
+		}
+	}
+	return ""
+}
+
+func filterByPrefix(candidates []string, prefix string) []string {
+	seen := make(map[string]struct{}, len(candidates))
+	var result []string
+	upperPrefix := strings.ToUpper(prefix)
+	for _, c := range candidates {
+		if !strings.HasPrefix(strings.ToUpper(c), upperPrefix) {
+			continue
+		}
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+		result = append(result, c)
+	}
+	sort.Strings(result)
+	return result
+}
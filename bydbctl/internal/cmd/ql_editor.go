@@ -0,0 +1,310 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+const (
+	keyCtrlA     = 1
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyBackspace = 8
+	keyTab       = 9
+	keyLF        = 10
+	keyCtrlL     = 12
+	keyCR        = 13
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+var errInterrupted = errors.New("interrupted")
+
+// completeFn returns the completion candidates of the word ending at the cursor.
+// The head is the text in front of the cursor, the word is its last token.
+type completeFn func(head, word string) []string
+
+// lineEditor is a minimal line editor supporting history navigation and tab completion.
+// It falls back to plain line reading when the input is not a terminal.
+type lineEditor struct {
+	in           *bufio.Reader
+	out          io.Writer
+	complete     completeFn
+	historyFile  string
+	history      []string
+	fd           int
+	historyLimit int
+	interactive  bool
+}
+
+func newLineEditor(in io.Reader, out io.Writer, historyFile string, complete completeFn) *lineEditor {
+	e := &lineEditor{
+		in:           bufio.NewReader(in),
+		out:          out,
+		complete:     complete,
+		historyFile:  historyFile,
+		historyLimit: 1000,
+		fd:           -1,
+	}
+	if f, ok := in.(*os.File); ok {
+		e.fd = int(f.Fd())
+		e.interactive = isTerminal(e.fd)
+	}
+	e.loadHistory()
+	return e
+}
+
+// readLine reads a line after printing the prompt. It returns io.EOF when the input is exhausted
+// and errInterrupted when the user presses Ctrl-C.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	if !e.interactive {
+		return e.readPlainLine()
+	}
+	restore, err := makeRaw(e.fd)
+	if err != nil {
+		e.interactive = false
+		return e.readPlainLine()
+	}
+	defer func() {
+		_ = restore()
+	}()
+	return e.edit(prompt)
+}
+
+func (e *lineEditor) readPlainLine() (string, error) {
+	line, err := e.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (e *lineEditor) edit(prompt string) (string, error) {
+	var buf []rune
+	pos := 0
+	historyIdx := len(e.history)
+	var pending []rune
+	e.redraw(prompt, buf, pos)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch r {
+		case keyCR, keyLF:
+			fmt.Fprint(e.out, "\r\n")
+			return string(buf), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case keyCtrlD:
+			if len(buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(buf) {
+				buf = append(buf[:pos], buf[pos+1:]...)
+			}
+		case keyBackspace, keyDelete:
+			if pos > 0 {
+				buf = append(buf[:pos-1], buf[pos:]...)
+				pos--
+			}
+		case keyCtrlA:
+			pos = 0
+		case keyCtrlE:
+			pos = len(buf)
+		case keyCtrlU:
+			buf = buf[pos:]
+			pos = 0
+		case keyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case keyTab:
+			buf, pos = e.completeAt(prompt, buf, pos)
+		case keyEscape:
+			seq, seqErr := e.readEscape()
+			if seqErr != nil {
+				return "", seqErr
+			}
+			switch seq {
+			case 'A':
+				if historyIdx > 0 {
+					if historyIdx == len(e.history) {
+						pending = buf
+					}
+					historyIdx--
+					buf = []rune(e.history[historyIdx])
+					pos = len(buf)
+				}
+			case 'B':
+				if historyIdx < len(e.history) {
+					historyIdx++
+					if historyIdx == len(e.history) {
+						buf = pending
+					} else {
+						buf = []rune(e.history[historyIdx])
+					}
+					pos = len(buf)
+				}
+			case 'C':
+				if pos < len(buf) {
+					pos++
+				}
+			case 'D':
+				if pos > 0 {
+					pos--
+				}
+			case 'H':
+				pos = 0
+			case 'F':
+				pos = len(buf)
+			}
+		default:
+			if !unicode.IsPrint(r) {
+				continue
+			}
+			buf = append(buf[:pos], append([]rune{r}, buf[pos:]...)...)
+			pos++
+		}
+		e.redraw(prompt, buf, pos)
+	}
+}
+
+// readEscape consumes an ANSI escape sequence and returns its final byte.
+func (e *lineEditor) readEscape() (rune, error) {
+	r, _, err := e.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if r != '[' && r != 'O' {
+		return r, nil
+	}
+	for {
+		r, _, err = e.in.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if r >= 0x40 && r <= 0x7e {
+			return r, nil
+		}
+	}
+}
+
+func (e *lineEditor) redraw(prompt string, buf []rune, pos int) {
+	fmt.Fprintf(e.out, "\r\x1b[K%s%s", prompt, string(buf))
+	if back := len(buf) - pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+func (e *lineEditor) completeAt(prompt string, buf []rune, pos int) ([]rune, int) {
+	if e.complete == nil {
+		return buf, pos
+	}
+	start := pos
+	for start > 0 && !isWordSeparator(buf[start-1]) {
+		start--
+	}
+	head := string(buf[:pos])
+	word := string(buf[start:pos])
+	candidates := e.complete(head, word)
+	if len(candidates) == 0 {
+		return buf, pos
+	}
+	replacement := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		replacement += " "
+	}
+	if len([]rune(replacement)) <= len([]rune(word)) {
+		fmt.Fprint(e.out, "\r\n")
+		fmt.Fprint(e.out, strings.Join(candidates, "  "))
+		fmt.Fprint(e.out, "\r\n")
+		e.redraw(prompt, buf, pos)
+		return buf, pos
+	}
+	tail := append([]rune(replacement), buf[pos:]...)
+	buf = append(buf[:start:start], tail...)
+	return buf, start + len([]rune(replacement))
+}
+
+func isWordSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == ',' || r == '(' || r == ')' || r == '='
+}
+
+func commonPrefix(candidates []string) string {
+	prefix := []rune(candidates[0])
+	for _, c := range candidates[1:] {
+		cr := []rune(c)
+		i := 0
+		for i < len(prefix) && i < len(cr) && prefix[i] == cr[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	return string(prefix)
+}
+
+// addHistory records an entry and appends it to the history file.
+func (e *lineEditor) addHistory(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == entry) {
+		return
+	}
+	e.history = append(e.history, entry)
+	if len(e.history) > e.historyLimit {
+		e.history = e.history[len(e.history)-e.historyLimit:]
+	}
+	if e.historyFile == "" {
+		return
+	}
+	f, err := os.OpenFile(e.historyFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = fmt.Fprintln(f, strings.ReplaceAll(entry, "\n", " "))
+}
+
+func (e *lineEditor) loadHistory() {
+	if e.historyFile == "" {
+		return
+	}
+	f, err := os.Open(e.historyFile)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			e.history = append(e.history, line)
+		}
+	}
+	if len(e.history) > e.historyLimit {
+		e.history = e.history[len(e.history)-e.historyLimit:]
+	}
+}
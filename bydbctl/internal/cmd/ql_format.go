@@ -0,0 +1,294 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// qlTable is the tabular view of a BydbQL response.
+type qlTable struct {
+	index   map[string]int
+	columns []string
+	rows    []map[string]string
+}
+
+func newQLTable(columns ...string) *qlTable {
+	t := &qlTable{index: make(map[string]int)}
+	for _, c := range columns {
+		t.addColumn(c)
+	}
+	return t
+}
+
+func (t *qlTable) addColumn(column string) {
+	if _, ok := t.index[column]; ok {
+		return
+	}
+	t.index[column] = len(t.columns)
+	t.columns = append(t.columns, column)
+}
+
+func (t *qlTable) newRow() map[string]string {
+	row := make(map[string]string)
+	t.rows = append(t.rows, row)
+	return row
+}
+
+func (t *qlTable) set(row map[string]string, column string, value any) {
+	t.addColumn(column)
+	row[column] = qlValueString(value)
+}
+
+func (t *qlTable) setTags(row map[string]string, tags any) {
+	for _, tag := range asSlice(tags) {
+		m := asMap(tag)
+		key, _ := m["key"].(string)
+		t.set(row, key, m["value"])
+	}
+}
+
+func (t *qlTable) setTagFamilies(row map[string]string, tagFamilies any) {
+	for _, tf := range asSlice(tagFamilies) {
+		t.setTags(row, asMap(tf)["tags"])
+	}
+}
+
+func (t *qlTable) records() [][]string {
+	records := make([][]string, 0, len(t.rows))
+	for _, row := range t.rows {
+		record := make([]string, len(t.columns))
+		for i, c := range t.columns {
+			record[i] = row[c]
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// toQLTable flattens the JSON form of bydbqlv1.QueryResponse into rows.
+func toQLTable(body []byte) (*qlTable, error) {
+	var resp map[string]any
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	switch {
+	case resp["streamResult"] != nil:
+		t := newQLTable("element_id", "timestamp")
+		for _, e := range asSlice(asMap(resp["streamResult"])["elements"]) {
+			element := asMap(e)
+			row := t.newRow()
+			t.set(row, "element_id", element["elementId"])
+			t.set(row, "timestamp", element["timestamp"])
+			t.setTagFamilies(row, element["tagFamilies"])
+		}
+		return t, nil
+	case resp["measureResult"] != nil:
+		t := newQLTable("timestamp")
+		for _, dp := range asSlice(asMap(resp["measureResult"])["dataPoints"]) {
+			dataPoint := asMap(dp)
+			row := t.newRow()
+			t.set(row, "timestamp", dataPoint["timestamp"])
+			t.setTagFamilies(row, dataPoint["tagFamilies"])
+			for _, f := range asSlice(dataPoint["fields"]) {
+				field := asMap(f)
+				fieldName, _ := field["name"].(string)
+				t.set(row, fieldName, field["value"])
+			}
+		}
+		return t, nil
+	case resp["propertyResult"] != nil:
+		t := newQLTable("group", "name", "id", "updated_at")
+		for _, p := range asSlice(asMap(resp["propertyResult"])["properties"]) {
+			property := asMap(p)
+			metadata := asMap(property["metadata"])
+			row := t.newRow()
+			t.set(row, "group", metadata["group"])
+			t.set(row, "name", metadata["name"])
+			t.set(row, "id", property["id"])
+			t.set(row, "updated_at", property["updatedAt"])
+			t.setTags(row, property["tags"])
+		}
+		return t, nil
+	case resp["traceResult"] != nil:
+		t := newQLTable("trace_id", "span_id")
+		for _, tr := range asSlice(asMap(resp["traceResult"])["traces"]) {
+			trace := asMap(tr)
+			for _, sp := range asSlice(trace["spans"]) {
+				span := asMap(sp)
+				row := t.newRow()
+				t.set(row, "trace_id", trace["traceId"])
+				t.set(row, "span_id", span["spanId"])
+				t.setTags(row, span["tags"])
+			}
+		}
+		return t, nil
+	case resp["topnResult"] != nil:
+		t := newQLTable("timestamp")
+		for _, l := range asSlice(asMap(resp["topnResult"])["lists"]) {
+			list := asMap(l)
+			for _, it := range asSlice(list["items"]) {
+				item := asMap(it)
+				row := t.newRow()
+				t.set(row, "timestamp", list["timestamp"])
+				t.setTags(row, item["entity"])
+				t.set(row, "value", item["value"])
+			}
+		}
+		return t, nil
+	}
+	return newQLTable(), nil
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+// qlValueString renders the JSON form of a TagValue or FieldValue, for example {"str":{"value":"a"}}.
+func qlValueString(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case map[string]any:
+		if len(val) == 0 {
+			return ""
+		}
+		if _, ok := val["null"]; ok {
+			return "null"
+		}
+		if inner, ok := val["value"]; ok {
+			return qlValueString(inner)
+		}
+		if len(val) == 1 {
+			for _, inner := range val {
+				return qlValueString(inner)
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, k+"="+qlValueString(val[k]))
+		}
+		return strings.Join(parts, ",")
+	case []any:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, qlValueString(item))
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func printQLResult(out io.Writer, format string, body []byte) error {
+	switch format {
+	case qlOutputJSON:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, body, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		_, err := out.Write(buf.Bytes())
+		return err
+	case qlOutputYAML:
+		y, err := yaml.JSONToYAML(body)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(y)
+		return err
+	}
+	t, err := toQLTable(body)
+	if err != nil {
+		return err
+	}
+	if format == qlOutputCSV {
+		w := csv.NewWriter(out)
+		if err = w.Write(t.columns); err != nil {
+			return err
+		}
+		if err = w.WriteAll(t.records()); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+	printQLTable(out, t)
+	return nil
+}
+
+func printQLTable(out io.Writer, t *qlTable) {
+	records := t.records()
+	widths := make([]int, len(t.columns))
+	for i, c := range t.columns {
+		widths[i] = len(c)
+	}
+	for _, r := range records {
+		for i, v := range r {
+			if len(v) > widths[i] {
+				widths[i] = len(v)
+			}
+		}
+	}
+	separator := func() {
+		for _, w := range widths {
+			fmt.Fprint(out, "+", strings.Repeat("-", w+2))
+		}
+		fmt.Fprintln(out, "+")
+	}
+	line := func(values []string) {
+		for i, v := range values {
+			fmt.Fprintf(out, "| %-*s ", widths[i], v)
+		}
+		fmt.Fprintln(out, "|")
+	}
+	if len(t.columns) > 0 {
+		separator()
+		line(t.columns)
+		separator()
+		for _, r := range records {
+			line(r)
+		}
+		separator()
+	}
+	if len(records) == 1 {
+		fmt.Fprintln(out, "(1 row)")
+		return
+	}
+	fmt.Fprintf(out, "(%d rows)\n", len(records))
+}
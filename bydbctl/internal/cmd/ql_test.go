@@ -0,0 +1,100 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/zenizh/go-capturer"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/apache/skywalking-banyandb/bydbctl/internal/cmd"
+	"github.com/apache/skywalking-banyandb/pkg/test/flags"
+	"github.com/apache/skywalking-banyandb/pkg/test/setup"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+	cases_measure_data "github.com/apache/skywalking-banyandb/test/cases/measure/data"
+)
+
+var _ = Describe("BydbQL Shell", func() {
+	var addr, grpcAddr string
+	var deferFunc func()
+	var rootCmd *cobra.Command
+	var stmt string
+	BeforeEach(func() {
+		grpcAddr, addr, deferFunc = setup.Standalone(nil)
+		addr = httpSchema + addr
+		rootCmd = &cobra.Command{Use: "root"}
+		cmd.RootCmdFlags(rootCmd)
+		conn, err := grpclib.NewClient(
+			grpcAddr,
+			grpclib.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		now := timestamp.NowMilli()
+		cases_measure_data.Write(conn, "service_cpm_minute", "sw_metric", "service_cpm_minute_data.json", now, time.Minute)
+		stmt = fmt.Sprintf("SELECT id, total FROM MEASURE service_cpm_minute IN sw_metric TIME BETWEEN '%s' AND '%s'",
+			now.Add(-time.Hour).Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339))
+	})
+
+	It("executes a statement with csv output", func() {
+		rootCmd.SetArgs([]string{"ql", "-a", addr, "-o", "csv", "-e", stmt})
+		issue := func() string {
+			return capturer.CaptureStdout(func() {
+				err := rootCmd.Execute()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		}
+		Eventually(func() int {
+			out := issue()
+			GinkgoWriter.Println(out)
+			return strings.Count(strings.TrimSpace(out), "\n")
+		}, flags.EventuallyTimeout).Should(Equal(6))
+		Expect(issue()).To(HavePrefix("timestamp,id,total"))
+	})
+
+	It("executes statements from the input with timing", func() {
+		rootCmd.SetArgs([]string{"ql", "-a", addr, "--history-file", ""})
+		issue := func() string {
+			rootCmd.SetIn(strings.NewReader("\\timing on\n" + stmt + "\n;\n"))
+			return capturer.CaptureStdout(func() {
+				err := rootCmd.Execute()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		}
+		Eventually(issue, flags.EventuallyTimeout).Should(ContainSubstring("(6 rows)"))
+		out := issue()
+		Expect(out).To(ContainSubstring("Timing is on."))
+		Expect(out).To(ContainSubstring("Time: "))
+	})
+
+	It("reports a syntax error", func() {
+		rootCmd.SetArgs([]string{"ql", "-a", addr, "-e", "SELECT FROM"})
+		err := rootCmd.Execute()
+		Expect(err).To(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		deferFunc()
+	})
+})
@@ -180,7 +180,33 @@ func parseGroupFromFlags() ([]reqBody, error) {
 }
 
 func parseTimeRangeFromFlagAndYAML(reader io.Reader) (requests []reqBody, err error) {
+	tr, err := parseTimeRangeFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	var rawRequests []reqBody
+	if rawRequests, err = parseNameAndGroupFromYAML(reader); err != nil {
+		return nil, err
+	}
+	for _, rb := range rawRequests {
+		if rb.parsedData["timeRange"] != nil {
+			requests = append(requests, rb)
+			continue
+		}
+		rb.parsedData["timeRange"] = tr
+		rb.data, err = json.Marshal(rb.parsedData)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, rb)
+	}
+	return requests, nil
+}
+
+// parseTimeRangeFromFlags parses the time range from the "start" and "end" flags.
+func parseTimeRangeFromFlags() (map[string]interface{}, error) {
 	var startTS, endTS time.Time
+	var err error
 	switch {
 	case start == "" && end == "":
 		startTS = time.Now().Add((-30) * time.Minute)
@@ -203,28 +229,10 @@ func parseTimeRangeFromFlagAndYAML(reader io.Reader) (requests []reqBody, err er
 		}
 		startTS = endTS.Add(-timeRange)
 	}
-	s := startTS.Format(time.RFC3339)
-	e := endTS.Format(time.RFC3339)
-	var rawRequests []reqBody
-	if rawRequests, err = parseNameAndGroupFromYAML(reader); err != nil {
-		return nil, err
-	}
-	for _, rb := range rawRequests {
-		if rb.parsedData["timeRange"] != nil {
-			requests = append(requests, rb)
-			continue
-		}
-		timeRange := make(map[string]interface{})
-		timeRange["begin"] = s
-		timeRange["end"] = e
-		rb.parsedData["timeRange"] = timeRange
-		rb.data, err = json.Marshal(rb.parsedData)
-		if err != nil {
-			return nil, err
-		}
-		requests = append(requests, rb)
-	}
-	return requests, nil
+	tr := make(map[string]interface{})
+	tr["begin"] = startTS.Format(time.RFC3339)
+	tr["end"] = endTS.Format(time.RFC3339)
+	return tr, nil
 }
 
 func parseTime(timestamp string) (time.Time, error) {
@@ -307,6 +315,16 @@ func yamlPrinter(index int, _ reqBody, body []byte) error {
 	return nil
 }
 
+// dryRunPrinter prints the compatibility report in the dry-run mode, otherwise it calls p.
+func dryRunPrinter(p printer) printer {
+	return func(index int, reqBody reqBody, body []byte) error {
+		if dryRun {
+			return yamlPrinter(index, reqBody, body)
+		}
+		return p(index, reqBody, body)
+	}
+}
+
 func rest(pfn paramsFn, fn reqFn, printer printer, enableTLS bool, insecure bool, cert string) (err error) {
 	var requests []reqBody
 	if pfn == nil {
@@ -36,17 +36,19 @@ const (
 )
 
 var (
-	filePath  string
-	name      string
-	start     string
-	end       string
-	cfgFile   string
-	enableTLS bool
-	insecure  bool
-	cert      string
-	username  string
-	password  string
-	rootCmd   = &cobra.Command{
+	filePath   string
+	name       string
+	start      string
+	end        string
+	cfgFile    string
+	enableTLS  bool
+	dryRun     bool
+	unusedDays int
+	insecure   bool
+	cert       string
+	username   string
+	password   string
+	rootCmd    = &cobra.Command{
 		DisableAutoGenTag: true,
 		Version:           version.Build(),
 		Short:             "bydbctl is the command line tool of BanyanDB",
@@ -59,6 +61,8 @@ func ResetFlags() {
 	name = ""
 	start = ""
 	end = ""
+	dryRun = false
+	unusedDays = defaultUnusedDays
 }
 
 // Execute executes the root command.
@@ -80,7 +84,8 @@ func RootCmdFlags(command *cobra.Command) {
 	_ = viper.BindPFlag("password", command.PersistentFlags().Lookup("password"))
 
 	command.AddCommand(newGroupCmd(), newUseCmd(), newStreamCmd(), newMeasureCmd(), newTopnCmd(),
-		newIndexRuleCmd(), newIndexRuleBindingCmd(), newPropertyCmd(), newTraceCmd(), newHealthCheckCmd(), newAnalyzeCmd())
+		newIndexRuleCmd(), newIndexRuleBindingCmd(), newPropertyCmd(), newTraceCmd(), newHealthCheckCmd(), newAnalyzeCmd(), newQLCmd(),
+		newApplyCmd(), newDiffCmd(), newSeriesCmd())
 }
 
 func init() {
@@ -151,6 +156,12 @@ func bindFileFlag(commands ...*cobra.Command) {
 	}
 }
 
+func bindDryRunFlag(commands ...*cobra.Command) {
+	for _, c := range commands {
+		c.Flags().BoolVarP(&dryRun, "dry-run", "", false, "Only check the request and print its compatibility report")
+	}
+}
+
 func bindNameFlag(commands ...*cobra.Command) {
 	for _, c := range commands {
 		c.Flags().StringVarP(&name, "name", "n", "", "the name of the resource")
@@ -0,0 +1,390 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+)
+
+// schemaObject is a schema object managed by a registry service.
+type schemaObject interface {
+	proto.Message
+	GetMetadata() *commonv1.Metadata
+}
+
+// schemaKind describes how to manage a kind of schema objects through the registry services.
+type schemaKind struct {
+	newObject   func() schemaObject
+	wrapCreate  func(schemaObject) proto.Message
+	wrapUpdate  func(schemaObject) proto.Message
+	unwrapGet   func(body []byte) (schemaObject, error)
+	unwrapList  func(body []byte) ([]schemaObject, error)
+	name        string
+	schemaPath  string
+	order       int
+	groupScoped bool
+}
+
+// schemaKinds are sorted by their dependencies: groups, resources, index rules, index rule bindings and TopN aggregations.
+var schemaKinds = []*schemaKind{
+	{
+		name:        "group",
+		schemaPath:  "/api/v1/group/schema",
+		order:       0,
+		groupScoped: false,
+		newObject:   func() schemaObject { return new(commonv1.Group) },
+		wrapCreate: func(o schemaObject) proto.Message {
+			return &databasev1.GroupRegistryServiceCreateRequest{Group: o.(*commonv1.Group)}
+		},
+		wrapUpdate: func(o schemaObject) proto.Message {
+			return &databasev1.GroupRegistryServiceUpdateRequest{Group: o.(*commonv1.Group)}
+		},
+		unwrapGet: func(body []byte) (schemaObject, error) {
+			resp := new(databasev1.GroupRegistryServiceGetResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return resp.GetGroup(), nil
+		},
+		unwrapList: func(body []byte) ([]schemaObject, error) {
+			resp := new(databasev1.GroupRegistryServiceListResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return toSchemaObjects(resp.GetGroup()), nil
+		},
+	},
+	{
+		name:        "stream",
+		schemaPath:  streamSchemaPath,
+		order:       1,
+		groupScoped: true,
+		newObject:   func() schemaObject { return new(databasev1.Stream) },
+		wrapCreate: func(o schemaObject) proto.Message {
+			return &databasev1.StreamRegistryServiceCreateRequest{Stream: o.(*databasev1.Stream)}
+		},
+		wrapUpdate: func(o schemaObject) proto.Message {
+			return &databasev1.StreamRegistryServiceUpdateRequest{Stream: o.(*databasev1.Stream)}
+		},
+		unwrapGet: func(body []byte) (schemaObject, error) {
+			resp := new(databasev1.StreamRegistryServiceGetResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return resp.GetStream(), nil
+		},
+		unwrapList: func(body []byte) ([]schemaObject, error) {
+			resp := new(databasev1.StreamRegistryServiceListResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return toSchemaObjects(resp.GetStream()), nil
+		},
+	},
+	{
+		name:        "measure",
+		schemaPath:  measureSchemaPath,
+		order:       1,
+		groupScoped: true,
+		newObject:   func() schemaObject { return new(databasev1.Measure) },
+		wrapCreate: func(o schemaObject) proto.Message {
+			return &databasev1.MeasureRegistryServiceCreateRequest{Measure: o.(*databasev1.Measure)}
+		},
+		wrapUpdate: func(o schemaObject) proto.Message {
+			return &databasev1.MeasureRegistryServiceUpdateRequest{Measure: o.(*databasev1.Measure)}
+		},
+		unwrapGet: func(body []byte) (schemaObject, error) {
+			resp := new(databasev1.MeasureRegistryServiceGetResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return resp.GetMeasure(), nil
+		},
+		unwrapList: func(body []byte) ([]schemaObject, error) {
+			resp := new(databasev1.MeasureRegistryServiceListResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return toSchemaObjects(resp.GetMeasure()), nil
+		},
+	},
+	{
+		name:        "trace",
+		schemaPath:  traceSchemaPath,
+		order:       1,
+		groupScoped: true,
+		newObject:   func() schemaObject { return new(databasev1.Trace) },
+		wrapCreate: func(o schemaObject) proto.Message {
+			return &databasev1.TraceRegistryServiceCreateRequest{Trace: o.(*databasev1.Trace)}
+		},
+		wrapUpdate: func(o schemaObject) proto.Message {
+			return &databasev1.TraceRegistryServiceUpdateRequest{Trace: o.(*databasev1.Trace)}
+		},
+		unwrapGet: func(body []byte) (schemaObject, error) {
+			resp := new(databasev1.TraceRegistryServiceGetResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return resp.GetTrace(), nil
+		},
+		unwrapList: func(body []byte) ([]schemaObject, error) {
+			resp := new(databasev1.TraceRegistryServiceListResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return toSchemaObjects(resp.GetTrace()), nil
+		},
+	},
+	{
+		name:        "property",
+		schemaPath:  propertySchemaPath,
+		order:       1,
+		groupScoped: true,
+		newObject:   func() schemaObject { return new(databasev1.Property) },
+		wrapCreate: func(o schemaObject) proto.Message {
+			return &databasev1.PropertyRegistryServiceCreateRequest{Property: o.(*databasev1.Property)}
+		},
+		wrapUpdate: func(o schemaObject) proto.Message {
+			return &databasev1.PropertyRegistryServiceUpdateRequest{Property: o.(*databasev1.Property)}
+		},
+		unwrapGet: func(body []byte) (schemaObject, error) {
+			resp := new(databasev1.PropertyRegistryServiceGetResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return resp.GetProperty(), nil
+		},
+		unwrapList: func(body []byte) ([]schemaObject, error) {
+			resp := new(databasev1.PropertyRegistryServiceListResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return toSchemaObjects(resp.GetProperties()), nil
+		},
+	},
+	{
+		name:        "index-rule",
+		schemaPath:  indexRuleSchemaPath,
+		order:       2,
+		groupScoped: true,
+		newObject:   func() schemaObject { return new(databasev1.IndexRule) },
+		wrapCreate: func(o schemaObject) proto.Message {
+			return &databasev1.IndexRuleRegistryServiceCreateRequest{IndexRule: o.(*databasev1.IndexRule)}
+		},
+		wrapUpdate: func(o schemaObject) proto.Message {
+			return &databasev1.IndexRuleRegistryServiceUpdateRequest{IndexRule: o.(*databasev1.IndexRule)}
+		},
+		unwrapGet: func(body []byte) (schemaObject, error) {
+			resp := new(databasev1.IndexRuleRegistryServiceGetResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return resp.GetIndexRule(), nil
+		},
+		unwrapList: func(body []byte) ([]schemaObject, error) {
+			resp := new(databasev1.IndexRuleRegistryServiceListResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return toSchemaObjects(resp.GetIndexRule()), nil
+		},
+	},
+	{
+		name:        "index-rule-binding",
+		schemaPath:  indexRuleBindingSchemaPath,
+		order:       3,
+		groupScoped: true,
+		newObject:   func() schemaObject { return new(databasev1.IndexRuleBinding) },
+		wrapCreate: func(o schemaObject) proto.Message {
+			return &databasev1.IndexRuleBindingRegistryServiceCreateRequest{IndexRuleBinding: o.(*databasev1.IndexRuleBinding)}
+		},
+		wrapUpdate: func(o schemaObject) proto.Message {
+			return &databasev1.IndexRuleBindingRegistryServiceUpdateRequest{IndexRuleBinding: o.(*databasev1.IndexRuleBinding)}
+		},
+		unwrapGet: func(body []byte) (schemaObject, error) {
+			resp := new(databasev1.IndexRuleBindingRegistryServiceGetResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return resp.GetIndexRuleBinding(), nil
+		},
+		unwrapList: func(body []byte) ([]schemaObject, error) {
+			resp := new(databasev1.IndexRuleBindingRegistryServiceListResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return toSchemaObjects(resp.GetIndexRuleBinding()), nil
+		},
+	},
+	{
+		name:        "topn-aggregation",
+		schemaPath:  topnSchemaPath,
+		order:       4,
+		groupScoped: true,
+		newObject:   func() schemaObject { return new(databasev1.TopNAggregation) },
+		wrapCreate: func(o schemaObject) proto.Message {
+			return &databasev1.TopNAggregationRegistryServiceCreateRequest{TopNAggregation: o.(*databasev1.TopNAggregation)}
+		},
+		wrapUpdate: func(o schemaObject) proto.Message {
+			return &databasev1.TopNAggregationRegistryServiceUpdateRequest{TopNAggregation: o.(*databasev1.TopNAggregation)}
+		},
+		unwrapGet: func(body []byte) (schemaObject, error) {
+			resp := new(databasev1.TopNAggregationRegistryServiceGetResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return resp.GetTopNAggregation(), nil
+		},
+		unwrapList: func(body []byte) ([]schemaObject, error) {
+			resp := new(databasev1.TopNAggregationRegistryServiceListResponse)
+			if err := protojson.Unmarshal(body, resp); err != nil {
+				return nil, err
+			}
+			return toSchemaObjects(resp.GetTopNAggregation()), nil
+		},
+	},
+}
+
+func findSchemaKind(name string) (*schemaKind, error) {
+	for _, k := range schemaKinds {
+		if k.name == name {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown kind %q", name)
+}
+
+func toSchemaObjects[T schemaObject](objects []T) []schemaObject {
+	result := make([]schemaObject, 0, len(objects))
+	for _, o := range objects {
+		result = append(result, o)
+	}
+	return result
+}
+
+func (k *schemaKind) itemPath() string {
+	if k.groupScoped {
+		return k.schemaPath + pathTemp
+	}
+	return k.schemaPath + "/{group}"
+}
+
+func (k *schemaKind) listPath() string {
+	if k.groupScoped {
+		return k.schemaPath + "/lists/{group}"
+	}
+	return k.schemaPath + "/lists"
+}
+
+func (k *schemaKind) setItemParams(req *resty.Request, md *commonv1.Metadata) *resty.Request {
+	if k.groupScoped {
+		return req.SetPathParam("group", md.GetGroup()).SetPathParam("name", md.GetName())
+	}
+	return req.SetPathParam("group", md.GetName())
+}
+
+func (k *schemaKind) get(md *commonv1.Metadata) (schemaObject, error) {
+	body, err := restCall(func(request request) (*resty.Response, error) {
+		return k.setItemParams(request.req, md).Get(getPath(k.itemPath()))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return k.unwrapGet(body)
+}
+
+func (k *schemaKind) list(group string) ([]schemaObject, error) {
+	body, err := restCall(func(request request) (*resty.Response, error) {
+		return request.req.SetPathParam("group", group).Get(getPath(k.listPath()))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return k.unwrapList(body)
+}
+
+func (k *schemaKind) create(o schemaObject) error {
+	b, err := protojson.Marshal(k.wrapCreate(o))
+	if err != nil {
+		return err
+	}
+	_, err = restCall(func(request request) (*resty.Response, error) {
+		return request.req.SetBody(b).Post(getPath(k.schemaPath))
+	})
+	return err
+}
+
+func (k *schemaKind) update(o schemaObject) error {
+	b, err := protojson.Marshal(k.wrapUpdate(o))
+	if err != nil {
+		return err
+	}
+	_, err = restCall(func(request request) (*resty.Response, error) {
+		return k.setItemParams(request.req.SetBody(b), o.GetMetadata()).Put(getPath(k.itemPath()))
+	})
+	return err
+}
+
+func (k *schemaKind) delete(md *commonv1.Metadata) error {
+	_, err := restCall(func(request request) (*resty.Response, error) {
+		return k.setItemParams(request.req, md).Delete(getPath(k.itemPath()))
+	})
+	return err
+}
+
+// objectKey identifies a schema object of a kind.
+func (k *schemaKind) objectKey(md *commonv1.Metadata) string {
+	if k.groupScoped {
+		return k.name + ":" + md.GetGroup() + "/" + md.GetName()
+	}
+	return k.name + ":" + md.GetName()
+}
+
+// normalizeSchema returns a copy of the object without the fields maintained by the server,
+// so that the objects in files and in the cluster can be compared.
+func normalizeSchema(o schemaObject) schemaObject {
+	c := proto.Clone(o).(schemaObject)
+	if md := c.GetMetadata(); md != nil {
+		md.Id = 0
+		md.CreateRevision = 0
+		md.ModRevision = 0
+	}
+	r := c.ProtoReflect()
+	if fd := r.Descriptor().Fields().ByName(protoreflect.Name("updated_at")); fd != nil {
+		r.Clear(fd)
+	}
+	return c
+}
+
+// restCall sends a single request and returns the response body.
+func restCall(fn reqFn) ([]byte, error) {
+	var body []byte
+	err := rest(nil, fn, func(_ int, _ reqBody, b []byte) error {
+		body = b
+		return nil
+	}, enableTLS, insecure, cert)
+	return body, err
+}
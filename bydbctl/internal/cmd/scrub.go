@@ -0,0 +1,125 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	"github.com/apache/skywalking-banyandb/pkg/version"
+)
+
+const scrubPollInterval = 2 * time.Second
+
+type scrubProgress struct {
+	StartedAt      *time.Time `json:"startedAt,omitempty"`
+	FinishedAt     *time.Time `json:"finishedAt,omitempty"`
+	Node           string     `json:"node"`
+	Progress       string     `json:"progress"`
+	CorruptedParts []string   `json:"corruptedParts,omitempty"`
+	PartsTotal     int64      `json:"partsTotal"`
+	PartsScrubbed  int64      `json:"partsScrubbed"`
+	PartsSkipped   int64      `json:"partsSkipped"`
+	BytesScrubbed  int64      `json:"bytesScrubbed"`
+	Running        bool       `json:"running"`
+}
+
+func newGroupScrubCmd() *cobra.Command {
+	var statusOnly, wait bool
+	scrubCmd := &cobra.Command{
+		Use:     "scrub [-g group] [--status] [--wait]",
+		Version: version.Build(),
+		Short:   "Verify the checksums of the parts of a group on the data nodes and show the progress",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			scrub := !statusOnly
+			for round := 0; ; round++ {
+				running := false
+				err := rest(parseGroupFromFlags, func(request request) (*resty.Response, error) {
+					req := request.req.SetPathParam("group", request.group)
+					if scrub {
+						req.SetQueryParam("scrub", "true")
+					}
+					return req.Get(getPath(groupContentPath))
+				}, func(_ int, reqBody reqBody, body []byte) error {
+					resp := new(databasev1.GroupRegistryServiceInspectResponse)
+					if err := protojson.Unmarshal(body, resp); err != nil {
+						return err
+					}
+					progress := buildScrubProgress(resp.GetDataInfo())
+					for _, p := range progress {
+						running = running || p.Running
+					}
+					report, err := json.Marshal(progress)
+					if err != nil {
+						return err
+					}
+					return yamlPrinter(round, reqBody, report)
+				}, enableTLS, insecure, cert)
+				if err != nil || !wait || !running {
+					return err
+				}
+				scrub = false
+				time.Sleep(scrubPollInterval)
+			}
+		},
+	}
+	scrubCmd.Flags().BoolVar(&statusOnly, "status", false, "Show the progress of the latest scrub without starting a new one")
+	scrubCmd.Flags().BoolVar(&wait, "wait", false, "Keep showing the progress until the scrub finishes on all the nodes")
+	return scrubCmd
+}
+
+// buildScrubProgress lists the progress of the latest scrub on each data node.
+// The nodes which have never scrubbed the group are left out.
+func buildScrubProgress(dataInfo []*databasev1.DataInfo) []*scrubProgress {
+	result := make([]*scrubProgress, 0, len(dataInfo))
+	for _, di := range dataInfo {
+		s := di.GetScrubStatus()
+		if s == nil {
+			continue
+		}
+		p := &scrubProgress{
+			Node:           di.GetNode().GetMetadata().GetName(),
+			Running:        s.GetRunning(),
+			PartsTotal:     s.GetPartsTotal(),
+			PartsScrubbed:  s.GetPartsScrubbed(),
+			PartsSkipped:   s.GetPartsSkipped(),
+			BytesScrubbed:  s.GetBytesScrubbed(),
+			CorruptedParts: s.GetCorruptedParts(),
+			Progress:       "100%",
+		}
+		if s.GetPartsTotal() > 0 {
+			p.Progress = fmt.Sprintf("%d%%", (s.GetPartsScrubbed()+s.GetPartsSkipped())*100/s.GetPartsTotal())
+		}
+		if s.GetStartedAt() != nil {
+			startedAt := s.GetStartedAt().AsTime()
+			p.StartedAt = &startedAt
+		}
+		if s.GetFinishedAt() != nil {
+			finishedAt := s.GetFinishedAt().AsTime()
+			p.FinishedAt = &finishedAt
+		}
+		result = append(result, p)
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/zenizh/go-capturer"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apache/skywalking-banyandb/bydbctl/internal/cmd"
+	"github.com/apache/skywalking-banyandb/pkg/test/setup"
+)
+
+var _ = Describe("Group Scrub", func() {
+	var addr string
+	var deferFunc func()
+	var rootCmd *cobra.Command
+	BeforeEach(func() {
+		_, addr, deferFunc = setup.Standalone(nil)
+		addr = httpSchema + addr
+		rootCmd = &cobra.Command{Use: "root"}
+		cmd.RootCmdFlags(rootCmd)
+	})
+
+	type progress struct {
+		FinishedAt *time.Time `json:"finishedAt"`
+		StartedAt  *time.Time `json:"startedAt"`
+		Progress   string     `json:"progress"`
+		PartsTotal int64      `json:"partsTotal"`
+		Running    bool       `json:"running"`
+	}
+	// lastProgress parses the progress printed by the last round of the command.
+	lastProgress := func(out string) []progress {
+		rounds := strings.Split(out, "---\n")
+		var result []progress
+		Expect(yaml.Unmarshal([]byte(rounds[len(rounds)-1]), &result)).To(Succeed())
+		return result
+	}
+
+	It("shows no progress before the group is scrubbed", func() {
+		rootCmd.SetArgs([]string{"group", "scrub", "-a", addr, "-g", "sw_metric", "--status"})
+		out := capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		Expect(lastProgress(out)).To(BeEmpty())
+	})
+
+	It("reports the progress until the scrub finishes", func() {
+		rootCmd.SetArgs([]string{"group", "scrub", "-a", addr, "-g", "sw_metric", "--wait"})
+		out := capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		result := lastProgress(out)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Running).To(BeFalse())
+		Expect(result[0].Progress).To(Equal("100%"))
+		Expect(result[0].StartedAt).NotTo(BeNil())
+		Expect(result[0].FinishedAt).NotTo(BeNil())
+
+		// The status of the finished scrub is kept.
+		rootCmd.SetArgs([]string{"group", "scrub", "-a", addr, "-g", "sw_metric", "--status"})
+		out = capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		status := lastProgress(out)
+		Expect(status).To(HaveLen(1))
+		Expect(status[0].StartedAt).To(Equal(result[0].StartedAt))
+		Expect(status[0].Running).To(BeFalse())
+	})
+
+	AfterEach(func() {
+		deferFunc()
+	})
+})
@@ -0,0 +1,175 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/apache/skywalking-banyandb/pkg/version"
+)
+
+const (
+	seriesTagValuesPath   = "/api/v1/explorer/tag-values"
+	seriesListPath        = "/api/v1/explorer/series"
+	seriesCardinalityPath = "/api/v1/explorer/series-cardinality"
+)
+
+var (
+	tagName   string
+	tagPrefix string
+	limit     uint32
+	pageSize  uint32
+	pageToken string
+	tagNames  []string
+	topN      uint32
+)
+
+func newSeriesCmd() *cobra.Command {
+	seriesCmd := &cobra.Command{
+		Use:     "series",
+		Version: version.Build(),
+		Short:   "Explore the series and the tag values stored on the data nodes",
+	}
+
+	tagValuesCmd := &cobra.Command{
+		Use:     "tag-values [-g group] -n name -t tag [--prefix prefix] [--limit limit] [-s start_time] [-e end_time]",
+		Version: version.Build(),
+		Short:   "List the distinct values of an entity tag or an indexed tag",
+		Long:    timeRangeUsage,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return rest(parseTagValuesFromFlags, func(request request) (*resty.Response, error) {
+				return request.req.SetBody(request.data).Post(getPath(seriesTagValuesPath))
+			}, yamlPrinter, enableTLS, insecure, cert)
+		},
+	}
+	bindNameFlag(tagValuesCmd)
+	tagValuesCmd.Flags().StringVarP(&tagName, "tag", "t", "", "the name of the tag")
+	_ = tagValuesCmd.MarkFlagRequired("tag")
+	tagValuesCmd.Flags().StringVarP(&tagPrefix, "prefix", "", "", "List the values starting with the prefix")
+	tagValuesCmd.Flags().Uint32VarP(&limit, "limit", "", 0, "The max number of values, the server applies 100 if absent")
+	bindTimeRangeFlag(tagValuesCmd)
+
+	listCmd := &cobra.Command{
+		Use:     "list [-g group] -n name [--page-size size] [--page-token token] [-s start_time] [-e end_time]",
+		Version: version.Build(),
+		Short:   "List the series and their entity tags page by page",
+		Long:    timeRangeUsage,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return rest(parseListSeriesFromFlags, func(request request) (*resty.Response, error) {
+				return request.req.SetBody(request.data).Post(getPath(seriesListPath))
+			}, yamlPrinter, enableTLS, insecure, cert)
+		},
+	}
+	bindNameFlag(listCmd)
+	listCmd.Flags().Uint32VarP(&pageSize, "page-size", "", 0, "The max number of series in a page, the server applies 100 if absent")
+	listCmd.Flags().StringVarP(&pageToken, "page-token", "", "", "The next page token returned by the previous page")
+	bindTimeRangeFlag(listCmd)
+
+	cardinalityCmd := &cobra.Command{
+		Use:     "cardinality [-g group] -n name [--tags tag1,tag2] [--top-n n] [-s start_time] [-e end_time]",
+		Version: version.Build(),
+		Short:   "Count the series per segment and break them down by the tag values",
+		Long:    timeRangeUsage,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return rest(parseSeriesCardinalityFromFlags, func(request request) (*resty.Response, error) {
+				return request.req.SetBody(request.data).Post(getPath(seriesCardinalityPath))
+			}, yamlPrinter, enableTLS, insecure, cert)
+		},
+	}
+	bindNameFlag(cardinalityCmd)
+	cardinalityCmd.Flags().StringSliceVarP(&tagNames, "tags", "", nil, "The entity tags to break down, all entity tags if absent")
+	cardinalityCmd.Flags().Uint32VarP(&topN, "top-n", "", 0, "The number of the top values per tag, the server applies 10 if absent")
+	bindTimeRangeFlag(cardinalityCmd)
+
+	bindTLSRelatedFlag(tagValuesCmd, listCmd, cardinalityCmd)
+	seriesCmd.AddCommand(tagValuesCmd, listCmd, cardinalityCmd)
+	return seriesCmd
+}
+
+func parseTagValuesFromFlags() ([]reqBody, error) {
+	requests, err := parseGroupFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	tr, err := parseTimeRangeFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	requests[0].name = name
+	requests[0].data, err = json.Marshal(map[string]interface{}{
+		"group":     requests[0].group,
+		"name":      name,
+		"tag":       tagName,
+		"prefix":    tagPrefix,
+		"limit":     limit,
+		"timeRange": tr,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func parseListSeriesFromFlags() ([]reqBody, error) {
+	requests, err := parseGroupFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	tr, err := parseTimeRangeFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	requests[0].name = name
+	requests[0].data, err = json.Marshal(map[string]interface{}{
+		"group":     requests[0].group,
+		"name":      name,
+		"pageSize":  pageSize,
+		"pageToken": pageToken,
+		"timeRange": tr,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func parseSeriesCardinalityFromFlags() ([]reqBody, error) {
+	requests, err := parseGroupFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	tr, err := parseTimeRangeFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	requests[0].name = name
+	requests[0].data, err = json.Marshal(map[string]interface{}{
+		"group":     requests[0].group,
+		"name":      name,
+		"tags":      tagNames,
+		"topN":      topN,
+		"timeRange": tr,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
@@ -62,6 +62,7 @@ func newStreamCmd() *cobra.Command {
 					}
 					cr := &databasev1.StreamRegistryServiceCreateRequest{
 						Stream: s,
+						DryRun: dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -69,11 +70,11 @@ func newStreamCmd() *cobra.Command {
 					}
 					return request.req.SetBody(b).Post(getPath(streamSchemaPath))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("stream %s.%s is created", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -91,6 +92,7 @@ func newStreamCmd() *cobra.Command {
 					}
 					cr := &databasev1.StreamRegistryServiceUpdateRequest{
 						Stream: s,
+						DryRun: dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -100,11 +102,11 @@ func newStreamCmd() *cobra.Command {
 						SetPathParam("name", request.name).SetPathParam("group", request.group).
 						Put(getPath(streamSchemaPathWithParams))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("stream %s.%s is updated", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -159,9 +161,11 @@ func newStreamCmd() *cobra.Command {
 		},
 	}
 	bindFileFlag(createCmd, updateCmd, queryCmd)
+	bindDryRunFlag(createCmd, updateCmd)
 	bindTimeRangeFlag(queryCmd)
 
 	bindTLSRelatedFlag(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd)
-	streamCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd)
+	streamCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd, newWriteCmd("stream", &streamWriter{}))
+	streamCmd.AddCommand(newHistoryCmds(databasev1.SchemaKind_SCHEMA_KIND_STREAM, "stream")...)
 	return streamCmd
 }
@@ -0,0 +1,126 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	streamv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/stream/v1"
+)
+
+type streamWriter struct {
+	schema *databasev1.Stream
+}
+
+func (w *streamWriter) loadSchema(ctx context.Context, conn *grpc.ClientConn, md *commonv1.Metadata) (*commonv1.Metadata, error) {
+	resp, err := databasev1.NewStreamRegistryServiceClient(conn).Get(ctx, &databasev1.StreamRegistryServiceGetRequest{Metadata: md})
+	if err != nil {
+		return nil, err
+	}
+	w.schema = resp.GetStream()
+	return w.schema.GetMetadata(), nil
+}
+
+func (w *streamWriter) fromDocument(doc []byte, md *commonv1.Metadata) (writeItem, error) {
+	req := new(streamv1.WriteRequest)
+	if err := protojson.Unmarshal(doc, req); err != nil {
+		return writeItem{}, err
+	}
+	var err error
+	if req.Metadata, err = defaultMetadata(req.GetMetadata(), md); err != nil {
+		return writeItem{}, err
+	}
+	return writeItem{request: req}, nil
+}
+
+func (w *streamWriter) csvTargets() map[string]struct{} {
+	targets := map[string]struct{}{"timestamp": {}, "element_id": {}}
+	for _, tf := range w.schema.GetTagFamilies() {
+		for _, t := range tf.GetTags() {
+			targets[t.GetName()] = struct{}{}
+		}
+	}
+	return targets
+}
+
+func (w *streamWriter) fromCSV(record []string, columns map[string]int, md *commonv1.Metadata) (writeItem, error) {
+	ts, err := parseCSVTimestamp(csvValue(record, columns, "timestamp"))
+	if err != nil {
+		return writeItem{}, err
+	}
+	tagFamilies, err := csvTagFamilies(w.schema.GetTagFamilies(), record, columns)
+	if err != nil {
+		return writeItem{}, err
+	}
+	return writeItem{request: &streamv1.WriteRequest{
+		Metadata: md,
+		Element: &streamv1.ElementValue{
+			ElementId:   csvValue(record, columns, "element_id"),
+			Timestamp:   ts,
+			TagFamilies: tagFamilies,
+		},
+	}}, nil
+}
+
+func (w *streamWriter) writeBatch(ctx context.Context, conn *grpc.ClientConn, items []writeItem) ([]writeFailure, error) {
+	client, err := streamv1.NewStreamServiceClient(conn).Write(ctx)
+	if err != nil {
+		return nil, err
+	}
+	firstID, records := assignMessageIDs(items)
+	var last *commonv1.Metadata
+	for i, item := range items {
+		req := item.request.(*streamv1.WriteRequest)
+		if err = client.Send(&streamv1.WriteRequest{
+			Metadata:      metadataForBatch(req.GetMetadata(), last),
+			Element:       req.GetElement(),
+			MessageId:     firstID + uint64(i),
+			TagFamilySpec: req.GetTagFamilySpec(),
+		}); err != nil {
+			return nil, err
+		}
+		last = req.GetMetadata()
+	}
+	if err = client.CloseSend(); err != nil {
+		return nil, err
+	}
+	var failures []writeFailure
+	for {
+		resp, err := client.Recv()
+		if errors.Is(err, io.EOF) {
+			return failures, nil
+		}
+		if err != nil {
+			return failures, err
+		}
+		if !isSucceed(resp.GetStatus()) {
+			failures = append(failures, writeFailure{
+				record: records[resp.GetMessageId()],
+				id:     resp.GetMetadata().GetGroup() + "." + resp.GetMetadata().GetName(),
+				status: resp.GetStatus(),
+			})
+		}
+	}
+}
@@ -58,6 +58,7 @@ func newTopnCmd() *cobra.Command {
 					}
 					cr := &databasev1.TopNAggregationRegistryServiceCreateRequest{
 						TopNAggregation: s,
+						DryRun:          dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -65,11 +66,11 @@ func newTopnCmd() *cobra.Command {
 					}
 					return request.req.SetBody(b).Post(getPath(topnSchemaPath))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("topn %s.%s is created", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -88,6 +89,7 @@ func newTopnCmd() *cobra.Command {
 					}
 					cr := &databasev1.TopNAggregationRegistryServiceUpdateRequest{
 						TopNAggregation: s,
+						DryRun:          dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -97,11 +99,11 @@ func newTopnCmd() *cobra.Command {
 						SetPathParam("name", request.name).SetPathParam("group", request.group).
 						Put(getPath(topnSchemaPathWithParams))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("topn %s.%s is updated", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -159,9 +161,11 @@ func newTopnCmd() *cobra.Command {
 		},
 	}
 	bindFileFlag(createCmd, updateCmd, queryCmd)
+	bindDryRunFlag(createCmd, updateCmd)
 	bindTimeRangeFlag(queryCmd)
 
 	bindTLSRelatedFlag(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd)
 	topnCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd)
+	topnCmd.AddCommand(newHistoryCmds(databasev1.SchemaKind_SCHEMA_KIND_TOPN_AGGREGATION, "topn")...)
 	return topnCmd
 }
@@ -56,7 +56,8 @@ func newTraceCmd() *cobra.Command {
 						return nil, err
 					}
 					cr := &databasev1.TraceRegistryServiceCreateRequest{
-						Trace: s,
+						Trace:  s,
+						DryRun: dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -64,11 +65,11 @@ func newTraceCmd() *cobra.Command {
 					}
 					return request.req.SetBody(b).Post(getPath(traceSchemaPath))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("trace %s.%s is created", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -85,7 +86,8 @@ func newTraceCmd() *cobra.Command {
 						return nil, err
 					}
 					cr := &databasev1.TraceRegistryServiceUpdateRequest{
-						Trace: s,
+						Trace:  s,
+						DryRun: dryRun,
 					}
 					b, err := protojson.Marshal(cr)
 					if err != nil {
@@ -95,11 +97,11 @@ func newTraceCmd() *cobra.Command {
 						SetPathParam("name", request.name).SetPathParam("group", request.group).
 						Put(getPath(traceSchemaPathWithParams))
 				},
-				func(_ int, reqBody reqBody, _ []byte) error {
+				dryRunPrinter(func(_ int, reqBody reqBody, _ []byte) error {
 					fmt.Printf("trace %s.%s is updated", reqBody.group, reqBody.name)
 					fmt.Println()
 					return nil
-				}, enableTLS, insecure, cert)
+				}), enableTLS, insecure, cert)
 		},
 	}
 
@@ -155,8 +157,10 @@ func newTraceCmd() *cobra.Command {
 	}
 
 	bindFileFlag(createCmd, updateCmd, queryCmd)
+	bindDryRunFlag(createCmd, updateCmd)
 	bindTimeRangeFlag(queryCmd)
 	bindTLSRelatedFlag(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd)
-	traceCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd)
+	traceCmd.AddCommand(getCmd, createCmd, deleteCmd, updateCmd, listCmd, queryCmd, newWriteCmd("trace", &traceWriter{}))
+	traceCmd.AddCommand(newHistoryCmds(databasev1.SchemaKind_SCHEMA_KIND_TRACE, "trace")...)
 	return traceCmd
 }
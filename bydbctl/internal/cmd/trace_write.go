@@ -0,0 +1,143 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	tracev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/trace/v1"
+)
+
+type traceWriter struct {
+	schema *databasev1.Trace
+}
+
+func (w *traceWriter) loadSchema(ctx context.Context, conn *grpc.ClientConn, md *commonv1.Metadata) (*commonv1.Metadata, error) {
+	resp, err := databasev1.NewTraceRegistryServiceClient(conn).Get(ctx, &databasev1.TraceRegistryServiceGetRequest{Metadata: md})
+	if err != nil {
+		return nil, err
+	}
+	w.schema = resp.GetTrace()
+	return w.schema.GetMetadata(), nil
+}
+
+func (w *traceWriter) fromDocument(doc []byte, md *commonv1.Metadata) (writeItem, error) {
+	req := new(tracev1.WriteRequest)
+	if err := protojson.Unmarshal(doc, req); err != nil {
+		return writeItem{}, err
+	}
+	var err error
+	if req.Metadata, err = defaultMetadata(req.GetMetadata(), md); err != nil {
+		return writeItem{}, err
+	}
+	if req.GetVersion() == 0 {
+		return writeItem{}, errors.New("absent node: version, it should be greater than 0")
+	}
+	return writeItem{request: req}, nil
+}
+
+func (w *traceWriter) csvTargets() map[string]struct{} {
+	targets := map[string]struct{}{"span": {}, "version": {}}
+	for _, t := range w.schema.GetTags() {
+		targets[t.GetName()] = struct{}{}
+	}
+	return targets
+}
+
+func (w *traceWriter) fromCSV(record []string, columns map[string]int, md *commonv1.Metadata) (writeItem, error) {
+	req := &tracev1.WriteRequest{Metadata: md}
+	for _, t := range w.schema.GetTags() {
+		v, err := parseCSVTagValue(t.GetType(), csvValue(record, columns, t.GetName()))
+		if err != nil {
+			return writeItem{}, fmt.Errorf("tag %s: %w", t.GetName(), err)
+		}
+		req.Tags = append(req.Tags, v)
+	}
+	if s := csvValue(record, columns, "span"); s != "" {
+		span, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return writeItem{}, fmt.Errorf("span: %w", err)
+		}
+		req.Span = span
+	}
+	version, err := strconv.ParseUint(csvValue(record, columns, "version"), 10, 64)
+	if err != nil || version == 0 {
+		return writeItem{}, errors.New("the version column is required and it should be greater than 0")
+	}
+	req.Version = version
+	return writeItem{request: req}, nil
+}
+
+func (w *traceWriter) writeBatch(ctx context.Context, conn *grpc.ClientConn, items []writeItem) ([]writeFailure, error) {
+	client, err := tracev1.NewTraceServiceClient(conn).Write(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The trace write response carries the version instead of a message id.
+	records := make(map[uint64][]int, len(items))
+	var last *commonv1.Metadata
+	for _, item := range items {
+		req := item.request.(*tracev1.WriteRequest)
+		if err = client.Send(&tracev1.WriteRequest{
+			Metadata: metadataForBatch(req.GetMetadata(), last),
+			Tags:     req.GetTags(),
+			Span:     req.GetSpan(),
+			Version:  req.GetVersion(),
+			TagSpec:  req.GetTagSpec(),
+		}); err != nil {
+			return nil, err
+		}
+		last = req.GetMetadata()
+		records[req.GetVersion()] = append(records[req.GetVersion()], item.record)
+	}
+	if err = client.CloseSend(); err != nil {
+		return nil, err
+	}
+	var failures []writeFailure
+	for {
+		resp, err := client.Recv()
+		if errors.Is(err, io.EOF) {
+			return failures, nil
+		}
+		if err != nil {
+			return failures, err
+		}
+		if isSucceed(resp.GetStatus()) {
+			continue
+		}
+		var record int
+		if rr := records[resp.GetVersion()]; len(rr) > 0 {
+			record, records[resp.GetVersion()] = rr[0], rr[1:]
+		}
+		failures = append(failures, writeFailure{
+			record: record,
+			id:     fmt.Sprintf("%s.%s version %d", resp.GetMetadata().GetGroup(), resp.GetMetadata().GetName(), resp.GetVersion()),
+			status: resp.GetStatus(),
+		})
+	}
+}
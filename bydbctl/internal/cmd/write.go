@@ -0,0 +1,446 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/bydbctl/pkg/file"
+	"github.com/apache/skywalking-banyandb/pkg/grpchelper"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
+	"github.com/apache/skywalking-banyandb/pkg/version"
+)
+
+const (
+	writeUsage = `The input is one of the following formats, detected by the file extension or set by "--format":
+		1. yaml/json/ndjson: every document is a WriteRequest of the resource in the protojson format.
+		   The "metadata" is filled with the group and name flags when it's absent, and the "message_id" is assigned by bydbctl.
+		   The message IDs grow over time, since the server takes them as the versions of the data without one.
+		2. csv: the first line is the header. Every column is mapped to the tag or field with the same name,
+		   "--map column=target" changes the target, and "--map column=-" skips a column.
+		   The special targets are "timestamp" for measure and stream, "element_id" for stream,
+		   "version" for measure and trace, and "span" (base64 encoded) for trace.
+		   Timestamps are RFC3339 strings or milliseconds since the epoch. Array values are separated by ";".`
+	csvArraySeparator = ";"
+	csvSkipColumn     = "-"
+)
+
+// writeOptions holds the flags shared by the write commands.
+type writeOptions struct {
+	grpcAddr    string
+	format      string
+	mappings    []string
+	timeout     time.Duration
+	batchSize   int
+	concurrency int
+}
+
+// writeFailure is a request rejected by the server.
+type writeFailure struct {
+	id     string
+	status string
+	record int
+}
+
+// dataWriter converts the input to WriteRequests of a resource and sends them through its Write stream.
+type dataWriter interface {
+	// loadSchema fetches the schema of the resource and returns its metadata.
+	loadSchema(ctx context.Context, conn *grpc.ClientConn, md *commonv1.Metadata) (*commonv1.Metadata, error)
+	fromDocument(doc []byte, md *commonv1.Metadata) (writeItem, error)
+	fromCSV(record []string, columns map[string]int, md *commonv1.Metadata) (writeItem, error)
+	// csvTargets returns the names a CSV column can be mapped to.
+	csvTargets() map[string]struct{}
+	// writeBatch sends the items through a Write stream and returns the rejected ones.
+	writeBatch(ctx context.Context, conn *grpc.ClientConn, items []writeItem) ([]writeFailure, error)
+}
+
+// messageIDSeq is the last message ID assigned. It's seeded with the current time, so the IDs never repeat across the runs.
+var messageIDSeq atomic.Uint64
+
+// assignMessageIDs reserves a message ID for each item, and returns the map from the IDs to the records.
+// The server takes the message ID as the default version of a data point,
+// so the IDs of later writes must be greater than the ones written before.
+func assignMessageIDs(items []writeItem) (uint64, map[uint64]int) {
+	messageIDSeq.CompareAndSwap(0, uint64(time.Now().UnixNano()))
+	n := uint64(len(items))
+	first := messageIDSeq.Add(n) - n + 1
+	records := make(map[uint64]int, len(items))
+	for i, item := range items {
+		records[first+uint64(i)] = item.record
+	}
+	return first, records
+}
+
+// writeItem is a WriteRequest with its position in the input.
+type writeItem struct {
+	request any
+	record  int
+}
+
+func newWriteCmd(kind string, writer dataWriter) *cobra.Command {
+	opts := &writeOptions{}
+	writeCmd := &cobra.Command{
+		Use:     "write [-g group] [-n name] -f [file|-]",
+		Version: version.Build(),
+		Short:   fmt.Sprintf("Write data into a %s from files", kind),
+		Long:    writeUsage,
+		RunE: func(cmd *cobra.Command, _ []string) (err error) {
+			if filePath == "" {
+				return errors.New("the input file is required, use \"-\" to read from stdin")
+			}
+			if opts.batchSize < 1 || opts.concurrency < 1 {
+				return errors.New("batch-size and concurrency should be positive")
+			}
+			format := opts.format
+			if format == "" {
+				format = file.DetectFormat(filePath)
+			}
+			md := &commonv1.Metadata{Group: viper.GetString("group"), Name: name}
+			ctx := writeContext()
+			conn, err := dialWrite(opts)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			items, err := readWriteItems(ctx, cmd, conn, writer, format, md, opts)
+			if err != nil {
+				return err
+			}
+			begin := time.Now()
+			failures, err := runWriteBatches(ctx, conn, writer, items, opts)
+			if err != nil {
+				return err
+			}
+			return reportWrite(kind, len(items), failures, time.Since(begin))
+		},
+	}
+	writeCmd.Flags().StringVarP(&name, "name", "n", "", "the name of the resource, it is used when the input doesn't contain metadata")
+	writeCmd.Flags().StringVarP(&filePath, "file", "f", "", "The data file to write, \"-\" reads from stdin")
+	writeCmd.Flags().StringVar(&opts.grpcAddr, "grpc-addr", "localhost:17912", "Grpc server's address, the format is Domain:Port")
+	writeCmd.Flags().StringVar(&opts.format, "format", "", "The input format: yaml, json, ndjson or csv. It's detected by the file extension by default")
+	writeCmd.Flags().StringArrayVar(&opts.mappings, "map", nil, "Map a csv column to a tag, field or special target, the format is column=target")
+	writeCmd.Flags().IntVar(&opts.batchSize, "batch-size", 1000, "The number of requests sent through a write stream")
+	writeCmd.Flags().IntVar(&opts.concurrency, "concurrency", 2, "The number of concurrent write streams")
+	writeCmd.Flags().DurationVar(&opts.timeout, "timeout", time.Minute, "The timeout of a write stream")
+	bindTLSRelatedFlag(writeCmd)
+	return writeCmd
+}
+
+// grpcCredentials returns the username and password from the flags or the config file.
+func grpcCredentials() (string, string) {
+	if username != "" {
+		return username, password
+	}
+	return viper.GetString("username"), viper.GetString("password")
+}
+
+func writeContext() context.Context {
+	user, pwd := grpcCredentials()
+	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs("username", user, "password", pwd))
+}
+
+func dialWrite(opts *writeOptions) (*grpc.ClientConn, error) {
+	dialOpts, err := grpchelper.SecureOptions(nil, enableTLS, insecure, cert)
+	if err != nil {
+		return nil, err
+	}
+	user, pwd := grpcCredentials()
+	return grpchelper.ConnWithAuth(opts.grpcAddr, 10*time.Second, user, pwd, dialOpts...)
+}
+
+func readWriteItems(ctx context.Context, cmd *cobra.Command, conn *grpc.ClientConn, writer dataWriter,
+	format string, md *commonv1.Metadata, opts *writeOptions,
+) ([]writeItem, error) {
+	if format != file.FormatCSV {
+		docs, err := file.ReadDocuments(filePath, format, cmd.InOrStdin())
+		if err != nil {
+			return nil, err
+		}
+		items := make([]writeItem, 0, len(docs))
+		for i, d := range docs {
+			item, err := writer.fromDocument(d, md)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to parse the document %d", i+1)
+			}
+			item.record = i + 1
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	if md.GetGroup() == "" || md.GetName() == "" {
+		return nil, errors.New("please specify the group and name through the flags or the config file to write a csv file")
+	}
+	md, err := writer.loadSchema(ctx, conn, md)
+	if err != nil {
+		return nil, err
+	}
+	header, records, err := file.ReadCSV(filePath, cmd.InOrStdin())
+	if err != nil {
+		return nil, err
+	}
+	columns, err := mapCSVColumns(header, opts.mappings, writer.csvTargets())
+	if err != nil {
+		return nil, err
+	}
+	items := make([]writeItem, 0, len(records))
+	for i, r := range records {
+		item, err := writer.fromCSV(r, columns, md)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to parse the csv record %d", i+1)
+		}
+		item.record = i + 1
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// mapCSVColumns returns the column index of each target.
+func mapCSVColumns(header, mappings []string, targets map[string]struct{}) (map[string]int, error) {
+	renames := make(map[string]string, len(mappings))
+	for _, m := range mappings {
+		column, target, ok := strings.Cut(m, "=")
+		if !ok || column == "" || target == "" {
+			return nil, fmt.Errorf("invalid mapping %q, the format is column=target", m)
+		}
+		renames[strings.TrimSpace(column)] = strings.TrimSpace(target)
+	}
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		target := h
+		if t, ok := renames[h]; ok {
+			target = t
+		}
+		if target == csvSkipColumn {
+			continue
+		}
+		if _, ok := targets[target]; !ok {
+			return nil, fmt.Errorf("column %q is mapped to an unknown tag or field %q", h, target)
+		}
+		if _, ok := columns[target]; ok {
+			return nil, fmt.Errorf("more than one column is mapped to %q", target)
+		}
+		columns[target] = i
+	}
+	return columns, nil
+}
+
+// runWriteBatches splits the items into batches and writes them through concurrent streams.
+func runWriteBatches(ctx context.Context, conn *grpc.ClientConn, writer dataWriter, items []writeItem, opts *writeOptions) ([]writeFailure, error) {
+	batches := make(chan []writeItem)
+	var mu sync.Mutex
+	var failures []writeFailure
+	var errs []error
+	var wg sync.WaitGroup
+	for i := 0; i < opts.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				batchCtx, cancel := context.WithTimeout(ctx, opts.timeout)
+				ff, err := writer.writeBatch(batchCtx, conn, batch)
+				cancel()
+				mu.Lock()
+				failures = append(failures, ff...)
+				if err != nil {
+					errs = append(errs, errors.WithMessagef(err, "failed to write records %d-%d", batch[0].record, batch[len(batch)-1].record))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for start := 0; start < len(items); start += opts.batchSize {
+		end := start + opts.batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		batches <- items[start:end]
+	}
+	close(batches)
+	wg.Wait()
+	if len(errs) > 0 {
+		return failures, errs[0]
+	}
+	return failures, nil
+}
+
+func reportWrite(kind string, total int, failures []writeFailure, elapsed time.Duration) error {
+	fmt.Printf("%d %s records are sent in %s, %d failed\n", total, kind, elapsed.Round(time.Millisecond), len(failures))
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].record < failures[j].record })
+	for _, f := range failures {
+		fmt.Printf("record %d (%s): %s\n", f.record, f.id, f.status)
+	}
+	return fmt.Errorf("%d of %d %s records are rejected", len(failures), total, kind)
+}
+
+// metadataForBatch clears the metadata of a request when it equals the previous one in the same stream.
+func metadataForBatch(md, last *commonv1.Metadata) *commonv1.Metadata {
+	if last != nil && md.GetGroup() == last.GetGroup() && md.GetName() == last.GetName() && md.GetModRevision() == last.GetModRevision() {
+		return nil
+	}
+	return md
+}
+
+func defaultMetadata(md, fallback *commonv1.Metadata) (*commonv1.Metadata, error) {
+	if md != nil {
+		return md, nil
+	}
+	if fallback.GetGroup() == "" || fallback.GetName() == "" {
+		return nil, errors.New("absent node: metadata, specify the group and name through the input or the flags")
+	}
+	return &commonv1.Metadata{Group: fallback.GetGroup(), Name: fallback.GetName()}, nil
+}
+
+func isSucceed(status string) bool {
+	return status == "" || status == modelv1.Status_STATUS_SUCCEED.String()
+}
+
+func parseCSVTimestamp(value string) (*timestamppb.Timestamp, error) {
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return timestamppb.New(time.UnixMilli(ms)), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, errors.Errorf("invalid timestamp %q, it should be RFC3339 or milliseconds", value)
+	}
+	return timestamppb.New(t), nil
+}
+
+func parseCSVTagValue(tagType databasev1.TagType, value string) (*modelv1.TagValue, error) {
+	if value == "" {
+		return pbv1.NullTagValue, nil
+	}
+	switch tagType {
+	case databasev1.TagType_TAG_TYPE_STRING:
+		return &modelv1.TagValue{Value: &modelv1.TagValue_Str{Str: &modelv1.Str{Value: value}}}, nil
+	case databasev1.TagType_TAG_TYPE_INT:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &modelv1.TagValue{Value: &modelv1.TagValue_Int{Int: &modelv1.Int{Value: v}}}, nil
+	case databasev1.TagType_TAG_TYPE_STRING_ARRAY:
+		return &modelv1.TagValue{Value: &modelv1.TagValue_StrArray{StrArray: &modelv1.StrArray{Value: strings.Split(value, csvArraySeparator)}}}, nil
+	case databasev1.TagType_TAG_TYPE_INT_ARRAY:
+		parts := strings.Split(value, csvArraySeparator)
+		values := make([]int64, 0, len(parts))
+		for _, p := range parts {
+			v, err := strconv.ParseInt(strings.TrimSpace(p), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return &modelv1.TagValue{Value: &modelv1.TagValue_IntArray{IntArray: &modelv1.IntArray{Value: values}}}, nil
+	case databasev1.TagType_TAG_TYPE_DATA_BINARY:
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+		return &modelv1.TagValue{Value: &modelv1.TagValue_BinaryData{BinaryData: b}}, nil
+	case databasev1.TagType_TAG_TYPE_TIMESTAMP:
+		ts, err := parseCSVTimestamp(value)
+		if err != nil {
+			return nil, err
+		}
+		return &modelv1.TagValue{Value: &modelv1.TagValue_Timestamp{Timestamp: ts}}, nil
+	default:
+		return nil, errors.Errorf("unsupported tag type %s", tagType)
+	}
+}
+
+func parseCSVFieldValue(fieldType databasev1.FieldType, value string) (*modelv1.FieldValue, error) {
+	if value == "" {
+		return pbv1.NullFieldValue, nil
+	}
+	switch fieldType {
+	case databasev1.FieldType_FIELD_TYPE_STRING:
+		return &modelv1.FieldValue{Value: &modelv1.FieldValue_Str{Str: &modelv1.Str{Value: value}}}, nil
+	case databasev1.FieldType_FIELD_TYPE_INT:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &modelv1.FieldValue{Value: &modelv1.FieldValue_Int{Int: &modelv1.Int{Value: v}}}, nil
+	case databasev1.FieldType_FIELD_TYPE_FLOAT:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &modelv1.FieldValue{Value: &modelv1.FieldValue_Float{Float: &modelv1.Float{Value: v}}}, nil
+	case databasev1.FieldType_FIELD_TYPE_DATA_BINARY:
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+		return &modelv1.FieldValue{Value: &modelv1.FieldValue_BinaryData{BinaryData: b}}, nil
+	case databasev1.FieldType_FIELD_TYPE_HISTOGRAM:
+		h := &modelv1.Histogram{}
+		if err := protojson.Unmarshal([]byte(value), h); err != nil {
+			return nil, err
+		}
+		return &modelv1.FieldValue{Value: &modelv1.FieldValue_Histogram{Histogram: h}}, nil
+	default:
+		return nil, errors.Errorf("unsupported field type %s", fieldType)
+	}
+}
+
+func csvValue(record []string, columns map[string]int, target string) string {
+	i, ok := columns[target]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func csvTagFamilies(tagFamilies []*databasev1.TagFamilySpec, record []string, columns map[string]int) ([]*modelv1.TagFamilyForWrite, error) {
+	result := make([]*modelv1.TagFamilyForWrite, 0, len(tagFamilies))
+	for _, tf := range tagFamilies {
+		family := &modelv1.TagFamilyForWrite{}
+		for _, t := range tf.GetTags() {
+			v, err := parseCSVTagValue(t.GetType(), csvValue(record, columns, t.GetName()))
+			if err != nil {
+				return nil, errors.WithMessagef(err, "tag %s", t.GetName())
+			}
+			family.Tags = append(family.Tags, v)
+		}
+		result = append(result, family)
+	}
+	return result, nil
+}
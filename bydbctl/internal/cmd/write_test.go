@@ -0,0 +1,179 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/zenizh/go-capturer"
+
+	measurev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/measure/v1"
+	"github.com/apache/skywalking-banyandb/bydbctl/internal/cmd"
+	"github.com/apache/skywalking-banyandb/pkg/test/flags"
+	"github.com/apache/skywalking-banyandb/pkg/test/helpers"
+	"github.com/apache/skywalking-banyandb/pkg/test/setup"
+	"github.com/apache/skywalking-banyandb/pkg/timestamp"
+)
+
+var _ = Describe("Measure Data Write", func() {
+	var addr, grpcAddr string
+	var deferFunc func()
+	var rootCmd *cobra.Command
+	var now time.Time
+	BeforeEach(func() {
+		grpcAddr, addr, deferFunc = setup.Standalone(nil)
+		addr = httpSchema + addr
+		rootCmd = &cobra.Command{Use: "root"}
+		cmd.RootCmdFlags(rootCmd)
+		now = timestamp.NowMilli()
+	})
+
+	query := func() int {
+		rootCmd.SetArgs([]string{"measure", "query", "-a", addr, "--start", "-1h", "--end", "1h", "-f", "-"})
+		rootCmd.SetIn(strings.NewReader(`
+name: service_cpm_minute
+groups: ["sw_metric"]
+tagProjection:
+  tagFamilies:
+    - name: default
+      tags:
+        - id`))
+		out := capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		resp := new(measurev1.QueryResponse)
+		helpers.UnmarshalYAML([]byte(out), resp)
+		return len(resp.DataPoints)
+	}
+
+	It("writes csv records", func() {
+		var sb strings.Builder
+		sb.WriteString("ts,id,entity_id,total,value\n")
+		for i := 0; i < 5; i++ {
+			sb.WriteString(fmt.Sprintf("%d,svc%d,entity_%d,%d,%d\n", now.Add(-time.Duration(i)*time.Minute).UnixMilli(), i, i, i*10, i))
+		}
+		rootCmd.SetArgs([]string{
+			"measure", "write", "--grpc-addr", grpcAddr, "-g", "sw_metric", "-n", "service_cpm_minute",
+			"--format", "csv", "--map", "ts=timestamp", "--batch-size", "2", "-f", "-",
+		})
+		rootCmd.SetIn(strings.NewReader(sb.String()))
+		out := capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		Expect(out).To(ContainSubstring("5 measure records are sent"))
+		Eventually(query, flags.EventuallyTimeout).Should(Equal(5))
+	})
+
+	It("writes yaml documents", func() {
+		rootCmd.SetArgs([]string{"measure", "write", "--grpc-addr", grpcAddr, "-g", "sw_metric", "-n", "service_cpm_minute", "-f", "-"})
+		rootCmd.SetIn(strings.NewReader(fmt.Sprintf(`
+dataPoint:
+  timestamp: %s
+  tagFamilies:
+    - tags:
+        - str:
+            value: svc1
+        - str:
+            value: entity_1
+  fields:
+    - int:
+        value: 10
+    - int:
+        value: 1
+---
+metadata:
+  group: sw_metric
+  name: service_cpm_minute
+dataPoint:
+  timestamp: %s
+  tagFamilies:
+    - tags:
+        - str:
+            value: svc2
+        - str:
+            value: entity_2
+  fields:
+    - int:
+        value: 20
+    - int:
+        value: 2`, now.Format(time.RFC3339), now.Add(-time.Minute).Format(time.RFC3339))))
+		out := capturer.CaptureStdout(func() {
+			err := rootCmd.Execute()
+			Expect(err).NotTo(HaveOccurred())
+		})
+		Expect(out).To(ContainSubstring("2 measure records are sent"))
+		Eventually(query, flags.EventuallyTimeout).Should(Equal(2))
+	})
+
+	It("overwrites the data points written by an earlier run", func() {
+		write := func(total int) {
+			rootCmd.SetArgs([]string{
+				"measure", "write", "--grpc-addr", grpcAddr, "-g", "sw_metric", "-n", "service_cpm_minute",
+				"--format", "csv", "--map", "ts=timestamp", "-f", "-",
+			})
+			rootCmd.SetIn(strings.NewReader(fmt.Sprintf("ts,id,entity_id,total,value\n%d,svc1,entity_1,%d,1\n", now.UnixMilli(), total)))
+			capturer.CaptureStdout(func() {
+				err := rootCmd.Execute()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		}
+		write(10)
+		write(20)
+		Eventually(func() int64 {
+			rootCmd.SetArgs([]string{"measure", "query", "-a", addr, "--start", "-1h", "--end", "1h", "-f", "-"})
+			rootCmd.SetIn(strings.NewReader(`
+name: service_cpm_minute
+groups: ["sw_metric"]
+tagProjection:
+  tagFamilies:
+    - name: default
+      tags:
+        - id
+fieldProjection:
+  names: ["total"]`))
+			out := capturer.CaptureStdout(func() {
+				err := rootCmd.Execute()
+				Expect(err).NotTo(HaveOccurred())
+			})
+			resp := new(measurev1.QueryResponse)
+			helpers.UnmarshalYAML([]byte(out), resp)
+			if len(resp.DataPoints) != 1 || len(resp.DataPoints[0].Fields) != 1 {
+				return -1
+			}
+			return resp.DataPoints[0].Fields[0].GetValue().GetInt().GetValue()
+		}, flags.EventuallyTimeout).Should(Equal(int64(20)))
+	})
+
+	It("rejects a csv column without a target", func() {
+		rootCmd.SetArgs([]string{"measure", "write", "--grpc-addr", grpcAddr, "-g", "sw_metric", "-n", "service_cpm_minute", "--format", "csv", "-f", "-"})
+		rootCmd.SetIn(strings.NewReader("timestamp,unknown\n0,a\n"))
+		err := rootCmd.Execute()
+		Expect(err).To(MatchError(ContainSubstring("unknown tag or field")))
+	})
+
+	AfterEach(func() {
+		deferFunc()
+	})
+})
@@ -0,0 +1,177 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Supported formats of data files.
+const (
+	FormatYAML   = "yaml"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+// DetectFormat returns the format indicated by the extension of the path.
+// YAML is assumed when the extension is unknown or the path is "-".
+func DetectFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	case ".csv":
+		return FormatCSV
+	default:
+		return FormatYAML
+	}
+}
+
+// ReadDocuments reads a YAML, JSON or NDJSON file, or stdin when the path is "-", and converts every document to JSON.
+// A YAML file may contain several documents separated by "---", and a JSON file may hold an array of documents.
+func ReadDocuments(path, format string, reader io.Reader) (docs [][]byte, err error) {
+	content, err := readAll(path, reader)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			docs = append(docs, append([]byte(nil), line...))
+		}
+		return docs, scanner.Err()
+	case FormatJSON:
+		trimmed := bytes.TrimSpace(content)
+		if len(trimmed) > 0 && trimmed[0] == '[' {
+			var items []json.RawMessage
+			if err = json.Unmarshal(trimmed, &items); err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				docs = append(docs, item)
+			}
+			return docs, nil
+		}
+		if len(trimmed) == 0 {
+			return nil, nil
+		}
+		return [][]byte{trimmed}, nil
+	case FormatYAML:
+		return yamlToJSONDocuments(content)
+	default:
+		return nil, fmt.Errorf("unsupported document format %q", format)
+	}
+}
+
+// ReadYAMLDocuments reads the YAML files in the same way as Read does, and converts every document in them to JSON.
+func ReadYAMLDocuments(path string, reader io.Reader) (docs [][]byte, err error) {
+	contents, err := Read(path, reader)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contents {
+		d, err := yamlToJSONDocuments(c)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, d...)
+	}
+	return docs, nil
+}
+
+// ReadCSV reads a CSV file, or stdin when the path is "-". The first record is returned as the header.
+func ReadCSV(path string, reader io.Reader) (header []string, records [][]string, err error) {
+	content, err := readAll(path, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := csv.NewReader(bytes.NewReader(content))
+	r.TrimLeadingSpace = true
+	all, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(all) == 0 {
+		return nil, nil, nil
+	}
+	header = all[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	return header, all[1:], nil
+}
+
+func readAll(path string, reader io.Reader) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(bufio.NewReader(reader))
+	}
+	return os.ReadFile(path)
+}
+
+func yamlToJSONDocuments(content []byte) (docs [][]byte, err error) {
+	for _, d := range splitYAMLDocuments(content) {
+		if len(bytes.TrimSpace(d)) == 0 {
+			continue
+		}
+		j, err := yaml.YAMLToJSON(d)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(j, []byte("null")) {
+			continue
+		}
+		docs = append(docs, j)
+	}
+	return docs, nil
+}
+
+func splitYAMLDocuments(content []byte) [][]byte {
+	var docs [][]byte
+	var current bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimRight(line, " \t") == "---" {
+			docs = append(docs, append([]byte(nil), current.Bytes()...))
+			current.Reset()
+			continue
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+	return append(docs, current.Bytes())
+}
@@ -0,0 +1,105 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatYAML, DetectFormat("-"))
+	assert.Equal(t, FormatYAML, DetectFormat("data.yml"))
+	assert.Equal(t, FormatJSON, DetectFormat("data.JSON"))
+	assert.Equal(t, FormatNDJSON, DetectFormat("data.jsonl"))
+	assert.Equal(t, FormatCSV, DetectFormat("data.csv"))
+}
+
+func TestReadDocuments(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		want   []string
+	}{
+		{
+			name:   "yaml documents",
+			format: FormatYAML,
+			input:  "a: 1\n---\nb: 2\n---\n",
+			want:   []string{`{"a":1}`, `{"b":2}`},
+		},
+		{
+			name:   "json array",
+			format: FormatJSON,
+			input:  `[{"a":1},{"b":2}]`,
+			want:   []string{`{"a":1}`, `{"b":2}`},
+		},
+		{
+			name:   "json object",
+			format: FormatJSON,
+			input:  ` {"a":1} `,
+			want:   []string{`{"a":1}`},
+		},
+		{
+			name:   "ndjson",
+			format: FormatNDJSON,
+			input:  "{\"a\":1}\n\n{\"b\":2}\n",
+			want:   []string{`{"a":1}`, `{"b":2}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := ReadDocuments("-", tt.format, strings.NewReader(tt.input))
+			require.NoError(t, err)
+			got := make([]string, 0, len(docs))
+			for _, d := range docs {
+				got = append(got, string(d))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	_, err := ReadDocuments("-", "xml", strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestReadCSV(t *testing.T) {
+	header, records, err := ReadCSV("-", strings.NewReader("timestamp, id ,total\n2024-01-01T00:00:00Z,svc1,10\n2024-01-01T00:01:00Z,svc2,20\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"timestamp", "id", "total"}, header)
+	assert.Equal(t, [][]string{{"2024-01-01T00:00:00Z", "svc1", "10"}, {"2024-01-01T00:01:00Z", "svc2", "20"}}, records)
+}
+
+func TestReadYAMLDocuments(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("a: 1\n---\nb: 2\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "c.yml"), []byte("c: 3\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("d: 4\n"), 0o600))
+	docs, err := ReadYAMLDocuments(dir, nil)
+	require.NoError(t, err)
+	got := make([]string, 0, len(docs))
+	for _, d := range docs {
+		got = append(got, string(d))
+	}
+	assert.Equal(t, []string{`{"a":1}`, `{"b":2}`, `{"c":3}`}, got)
+}
@@ -70,6 +70,13 @@ func init() {
 	}
 }
 
+// Keywords returns a copy of the BydbQL keyword list in upper case.
+func Keywords() []string {
+	keywords := make([]string, len(bydbqlKeywords))
+	copy(keywords, bydbqlKeywords)
+	return keywords
+}
+
 // ParseQuery parses a BydbQL query string into a Grammar struct.
 func ParseQuery(query string) (*Grammar, error) {
 	// Parse using Participle
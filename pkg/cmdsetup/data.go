@@ -24,6 +24,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/apache/skywalking-banyandb/api/common"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	"github.com/apache/skywalking-banyandb/banyand/liaison/grpc/route"
 	"github.com/apache/skywalking-banyandb/banyand/measure"
 	"github.com/apache/skywalking-banyandb/banyand/metadata/service"
@@ -33,6 +34,7 @@ import (
 	"github.com/apache/skywalking-banyandb/banyand/protector"
 	"github.com/apache/skywalking-banyandb/banyand/query"
 	"github.com/apache/skywalking-banyandb/banyand/queue"
+	"github.com/apache/skywalking-banyandb/banyand/queue/pub"
 	"github.com/apache/skywalking-banyandb/banyand/queue/sub"
 	"github.com/apache/skywalking-banyandb/banyand/stream"
 	"github.com/apache/skywalking-banyandb/banyand/trace"
@@ -63,16 +65,18 @@ func newDataCmd(runners ...run.Unit) *cobra.Command {
 	pipeline.SetRouteProviders(map[string]route.TableProvider{
 		"property": propertySvc,
 	})
+	// peerClient reaches the other data nodes to repair the corrupted parts from their replicas.
+	peerClient := pub.New(metaSvc, databasev1.Role_ROLE_DATA)
 
-	streamSvc, err := stream.NewService(metaSvc, pipeline, metricSvc, pm, propertyStreamPipeline)
+	streamSvc, err := stream.NewService(metaSvc, pipeline, metricSvc, pm, propertyStreamPipeline, peerClient)
 	if err != nil {
 		l.Fatal().Err(err).Msg("failed to initiate stream service")
 	}
-	measureSvc, err := measure.NewDataSVC(metaSvc, pipeline, metricsPipeline, metricSvc, pm)
+	measureSvc, err := measure.NewDataSVC(metaSvc, pipeline, metricsPipeline, metricSvc, pm, peerClient)
 	if err != nil {
 		l.Fatal().Err(err).Msg("failed to initiate measure service")
 	}
-	traceSvc, err := trace.NewService(metaSvc, pipeline, metricSvc, pm)
+	traceSvc, err := trace.NewService(metaSvc, pipeline, metricSvc, pm, peerClient)
 	if err != nil {
 		l.Fatal().Err(err).Msg("failed to initiate trace service")
 	}
@@ -90,6 +94,7 @@ func newDataCmd(runners ...run.Unit) *cobra.Command {
 		metaSvc,
 		pm,
 		pipeline,
+		peerClient,
 		propertyStreamPipeline,
 		propertySvc,
 		measureSvc,
@@ -58,11 +58,11 @@ func newStandaloneCmd(runners ...run.Unit) *cobra.Command {
 	if err != nil {
 		l.Fatal().Err(err).Msg("failed to initiate property service")
 	}
-	streamSvc, err := stream.NewService(metaSvc, dataPipeline, metricSvc, pm, nil)
+	streamSvc, err := stream.NewService(metaSvc, dataPipeline, metricSvc, pm, nil, nil)
 	if err != nil {
 		l.Fatal().Err(err).Msg("failed to initiate stream service")
 	}
-	traceSvc, err := trace.NewService(metaSvc, dataPipeline, metricSvc, pm)
+	traceSvc, err := trace.NewService(metaSvc, dataPipeline, metricSvc, pm, nil)
 	if err != nil {
 		l.Fatal().Err(err).Msg("failed to initiate trace service")
 	}
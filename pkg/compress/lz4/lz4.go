@@ -0,0 +1,194 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package lz4 provides LZ4 compression and decompression of the block format.
+//
+// It implements the LZ4 block format described at
+// https://github.com/lz4/lz4/blob/dev/doc/lz4_Block_format.md with a greedy single-probe matcher,
+// which favours speed over the compression ratio.
+package lz4
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+const (
+	minMatch     = 4
+	mfLimit      = 12
+	lastLiterals = 5
+	maxOffset    = 1<<16 - 1
+	hashLog      = 14
+	hashShift    = 32 - hashLog
+)
+
+var (
+	errCorrupted = errors.New("lz4: corrupted block")
+
+	tablePool = sync.Pool{
+		New: func() any {
+			return new([1 << hashLog]int32)
+		},
+	}
+)
+
+// Compress compresses the src and appends the result to dst.
+func Compress(dst, src []byte) []byte {
+	table := tablePool.Get().(*[1 << hashLog]int32)
+	defer func() {
+		*table = [1 << hashLog]int32{}
+		tablePool.Put(table)
+	}()
+
+	anchor := 0
+	if len(src) > mfLimit {
+		limit := len(src) - mfLimit
+		matchLimit := len(src) - lastLiterals
+		for i := 0; i < limit; {
+			seq := binary.LittleEndian.Uint32(src[i:])
+			h := (seq * 2654435761) >> hashShift
+			// positions are stored with an offset of one so that zero marks an empty slot
+			ref := int(table[h]) - 1
+			table[h] = int32(i + 1)
+			if ref < 0 || i-ref > maxOffset || binary.LittleEndian.Uint32(src[ref:]) != seq {
+				i++
+				continue
+			}
+			for i > anchor && ref > 0 && src[i-1] == src[ref-1] {
+				i--
+				ref--
+			}
+			end := i + minMatch
+			for end < matchLimit && src[end] == src[ref+end-i] {
+				end++
+			}
+			dst = appendSequence(dst, src[anchor:i], i-ref, end-i)
+			i = end
+			anchor = i
+		}
+	}
+	return appendLiterals(dst, src[anchor:])
+}
+
+func appendSequence(dst, literals []byte, offset, matchLen int) []byte {
+	litLen := len(literals)
+	ml := matchLen - minMatch
+	token := byte(min(litLen, 15))<<4 | byte(min(ml, 15))
+	dst = append(dst, token)
+	if litLen >= 15 {
+		dst = appendLength(dst, litLen-15)
+	}
+	dst = append(dst, literals...)
+	dst = append(dst, byte(offset), byte(offset>>8))
+	if ml >= 15 {
+		dst = appendLength(dst, ml-15)
+	}
+	return dst
+}
+
+func appendLiterals(dst, literals []byte) []byte {
+	litLen := len(literals)
+	dst = append(dst, byte(min(litLen, 15))<<4)
+	if litLen >= 15 {
+		dst = appendLength(dst, litLen-15)
+	}
+	return append(dst, literals...)
+}
+
+func appendLength(dst []byte, n int) []byte {
+	for n >= 255 {
+		dst = append(dst, 255)
+		n -= 255
+	}
+	return append(dst, byte(n))
+}
+
+// Decompress decompresses the src and appends the result to dst.
+func Decompress(dst, src []byte) ([]byte, error) {
+	base := len(dst)
+	i := 0
+	for {
+		if i >= len(src) {
+			return dst[:base], errCorrupted
+		}
+		token := src[i]
+		i++
+
+		litLen := int(token >> 4)
+		if litLen == 15 {
+			n, next, err := readLength(src, i)
+			if err != nil {
+				return dst[:base], err
+			}
+			litLen += n
+			i = next
+		}
+		if litLen > len(src)-i {
+			return dst[:base], errCorrupted
+		}
+		dst = append(dst, src[i:i+litLen]...)
+		i += litLen
+		if i == len(src) {
+			return dst, nil
+		}
+
+		if i+2 > len(src) {
+			return dst[:base], errCorrupted
+		}
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+		if offset == 0 || offset > len(dst)-base {
+			return dst[:base], errCorrupted
+		}
+		matchLen := int(token & 15)
+		if matchLen == 15 {
+			n, next, err := readLength(src, i)
+			if err != nil {
+				return dst[:base], err
+			}
+			matchLen += n
+			i = next
+		}
+		matchLen += minMatch
+
+		pos := len(dst) - offset
+		if offset >= matchLen {
+			dst = append(dst, dst[pos:pos+matchLen]...)
+			continue
+		}
+		// the match overlaps the bytes it produces, so it has to be copied byte by byte
+		for k := 0; k < matchLen; k++ {
+			dst = append(dst, dst[pos+k])
+		}
+	}
+}
+
+func readLength(src []byte, i int) (int, int, error) {
+	n := 0
+	for {
+		if i >= len(src) {
+			return 0, i, errCorrupted
+		}
+		b := src[i]
+		i++
+		n += int(b)
+		if b != 255 {
+			return n, i, nil
+		}
+	}
+}
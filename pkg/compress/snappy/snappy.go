@@ -0,0 +1,59 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package snappy provides Snappy compression and decompression.
+package snappy
+
+import (
+	"github.com/klauspost/compress/s2"
+
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+)
+
+// Compress compresses the src and appends the result to dst.
+func Compress(dst, src []byte) []byte {
+	n := s2.MaxEncodedLen(len(src))
+	if n < 0 {
+		logger.Panicf("too large block to compress with Snappy: %d bytes", len(src))
+	}
+	dstLen := len(dst)
+	dst = growBuf(dst, n)
+	encoded := s2.EncodeSnappy(dst[dstLen:dstLen+n], src)
+	return dst[:dstLen+len(encoded)]
+}
+
+// Decompress decompresses the src and appends the result to dst.
+func Decompress(dst, src []byte) ([]byte, error) {
+	n, err := s2.DecodedLen(src)
+	if err != nil {
+		return dst, err
+	}
+	dstLen := len(dst)
+	dst = growBuf(dst, n)
+	decoded, err := s2.Decode(dst[dstLen:dstLen+n], src)
+	if err != nil {
+		return dst[:dstLen], err
+	}
+	return dst[:dstLen+len(decoded)], nil
+}
+
+func growBuf(dst []byte, n int) []byte {
+	if need := len(dst) + n - cap(dst); need > 0 {
+		dst = append(dst[:cap(dst)], make([]byte, need)...)[:len(dst)]
+	}
+	return dst
+}
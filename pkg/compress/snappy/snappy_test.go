@@ -0,0 +1,98 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snappy_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/pkg/compress/snappy"
+)
+
+func randString(n int) []byte {
+	letters := []byte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+	b := make([]byte, n)
+	for i := range b {
+		maxVal := big.NewInt(int64(len(letters)))
+		randIndex, err := rand.Int(rand.Reader, maxVal)
+		if err != nil {
+			panic(err)
+		}
+		b[i] = letters[randIndex.Int64()]
+	}
+
+	return b
+}
+
+func TestCompressAndDecompress(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "Empty",
+			data: []byte{},
+		},
+		{
+			name: "SingleByte",
+			data: randString(1),
+		},
+		{
+			name: "NormalSizeBytes",
+			data: randString(1000), // 1000 bytes
+		},
+		{
+			name: "RepeatedBytes",
+			data: bytes.Repeat([]byte("service_cpm_minute"), 1000),
+		},
+		{
+			name: "SuperBigBytes",
+			data: randString(1e6), // 1 million bytes
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prefix := []byte("prefix")
+			compressed := snappy.Compress(append([]byte{}, prefix...), tc.data)
+			require.Equal(t, prefix, compressed[:len(prefix)], "Compress should append to dst")
+
+			decompressed, err := snappy.Decompress(append([]byte{}, prefix...), compressed[len(prefix):])
+			require.NoError(t, err, "Decompress should not return an error")
+			require.Equal(t, prefix, decompressed[:len(prefix)], "Decompress should append to dst")
+			require.Equal(t, tc.data, decompressed[len(prefix):], "Decompressed data should be equal to the original data")
+		})
+	}
+}
+
+func TestCompressRepeatedBytes(t *testing.T) {
+	data := bytes.Repeat([]byte("service_cpm_minute"), 1000)
+	compressed := snappy.Compress(nil, data)
+	require.Less(t, len(compressed), len(data)/10)
+}
+
+func TestDecompressCorrupted(t *testing.T) {
+	compressed := snappy.Compress(nil, bytes.Repeat([]byte("service_cpm_minute"), 100))
+	_, err := snappy.Decompress(nil, compressed[:len(compressed)/2])
+	require.Error(t, err)
+}
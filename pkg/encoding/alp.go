@@ -0,0 +1,217 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/bits"
+
+	pkgbytes "github.com/apache/skywalking-banyandb/pkg/bytes"
+)
+
+const (
+	alpMaxExponent = 18
+	alpSampleSize  = 32
+	// alpMaxEncoded bounds the scaled values, so that they are exactly representable by both int64 and float64 rounding.
+	alpMaxEncoded = 1 << 62
+	// alpExceptionCost is the estimated bits of an exception, which stores its position and raw value.
+	alpExceptionCost = 64 + 16
+)
+
+var alpExp10 = [alpMaxExponent + 1]float64{
+	1, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9,
+	1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18,
+}
+
+// Float64ListToALP encodes float64 values with ALP (Adaptive Lossless floating-Point compression).
+// https://dl.acm.org/doi/pdf/10.1145/3626717
+//
+// It scales the values by 10^e/10^f into integers, picking e and f on a sample, and bit-packs them
+// with a frame of reference. The values not round-tripping through the scaling, like NaN, Inf and
+// the high-precision ones, are stored aside as exceptions.
+func Float64ListToALP(dst []byte, nums []float64) []byte {
+	e, f := alpChooseExponents(nums)
+
+	ints := GenerateInt64List(len(nums))
+	defer ReleaseInt64List(ints)
+	exceptions := GenerateUint64List(0)
+	defer ReleaseUint64List(exceptions)
+
+	minValue, maxValue := int64(math.MaxInt64), int64(math.MinInt64)
+	for i, v := range nums {
+		d, ok := alpEncode(v, e, f)
+		if !ok {
+			exceptions.L = append(exceptions.L, uint64(i))
+			continue
+		}
+		ints.L[i] = d
+		minValue = min(minValue, d)
+		maxValue = max(maxValue, d)
+	}
+	if len(exceptions.L) == len(nums) {
+		minValue, maxValue = 0, 0
+	}
+	// the exceptions take the frame of reference, which packs them into zero bits
+	for _, pos := range exceptions.L {
+		ints.L[pos] = minValue
+	}
+
+	dst = append(dst, byte(e), byte(f))
+	dst = VarInt64ToBytes(dst, minValue)
+	width := 64 - bits.LeadingZeros64(uint64(maxValue)-uint64(minValue))
+	dst = append(dst, byte(width))
+	if width > 0 {
+		bb := &pkgbytes.Buffer{Buf: dst}
+		w := NewWriter()
+		w.Reset(bb)
+		for _, d := range ints.L {
+			w.WriteBits(uint64(d)-uint64(minValue), width)
+		}
+		w.Flush()
+		dst = bb.Buf
+	}
+
+	dst = VarUint64ToBytes(dst, uint64(len(exceptions.L)))
+	var prev uint64
+	for _, pos := range exceptions.L {
+		dst = VarUint64ToBytes(dst, pos-prev)
+		prev = pos
+	}
+	for _, pos := range exceptions.L {
+		dst = Uint64ToBytes(dst, math.Float64bits(nums[pos]))
+	}
+	return dst
+}
+
+// ALPToFloat64List decodes float64 values encoded by Float64ListToALP.
+func ALPToFloat64List(dst []float64, src []byte, itemsCount int) ([]float64, error) {
+	dst = ExtendListCapacity(dst, itemsCount)
+	if len(src) < 2 {
+		return nil, fmt.Errorf("cannot decode ALP exponents from %d bytes", len(src))
+	}
+	e, f := int(src[0]), int(src[1])
+	if f > e || e > alpMaxExponent {
+		return nil, fmt.Errorf("invalid ALP exponents: e=%d, f=%d", e, f)
+	}
+	src, minValue, err := BytesToVarInt64(src[2:])
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode ALP frame of reference: %w", err)
+	}
+	if len(src) < 1 {
+		return nil, fmt.Errorf("cannot decode ALP bit width from empty src")
+	}
+	width := int(src[0])
+	if width > 64 {
+		return nil, fmt.Errorf("invalid ALP bit width: %d", width)
+	}
+	src = src[1:]
+
+	start := len(dst)
+	packedLen := (itemsCount*width + 7) / 8
+	if len(src) < packedLen {
+		return nil, fmt.Errorf("cannot decode %d ALP values of %d bits from %d bytes", itemsCount, width, len(src))
+	}
+	r := NewReader(bytes.NewReader(src[:packedLen]))
+	for i := 0; i < itemsCount; i++ {
+		var delta uint64
+		if width > 0 {
+			if delta, err = r.ReadBits(width); err != nil {
+				return nil, fmt.Errorf("cannot decode ALP value %d of %d: %w", i, itemsCount, err)
+			}
+		}
+		d := int64(uint64(minValue) + delta)
+		dst = append(dst, alpDecode(d, e, f))
+	}
+	src = src[packedLen:]
+
+	src, exceptionsCount := BytesToVarUint64(src)
+	if exceptionsCount > uint64(itemsCount) {
+		return nil, fmt.Errorf("too many ALP exceptions: %d of %d values", exceptionsCount, itemsCount)
+	}
+	positions := GenerateUint64List(int(exceptionsCount))
+	defer ReleaseUint64List(positions)
+	var pos uint64
+	for i := range positions.L {
+		var delta uint64
+		src, delta = BytesToVarUint64(src)
+		pos += delta
+		if pos >= uint64(itemsCount) {
+			return nil, fmt.Errorf("invalid ALP exception position %d of %d values", pos, itemsCount)
+		}
+		positions.L[i] = pos
+	}
+	if uint64(len(src)) != 8*exceptionsCount {
+		return nil, fmt.Errorf("unexpected ALP exceptions length for %d exceptions; got %d bytes", exceptionsCount, len(src))
+	}
+	for _, pos := range positions.L {
+		dst[start+int(pos)] = math.Float64frombits(BytesToUint64(src))
+		src = src[8:]
+	}
+	return dst, nil
+}
+
+// alpEncode scales v into an integer, which is decoded back to exactly v.
+func alpEncode(v float64, e, f int) (int64, bool) {
+	n := v * alpExp10[e] / alpExp10[f]
+	// the negated comparisons reject NaN as well
+	if !(n > -alpMaxEncoded && n < alpMaxEncoded) {
+		return 0, false
+	}
+	d := int64(math.Round(n))
+	if math.Float64bits(alpDecode(d, e, f)) != math.Float64bits(v) {
+		return 0, false
+	}
+	return d, true
+}
+
+// alpDecode divides instead of multiplying by 10^-e, since the division is exact for the values parsed from decimals.
+func alpDecode(d int64, e, f int) float64 {
+	return float64(d) * alpExp10[f] / alpExp10[e]
+}
+
+// alpChooseExponents picks the exponents minimizing the estimated size of a sample.
+func alpChooseExponents(nums []float64) (int, int) {
+	step := max(len(nums)/alpSampleSize, 1)
+	bestE, bestF, bestCost := 0, 0, math.MaxInt
+	for e := 0; e <= alpMaxExponent; e++ {
+		for f := 0; f <= e; f++ {
+			exceptions, sampled := 0, 0
+			minValue, maxValue := int64(math.MaxInt64), int64(math.MinInt64)
+			for i := 0; i < len(nums); i += step {
+				sampled++
+				d, ok := alpEncode(nums[i], e, f)
+				if !ok {
+					exceptions++
+					continue
+				}
+				minValue = min(minValue, d)
+				maxValue = max(maxValue, d)
+			}
+			width := 0
+			if exceptions < sampled {
+				width = 64 - bits.LeadingZeros64(uint64(maxValue)-uint64(minValue))
+			}
+			if cost := sampled*width + exceptions*alpExceptionCost; cost < bestCost {
+				bestE, bestF, bestCost = e, f, cost
+			}
+		}
+	}
+	return bestE, bestF
+}
@@ -0,0 +1,62 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestALP(t *testing.T) {
+	for name, values := range float64Fixtures() {
+		t.Run(name, func(t *testing.T) {
+			encoded := Float64ListToALP([]byte{0xff}, values)
+			assert.Equal(t, byte(0xff), encoded[0])
+			decoded, err := ALPToFloat64List(nil, encoded[1:], len(values))
+			require.NoError(t, err)
+			requireSameFloat64s(t, values, decoded)
+		})
+	}
+}
+
+func TestALPChooseExponents(t *testing.T) {
+	e, f := alpChooseExponents([]float64{12.34, 5.6, 78.9, 0.01})
+	assert.Equal(t, 2, e-f)
+	e, f = alpChooseExponents([]float64{1000, 2000, 3000})
+	assert.Equal(t, 0, e-f)
+}
+
+func TestALPExceptions(t *testing.T) {
+	values := []float64{1.5, math.NaN(), 2.25, math.Copysign(0, -1), 3.75, math.Pi}
+	encoded := Float64ListToALP(nil, values)
+	decoded, err := ALPToFloat64List(nil, encoded, len(values))
+	require.NoError(t, err)
+	requireSameFloat64s(t, values, decoded)
+}
+
+func TestALPToFloat64ListCorrupted(t *testing.T) {
+	values := float64Fixtures()["percent"]
+	encoded := Float64ListToALP(nil, values)
+	_, err := ALPToFloat64List(nil, encoded[:len(encoded)/2], len(values))
+	assert.Error(t, err)
+	_, err = ALPToFloat64List(nil, []byte{1, 2}, len(values))
+	assert.Error(t, err)
+}
@@ -43,6 +43,17 @@ func DecodeBytes(src []byte) ([]byte, []byte, error) {
 
 // EncodeBytesBlock encodes a block of strings into dst.
 func EncodeBytesBlock(dst []byte, a [][]byte) []byte {
+	return encodeBytesBlock(dst, a, compressBlock)
+}
+
+// EncodeBytesBlockUncompressed encodes a block of strings into dst without compressing them.
+// It suits the values compressed by clients and the blocks compressed as a whole afterwards.
+// BytesBlockDecoder decodes both blocks.
+func EncodeBytesBlockUncompressed(dst []byte, a [][]byte) []byte {
+	return encodeBytesBlock(dst, a, rawBlock)
+}
+
+func encodeBytesBlock(dst []byte, a [][]byte, compress func(dst, src []byte) []byte) []byte {
 	u64s := GenerateUint64List(len(a))
 	aLens := u64s.L[:0]
 	for _, s := range a {
@@ -63,7 +74,7 @@ func EncodeBytesBlock(dst []byte, a [][]byte) []byte {
 		b = append(b, s...)
 	}
 	bb.Buf = b
-	dst = compressBlock(dst, bb.Buf)
+	dst = compress(dst, bb.Buf)
 	bbPool.Release(bb)
 
 	return dst
@@ -286,8 +297,19 @@ func decodeUint64List(dst []uint64, src []byte, itemsCount uint64) ([]uint64, er
 const (
 	compressTypePlain = 0
 	compressTypeZSTD  = 1
+	compressTypeRaw   = 2
 )
 
+func rawBlock(dst, src []byte) []byte {
+	if len(src) < 128 {
+		dst = append(dst, compressTypePlain, byte(len(src)))
+		return append(dst, src...)
+	}
+	dst = append(dst, compressTypeRaw)
+	dst = VarUint64ToBytes(dst, uint64(len(src)))
+	return append(dst, src...)
+}
+
 func compressBlock(dst, src []byte) []byte {
 	if len(src) < 128 {
 		dst = append(dst, compressTypePlain, byte(len(src)))
@@ -344,8 +366,17 @@ func decompressBlock(dst, src []byte) ([]byte, []byte, error) {
 		dst = append(dst, bb.Buf...)
 		bbPool.Release(bb)
 		return dst, src, nil
+	case compressTypeRaw:
+		tail, blockLen := BytesToVarUint64(src)
+		src = tail
+		if uint64(len(src)) < blockLen {
+			return dst, src, fmt.Errorf("cannot read raw block with the size %d bytes from %d bytes", blockLen, len(src))
+		}
+		dst = append(dst, src[:blockLen]...)
+		src = src[blockLen:]
+		return dst, src, nil
 	default:
-		return dst, src, fmt.Errorf("unexpected block type: %d; supported types: 0, 1", blockType)
+		return dst, src, fmt.Errorf("unexpected block type: %d; supported types: 0, 1, 2", blockType)
 	}
 }
 
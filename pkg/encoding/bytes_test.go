@@ -18,6 +18,7 @@
 package encoding_test
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -62,6 +63,25 @@ func TestEncodeBlockAndDecode(t *testing.T) {
 	}
 }
 
+func TestEncodeBytesBlockUncompressed(t *testing.T) {
+	for _, size := range []int{10, 1000} {
+		t.Run(fmt.Sprintf("size_%d", size), func(t *testing.T) {
+			slices := [][]byte{
+				bytes.Repeat([]byte("a"), size),
+				nil,
+				{},
+				bytes.Repeat([]byte("b"), size),
+			}
+			encoded := encoding.EncodeBytesBlockUncompressed(nil, slices)
+			require.Greater(t, len(encoded), 2*size)
+			blockDecoder := &encoding.BytesBlockDecoder{}
+			decoded, err := blockDecoder.Decode(nil, encoded, uint64(len(slices)))
+			require.NoError(t, err)
+			assert.Equal(t, slices, decoded)
+		})
+	}
+}
+
 func TestEncodeBytesBlockEdgeCases(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,222 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/bits"
+
+	pkgbytes "github.com/apache/skywalking-banyandb/pkg/bytes"
+	"github.com/apache/skywalking-banyandb/pkg/pool"
+)
+
+const (
+	chimpPreviousValues     = 128
+	chimpPreviousValuesLog2 = 7
+	// chimpThreshold is the number of trailing zeros a reference value must produce to be worth its index.
+	chimpThreshold = 6 + chimpPreviousValuesLog2
+	chimpKeyMask   = 1<<(chimpThreshold+1) - 1
+	// chimpNoLeading marks the stored leading zeros invalid, so the next value must write its own.
+	chimpNoLeading = 65
+)
+
+var (
+	// chimpLeadingCodes are the leading zeros representable by 3 bits.
+	chimpLeadingCodes  = [8]int{0, 8, 12, 16, 18, 20, 22, 24}
+	chimpLeadingToCode = func() (r [25]uint64) {
+		for code, leading := range chimpLeadingCodes {
+			r[leading] = uint64(code)
+		}
+		return r
+	}()
+	// chimpLeadingRound rounds the leading zeros down to the closest representable one.
+	chimpLeadingRound = func() (r [65]int) {
+		for i := range r {
+			for _, leading := range chimpLeadingCodes {
+				if leading <= i {
+					r[i] = leading
+				}
+			}
+		}
+		return r
+	}()
+	chimpIndicesPool = pool.Register[*chimpIndices]("encoding-chimpIndices")
+)
+
+// chimpIndices maps the least significant bits of a value to the position it was last seen.
+type chimpIndices [chimpKeyMask + 1]int32
+
+// Float64ListToChimp128 encodes float64 values with Chimp128.
+// https://www.vldb.org/pvldb/vol15/p3058-liakos.pdf
+//
+// Like Gorilla, it stores the XOR of a value and a previous one, but it picks the previous one among the last 128 values
+// sharing the most trailing bits, which suits the values repeated with a few variations like the gauges.
+func Float64ListToChimp128(dst []byte, nums []float64) []byte {
+	if len(nums) == 0 {
+		return dst
+	}
+	bb := &pkgbytes.Buffer{Buf: dst}
+	w := NewWriter()
+	w.Reset(bb)
+
+	var stored [chimpPreviousValues]uint64
+	indices := chimpIndicesPool.Get()
+	if indices == nil {
+		indices = &chimpIndices{}
+	}
+	defer chimpIndicesPool.Put(indices)
+	for i := range indices {
+		indices[i] = -chimpPreviousValues
+	}
+	storedLeading := chimpNoLeading
+
+	first := math.Float64bits(nums[0])
+	w.WriteBits(first, 64)
+	stored[0] = first
+	indices[first&chimpKeyMask] = 0
+
+	for index := 1; index < len(nums); index++ {
+		v := math.Float64bits(nums[index])
+		key := v & chimpKeyMask
+		prevIndex := (index - 1) % chimpPreviousValues
+		xor := stored[prevIndex] ^ v
+		trailing := 0
+		if ref := int(indices[key]); index-ref <= chimpPreviousValues {
+			refXOR := stored[ref%chimpPreviousValues] ^ v
+			if refTrailing := bits.TrailingZeros64(refXOR); refTrailing > chimpThreshold {
+				prevIndex = ref % chimpPreviousValues
+				xor = refXOR
+				trailing = refTrailing
+			}
+		}
+
+		switch {
+		case xor == 0:
+			// '00' followed by the index of the equal value
+			w.WriteBits(uint64(prevIndex), 2+chimpPreviousValuesLog2)
+			storedLeading = chimpNoLeading
+		case trailing > chimpThreshold:
+			// '01' followed by the index, the leading zeros and the significant bits
+			leading := chimpLeadingRound[bits.LeadingZeros64(xor)]
+			significant := 64 - leading - trailing
+			w.WriteBits(1, 2)
+			w.WriteBits(uint64(prevIndex), chimpPreviousValuesLog2)
+			w.WriteBits(chimpLeadingToCode[leading], 3)
+			w.WriteBits(uint64(significant), 6)
+			w.WriteBits(xor>>uint(trailing), significant)
+			storedLeading = chimpNoLeading
+		default:
+			leading := chimpLeadingRound[bits.LeadingZeros64(xor)]
+			if leading == storedLeading {
+				// '10' reuses the leading zeros of the previous value
+				w.WriteBits(2, 2)
+			} else {
+				// '11' followed by the new leading zeros
+				w.WriteBits(3, 2)
+				w.WriteBits(chimpLeadingToCode[leading], 3)
+				storedLeading = leading
+			}
+			w.WriteBits(xor, 64-leading)
+		}
+
+		stored[index%chimpPreviousValues] = v
+		indices[key] = int32(index)
+	}
+	w.Flush()
+	return bb.Buf
+}
+
+// Chimp128ToFloat64List decodes float64 values encoded by Float64ListToChimp128.
+func Chimp128ToFloat64List(dst []float64, src []byte, itemsCount int) ([]float64, error) {
+	dst = ExtendListCapacity(dst, itemsCount)
+	if itemsCount == 0 {
+		return dst, nil
+	}
+	r := NewReader(bytes.NewReader(src))
+	fail := func(i int, err error) ([]float64, error) {
+		return nil, fmt.Errorf("cannot decode chimp128 value %d of %d: %w", i, itemsCount, err)
+	}
+
+	var stored [chimpPreviousValues]uint64
+	storedLeading := chimpNoLeading
+	v, err := r.ReadBits(64)
+	if err != nil {
+		return fail(0, err)
+	}
+	stored[0] = v
+	dst = append(dst, math.Float64frombits(v))
+
+	for index := 1; index < itemsCount; index++ {
+		flag, err := r.ReadBits(2)
+		if err != nil {
+			return fail(index, err)
+		}
+		prev := stored[(index-1)%chimpPreviousValues]
+		switch flag {
+		case 0:
+			ref, err := r.ReadBits(chimpPreviousValuesLog2)
+			if err != nil {
+				return fail(index, err)
+			}
+			v = stored[ref]
+			storedLeading = chimpNoLeading
+		case 1:
+			fields, err := r.ReadBits(chimpPreviousValuesLog2 + 3 + 6)
+			if err != nil {
+				return fail(index, err)
+			}
+			ref := fields >> 9
+			leading := chimpLeadingCodes[(fields>>6)&0x7]
+			significant := int(fields & 0x3F)
+			if significant == 0 || leading+significant > 64 {
+				return fail(index, fmt.Errorf("invalid significant bits %d with %d leading zeros", significant, leading))
+			}
+			xor, err := r.ReadBits(significant)
+			if err != nil {
+				return fail(index, err)
+			}
+			v = stored[ref] ^ xor<<uint(64-leading-significant)
+			storedLeading = chimpNoLeading
+		case 2:
+			if storedLeading == chimpNoLeading {
+				return fail(index, fmt.Errorf("missing leading zeros"))
+			}
+			xor, err := r.ReadBits(64 - storedLeading)
+			if err != nil {
+				return fail(index, err)
+			}
+			v = prev ^ xor
+		default:
+			code, err := r.ReadBits(3)
+			if err != nil {
+				return fail(index, err)
+			}
+			storedLeading = chimpLeadingCodes[code]
+			xor, err := r.ReadBits(64 - storedLeading)
+			if err != nil {
+				return fail(index, err)
+			}
+			v = prev ^ xor
+		}
+		stored[index%chimpPreviousValues] = v
+		dst = append(dst, math.Float64frombits(v))
+	}
+	return dst, nil
+}
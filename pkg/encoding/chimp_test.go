@@ -0,0 +1,98 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// float64Fixtures are the value sequences shared by the tests of the float encodings.
+func float64Fixtures() map[string][]float64 {
+	r := rand.New(rand.NewSource(1))
+	counter := make([]float64, 1000)
+	gauge := make([]float64, 1000)
+	percent := make([]float64, 1000)
+	repeated := make([]float64, 1000)
+	v := 36.6
+	for i := range counter {
+		counter[i] = float64(i * 60)
+		v += r.NormFloat64() * 0.01
+		gauge[i] = v
+		percent[i] = math.Round(r.Float64()*10000) / 100
+		repeated[i] = []float64{0.25, 0.5, 0.75}[r.Intn(3)]
+	}
+	return map[string][]float64{
+		"single":   {1.5},
+		"counter":  counter,
+		"gauge":    gauge,
+		"percent":  percent,
+		"repeated": repeated,
+		"special":  {math.NaN(), math.Inf(1), 1.23, math.Inf(-1), 0, math.Copysign(0, -1), math.MaxFloat64, math.SmallestNonzeroFloat64},
+		"mixed":    {1.23, 4.56, 7.89, 3.1415926, -2.5e-10, 1e300, 18.1, 100.4, 100.0, 33.333, 50.2},
+	}
+}
+
+func requireSameFloat64s(t *testing.T, expected, actual []float64) {
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		require.Equalf(t, math.Float64bits(expected[i]), math.Float64bits(actual[i]), "value %d: %v != %v", i, expected[i], actual[i])
+	}
+}
+
+func TestChimp128(t *testing.T) {
+	for name, values := range float64Fixtures() {
+		t.Run(name, func(t *testing.T) {
+			encoded := Float64ListToChimp128([]byte{0xff}, values)
+			assert.Equal(t, byte(0xff), encoded[0])
+			decoded, err := Chimp128ToFloat64List(nil, encoded[1:], len(values))
+			require.NoError(t, err)
+			requireSameFloat64s(t, values, decoded)
+		})
+	}
+}
+
+func TestChimp128ReusesPreviousValues(t *testing.T) {
+	values := make([]float64, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		values = append(values, []float64{0.123456789, 98.7654321, 5.5}[i%3])
+	}
+	chimp := Float64ListToChimp128(nil, values)
+	gorilla := Float64ListToGorilla(nil, values)
+	assert.Less(t, len(chimp), len(gorilla))
+}
+
+func TestChimp128ToFloat64ListTruncated(t *testing.T) {
+	values := float64Fixtures()["gauge"]
+	encoded := Float64ListToChimp128(nil, values)
+	_, err := Chimp128ToFloat64List(nil, encoded[:len(encoded)/2], len(values))
+	assert.Error(t, err)
+}
+
+func TestChimpLeadingRound(t *testing.T) {
+	assert.Equal(t, 0, chimpLeadingRound[7])
+	assert.Equal(t, 8, chimpLeadingRound[11])
+	assert.Equal(t, 12, chimpLeadingRound[15])
+	assert.Equal(t, 16, chimpLeadingRound[17])
+	assert.Equal(t, 22, chimpLeadingRound[23])
+	assert.Equal(t, 24, chimpLeadingRound[64])
+}
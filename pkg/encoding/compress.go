@@ -0,0 +1,97 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"fmt"
+
+	"github.com/apache/skywalking-banyandb/pkg/compress/lz4"
+	"github.com/apache/skywalking-banyandb/pkg/compress/snappy"
+	"github.com/apache/skywalking-banyandb/pkg/compress/zstd"
+)
+
+// CompressType indicates the compressor applied to a whole encoded block.
+// The values are aligned with the CompressionMethod declared in the field specification.
+type CompressType byte
+
+// CompressType constants.
+const (
+	// CompressTypeDefault leaves the compression to the encoding of the block.
+	CompressTypeDefault CompressType = iota
+	CompressTypeZSTD
+	CompressTypeLZ4
+	CompressTypeSnappy
+	// CompressTypeNone disables any compression, which suits the values compressed by clients.
+	CompressTypeNone
+)
+
+// String returns the name of the compress type.
+func (ct CompressType) String() string {
+	switch ct {
+	case CompressTypeDefault:
+		return "default"
+	case CompressTypeZSTD:
+		return "zstd"
+	case CompressTypeLZ4:
+		return "lz4"
+	case CompressTypeSnappy:
+		return "snappy"
+	case CompressTypeNone:
+		return "none"
+	default:
+		return fmt.Sprintf("unknown(%d)", ct)
+	}
+}
+
+// IsBlockCompressed reports whether the compress type compresses the whole block,
+// in which case the values inside the block are left uncompressed.
+func (ct CompressType) IsBlockCompressed() bool {
+	return ct == CompressTypeZSTD || ct == CompressTypeLZ4 || ct == CompressTypeSnappy
+}
+
+// CompressBlockWith compresses src with the compress type and appends the result to dst.
+func CompressBlockWith(dst, src []byte, ct CompressType) ([]byte, error) {
+	switch ct {
+	case CompressTypeDefault, CompressTypeNone:
+		return append(dst, src...), nil
+	case CompressTypeZSTD:
+		return zstd.Compress(dst, src, 1), nil
+	case CompressTypeLZ4:
+		return lz4.Compress(dst, src), nil
+	case CompressTypeSnappy:
+		return snappy.Compress(dst, src), nil
+	default:
+		return dst, fmt.Errorf("unknown compress type: %s", ct)
+	}
+}
+
+// DecompressBlockWith decompresses src with the compress type and appends the result to dst.
+func DecompressBlockWith(dst, src []byte, ct CompressType) ([]byte, error) {
+	switch ct {
+	case CompressTypeDefault, CompressTypeNone:
+		return append(dst, src...), nil
+	case CompressTypeZSTD:
+		return zstd.Decompress(dst, src)
+	case CompressTypeLZ4:
+		return lz4.Decompress(dst, src)
+	case CompressTypeSnappy:
+		return snappy.Decompress(dst, src)
+	default:
+		return dst, fmt.Errorf("unknown compress type: %s", ct)
+	}
+}
@@ -0,0 +1,57 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
+)
+
+func TestCompressBlockWith(t *testing.T) {
+	src := bytes.Repeat([]byte("service_resp_time"), 100)
+	for _, ct := range []encoding.CompressType{
+		encoding.CompressTypeDefault,
+		encoding.CompressTypeZSTD,
+		encoding.CompressTypeLZ4,
+		encoding.CompressTypeSnappy,
+		encoding.CompressTypeNone,
+	} {
+		t.Run(ct.String(), func(t *testing.T) {
+			compressed, err := encoding.CompressBlockWith([]byte("prefix"), src, ct)
+			require.NoError(t, err)
+			require.Equal(t, []byte("prefix"), compressed[:6])
+			if ct.IsBlockCompressed() {
+				require.Less(t, len(compressed), len(src))
+			}
+			decompressed, err := encoding.DecompressBlockWith(nil, compressed[6:], ct)
+			require.NoError(t, err)
+			require.Equal(t, src, decompressed)
+		})
+	}
+}
+
+func TestCompressBlockWithUnknownType(t *testing.T) {
+	_, err := encoding.CompressBlockWith(nil, []byte("a"), encoding.CompressType(99))
+	require.Error(t, err)
+	_, err = encoding.DecompressBlockWith(nil, []byte("a"), encoding.CompressType(99))
+	require.Error(t, err)
+}
@@ -78,8 +78,17 @@ func valuesEqual(a, b []byte) bool {
 
 // Encode encodes the dictionary.
 func (d *Dictionary) Encode(dst []byte) []byte {
+	return d.encode(dst, EncodeBytesBlock)
+}
+
+// EncodeUncompressed encodes the dictionary without compressing its values.
+func (d *Dictionary) EncodeUncompressed(dst []byte) []byte {
+	return d.encode(dst, EncodeBytesBlockUncompressed)
+}
+
+func (d *Dictionary) encode(dst []byte, encodeValues func(dst []byte, a [][]byte) []byte) []byte {
 	dst = VarUint64ToBytes(dst, uint64(len(d.values)))
-	dst = EncodeBytesBlock(dst, d.values)
+	dst = encodeValues(dst, d.values)
 	re := encodeRLE(d.tmp, d.indices)
 	be := encodeBitPacking(re)
 	dst = append(dst, be...)
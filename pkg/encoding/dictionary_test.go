@@ -18,6 +18,7 @@
 package encoding
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -70,6 +71,25 @@ func TestEncodeAndDecodeDictionary(t *testing.T) {
 	require.Equal(t, values, decoded)
 }
 
+func TestEncodeUncompressedAndDecodeDictionary(t *testing.T) {
+	dict := NewDictionary()
+	values := [][]byte{
+		bytes.Repeat([]byte("a"), 200),
+		bytes.Repeat([]byte("b"), 200),
+		bytes.Repeat([]byte("a"), 200),
+	}
+	for _, value := range values {
+		dict.Add(value)
+	}
+
+	encoded := dict.EncodeUncompressed(nil)
+	require.Greater(t, len(encoded), 400)
+	dict.Reset()
+	decoded, err := dict.Decode(nil, encoded, 3)
+	require.NoError(t, err)
+	require.Equal(t, values, decoded)
+}
+
 func TestEncodeAndDecodeDictionaryEdgeCases(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -95,6 +95,18 @@ const (
 	EncodeTypeDeltaOfDeltaWithVersion
 	EncodeTypePlain
 	EncodeTypeDictionary
+	EncodeTypeHistogram
+	EncodeTypeGorilla
+	EncodeTypeChimp128
+	EncodeTypeALP
+	// EncodeTypeDecimal scales float values into integers by a decimal exponent.
+	// Its encoded bytes start with the EncodeType of the integers instead of itself.
+	EncodeTypeDecimal
+	EncodeTypeFSST
+	EncodeTypeFrameOfReference
+	EncodeTypeRunLength
+	EncodeTypeFrameOfReferenceWithVersion
+	EncodeTypeRunLengthWithVersion
 )
 
 // GetVersionType returns the version type of the given encoding type.
@@ -108,6 +120,10 @@ func GetVersionType(et EncodeType) EncodeType {
 		return EncodeTypeDeltaWithVersion
 	case EncodeTypeDeltaOfDelta:
 		return EncodeTypeDeltaOfDeltaWithVersion
+	case EncodeTypeFrameOfReference:
+		return EncodeTypeFrameOfReferenceWithVersion
+	case EncodeTypeRunLength:
+		return EncodeTypeRunLengthWithVersion
 	default:
 		return EncodeTypeUnknown
 	}
@@ -124,6 +140,10 @@ func GetCommonType(et EncodeType) EncodeType {
 		return EncodeTypeDelta
 	case EncodeTypeDeltaOfDeltaWithVersion:
 		return EncodeTypeDeltaOfDelta
+	case EncodeTypeFrameOfReferenceWithVersion:
+		return EncodeTypeFrameOfReference
+	case EncodeTypeRunLengthWithVersion:
+		return EncodeTypeRunLength
 	default:
 		return EncodeTypeUnknown
 	}
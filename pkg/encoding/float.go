@@ -18,9 +18,12 @@
 package encoding
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 
+	pkgbytes "github.com/apache/skywalking-banyandb/pkg/bytes"
+	"github.com/apache/skywalking-banyandb/pkg/convert"
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 )
 
@@ -86,3 +89,145 @@ func countDecimalPlaces(f float64, maxPlace int) int16 {
 	}
 	return int16(maxPlace)
 }
+
+// Float64ListToGorilla encodes float64 values with the XOR compression of the Gorilla paper.
+// Unlike the decimal conversion, it is lossless for any float64 including NaN and Inf.
+func Float64ListToGorilla(dst []byte, nums []float64) []byte {
+	bb := &pkgbytes.Buffer{Buf: dst}
+	w := NewWriter()
+	w.Reset(bb)
+	e := NewXOREncoder(w)
+	for _, f := range nums {
+		e.Write(math.Float64bits(f))
+	}
+	w.Flush()
+	return bb.Buf
+}
+
+// GorillaToFloat64List decodes float64 values encoded by Float64ListToGorilla.
+func GorillaToFloat64List(dst []float64, src []byte, itemsCount int) ([]float64, error) {
+	dst = ExtendListCapacity(dst, itemsCount)
+	d := NewXORDecoder(NewReader(bytes.NewReader(src)))
+	for i := 0; i < itemsCount; i++ {
+		if !d.Next() {
+			return nil, fmt.Errorf("cannot decode gorilla value %d of %d: %w", i, itemsCount, d.Err())
+		}
+		dst = append(dst, math.Float64frombits(d.Value()))
+	}
+	return dst, nil
+}
+
+const (
+	float64SampleChunkSize = 64
+	float64SampleChunks    = 4
+)
+
+// float64Candidates are the encodings Float64ListToBytes tries.
+// The former wins a tie, which keeps the decimal conversion for the values it suits.
+var float64Candidates = []EncodeType{EncodeTypeDecimal, EncodeTypeALP, EncodeTypeChimp128, EncodeTypeGorilla}
+
+// Float64ListToBytes encodes float64 values with the encoding producing the smallest output on a sample of them.
+// The encoded bytes start with the chosen EncodeType, except for EncodeTypeDecimal.
+func Float64ListToBytes(dst []byte, a []float64) ([]byte, EncodeType) {
+	et := chooseFloat64EncodeType(a)
+	result, err := EncodeFloat64List(dst, a, et)
+	if err == nil {
+		return result, et
+	}
+	// the decimal conversion fails on the values out of the sample, which ALP stores as exceptions
+	result, _ = EncodeFloat64List(dst, a, EncodeTypeALP)
+	return result, EncodeTypeALP
+}
+
+// EncodeFloat64List encodes float64 values with the given encoding.
+// The encoded bytes start with the EncodeType, except for EncodeTypeDecimal.
+func EncodeFloat64List(dst []byte, a []float64, et EncodeType) ([]byte, error) {
+	switch et {
+	case EncodeTypeGorilla:
+		return Float64ListToGorilla(append(dst, byte(et)), a), nil
+	case EncodeTypeChimp128:
+		return Float64ListToChimp128(append(dst, byte(et)), a), nil
+	case EncodeTypeALP:
+		return Float64ListToALP(append(dst, byte(et)), a), nil
+	case EncodeTypeDecimal:
+		intValues := GenerateInt64List(0)
+		defer ReleaseInt64List(intValues)
+		var exp int16
+		var err error
+		intValues.L, exp, err = Float64ListToDecimalIntList(intValues.L[:0], a)
+		if err != nil {
+			return dst, err
+		}
+		bb := bbPool.Generate()
+		defer bbPool.Release(bb)
+		var intEncodeType EncodeType
+		var firstValue int64
+		bb.Buf, intEncodeType, firstValue = Int64ListToBytes(bb.Buf[:0], intValues.L)
+		dst = append(dst, byte(intEncodeType))
+		dst = append(dst, convert.Int16ToBytes(exp)...)
+		dst = append(dst, convert.Int64ToBytes(firstValue)...)
+		return append(dst, bb.Buf...), nil
+	default:
+		return dst, fmt.Errorf("unsupported float encode type: %d", et)
+	}
+}
+
+// BytesToFloat64List decodes float64 values encoded by EncodeFloat64List.
+func BytesToFloat64List(dst []float64, src []byte, itemsCount int) ([]float64, error) {
+	if len(src) < 1 {
+		return nil, fmt.Errorf("cannot decode float encode type from empty src")
+	}
+	switch et := EncodeType(src[0]); et {
+	case EncodeTypeGorilla:
+		return GorillaToFloat64List(dst, src[1:], itemsCount)
+	case EncodeTypeChimp128:
+		return Chimp128ToFloat64List(dst, src[1:], itemsCount)
+	case EncodeTypeALP:
+		return ALPToFloat64List(dst, src[1:], itemsCount)
+	case EncodeTypeConst, EncodeTypeDeltaConst, EncodeTypeDelta, EncodeTypeDeltaOfDelta, EncodeTypeFrameOfReference, EncodeTypeRunLength:
+		const headerLen = 11
+		if len(src) < headerLen {
+			return nil, fmt.Errorf("src length too short: expect at least %d bytes, but got %d bytes", headerLen, len(src))
+		}
+		exp := convert.BytesToInt16(src[1:3])
+		firstValue := convert.BytesToInt64(src[3:11])
+		intValues := GenerateInt64List(0)
+		defer ReleaseInt64List(intValues)
+		var err error
+		intValues.L, err = BytesToInt64List(intValues.L[:0], src[headerLen:], et, firstValue, itemsCount)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode decimal int values: %w", err)
+		}
+		return DecimalIntListToFloat64List(dst, intValues.L, exp, itemsCount)
+	default:
+		return nil, fmt.Errorf("unsupported float encode type: %d", et)
+	}
+}
+
+func chooseFloat64EncodeType(a []float64) EncodeType {
+	var sampleBuf [float64SampleChunkSize * float64SampleChunks]float64
+	sample := a
+	if len(a) > len(sampleBuf) {
+		// the XOR encodings depend on the adjacent values, so the sample takes contiguous chunks
+		sample = sampleBuf[:0]
+		for i := 0; i < float64SampleChunks; i++ {
+			start := i * (len(a) - float64SampleChunkSize) / (float64SampleChunks - 1)
+			sample = append(sample, a[start:start+float64SampleChunkSize]...)
+		}
+	}
+
+	bb := bbPool.Generate()
+	defer bbPool.Release(bb)
+	best, bestSize := EncodeTypeALP, math.MaxInt
+	for _, et := range float64Candidates {
+		var err error
+		bb.Buf, err = EncodeFloat64List(bb.Buf[:0], sample, et)
+		if err != nil {
+			continue
+		}
+		if len(bb.Buf) < bestSize {
+			best, bestSize = et, len(bb.Buf)
+		}
+	}
+	return best
+}
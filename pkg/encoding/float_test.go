@@ -18,7 +18,9 @@
 package encoding
 
 import (
+	"fmt"
 	"math"
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -114,3 +116,177 @@ func TestEmptyInputPanics(t *testing.T) {
 		_, _, _ = Float64ListToDecimalIntList([]int64{}, []float64{})
 	})
 }
+
+func TestFloat64ListToGorilla(t *testing.T) {
+	cases := []struct {
+		name  string
+		input []float64
+	}{
+		{
+			name:  "Single value",
+			input: []float64{1.5},
+		},
+		{
+			name:  "Repeated values",
+			input: []float64{0.95, 0.95, 0.95, 0.95},
+		},
+		{
+			name:  "Normal float values",
+			input: []float64{1.23, 4.56, 7.89, 3.1415926, -2.5e-10, 1e300},
+		},
+		{
+			name:  "Special values",
+			input: []float64{math.NaN(), math.Inf(1), math.Inf(-1), 0, math.Copysign(0, -1)},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := Float64ListToGorilla([]byte{0xff}, tc.input)
+			assert.Equal(t, byte(0xff), encoded[0])
+			decoded, err := GorillaToFloat64List(nil, encoded[1:], len(tc.input))
+			assert.NoError(t, err)
+			assert.Len(t, decoded, len(tc.input))
+			for i := range tc.input {
+				assert.Equal(t, math.Float64bits(tc.input[i]), math.Float64bits(decoded[i]))
+			}
+		})
+	}
+}
+
+func TestGorillaToFloat64ListTruncated(t *testing.T) {
+	encoded := Float64ListToGorilla(nil, []float64{1.23, 4.56, 7.89})
+	_, err := GorillaToFloat64List(nil, encoded[:4], 3)
+	assert.Error(t, err)
+}
+
+func TestFloat64ListToBytes(t *testing.T) {
+	for name, values := range float64Fixtures() {
+		t.Run(name, func(t *testing.T) {
+			encoded, et := Float64ListToBytes(nil, values)
+			assert.Contains(t, float64Candidates, et)
+			decoded, err := BytesToFloat64List(nil, encoded, len(values))
+			assert.NoError(t, err)
+			if et == EncodeTypeDecimal {
+				// the decimal conversion keeps the values up to 15 decimal places
+				assert.InDeltaSlice(t, values, decoded, 1e-9)
+				return
+			}
+			requireSameFloat64s(t, values, decoded)
+		})
+	}
+}
+
+func TestFloat64ListToBytesChoosesSmallest(t *testing.T) {
+	fixtures := float64Fixtures()
+	for _, name := range []string{"counter", "gauge", "percent", "repeated"} {
+		values := fixtures[name]
+		encoded, et := Float64ListToBytes(nil, values)
+		for _, candidate := range float64Candidates {
+			other, err := EncodeFloat64List(nil, values, candidate)
+			if err != nil {
+				continue
+			}
+			// the choice is made on a sample, so it is allowed to be a bit larger than the best one
+			assert.LessOrEqualf(t, len(encoded), len(other)*11/10+16, "%s: %d chosen, %d with %d", name, len(encoded), len(other), candidate)
+		}
+		t.Logf("%s: %d bytes with the encode type %d", name, len(encoded), et)
+	}
+}
+
+func TestFloat64ListToBytesFallsBackToALP(t *testing.T) {
+	values := make([]float64, 1000)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	// out of the sample, the decimal conversion fails on NaN
+	values[100] = math.NaN()
+	encoded, et := Float64ListToBytes(nil, values)
+	assert.NotEqual(t, EncodeTypeDecimal, et)
+	decoded, err := BytesToFloat64List(nil, encoded, len(values))
+	assert.NoError(t, err)
+	requireSameFloat64s(t, values, decoded)
+}
+
+func TestBytesToFloat64ListUnsupported(t *testing.T) {
+	_, err := BytesToFloat64List(nil, []byte{byte(EncodeTypePlain)}, 1)
+	assert.Error(t, err)
+	_, err = BytesToFloat64List(nil, nil, 1)
+	assert.Error(t, err)
+}
+
+// float64BenchmarkFixtures are the float values of the existing tests of the decimal, XOR and measure encodings,
+// so the float encodings are benchmarked on the same data as the encodings they replace.
+func float64BenchmarkFixtures() map[string][]float64 {
+	// the patterns of BenchmarkFloat64Encoding in the measure package
+	const n = 10_000
+	r := rand.New(rand.NewSource(1))
+	incrementing := make([]float64, n)
+	smallFluctuations := make([]float64, n)
+	random := make([]float64, n)
+	val := 25.0
+	for i := 0; i < n; i++ {
+		incrementing[i] = 1.0 + float64(i)
+		val += (r.Float64() - 0.5) * 0.2
+		smallFluctuations[i] = val
+		random[i] = r.Float64() * 100
+	}
+	return map[string][]float64{
+		"decimal_int":                {1.0, 2.0, 3.0},
+		"decimal_normal":             {1.23, 4.56, 7.89, 1.4999, 1.5001},
+		"decimal_mixed_precision":    {0.1, 0.12, 0.123},
+		"decimal_places":             {1.000_000_000_000_001, 2.100_000_000_000_002, 3.1},
+		"decimal_unsupported":        {math.NaN(), 1.23, math.Inf(1), math.MaxFloat64},
+		"xor":                        {76, 50, 50, 999999999, 100},
+		"measure_field":              {1221233.343, 2442466.686, 3663699.029},
+		"measure_const":              make([]float64, n),
+		"measure_incrementing":       incrementing,
+		"measure_small_fluctuations": smallFluctuations,
+		"measure_random":             random,
+	}
+}
+
+func BenchmarkEncodeFloat64List(b *testing.B) {
+	for name, values := range float64BenchmarkFixtures() {
+		for _, et := range float64Candidates {
+			encoded, err := EncodeFloat64List(nil, values, et)
+			if err != nil {
+				continue
+			}
+			b.Run(fmt.Sprintf("%s_type=%d", name, et), func(b *testing.B) {
+				b.ReportAllocs()
+				b.ReportMetric(float64(len(encoded))*8/float64(len(values)), "bits/value")
+				for i := 0; i < b.N; i++ {
+					encoded, _ = EncodeFloat64List(encoded[:0], values, et)
+				}
+			})
+		}
+		b.Run(name+"_adaptive", func(b *testing.B) {
+			b.ReportAllocs()
+			encoded, _ := Float64ListToBytes(nil, values)
+			b.ReportMetric(float64(len(encoded))*8/float64(len(values)), "bits/value")
+			for i := 0; i < b.N; i++ {
+				encoded, _ = Float64ListToBytes(encoded[:0], values)
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeFloat64List(b *testing.B) {
+	for name, values := range float64BenchmarkFixtures() {
+		for _, et := range float64Candidates {
+			encoded, err := EncodeFloat64List(nil, values, et)
+			if err != nil {
+				continue
+			}
+			b.Run(fmt.Sprintf("%s_type=%d", name, et), func(b *testing.B) {
+				b.ReportAllocs()
+				decoded := make([]float64, 0, len(values))
+				for i := 0; i < b.N; i++ {
+					if decoded, err = BytesToFloat64List(decoded[:0], encoded, len(values)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,375 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+const (
+	fsstMaxSymbols      = 255
+	fsstMaxSymbolLength = 8
+	// fsstEscape is followed by a literal byte which isn't covered by any symbol.
+	fsstEscape      = 255
+	fsstTrainRounds = 5
+	// fsstSampleSize caps the bytes the symbol table is trained on.
+	fsstSampleSize = 8 << 10
+)
+
+var errFSSTCorrupted = errors.New("corrupted fsst data")
+
+// EncodeFSST compresses values with a symbol table trained on them, and appends the result to dst.
+// https://www.vldb.org/pvldb/vol13/p2649-boncz.pdf
+//
+// FSST replaces the frequent substrings of up to 8 bytes by 1-byte codes.
+// Unlike the block compression, each value is compressed on its own,
+// so FSSTDecoder can decompress any value without touching the others.
+func EncodeFSST(dst []byte, a [][]byte) []byte {
+	t := trainFSST(a)
+	dst = t.marshal(dst)
+
+	u64List := GenerateUint64List(len(a))
+	defer ReleaseUint64List(u64List)
+	bb := bbPool.Generate()
+	defer bbPool.Release(bb)
+	lens := u64List.L
+	for i, v := range a {
+		if v == nil {
+			lens[i] = 0
+			continue
+		}
+		start := len(bb.Buf)
+		bb.Buf = t.compress(bb.Buf, v)
+		// Shift the length by one to tell the empty values from the null ones.
+		lens[i] = uint64(len(bb.Buf)-start) + 1
+	}
+	dst = EncodeUint64Block(dst, lens)
+	dst = VarUint64ToBytes(dst, uint64(len(bb.Buf)))
+	return append(dst, bb.Buf...)
+}
+
+// FSSTDecoder decodes the values encoded by EncodeFSST.
+type FSSTDecoder struct {
+	symbols   [][]byte
+	symbolBuf []byte
+	lens      []uint64
+	offsets   []uint64
+	data      []byte
+	ends      []int
+}
+
+// Reset resets the decoder.
+func (d *FSSTDecoder) Reset() {
+	d.symbols = d.symbols[:0]
+	d.symbolBuf = d.symbolBuf[:0]
+	d.lens = d.lens[:0]
+	d.offsets = d.offsets[:0]
+	d.data = nil
+	d.ends = d.ends[:0]
+}
+
+// Init prepares the decoder to decompress itemsCount values from src.
+// The decoder refers to src until it is reset.
+func (d *FSSTDecoder) Init(src []byte, itemsCount uint64) error {
+	d.Reset()
+	src, count := BytesToVarUint64(src)
+	if count > fsstMaxSymbols || uint64(len(src)) < count {
+		return fmt.Errorf("cannot decode %d fsst symbols: %w", count, errFSSTCorrupted)
+	}
+	symbolLens := src[:count]
+	src = src[count:]
+	for _, n := range symbolLens {
+		if n == 0 || n > fsstMaxSymbolLength || len(src) < int(n) {
+			return fmt.Errorf("cannot decode a fsst symbol of %d bytes: %w", n, errFSSTCorrupted)
+		}
+		d.symbolBuf = append(d.symbolBuf, src[:n]...)
+		src = src[n:]
+	}
+	var start int
+	for _, n := range symbolLens {
+		d.symbols = append(d.symbols, d.symbolBuf[start:start+int(n)])
+		start += int(n)
+	}
+
+	var err error
+	d.lens, src, err = DecodeUint64Block(d.lens, src, itemsCount)
+	if err != nil {
+		return fmt.Errorf("cannot decode fsst value lengths: %w", err)
+	}
+	src, dataLen := BytesToVarUint64(src)
+	if uint64(len(src)) != dataLen {
+		return fmt.Errorf("unexpected fsst data length; got %d bytes; want %d bytes: %w", len(src), dataLen, errFSSTCorrupted)
+	}
+	var offset uint64
+	for _, n := range d.lens {
+		d.offsets = append(d.offsets, offset)
+		if n > 0 {
+			offset += n - 1
+		}
+	}
+	if offset != dataLen {
+		return fmt.Errorf("fsst value lengths sum up to %d bytes; want %d bytes: %w", offset, dataLen, errFSSTCorrupted)
+	}
+	d.data = src
+	return nil
+}
+
+// Len returns the number of values.
+func (d *FSSTDecoder) Len() int {
+	return len(d.lens)
+}
+
+// IsNull reports whether the i-th value is null.
+func (d *FSSTDecoder) IsNull(i int) bool {
+	return d.lens[i] == 0
+}
+
+// AppendValue decompresses the i-th value and appends it to dst.
+func (d *FSSTDecoder) AppendValue(dst []byte, i int) ([]byte, error) {
+	if d.lens[i] == 0 {
+		return dst, nil
+	}
+	start := d.offsets[i]
+	return d.decompress(dst, d.data[start:start+d.lens[i]-1])
+}
+
+// Decode decompresses all values and appends them to dst.
+// The null values are decoded as nil, and the empty ones as non-nil empty slices.
+func (d *FSSTDecoder) Decode(dst [][]byte) ([][]byte, error) {
+	buf := make([]byte, 0, 2*len(d.data))
+	d.ends = d.ends[:0]
+	var err error
+	for i := range d.lens {
+		if buf, err = d.AppendValue(buf, i); err != nil {
+			return dst, err
+		}
+		d.ends = append(d.ends, len(buf))
+	}
+	var start int
+	for i, end := range d.ends {
+		if d.lens[i] == 0 {
+			dst = append(dst, nil)
+			continue
+		}
+		dst = append(dst, buf[start:end:end])
+		start = end
+	}
+	return dst, nil
+}
+
+func (d *FSSTDecoder) decompress(dst, src []byte) ([]byte, error) {
+	for i := 0; i < len(src); i++ {
+		code := src[i]
+		if code == fsstEscape {
+			i++
+			if i == len(src) {
+				return dst, fmt.Errorf("missing the escaped byte: %w", errFSSTCorrupted)
+			}
+			dst = append(dst, src[i])
+			continue
+		}
+		if int(code) >= len(d.symbols) {
+			return dst, fmt.Errorf("unknown fsst code %d: %w", code, errFSSTCorrupted)
+		}
+		dst = append(dst, d.symbols[code]...)
+	}
+	return dst, nil
+}
+
+// fsstTable maps the codes to the symbols.
+type fsstTable struct {
+	symbols [][]byte
+	packed  []fsstSymbol
+	// byFirst lists the codes of the symbols starting with a byte, the longest first.
+	byFirst [256][]byte
+}
+
+func newFSSTTable(symbols [][]byte) *fsstTable {
+	t := &fsstTable{symbols: symbols}
+	for code, sym := range symbols {
+		t.packed = append(t.packed, newFSSTSymbol(sym))
+		t.byFirst[sym[0]] = append(t.byFirst[sym[0]], byte(code))
+	}
+	for i := range t.byFirst {
+		codes := t.byFirst[i]
+		sort.SliceStable(codes, func(a, b int) bool {
+			return len(symbols[codes[a]]) > len(symbols[codes[b]])
+		})
+	}
+	return t
+}
+
+// find returns the code of the longest symbol prefixing s, and the length of the symbol.
+// The length is 0 if no symbol matches.
+func (t *fsstTable) find(s []byte) (byte, int) {
+	var word uint64
+	if len(s) >= fsstMaxSymbolLength {
+		word = binary.LittleEndian.Uint64(s)
+	} else {
+		word = newFSSTSymbol(s).value
+	}
+	for _, code := range t.byFirst[s[0]] {
+		sym := t.packed[code]
+		if sym.length <= len(s) && word&sym.mask() == sym.value {
+			return code, sym.length
+		}
+	}
+	return 0, 0
+}
+
+func (t *fsstTable) compress(dst, src []byte) []byte {
+	for len(src) > 0 {
+		code, n := t.find(src)
+		if n == 0 {
+			dst = append(dst, fsstEscape, src[0])
+			src = src[1:]
+			continue
+		}
+		dst = append(dst, code)
+		src = src[n:]
+	}
+	return dst
+}
+
+func (t *fsstTable) marshal(dst []byte) []byte {
+	dst = VarUint64ToBytes(dst, uint64(len(t.symbols)))
+	for _, sym := range t.symbols {
+		dst = append(dst, byte(len(sym)))
+	}
+	for _, sym := range t.symbols {
+		dst = append(dst, sym...)
+	}
+	return dst
+}
+
+// trainFSST builds the symbol table bottom-up. Each round compresses the sample with the current table,
+// then keeps the symbols and the concatenations of adjacent symbols saving the most bytes.
+func trainFSST(a [][]byte) *fsstTable {
+	sample := fsstSample(a)
+	t := newFSSTTable(nil)
+	gains := make(map[fsstSymbol]int, fsstSampleSize)
+	for round := 0; round < fsstTrainRounds; round++ {
+		clear(gains)
+		for _, v := range sample {
+			var prev []byte
+			for len(v) > 0 {
+				_, n := t.find(v)
+				if n == 0 {
+					n = 1
+				}
+				sym := v[:n]
+				gains[newFSSTSymbol(sym)] += n
+				if len(prev) > 0 && len(prev)+n <= fsstMaxSymbolLength {
+					// prev and sym are adjacent in v.
+					pair := prev[:len(prev)+n]
+					gains[newFSSTSymbol(pair)] += len(pair)
+				}
+				prev = sym
+				v = v[n:]
+			}
+		}
+		t = newFSSTTable(fsstTopSymbols(gains))
+	}
+	return t
+}
+
+// fsstSymbol packs a symbol into a comparable value.
+type fsstSymbol struct {
+	value  uint64
+	length int
+}
+
+func newFSSTSymbol(b []byte) fsstSymbol {
+	var v uint64
+	for i, c := range b {
+		v |= uint64(c) << (8 * i)
+	}
+	return fsstSymbol{value: v, length: len(b)}
+}
+
+func (s fsstSymbol) mask() uint64 {
+	if s.length == fsstMaxSymbolLength {
+		return math.MaxUint64
+	}
+	return 1<<(8*s.length) - 1
+}
+
+func (s fsstSymbol) bytes() []byte {
+	b := make([]byte, s.length)
+	for i := range b {
+		b[i] = byte(s.value >> (8 * i))
+	}
+	return b
+}
+
+func fsstTopSymbols(gains map[fsstSymbol]int) [][]byte {
+	type candidate struct {
+		sym  fsstSymbol
+		gain int
+	}
+	candidates := make([]candidate, 0, len(gains))
+	for sym, gain := range gains {
+		candidates = append(candidates, candidate{sym: sym, gain: gain})
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.gain != b.gain {
+			return cmp.Compare(b.gain, a.gain)
+		}
+		if a.sym.length != b.sym.length {
+			return cmp.Compare(b.sym.length, a.sym.length)
+		}
+		return cmp.Compare(a.sym.value, b.sym.value)
+	})
+	if len(candidates) > fsstMaxSymbols {
+		candidates = candidates[:fsstMaxSymbols]
+	}
+	symbols := make([][]byte, len(candidates))
+	for i, c := range candidates {
+		symbols[i] = c.sym.bytes()
+	}
+	return symbols
+}
+
+// fsstSample picks evenly spaced values until fsstSampleSize bytes are collected.
+func fsstSample(a [][]byte) [][]byte {
+	var total int
+	for _, v := range a {
+		total += len(v)
+	}
+	step := 1
+	if total > fsstSampleSize {
+		step = (total + fsstSampleSize - 1) / fsstSampleSize
+	}
+	var sample [][]byte
+	var size int
+	for i := 0; i < len(a) && size < fsstSampleSize; i += step {
+		v := a[i]
+		if len(v) > fsstSampleSize-size {
+			v = v[:fsstSampleSize-size]
+		}
+		sample = append(sample, v)
+		size += len(v)
+	}
+	return sample
+}
@@ -0,0 +1,109 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fsstFixture() [][]byte {
+	endpoints := []string{"/api/v1/orders", "/api/v1/users/profile", "/api/v2/payments/checkout", "/health"}
+	values := make([][]byte, 0, 2000)
+	for i := 0; i < cap(values); i++ {
+		values = append(values, []byte(fmt.Sprintf("https://shop.example.com%s?id=%d&trace=%x", endpoints[i%len(endpoints)], i*7919, i*104729)))
+	}
+	return values
+}
+
+func TestFSSTRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		values [][]byte
+	}{
+		{name: "urls", values: fsstFixture()},
+		{name: "with null and empty", values: [][]byte{[]byte("select * from t"), nil, {}, []byte("select * from t where id = 1"), nil}},
+		{name: "all null", values: [][]byte{nil, nil}},
+		{name: "all bytes", values: [][]byte{{0, 1, 2, 255, 254}, {255, 255}}},
+		{name: "empty", values: [][]byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix := []byte("prefix")
+			encoded := EncodeFSST(append([]byte{}, prefix...), tt.values)
+			require.Equal(t, prefix, encoded[:len(prefix)])
+
+			var decoder FSSTDecoder
+			require.NoError(t, decoder.Init(encoded[len(prefix):], uint64(len(tt.values))))
+			require.Equal(t, len(tt.values), decoder.Len())
+			decoded, err := decoder.Decode(nil)
+			require.NoError(t, err)
+			require.Len(t, decoded, len(tt.values))
+			for i, v := range tt.values {
+				assert.Equal(t, v == nil, decoded[i] == nil, "value %d", i)
+				assert.Equal(t, string(v), string(decoded[i]), "value %d", i)
+			}
+		})
+	}
+}
+
+func TestFSSTRandomAccess(t *testing.T) {
+	values := fsstFixture()
+	values[10] = nil
+	encoded := EncodeFSST(nil, values)
+
+	var decoder FSSTDecoder
+	require.NoError(t, decoder.Init(encoded, uint64(len(values))))
+	for _, i := range []int{1999, 0, 10, 777} {
+		v, err := decoder.AppendValue(nil, i)
+		require.NoError(t, err)
+		assert.Equal(t, values[i] == nil, decoder.IsNull(i))
+		assert.Equal(t, string(values[i]), string(v))
+	}
+}
+
+func TestFSSTCompressionRatio(t *testing.T) {
+	values := fsstFixture()
+	var size int
+	for _, v := range values {
+		size += len(v)
+	}
+	encoded := EncodeFSST(nil, values)
+	assert.Less(t, len(encoded), size/2)
+}
+
+func TestFSSTCorrupted(t *testing.T) {
+	values := fsstFixture()[:100]
+	encoded := EncodeFSST(nil, values)
+
+	var decoder FSSTDecoder
+	require.Error(t, decoder.Init(encoded[:len(encoded)-1], uint64(len(values))))
+	require.Error(t, decoder.Init(encoded, uint64(len(values)+1)))
+}
+
+func BenchmarkEncodeFSST(b *testing.B) {
+	values := fsstFixture()
+	b.ReportAllocs()
+	var dst []byte
+	for i := 0; i < b.N; i++ {
+		dst = EncodeFSST(dst[:0], values)
+	}
+}
@@ -0,0 +1,446 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrIncompatibleHistogram indicates the histograms can't be encoded or merged together.
+var ErrIncompatibleHistogram = errors.New("incompatible histograms")
+
+// HistogramKind is the kind of the buckets of a histogram.
+type HistogramKind byte
+
+// HistogramKind constants.
+const (
+	HistogramKindUnknown HistogramKind = iota
+	HistogramKindExplicit
+	HistogramKindExponential
+)
+
+// Histogram is a distribution of observations counted in buckets.
+//
+// An explicit histogram counts the observations in the buckets split by Bounds,
+// and Counts has one more element than Bounds for the +Inf bucket.
+// An exponential histogram counts them in the buckets whose bounds are powers of 2^(2^-Scale),
+// and the bucket of the index i covers (base^i, base^(i+1)].
+type Histogram struct {
+	Bounds         []float64
+	Counts         []uint64
+	PositiveCounts []uint64
+	NegativeCounts []uint64
+	Sum            float64
+	ZeroThreshold  float64
+	Count          uint64
+	ZeroCount      uint64
+	Scale          int32
+	PositiveOffset int32
+	NegativeOffset int32
+	Kind           HistogramKind
+}
+
+// Reset resets the histogram to be empty.
+func (h *Histogram) Reset() {
+	h.Bounds = h.Bounds[:0]
+	h.Counts = h.Counts[:0]
+	h.PositiveCounts = h.PositiveCounts[:0]
+	h.NegativeCounts = h.NegativeCounts[:0]
+	h.Sum = 0
+	h.ZeroThreshold = 0
+	h.Count = 0
+	h.ZeroCount = 0
+	h.Scale = 0
+	h.PositiveOffset = 0
+	h.NegativeOffset = 0
+	h.Kind = HistogramKindUnknown
+}
+
+// CopyFrom copies src to h.
+func (h *Histogram) CopyFrom(src *Histogram) {
+	h.Bounds = append(h.Bounds[:0], src.Bounds...)
+	h.Counts = append(h.Counts[:0], src.Counts...)
+	h.PositiveCounts = append(h.PositiveCounts[:0], src.PositiveCounts...)
+	h.NegativeCounts = append(h.NegativeCounts[:0], src.NegativeCounts...)
+	h.Sum = src.Sum
+	h.ZeroThreshold = src.ZeroThreshold
+	h.Count = src.Count
+	h.ZeroCount = src.ZeroCount
+	h.Scale = src.Scale
+	h.PositiveOffset = src.PositiveOffset
+	h.NegativeOffset = src.NegativeOffset
+	h.Kind = src.Kind
+}
+
+// Marshal appends the binary form of h to dst.
+func (h *Histogram) Marshal(dst []byte) []byte {
+	dst = append(dst, byte(h.Kind))
+	dst = VarUint64ToBytes(dst, h.Count)
+	dst = Uint64ToBytes(dst, math.Float64bits(h.Sum))
+	switch h.Kind {
+	case HistogramKindExplicit:
+		dst = VarUint64ToBytes(dst, uint64(len(h.Bounds)))
+		for _, b := range h.Bounds {
+			dst = Uint64ToBytes(dst, math.Float64bits(b))
+		}
+		dst = marshalHistogramCounts(dst, h.Counts)
+	case HistogramKindExponential:
+		dst = VarInt64ToBytes(dst, int64(h.Scale))
+		dst = Uint64ToBytes(dst, math.Float64bits(h.ZeroThreshold))
+		dst = VarUint64ToBytes(dst, h.ZeroCount)
+		dst = VarInt64ToBytes(dst, int64(h.PositiveOffset))
+		dst = marshalHistogramCounts(dst, h.PositiveCounts)
+		dst = VarInt64ToBytes(dst, int64(h.NegativeOffset))
+		dst = marshalHistogramCounts(dst, h.NegativeCounts)
+	}
+	return dst
+}
+
+// Unmarshal decodes h from src, which is produced by Marshal.
+func (h *Histogram) Unmarshal(src []byte) error {
+	h.Reset()
+	if len(src) < 1 {
+		return fmt.Errorf("cannot unmarshal histogram kind from empty src")
+	}
+	h.Kind = HistogramKind(src[0])
+	src = src[1:]
+	var n uint64
+	src, h.Count = BytesToVarUint64(src)
+	if len(src) < 8 {
+		return fmt.Errorf("cannot unmarshal histogram sum from %d bytes", len(src))
+	}
+	h.Sum = math.Float64frombits(BytesToUint64(src))
+	src = src[8:]
+	var err error
+	switch h.Kind {
+	case HistogramKindExplicit:
+		src, n = BytesToVarUint64(src)
+		if uint64(len(src)) < n*8 {
+			return fmt.Errorf("cannot unmarshal %d histogram bounds from %d bytes", n, len(src))
+		}
+		for i := uint64(0); i < n; i++ {
+			h.Bounds = append(h.Bounds, math.Float64frombits(BytesToUint64(src)))
+			src = src[8:]
+		}
+		if h.Counts, src, err = unmarshalHistogramCounts(h.Counts, src); err != nil {
+			return err
+		}
+		if len(h.Counts) != len(h.Bounds)+1 {
+			return fmt.Errorf("the histogram has %d buckets, but %d bounds", len(h.Counts), len(h.Bounds))
+		}
+	case HistogramKindExponential:
+		var v int64
+		if src, v, err = BytesToVarInt64(src); err != nil {
+			return fmt.Errorf("cannot unmarshal histogram scale: %w", err)
+		}
+		h.Scale = int32(v)
+		if len(src) < 8 {
+			return fmt.Errorf("cannot unmarshal histogram zero threshold from %d bytes", len(src))
+		}
+		h.ZeroThreshold = math.Float64frombits(BytesToUint64(src))
+		src, h.ZeroCount = BytesToVarUint64(src[8:])
+		if src, v, err = BytesToVarInt64(src); err != nil {
+			return fmt.Errorf("cannot unmarshal histogram positive offset: %w", err)
+		}
+		h.PositiveOffset = int32(v)
+		if h.PositiveCounts, src, err = unmarshalHistogramCounts(h.PositiveCounts, src); err != nil {
+			return err
+		}
+		if src, v, err = BytesToVarInt64(src); err != nil {
+			return fmt.Errorf("cannot unmarshal histogram negative offset: %w", err)
+		}
+		h.NegativeOffset = int32(v)
+		if h.NegativeCounts, src, err = unmarshalHistogramCounts(h.NegativeCounts, src); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown histogram kind %d", h.Kind)
+	}
+	if len(src) > 0 {
+		return fmt.Errorf("unexpected %d bytes left after unmarshaling the histogram", len(src))
+	}
+	return nil
+}
+
+func marshalHistogramCounts(dst []byte, counts []uint64) []byte {
+	dst = VarUint64ToBytes(dst, uint64(len(counts)))
+	return VarUint64sToBytes(dst, counts)
+}
+
+func unmarshalHistogramCounts(dst []uint64, src []byte) ([]uint64, []byte, error) {
+	var n uint64
+	src, n = BytesToVarUint64(src)
+	if n > uint64(len(src)) {
+		return dst, src, fmt.Errorf("cannot unmarshal %d histogram counts from %d bytes", n, len(src))
+	}
+	dst = ExtendListCapacity(dst, int(n))
+	start := len(dst)
+	dst = dst[:start+int(n)]
+	tail, err := BytesToVarUint64s(dst[start:], src)
+	if err != nil {
+		return dst, src, fmt.Errorf("cannot unmarshal histogram counts: %w", err)
+	}
+	return dst, tail, nil
+}
+
+const (
+	histogramBlockExplicit byte = iota + 1
+	histogramBlockExponential
+)
+
+// EncodeHistogramBlock encodes a block of histograms marshaled by Histogram.Marshal into dst in columns.
+//
+// The explicit histograms sharing the same bounds store the bounds once, and each bucket is a column.
+// The exponential histograms store the bucket layouts and the flattened bucket counts in columns.
+// It returns ErrIncompatibleHistogram if the histograms are nil, or can't share a layout.
+func EncodeHistogramBlock(dst []byte, values [][]byte) ([]byte, error) {
+	if len(values) == 0 {
+		return dst, nil
+	}
+	hh := make([]Histogram, len(values))
+	for i, v := range values {
+		if v == nil {
+			return dst, fmt.Errorf("%w: the histogram at %d is nil", ErrIncompatibleHistogram, i)
+		}
+		if err := hh[i].Unmarshal(v); err != nil {
+			return dst, err
+		}
+	}
+	switch hh[0].Kind {
+	case HistogramKindExplicit:
+		return encodeExplicitHistograms(dst, hh)
+	case HistogramKindExponential:
+		return encodeExponentialHistograms(dst, hh)
+	default:
+		return dst, fmt.Errorf("%w: unknown histogram kind %d", ErrIncompatibleHistogram, hh[0].Kind)
+	}
+}
+
+func encodeExplicitHistograms(dst []byte, hh []Histogram) ([]byte, error) {
+	bounds := hh[0].Bounds
+	for i := range hh {
+		if hh[i].Kind != HistogramKindExplicit || !equalBounds(hh[i].Bounds, bounds) {
+			return dst, fmt.Errorf("%w: the histogram at %d has different buckets", ErrIncompatibleHistogram, i)
+		}
+	}
+	dst = append(dst, histogramBlockExplicit)
+	dst = VarUint64ToBytes(dst, uint64(len(bounds)))
+	for _, b := range bounds {
+		dst = Uint64ToBytes(dst, math.Float64bits(b))
+	}
+	dst = encodeHistogramCommons(dst, hh)
+	column := make([]uint64, 0, len(hh)*(len(bounds)+1))
+	for j := 0; j <= len(bounds); j++ {
+		for i := range hh {
+			column = append(column, hh[i].Counts[j])
+		}
+	}
+	return EncodeUint64Block(dst, column), nil
+}
+
+func encodeExponentialHistograms(dst []byte, hh []Histogram) ([]byte, error) {
+	for i := range hh {
+		if hh[i].Kind != HistogramKindExponential {
+			return dst, fmt.Errorf("%w: the histogram at %d has different buckets", ErrIncompatibleHistogram, i)
+		}
+	}
+	dst = append(dst, histogramBlockExponential)
+	dst = encodeHistogramCommons(dst, hh)
+	column := make([]uint64, len(hh))
+	encodeColumn := func(fn func(h *Histogram) uint64) {
+		for i := range hh {
+			column[i] = fn(&hh[i])
+		}
+		dst = EncodeUint64Block(dst, column)
+	}
+	encodeColumn(func(h *Histogram) uint64 { return zigzag(int64(h.Scale)) })
+	encodeColumn(func(h *Histogram) uint64 { return math.Float64bits(h.ZeroThreshold) })
+	encodeColumn(func(h *Histogram) uint64 { return h.ZeroCount })
+	encodeColumn(func(h *Histogram) uint64 { return zigzag(int64(h.PositiveOffset)) })
+	encodeColumn(func(h *Histogram) uint64 { return uint64(len(h.PositiveCounts)) })
+	encodeColumn(func(h *Histogram) uint64 { return zigzag(int64(h.NegativeOffset)) })
+	encodeColumn(func(h *Histogram) uint64 { return uint64(len(h.NegativeCounts)) })
+	var buckets []uint64
+	for i := range hh {
+		buckets = append(buckets, hh[i].PositiveCounts...)
+	}
+	for i := range hh {
+		buckets = append(buckets, hh[i].NegativeCounts...)
+	}
+	return EncodeUint64Block(dst, buckets), nil
+}
+
+func encodeHistogramCommons(dst []byte, hh []Histogram) []byte {
+	column := make([]uint64, len(hh))
+	for i := range hh {
+		column[i] = hh[i].Count
+	}
+	dst = EncodeUint64Block(dst, column)
+	for i := range hh {
+		column[i] = math.Float64bits(hh[i].Sum)
+	}
+	return EncodeUint64Block(dst, column)
+}
+
+// DecodeHistogramBlock decodes itemsCount histograms encoded by EncodeHistogramBlock from src,
+// and appends them to dst in the form of Histogram.Marshal.
+func DecodeHistogramBlock(dst [][]byte, src []byte, itemsCount uint64) ([][]byte, error) {
+	if itemsCount == 0 {
+		return dst, nil
+	}
+	if len(src) < 1 {
+		return dst, fmt.Errorf("cannot decode histogram block type from empty src")
+	}
+	hh := make([]Histogram, itemsCount)
+	var err error
+	switch src[0] {
+	case histogramBlockExplicit:
+		err = decodeExplicitHistograms(hh, src[1:])
+	case histogramBlockExponential:
+		err = decodeExponentialHistograms(hh, src[1:])
+	default:
+		err = fmt.Errorf("unknown histogram block type %d", src[0])
+	}
+	if err != nil {
+		return dst, err
+	}
+	for i := range hh {
+		dst = append(dst, hh[i].Marshal(nil))
+	}
+	return dst, nil
+}
+
+func decodeExplicitHistograms(hh []Histogram, src []byte) error {
+	var n uint64
+	src, n = BytesToVarUint64(src)
+	if uint64(len(src)) < n*8 {
+		return fmt.Errorf("cannot decode %d histogram bounds from %d bytes", n, len(src))
+	}
+	bounds := make([]float64, n)
+	for i := range bounds {
+		bounds[i] = math.Float64frombits(BytesToUint64(src))
+		src = src[8:]
+	}
+	src, err := decodeHistogramCommons(hh, src)
+	if err != nil {
+		return err
+	}
+	column, _, err := DecodeUint64Block(nil, src, uint64(len(hh))*(n+1))
+	if err != nil {
+		return fmt.Errorf("cannot decode histogram buckets: %w", err)
+	}
+	for i := range hh {
+		hh[i].Kind = HistogramKindExplicit
+		hh[i].Bounds = bounds
+		hh[i].Counts = make([]uint64, n+1)
+		for j := range hh[i].Counts {
+			hh[i].Counts[j] = column[j*len(hh)+i]
+		}
+	}
+	return nil
+}
+
+func decodeExponentialHistograms(hh []Histogram, src []byte) error {
+	src, err := decodeHistogramCommons(hh, src)
+	if err != nil {
+		return err
+	}
+	var column []uint64
+	decodeColumn := func(name string, fn func(h *Histogram, v uint64)) {
+		if err != nil {
+			return
+		}
+		column, src, err = DecodeUint64Block(column[:0], src, uint64(len(hh)))
+		if err != nil {
+			err = fmt.Errorf("cannot decode histogram %s: %w", name, err)
+			return
+		}
+		for i := range hh {
+			fn(&hh[i], column[i])
+		}
+	}
+	var positiveLen, negativeLen uint64
+	decodeColumn("scales", func(h *Histogram, v uint64) { h.Scale = int32(unzigzag(v)) })
+	decodeColumn("zero thresholds", func(h *Histogram, v uint64) { h.ZeroThreshold = math.Float64frombits(v) })
+	decodeColumn("zero counts", func(h *Histogram, v uint64) { h.ZeroCount = v })
+	decodeColumn("positive offsets", func(h *Histogram, v uint64) { h.PositiveOffset = int32(unzigzag(v)) })
+	decodeColumn("positive lengths", func(h *Histogram, v uint64) {
+		h.PositiveCounts = make([]uint64, v)
+		positiveLen += v
+	})
+	decodeColumn("negative offsets", func(h *Histogram, v uint64) { h.NegativeOffset = int32(unzigzag(v)) })
+	decodeColumn("negative lengths", func(h *Histogram, v uint64) {
+		h.NegativeCounts = make([]uint64, v)
+		negativeLen += v
+	})
+	if err != nil {
+		return err
+	}
+	column, _, err = DecodeUint64Block(column[:0], src, positiveLen+negativeLen)
+	if err != nil {
+		return fmt.Errorf("cannot decode histogram buckets: %w", err)
+	}
+	for i := range hh {
+		hh[i].Kind = HistogramKindExponential
+		column = column[copy(hh[i].PositiveCounts, column):]
+	}
+	for i := range hh {
+		column = column[copy(hh[i].NegativeCounts, column):]
+	}
+	return nil
+}
+
+func decodeHistogramCommons(hh []Histogram, src []byte) ([]byte, error) {
+	column, src, err := DecodeUint64Block(nil, src, uint64(len(hh)))
+	if err != nil {
+		return src, fmt.Errorf("cannot decode histogram counts: %w", err)
+	}
+	for i := range hh {
+		hh[i].Count = column[i]
+	}
+	column, src, err = DecodeUint64Block(column[:0], src, uint64(len(hh)))
+	if err != nil {
+		return src, fmt.Errorf("cannot decode histogram sums: %w", err)
+	}
+	for i := range hh {
+		hh[i].Sum = math.Float64frombits(column[i])
+	}
+	return src, nil
+}
+
+func equalBounds(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Float64bits(a[i]) != math.Float64bits(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func unzigzag(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
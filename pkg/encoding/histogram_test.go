@@ -0,0 +1,132 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramMarshalAndUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		h    Histogram
+	}{
+		{
+			name: "explicit",
+			h: Histogram{
+				Kind:   HistogramKindExplicit,
+				Count:  10,
+				Sum:    123.5,
+				Bounds: []float64{0.1, 1, 10},
+				Counts: []uint64{1, 2, 3, 4},
+			},
+		},
+		{
+			name: "exponential",
+			h: Histogram{
+				Kind:           HistogramKindExponential,
+				Count:          9,
+				Sum:            -2.25,
+				Scale:          -2,
+				ZeroThreshold:  0.001,
+				ZeroCount:      1,
+				PositiveOffset: -3,
+				PositiveCounts: []uint64{1, 0, 2},
+				NegativeOffset: 5,
+				NegativeCounts: []uint64{3, 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Histogram
+			require.NoError(t, got.Unmarshal(tt.h.Marshal(nil)))
+			assert.Equal(t, tt.h.Marshal(nil), got.Marshal(nil))
+		})
+	}
+}
+
+func TestHistogramUnmarshalMalformed(t *testing.T) {
+	h := Histogram{Kind: HistogramKindExplicit, Bounds: []float64{1}, Counts: []uint64{1, 2}}
+	b := h.Marshal(nil)
+	var got Histogram
+	assert.Error(t, got.Unmarshal(b[:len(b)-1]))
+	assert.Error(t, got.Unmarshal(nil))
+	assert.Error(t, got.Unmarshal([]byte{0xff, 0, 0, 0, 0, 0, 0, 0, 0, 0}))
+}
+
+func TestEncodeAndDecodeHistogramBlock(t *testing.T) {
+	explicit := func(count uint64, counts ...uint64) []byte {
+		h := Histogram{Kind: HistogramKindExplicit, Count: count, Sum: float64(count) * 1.5, Bounds: []float64{1, 5, 25}, Counts: counts}
+		return h.Marshal(nil)
+	}
+	exponential := func(offset int32, counts ...uint64) []byte {
+		h := Histogram{
+			Kind: HistogramKindExponential, Count: 3, Sum: 4.5, Scale: 3, ZeroThreshold: 1e-9, ZeroCount: 1,
+			PositiveOffset: offset, PositiveCounts: counts, NegativeOffset: -offset, NegativeCounts: counts[:1],
+		}
+		return h.Marshal(nil)
+	}
+	tests := []struct {
+		name    string
+		values  [][]byte
+		wantErr bool
+	}{
+		{
+			name:   "explicit",
+			values: [][]byte{explicit(6, 1, 2, 3, 0), explicit(0, 0, 0, 0, 0), explicit(100, 10, 80, 5, 5)},
+		},
+		{
+			name:   "exponential",
+			values: [][]byte{exponential(-2, 1, 1), exponential(7, 3), exponential(0, 1, 0, 0, 9)},
+		},
+		{
+			name:    "nil",
+			values:  [][]byte{explicit(1, 1, 0, 0, 0), nil},
+			wantErr: true,
+		},
+		{
+			name:    "mixed kinds",
+			values:  [][]byte{explicit(1, 1, 0, 0, 0), exponential(0, 1)},
+			wantErr: true,
+		},
+		{
+			name: "different bounds",
+			values: [][]byte{explicit(1, 1, 0, 0, 0), (&Histogram{
+				Kind: HistogramKindExplicit, Count: 1, Bounds: []float64{2}, Counts: []uint64{1, 0},
+			}).Marshal(nil)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := EncodeHistogramBlock(nil, tt.values)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrIncompatibleHistogram)
+				return
+			}
+			require.NoError(t, err)
+			decoded, err := DecodeHistogramBlock(nil, encoded, uint64(len(tt.values)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.values, decoded)
+		})
+	}
+}
@@ -19,6 +19,7 @@ package encoding
 
 import (
 	"fmt"
+	"math/bits"
 
 	"github.com/apache/skywalking-banyandb/pkg/logger"
 )
@@ -38,19 +39,38 @@ func Int64ListToBytes(dst []byte, a []int64) (result []byte, mt EncodeType, firs
 		dst = VarInt64ToBytes(dst, a[1]-a[0])
 		return dst, EncodeTypeDeltaConst, firstValue
 	}
-	if isDelta {
+	start := len(dst)
+	switch {
+	case isDelta, isIncremental(a):
+		mt = EncodeTypeDeltaOfDelta
 		dst, firstValue = int64sDeltaOfDeltaToBytes(dst, a)
-		return dst, EncodeTypeDeltaOfDelta, firstValue
+	default:
+		mt = EncodeTypeDelta
+		dst, firstValue = int64ListDeltaToBytes(dst, a)
 	}
+	return packIfSmaller(dst, start, a, mt, firstValue)
+}
 
-	if isIncremental(a) {
-		mt = EncodeTypeDeltaOfDelta
-		dst, firstValue = int64sDeltaOfDeltaToBytes(dst, a)
+// packIfSmaller replaces the delta encoded dst[start:] with the frame of reference
+// or the run-length encoding of a if either is smaller.
+// They suit the small-range values, such as the status codes, and the repeated values.
+func packIfSmaller(dst []byte, start int, a []int64, mt EncodeType, firstValue int64) ([]byte, EncodeType, int64) {
+	minValue, maxValue := a[0], a[0]
+	for _, v := range a[1:] {
+		minValue = min(minValue, v)
+		maxValue = max(maxValue, v)
+	}
+	width := bits.Len64(uint64(maxValue) - uint64(minValue))
+	forSize := frameOfReferenceSize(len(a), width)
+	rleSize := runLengthSize(a)
+	if size := len(dst) - start; size <= forSize && size <= rleSize {
 		return dst, mt, firstValue
 	}
-	mt = EncodeTypeDelta
-	dst, firstValue = int64ListDeltaToBytes(dst, a)
-	return dst, mt, firstValue
+	if rleSize < forSize {
+		dst, firstValue = int64ListToRunLength(dst[:start], a)
+		return dst, EncodeTypeRunLength, firstValue
+	}
+	return int64ListToFrameOfReference(dst[:start], a, minValue, width), EncodeTypeFrameOfReference, minValue
 }
 
 // BytesToInt64List decodes bytes into a list of int64.
@@ -71,6 +91,18 @@ func BytesToInt64List(dst []int64, src []byte, mt EncodeType, firstValue int64,
 			return nil, fmt.Errorf("cannot decode nearest delta2 data: %w", err)
 		}
 		return dst, nil
+	case EncodeTypeFrameOfReference:
+		dst, err = bytesFrameOfReferenceToInt64List(dst, src, firstValue, itemsCount)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode frame of reference data: %w", err)
+		}
+		return dst, nil
+	case EncodeTypeRunLength:
+		dst, err = bytesRunLengthToInt64List(dst, src, firstValue, itemsCount)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode run-length data: %w", err)
+		}
+		return dst, nil
 	case EncodeTypeConst:
 		if len(src) > 0 {
 			return nil, fmt.Errorf("unexpected data left in const encoding: %d bytes", len(src))
@@ -37,7 +37,7 @@ func TestInt64ListToBytes(t *testing.T) {
 			name:       "EncodeTypeDelta",
 			mt:         encoding.EncodeTypeDelta,
 			firstValue: 0,
-			values:     []int64{0, 2, 1, 3, 4},
+			values:     []int64{0, 1000, 999, 1001, 1000},
 		},
 		{
 			name:       "EncodeTypeDeltaOfDelta",
@@ -57,6 +57,18 @@ func TestInt64ListToBytes(t *testing.T) {
 			firstValue: 0,
 			values:     []int64{0, 1, 2, 3, 4},
 		},
+		{
+			name:       "EncodeTypeFrameOfReference",
+			mt:         encoding.EncodeTypeFrameOfReference,
+			firstValue: 200,
+			values:     []int64{200, 404, 200, 500, 201, 200, 302, 200, 503, 200},
+		},
+		{
+			name:       "EncodeTypeRunLength",
+			mt:         encoding.EncodeTypeRunLength,
+			firstValue: 7,
+			values:     []int64{7, 7, 7, 7, 7, 7, 7, 7, -1000000, -1000000, -1000000, -1000000, 7, 7, 7, 7},
+		},
 	}
 
 	for _, tc := range testCases {
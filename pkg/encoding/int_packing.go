@@ -0,0 +1,185 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// maxWindowWidth is the widest value unpacked from a single 8-byte window,
+// since the window starts at the byte holding the first bit of the value.
+const maxWindowWidth = 56
+
+// int64ListToFrameOfReference stores the values as the offsets from minValue,
+// packed into width bits each.
+func int64ListToFrameOfReference(dst []byte, a []int64, minValue int64, width int) []byte {
+	dst = append(dst, byte(width))
+	var acc uint64
+	var n int
+	for _, v := range a {
+		u := uint64(v) - uint64(minValue)
+		acc |= u << n
+		n += width
+		if n >= 64 {
+			dst = binary.LittleEndian.AppendUint64(dst, acc)
+			n -= 64
+			// The shift count reaches 64 if u is flushed completely, which clears acc.
+			acc = u >> (width - n)
+		}
+	}
+	for ; n > 0; n -= 8 {
+		dst = append(dst, byte(acc))
+		acc >>= 8
+	}
+	return dst
+}
+
+func bytesFrameOfReferenceToInt64List(dst []int64, src []byte, minValue int64, itemsCount int) ([]int64, error) {
+	if len(src) < 1 {
+		return nil, fmt.Errorf("cannot decode bit width from empty src")
+	}
+	width := int(src[0])
+	src = src[1:]
+	if width > 64 {
+		return nil, fmt.Errorf("unexpected bit width %d", width)
+	}
+	if want := (itemsCount*width + 7) / 8; len(src) != want {
+		return nil, fmt.Errorf("unexpected length of %d values packed into %d bits; got %d bytes; want %d bytes", itemsCount, width, len(src), want)
+	}
+
+	dstLen := len(dst)
+	dst = dst[:dstLen+itemsCount]
+	out := dst[dstLen:]
+	mask := uint64(1)<<width - 1
+	if width == 64 {
+		mask = ^uint64(0)
+	}
+	i := 0
+	if width == 0 {
+		for ; i < itemsCount; i++ {
+			out[i] = minValue
+		}
+	}
+	if width <= maxWindowWidth && len(src) >= 8 {
+		// The loop has no branch, and reads every value with one unaligned load.
+		// The window of a value must not run past src.
+		fast := min(itemsCount, (len(src)-8)*8/width+1)
+		for ; i < fast; i++ {
+			pos := i * width
+			out[i] = minValue + int64(binary.LittleEndian.Uint64(src[pos>>3:])>>(pos&7)&mask)
+		}
+	}
+	for ; i < itemsCount; i++ {
+		out[i] = minValue + int64(readBits(src, i*width, width))
+	}
+	return dst, nil
+}
+
+// readBits reads width bits starting at the bit position pos.
+func readBits(src []byte, pos, width int) uint64 {
+	var u uint64
+	for read := 0; read < width; {
+		b := uint64(src[(pos+read)>>3]) >> ((pos + read) & 7)
+		n := min(8-((pos+read)&7), width-read)
+		u |= (b & (1<<n - 1)) << read
+		read += n
+	}
+	return u
+}
+
+// frameOfReferenceSize returns the size of the values encoded by int64ListToFrameOfReference.
+func frameOfReferenceSize(itemsCount, width int) int {
+	return 1 + (itemsCount*width+7)/8
+}
+
+// int64ListToRunLength stores the runs of the same values.
+// Each run is the delta of its value from the previous run, followed by its length.
+// The value of the first run is a[0], which is returned as the first value.
+func int64ListToRunLength(dst []byte, a []int64) (result []byte, firstValue int64) {
+	firstValue = a[0]
+	prev := firstValue
+	runLen := uint64(0)
+	for _, v := range a {
+		if v == prev {
+			runLen++
+			continue
+		}
+		dst = VarUint64ToBytes(dst, runLen)
+		dst = VarInt64ToBytes(dst, v-prev)
+		prev = v
+		runLen = 1
+	}
+	return VarUint64ToBytes(dst, runLen), firstValue
+}
+
+func bytesRunLengthToInt64List(dst []int64, src []byte, firstValue int64, itemsCount int) ([]int64, error) {
+	v := firstValue
+	remaining := uint64(itemsCount)
+	for {
+		var runLen uint64
+		src, runLen = BytesToVarUint64(src)
+		if runLen == 0 || runLen > remaining {
+			return nil, fmt.Errorf("unexpected run length %d with %d values remaining", runLen, remaining)
+		}
+		remaining -= runLen
+		dstLen := len(dst)
+		dst = dst[:dstLen+int(runLen)]
+		run := dst[dstLen:]
+		for i := range run {
+			run[i] = v
+		}
+		if remaining == 0 {
+			break
+		}
+		var d int64
+		var err error
+		src, d, err = BytesToVarInt64(src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode the value of the run: %w", err)
+		}
+		v += d
+	}
+	if len(src) > 0 {
+		return nil, fmt.Errorf("unexpected data left after %d run-length encoded values: %d bytes", itemsCount, len(src))
+	}
+	return dst, nil
+}
+
+// runLengthSize returns the size of the values encoded by int64ListToRunLength.
+func runLengthSize(a []int64) int {
+	size := 0
+	prev := a[0]
+	runLen := uint64(0)
+	for _, v := range a {
+		if v == prev {
+			runLen++
+			continue
+		}
+		d := v - prev
+		size += varUint64Size(runLen) + varUint64Size(uint64((d<<1)^(d>>63)))
+		prev = v
+		runLen = 1
+	}
+	return size + varUint64Size(runLen)
+}
+
+func varUint64Size(u uint64) int {
+	return (bits.Len64(u|1) + 6) / 7
+}
@@ -0,0 +1,106 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameOfReferenceRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for width := 0; width <= 64; width++ {
+		for _, n := range []int{1, 7, 64, 129} {
+			minValue := int64(r.Uint64())
+			a := make([]int64, n)
+			for i := range a {
+				var offset uint64
+				if width > 0 {
+					offset = r.Uint64() >> (64 - width)
+				}
+				a[i] = int64(uint64(minValue) + offset)
+			}
+			a[0] = minValue
+
+			encoded := int64ListToFrameOfReference([]byte("prefix"), a, minValue, width)
+			require.Equal(t, "prefix", string(encoded[:6]))
+			require.Len(t, encoded[6:], frameOfReferenceSize(n, width))
+			decoded, err := bytesFrameOfReferenceToInt64List(make([]int64, 1, n+1), encoded[6:], minValue, n)
+			require.NoError(t, err, "width=%d n=%d", width, n)
+			require.Equal(t, a, decoded[1:], "width=%d n=%d", width, n)
+		}
+	}
+}
+
+func TestFrameOfReferenceCorrupted(t *testing.T) {
+	a := []int64{1, 5, 3, 7}
+	encoded := int64ListToFrameOfReference(nil, a, 1, 3)
+	_, err := bytesFrameOfReferenceToInt64List(make([]int64, 0, len(a)), encoded[:len(encoded)-1], 1, len(a))
+	require.Error(t, err)
+	_, err = bytesFrameOfReferenceToInt64List(make([]int64, 0, len(a)), []byte{65}, 1, len(a))
+	require.Error(t, err)
+}
+
+func TestRunLengthRoundTrip(t *testing.T) {
+	tests := [][]int64{
+		{1},
+		{1, 1, 1},
+		{1, 2, 3},
+		{math.MinInt64, math.MinInt64, math.MaxInt64, 0, 0, 0, 0},
+	}
+	for _, a := range tests {
+		encoded, firstValue := int64ListToRunLength(nil, a)
+		require.Equal(t, a[0], firstValue)
+		require.Len(t, encoded, runLengthSize(a))
+		decoded, err := bytesRunLengthToInt64List(make([]int64, 0, len(a)), encoded, firstValue, len(a))
+		require.NoError(t, err)
+		require.Equal(t, a, decoded)
+	}
+}
+
+func TestRunLengthCorrupted(t *testing.T) {
+	a := []int64{1, 1, 2, 2}
+	encoded, firstValue := int64ListToRunLength(nil, a)
+	_, err := bytesRunLengthToInt64List(make([]int64, 0, 8), encoded, firstValue, len(a)-1)
+	require.Error(t, err)
+	_, err = bytesRunLengthToInt64List(make([]int64, 0, 8), encoded, firstValue, len(a)+1)
+	require.Error(t, err)
+}
+
+func BenchmarkBytesToInt64List(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	statusCodes := []int64{200, 201, 204, 301, 302, 400, 404, 500, 503}
+	a := make([]int64, 8192)
+	for i := range a {
+		a[i] = statusCodes[r.Intn(len(statusCodes))]
+	}
+	src, mt, firstValue := Int64ListToBytes(nil, a)
+	require.Equal(b, EncodeTypeFrameOfReference, mt)
+	b.SetBytes(int64(len(a) * 8))
+	b.ReportAllocs()
+	dst := make([]int64, 0, len(a))
+	var err error
+	for i := 0; i < b.N; i++ {
+		if dst, err = BytesToInt64List(dst[:0], src, mt, firstValue, len(a)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
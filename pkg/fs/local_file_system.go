@@ -25,6 +25,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/disk"
@@ -323,7 +324,7 @@ func (fs *localFileSystem) CreateHardLink(srcPath, destPath string, filter func(
 		}
 	}
 	if !fi.IsDir() {
-		if err = os.Link(srcPath, destPath); err != nil {
+		if err = linkOrCopy(srcPath, destPath); err != nil {
 			code := otherError
 			if os.IsExist(err) {
 				code = isExistError
@@ -388,7 +389,7 @@ func (fs *localFileSystem) CreateHardLink(srcPath, destPath string, filter func(
 			}
 		}
 
-		if err := os.Link(path, destFullPath); err != nil {
+		if err := linkOrCopy(path, destFullPath); err != nil {
 			code := otherError
 			if os.IsExist(err) {
 				code = isExistError
@@ -409,6 +410,33 @@ func (fs *localFileSystem) CreateHardLink(srcPath, destPath string, filter func(
 	return nil
 }
 
+// linkOrCopy creates a hard link of src at dest. It falls back to copying the file
+// when they are on different devices, e.g. a segment placed on another data volume.
+func linkOrCopy(src, dest string) error {
+	err := os.Link(src, dest)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err = out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
 // Write adds new data to the end of a file.
 func (file *LocalFile) Write(buffer []byte) (int, error) {
 	size, err := file.file.Write(buffer)
@@ -34,6 +34,11 @@ import (
 	config2 "github.com/apache/skywalking-banyandb/pkg/fs/remote/config"
 )
 
+var (
+	_ remote.FS              = (*s3FS)(nil)
+	_ remote.RangeDownloader = (*s3FS)(nil)
+)
+
 // todo: Maybe we can bring in minio, oss
 type s3FS struct {
 	client            *s3.Client
@@ -149,6 +154,19 @@ func (s *s3FS) Download(ctx context.Context, path string) (io.ReadCloser, error)
 	return resp.Body, nil
 }
 
+func (s *s3FS) DownloadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	key := s.getFullPath(path)
+	resp, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
 func (s *s3FS) List(ctx context.Context, prefix string) ([]string, error) {
 	fullPrefix := s.getFullPath(prefix)
 	var files []string
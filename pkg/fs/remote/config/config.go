@@ -29,11 +29,11 @@ type FsConfig struct {
 // S3Config represents the configuration for S3.
 type S3Config struct {
 	// S3 configuration
-	S3ConfigFilePath     string
-	S3CredentialFilePath string
-	S3ProfileName        string
-	S3StorageClass       string
-	S3ChecksumAlgorithm  string
+	S3ConfigFilePath     string `json:"config_file,omitempty"`
+	S3CredentialFilePath string `json:"credential_file,omitempty"`
+	S3ProfileName        string `json:"profile,omitempty"`
+	S3StorageClass       string `json:"storage_class,omitempty"`
+	S3ChecksumAlgorithm  string `json:"checksum_algorithm,omitempty"`
 }
 
 // AzureConfig represents the configuration for Azure.
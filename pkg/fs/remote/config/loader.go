@@ -63,3 +63,46 @@ func LoadFSConfig(path string) (*FsConfig, error) {
 
 	return cfg, nil
 }
+
+// Load decodes the configuration of any provider from a JSON or YAML file.
+// Both formats use the JSON field names. Missing provider sections are initialized
+// so that the file systems fall back to their default credentials.
+func Load(path string) (*FsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+	case ".yaml", ".yml":
+		var raw map[string]any
+		if err = yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parse YAML: %w", err)
+		}
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, fmt.Errorf("convert YAML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", ext)
+	}
+	cfg := &FsConfig{}
+	if err = json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+	switch cfg.Provider {
+	case "", "azure", "s3", "gcp":
+	default:
+		return nil, fmt.Errorf("unsupported provider %q (expect azure, s3 or gcp)", cfg.Provider)
+	}
+	if cfg.S3 == nil {
+		cfg.S3 = &S3Config{}
+	}
+	if cfg.Azure == nil {
+		cfg.Azure = &AzureConfig{}
+	}
+	if cfg.GCP == nil {
+		cfg.GCP = &GCPConfig{}
+	}
+	return cfg, nil
+}
@@ -41,7 +41,10 @@ import (
 
 const checksumSha256Key = "checksum_sha256"
 
-var _ remote.FS = (*gcsFS)(nil)
+var (
+	_ remote.FS              = (*gcsFS)(nil)
+	_ remote.RangeDownloader = (*gcsFS)(nil)
+)
 
 // gcsFS implements remote.FS backed by Google Cloud Storage.
 // Field order is optimized to reduce struct padding.
@@ -206,6 +209,17 @@ func (g *gcsFS) Download(ctx context.Context, p string) (io.ReadCloser, error) {
 	return g.verifier.Wrap(reader, expected), nil
 }
 
+// DownloadRange reads a byte range of the object. The sha256 checksum covers the whole
+// object, so range reads rely on the transport integrity checks of the client.
+func (g *gcsFS) DownloadRange(ctx context.Context, p string, offset, length int64) (io.ReadCloser, error) {
+	objPath := g.getFullPath(p)
+	reader, err := g.client.Bucket(g.bucket).Object(objPath).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create range reader: %w", err)
+	}
+	return reader, nil
+}
+
 func (g *gcsFS) List(ctx context.Context, prefix string) ([]string, error) {
 	fullPrefix := g.getFullPath(prefix)
 	logger.Infof("GCS List: bucket=%s, prefix=%s, fullPrefix=%s", g.bucket, prefix, fullPrefix)
@@ -29,7 +29,10 @@ import (
 
 const dirPerm = 0o755
 
-var _ remote.FS = (*fs)(nil)
+var (
+	_ remote.FS              = (*fs)(nil)
+	_ remote.RangeDownloader = (*fs)(nil)
+)
 
 type fs struct {
 	baseDir string
@@ -64,6 +67,23 @@ func (l *fs) Download(_ context.Context, path string) (io.ReadCloser, error) {
 	return os.Open(fullPath)
 }
 
+func (l *fs) DownloadRange(_ context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	fullPath := filepath.Join(l.baseDir, path)
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	return &sectionReadCloser{
+		Reader: io.NewSectionReader(f, offset, length),
+		Closer: f,
+	}, nil
+}
+
+type sectionReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
 func (l *fs) List(_ context.Context, prefix string) ([]string, error) {
 	var files []string
 	fullPath := filepath.Join(l.baseDir, prefix)
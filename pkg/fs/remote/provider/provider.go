@@ -0,0 +1,53 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package provider creates remote file systems from destination URLs.
+package provider
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/aws"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/azure"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/config"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/gcp"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/local"
+)
+
+// NewFS creates the remote file system addressed by dest, e.g. file:///backups,
+// s3:///bucket/path, azure://container/path or gs://bucket/path.
+func NewFS(dest string, cfg *config.FsConfig) (remote.FS, error) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dest URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return local.NewFS(u.Path)
+	case "s3":
+		return aws.NewFS(u.Path, cfg)
+	case "azure":
+		return azure.NewFS(u.Host+u.Path, cfg)
+	case "gcs", "gs":
+		return gcp.NewFS(u.Host+u.Path, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported scheme: %s", u.Scheme)
+	}
+}
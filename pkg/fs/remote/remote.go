@@ -47,3 +47,11 @@ type FS interface {
 	// Must be called when the client is no longer needed.
 	Close() error
 }
+
+// RangeDownloader is implemented by remote file systems which can fetch a byte range of an object
+// without transferring the whole object. Tiered storage relies on it to read blocks in place.
+type RangeDownloader interface {
+	// DownloadRange retrieves length bytes of the object at path, starting at offset.
+	// Returns a ReadCloser that must be closed by the caller after consumption.
+	DownloadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error)
+}
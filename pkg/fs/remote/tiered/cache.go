@@ -0,0 +1,251 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tiered
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// DefaultChunkSize is the granularity in which remote objects are fetched and cached.
+	DefaultChunkSize = 4 << 20
+
+	cacheDirPerm  = 0o755
+	cacheFilePerm = 0o600
+	tmpSuffix     = ".tmp"
+)
+
+// Cache is a read-through cache which keeps chunks of remote objects on the local disk.
+// Chunks are evicted in least-recently-used order once the cache grows beyond its capacity.
+type Cache struct {
+	entries   map[string]*list.Element
+	lru       *list.List
+	root      string
+	maxBytes  uint64
+	size      uint64
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	chunkSize int64
+	mu        sync.Mutex
+}
+
+type cacheEntry struct {
+	name string
+	size uint64
+}
+
+// NewCache opens the cache rooted at root. Chunks left by a previous run are loaded
+// in modification order, so the most recently written ones are evicted last.
+func NewCache(root string, maxBytes uint64) (*Cache, error) {
+	if err := os.MkdirAll(root, cacheDirPerm); err != nil {
+		return nil, err
+	}
+	c := &Cache{
+		root:      root,
+		maxBytes:  maxBytes,
+		chunkSize: DefaultChunkSize,
+		entries:   make(map[string]*list.Element),
+		lru:       list.New(),
+	}
+	type chunkFile struct {
+		name    string
+		size    uint64
+		modTime int64
+	}
+	var files []chunkFile
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(p, tmpSuffix) {
+			return os.Remove(p)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, chunkFile{name: rel, size: uint64(info.Size()), modTime: info.ModTime().UnixNano()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the cache %s: %w", root, err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime < files[j].modTime })
+	for _, f := range files {
+		c.entries[f.name] = c.lru.PushFront(&cacheEntry{name: f.name, size: f.size})
+		c.size += f.size
+	}
+	c.evictLocked(c.maxBytes)
+	return c, nil
+}
+
+// ChunkSize returns the size of the chunks stored in the cache.
+func (c *Cache) ChunkSize() int64 {
+	return c.chunkSize
+}
+
+// Get returns the cached chunk of the object, or false if it is absent.
+func (c *Cache) Get(object string, chunk int64) ([]byte, bool) {
+	name := chunkName(object, chunk)
+	c.mu.Lock()
+	e, ok := c.entries[name]
+	if ok {
+		c.lru.MoveToFront(e)
+	}
+	c.mu.Unlock()
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(c.root, name))
+	if err != nil {
+		c.remove(name)
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return data, true
+}
+
+// Put stores a chunk of the object and evicts the least recently used chunks if the
+// cache exceeds its capacity. Failures are returned but leave the cache consistent.
+func (c *Cache) Put(object string, chunk int64, data []byte) error {
+	if c.maxBytes == 0 || uint64(len(data)) > c.maxBytes {
+		return nil
+	}
+	name := chunkName(object, chunk)
+	p := filepath.Join(c.root, name)
+	if err := os.MkdirAll(filepath.Dir(p), cacheDirPerm); err != nil {
+		return err
+	}
+	tmp := p + tmpSuffix
+	if err := os.WriteFile(tmp, data, cacheFilePerm); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[name]; ok {
+		ce := e.Value.(*cacheEntry)
+		c.size -= ce.size
+		ce.size = uint64(len(data))
+		c.size += ce.size
+		c.lru.MoveToFront(e)
+	} else {
+		c.entries[name] = c.lru.PushFront(&cacheEntry{name: name, size: uint64(len(data))})
+		c.size += uint64(len(data))
+	}
+	c.evictLocked(c.maxBytes)
+	return nil
+}
+
+// Size returns the bytes held by the cache.
+func (c *Cache) Size() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Entries returns the number of cached chunks.
+func (c *Cache) Entries() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(len(c.entries))
+}
+
+// Hits returns the number of chunk lookups served by the cache.
+func (c *Cache) Hits() uint64 {
+	return c.hits.Load()
+}
+
+// Misses returns the number of chunk lookups which had to go to the remote storage.
+func (c *Cache) Misses() uint64 {
+	return c.misses.Load()
+}
+
+// Evict drops the least recently used chunks until the cache holds at most target bytes.
+// It returns the number of bytes released.
+func (c *Cache) Evict(target uint64) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictLocked(target)
+}
+
+func (c *Cache) evictLocked(target uint64) uint64 {
+	var freed uint64
+	for c.size > target {
+		e := c.lru.Back()
+		if e == nil {
+			break
+		}
+		ce := e.Value.(*cacheEntry)
+		if err := os.Remove(filepath.Join(c.root, ce.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		c.lru.Remove(e)
+		delete(c.entries, ce.name)
+		c.size -= ce.size
+		freed += ce.size
+	}
+	return freed
+}
+
+func (c *Cache) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[name]
+	if !ok {
+		return
+	}
+	ce := e.Value.(*cacheEntry)
+	c.lru.Remove(e)
+	delete(c.entries, name)
+	c.size -= ce.size
+	_ = os.Remove(filepath.Join(c.root, name))
+}
+
+// chunkName maps a chunk to a file in the cache. Objects are hashed so that arbitrary
+// remote paths fit the local file system, and fanned out over 256 directories.
+func chunkName(object string, chunk int64) string {
+	sum := sha256.Sum256([]byte(object))
+	h := hex.EncodeToString(sum[:])
+	return filepath.Join(h[:2], h[2:]+"_"+strconv.FormatInt(chunk, 10))
+}
@@ -0,0 +1,90 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tiered
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_PutGet(t *testing.T) {
+	c, err := NewCache(t.TempDir(), 1024)
+	require.NoError(t, err)
+
+	_, ok := c.Get("seg-1/fv.bin", 0)
+	assert.False(t, ok)
+	require.NoError(t, c.Put("seg-1/fv.bin", 0, []byte("hello")))
+	data, ok := c.Get("seg-1/fv.bin", 0)
+	require.True(t, ok)
+	assert.Equal(t, []byte("hello"), data)
+	_, ok = c.Get("seg-1/fv.bin", 1)
+	assert.False(t, ok)
+
+	assert.Equal(t, uint64(5), c.Size())
+	assert.Equal(t, uint64(1), c.Entries())
+	assert.Equal(t, uint64(1), c.Hits())
+	assert.Equal(t, uint64(2), c.Misses())
+}
+
+func TestCache_EvictLRU(t *testing.T) {
+	c, err := NewCache(t.TempDir(), 300)
+	require.NoError(t, err)
+	chunk := bytes.Repeat([]byte{1}, 100)
+	require.NoError(t, c.Put("a", 0, chunk))
+	require.NoError(t, c.Put("b", 0, chunk))
+	require.NoError(t, c.Put("c", 0, chunk))
+	// Touch "a" so that "b" becomes the least recently used chunk.
+	_, ok := c.Get("a", 0)
+	require.True(t, ok)
+	require.NoError(t, c.Put("d", 0, chunk))
+
+	assert.Equal(t, uint64(300), c.Size())
+	_, ok = c.Get("b", 0)
+	assert.False(t, ok)
+	_, ok = c.Get("a", 0)
+	assert.True(t, ok)
+
+	assert.Equal(t, uint64(200), c.Evict(100))
+	assert.Equal(t, uint64(100), c.Size())
+	_, ok = c.Get("a", 0)
+	assert.True(t, ok, "the most recently used chunk is kept")
+}
+
+func TestCache_Reload(t *testing.T) {
+	root := t.TempDir()
+	c, err := NewCache(root, 1024)
+	require.NoError(t, err)
+	require.NoError(t, c.Put("a", 0, []byte("abc")))
+	require.NoError(t, c.Put("a", 1, []byte("de")))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "stale"+tmpSuffix), []byte("x"), cacheFilePerm))
+
+	c, err = NewCache(root, 1024)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), c.Size())
+	assert.Equal(t, uint64(2), c.Entries())
+	data, ok := c.Get("a", 1)
+	require.True(t, ok)
+	assert.Equal(t, []byte("de"), data)
+	_, err = os.Stat(filepath.Join(root, "stale"+tmpSuffix))
+	assert.True(t, os.IsNotExist(err))
+}
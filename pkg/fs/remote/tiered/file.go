@@ -0,0 +1,134 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tiered
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+)
+
+var _ fs.File = (*remoteFile)(nil)
+
+var errReadOnly = errors.New("offloaded files are read-only")
+
+// remoteFile is a read-only view of an offloaded file. Reads are split into chunks
+// which are served by the cache or fetched from the remote storage.
+type remoteFile struct {
+	fs     *FileSystem
+	path   string
+	object string
+	size   int64
+}
+
+func (r *remoteFile) Read(offset int64, buffer []byte) (int, error) {
+	if offset >= r.size {
+		return 0, io.EOF
+	}
+	var n int
+	chunkSize := r.fs.chunkSize()
+	for n < len(buffer) && offset < r.size {
+		chunk := offset / chunkSize
+		data, err := r.fs.readChunk(r.object, chunk, r.size)
+		if err != nil {
+			return n, fmt.Errorf("failed to read the offloaded file %s: %w", r.path, err)
+		}
+		start := offset - chunk*chunkSize
+		if start >= int64(len(data)) {
+			return n, io.ErrUnexpectedEOF
+		}
+		c := copy(buffer[n:], data[start:])
+		n += c
+		offset += int64(c)
+	}
+	if n < len(buffer) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (r *remoteFile) Readv(offset int64, iov *[][]byte) (int, error) {
+	var size int
+	for _, buffer := range *iov {
+		n, err := r.Read(offset, buffer)
+		size += n
+		if err != nil {
+			return size, err
+		}
+		offset += int64(n)
+	}
+	return size, nil
+}
+
+func (r *remoteFile) SequentialRead() fs.SeqReader {
+	return &remoteSeqReader{file: r}
+}
+
+func (r *remoteFile) Size() (int64, error) {
+	return r.size, nil
+}
+
+func (r *remoteFile) Path() string {
+	return r.path
+}
+
+func (r *remoteFile) Close() error {
+	return nil
+}
+
+func (r *remoteFile) Write(_ []byte) (int, error) {
+	return 0, errReadOnly
+}
+
+func (r *remoteFile) Writev(_ *[][]byte) (int, error) {
+	return 0, errReadOnly
+}
+
+func (r *remoteFile) SequentialWrite() fs.SeqWriter {
+	return nil
+}
+
+type remoteSeqReader struct {
+	file   *remoteFile
+	offset int64
+}
+
+func (s *remoteSeqReader) Read(p []byte) (int, error) {
+	if s.offset >= s.file.size {
+		return 0, io.EOF
+	}
+	if remaining := s.file.size - s.offset; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := s.file.Read(s.offset, p)
+	s.offset += int64(n)
+	if errors.Is(err, io.EOF) && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (s *remoteSeqReader) Path() string {
+	return s.file.path
+}
+
+func (s *remoteSeqReader) Close() error {
+	return nil
+}
@@ -0,0 +1,410 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package tiered implements a file system which keeps hot files on the local disk and
+// serves offloaded files from a remote object storage through a local read-through cache.
+package tiered
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+)
+
+// ManifestFilename is the file which lists the offloaded files of a directory.
+const ManifestFilename = "remote.json"
+
+const (
+	fetchRetries = 3
+	fetchBackoff = 100 * time.Millisecond
+)
+
+var _ fs.FileSystem = (*FileSystem)(nil)
+
+// FileSystem wraps a local file system. Files listed in the manifest of a directory are
+// read from the remote storage, all other operations go to the local file system.
+type FileSystem struct {
+	fs.FileSystem
+	remote    remote.FS
+	cache     *Cache
+	l         *logger.Logger
+	manifests map[string]*manifest
+	inflight  map[string]*fetchCall
+	root      string
+	mu        sync.RWMutex
+	fetchMu   sync.Mutex
+}
+
+type manifest struct {
+	Files map[string]int64 `json:"files"`
+}
+
+type fetchCall struct {
+	err  error
+	data []byte
+	wg   sync.WaitGroup
+}
+
+// NewFileSystem returns a tiered file system. Remote objects are keyed by the path of
+// the local file relative to root. The cache is optional.
+func NewFileSystem(local fs.FileSystem, remoteFS remote.FS, cache *Cache, root string, l *logger.Logger) *FileSystem {
+	return &FileSystem{
+		FileSystem: local,
+		remote:     remoteFS,
+		cache:      cache,
+		root:       filepath.Clean(root),
+		l:          l,
+		manifests:  make(map[string]*manifest),
+		inflight:   make(map[string]*fetchCall),
+	}
+}
+
+// OpenFile opens the local file, or a read-only remote file if the file has been offloaded.
+func (f *FileSystem) OpenFile(name string) (fs.File, error) {
+	file, err := f.FileSystem.OpenFile(name)
+	if err == nil || !isNotExist(err) {
+		return file, err
+	}
+	if rf, ok := f.openRemote(name); ok {
+		return rf, nil
+	}
+	return nil, err
+}
+
+// Read reads the entire local or offloaded file.
+func (f *FileSystem) Read(name string) ([]byte, error) {
+	data, err := f.FileSystem.Read(name)
+	if err == nil || !isNotExist(err) {
+		return data, err
+	}
+	rf, ok := f.openRemote(name)
+	if !ok {
+		return data, err
+	}
+	buf := make([]byte, rf.size)
+	if _, err = rf.Read(0, buf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// IsExist reports whether the path exists locally or has been offloaded.
+func (f *FileSystem) IsExist(path string) bool {
+	if f.FileSystem.IsExist(path) {
+		return true
+	}
+	_, ok := f.lookup(path)
+	return ok
+}
+
+// ReadDir lists the local entries together with the offloaded files of the directory.
+func (f *FileSystem) ReadDir(dirname string) []fs.DirEntry {
+	ee := f.FileSystem.ReadDir(dirname)
+	m := f.manifest(dirname)
+	if m == nil || len(m.Files) == 0 {
+		return ee
+	}
+	seen := make(map[string]struct{}, len(ee))
+	for _, e := range ee {
+		seen[e.Name()] = struct{}{}
+	}
+	for name := range m.Files {
+		if _, ok := seen[name]; !ok {
+			ee = append(ee, fileEntry(name))
+		}
+	}
+	sort.Slice(ee, func(i, j int) bool { return ee[i].Name() < ee[j].Name() })
+	return ee
+}
+
+// MustRMAll removes the remote objects offloaded from the directory tree before removing it locally.
+func (f *FileSystem) MustRMAll(path string) {
+	path = filepath.Clean(path)
+	_ = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != ManifestFilename {
+			return nil
+		}
+		dir := filepath.Dir(p)
+		m := f.manifest(dir)
+		if m == nil {
+			return nil
+		}
+		for name := range m.Files {
+			object, err := f.objectPath(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			if err := f.remote.Delete(context.Background(), object); err != nil {
+				f.l.Warn().Err(err).Str("object", object).Msg("failed to delete the offloaded file")
+			}
+		}
+		return nil
+	})
+	f.mu.Lock()
+	for dir := range f.manifests {
+		if dir == path || strings.HasPrefix(dir, path+string(filepath.Separator)) {
+			delete(f.manifests, dir)
+		}
+	}
+	f.mu.Unlock()
+	f.FileSystem.MustRMAll(path)
+}
+
+// IsOffloaded reports whether files of the directory have been moved to the remote storage.
+func (f *FileSystem) IsOffloaded(dir string) bool {
+	return f.FileSystem.IsExist(filepath.Join(dir, ManifestFilename))
+}
+
+// OffloadDir uploads the files of dir, except those accepted by keep, to the remote storage.
+// The manifest is persisted before the local copies are removed, so a crash in between
+// leaves readable local files. It returns the number of bytes offloaded.
+func (f *FileSystem) OffloadDir(ctx context.Context, dir string, keep func(name string) bool) (uint64, error) {
+	dir = filepath.Clean(dir)
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	m := &manifest{Files: make(map[string]int64)}
+	if old := f.manifest(dir); old != nil {
+		for name, size := range old.Files {
+			m.Files[name] = size
+		}
+	}
+	var offloaded []string
+	var total uint64
+	for _, de := range des {
+		name := de.Name()
+		if de.IsDir() || name == ManifestFilename || (keep != nil && keep(name)) {
+			continue
+		}
+		size, uploadErr := f.upload(ctx, filepath.Join(dir, name))
+		if uploadErr != nil {
+			return 0, uploadErr
+		}
+		m.Files[name] = size
+		offloaded = append(offloaded, name)
+		total += uint64(size)
+	}
+	if len(offloaded) == 0 {
+		return 0, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return 0, err
+	}
+	manifestPath := filepath.Join(dir, ManifestFilename)
+	tmp := manifestPath + tmpSuffix
+	if err = os.WriteFile(tmp, data, cacheFilePerm); err != nil {
+		return 0, err
+	}
+	if err = os.Rename(tmp, manifestPath); err != nil {
+		return 0, err
+	}
+	f.FileSystem.SyncPath(dir)
+	f.mu.Lock()
+	f.manifests[dir] = m
+	f.mu.Unlock()
+	for _, name := range offloaded {
+		if err := f.FileSystem.DeleteFile(filepath.Join(dir, name)); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (f *FileSystem) upload(ctx context.Context, name string) (int64, error) {
+	object, err := f.objectPath(name)
+	if err != nil {
+		return 0, err
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if err := f.remote.Upload(ctx, object, file); err != nil {
+		return 0, fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+	return info.Size(), nil
+}
+
+func (f *FileSystem) openRemote(name string) (*remoteFile, bool) {
+	size, ok := f.lookup(name)
+	if !ok {
+		return nil, false
+	}
+	object, err := f.objectPath(name)
+	if err != nil {
+		return nil, false
+	}
+	return &remoteFile{fs: f, path: name, object: object, size: size}, true
+}
+
+func (f *FileSystem) lookup(name string) (int64, bool) {
+	m := f.manifest(filepath.Dir(name))
+	if m == nil {
+		return 0, false
+	}
+	size, ok := m.Files[filepath.Base(name)]
+	return size, ok
+}
+
+func (f *FileSystem) manifest(dir string) *manifest {
+	dir = filepath.Clean(dir)
+	f.mu.RLock()
+	m, ok := f.manifests[dir]
+	f.mu.RUnlock()
+	if ok {
+		return m
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFilename))
+	if err != nil {
+		// Missing manifests are not cached because the directory may be offloaded later.
+		return nil
+	}
+	m = &manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		f.l.Warn().Err(err).Str("dir", dir).Msg("ignore the corrupted manifest of offloaded files")
+		return nil
+	}
+	f.mu.Lock()
+	f.manifests[dir] = m
+	f.mu.Unlock()
+	return m
+}
+
+func (f *FileSystem) objectPath(name string) (string, error) {
+	rel, err := filepath.Rel(f.root, filepath.Clean(name))
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is not under the tiered root %s", name, f.root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// readChunk returns a chunk of the object. Concurrent reads of the same chunk share one fetch.
+func (f *FileSystem) readChunk(object string, chunk, size int64) ([]byte, error) {
+	if f.cache != nil {
+		if data, ok := f.cache.Get(object, chunk); ok {
+			return data, nil
+		}
+	}
+	key := fmt.Sprintf("%s#%d", object, chunk)
+	f.fetchMu.Lock()
+	if c, ok := f.inflight[key]; ok {
+		f.fetchMu.Unlock()
+		c.wg.Wait()
+		return c.data, c.err
+	}
+	c := &fetchCall{}
+	c.wg.Add(1)
+	f.inflight[key] = c
+	f.fetchMu.Unlock()
+
+	offset := chunk * f.chunkSize()
+	c.data, c.err = f.fetch(object, offset, min(f.chunkSize(), size-offset))
+	if c.err == nil && f.cache != nil {
+		if err := f.cache.Put(object, chunk, c.data); err != nil {
+			f.l.Warn().Err(err).Str("object", object).Msg("failed to cache the remote chunk")
+		}
+	}
+	c.wg.Done()
+	f.fetchMu.Lock()
+	delete(f.inflight, key)
+	f.fetchMu.Unlock()
+	return c.data, c.err
+}
+
+func (f *FileSystem) chunkSize() int64 {
+	if f.cache != nil {
+		return f.cache.ChunkSize()
+	}
+	return DefaultChunkSize
+}
+
+func (f *FileSystem) fetch(object string, offset, length int64) ([]byte, error) {
+	var err error
+	for i := 0; i < fetchRetries; i++ {
+		if i > 0 {
+			time.Sleep(fetchBackoff << (i - 1))
+		}
+		var data []byte
+		if data, err = f.fetchOnce(object, offset, length); err == nil {
+			return data, nil
+		}
+		f.l.Warn().Err(err).Str("object", object).Int64("offset", offset).Int("attempt", i+1).Msg("failed to fetch the remote chunk")
+	}
+	return nil, err
+}
+
+func (f *FileSystem) fetchOnce(object string, offset, length int64) ([]byte, error) {
+	ctx := context.Background()
+	var rc io.ReadCloser
+	var err error
+	if rd, ok := f.remote.(remote.RangeDownloader); ok {
+		rc, err = rd.DownloadRange(ctx, object, offset, length)
+	} else {
+		rc, err = f.remote.Download(ctx, object)
+		if err == nil {
+			_, err = io.CopyN(io.Discard, rc, offset)
+		}
+	}
+	if rc != nil {
+		defer rc.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, length)
+	if _, err = io.ReadFull(rc, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func isNotExist(err error) bool {
+	var fsErr *fs.FileSystemError
+	return errors.As(err, &fsErr) && fsErr.Code == fs.IsNotExistError
+}
+
+type fileEntry string
+
+func (e fileEntry) Name() string {
+	return string(e)
+}
+
+func (e fileEntry) IsDir() bool {
+	return false
+}
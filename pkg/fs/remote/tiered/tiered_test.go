@@ -0,0 +1,133 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tiered
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/pkg/fs"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote"
+	"github.com/apache/skywalking-banyandb/pkg/fs/remote/local"
+	"github.com/apache/skywalking-banyandb/pkg/logger"
+)
+
+// downloadOnlyFS hides the range support of the wrapped file system.
+type downloadOnlyFS struct {
+	remote.FS
+}
+
+func TestFileSystem_Offload(t *testing.T) {
+	for name, wrap := range map[string]func(remote.FS) remote.FS{
+		"range":    func(r remote.FS) remote.FS { return r },
+		"download": func(r remote.FS) remote.FS { return downloadOnlyFS{r} },
+	} {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			remoteDir := t.TempDir()
+			remoteFS, err := local.NewFS(remoteDir)
+			require.NoError(t, err)
+			cache, err := NewCache(t.TempDir(), 1<<20)
+			require.NoError(t, err)
+			cache.chunkSize = 4
+			tfs := NewFileSystem(fs.NewLocalFileSystem(), wrap(remoteFS), cache, root, logger.GetLogger("test"))
+
+			partDir := filepath.Join(root, "seg-20240101", "shard-0", "0000000000000001")
+			require.NoError(t, os.MkdirAll(partDir, 0o755))
+			content := []byte("0123456789abcdef-tail")
+			require.NoError(t, os.WriteFile(filepath.Join(partDir, "fv.bin"), content, 0o600))
+			require.NoError(t, os.WriteFile(filepath.Join(partDir, "metadata.json"), []byte("{}"), 0o600))
+			assert.False(t, tfs.IsOffloaded(partDir))
+
+			n, err := tfs.OffloadDir(context.Background(), partDir, func(name string) bool { return name == "metadata.json" })
+			require.NoError(t, err)
+			assert.Equal(t, uint64(len(content)), n)
+			assert.True(t, tfs.IsOffloaded(partDir))
+			_, err = os.Stat(filepath.Join(partDir, "fv.bin"))
+			assert.True(t, os.IsNotExist(err))
+			_, err = os.Stat(filepath.Join(remoteDir, "seg-20240101", "shard-0", "0000000000000001", "fv.bin"))
+			require.NoError(t, err)
+
+			var names []string
+			for _, e := range tfs.ReadDir(partDir) {
+				names = append(names, e.Name())
+			}
+			assert.Equal(t, []string{"fv.bin", "metadata.json", ManifestFilename}, names)
+			assert.True(t, tfs.IsExist(filepath.Join(partDir, "fv.bin")))
+
+			data, err := tfs.Read(filepath.Join(partDir, "fv.bin"))
+			require.NoError(t, err)
+			assert.Equal(t, content, data)
+
+			f, err := tfs.OpenFile(filepath.Join(partDir, "fv.bin"))
+			require.NoError(t, err)
+			size, err := f.Size()
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(content)), size)
+			buf := make([]byte, 7)
+			fs.MustReadData(f, 3, buf)
+			assert.Equal(t, content[3:10], buf)
+			iov := [][]byte{make([]byte, 2), make([]byte, 5)}
+			rn, err := f.Readv(9, &iov)
+			require.NoError(t, err)
+			assert.Equal(t, 7, rn)
+			assert.Equal(t, content[9:11], iov[0])
+			assert.Equal(t, content[11:16], iov[1])
+			all, err := io.ReadAll(f.SequentialRead())
+			require.NoError(t, err)
+			assert.Equal(t, content, all)
+			_, err = f.Write([]byte("x"))
+			assert.Error(t, err)
+			assert.NoError(t, f.Close())
+			assert.NotZero(t, cache.Hits())
+
+			_, err = tfs.OpenFile(filepath.Join(partDir, "missing.bin"))
+			assert.True(t, isNotExist(err))
+
+			tfs.MustRMAll(filepath.Join(root, "seg-20240101"))
+			_, err = os.Stat(filepath.Join(remoteDir, "seg-20240101", "shard-0", "0000000000000001", "fv.bin"))
+			assert.True(t, os.IsNotExist(err))
+			assert.False(t, tfs.IsExist(filepath.Join(partDir, "fv.bin")))
+		})
+	}
+}
+
+func TestFileSystem_Reopen(t *testing.T) {
+	root := t.TempDir()
+	remoteFS, err := local.NewFS(t.TempDir())
+	require.NoError(t, err)
+	partDir := filepath.Join(root, "seg-20240101", "shard-0", "0000000000000002")
+	require.NoError(t, os.MkdirAll(partDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(partDir, "timestamps.bin"), []byte("timestamps"), 0o600))
+
+	tfs := NewFileSystem(fs.NewLocalFileSystem(), remoteFS, nil, root, logger.GetLogger("test"))
+	_, err = tfs.OffloadDir(context.Background(), partDir, nil)
+	require.NoError(t, err)
+
+	// A new instance discovers the offloaded files from the manifest left on disk.
+	tfs = NewFileSystem(fs.NewLocalFileSystem(), remoteFS, nil, root, logger.GetLogger("test"))
+	data, err := tfs.Read(filepath.Join(partDir, "timestamps.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("timestamps"), data)
+}
@@ -786,6 +786,8 @@ func (fk *FieldKey) UnmarshalAll(data []byte) error {
 type Batch struct {
 	PersistentCallback func(error)
 	Documents          Documents
+	// Update replaces the documents with the same IDs instead of inserting new ones.
+	Update bool
 }
 
 // Writer allows writing fields and docID in a document to an index.
@@ -829,6 +831,9 @@ type Store interface {
 	Reset()
 	TakeFileSnapshot(dst string) error
 	Stats() (dataCount int64, dataSizeBytes int64)
+	FieldSize(fieldKey FieldKey) int64
+	// Terms returns the distinct terms of a field starting with the prefix in ascending order.
+	Terms(fieldKey FieldKey, prefix []byte) ([][]byte, error)
 }
 
 // Series represents a series in an index.
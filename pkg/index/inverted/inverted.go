@@ -19,6 +19,7 @@
 package inverted
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"log"
@@ -174,6 +175,9 @@ func releaseBatch(b *blugeIndex.Batch) {
 	batchPool.Put(b)
 }
 
+// estimatedPostingBytes is the estimated bytes of an item in a posting list.
+const estimatedPostingBytes = 4
+
 func (s *store) Batch(batch index.Batch) error {
 	if !s.closer.AddRunning() {
 		return nil
@@ -211,6 +215,10 @@ func (s *store) Batch(batch index.Batch) error {
 		if d.Timestamp > 0 {
 			doc.AddField(bluge.NewDateTimeField(timestampField, time.Unix(0, d.Timestamp)).StoreValue())
 		}
+		if batch.Update {
+			b.Update(doc.ID(), doc)
+			continue
+		}
 		b.Insert(doc)
 	}
 	return s.writer.Batch(b)
@@ -527,6 +535,60 @@ func (s *store) Stats() (dataCount int64, dataSizeBytes int64) {
 	return int64(count), int64(status.CurOnDiskBytes)
 }
 
+// FieldSize estimates the bytes a field takes on disk by the lengths of its terms and postings.
+func (s *store) FieldSize(fieldKey index.FieldKey) int64 {
+	reader, err := s.writer.Reader()
+	if err != nil {
+		return 0
+	}
+	defer reader.Close()
+	dict, err := reader.DictionaryIterator(fieldKey.Marshal(), nil, nil, nil)
+	if err != nil {
+		return 0
+	}
+	defer dict.Close()
+	var size int64
+	for {
+		de, err := dict.Next()
+		if err != nil || de == nil {
+			return size
+		}
+		size += int64(len(de.Term())) + int64(de.Count())*estimatedPostingBytes
+	}
+}
+
+func (s *store) Terms(fieldKey index.FieldKey, prefix []byte) ([][]byte, error) {
+	reader, err := s.writer.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	var start []byte
+	if len(prefix) > 0 {
+		start = prefix
+	}
+	dict, err := reader.DictionaryIterator(fieldKey.Marshal(), nil, start, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer dict.Close()
+	var terms [][]byte
+	for {
+		de, err := dict.Next()
+		if err != nil {
+			return nil, err
+		}
+		if de == nil {
+			return terms, nil
+		}
+		term := []byte(de.Term())
+		if !bytes.HasPrefix(term, prefix) {
+			return terms, nil
+		}
+		terms = append(terms, term)
+	}
+}
+
 type blugeMatchIterator struct {
 	delegated     search.DocumentMatchIterator
 	err           error
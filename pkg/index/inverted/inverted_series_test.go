@@ -493,6 +493,37 @@ func TestStore_SearchWithSecondaryQuery(t *testing.T) {
 	}
 }
 
+func TestStore_SearchRecordsPostings(t *testing.T) {
+	tester := require.New(t)
+	path, fn := setUp(tester)
+	s, err := NewStore(StoreOpts{
+		Path:   path,
+		Logger: logger.GetLogger("test"),
+	})
+	tester.NoError(err)
+	defer func() {
+		tester.NoError(s.Close())
+		fn()
+	}()
+	insertData(tester, s)
+
+	rule := &databasev1.IndexRule{Metadata: &commonv1.Metadata{Name: "service_name", Id: fieldKeyServiceName.IndexRuleID}}
+	index.RemoveUsage(rule.Metadata.Id)
+	defer index.RemoveUsage(rule.Metadata.Id)
+	secondaryQuery := &queryNode{
+		query: recordPostings(bluge.NewTermQuery("svc2").SetField(fieldKeyServiceName.Marshal()), rule),
+		node:  newTermNode("svc2", rule),
+	}
+	query, err := s.BuildQuery([]index.SeriesMatcher{{Type: index.SeriesMatcherTypeExact, Match: []byte("test2")}}, secondaryQuery, nil)
+	tester.NoError(err)
+	_, err = s.Search(context.Background(), []index.FieldKey{fieldKeyServiceName}, query, 0)
+	tester.NoError(err)
+
+	usages := index.CollectUsages([]*databasev1.IndexRule{rule}, nil)
+	tester.Len(usages, 1)
+	assert.Equal(t, int64(1), usages[0].GetPostingListSize())
+}
+
 func TestStore_SeriesSort(t *testing.T) {
 	tester := require.New(t)
 	path, fn := setUp(tester)
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/search"
 	"github.com/pkg/errors"
 
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
@@ -59,6 +60,27 @@ func (g GlobalIndexError) Error() string { return g.IndexRule.String() }
 
 var _ index.Query = (*queryNode)(nil)
 
+// postingsQuery records the size of the posting list of its index rule whenever it's searched.
+type postingsQuery struct {
+	bluge.Query
+	indexRuleID uint32
+}
+
+func recordPostings(q bluge.Query, indexRule *databasev1.IndexRule) bluge.Query {
+	if indexRule.GetMetadata().GetName() == "" {
+		return q
+	}
+	return &postingsQuery{Query: q, indexRuleID: indexRule.GetMetadata().GetId()}
+}
+
+func (q *postingsQuery) Searcher(i search.Reader, options search.SearcherOptions) (search.Searcher, error) {
+	searcher, err := q.Query.Searcher(i, options)
+	if err == nil {
+		index.RecordPostings(q.indexRuleID, int(searcher.Count()))
+	}
+	return searcher, err
+}
+
 // queryNode is a wrapper for bluge.Query.
 type queryNode struct {
 	query bluge.Query
@@ -269,6 +291,13 @@ func buildIndexModeCriteria(criteria *modelv1.Criteria, schema logical.Schema, e
 func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexRule,
 	expr logical.LiteralExpr, fieldKey string,
 ) (*queryNode, error) {
+	// The property queries use the index rules made up from the tag names, which have no names.
+	if indexRule.GetMetadata().GetName() != "" {
+		index.RecordHit(indexRule.GetMetadata().GetId())
+	}
+	postings := func(q bluge.Query) bluge.Query {
+		return recordPostings(q, indexRule)
+	}
 	str := expr.String()
 	switch cond.Op {
 	case modelv1.Condition_BINARY_OP_GT:
@@ -276,7 +305,7 @@ func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexR
 		if len(bb) != 1 {
 			return nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "don't support multiple or null value: %s", cond)
 		}
-		query := bluge.NewTermRangeInclusiveQuery(convert.BytesToString(bb[0]), maxTerm, false, false).SetField(fieldKey)
+		query := postings(bluge.NewTermRangeInclusiveQuery(convert.BytesToString(bb[0]), maxTerm, false, false).SetField(fieldKey))
 		node := newTermRangeInclusiveNode(str, maxInf, false, false, indexRule, false)
 		return &queryNode{query, node}, nil
 	case modelv1.Condition_BINARY_OP_GE:
@@ -284,7 +313,7 @@ func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexR
 		if len(bb) != 1 {
 			return nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "don't support multiple or null value: %s", cond)
 		}
-		query := bluge.NewTermRangeInclusiveQuery(convert.BytesToString(bb[0]), maxTerm, true, false).SetField(fieldKey)
+		query := postings(bluge.NewTermRangeInclusiveQuery(convert.BytesToString(bb[0]), maxTerm, true, false).SetField(fieldKey))
 		node := newTermRangeInclusiveNode(str, maxInf, true, false, indexRule, false)
 		return &queryNode{query, node}, nil
 	case modelv1.Condition_BINARY_OP_LT:
@@ -292,7 +321,7 @@ func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexR
 		if len(bb) != 1 {
 			return nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "don't support multiple or null value: %s", cond)
 		}
-		query := bluge.NewTermRangeInclusiveQuery(minTerm, convert.BytesToString(bb[0]), false, false).SetField(fieldKey)
+		query := postings(bluge.NewTermRangeInclusiveQuery(minTerm, convert.BytesToString(bb[0]), false, false).SetField(fieldKey))
 		node := newTermRangeInclusiveNode(minInf, str, false, false, indexRule, false)
 		return &queryNode{query, node}, nil
 	case modelv1.Condition_BINARY_OP_LE:
@@ -300,7 +329,7 @@ func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexR
 		if len(bb) != 1 {
 			return nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "don't support multiple or null value: %s", cond)
 		}
-		query := bluge.NewTermRangeInclusiveQuery(minTerm, convert.BytesToString(bb[0]), false, true).SetField(fieldKey)
+		query := postings(bluge.NewTermRangeInclusiveQuery(minTerm, convert.BytesToString(bb[0]), false, true).SetField(fieldKey))
 		node := newTermRangeInclusiveNode(minInf, str, false, true, indexRule, false)
 		return &queryNode{query, node}, nil
 	case modelv1.Condition_BINARY_OP_EQ:
@@ -308,7 +337,7 @@ func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexR
 		if len(bb) != 1 {
 			return nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "don't support multiple or null value: %s", cond)
 		}
-		query := bluge.NewTermQuery(convert.BytesToString(bb[0])).SetField(fieldKey)
+		query := postings(bluge.NewTermQuery(convert.BytesToString(bb[0])).SetField(fieldKey))
 		node := newTermNode(str, indexRule)
 		return &queryNode{query, node}, nil
 	case modelv1.Condition_BINARY_OP_MATCH:
@@ -320,23 +349,41 @@ func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexR
 			return nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "don't support multiple or null value: %s", cond)
 		}
 		analyzer, operator := getMatchOptions(indexRule.Analyzer, cond.MatchOption)
-		query := bluge.NewMatchQuery(convert.BytesToString(bb[0])).SetField(fieldKey).SetAnalyzer(analyzer).SetOperator(operator)
+		query := postings(bluge.NewMatchQuery(convert.BytesToString(bb[0])).SetField(fieldKey).SetAnalyzer(analyzer).SetOperator(operator))
 		node := newMatchNode(str, indexRule)
 		return &queryNode{query, node}, nil
+	case modelv1.Condition_BINARY_OP_DESCENDANT_OF, modelv1.Condition_BINARY_OP_CHILD_OF:
+		if indexRule == nil || indexRule.Type != databasev1.IndexRule_TYPE_TREE {
+			return nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "tree index rule is mandatory for %s operation: %s", cond.Op, cond)
+		}
+		bb := expr.Bytes()
+		if len(bb) != 1 {
+			return nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "don't support multiple or null value: %s", cond)
+		}
+		fk := index.FieldKey{IndexRuleID: indexRule.Metadata.Id}
+		if cond.Op == modelv1.Condition_BINARY_OP_CHILD_OF {
+			fk = index.TreeParentKey(fk)
+		} else {
+			fk = index.TreeAncestorKey(fk)
+		}
+		path := index.TreePath(convert.BytesToString(bb[0]))
+		query := postings(bluge.NewTermQuery(path).SetField(fk.Marshal()))
+		node := newTreeNode(path, cond.Op == modelv1.Condition_BINARY_OP_CHILD_OF, indexRule)
+		return &queryNode{query, node}, nil
 	case modelv1.Condition_BINARY_OP_NE:
 		bb := expr.Bytes()
 		if len(bb) != 1 {
 			return nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "don't support multiple or null value: %s", cond)
 		}
 		query, node := bluge.NewBooleanQuery(), newMustNotNode()
-		query.AddMustNot(bluge.NewTermQuery(convert.BytesToString(bb[0])).SetField(fieldKey))
+		query.AddMustNot(postings(bluge.NewTermQuery(convert.BytesToString(bb[0])).SetField(fieldKey)))
 		node.SetSubNode(newTermNode(str, indexRule))
 		return &queryNode{query, node}, nil
 	case modelv1.Condition_BINARY_OP_HAVING:
 		bb, elements := expr.Bytes(), expr.Elements()
 		query, node := bluge.NewBooleanQuery(), newMustNode()
 		for _, b := range bb {
-			query.AddMust(bluge.NewTermQuery(string(b)).SetField(fieldKey))
+			query.AddMust(postings(bluge.NewTermQuery(string(b)).SetField(fieldKey)))
 		}
 		for _, e := range elements {
 			node.Append(newTermNode(e, indexRule))
@@ -346,7 +393,7 @@ func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexR
 		bb, elements := expr.Bytes(), expr.Elements()
 		subQuery, subNode := bluge.NewBooleanQuery(), newMustNode()
 		for _, b := range bb {
-			subQuery.AddMust(bluge.NewTermQuery(string(b)).SetField(fieldKey))
+			subQuery.AddMust(postings(bluge.NewTermQuery(string(b)).SetField(fieldKey)))
 		}
 		for _, e := range elements {
 			subNode.Append(newTermNode(e, indexRule))
@@ -360,7 +407,7 @@ func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexR
 		query, node := bluge.NewBooleanQuery(), newShouldNode()
 		query.SetMinShould(1)
 		for _, b := range bb {
-			query.AddShould(bluge.NewTermQuery(string(b)).SetField(fieldKey))
+			query.AddShould(postings(bluge.NewTermQuery(string(b)).SetField(fieldKey)))
 		}
 		for _, e := range elements {
 			node.Append(newTermNode(e, indexRule))
@@ -371,7 +418,7 @@ func parseConditionToQuery(cond *modelv1.Condition, indexRule *databasev1.IndexR
 		subQuery, subNode := bluge.NewBooleanQuery(), newShouldNode()
 		subQuery.SetMinShould(1)
 		for _, b := range bb {
-			subQuery.AddShould(bluge.NewTermQuery(string(b)).SetField(fieldKey))
+			subQuery.AddShould(postings(bluge.NewTermQuery(string(b)).SetField(fieldKey)))
 		}
 		for _, e := range elements {
 			subNode.Append(newTermNode(e, indexRule))
@@ -574,6 +621,37 @@ func (m *matchNode) String() string {
 	return convert.JSONToString(m)
 }
 
+type treeNode struct {
+	indexRule *databasev1.IndexRule
+	path      string
+	children  bool
+}
+
+func newTreeNode(path string, children bool, indexRule *databasev1.IndexRule) *treeNode {
+	return &treeNode{
+		indexRule: indexRule,
+		path:      path,
+		children:  children,
+	}
+}
+
+func (t *treeNode) MarshalJSON() ([]byte, error) {
+	inner := make(map[string]interface{}, 1)
+	inner["index"] = t.indexRule.Metadata.Name + ":" + t.indexRule.Metadata.Group
+	inner["value"] = t.path
+	data := make(map[string]interface{}, 1)
+	if t.children {
+		data["childOf"] = inner
+	} else {
+		data["descendantOf"] = inner
+	}
+	return json.Marshal(data)
+}
+
+func (t *treeNode) String() string {
+	return convert.JSONToString(t)
+}
+
 type prefixNode struct {
 	prefix string
 }
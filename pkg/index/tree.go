@@ -0,0 +1,99 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package index
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// TreePathSeparator separates the levels of a path indexed by a tree index rule.
+	TreePathSeparator = "/"
+
+	treeAncestorPrefix = "_tree_ancestor_"
+	treeParentPrefix   = "_tree_parent_"
+)
+
+// TreePath normalizes a path by removing the trailing separators.
+func TreePath(path string) string {
+	trimmed := strings.TrimRight(path, TreePathSeparator)
+	if trimmed == "" && path != "" {
+		return TreePathSeparator
+	}
+	return trimmed
+}
+
+// TreeAncestors returns the ancestors of a path from the root to its parent.
+// A path starting with the separator has the separator as its root.
+func TreeAncestors(path string) []string {
+	path = TreePath(path)
+	var ancestors []string
+	for i := 0; i < len(path); i++ {
+		if path[i] != TreePathSeparator[0] {
+			continue
+		}
+		if i == 0 {
+			if len(path) > 1 {
+				ancestors = append(ancestors, TreePathSeparator)
+			}
+			continue
+		}
+		ancestors = append(ancestors, path[:i])
+	}
+	return ancestors
+}
+
+// TreeAncestorKey returns the key of the field holding the ancestors of a path.
+func TreeAncestorKey(key FieldKey) FieldKey {
+	return FieldKey{
+		TagName:   treeAncestorPrefix + strconv.FormatUint(uint64(key.IndexRuleID), 10),
+		SeriesID:  key.SeriesID,
+		TimeRange: key.TimeRange,
+	}
+}
+
+// TreeParentKey returns the key of the field holding the parent of a path.
+func TreeParentKey(key FieldKey) FieldKey {
+	return FieldKey{
+		TagName:   treeParentPrefix + strconv.FormatUint(uint64(key.IndexRuleID), 10),
+		SeriesID:  key.SeriesID,
+		TimeRange: key.TimeRange,
+	}
+}
+
+// NewTreeFields creates the fields locating a path in the tree: one field per ancestor and one for the parent.
+// The path itself is indexed by the field of the index rule.
+func NewTreeFields(key FieldKey, path string) []Field {
+	ancestors := TreeAncestors(path)
+	if len(ancestors) == 0 {
+		return nil
+	}
+	fields := make([]Field, 0, len(ancestors)+1)
+	ancestorKey := TreeAncestorKey(key)
+	for _, a := range ancestors {
+		f := NewStringField(ancestorKey, a)
+		f.Index = true
+		f.NoSort = true
+		fields = append(fields, f)
+	}
+	f := NewStringField(TreeParentKey(key), ancestors[len(ancestors)-1])
+	f.Index = true
+	f.NoSort = true
+	return append(fields, f)
+}
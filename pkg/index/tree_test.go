@@ -0,0 +1,66 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeAncestors(t *testing.T) {
+	tests := []struct {
+		path      string
+		ancestors []string
+	}{
+		{path: "region", ancestors: nil},
+		{path: "region/zone/host", ancestors: []string{"region", "region/zone"}},
+		{path: "region/zone/", ancestors: []string{"region"}},
+		{path: "/", ancestors: nil},
+		{path: "/api", ancestors: []string{"/"}},
+		{path: "/api/v1/users", ancestors: []string{"/", "/api", "/api/v1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.ancestors, TreeAncestors(tt.path))
+		})
+	}
+}
+
+func TestTreePath(t *testing.T) {
+	assert.Equal(t, "region/zone", TreePath("region/zone/"))
+	assert.Equal(t, "/", TreePath("//"))
+	assert.Equal(t, "", TreePath(""))
+}
+
+func TestNewTreeFields(t *testing.T) {
+	key := FieldKey{IndexRuleID: 1, SeriesID: 2}
+	fields := NewTreeFields(key, "region/zone/host")
+	require.Len(t, fields, 3)
+	ancestorKey, parentKey := TreeAncestorKey(key), TreeParentKey(key)
+	assert.Equal(t, ancestorKey, fields[0].Key)
+	assert.Equal(t, "region", string(fields[0].GetBytes()))
+	assert.Equal(t, ancestorKey, fields[1].Key)
+	assert.Equal(t, "region/zone", string(fields[1].GetBytes()))
+	assert.Equal(t, parentKey, fields[2].Key)
+	assert.Equal(t, "region/zone", string(fields[2].GetBytes()))
+	assert.NotEqual(t, ancestorKey.Marshal(), parentKey.Marshal())
+	assert.NotEqual(t, key.Marshal(), ancestorKey.Marshal())
+	assert.Empty(t, NewTreeFields(key, "region"))
+}
@@ -0,0 +1,90 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package index
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+)
+
+var (
+	usages       sync.Map
+	trackedSince = time.Now()
+)
+
+type usageCounter struct {
+	hits     atomic.Int64
+	postings atomic.Int64
+	lastUsed atomic.Int64
+}
+
+func loadUsageCounter(indexRuleID uint32) *usageCounter {
+	if c, ok := usages.Load(indexRuleID); ok {
+		return c.(*usageCounter)
+	}
+	c, _ := usages.LoadOrStore(indexRuleID, &usageCounter{})
+	return c.(*usageCounter)
+}
+
+// RecordHit records a query using the index rule.
+func RecordHit(indexRuleID uint32) {
+	c := loadUsageCounter(indexRuleID)
+	c.hits.Add(1)
+	c.lastUsed.Store(time.Now().UnixNano())
+}
+
+// RecordPostings records the size of a posting list the index rule returns.
+func RecordPostings(indexRuleID uint32, size int) {
+	loadUsageCounter(indexRuleID).postings.Add(int64(size))
+}
+
+// RemoveUsage drops the counters of a deleted index rule.
+func RemoveUsage(indexRuleID uint32) {
+	usages.Delete(indexRuleID)
+}
+
+// CollectUsages returns how the queries on this node use the index rules.
+// sizes holds the estimated bytes of the index rules on disk.
+func CollectUsages(rules []*databasev1.IndexRule, sizes map[uint32]int64) []*databasev1.IndexRuleUsage {
+	result := make([]*databasev1.IndexRuleUsage, 0, len(rules))
+	for _, r := range rules {
+		id := r.GetMetadata().GetId()
+		u := &databasev1.IndexRuleUsage{
+			IndexRule:       r.GetMetadata().GetName(),
+			DiskSizeBytes:   sizes[id],
+			TrackedSince:    timestamppb.New(trackedSince),
+			PostingListSize: proto.Int64(0),
+		}
+		if v, ok := usages.Load(id); ok {
+			c := v.(*usageCounter)
+			u.QueryHits = c.hits.Load()
+			u.PostingListSize = proto.Int64(c.postings.Load())
+			if lastUsed := c.lastUsed.Load(); lastUsed > 0 {
+				u.LastUsedAt = timestamppb.New(time.Unix(0, lastUsed))
+			}
+		}
+		result = append(result, u)
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+)
+
+func TestCollectUsages(t *testing.T) {
+	used := &databasev1.IndexRule{Metadata: &commonv1.Metadata{Name: "used", Id: 1001}}
+	unused := &databasev1.IndexRule{Metadata: &commonv1.Metadata{Name: "unused", Id: 1002}}
+	RecordHit(1001)
+	RecordPostings(1001, 10)
+	RecordPostings(1001, 5)
+	RecordHit(1001)
+
+	usages := CollectUsages([]*databasev1.IndexRule{used, unused}, map[uint32]int64{1002: 128})
+	require.Len(t, usages, 2)
+	assert.Equal(t, "used", usages[0].GetIndexRule())
+	assert.Equal(t, int64(2), usages[0].GetQueryHits())
+	require.NotNil(t, usages[0].PostingListSize)
+	assert.Equal(t, int64(15), usages[0].GetPostingListSize())
+	assert.NotNil(t, usages[0].GetLastUsedAt())
+	assert.Equal(t, "unused", usages[1].GetIndexRule())
+	assert.Zero(t, usages[1].GetQueryHits())
+	assert.Nil(t, usages[1].GetLastUsedAt())
+	assert.Equal(t, int64(128), usages[1].GetDiskSizeBytes())
+	assert.NotNil(t, usages[1].GetTrackedSince())
+	require.NotNil(t, usages[1].PostingListSize)
+	assert.Zero(t, usages[1].GetPostingListSize())
+
+	// The counters of a deleted index rule are dropped.
+	RemoveUsage(1001)
+	usages = CollectUsages([]*databasev1.IndexRule{used}, nil)
+	require.Len(t, usages, 1)
+	assert.Zero(t, usages[0].GetQueryHits())
+	assert.Zero(t, usages[0].GetPostingListSize())
+	assert.Nil(t, usages[0].GetLastUsedAt())
+}
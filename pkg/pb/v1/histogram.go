@@ -0,0 +1,138 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
+)
+
+const (
+	minHistogramScale = -10
+	maxHistogramScale = 20
+)
+
+var errMalformedHistogram = errors.New("histogram is malformed")
+
+// HistogramFromProto converts src to h. It returns an error if the buckets of src are malformed.
+func HistogramFromProto(h *encoding.Histogram, src *modelv1.Histogram) error {
+	h.Reset()
+	h.Count = src.GetCount()
+	h.Sum = src.GetSum()
+	var total uint64
+	switch buckets := src.GetBuckets().(type) {
+	case *modelv1.Histogram_Explicit:
+		bounds, counts := buckets.Explicit.GetBounds(), buckets.Explicit.GetCounts()
+		if len(counts) != len(bounds)+1 {
+			return errors.WithMessagef(errMalformedHistogram, "%d bounds require %d bucket counts, got %d", len(bounds), len(bounds)+1, len(counts))
+		}
+		for i, b := range bounds {
+			if math.IsNaN(b) || math.IsInf(b, 0) || (i > 0 && b <= bounds[i-1]) {
+				return errors.WithMessagef(errMalformedHistogram, "the bounds must be finite and ascending, got %v", bounds)
+			}
+		}
+		h.Kind = encoding.HistogramKindExplicit
+		h.Bounds = append(h.Bounds, bounds...)
+		h.Counts = append(h.Counts, counts...)
+		for _, c := range counts {
+			total += c
+		}
+	case *modelv1.Histogram_Exponential:
+		e := buckets.Exponential
+		if e.GetScale() < minHistogramScale || e.GetScale() > maxHistogramScale {
+			return errors.WithMessagef(errMalformedHistogram, "the scale %d is out of [%d, %d]", e.GetScale(), minHistogramScale, maxHistogramScale)
+		}
+		if math.IsNaN(e.GetZeroThreshold()) || e.GetZeroThreshold() < 0 {
+			return errors.WithMessagef(errMalformedHistogram, "the zero threshold %v is invalid", e.GetZeroThreshold())
+		}
+		h.Kind = encoding.HistogramKindExponential
+		h.Scale = e.GetScale()
+		h.ZeroThreshold = e.GetZeroThreshold()
+		h.ZeroCount = e.GetZeroCount()
+		h.PositiveOffset = e.GetPositiveOffset()
+		h.PositiveCounts = append(h.PositiveCounts, e.GetPositiveCounts()...)
+		h.NegativeOffset = e.GetNegativeOffset()
+		h.NegativeCounts = append(h.NegativeCounts, e.GetNegativeCounts()...)
+		total = h.ZeroCount
+		for _, c := range h.PositiveCounts {
+			total += c
+		}
+		for _, c := range h.NegativeCounts {
+			total += c
+		}
+	default:
+		return errors.WithMessage(errMalformedHistogram, "the buckets are absent")
+	}
+	if h.Count == 0 {
+		h.Count = total
+	}
+	return nil
+}
+
+// HistogramToProto converts h to its protobuf form.
+func HistogramToProto(h *encoding.Histogram) *modelv1.Histogram {
+	result := &modelv1.Histogram{
+		Count: h.Count,
+		Sum:   h.Sum,
+	}
+	switch h.Kind {
+	case encoding.HistogramKindExplicit:
+		result.Buckets = &modelv1.Histogram_Explicit{Explicit: &modelv1.ExplicitBuckets{
+			Bounds: append([]float64(nil), h.Bounds...),
+			Counts: append([]uint64(nil), h.Counts...),
+		}}
+	case encoding.HistogramKindExponential:
+		result.Buckets = &modelv1.Histogram_Exponential{Exponential: &modelv1.ExponentialBuckets{
+			Scale:          h.Scale,
+			ZeroThreshold:  h.ZeroThreshold,
+			ZeroCount:      h.ZeroCount,
+			PositiveOffset: h.PositiveOffset,
+			PositiveCounts: append([]uint64(nil), h.PositiveCounts...),
+			NegativeOffset: h.NegativeOffset,
+			NegativeCounts: append([]uint64(nil), h.NegativeCounts...),
+		}}
+	}
+	return result
+}
+
+// MarshalHistogram appends the binary form of src to dst.
+func MarshalHistogram(dst []byte, src *modelv1.Histogram) ([]byte, error) {
+	var h encoding.Histogram
+	if err := HistogramFromProto(&h, src); err != nil {
+		return dst, err
+	}
+	return h.Marshal(dst), nil
+}
+
+// UnmarshalHistogram decodes the binary form produced by MarshalHistogram.
+func UnmarshalHistogram(src []byte) (*modelv1.Histogram, error) {
+	var h encoding.Histogram
+	if err := h.Unmarshal(src); err != nil {
+		return nil, errors.WithMessage(errMalformedField, err.Error())
+	}
+	return HistogramToProto(&h), nil
+}
+
+// HistogramFieldValue wraps h as a field value.
+func HistogramFieldValue(h *encoding.Histogram) *modelv1.FieldValue {
+	return &modelv1.FieldValue{Value: &modelv1.FieldValue_Histogram{Histogram: HistogramToProto(h)}}
+}
@@ -65,6 +65,8 @@ func FieldValueTypeConv(fieldValue *modelv1.FieldValue) (tagType databasev1.Fiel
 		return databasev1.FieldType_FIELD_TYPE_STRING, false
 	case *modelv1.FieldValue_BinaryData:
 		return databasev1.FieldType_FIELD_TYPE_DATA_BINARY, false
+	case *modelv1.FieldValue_Histogram:
+		return databasev1.FieldType_FIELD_TYPE_HISTOGRAM, false
 	case *modelv1.FieldValue_Null:
 		return databasev1.FieldType_FIELD_TYPE_UNSPECIFIED, true
 	}
@@ -196,6 +196,15 @@ func DecodeFieldValue(fieldValue []byte, fieldSpec *databasev1.FieldSpec) (*mode
 		return &modelv1.FieldValue{Value: &modelv1.FieldValue_Float{Float: &modelv1.Float{Value: convert.BytesToFloat64(fieldValue)}}}, nil
 	case databasev1.FieldType_FIELD_TYPE_DATA_BINARY:
 		return &modelv1.FieldValue{Value: &modelv1.FieldValue_BinaryData{BinaryData: fieldValue}}, nil
+	case databasev1.FieldType_FIELD_TYPE_HISTOGRAM:
+		if len(fieldValue) == 0 {
+			return NullFieldValue, nil
+		}
+		h, err := UnmarshalHistogram(fieldValue)
+		if err != nil {
+			return nil, err
+		}
+		return &modelv1.FieldValue{Value: &modelv1.FieldValue_Histogram{Histogram: h}}, nil
 	}
 	return &modelv1.FieldValue{Value: &modelv1.FieldValue_Null{}}, nil
 }
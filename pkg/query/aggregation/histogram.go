@@ -0,0 +1,281 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package aggregation
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
+)
+
+// MergeHistogram merges the buckets of src into dst.
+//
+// The explicit histograms having different bounds are merged into the buckets split by their common bounds.
+// The exponential histograms having different scales are merged at the coarser scale,
+// and the buckets under the wider zero threshold are merged into the zero bucket.
+func MergeHistogram(dst, src *encoding.Histogram) error {
+	if src.Kind == encoding.HistogramKindUnknown {
+		return nil
+	}
+	if dst.Kind == encoding.HistogramKindUnknown {
+		dst.CopyFrom(src)
+		return nil
+	}
+	if dst.Kind != src.Kind {
+		return errors.WithMessage(encoding.ErrIncompatibleHistogram, "cannot merge explicit and exponential buckets")
+	}
+	if dst.Kind == encoding.HistogramKindExplicit {
+		mergeExplicitBuckets(dst, src)
+	} else {
+		mergeExponentialBuckets(dst, src)
+	}
+	dst.Count += src.Count
+	dst.Sum += src.Sum
+	return nil
+}
+
+func mergeExplicitBuckets(dst, src *encoding.Histogram) {
+	if !equalBounds(dst.Bounds, src.Bounds) {
+		var bounds []float64
+		for _, b := range dst.Bounds {
+			if i := sort.SearchFloat64s(src.Bounds, b); i < len(src.Bounds) && src.Bounds[i] == b {
+				bounds = append(bounds, b)
+			}
+		}
+		dst.Counts = rebucket(dst.Bounds, dst.Counts, bounds)
+		dst.Bounds = bounds
+		for i, c := range rebucket(src.Bounds, src.Counts, bounds) {
+			dst.Counts[i] += c
+		}
+		return
+	}
+	for i, c := range src.Counts {
+		dst.Counts[i] += c
+	}
+}
+
+// rebucket moves the counts of the buckets split by bounds into the coarser buckets split by target,
+// which must be a subset of bounds.
+func rebucket(bounds []float64, counts []uint64, target []float64) []uint64 {
+	result := make([]uint64, len(target)+1)
+	j := 0
+	for i, c := range counts {
+		if i == len(bounds) {
+			j = len(target)
+		}
+		for j < len(target) && bounds[i] > target[j] {
+			j++
+		}
+		result[j] += c
+	}
+	return result
+}
+
+func equalBounds(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeExponentialBuckets(dst, src *encoding.Histogram) {
+	other := &encoding.Histogram{}
+	other.CopyFrom(src)
+	if dst.Scale > other.Scale {
+		downscale(dst, dst.Scale-other.Scale)
+	} else if other.Scale > dst.Scale {
+		downscale(other, other.Scale-dst.Scale)
+	}
+	if dst.ZeroThreshold < other.ZeroThreshold {
+		widenZeroBucket(dst, other.ZeroThreshold)
+	} else if other.ZeroThreshold < dst.ZeroThreshold {
+		widenZeroBucket(other, dst.ZeroThreshold)
+	}
+	dst.ZeroCount += other.ZeroCount
+	dst.PositiveOffset, dst.PositiveCounts = addBuckets(dst.PositiveOffset, dst.PositiveCounts, other.PositiveOffset, other.PositiveCounts)
+	dst.NegativeOffset, dst.NegativeCounts = addBuckets(dst.NegativeOffset, dst.NegativeCounts, other.NegativeOffset, other.NegativeCounts)
+}
+
+// downscale lowers the scale of h by delta, merging every 2^delta adjacent buckets.
+func downscale(h *encoding.Histogram, delta int32) {
+	h.Scale -= delta
+	h.PositiveOffset, h.PositiveCounts = downscaleBuckets(h.PositiveOffset, h.PositiveCounts, delta)
+	h.NegativeOffset, h.NegativeCounts = downscaleBuckets(h.NegativeOffset, h.NegativeCounts, delta)
+}
+
+func downscaleBuckets(offset int32, counts []uint64, delta int32) (int32, []uint64) {
+	if len(counts) == 0 {
+		return offset >> delta, counts
+	}
+	newOffset := offset >> delta
+	result := make([]uint64, ((offset+int32(len(counts))-1)>>delta)-newOffset+1)
+	for i, c := range counts {
+		result[((offset+int32(i))>>delta)-newOffset] += c
+	}
+	return newOffset, result
+}
+
+// widenZeroBucket merges the buckets whose upper bounds are within threshold into the zero bucket.
+func widenZeroBucket(h *encoding.Histogram, threshold float64) {
+	h.ZeroThreshold = threshold
+	h.PositiveOffset, h.PositiveCounts = foldBuckets(h, h.PositiveOffset, h.PositiveCounts, threshold)
+	h.NegativeOffset, h.NegativeCounts = foldBuckets(h, h.NegativeOffset, h.NegativeCounts, threshold)
+}
+
+func foldBuckets(h *encoding.Histogram, offset int32, counts []uint64, threshold float64) (int32, []uint64) {
+	base := exponentialBase(h.Scale)
+	n := 0
+	for n < len(counts) && math.Pow(base, float64(offset+int32(n)+1)) <= threshold {
+		h.ZeroCount += counts[n]
+		n++
+	}
+	return offset + int32(n), counts[n:]
+}
+
+func addBuckets(offsetA int32, countsA []uint64, offsetB int32, countsB []uint64) (int32, []uint64) {
+	if len(countsB) == 0 {
+		return offsetA, countsA
+	}
+	if len(countsA) == 0 {
+		return offsetB, append([]uint64(nil), countsB...)
+	}
+	offset := min(offsetA, offsetB)
+	end := max(offsetA+int32(len(countsA)), offsetB+int32(len(countsB)))
+	result := make([]uint64, end-offset)
+	for i, c := range countsA {
+		result[offsetA-offset+int32(i)] += c
+	}
+	for i, c := range countsB {
+		result[offsetB-offset+int32(i)] += c
+	}
+	return offset, result
+}
+
+func exponentialBase(scale int32) float64 {
+	return math.Pow(2, math.Pow(2, -float64(scale)))
+}
+
+// HistogramQuantile estimates the q-quantile of the observations in h, like the histogram_quantile of PromQL.
+//
+// It interpolates linearly within an explicit bucket, and exponentially within an exponential bucket.
+// It returns NaN if h has no observation, -Inf if q < 0, and +Inf if q > 1.
+// If the quantile falls into the +Inf bucket of an explicit histogram, the highest bound is returned.
+func HistogramQuantile(h *encoding.Histogram, q float64) float64 {
+	if math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(1)
+	}
+	switch h.Kind {
+	case encoding.HistogramKindExplicit:
+		return explicitQuantile(h, q)
+	case encoding.HistogramKindExponential:
+		return exponentialQuantile(h, q)
+	default:
+		return math.NaN()
+	}
+}
+
+func explicitQuantile(h *encoding.Histogram, q float64) float64 {
+	var total uint64
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total == 0 {
+		return math.NaN()
+	}
+	if len(h.Bounds) == 0 {
+		return math.Inf(1)
+	}
+	rank := q * float64(total)
+	var cumulative uint64
+	for i, c := range h.Counts {
+		if float64(cumulative+c) < rank || c == 0 {
+			cumulative += c
+			continue
+		}
+		if i == len(h.Bounds) {
+			return h.Bounds[len(h.Bounds)-1]
+		}
+		upper := h.Bounds[i]
+		var lower float64
+		switch {
+		case i > 0:
+			lower = h.Bounds[i-1]
+		case upper <= 0:
+			return upper
+		}
+		return lower + (upper-lower)*(rank-float64(cumulative))/float64(c)
+	}
+	return h.Bounds[len(h.Bounds)-1]
+}
+
+func exponentialQuantile(h *encoding.Histogram, q float64) float64 {
+	total := h.ZeroCount
+	for _, c := range h.PositiveCounts {
+		total += c
+	}
+	for _, c := range h.NegativeCounts {
+		total += c
+	}
+	if total == 0 {
+		return math.NaN()
+	}
+	base := exponentialBase(h.Scale)
+	rank := q * float64(total)
+	var cumulative float64
+	// The negative buckets go from the most negative one, which has the highest index.
+	for i := len(h.NegativeCounts) - 1; i >= 0; i-- {
+		c := float64(h.NegativeCounts[i])
+		if c > 0 && cumulative+c >= rank {
+			idx := float64(h.NegativeOffset + int32(i))
+			lower, upper := math.Pow(base, idx+1), math.Pow(base, idx)
+			return -lower * math.Pow(upper/lower, (rank-cumulative)/c)
+		}
+		cumulative += c
+	}
+	if c := float64(h.ZeroCount); c > 0 && cumulative+c >= rank {
+		return -h.ZeroThreshold + 2*h.ZeroThreshold*(rank-cumulative)/c
+	}
+	cumulative += float64(h.ZeroCount)
+	for i, count := range h.PositiveCounts {
+		c := float64(count)
+		if c > 0 && cumulative+c >= rank {
+			idx := float64(h.PositiveOffset + int32(i))
+			lower, upper := math.Pow(base, idx), math.Pow(base, idx+1)
+			return lower * math.Pow(upper/lower, (rank-cumulative)/c)
+		}
+		cumulative += c
+	}
+	if n := len(h.PositiveCounts); n > 0 {
+		return math.Pow(base, float64(h.PositiveOffset+int32(n)))
+	}
+	return h.ZeroThreshold
+}
@@ -0,0 +1,107 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package aggregation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
+)
+
+func explicitHistogram(bounds []float64, counts ...uint64) *encoding.Histogram {
+	h := &encoding.Histogram{Kind: encoding.HistogramKindExplicit, Bounds: bounds, Counts: counts}
+	for _, c := range counts {
+		h.Count += c
+	}
+	return h
+}
+
+func TestMergeExplicitHistogram(t *testing.T) {
+	dst := &encoding.Histogram{}
+	require.NoError(t, MergeHistogram(dst, explicitHistogram([]float64{1, 2, 4}, 1, 2, 3, 4)))
+	require.NoError(t, MergeHistogram(dst, explicitHistogram([]float64{1, 2, 4}, 1, 1, 1, 1)))
+	assert.Equal(t, []float64{1, 2, 4}, dst.Bounds)
+	assert.Equal(t, []uint64{2, 3, 4, 5}, dst.Counts)
+	assert.Equal(t, uint64(14), dst.Count)
+
+	// the buckets are merged into the ones split by the common bounds
+	require.NoError(t, MergeHistogram(dst, explicitHistogram([]float64{2, 3}, 1, 1, 1)))
+	assert.Equal(t, []float64{2}, dst.Bounds)
+	assert.Equal(t, []uint64{6, 11}, dst.Counts)
+	assert.Equal(t, uint64(17), dst.Count)
+
+	err := MergeHistogram(dst, &encoding.Histogram{Kind: encoding.HistogramKindExponential})
+	assert.ErrorIs(t, err, encoding.ErrIncompatibleHistogram)
+}
+
+func TestMergeExponentialHistogram(t *testing.T) {
+	dst := &encoding.Histogram{
+		Kind: encoding.HistogramKindExponential, Scale: 1, ZeroCount: 1, Count: 7,
+		PositiveOffset: -1, PositiveCounts: []uint64{1, 2, 3},
+	}
+	src := &encoding.Histogram{
+		Kind: encoding.HistogramKindExponential, Scale: 0, ZeroCount: 2, Count: 6,
+		PositiveOffset: 1, PositiveCounts: []uint64{1}, NegativeOffset: 0, NegativeCounts: []uint64{3},
+	}
+	require.NoError(t, MergeHistogram(dst, src))
+	// the indexes -1, 0, 1 at the scale 1 become -1, 0, 0 at the scale 0
+	assert.Equal(t, int32(0), dst.Scale)
+	assert.Equal(t, int32(-1), dst.PositiveOffset)
+	assert.Equal(t, []uint64{1, 5, 1}, dst.PositiveCounts)
+	assert.Equal(t, int32(0), dst.NegativeOffset)
+	assert.Equal(t, []uint64{3}, dst.NegativeCounts)
+	assert.Equal(t, uint64(3), dst.ZeroCount)
+	assert.Equal(t, uint64(13), dst.Count)
+
+	// the buckets within the wider zero threshold are merged into the zero bucket
+	require.NoError(t, MergeHistogram(dst, &encoding.Histogram{Kind: encoding.HistogramKindExponential, ZeroThreshold: 1}))
+	assert.Equal(t, 1.0, dst.ZeroThreshold)
+	assert.Equal(t, uint64(4), dst.ZeroCount)
+	assert.Equal(t, int32(0), dst.PositiveOffset)
+	assert.Equal(t, []uint64{5, 1}, dst.PositiveCounts)
+	assert.Equal(t, []uint64{3}, dst.NegativeCounts)
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	h := explicitHistogram([]float64{1, 2, 4}, 10, 10, 20, 0)
+	assert.InDelta(t, 0.5, HistogramQuantile(h, 0.125), 1e-9)
+	assert.InDelta(t, 2, HistogramQuantile(h, 0.5), 1e-9)
+	assert.InDelta(t, 3, HistogramQuantile(h, 0.75), 1e-9)
+	assert.InDelta(t, 4, HistogramQuantile(h, 1), 1e-9)
+	assert.True(t, math.IsInf(HistogramQuantile(h, -1), -1))
+	assert.True(t, math.IsInf(HistogramQuantile(h, 2), 1))
+	assert.True(t, math.IsNaN(HistogramQuantile(explicitHistogram([]float64{1}, 0, 0), 0.5)))
+	// the quantile in the +Inf bucket is the highest bound
+	assert.InDelta(t, 1, HistogramQuantile(explicitHistogram([]float64{1}, 1, 9), 0.9), 1e-9)
+
+	e := &encoding.Histogram{
+		Kind: encoding.HistogramKindExponential, Scale: 0,
+		PositiveOffset: 0, PositiveCounts: []uint64{5, 5},
+		NegativeOffset: 0, NegativeCounts: []uint64{10},
+	}
+	// the negative bucket 0 covers [-2, -1)
+	assert.InDelta(t, -2, HistogramQuantile(e, 0), 1e-9)
+	// the positive bucket 0 covers (1, 2], and the bucket 1 covers (2, 4]
+	assert.InDelta(t, 2, HistogramQuantile(e, 0.75), 1e-9)
+	assert.InDelta(t, 4, HistogramQuantile(e, 1), 1e-9)
+	assert.InDelta(t, math.Sqrt(8), HistogramQuantile(e, 0.875), 1e-9)
+}
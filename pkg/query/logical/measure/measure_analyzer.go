@@ -123,6 +123,7 @@ func Analyze(criteria *measurev1.QueryRequest, metadata []*commonv1.Metadata, ss
 		plan = newUnresolvedAggregation(plan,
 			logical.NewField(criteria.GetAgg().GetFieldName()),
 			criteria.GetAgg().GetFunction(),
+			criteria.GetAgg().GetHistogramQuantile(),
 			criteria.GetGroupBy() != nil,
 			emitPartial,
 			false,
@@ -179,6 +180,7 @@ func DistributedAnalyze(criteria *measurev1.QueryRequest, ss []logical.Schema) (
 		plan = newUnresolvedAggregation(plan,
 			logical.NewField(criteria.GetAgg().GetFieldName()),
 			criteria.GetAgg().GetFunction(),
+			criteria.GetAgg().GetHistogramQuantile(),
 			criteria.GetGroupBy() != nil,
 			false,       // emitPartial: liaison does not emit partial
 			pushDownAgg, // reduceMode: only reduce partials when push-down is active (no TopN)
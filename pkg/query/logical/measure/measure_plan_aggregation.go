@@ -27,6 +27,8 @@ import (
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	measurev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/measure/v1"
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/pkg/encoding"
+	pbv1 "github.com/apache/skywalking-banyandb/pkg/pb/v1"
 	"github.com/apache/skywalking-banyandb/pkg/query/aggregation"
 	"github.com/apache/skywalking-banyandb/pkg/query/executor"
 	"github.com/apache/skywalking-banyandb/pkg/query/logical"
@@ -123,25 +125,63 @@ func (a *reduceAccumulator[N]) Reset() {
 	a.reduceFunc.Reset()
 }
 
+// histogramAccumulator implements aggAccumulator by merging the buckets of histograms.
+// It estimates the quantile of the merged histogram if required, except emitting the partial result.
+type histogramAccumulator struct {
+	quantile    *measurev1.QueryRequest_Aggregation_HistogramQuantile
+	merged      encoding.Histogram
+	histogram   encoding.Histogram
+	emitPartial bool
+}
+
+func (a *histogramAccumulator) Feed(dp *measurev1.DataPoint, fieldIdx int) error {
+	h := dp.GetFields()[fieldIdx].GetValue().GetHistogram()
+	if h == nil {
+		return nil
+	}
+	if err := pbv1.HistogramFromProto(&a.histogram, h); err != nil {
+		return err
+	}
+	return aggregation.MergeHistogram(&a.merged, &a.histogram)
+}
+
+func (a *histogramAccumulator) Result(fieldName string) ([]*measurev1.DataPoint_Field, error) {
+	if a.quantile != nil && !a.emitPartial {
+		return []*measurev1.DataPoint_Field{{Name: fieldName, Value: &modelv1.FieldValue{
+			Value: &modelv1.FieldValue_Float{Float: &modelv1.Float{Value: aggregation.HistogramQuantile(&a.merged, a.quantile.GetQuantile())}},
+		}}}, nil
+	}
+	if a.merged.Kind == encoding.HistogramKindUnknown {
+		return []*measurev1.DataPoint_Field{{Name: fieldName, Value: pbv1.NullFieldValue}}, nil
+	}
+	return []*measurev1.DataPoint_Field{{Name: fieldName, Value: pbv1.HistogramFieldValue(&a.merged)}}, nil
+}
+
+func (a *histogramAccumulator) Reset() {
+	a.merged.Reset()
+}
+
 type unresolvedAggregation struct {
-	unresolvedInput  logical.UnresolvedPlan
-	aggregationField *logical.Field
-	aggrFunc         modelv1.AggregationFunction
-	isGroup          bool
-	emitPartial      bool
-	reduceMode       bool
+	unresolvedInput   logical.UnresolvedPlan
+	aggregationField  *logical.Field
+	histogramQuantile *measurev1.QueryRequest_Aggregation_HistogramQuantile
+	aggrFunc          modelv1.AggregationFunction
+	isGroup           bool
+	emitPartial       bool
+	reduceMode        bool
 }
 
 func newUnresolvedAggregation(input logical.UnresolvedPlan, aggrField *logical.Field, aggrFunc modelv1.AggregationFunction,
-	isGroup bool, emitPartial bool, reduceMode bool,
+	histogramQuantile *measurev1.QueryRequest_Aggregation_HistogramQuantile, isGroup bool, emitPartial bool, reduceMode bool,
 ) logical.UnresolvedPlan {
 	return &unresolvedAggregation{
-		unresolvedInput:  input,
-		aggrFunc:         aggrFunc,
-		aggregationField: aggrField,
-		isGroup:          isGroup,
-		emitPartial:      emitPartial,
-		reduceMode:       reduceMode,
+		unresolvedInput:   input,
+		aggrFunc:          aggrFunc,
+		aggregationField:  aggrField,
+		histogramQuantile: histogramQuantile,
+		isGroup:           isGroup,
+		emitPartial:       emitPartial,
+		reduceMode:        reduceMode,
 	}
 }
 
@@ -160,11 +200,16 @@ func (gba *unresolvedAggregation) Analyze(measureSchema logical.Schema) (logical
 		return nil, errors.Wrap(errFieldNotDefined, "aggregation schema")
 	}
 	fieldRef := aggregationFieldRefs[0]
+	if gba.histogramQuantile != nil && fieldRef.Spec.Spec.FieldType != databasev1.FieldType_FIELD_TYPE_HISTOGRAM {
+		return nil, errors.WithMessagef(errUnsupportedAggregationField, "histogram_quantile requires a histogram field: %s", fieldRef.Spec.Spec)
+	}
 	switch fieldRef.Spec.Spec.FieldType {
 	case databasev1.FieldType_FIELD_TYPE_INT:
 		return newAggregationPlan[int64](gba, prevPlan, schema, fieldRef)
 	case databasev1.FieldType_FIELD_TYPE_FLOAT:
 		return newAggregationPlan[float64](gba, prevPlan, schema, fieldRef)
+	case databasev1.FieldType_FIELD_TYPE_HISTOGRAM:
+		return newHistogramAggregationPlan(gba, prevPlan, schema, fieldRef)
 	default:
 		return nil, errors.WithMessagef(errUnsupportedAggregationField, "field: %s", fieldRef.Spec.Spec)
 	}
@@ -196,6 +241,27 @@ func newAggregationPlan[N aggregation.Number](gba *unresolvedAggregation, prevPl
 		}
 		acc = &mapAccumulator[N]{mapFunc: mapFunc, aggrType: gba.aggrFunc, emitPartial: gba.emitPartial}
 	}
+	return buildAggregationPlan(gba, prevPlan, measureSchema, fieldRef, acc), nil
+}
+
+// newHistogramAggregationPlan merges the buckets of the histograms in both map and reduce modes,
+// since the merged histogram is the partial result of itself.
+func newHistogramAggregationPlan(gba *unresolvedAggregation, prevPlan logical.Plan,
+	measureSchema logical.Schema, fieldRef *logical.FieldRef,
+) (*aggregationPlan[float64], error) {
+	if gba.aggrFunc != modelv1.AggregationFunction_AGGREGATION_FUNCTION_SUM {
+		return nil, errors.WithMessagef(errUnsupportedAggregationField, "only SUM merges the histograms of the field: %s", fieldRef.Spec.Spec)
+	}
+	acc := &histogramAccumulator{
+		quantile:    gba.histogramQuantile,
+		emitPartial: gba.emitPartial,
+	}
+	return buildAggregationPlan[float64](gba, prevPlan, measureSchema, fieldRef, acc), nil
+}
+
+func buildAggregationPlan[N aggregation.Number](gba *unresolvedAggregation, prevPlan logical.Plan,
+	measureSchema logical.Schema, fieldRef *logical.FieldRef, acc aggAccumulator[N],
+) *aggregationPlan[N] {
 	return &aggregationPlan[N]{
 		Parent: &logical.Parent{
 			UnresolvedInput: gba.unresolvedInput,
@@ -206,7 +272,7 @@ func newAggregationPlan[N aggregation.Number](gba *unresolvedAggregation, prevPl
 		aggregationFieldRef: fieldRef,
 		aggrType:            gba.aggrFunc,
 		isGroup:             gba.isGroup,
-	}, nil
+	}
 }
 
 func (g *aggregationPlan[N]) String() string {
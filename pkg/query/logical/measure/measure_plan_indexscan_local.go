@@ -146,10 +146,25 @@ func (uis *unresolvedIndexScan) Analyze(s logical.Schema) (logical.Plan, error)
 			return nil, err
 		}
 	}
-	query, entities, _, err := inverted.BuildQuery(uis.criteria, s, entityMap, entity)
+	indexCriteria, zoneMapCriteria := splitZoneMapCriteria(uis.criteria, s, entityMap)
+	query, entities, _, err := inverted.BuildQuery(indexCriteria, s, entityMap, entity)
 	if err != nil {
 		return nil, err
 	}
+	var skippingFilter index.Filter
+	var tagFilter logical.TagFilter
+	var tagSpecs logical.TagSpecMap
+	if zoneMapCriteria != nil {
+		if skippingFilter, err = buildZoneMapFilter(zoneMapCriteria, entityMap, entity); err != nil {
+			return nil, err
+		}
+		if tagFilter, err = logical.BuildTagFilter(zoneMapCriteria, entityMap, s, s, false); err != nil {
+			return nil, err
+		}
+		// The data points of the blocks passing the zone maps are filtered by the values of the tags
+		projTags = projectCriteriaTags(projTags, zoneMapCriteria, s, tagFamilies)
+		tagSpecs = projectedTagSpecs(projTags, s)
+	}
 
 	return &localIndexScan{
 		timeRange:            tr,
@@ -161,6 +176,9 @@ func (uis *unresolvedIndexScan) Analyze(s logical.Schema) (logical.Plan, error)
 		metadata:             uis.metadata,
 		query:                query,
 		entities:             entities,
+		skippingFilter:       skippingFilter,
+		tagFilter:            tagFilter,
+		tagSpecs:             tagSpecs,
 		groupByEntity:        uis.groupByEntity,
 		hiddenTags:           hiddenTags,
 		uis:                  uis,
@@ -178,11 +196,14 @@ type localIndexScan struct {
 	ec                   executor.MeasureExecutionContext
 	schema               logical.Schema
 	query                index.Query
+	skippingFilter       index.Filter
+	tagFilter            logical.TagFilter
 	uis                  *unresolvedIndexScan
 	order                *logical.OrderBy
 	metadata             *commonv1.Metadata
 	l                    *logger.Logger
 	hiddenTags           logical.HiddenTagSet
+	tagSpecs             logical.TagSpecMap
 	timeRange            timestamp.TimeRange
 	projectionTagsRefs   [][]*logical.TagRef
 	projectionFieldsRefs []*logical.FieldRef
@@ -223,6 +244,7 @@ func (i *localIndexScan) Execute(ctx context.Context) (mit executor.MIterator, e
 		TimeRange:       &i.timeRange,
 		Entities:        i.entities,
 		Query:           i.query,
+		SkippingFilter:  i.skippingFilter,
 		Order:           orderBy,
 		TagProjection:   i.projectionTags,
 		FieldProjection: i.projectionFields,
@@ -235,13 +257,20 @@ func (i *localIndexScan) Execute(ctx context.Context) (mit executor.MIterator, e
 		projectionTags:   i.projectionTags,
 		projectionFields: i.projectionFields,
 		hiddenTags:       i.hiddenTags,
+		tagFilter:        i.tagFilter,
+		tagSpecs:         i.tagSpecs,
 	}, nil
 }
 
 func (i *localIndexScan) String() string {
-	return fmt.Sprintf("IndexScan: startTime=%d,endTime=%d,Metadata{group=%s,name=%s},conditions=%s; projection=%s; order=%s;",
+	if i.skippingFilter == nil {
+		return fmt.Sprintf("IndexScan: startTime=%d,endTime=%d,Metadata{group=%s,name=%s},conditions=%s; projection=%s; order=%s;",
+			i.timeRange.Start.Unix(), i.timeRange.End.Unix(), i.metadata.GetGroup(), i.metadata.GetName(),
+			i.query, logical.FormatTagRefs(", ", i.projectionTagsRefs...), i.order)
+	}
+	return fmt.Sprintf("IndexScan: startTime=%d,endTime=%d,Metadata{group=%s,name=%s},conditions=%s; zoneMaps=%s; projection=%s; order=%s;",
 		i.timeRange.Start.Unix(), i.timeRange.End.Unix(), i.metadata.GetGroup(), i.metadata.GetName(),
-		i.query, logical.FormatTagRefs(", ", i.projectionTagsRefs...), i.order)
+		i.query, i.skippingFilter, logical.FormatTagRefs(", ", i.projectionTagsRefs...), i.order)
 }
 
 func (i *localIndexScan) Children() []logical.Plan {
@@ -278,7 +307,9 @@ func indexScan(startTime, endTime time.Time, metadata *commonv1.Metadata, projec
 
 type resultMIterator struct {
 	result           model.MeasureQueryResult
+	tagFilter        logical.TagFilter
 	hiddenTags       logical.HiddenTagSet
+	tagSpecs         logical.TagSpecMap
 	err              error
 	current          []*measurev1.InternalDataPoint
 	projectionTags   []model.TagProjection
@@ -294,15 +325,27 @@ func (ei *resultMIterator) Next() bool {
 	if ei.i < len(ei.current) {
 		return true
 	}
-
-	r := ei.result.Pull()
-	if r == nil {
-		return false
-	}
-	if r.Error != nil {
-		ei.err = r.Error
-		return false
+	for {
+		r := ei.result.Pull()
+		if r == nil {
+			return false
+		}
+		if r.Error != nil {
+			ei.err = r.Error
+			return false
+		}
+		if err := ei.load(r); err != nil {
+			ei.err = err
+			return false
+		}
+		if len(ei.current) > 0 {
+			return true
+		}
 	}
+}
+
+// load builds the data points of the result, dropping the ones rejected by the tag filter.
+func (ei *resultMIterator) load(r *model.MeasureResult) error {
 	ei.current = ei.current[:0]
 	ei.i = 0
 	tagFamilyMap := make(map[string]*model.TagFamily, len(r.TagFamilies))
@@ -342,6 +385,16 @@ func (ei *resultMIterator) Next() bool {
 			}
 		}
 
+		if ei.tagFilter != nil {
+			ok, err := ei.tagFilter.Match(logical.TagFamilies(dp.TagFamilies), ei.tagSpecs)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+
 		// Strip hidden tags from the result
 		dp.TagFamilies = ei.hiddenTags.StripHiddenTags(dp.TagFamilies)
 
@@ -374,8 +427,7 @@ func (ei *resultMIterator) Next() bool {
 			ShardId:   uint32(shardID),
 		})
 	}
-
-	return true
+	return nil
 }
 
 func (ei *resultMIterator) Current() []*measurev1.InternalDataPoint {
@@ -18,13 +18,17 @@
 package measure
 
 import (
+	"context"
+	"math"
 	"testing"
 	"time"
 
 	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/query/logical"
+	"github.com/apache/skywalking-banyandb/pkg/query/model"
 )
 
 func TestLocalIndexScanSchemaRetainsNonProjectedTags(t *testing.T) {
@@ -161,3 +165,170 @@ func TestLocalIndexScanSchemaProjectionInIndexMode(t *testing.T) {
 		t.Fatalf("expected schema to exclude non_projected_tag when using projection")
 	}
 }
+
+type recordingMeasureEC struct {
+	opts    model.MeasureQueryOptions
+	results []*model.MeasureResult
+}
+
+func (ec *recordingMeasureEC) Query(_ context.Context, opts model.MeasureQueryOptions) (model.MeasureQueryResult, error) {
+	ec.opts = opts
+	return ec, nil
+}
+
+func (ec *recordingMeasureEC) Pull() *model.MeasureResult {
+	if len(ec.results) == 0 {
+		return nil
+	}
+	r := ec.results[0]
+	ec.results = ec.results[1:]
+	return r
+}
+
+func (ec *recordingMeasureEC) Release() {}
+
+type rangeFilterOp struct {
+	ranges map[string]index.RangeOpts
+	skip   bool
+}
+
+func (op *rangeFilterOp) Eq(_ string, _ string) bool {
+	return false
+}
+
+func (op *rangeFilterOp) Range(tagName string, rangeOpts index.RangeOpts) (bool, error) {
+	op.ranges[tagName] = rangeOpts
+	return op.skip, nil
+}
+
+func (op *rangeFilterOp) Having(_ string, _ []string) bool {
+	return false
+}
+
+func TestLocalIndexScanZoneMapFilter(t *testing.T) {
+	measureMeta := &databasev1.Measure{
+		Entity: &databasev1.Entity{
+			TagNames: []string{"entity_id"},
+		},
+		TagFamilies: []*databasev1.TagFamilySpec{
+			{
+				Name: "default",
+				Tags: []*databasev1.TagSpec{
+					{Name: "entity_id", Type: databasev1.TagType_TAG_TYPE_STRING},
+					{Name: "filter_tag", Type: databasev1.TagType_TAG_TYPE_STRING},
+					{Name: "latency", Type: databasev1.TagType_TAG_TYPE_INT},
+				},
+			},
+		},
+	}
+	indexRules := []*databasev1.IndexRule{
+		{
+			Metadata: &commonv1.Metadata{Name: "filter-tag-rule", Id: 1},
+			Type:     databasev1.IndexRule_TYPE_INVERTED,
+			Tags:     []string{"filter_tag"},
+		},
+	}
+	schema, err := BuildSchema(measureMeta, indexRules)
+	if err != nil {
+		t.Fatalf("build schema: %v", err)
+	}
+
+	criteria := &modelv1.Criteria{
+		Exp: &modelv1.Criteria_Le{
+			Le: &modelv1.LogicalExpression{
+				Op: modelv1.LogicalExpression_LOGICAL_OP_AND,
+				Left: &modelv1.Criteria{
+					Exp: &modelv1.Criteria_Condition{
+						Condition: &modelv1.Condition{
+							Name:  "filter_tag",
+							Op:    modelv1.Condition_BINARY_OP_EQ,
+							Value: &modelv1.TagValue{Value: &modelv1.TagValue_Str{Str: &modelv1.Str{Value: "match"}}},
+						},
+					},
+				},
+				Right: &modelv1.Criteria{
+					Exp: &modelv1.Criteria_Condition{
+						Condition: &modelv1.Condition{
+							Name:  "latency",
+							Op:    modelv1.Condition_BINARY_OP_GT,
+							Value: &modelv1.TagValue{Value: &modelv1.TagValue_Int{Int: &modelv1.Int{Value: 100}}},
+						},
+					},
+				},
+			},
+		},
+	}
+	intValue := func(v int64) *modelv1.TagValue {
+		return &modelv1.TagValue{Value: &modelv1.TagValue_Int{Int: &modelv1.Int{Value: v}}}
+	}
+	ec := &recordingMeasureEC{
+		results: []*model.MeasureResult{
+			{
+				SID:         1,
+				Timestamps:  []int64{1, 2},
+				Versions:    []int64{1, 1},
+				TagFamilies: []model.TagFamily{{Name: "default", Tags: []model.Tag{{Name: "latency", Values: []*modelv1.TagValue{intValue(50), intValue(80)}}}}},
+			},
+			{
+				SID:         2,
+				Timestamps:  []int64{3, 4},
+				Versions:    []int64{1, 1},
+				TagFamilies: []model.TagFamily{{Name: "default", Tags: []model.Tag{{Name: "latency", Values: []*modelv1.TagValue{intValue(150), intValue(90)}}}}},
+			},
+		},
+	}
+
+	plan, err := indexScan(
+		time.Unix(0, 0),
+		time.Unix(1, 0),
+		&commonv1.Metadata{Name: "test", Group: "default"},
+		nil,
+		nil,
+		false,
+		criteria,
+		ec,
+	).Analyze(schema)
+	if err != nil {
+		t.Fatalf("analyze plan: %v", err)
+	}
+	mit, err := plan.(*localIndexScan).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("execute plan: %v", err)
+	}
+
+	if ec.opts.Query == nil {
+		t.Fatalf("expected the condition on the indexed tag to query the series index")
+	}
+	if ec.opts.SkippingFilter == nil {
+		t.Fatalf("expected the condition on the non-indexed int tag to build the zone map filter")
+	}
+	op := &rangeFilterOp{ranges: make(map[string]index.RangeOpts), skip: true}
+	skipped, err := ec.opts.SkippingFilter.ShouldSkip(op)
+	if err != nil {
+		t.Fatalf("evaluate zone maps: %v", err)
+	}
+	if !skipped {
+		t.Fatalf("expected the block out of the zone map to be skipped")
+	}
+	expectedRange := index.NewIntRangeOpts(100, math.MaxInt64, false, false)
+	if got := op.ranges["latency"]; got.IncludesLower != expectedRange.IncludesLower ||
+		got.Lower.String() != expectedRange.Lower.String() || got.Upper.String() != expectedRange.Upper.String() {
+		t.Fatalf("unexpected zone map range of latency: %+v", got)
+	}
+
+	var timestamps []int64
+	for mit.Next() {
+		for _, dp := range mit.Current() {
+			timestamps = append(timestamps, dp.GetTimestamp().AsTime().UnixNano())
+			if len(dp.GetTagFamilies()) != 0 {
+				t.Fatalf("expected the hidden latency tag to be stripped, got %v", dp.GetTagFamilies())
+			}
+		}
+	}
+	if err = mit.Close(); err != nil {
+		t.Fatalf("close iterator: %v", err)
+	}
+	if len(timestamps) != 1 || timestamps[0] != 3 {
+		t.Fatalf("expected only the data point matching latency > 100, got %v", timestamps)
+	}
+}
@@ -0,0 +1,241 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package measure
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/apache/skywalking-banyandb/api/common"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
+	"github.com/apache/skywalking-banyandb/pkg/index"
+	"github.com/apache/skywalking-banyandb/pkg/index/posting"
+	"github.com/apache/skywalking-banyandb/pkg/query/logical"
+	"github.com/apache/skywalking-banyandb/pkg/query/model"
+)
+
+// splitZoneMapCriteria moves the conditions on the non-indexed int tags out of the criteria searched in the series index.
+// The moved conditions skip blocks by the zone maps of the tags, and filter the data points of the remaining blocks.
+// Only the conditions joined by AND are moved, because the series index evaluates every branch of an OR.
+func splitZoneMapCriteria(criteria *modelv1.Criteria, s logical.Schema, entityDict map[string]int) (*modelv1.Criteria, *modelv1.Criteria) {
+	switch criteria.GetExp().(type) {
+	case *modelv1.Criteria_Condition:
+		if isZoneMapCondition(criteria.GetCondition(), s, entityDict) {
+			return nil, criteria
+		}
+	case *modelv1.Criteria_Le:
+		le := criteria.GetLe()
+		if le.GetOp() != modelv1.LogicalExpression_LOGICAL_OP_AND {
+			return criteria, nil
+		}
+		leftIndex, leftZoneMap := splitZoneMapCriteria(le.GetLeft(), s, entityDict)
+		rightIndex, rightZoneMap := splitZoneMapCriteria(le.GetRight(), s, entityDict)
+		return andCriteria(leftIndex, rightIndex), andCriteria(leftZoneMap, rightZoneMap)
+	}
+	return criteria, nil
+}
+
+func isZoneMapCondition(cond *modelv1.Condition, s logical.Schema, entityDict map[string]int) bool {
+	if _, isEntity := entityDict[cond.GetName()]; isEntity {
+		return false
+	}
+	if ok, _ := s.IndexDefined(cond.GetName()); ok {
+		return false
+	}
+	tagSpec := s.FindTagSpecByName(cond.GetName())
+	if tagSpec == nil || tagSpec.Spec.GetType() != databasev1.TagType_TAG_TYPE_INT {
+		return false
+	}
+	if _, isInt := cond.GetValue().GetValue().(*modelv1.TagValue_Int); !isInt {
+		return false
+	}
+	switch cond.GetOp() {
+	case modelv1.Condition_BINARY_OP_GT, modelv1.Condition_BINARY_OP_GE, modelv1.Condition_BINARY_OP_LT,
+		modelv1.Condition_BINARY_OP_LE, modelv1.Condition_BINARY_OP_EQ:
+		return true
+	}
+	return false
+}
+
+func andCriteria(left, right *modelv1.Criteria) *modelv1.Criteria {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &modelv1.Criteria{
+		Exp: &modelv1.Criteria_Le{
+			Le: &modelv1.LogicalExpression{
+				Op:    modelv1.LogicalExpression_LOGICAL_OP_AND,
+				Left:  left,
+				Right: right,
+			},
+		},
+	}
+}
+
+// projectCriteriaTags appends the tags of the criteria absent from the projection to their tag families.
+func projectCriteriaTags(projTags []model.TagProjection, criteria *modelv1.Criteria, s logical.Schema,
+	tagFamilies []*databasev1.TagFamilySpec,
+) []model.TagProjection {
+	tagNames := make(map[string]struct{})
+	logical.CollectCriteriaTagNames(criteria, tagNames)
+	for _, proj := range projTags {
+		for _, name := range proj.Names {
+			delete(tagNames, name)
+		}
+	}
+	names := make([]string, 0, len(tagNames))
+	for name := range tagNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tagSpec := s.FindTagSpecByName(name)
+		if tagSpec == nil || tagSpec.TagFamilyIdx >= len(tagFamilies) {
+			continue
+		}
+		family := tagFamilies[tagSpec.TagFamilyIdx].GetName()
+		found := false
+		for i := range projTags {
+			if projTags[i].Family == family {
+				projTags[i].Names = append(projTags[i].Names, name)
+				found = true
+				break
+			}
+		}
+		if !found {
+			projTags = append(projTags, model.TagProjection{Family: family, Names: []string{name}})
+		}
+	}
+	return projTags
+}
+
+// projectedTagSpecs registers the tags at their positions in the data points built by the projection.
+func projectedTagSpecs(projTags []model.TagProjection, s logical.Schema) logical.TagSpecMap {
+	tagSpecs := make(logical.TagSpecMap)
+	for familyIdx, proj := range projTags {
+		for tagIdx, name := range proj.Names {
+			if tagSpec := s.FindTagSpecByName(name); tagSpec != nil {
+				tagSpecs.RegisterTag(familyIdx, tagIdx, tagSpec.Spec)
+			}
+		}
+	}
+	return tagSpecs
+}
+
+// buildZoneMapFilter builds the filter skipping the blocks whose zone maps can't match the criteria split by splitZoneMapCriteria.
+func buildZoneMapFilter(criteria *modelv1.Criteria, entityDict map[string]int, entity []*modelv1.TagValue) (index.Filter, error) {
+	switch criteria.GetExp().(type) {
+	case *modelv1.Criteria_Condition:
+		cond := criteria.GetCondition()
+		expr, _, err := logical.ParseExprOrEntity(entityDict, entity, cond)
+		if err != nil {
+			return nil, err
+		}
+		return parseConditionToZoneMapFilter(cond, expr), nil
+	case *modelv1.Criteria_Le:
+		left, err := buildZoneMapFilter(criteria.GetLe().GetLeft(), entityDict, entity)
+		if err != nil {
+			return nil, err
+		}
+		right, err := buildZoneMapFilter(criteria.GetLe().GetRight(), entityDict, entity)
+		if err != nil {
+			return nil, err
+		}
+		return &zoneMapAndFilter{left: left, right: right}, nil
+	}
+	return nil, logical.ErrInvalidCriteriaType
+}
+
+func parseConditionToZoneMapFilter(cond *modelv1.Condition, expr logical.LiteralExpr) index.Filter {
+	var opts index.RangeOpts
+	switch cond.Op {
+	case modelv1.Condition_BINARY_OP_GT:
+		opts = expr.RangeOpts(false, false, false)
+	case modelv1.Condition_BINARY_OP_GE:
+		opts = expr.RangeOpts(false, true, false)
+	case modelv1.Condition_BINARY_OP_LT:
+		opts = expr.RangeOpts(true, false, false)
+	case modelv1.Condition_BINARY_OP_LE:
+		opts = expr.RangeOpts(true, false, true)
+	case modelv1.Condition_BINARY_OP_EQ:
+		opts = expr.RangeOpts(false, true, true)
+		opts.Upper = expr.RangeOpts(true, true, true).Upper
+	}
+	return &zoneMapRangeFilter{tagName: cond.Name, opts: opts}
+}
+
+// zoneMapAndFilter implements index.Filter for the AND of the zone map conditions.
+type zoneMapAndFilter struct {
+	left  index.Filter
+	right index.Filter
+}
+
+func (zaf *zoneMapAndFilter) Execute(_ index.GetSearcher, _ common.SeriesID, _ *index.RangeOpts) (posting.List, posting.List, error) {
+	panic("zoneMapAndFilter.Execute should not be invoked")
+}
+
+func (zaf *zoneMapAndFilter) ShouldSkip(op index.FilterOp) (bool, error) {
+	// Any side out of the zone maps skips the block
+	leftSkip, err := zaf.left.ShouldSkip(op)
+	if err != nil || leftSkip {
+		return leftSkip, err
+	}
+	return zaf.right.ShouldSkip(op)
+}
+
+func (zaf *zoneMapAndFilter) String() string {
+	return "and(" + zaf.left.String() + "," + zaf.right.String() + ")"
+}
+
+// zoneMapRangeFilter implements index.Filter for a range or an equality of an int tag.
+type zoneMapRangeFilter struct {
+	tagName string
+	opts    index.RangeOpts
+}
+
+func (zrf *zoneMapRangeFilter) Execute(_ index.GetSearcher, _ common.SeriesID, _ *index.RangeOpts) (posting.List, posting.List, error) {
+	panic("zoneMapRangeFilter.Execute should not be invoked")
+}
+
+func (zrf *zoneMapRangeFilter) ShouldSkip(op index.FilterOp) (bool, error) {
+	return op.Range(zrf.tagName, zrf.opts)
+}
+
+func (zrf *zoneMapRangeFilter) String() string {
+	var builder strings.Builder
+	builder.WriteString(zrf.tagName)
+	builder.WriteString(":")
+	if zrf.opts.IncludesLower {
+		builder.WriteString("[")
+	} else {
+		builder.WriteString("(")
+	}
+	builder.WriteString(zrf.opts.Lower.String())
+	builder.WriteString(",")
+	builder.WriteString(zrf.opts.Upper.String())
+	if zrf.opts.IncludesUpper {
+		builder.WriteString("]")
+	} else {
+		builder.WriteString(")")
+	}
+	return builder.String()
+}
@@ -55,9 +55,14 @@ func buildLocalFilter(criteria *modelv1.Criteria, schema logical.Schema,
 			return nil, parsedEntity, nil
 		}
 		if schema != nil {
-			if ok, indexRule := schema.IndexDefined(cond.Name); ok && indexRule.Type == indexRuleType {
+			if ok, indexRule := schema.IndexDefined(cond.Name); ok && storedBy(indexRule, indexRuleType) {
 				return parseConditionToFilter(cond, indexRule, expr, entity, schema)
 			}
+			if indexRuleType == databasev1.IndexRule_TYPE_SKIPPING {
+				if f := parseConditionToZoneMapFilter(cond, schema, expr); f != nil {
+					return f, [][]*modelv1.TagValue{entity}, nil
+				}
+			}
 		}
 		return ENode, [][]*modelv1.TagValue{entity}, nil
 	case *modelv1.Criteria_Le:
@@ -109,9 +114,19 @@ func buildLocalFilter(criteria *modelv1.Criteria, schema logical.Schema,
 	return nil, nil, logical.ErrInvalidCriteriaType
 }
 
+// storedBy reports whether the index rule is stored by the indices of the type.
+// The tree indices are stored by the inverted index.
+func storedBy(indexRule *databasev1.IndexRule, indexRuleType databasev1.IndexRule_Type) bool {
+	if indexRule.Type == databasev1.IndexRule_TYPE_TREE {
+		return indexRuleType == databasev1.IndexRule_TYPE_INVERTED
+	}
+	return indexRule.Type == indexRuleType
+}
+
 func parseConditionToFilter(cond *modelv1.Condition, indexRule *databasev1.IndexRule,
 	expr logical.LiteralExpr, entity []*modelv1.TagValue, schema logical.Schema,
 ) (index.Filter, [][]*modelv1.TagValue, error) {
+	index.RecordHit(indexRule.GetMetadata().GetId())
 	switch cond.Op {
 	case modelv1.Condition_BINARY_OP_GT:
 		return newRange(indexRule, expr.RangeOpts(false, false, false)), [][]*modelv1.TagValue{entity}, nil
@@ -127,7 +142,12 @@ func parseConditionToFilter(cond *modelv1.Condition, indexRule *databasev1.Index
 		if indexRule.Type == databasev1.IndexRule_TYPE_INVERTED {
 			return newMatch(indexRule, expr, cond.MatchOption), [][]*modelv1.TagValue{entity}, nil
 		}
-		return nil, nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "index filter parses %v for skipping index", cond)
+		return nil, nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "index filter parses %v for %s index", cond, indexRule.Type)
+	case modelv1.Condition_BINARY_OP_DESCENDANT_OF, modelv1.Condition_BINARY_OP_CHILD_OF:
+		if indexRule.Type != databasev1.IndexRule_TYPE_TREE {
+			return nil, nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "index filter parses %v for %s index", cond, indexRule.Type)
+		}
+		return newTree(indexRule, expr, cond.Op == modelv1.Condition_BINARY_OP_CHILD_OF), [][]*modelv1.TagValue{entity}, nil
 	case modelv1.Condition_BINARY_OP_NE:
 		return newNot(indexRule, newEq(indexRule, expr)), [][]*modelv1.TagValue{entity}, nil
 	case modelv1.Condition_BINARY_OP_HAVING:
@@ -194,6 +214,42 @@ func parseConditionToFilter(cond *modelv1.Condition, indexRule *databasev1.Index
 	return nil, nil, errors.WithMessagef(logical.ErrUnsupportedConditionOp, "index filter parses %v", cond)
 }
 
+// parseConditionToZoneMapFilter skips blocks by the zone maps of the non-indexed int tags.
+// It returns nil if the condition can't be evaluated against the zone maps.
+func parseConditionToZoneMapFilter(cond *modelv1.Condition, schema logical.Schema, expr logical.LiteralExpr) index.Filter {
+	tagSpec := schema.FindTagSpecByName(cond.Name)
+	if tagSpec == nil || tagSpec.Spec.GetType() != databasev1.TagType_TAG_TYPE_INT {
+		return nil
+	}
+	indexRule := &databasev1.IndexRule{
+		Tags: []string{cond.Name},
+		Type: databasev1.IndexRule_TYPE_SKIPPING,
+	}
+	var opts index.RangeOpts
+	switch cond.Op {
+	case modelv1.Condition_BINARY_OP_GT:
+		opts = expr.RangeOpts(false, false, false)
+	case modelv1.Condition_BINARY_OP_GE:
+		opts = expr.RangeOpts(false, true, false)
+	case modelv1.Condition_BINARY_OP_LT:
+		opts = expr.RangeOpts(true, false, false)
+	case modelv1.Condition_BINARY_OP_LE:
+		opts = expr.RangeOpts(true, false, true)
+	case modelv1.Condition_BINARY_OP_EQ:
+		opts = expr.RangeOpts(false, true, true)
+		opts.Upper = expr.RangeOpts(true, true, true).Upper
+	default:
+		return nil
+	}
+	if _, ok := opts.Lower.(*index.FloatTermValue); !ok {
+		return nil
+	}
+	if _, ok := opts.Upper.(*index.FloatTermValue); !ok {
+		return nil
+	}
+	return newRange(indexRule, opts)
+}
+
 type fieldKey struct {
 	*databasev1.IndexRule
 }
@@ -214,6 +270,14 @@ func (fk fieldKey) toIndex(seriesID common.SeriesID, tr *index.RangeOpts) index.
 	return ifk
 }
 
+// recordPostings records the size of the posting list the index rule returns.
+func (fk fieldKey) recordPostings(list, timestamps posting.List, err error) (posting.List, posting.List, error) {
+	if err == nil && list != nil && fk.Metadata != nil {
+		index.RecordPostings(fk.Metadata.Id, list.Len())
+	}
+	return list, timestamps, err
+}
+
 type logicalOP interface {
 	index.Filter
 	merge(...posting.List) (posting.List, error)
@@ -453,7 +517,7 @@ func (eq *eq) Execute(searcher index.GetSearcher, seriesID common.SeriesID, tr *
 	if err != nil {
 		return nil, nil, err
 	}
-	return s.MatchTerms(eq.Expr.Field(eq.Key.toIndex(seriesID, tr)))
+	return eq.Key.recordPostings(s.MatchTerms(eq.Expr.Field(eq.Key.toIndex(seriesID, tr))))
 }
 
 func (eq *eq) ShouldSkip(tagFamilyFilters index.FilterOp) (bool, error) {
@@ -495,11 +559,11 @@ func (match *match) Execute(searcher index.GetSearcher, seriesID common.SeriesID
 	for i, v := range bb {
 		matches[i] = string(v)
 	}
-	return s.Match(
+	return match.Key.recordPostings(s.Match(
 		match.Key.toIndex(seriesID, tr),
 		matches,
 		match.opts,
-	)
+	))
 }
 
 func (match *match) ShouldSkip(_ index.FilterOp) (bool, error) {
@@ -516,6 +580,53 @@ func (match *match) String() string {
 	return convert.JSONToString(match)
 }
 
+type tree struct {
+	*leaf
+	children bool
+}
+
+func newTree(indexRule *databasev1.IndexRule, values logical.LiteralExpr, children bool) *tree {
+	return &tree{
+		leaf: &leaf{
+			Key:  newFieldKeyWithIndexRule(indexRule),
+			Expr: values,
+		},
+		children: children,
+	}
+}
+
+func (t *tree) Execute(searcher index.GetSearcher, seriesID common.SeriesID, tr *index.RangeOpts) (posting.List, posting.List, error) {
+	s, err := searcher(t.Key.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := t.Key.toIndex(seriesID, tr)
+	if t.children {
+		key = index.TreeParentKey(key)
+	} else {
+		key = index.TreeAncestorKey(key)
+	}
+	return t.Key.recordPostings(s.MatchTerms(index.NewStringField(key, index.TreePath(t.Expr.String()))))
+}
+
+func (t *tree) ShouldSkip(_ index.FilterOp) (bool, error) {
+	return false, nil
+}
+
+func (t *tree) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{}, 1)
+	if t.children {
+		data["childOf"] = t.leaf
+	} else {
+		data["descendantOf"] = t.leaf
+	}
+	return json.Marshal(data)
+}
+
+func (t *tree) String() string {
+	return convert.JSONToString(t)
+}
+
 type rangeOp struct {
 	*leaf
 	Opts index.RangeOpts
@@ -535,7 +646,7 @@ func (r *rangeOp) Execute(searcher index.GetSearcher, seriesID common.SeriesID,
 	if err != nil {
 		return nil, nil, err
 	}
-	return s.Range(r.Key.toIndex(seriesID, tr), r.Opts)
+	return r.Key.recordPostings(s.Range(r.Key.toIndex(seriesID, tr), r.Opts))
 }
 
 func (r *rangeOp) ShouldSkip(tagFamilyFilters index.FilterOp) (bool, error) {
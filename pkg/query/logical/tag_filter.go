@@ -28,6 +28,7 @@ import (
 	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
 	modelv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/model/v1"
 	"github.com/apache/skywalking-banyandb/pkg/convert"
+	"github.com/apache/skywalking-banyandb/pkg/index"
 	"github.com/apache/skywalking-banyandb/pkg/index/analyzer"
 )
 
@@ -165,6 +166,10 @@ func parseFilter(cond *modelv1.Condition, expr ComparableExpr, schema Schema, in
 		return newEqTag(cond.Name, expr), nil
 	case modelv1.Condition_BINARY_OP_MATCH:
 		return newMatchTag(cond.Name, expr, indexChecker), nil
+	case modelv1.Condition_BINARY_OP_DESCENDANT_OF:
+		return newTreeTag(cond.Name, expr, false), nil
+	case modelv1.Condition_BINARY_OP_CHILD_OF:
+		return newTreeTag(cond.Name, expr, true), nil
 	case modelv1.Condition_BINARY_OP_NE:
 		return newNotTag(newEqTag(cond.Name, expr)), nil
 	case modelv1.Condition_BINARY_OP_HAVING:
@@ -441,6 +446,59 @@ func (eq *eqTag) String() string {
 	return convert.JSONToString(eq)
 }
 
+type treeTag struct {
+	*tagLeaf
+	children bool
+}
+
+func newTreeTag(tagName string, values LiteralExpr, children bool) *treeTag {
+	return &treeTag{
+		tagLeaf: &tagLeaf{
+			Name: tagName,
+			Expr: values,
+		},
+		children: children,
+	}
+}
+
+func (t *treeTag) Match(accessor TagValueIndexAccessor, registry TagSpecRegistry) (bool, error) {
+	expr, err := tagExpr(accessor, registry, t.Name, nil)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := expr.(*strLiteral); !ok {
+		return false, nil
+	}
+	ancestors := index.TreeAncestors(expr.String())
+	if len(ancestors) == 0 {
+		return false, nil
+	}
+	path := index.TreePath(t.Expr.String())
+	if t.children {
+		return ancestors[len(ancestors)-1] == path, nil
+	}
+	for _, a := range ancestors {
+		if a == path {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (t *treeTag) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{}, 1)
+	if t.children {
+		data["childOf"] = t.tagLeaf
+	} else {
+		data["descendantOf"] = t.tagLeaf
+	}
+	return json.Marshal(data)
+}
+
+func (t *treeTag) String() string {
+	return convert.JSONToString(t)
+}
+
 type rangeOpts struct {
 	Upper         ComparableExpr
 	Lower         ComparableExpr
@@ -366,6 +366,11 @@ func buildFilterFromCondition(cond *modelv1.Condition, schema logical.Schema, ta
 	if err != nil {
 		return nil, nil, collectedTagNames, traceIDs, minVal, maxVal, err
 	}
+	if schema != nil {
+		if ok, indexRule := schema.IndexDefined(cond.Name); ok {
+			index.RecordHit(indexRule.GetMetadata().GetId())
+		}
+	}
 	filter, entities, err := parseConditionToFilter(cond, schema, entity, expr)
 	return filter, entities, collectedTagNames, traceIDs, minVal, maxVal, err
 }
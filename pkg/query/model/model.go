@@ -58,6 +58,7 @@ type TagProjection struct {
 // MeasureQueryOptions is the options of a measure query.
 type MeasureQueryOptions struct {
 	Query           index.Query
+	SkippingFilter  index.Filter
 	TimeRange       *timestamp.TimeRange
 	Order           *index.OrderBy
 	Name            string
@@ -0,0 +1,98 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	gm "github.com/onsi/gomega"
+
+	commonv1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/common/v1"
+	databasev1 "github.com/apache/skywalking-banyandb/api/proto/banyandb/database/v1"
+)
+
+var _ = g.Describe("Schema history", func() {
+	var (
+		ctx           context.Context
+		groupClient   databasev1.GroupRegistryServiceClient
+		historyClient databasev1.SchemaHistoryServiceClient
+	)
+
+	g.BeforeEach(func() {
+		ctx = context.Background()
+		groupClient = databasev1.NewGroupRegistryServiceClient(SharedContext.Connection)
+		historyClient = databasev1.NewSchemaHistoryServiceClient(SharedContext.Connection)
+	})
+
+	g.It("should keep the versions of a group and roll it back", func() {
+		groupName := fmt.Sprintf("history-group-%d", time.Now().UnixNano())
+		md := &commonv1.Metadata{Name: groupName}
+
+		g.By("Creating the group")
+		_, err := groupClient.Create(ctx, &databasev1.GroupRegistryServiceCreateRequest{
+			Group: &commonv1.Group{
+				Metadata: md,
+				Catalog:  commonv1.Catalog_CATALOG_STREAM,
+				ResourceOpts: &commonv1.ResourceOpts{
+					ShardNum:        2,
+					SegmentInterval: &commonv1.IntervalRule{Unit: commonv1.IntervalRule_UNIT_DAY, Num: 1},
+					Ttl:             &commonv1.IntervalRule{Unit: commonv1.IntervalRule_UNIT_DAY, Num: 7},
+				},
+			},
+		})
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		defer func() {
+			_, _ = groupClient.Delete(ctx, &databasev1.GroupRegistryServiceDeleteRequest{Group: groupName})
+		}()
+
+		g.By("Updating the TTL of the group")
+		getResp, err := groupClient.Get(ctx, &databasev1.GroupRegistryServiceGetRequest{Group: groupName})
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		updated := getResp.GetGroup()
+		updated.ResourceOpts.Ttl.Num = 14
+		_, err = groupClient.Update(ctx, &databasev1.GroupRegistryServiceUpdateRequest{Group: updated})
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+
+		g.By("Listing the history")
+		historyReq := &databasev1.SchemaHistoryServiceHistoryRequest{Kind: databasev1.SchemaKind_SCHEMA_KIND_GROUP, Metadata: md}
+		historyResp, err := historyClient.History(ctx, historyReq)
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		revisions := historyResp.GetRevisions()
+		gm.Expect(revisions).To(gm.HaveLen(2))
+		gm.Expect(revisions[0].GetGroup().GetResourceOpts().GetTtl().GetNum()).To(gm.Equal(uint32(14)))
+		gm.Expect(revisions[1].GetGroup().GetResourceOpts().GetTtl().GetNum()).To(gm.Equal(uint32(7)))
+
+		g.By("Rolling the group back to its first version")
+		rollbackResp, err := historyClient.Rollback(ctx, &databasev1.SchemaHistoryServiceRollbackRequest{
+			Kind:        databasev1.SchemaKind_SCHEMA_KIND_GROUP,
+			Metadata:    md,
+			ModRevision: revisions[1].GetModRevision(),
+		})
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		gm.Expect(rollbackResp.GetModRevision()).To(gm.BeNumerically(">", revisions[0].GetModRevision()))
+		getResp, err = groupClient.Get(ctx, &databasev1.GroupRegistryServiceGetRequest{Group: groupName})
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		gm.Expect(getResp.GetGroup().GetResourceOpts().GetTtl().GetNum()).To(gm.Equal(uint32(7)))
+		historyResp, err = historyClient.History(ctx, historyReq)
+		gm.Expect(err).ShouldNot(gm.HaveOccurred())
+		gm.Expect(historyResp.GetRevisions()).To(gm.HaveLen(3))
+	})
+})